@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// AMQP is a RabbitMQ, or other AMQP 0.9.1, broker.
+// https://www.rabbitmq.com/tutorials/amqp-concepts.html
+type AMQP struct {
+	// Name of the "dataflow-amqp-{name}" secret, whose "url" and "password" are used if not specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// URL of the broker, e.g. "amqp://my-user@rabbitmq:5672/my-vhost", or "amqps://..." for TLS.
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// PasswordSecret is the secret selector to the user's password, which is used rather than any in the URL.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,3,opt,name=passwordSecret"`
+}
+
+// GetAddress returns the URL without the user's credentials.
+func (in AMQP) GetAddress() string {
+	u, err := url.Parse(in.URL)
+	if err != nil {
+		return in.URL
+	}
+	u.User = nil
+	return u.String()
+}
+
+// getDefaultPort returns the port of URLs that do not specify one.
+func (in AMQP) getDefaultPort() int32 {
+	if strings.HasPrefix(in.URL, "amqps:") {
+		return 5671
+	}
+	return 5672
+}
+
+// AMQPSource consumes messages from a queue, which must exist. Replicas are competing consumers of the queue.
+// Messages are acknowledged once they are processed, and rejected if they fail to be processed, so the broker
+// dead-letters them if the queue has a dead letter exchange. Messages that are not processed before the replica stops
+// are redelivered.
+type AMQPSource struct {
+	AMQP `json:",inline" protobuf:"bytes,1,opt,name=amqp"`
+	// Queue is the name of the queue.
+	Queue string `json:"queue" protobuf:"bytes,2,opt,name=queue"`
+	// Prefetch is the most unacknowledged messages the broker delivers to each replica at once.
+	// +kubebuilder:default=10
+	Prefetch uint32 `json:"prefetch,omitempty" protobuf:"varint,3,opt,name=prefetch"`
+}
+
+func (in AMQPSource) GetPrefetch() int {
+	if in.Prefetch > 0 {
+		return int(in.Prefetch)
+	}
+	return 10
+}
+
+func (in AMQPSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:amqp:%s:%s", in.GetAddress(), in.Queue)
+}
+
+// AMQPSink publishes persistent messages to an exchange, which must exist, waiting for the broker to confirm each one.
+type AMQPSink struct {
+	AMQP `json:",inline" protobuf:"bytes,1,opt,name=amqp"`
+	// Exchange is the name of the exchange. The default exchange, "", routes each message to the queue named by its
+	// routing key.
+	Exchange string `json:"exchange,omitempty" protobuf:"bytes,2,opt,name=exchange"`
+	// RoutingKey is a template for each message's routing key, e.g. "orders.{{ .Object.region }}". The template's data
+	// are the message, "Msg", as a string, the message decoded as JSON, "Object", if it is JSON, and its metadata,
+	// "Meta", i.e. "Source", "ID" and "Time". Sprig's functions are available.
+	RoutingKey string `json:"routingKey,omitempty" protobuf:"bytes,3,opt,name=routingKey"`
+}
@@ -0,0 +1,40 @@
+package v1alpha1
+
+import "fmt"
+
+// ArgoEventsSource consumes the events that an Argo Events event source publishes to a JetStream event bus.
+// https://argoproj.github.io/argo-events/eventbus/jetstream/
+type ArgoEventsSource struct {
+	// EventBusName is the name of the EventBus, in the same namespace as the pipeline.
+	// +kubebuilder:default=default
+	EventBusName string `json:"eventBusName,omitempty" protobuf:"bytes,1,opt,name=eventBusName"`
+	// EventSourceName is the name of the EventSource.
+	EventSourceName string `json:"eventSourceName" protobuf:"bytes,2,opt,name=eventSourceName"`
+	// EventName is the name of the event within the EventSource. If omitted, all of the EventSource's events are
+	// consumed.
+	EventName string `json:"eventName,omitempty" protobuf:"bytes,3,opt,name=eventName"`
+	// NATSURL overrides the URL of the EventBus's JetStream service, e.g. "nats://eventbus-default-js-svc:4222".
+	NATSURL string `json:"natsUrl,omitempty" protobuf:"bytes,4,opt,name=natsUrl"`
+}
+
+func (a ArgoEventsSource) GetEventBusName() string {
+	return StringOr(a.EventBusName, "default")
+}
+
+func (a ArgoEventsSource) GetNATSURL() string {
+	return StringOr(a.NATSURL, fmt.Sprintf("nats://eventbus-%s-js-svc:4222", a.GetEventBusName()))
+}
+
+// GetSubject returns the subject that Argo Events publishes the events to, "default.{eventSourceName}.{eventName}".
+func (a ArgoEventsSource) GetSubject() string {
+	return fmt.Sprintf("default.%s.%s", a.EventSourceName, StringOr(a.EventName, "*"))
+}
+
+// GetAuthSecretName returns the name of the secret Argo Events creates for the EventBus's client credentials.
+func (a ArgoEventsSource) GetAuthSecretName() string {
+	return fmt.Sprintf("eventbus-%s-js-client-auth", a.GetEventBusName())
+}
+
+func (a ArgoEventsSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:argo-events:%s:%s:%s", namespace, a.GetEventBusName(), a.GetSubject())
+}
@@ -0,0 +1,23 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArgoEventsSource(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		x := ArgoEventsSource{EventSourceName: "my-es"}
+		assert.Equal(t, "nats://eventbus-default-js-svc:4222", x.GetNATSURL())
+		assert.Equal(t, "default.my-es.*", x.GetSubject())
+		assert.Equal(t, "eventbus-default-js-client-auth", x.GetAuthSecretName())
+		assert.Equal(t, "urn:dataflow:argo-events:my-ns:default:default.my-es.*", x.GenURN(cluster, namespace))
+	})
+	t.Run("Event", func(t *testing.T) {
+		x := ArgoEventsSource{EventBusName: "my-eb", EventSourceName: "my-es", EventName: "my-event", NATSURL: "nats://my-nats:4222"}
+		assert.Equal(t, "nats://my-nats:4222", x.GetNATSURL())
+		assert.Equal(t, "default.my-es.my-event", x.GetSubject())
+		assert.Equal(t, "eventbus-my-eb-js-client-auth", x.GetAuthSecretName())
+	})
+}
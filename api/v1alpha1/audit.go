@@ -0,0 +1,8 @@
+package v1alpha1
+
+// Audit writes a record of every message the step processes to one of its sinks: the message's ID, source, size,
+// times, outcome and error, but not its payload. The sink only receives audit records, not the step's messages.
+type Audit struct {
+	// Sink is the name of the sink to write audit records to.
+	Sink string `json:"sink" protobuf:"bytes,1,opt,name=sink"`
+}
@@ -0,0 +1,16 @@
+package v1alpha1
+
+import "k8s.io/apimachinery/pkg/api/resource"
+
+// Buffer stores messages that could not be written to a sink, and retries them in the background.
+type Buffer struct {
+	// MaxSize is the maximum number of bytes to buffer. When the buffer is full, messages are returned to the source
+	// as errors, as if there were no buffer.
+	// +kubebuilder:default="16Mi"
+	MaxSize resource.Quantity `json:"maxSize,omitempty" protobuf:"bytes,1,opt,name=maxSize"`
+	// Volume to store the buffer on, e.g. a persistent volume claim. If omitted, the buffer uses the sidecar's
+	// in-memory volume, which counts towards the sidecar's memory usage.
+	Volume *AbstractVolumeSource `json:"volume,omitempty" protobuf:"bytes,2,opt,name=volume"`
+	// Encryption encrypts buffered messages, so they are never stored in plaintext.
+	Encryption *Encryption `json:"encryption,omitempty" protobuf:"bytes,3,opt,name=encryption"`
+}
@@ -0,0 +1,39 @@
+package v1alpha1
+
+import "fmt"
+
+// CloudEventsSink sends each message as a CloudEvent, using the HTTP binary content mode, e.g. to a Knative Broker.
+// https://github.com/cloudevents/spec/blob/v1.0.1/http-protocol-binding.md
+type CloudEventsSink struct {
+	// URL to send the events to. If omitted, the Broker's URL is used, otherwise the K_SINK environment variable
+	// (the Knative SinkBinding convention).
+	URL string `json:"url,omitempty" protobuf:"bytes,1,opt,name=url"`
+	// Broker is the name of a Knative Broker in the same namespace as the pipeline.
+	Broker string `json:"broker,omitempty" protobuf:"bytes,2,opt,name=broker"`
+	// Type is the type of the events.
+	// +kubebuilder:default=io.argoproj.dataflow.message
+	Type string `json:"type,omitempty" protobuf:"bytes,3,opt,name=type"`
+	// ContentType is the content type of the messages.
+	// +kubebuilder:default=application/octet-stream
+	ContentType        string `json:"contentType,omitempty" protobuf:"bytes,4,opt,name=contentType"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify,omitempty" protobuf:"varint,5,opt,name=insecureSkipVerify"`
+}
+
+// GetURL returns the URL to send the events to, or an empty string if neither the URL or the Broker are specified.
+func (c CloudEventsSink) GetURL(namespace string) string {
+	if c.URL != "" {
+		return c.URL
+	}
+	if c.Broker != "" {
+		return fmt.Sprintf("http://broker-ingress.knative-eventing.svc.cluster.local/%s/%s", namespace, c.Broker)
+	}
+	return ""
+}
+
+func (c CloudEventsSink) GetType() string {
+	return StringOr(c.Type, "io.argoproj.dataflow.message")
+}
+
+func (c CloudEventsSink) GetContentType() string {
+	return StringOr(c.ContentType, "application/octet-stream")
+}
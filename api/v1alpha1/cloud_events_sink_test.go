@@ -0,0 +1,13 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloudEventsSink_GetURL(t *testing.T) {
+	assert.Equal(t, "", CloudEventsSink{}.GetURL(namespace))
+	assert.Equal(t, "http://my-url", CloudEventsSink{URL: "http://my-url", Broker: "default"}.GetURL(namespace))
+	assert.Equal(t, "http://broker-ingress.knative-eventing.svc.cluster.local/my-ns/default", CloudEventsSink{Broker: "default"}.GetURL(namespace))
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+// Codec converts messages between a format and JSON. On a source, messages are decoded into JSON before they are
+// processed. On a sink, messages are encoded from JSON before they are written. Exactly one format must be specified.
+type Codec struct {
+	JSON     *JSONCodec     `json:"json,omitempty" protobuf:"bytes,1,opt,name=json"`
+	CSV      *CSVCodec      `json:"csv,omitempty" protobuf:"bytes,2,opt,name=csv"`
+	MsgPack  *MsgPackCodec  `json:"msgpack,omitempty" protobuf:"bytes,3,opt,name=msgpack"`
+	Avro     *AvroCodec     `json:"avro,omitempty" protobuf:"bytes,4,opt,name=avro"`
+	Protobuf *ProtobufCodec `json:"protobuf,omitempty" protobuf:"bytes,5,opt,name=protobuf"`
+}
+
+// JSONCodec only checks messages are valid JSON, and removes insignificant white-space.
+type JSONCodec struct{}
+
+// CSVCodec converts a single CSV record to a JSON object, keyed by column, or, if no columns are specified, to a JSON
+// array of strings.
+type CSVCodec struct {
+	// Columns are the names of the record's fields, in order.
+	Columns []string `json:"columns,omitempty" protobuf:"bytes,1,rep,name=columns"`
+	// Delimiter is the field delimiter, defaults to ",".
+	Delimiter string `json:"delimiter,omitempty" protobuf:"bytes,2,opt,name=delimiter"`
+}
+
+func (in CSVCodec) GetDelimiter() rune {
+	if in.Delimiter == "" {
+		return ','
+	}
+	return []rune(in.Delimiter)[0]
+}
+
+type MsgPackCodec struct{}
+
+// AvroCodec converts Avro binary encoded messages (without a container file header, or schema registry prefix).
+// Bytes and fixed values are base64 encoded strings in JSON, and unions are the value of the union's type.
+type AvroCodec struct {
+	// Schema is the Avro schema, as JSON.
+	Schema string `json:"schema" protobuf:"bytes,1,opt,name=schema"`
+}
+
+type ProtobufCodec struct {
+	// DescriptorSet is a serialized `FileDescriptorSet` containing the message type and its dependencies, e.g. created
+	// using `protoc --include_imports --descriptor_set_out=...`.
+	DescriptorSet []byte `json:"descriptorSet" protobuf:"bytes,1,opt,name=descriptorSet"`
+	// MessageName is the full name of the message type, e.g. `my.package.MyMessage`.
+	MessageName string `json:"messageName" protobuf:"bytes,2,opt,name=messageName"`
+}
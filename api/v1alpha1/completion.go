@@ -0,0 +1,37 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Completion is when a step runs to completion, rather than forever. When any of its criteria are met, the sidecar stops
+// its sources, and exits, and the step succeeds. Use with `terminator: true` to complete the whole pipeline.
+type Completion struct {
+	// Messages, if greater than zero, completes each replica once its sources have processed at least this many messages.
+	Messages uint64 `json:"messages,omitempty" protobuf:"varint,1,opt,name=messages"`
+	// Duration, if specified, completes each replica once it has run for this long.
+	Duration *metav1.Duration `json:"duration,omitempty" protobuf:"bytes,2,opt,name=duration"`
+	// Drained completes each replica once its sources have no pending messages. Unlike bounded sources, messages
+	// produced after the step starts are also processed.
+	Drained bool `json:"drained,omitempty" protobuf:"varint,3,opt,name=drained"`
+}
+
+func (in *Completion) GetMessages() uint64 {
+	if in == nil {
+		return 0
+	}
+	return in.Messages
+}
+
+func (in *Completion) GetDuration() time.Duration {
+	if in == nil || in.Duration == nil {
+		return 0
+	}
+	return in.Duration.Duration
+}
+
+func (in *Completion) GetDrained() bool {
+	return in != nil && in.Drained
+}
@@ -0,0 +1,20 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCompletion(t *testing.T) {
+	var x *Completion
+	assert.Zero(t, x.GetMessages())
+	assert.Zero(t, x.GetDuration())
+	assert.False(t, x.GetDrained())
+	x = &Completion{Messages: 10, Duration: &metav1.Duration{Duration: time.Minute}, Drained: true}
+	assert.Equal(t, uint64(10), x.GetMessages())
+	assert.Equal(t, time.Minute, x.GetDuration())
+	assert.True(t, x.GetDrained())
+}
@@ -9,9 +9,11 @@ import (
 
 const (
 	// conditions.
-	ConditionCompleted   = "Completed"   // the pipeline completed
-	ConditionRunning     = "Running"     // added if any step is currently running
-	ConditionTerminating = "Terminating" // added if any terminator step terminated
+	ConditionCompleted    = "Completed"    // the pipeline completed
+	ConditionRunning      = "Running"      // added if any step is currently running
+	ConditionTerminating  = "Terminating"  // added if any terminator step terminated
+	ConditionSlowConsumer = "SlowConsumer" // added if a step's pending messages grew for its slowConsumerDelay, while it was at maxReplicas
+	ConditionSLOViolated  = "SLOViolated"  // added if the pipeline has an SLO, true if any of its objectives are not met
 	// container names.
 	CtrInit    = "init"
 	CtrMain    = "main"
@@ -28,27 +30,62 @@ const (
 	EnvStep             = "ARGO_DATAFLOW_STEP"
 	EnvPeekDelay        = "ARGO_DATAFLOW_PEEK_DELAY"         // how long between peeking (default 4m)
 	EnvPullPolicy       = "ARGO_DATAFLOW_PULL_POLICY"        // default ""
+	EnvNamespaceRunners = "ARGO_DATAFLOW_NAMESPACE_RUNNERS"  // JSON object of namespace to Runner, overriding the runner image and pull policy for the namespace's steps, default "{}"
+	EnvNamespaceLimits  = "ARGO_DATAFLOW_NAMESPACE_LIMITS"   // JSON object of namespace (or "*" for any other namespace) to the limits of its pipelines, enforced by the webhook, default "{}"
+	EnvRunnerImage      = "ARGO_DATAFLOW_RUNNER_IMAGE"       // default "{imagePrefix}/dataflow-runner:{version}"
+	EnvInitResources    = "ARGO_DATAFLOW_INIT_RESOURCES"     // JSON resource requirements of the init container, default the standard resources
 	EnvScalingDelay     = "ARGO_DATAFLOW_SCALING_DELAY"      // how long to wait between any scaling events (including peeking) default "4m"
 	EnvUpdateInterval   = "ARGO_DATAFLOW_UPDATE_INTERVAL"    // default "15s"
 	EnvImagePullSecrets = "ARGO_DATAFLOW_IMAGE_PULL_SECRETS" // allows providing a list of imagePullSecrets as a comma delimited string (eg. "secret1,secret2")
+	EnvNetworkPolicy    = "ARGO_DATAFLOW_NETWORK_POLICY"     // create a network policy for each step, default "false"
+	EnvRestricted       = "ARGO_DATAFLOW_RESTRICTED"         // make pods comply with the "restricted" Pod Security Standard, default "false"
+	EnvVaultAddr        = "ARGO_DATAFLOW_VAULT_ADDR"         // read secrets from Vault, e.g. "https://vault:8200", default ""
+	EnvVaultRole        = "ARGO_DATAFLOW_VAULT_ROLE"         // the Vault Kubernetes auth role, default "argo-dataflow"
+	EnvVaultAuthPath    = "ARGO_DATAFLOW_VAULT_AUTH_PATH"    // where the Vault Kubernetes auth method is mounted, default "kubernetes"
+	EnvVaultPath        = "ARGO_DATAFLOW_VAULT_PATH"         // the Vault KV v2 path containing the secrets, default "secret/data/argo-dataflow"
+	EnvServiceMesh      = "ARGO_DATAFLOW_SERVICE_MESH"       // the service mesh the pods are part of, "istio" or "", default ""
+	EnvPodRetention     = "ARGO_DATAFLOW_POD_RETENTION"      // how long to keep pods that have stopped running before deleting them, default "1h"
+	EnvFaultInjection   = "ARGO_DATAFLOW_FAULT_INJECTION"    // allow faults to be injected into sidecars for chaos testing, see KeyFaults, default "false"
+	EnvWebhook          = "ARGO_DATAFLOW_WEBHOOK"            // serve the validating webhook, which requires a certificate, default "false"
+
+	// state store env vars, see State.
+	EnvState              = "ARGO_DATAFLOW_STATE"                // JSON of the step's State
+	EnvStateKeyPrefix     = "ARGO_DATAFLOW_STATE_KEY_PREFIX"     // prefix of the step's keys in shared state stores
+	EnvStateRedisURL      = "ARGO_DATAFLOW_STATE_REDIS_URL"      // URL of the Redis state store, if not in the State
+	EnvStateRedisPassword = "ARGO_DATAFLOW_STATE_REDIS_PASSWORD" // password of the Redis state store
 	// label/annotation keys.
-	KeyDefaultContainer = "kubectl.kubernetes.io/default-container"
-	KeyDescription      = "dataflow.argoproj.io/description"
-	KeyFinalizer        = "dataflow.argoproj.io/finalizer"
-	KeyOwner            = "dataflow.argoproj.io/owner"
-	KeyPipelineName     = "dataflow.argoproj.io/pipeline-name"
-	KeyReplica          = "dataflow.argoproj.io/replica"
-	KeyStepName         = "dataflow.argoproj.io/step-name" // the step name without pipeline name prefix
-	KeyHash             = "dataflow.argoproj.io/hash"      // hash of the object
+	KeyDefaultContainer  = "kubectl.kubernetes.io/default-container"
+	KeyDescription       = "dataflow.argoproj.io/description"
+	KeyFinalizer         = "dataflow.argoproj.io/finalizer"
+	KeyOwner             = "dataflow.argoproj.io/owner"
+	KeyPipelineName      = "dataflow.argoproj.io/pipeline-name"
+	KeyPromote           = "dataflow.argoproj.io/promote" // set to "true" to promote an upgrade once at parity, or "force", see PipelineSpec.Upgrade
+	KeyReplica           = "dataflow.argoproj.io/replica"
+	KeyRollback          = "dataflow.argoproj.io/rollback"            // set on a pipeline to roll back its spec to a revision, or "previous"
+	KeyResetOffset       = "dataflow.argoproj.io/reset-offset"        // set on a step to reset its sources, see ResetOffset
+	KeyScheduleName      = "dataflow.argoproj.io/schedule-name"       // the name of the scheduled pipeline a run was created by
+	KeyStepName          = "dataflow.argoproj.io/step-name"           // the step name without pipeline name prefix
+	KeyTenant            = "dataflow.argoproj.io/tenant"              // the tenant of the pipeline, see PipelineSpec.Tenant
+	KeyHash              = "dataflow.argoproj.io/hash"                // hash of the object
+	KeyConnecting        = "dataflow.argoproj.io/connecting"          // set by the sidecar on its pod, while it retries connecting to a source or sink's broker
+	KeyFaults            = "dataflow.argoproj.io/faults"              // set on a step to inject faults into its sidecars, e.g. "sink-error=0.1", only if fault injection is enabled
+	KeyPausedSources     = "dataflow.argoproj.io/paused-sources"      // set on a step to pause some of its sources, e.g. "source-a,source-b"
+	KeyWaitingForBrokers = "dataflow.argoproj.io/waiting-for-brokers" // set by the init container on its pod, the brokers it is waiting for, see WaitForBrokers
 	// paths.
 	PathAuthorization = "/var/run/argo-dataflow/authorization" // the authorization header which must be used by the main container to speak to the sidecar
 	PathCheckout      = "/var/run/argo-dataflow/checkout"
 	PathFIFOIn        = "/var/run/argo-dataflow/in"
 	PathFIFOOut       = "/var/run/argo-dataflow/out"
+	PathExpression    = "/var/run/argo-dataflow/expression" // the current expression of a filter or map step, written by the sidecar
 	PathGroups        = "/var/run/argo-dataflow/groups"
 	PathHandlerFile   = "/var/run/argo-dataflow/handler"
 	PathKill          = "/var/run/argo-dataflow/kill"
+	PathPostStart     = "/var/run/argo-dataflow/poststart"
+	PathPostStarted   = "/var/run/argo-dataflow/post-started" // created once the main container's lifecycle.postStart has succeeded
 	PathPreStop       = "/var/run/argo-dataflow/prestop"
+	PathStdio         = "/var/run/argo-dataflow/stdio"
+	PathSecrets       = "/var/run/argo-dataflow/secrets" // secrets are mounted here, in a sub-directory named after the secret
+	PathState         = "/var/run/argo-dataflow/state"   // the volume of a disk state store is mounted here
 	PathWorkingDir    = "/var/run/argo-dataflow/wd"
 	PathVarRun        = "/var/run/argo-dataflow"
 	// other const.
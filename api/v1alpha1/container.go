@@ -16,7 +16,7 @@ func (in Container) getContainer(req getContainerReq) corev1.Container {
 	return containerBuilder{}.
 		init(req).
 		image(in.Image).
-		command(in.Command...).
+		command(in.getCommand()...).
 		args(in.Args...).
 		appendEnv(in.Env...).
 		appendVolumeMounts(in.VolumeMounts...).
@@ -24,6 +24,13 @@ func (in Container) getContainer(req getContainerReq) corev1.Container {
 		build()
 }
 
+func (in Container) getCommand() []string {
+	if in.GetIn().Stdio {
+		return append([]string{PathStdio}, in.Command...)
+	}
+	return in.Command
+}
+
 func (in Container) GetIn() *Interface {
 	if in.In != nil {
 		return in.In
@@ -29,3 +29,10 @@ func TestContainer_getContainer(t *testing.T) {
 	assert.Equal(t, x.Env, c.Env)
 	assert.Equal(t, corev1.ResourceRequirements{Requests: map[corev1.ResourceName]resource.Quantity{"cpu": resource.MustParse("2")}}, c.Resources)
 }
+
+func TestContainer_getContainer_Stdio(t *testing.T) {
+	x := Container{In: &Interface{Stdio: true}, Command: []string{"sed", "-u"}, Args: []string{"s/a/b/"}}
+	c := x.getContainer(getContainerReq{})
+	assert.Equal(t, []string{PathStdio, "sed", "-u"}, c.Command)
+	assert.Equal(t, x.Args, c.Args)
+}
@@ -8,6 +8,10 @@ type Cron struct {
 	Schedule string `json:"schedule" protobuf:"bytes,1,opt,name=schedule"`
 	// +kubebuilder:default="2006-01-02T15:04:05Z07:00"
 	Layout string `json:"layout,omitempty" protobuf:"bytes,2,opt,name=layout"`
+	// Template, if specified, is a Go template of each message, instead of the time in the layout. As well as Sprig's
+	// functions, it can use `.Time` (the time the schedule fired) and `.Schedule`, e.g.
+	// `{"date": "{{.Time.Format "2006-01-02"}}"}`.
+	Template string `json:"template,omitempty" protobuf:"bytes,3,opt,name=template"`
 }
 
 func (in Cron) GenURN(cluster, namespace string) string {
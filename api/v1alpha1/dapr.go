@@ -0,0 +1,65 @@
+package v1alpha1
+
+import "fmt"
+
+// DaprSource receives messages from a Dapr input binding. Dapr sends each event to the sidecar at `/{binding}`, so
+// the binding's name must not be one of the sidecar's own paths (e.g. "metrics", "ready").
+// https://docs.dapr.io/developing-applications/building-blocks/bindings/howto-triggers/
+type DaprSource struct {
+	// Binding is the name of the binding component.
+	Binding string `json:"binding" protobuf:"bytes,1,opt,name=binding"`
+}
+
+func (d DaprSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:dapr:%s:%s:%s", cluster, namespace, d.Binding)
+}
+
+// DaprSink sends messages to a Dapr output binding.
+// https://docs.dapr.io/developing-applications/building-blocks/bindings/howto-bindings/
+type DaprSink struct {
+	// Binding is the name of the binding component.
+	Binding string `json:"binding" protobuf:"bytes,1,opt,name=binding"`
+	// Operation is the binding's operation, the operations each binding supports are in its component's docs.
+	// +kubebuilder:default=create
+	Operation string `json:"operation,omitempty" protobuf:"bytes,2,opt,name=operation"`
+	// Metadata is sent with each message, e.g. a Kafka binding's "key".
+	Metadata map[string]string `json:"metadata,omitempty" protobuf:"bytes,3,rep,name=metadata"`
+}
+
+func (d DaprSink) GetOperation() string {
+	return StringOr(d.Operation, "create")
+}
+
+func (in StepSpec) usesDapr() bool {
+	for _, s := range in.Sources {
+		if s.Dapr != nil {
+			return true
+		}
+	}
+	for _, s := range in.Sinks {
+		if s.Dapr != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetDaprAnnotations returns the annotations that have Dapr inject its sidecar into the step's pods, if the step has
+// Dapr sources or sinks. Dapr's sidecar sends input binding events to the sidecar's HTTP port.
+// https://docs.dapr.io/reference/arguments-annotations-overview/
+func (in Step) GetDaprAnnotations() map[string]string {
+	if !in.Spec.usesDapr() {
+		return nil
+	}
+	annotations := map[string]string{
+		"dapr.io/enabled": "true",
+		"dapr.io/app-id":  in.Name,
+	}
+	for _, s := range in.Spec.Sources {
+		if s.Dapr != nil {
+			annotations["dapr.io/app-port"] = "3569"
+			annotations["dapr.io/app-protocol"] = "http"
+		}
+	}
+	return annotations
+}
@@ -0,0 +1,24 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestStep_GetDaprAnnotations(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		assert.Nil(t, Step{}.GetDaprAnnotations())
+	})
+	t.Run("Sink", func(t *testing.T) {
+		s := Step{ObjectMeta: metav1.ObjectMeta{Name: "my-pl-my-step"}, Spec: StepSpec{Sinks: []Sink{{Dapr: &DaprSink{Binding: "my-binding"}}}}}
+		assert.Equal(t, map[string]string{"dapr.io/enabled": "true", "dapr.io/app-id": "my-pl-my-step"}, s.GetDaprAnnotations())
+		assert.Len(t, s.GetNetworkPolicyObj("my-pl", "").Spec.Egress, 1)
+	})
+	t.Run("Source", func(t *testing.T) {
+		s := Step{ObjectMeta: metav1.ObjectMeta{Name: "my-pl-my-step"}, Spec: StepSpec{Sources: []Source{{Dapr: &DaprSource{Binding: "my-binding"}}}}}
+		assert.Equal(t, "3569", s.GetDaprAnnotations()["dapr.io/app-port"])
+		assert.Equal(t, "http", s.GetDaprAnnotations()["dapr.io/app-protocol"])
+	})
+}
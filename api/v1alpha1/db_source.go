@@ -14,4 +14,24 @@ type DBSource struct {
 	CommitInterval metav1.Duration `json:"commitInterval,omitempty" protobuf:"bytes,5,opt,name=commitInterval"`
 	// +kubebuilder:default=true
 	InitSchema bool `json:"initSchema,omitempty" protobuf:"bytes,6,opt,name=initSchema"`
+	// OffsetStore is where the offset, the offset column's value of the last row processed, is committed: "Table", the
+	// argo_dataflow_offsets table in the database (created if InitSchema), or "Status", the step's status, so the
+	// source only needs to read from the database.
+	// +kubebuilder:default=Table
+	OffsetStore DBOffsetStore `json:"offsetStore,omitempty" protobuf:"bytes,7,opt,name=offsetStore,casttype=DBOffsetStore"`
+}
+
+// +kubebuilder:validation:Enum=Table;Status
+type DBOffsetStore string
+
+const (
+	DBOffsetStoreTable  DBOffsetStore = "Table"
+	DBOffsetStoreStatus DBOffsetStore = "Status"
+)
+
+func (in DBSource) GetOffsetStore() DBOffsetStore {
+	if in.OffsetStore == "" {
+		return DBOffsetStoreTable
+	}
+	return in.OffsetStore
 }
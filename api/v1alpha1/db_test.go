@@ -20,3 +20,8 @@ func TestDatabase_GenURN(t *testing.T) {
 		assert.Equal(t, "urn:dataflow:db:my-name.secret.my-ns.my-cluster:my-key", urn)
 	})
 }
+
+func TestDBSource_GetOffsetStore(t *testing.T) {
+	assert.Equal(t, DBOffsetStoreTable, DBSource{}.GetOffsetStore())
+	assert.Equal(t, DBOffsetStoreStatus, DBSource{OffsetStore: DBOffsetStoreStatus}.GetOffsetStore())
+}
@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ElasticsearchSource runs a query against an Elasticsearch index, once, when the source starts, and emits each hit as
+// a message. It pages through the hits using a point in time, so it sees a consistent view of the index, even if it is
+// being written to. Requires Elasticsearch 7.12 or later.
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/point-in-time-api.html
+type ElasticsearchSource struct {
+	// Name of the "dataflow-elasticsearch-{name}" secret, whose "url", "username" and "password" are used if not
+	// specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// URL of the Elasticsearch cluster, e.g. "http://elasticsearch:9200".
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// Index is the index, alias or data stream to query, or a comma-separated list of them.
+	Index string `json:"index" protobuf:"bytes,3,opt,name=index"`
+	// Query is the query, in Elasticsearch's query DSL, as JSON, e.g. `{"range": {"@timestamp": {"gte": "now-1d"}}}`.
+	// +kubebuilder:default="{\"match_all\": {}}"
+	Query string `json:"query,omitempty" protobuf:"bytes,4,opt,name=query"`
+	// PageSize is the number of hits fetched by each request.
+	// +kubebuilder:default=1000
+	PageSize uint32 `json:"pageSize,omitempty" protobuf:"varint,5,opt,name=pageSize"`
+	// KeepAlive is how long the point in time is kept between requests. It must be longer than it takes to process a
+	// page of hits.
+	// +kubebuilder:default="1m"
+	KeepAlive metav1.Duration `json:"keepAlive,omitempty" protobuf:"bytes,6,opt,name=keepAlive"`
+	// UsernameSecret is the secret selector to the username used for basic authentication.
+	UsernameSecret *corev1.SecretKeySelector `json:"usernameSecret,omitempty" protobuf:"bytes,7,opt,name=usernameSecret"`
+	// PasswordSecret is the secret selector to the password used for basic authentication.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,8,opt,name=passwordSecret"`
+}
+
+func (in ElasticsearchSource) GetQuery() string {
+	return StringOr(in.Query, `{"match_all": {}}`)
+}
+
+func (in ElasticsearchSource) GetPageSize() uint32 {
+	if in.PageSize > 0 {
+		return in.PageSize
+	}
+	return 1000
+}
+
+func (in ElasticsearchSource) GetKeepAlive() time.Duration {
+	if in.KeepAlive.Duration > 0 {
+		return in.KeepAlive.Duration
+	}
+	return time.Minute
+}
+
+func (in ElasticsearchSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:elasticsearch:%s:%s", in.URL, in.Index)
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestElasticsearchSource(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		x := ElasticsearchSource{}
+		assert.Equal(t, `{"match_all": {}}`, x.GetQuery())
+		assert.Equal(t, uint32(1000), x.GetPageSize())
+		assert.Equal(t, time.Minute, x.GetKeepAlive())
+	})
+	t.Run("Specified", func(t *testing.T) {
+		x := ElasticsearchSource{Query: `{"term": {"a": 1}}`, PageSize: 10, KeepAlive: metav1.Duration{Duration: time.Second}}
+		assert.Equal(t, `{"term": {"a": 1}}`, x.GetQuery())
+		assert.Equal(t, uint32(10), x.GetPageSize())
+		assert.Equal(t, time.Second, x.GetKeepAlive())
+	})
+	t.Run("GenURN", func(t *testing.T) {
+		x := ElasticsearchSource{URL: "http://elasticsearch:9200", Index: "my-index"}
+		assert.Equal(t, "urn:dataflow:elasticsearch:http://elasticsearch:9200:my-index", x.GenURN(cluster, namespace))
+	})
+}
@@ -0,0 +1,10 @@
+package v1alpha1
+
+import corev1 "k8s.io/api/core/v1"
+
+// Encryption encrypts data at rest using AES-GCM. The encrypted data is the 12 byte nonce, followed by the ciphertext.
+type Encryption struct {
+	// KeySecret is the secret containing the key, which must be 16, 24, or 32 bytes, selecting AES-128, AES-192, or
+	// AES-256.
+	KeySecret corev1.SecretKeySelector `json:"keySecret" protobuf:"bytes,1,opt,name=keySecret"`
+}
@@ -0,0 +1,26 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// EventHubs connects to an Azure Event Hubs namespace using its Kafka endpoint, so Event Hubs can be used without
+// bridging through a Kafka cluster. The topic is the name of the event hub. A source's consumer group is its group ID,
+// and its checkpoints are the offsets it commits, which Event Hubs stores for the consumer group. This is not a native
+// (AMQP) Event Hubs client: checkpoints are not kept in a Blob Storage checkpoint store, so are not shared with Event
+// Hubs SDK consumers.
+// https://docs.microsoft.com/en-us/azure/event-hubs/event-hubs-for-kafka-ecosystem-overview
+type EventHubs struct {
+	// Namespace is the name of the Event Hubs namespace, e.g. "my-namespace" for
+	// "my-namespace.servicebus.windows.net".
+	Namespace string `json:"namespace" protobuf:"bytes,1,opt,name=namespace"`
+	// ConnectionStringSecret is the secret selector to a connection string of the namespace, or the event hub, e.g.
+	// "Endpoint=sb://my-namespace.servicebus.windows.net/;SharedAccessKeyName=...;SharedAccessKey=...". Its policy must
+	// have the Listen claim for sources, and the Send claim for sinks.
+	ConnectionStringSecret corev1.SecretKeySelector `json:"connectionStringSecret" protobuf:"bytes,2,opt,name=connectionStringSecret"`
+}
+
+// GetBootstrapServer returns the address of the namespace's Kafka endpoint.
+func (in EventHubs) GetBootstrapServer() string {
+	return in.Namespace + ".servicebus.windows.net:9093"
+}
@@ -0,0 +1,93 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// +kubebuilder:validation:Enum="";RFC3339;Unix;UnixMilli
+type EventTimeFormat string
+
+const (
+	EventTimeRFC3339   EventTimeFormat = "RFC3339"
+	EventTimeUnix      EventTimeFormat = "Unix"      // seconds since the epoch
+	EventTimeUnixMilli EventTimeFormat = "UnixMilli" // milliseconds since the epoch
+)
+
+// EventTime extracts the time an event happened from each message, so the step's watermark (the latest event time it
+// has processed) can be tracked. Exactly one of JSONPath or Header must be specified.
+type EventTime struct {
+	// JSONPath is the path of the time in the message, which must be JSON, e.g. "$.time" or "$.event.createdAt".
+	JSONPath string `json:"jsonPath,omitempty" protobuf:"bytes,1,opt,name=jsonPath"`
+	// Header is the name of the header containing the time. Only Kafka and HTTP messages have headers.
+	Header string `json:"header,omitempty" protobuf:"bytes,2,opt,name=header"`
+	// Format is the format of the time, "RFC3339", "Unix" or "UnixMilli". Defaults to "RFC3339".
+	Format EventTimeFormat `json:"format,omitempty" protobuf:"bytes,3,opt,name=format,casttype=EventTimeFormat"`
+}
+
+func (in EventTime) GetFormat() EventTimeFormat {
+	if in.Format == "" {
+		return EventTimeRFC3339
+	}
+	return in.Format
+}
+
+// GetPath returns the JSON path's keys, which may only be a sequence of object keys, e.g. "$.event.createdAt".
+func (in EventTime) GetPath() ([]string, error) {
+	if !strings.HasPrefix(in.JSONPath, "$.") || strings.Contains(in.JSONPath, "..") || strings.HasSuffix(in.JSONPath, ".") {
+		return nil, fmt.Errorf("jsonPath %q must be a sequence of object keys, e.g. \"$.event.createdAt\"", in.JSONPath)
+	}
+	return strings.Split(strings.TrimPrefix(in.JSONPath, "$."), "."), nil
+}
+
+// Extract returns the event time of the message, given its header.
+func (in EventTime) Extract(msg []byte, header func(key string) string) (time.Time, error) {
+	var value string
+	if in.Header != "" {
+		if value = header(in.Header); value == "" {
+			return time.Time{}, fmt.Errorf("header %q not found", in.Header)
+		}
+	} else {
+		path, err := in.GetPath()
+		if err != nil {
+			return time.Time{}, err
+		}
+		var v interface{}
+		if err := json.Unmarshal(msg, &v); err != nil {
+			return time.Time{}, fmt.Errorf("failed to unmarshal message: %w", err)
+		}
+		for _, key := range path {
+			object, ok := v.(map[string]interface{})
+			if !ok {
+				return time.Time{}, fmt.Errorf("%q not found", in.JSONPath)
+			}
+			if v, ok = object[key]; !ok {
+				return time.Time{}, fmt.Errorf("%q not found", in.JSONPath)
+			}
+		}
+		switch x := v.(type) {
+		case string:
+			value = x
+		case float64:
+			value = strconv.FormatFloat(x, 'f', -1, 64)
+		default:
+			return time.Time{}, fmt.Errorf("%q must be a string or number", in.JSONPath)
+		}
+	}
+	switch in.GetFormat() {
+	case EventTimeUnix, EventTimeUnixMilli:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("event time %q must be a number", value)
+		}
+		if in.GetFormat() == EventTimeUnixMilli {
+			return time.Unix(0, int64(n*float64(time.Millisecond))), nil
+		}
+		return time.Unix(0, int64(n*float64(time.Second))), nil
+	default:
+		return time.Parse(time.RFC3339, value)
+	}
+}
@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventTime_GetPath(t *testing.T) {
+	path, err := EventTime{JSONPath: "$.event.createdAt"}.GetPath()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"event", "createdAt"}, path)
+	for _, p := range []string{"", "$", "$.", "event", "$..event", "$.event."} {
+		_, err := EventTime{JSONPath: p}.GetPath()
+		assert.Error(t, err, p)
+	}
+}
+
+func TestEventTime_Extract(t *testing.T) {
+	noHeader := func(string) string { return "" }
+	expected := time.Date(2021, 9, 1, 12, 0, 0, 0, time.UTC)
+	t.Run("JSONPath", func(t *testing.T) {
+		x, err := EventTime{JSONPath: "$.event.time"}.Extract([]byte(`{"event":{"time":"2021-09-01T12:00:00Z"}}`), noHeader)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(x))
+	})
+	t.Run("Unix", func(t *testing.T) {
+		x, err := EventTime{JSONPath: "$.time", Format: EventTimeUnix}.Extract([]byte(`{"time":1630497600}`), noHeader)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(x))
+	})
+	t.Run("UnixMilli", func(t *testing.T) {
+		x, err := EventTime{JSONPath: "$.time", Format: EventTimeUnixMilli}.Extract([]byte(`{"time":"1630497600000"}`), noHeader)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(x))
+	})
+	t.Run("Header", func(t *testing.T) {
+		x, err := EventTime{Header: "time"}.Extract(nil, func(key string) string {
+			return map[string]string{"time": "2021-09-01T12:00:00Z"}[key]
+		})
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(x))
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := EventTime{JSONPath: "$.event.time"}.Extract([]byte(`{"event":"now"}`), noHeader)
+		assert.EqualError(t, err, `"$.event.time" not found`)
+		_, err = EventTime{Header: "time"}.Extract(nil, noHeader)
+		assert.EqualError(t, err, `header "time" not found`)
+	})
+	t.Run("NotJSON", func(t *testing.T) {
+		_, err := EventTime{JSONPath: "$.time"}.Extract([]byte(`time`), noHeader)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,85 @@
+package v1alpha1
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FileSink appends messages to files on a volume, e.g. a persistent volume claim, for when object storage is not
+// available. Files are rotated when they reach MaxSize or MaxAge. A file is written with the ".inprogress" suffix,
+// which is removed when it is rotated, so anything reading the volume should ignore files with that suffix.
+type FileSink struct {
+	// Volume to write the files to.
+	Volume AbstractVolumeSource `json:"volume" protobuf:"bytes,1,opt,name=volume"`
+	// Partition is an expression that returns the directory, within the volume, each message is written to, e.g.
+	// `"date=" + ctx.time[0:10]`. If omitted, files are written to the root of the volume.
+	Partition string `json:"partition,omitempty" protobuf:"bytes,2,opt,name=partition"`
+	// Format of the files: "NDJSON", one JSON message per line, or "Parquet", which requires ParquetSchema.
+	// +kubebuilder:default=NDJSON
+	Format FileFormat `json:"format,omitempty" protobuf:"bytes,3,opt,name=format,casttype=FileFormat"`
+	// ParquetSchema is the schema of Parquet files, in parquet-go's JSON format. Messages must be JSON objects.
+	// https://github.com/xitongsys/parquet-go#json
+	ParquetSchema string `json:"parquetSchema,omitempty" protobuf:"bytes,4,opt,name=parquetSchema"`
+	// MaxSize is the number of bytes of messages written to a file before it is rotated. Parquet files are kept in
+	// memory until they are rotated, so this must fit in the sidecar's memory.
+	// +kubebuilder:default="64Mi"
+	MaxSize resource.Quantity `json:"maxSize,omitempty" protobuf:"bytes,5,opt,name=maxSize"`
+	// MaxAge is how long a file is written to before it is rotated.
+	// +kubebuilder:default="10m"
+	MaxAge metav1.Duration `json:"maxAge,omitempty" protobuf:"bytes,6,opt,name=maxAge"`
+	// Sync is when files are synced to disk: "Always", before each message is acknowledged, or "Rotate", when the file
+	// is rotated, which is faster, but messages that have been acknowledged are lost if the node fails. Parquet files
+	// can only be synced when they are rotated.
+	// +kubebuilder:default=Rotate
+	Sync FileSync `json:"sync,omitempty" protobuf:"bytes,7,opt,name=sync,casttype=FileSync"`
+	// OnRotate, if specified, is sent a JSON object describing each file when it is rotated, e.g.
+	// `{"path": "date=2021-10-15/my-pipeline-main-0-1634284800000000000.ndjson", "messages": 1000, "size": 65536}`,
+	// where "my-pipeline-main-0" is the pipeline, step and replica.
+	OnRotate *HTTPSink `json:"onRotate,omitempty" protobuf:"bytes,8,opt,name=onRotate"`
+}
+
+// +kubebuilder:validation:Enum=NDJSON;Parquet
+type FileFormat string
+
+const (
+	FileFormatNDJSON  FileFormat = "NDJSON"
+	FileFormatParquet FileFormat = "Parquet"
+)
+
+// +kubebuilder:validation:Enum=Always;Rotate
+type FileSync string
+
+const (
+	FileSyncAlways FileSync = "Always"
+	FileSyncRotate FileSync = "Rotate"
+)
+
+func (in FileSink) GetFormat() FileFormat {
+	if in.Format == "" {
+		return FileFormatNDJSON
+	}
+	return in.Format
+}
+
+func (in FileSink) GetMaxSize() int64 {
+	if in.MaxSize.IsZero() {
+		return 64 * 1024 * 1024
+	}
+	return in.MaxSize.Value()
+}
+
+func (in FileSink) GetMaxAge() time.Duration {
+	if in.MaxAge.Duration > 0 {
+		return in.MaxAge.Duration
+	}
+	return 10 * time.Minute
+}
+
+func (in FileSink) GetSync() FileSync {
+	if in.Sync == "" {
+		return FileSyncRotate
+	}
+	return in.Sync
+}
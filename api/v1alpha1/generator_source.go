@@ -0,0 +1,34 @@
+package v1alpha1
+
+import "fmt"
+
+// GeneratorSource generates messages from a template, for load testing and demoing pipelines without an external
+// producer.
+type GeneratorSource struct {
+	// Rate is the most messages each replica generates per second.
+	// +kubebuilder:default=1
+	Rate uint32 `json:"rate,omitempty" protobuf:"varint,1,opt,name=rate"`
+	// Template is a Go template of each message. As well as Sprig's functions, it can use `seq` (the message's
+	// sequence number, starting at zero on each replica), `replica`, and fake data such as `name` and `email`, e.g.
+	// `{"id": {{seq}}, "name": "{{name}}"}`.
+	// +kubebuilder:default="{{seq}}"
+	Template string `json:"template,omitempty" protobuf:"bytes,2,opt,name=template"`
+	// Count, if greater than zero, is the number of messages each replica generates. If the source is bounded, it is
+	// drained once they have been processed.
+	Count uint64 `json:"count,omitempty" protobuf:"varint,3,opt,name=count"`
+}
+
+func (g GeneratorSource) GetRate() uint32 {
+	if g.Rate > 0 {
+		return g.Rate
+	}
+	return 1
+}
+
+func (g GeneratorSource) GetTemplate() string {
+	return StringOr(g.Template, "{{seq}}")
+}
+
+func (g GeneratorSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:generator:%s:%s", cluster, namespace)
+}
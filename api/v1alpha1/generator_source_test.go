@@ -0,0 +1,21 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGeneratorSource_GetRate(t *testing.T) {
+	assert.Equal(t, uint32(1), GeneratorSource{}.GetRate())
+	assert.Equal(t, uint32(10), GeneratorSource{Rate: 10}.GetRate())
+}
+
+func TestGeneratorSource_GetTemplate(t *testing.T) {
+	assert.Equal(t, "{{seq}}", GeneratorSource{}.GetTemplate())
+	assert.Equal(t, "{{name}}", GeneratorSource{Template: "{{name}}"}.GetTemplate())
+}
+
+func TestGeneratorSource_GenURN(t *testing.T) {
+	assert.Equal(t, "urn:dataflow:generator:my-cluster:my-ns", Source{Generator: &GeneratorSource{}}.GenURN("my-cluster", "my-ns"))
+}
@@ -19,4 +19,16 @@ type GetPodSpecReq struct {
 	ImagePullSecrets []corev1.LocalObjectReference `protobuf:"bytes,11,rep,name=imagePullSecrets"`
 	Hostname         string                        `protobuf:"bytes,12,opt,name=hostname"`
 	Subdomain        string                        `protobuf:"bytes,13,opt,name=subdomain"`
+	Restricted       bool                          `protobuf:"varint,14,opt,name=restricted"`
+	// InitResources, if specified, are the init container's resources, rather than the standard resources.
+	InitResources *corev1.ResourceRequirements `protobuf:"bytes,15,opt,name=initResources"`
+	// FaultInjection allows faults to be injected into the sidecar, see KeyFaults.
+	FaultInjection bool `protobuf:"varint,16,opt,name=faultInjection"`
+}
+
+func (in GetPodSpecReq) getInitResources() corev1.ResourceRequirements {
+	if in.InitResources != nil {
+		return *in.InitResources
+	}
+	return standardResources
 }
@@ -1,3 +1,11 @@
 package v1alpha1
 
-type HTTP struct{}
+const defaultInPort = 8080
+
+type HTTP struct {
+	// Container is the name of the container that serves the interface, either the main container (the default) or one
+	// of the step's containers.
+	Container string `json:"container,omitempty" protobuf:"bytes,1,opt,name=container"`
+	// Port is the port the interface is served on. Defaults to the first port of the container, or 8080.
+	Port int32 `json:"port,omitempty" protobuf:"varint,2,opt,name=port"`
+}
@@ -0,0 +1,22 @@
+package v1alpha1
+
+type HTTPIngress struct {
+	// Host is the host the ingress routes, if not specified, every host is routed.
+	Host string `json:"host,omitempty" protobuf:"bytes,1,opt,name=host"`
+	// Path is the path prefix the ingress routes, defaults to `/`. Requests must still be sent to
+	// `/sources/{sourceName}`, so this is only useful if the ingress controller rewrites the path.
+	Path string `json:"path,omitempty" protobuf:"bytes,2,opt,name=path"`
+	// IngressClassName is the class of the ingress, if not specified, the cluster's default class is used.
+	IngressClassName string `json:"ingressClassName,omitempty" protobuf:"bytes,3,opt,name=ingressClassName"`
+	// Annotations are added to the ingress, e.g. to tell the ingress controller the service is HTTPS.
+	Annotations map[string]string `json:"annotations,omitempty" protobuf:"bytes,4,rep,name=annotations"`
+	// TLSSecretName, if specified, is the secret containing the certificate and key the ingress serves.
+	TLSSecretName string `json:"tlsSecretName,omitempty" protobuf:"bytes,5,opt,name=tlsSecretName"`
+}
+
+func (in HTTPIngress) GetPath() string {
+	if in.Path == "" {
+		return "/"
+	}
+	return in.Path
+}
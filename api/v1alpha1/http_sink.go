@@ -4,4 +4,12 @@ type HTTPSink struct {
 	URL                string       `json:"url" protobuf:"bytes,1,opt,name=url"`
 	Headers            []HTTPHeader `json:"headers,omitempty" protobuf:"bytes,2,rep,name=headers"`
 	InsecureSkipVerify bool         `json:"insecureSkipVerify,omitempty" protobuf:"varint,3,opt,name=insecureSkipVerify"`
+	// Name of the HTTP configuration. Each `headers.{Header-Name}` key in `secret/dataflow-http-{name}` is sent as that
+	// header, unless the header is specified in `headers`.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,4,opt,name=name"`
+	// Dedupe, if true, records the ID of each message sent in the step's state, and does not send messages again, e.g.
+	// when a source redelivers them after a restart. With a disk or Redis state, this gives effectively-once delivery to
+	// endpoints that are not idempotent. IDs are kept until the state's TTL elapses.
+	Dedupe bool `json:"dedupe,omitempty" protobuf:"varint,5,opt,name=dedupe"`
 }
@@ -2,10 +2,26 @@ package v1alpha1
 
 import (
 	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 type HTTPSource struct {
 	ServiceName string `json:"serviceName,omitempty" protobuf:"bytes,1,opt,name=serviceName"` // the service name to create, defaults to `${pipelineName}-${stepName}`.
+	// OIDC, if specified, requires requests to present a JWT from the issuer, rather than the step's bearer token.
+	OIDC *OIDC `json:"oidc,omitempty" protobuf:"bytes,2,opt,name=oidc"`
+	// TLS, if specified, is the certificate and key the source serves, rather than a self-signed certificate,
+	// e.g. a certificate issued by cert-manager. The CA certificate is not used.
+	TLS *TLS `json:"tls,omitempty" protobuf:"bytes,3,opt,name=tls"`
+	// ServiceType is the type of the service, e.g. `LoadBalancer` to expose the source outside the cluster. Defaults
+	// to `ClusterIP`.
+	// +kubebuilder:validation:Enum="";ClusterIP;NodePort;LoadBalancer
+	ServiceType corev1.ServiceType `json:"serviceType,omitempty" protobuf:"bytes,4,opt,name=serviceType,casttype=k8s.io/api/core/v1.ServiceType"`
+	// Ingress, if specified, has the controller create an ingress for the service.
+	Ingress *HTTPIngress `json:"ingress,omitempty" protobuf:"bytes,5,opt,name=ingress"`
+	// TokenSecret, if specified, is the bearer token that requests must present, rather than the one the controller
+	// generates, so it can be managed, and shared with clients, like any other credential.
+	TokenSecret *corev1.SecretKeySelector `json:"tokenSecret,omitempty" protobuf:"bytes,6,opt,name=tokenSecret"`
 }
 
 func (in HTTPSource) GenURN(cluster, namespace string) string {
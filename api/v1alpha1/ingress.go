@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetIngressObj returns an ingress that routes to the HTTP source's service. The service is HTTPS, so the ingress
+// controller must usually be told this using an annotation.
+func (in Step) GetIngressObj(serviceName, pipelineName string, x HTTPIngress) *networkingv1.Ingress {
+	pathType := networkingv1.PathTypePrefix
+	obj := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       in.Namespace,
+			Name:            serviceName,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(in.GetObjectMeta(), StepGroupVersionKind)},
+			Labels:          in.GetObjectLabels(pipelineName),
+			Annotations:     x.Annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: x.Host,
+				IngressRuleValue: networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+					Paths: []networkingv1.HTTPIngressPath{{
+						Path:     x.GetPath(),
+						PathType: &pathType,
+						Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+							Name: serviceName,
+							Port: networkingv1.ServiceBackendPort{Number: 443},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+	if x.IngressClassName != "" {
+		obj.Spec.IngressClassName = &x.IngressClassName
+	}
+	if x.TLSSecretName != "" {
+		tls := networkingv1.IngressTLS{SecretName: x.TLSSecretName}
+		if x.Host != "" {
+			tls.Hosts = []string{x.Host}
+		}
+		obj.Spec.TLS = []networkingv1.IngressTLS{tls}
+	}
+	return obj
+}
@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep_GetIngressObj(t *testing.T) {
+	step := Step{Spec: StepSpec{Name: "main"}}
+	step.Name = "my-pl-main"
+	step.Namespace = "my-ns"
+	t.Run("Default", func(t *testing.T) {
+		obj := step.GetIngressObj("my-svc", "my-pl", HTTPIngress{})
+		assert.Equal(t, "my-svc", obj.Name)
+		assert.Equal(t, "my-ns", obj.Namespace)
+		assert.Nil(t, obj.Spec.IngressClassName)
+		assert.Empty(t, obj.Spec.TLS)
+		if assert.Len(t, obj.Spec.Rules, 1) {
+			r := obj.Spec.Rules[0]
+			assert.Empty(t, r.Host)
+			if assert.Len(t, r.HTTP.Paths, 1) {
+				p := r.HTTP.Paths[0]
+				assert.Equal(t, "/", p.Path)
+				assert.Equal(t, "my-svc", p.Backend.Service.Name)
+				assert.Equal(t, int32(443), p.Backend.Service.Port.Number)
+			}
+		}
+	})
+	t.Run("Specified", func(t *testing.T) {
+		obj := step.GetIngressObj("my-svc", "my-pl", HTTPIngress{
+			Host:             "my-host",
+			Path:             "/my-path",
+			IngressClassName: "nginx",
+			Annotations:      map[string]string{"nginx.ingress.kubernetes.io/backend-protocol": "HTTPS"},
+			TLSSecretName:    "my-cert",
+		})
+		assert.Equal(t, "HTTPS", obj.Annotations["nginx.ingress.kubernetes.io/backend-protocol"])
+		assert.Equal(t, "nginx", *obj.Spec.IngressClassName)
+		assert.Equal(t, "my-host", obj.Spec.Rules[0].Host)
+		assert.Equal(t, "/my-path", obj.Spec.Rules[0].HTTP.Paths[0].Path)
+		if assert.Len(t, obj.Spec.TLS, 1) {
+			assert.Equal(t, "my-cert", obj.Spec.TLS[0].SecretName)
+			assert.Equal(t, []string{"my-host"}, obj.Spec.TLS[0].Hosts)
+		}
+	})
+}
@@ -3,6 +3,10 @@ package v1alpha1
 type Interface struct {
 	FIFO bool  `json:"fifo,omitempty" protobuf:"varint,1,opt,name=fifo"`
 	HTTP *HTTP `json:"http,omitempty" protobuf:"bytes,2,opt,name=http"`
+	// Stdio writes each message to the container's standard input, as a line, and reads the line it writes to standard
+	// output in reply, which is sent to the sinks, unless it is empty. The container's command is required, and is run
+	// by a wrapper that serves the HTTP interface, so Unix filters (e.g. `sed -u`) can be used as handlers.
+	Stdio bool `json:"stdio,omitempty" protobuf:"varint,3,opt,name=stdio"`
 }
 
 var DefaultInterface = &Interface{HTTP: &HTTP{}}
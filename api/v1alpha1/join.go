@@ -0,0 +1,60 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Join correlates messages from two of the step's sources by key, and sends a merged message to the sinks for each
+// pair, e.g. to join orders with their payments. It runs inside the sidecar, so no main container is created.
+//
+// Messages wait for their match in the step's state, by default in memory. The step should have a single replica,
+// otherwise messages with the same key may be consumed by different replicas and never match.
+//
+// The merged message is a JSON object, e.g. `{"key": "my-key", "left": {...}, "right": {...}}`. Messages that are
+// JSON are embedded as they are, otherwise as strings.
+type Join struct {
+	// Left is the name of the source of the left messages.
+	Left string `json:"left" protobuf:"bytes,1,opt,name=left"`
+	// Right is the name of the source of the right messages.
+	Right string `json:"right" protobuf:"bytes,2,opt,name=right"`
+	// Key is an expression that returns a message's key as a string, e.g. `string(object(msg).orderId)`. It is used
+	// for messages from both sources. Each message matches the oldest waiting message with the same key from the
+	// other source.
+	Key string `json:"key" protobuf:"bytes,3,opt,name=key"`
+	// Window is how long a message waits for its match.
+	// +kubebuilder:default="1m"
+	Window *metav1.Duration `json:"window,omitempty" protobuf:"bytes,4,opt,name=window"`
+	// Unmatched is what happens to messages that are not matched within the window.
+	// +kubebuilder:default=Drop
+	Unmatched JoinUnmatched `json:"unmatched,omitempty" protobuf:"bytes,5,opt,name=unmatched,casttype=JoinUnmatched"`
+	// Lateness, if specified, handles messages that are late by event time, rather than joining them.
+	Lateness *Lateness `json:"lateness,omitempty" protobuf:"bytes,6,opt,name=lateness"`
+}
+
+// +kubebuilder:validation:Enum=Drop;Emit;DeadLetterQueue
+type JoinUnmatched string
+
+const (
+	// JoinUnmatchedDrop drops unmatched messages.
+	JoinUnmatchedDrop JoinUnmatched = "Drop"
+	// JoinUnmatchedEmit sends unmatched messages to the sinks, merged without the other side, i.e. an outer join.
+	JoinUnmatchedEmit JoinUnmatched = "Emit"
+	// JoinUnmatchedDeadLetterQueue sends unmatched messages, as they are, to the step's dead-letter queue.
+	JoinUnmatchedDeadLetterQueue JoinUnmatched = "DeadLetterQueue"
+)
+
+func (in Join) GetWindow() time.Duration {
+	if in.Window != nil {
+		return in.Window.Duration
+	}
+	return time.Minute
+}
+
+func (in Join) GetUnmatched() JoinUnmatched {
+	if in.Unmatched == "" {
+		return JoinUnmatchedDrop
+	}
+	return in.Unmatched
+}
@@ -0,0 +1,19 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestJoin_GetWindow(t *testing.T) {
+	assert.Equal(t, time.Minute, Join{}.GetWindow())
+	assert.Equal(t, time.Second, Join{Window: &metav1.Duration{Duration: time.Second}}.GetWindow())
+}
+
+func TestJoin_GetUnmatched(t *testing.T) {
+	assert.Equal(t, JoinUnmatchedDrop, Join{}.GetUnmatched())
+	assert.Equal(t, JoinUnmatchedEmit, Join{Unmatched: JoinUnmatchedEmit}.GetUnmatched())
+}
@@ -35,6 +35,12 @@ type Kafka struct {
 	Name        string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
 	KafkaConfig `json:",inline" protobuf:"bytes,4,opt,name=kafkaConfig"`
 	Topic       string `json:"topic" protobuf:"bytes,3,opt,name=topic"`
+	// Strimzi has the controller create the topic, and a user to access it.
+	Strimzi *Strimzi `json:"strimzi,omitempty" protobuf:"bytes,5,opt,name=strimzi"`
+	// CreateTopic has the sidecar create the topic when it starts, if it does not exist.
+	CreateTopic *KafkaCreateTopic `json:"createTopic,omitempty" protobuf:"bytes,6,opt,name=createTopic"`
+	// EventHubs connects to an Azure Event Hubs namespace using its Kafka endpoint, rather than to a Kafka cluster.
+	EventHubs *EventHubs `json:"eventHubs,omitempty" protobuf:"bytes,7,opt,name=eventHubs"`
 }
 
 func (in Kafka) GenURN(cluster, namespace string) string {
@@ -0,0 +1,41 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type KafkaCreateTopic struct {
+	// Partitions is the number of partitions the topic is created with.
+	// +kubebuilder:default=1
+	Partitions int32 `json:"partitions,omitempty" protobuf:"varint,1,opt,name=partitions"`
+	// ReplicationFactor is the replication factor the topic is created with.
+	// +kubebuilder:default=1
+	ReplicationFactor int32 `json:"replicationFactor,omitempty" protobuf:"varint,2,opt,name=replicationFactor"`
+	// Retention is how long messages are kept (`retention.ms`). If not specified, the broker's default is used.
+	Retention *metav1.Duration `json:"retention,omitempty" protobuf:"bytes,3,opt,name=retention"`
+}
+
+func (in KafkaCreateTopic) GetPartitions() int {
+	if in.Partitions < 1 {
+		return 1
+	}
+	return int(in.Partitions)
+}
+
+func (in KafkaCreateTopic) GetReplicationFactor() int {
+	if in.ReplicationFactor < 1 {
+		return 1
+	}
+	return int(in.ReplicationFactor)
+}
+
+// GetConfig returns the topic-level configuration, e.g. `retention.ms`.
+func (in KafkaCreateTopic) GetConfig() map[string]string {
+	config := map[string]string{}
+	if in.Retention != nil {
+		config["retention.ms"] = fmt.Sprint(in.Retention.Milliseconds())
+	}
+	return config
+}
@@ -0,0 +1,24 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestKafkaCreateTopic(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		x := KafkaCreateTopic{}
+		assert.Equal(t, 1, x.GetPartitions())
+		assert.Equal(t, 1, x.GetReplicationFactor())
+		assert.Empty(t, x.GetConfig())
+	})
+	t.Run("Specified", func(t *testing.T) {
+		x := KafkaCreateTopic{Partitions: 3, ReplicationFactor: 2, Retention: &metav1.Duration{Duration: time.Hour}}
+		assert.Equal(t, 3, x.GetPartitions())
+		assert.Equal(t, 2, x.GetReplicationFactor())
+		assert.Equal(t, map[string]string{"retention.ms": "3600000"}, x.GetConfig())
+	})
+}
@@ -0,0 +1,13 @@
+package v1alpha1
+
+// KafkaHeaderMatch matches messages by one of their headers.
+type KafkaHeaderMatch struct {
+	Key string `json:"key" protobuf:"bytes,1,opt,name=key"`
+	// Value is the value the header must have. If not specified, the header only needs to be present.
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+}
+
+// Matches returns whether the header matches, given its value and whether it is present.
+func (in KafkaHeaderMatch) Matches(value []byte, present bool) bool {
+	return present && (in.Value == "" || in.Value == string(value))
+}
@@ -0,0 +1,17 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKafkaHeaderMatch_Matches(t *testing.T) {
+	presence := KafkaHeaderMatch{Key: "team"}
+	assert.True(t, presence.Matches([]byte("a"), true))
+	assert.False(t, presence.Matches(nil, false))
+	value := KafkaHeaderMatch{Key: "team", Value: "a"}
+	assert.True(t, value.Matches([]byte("a"), true))
+	assert.False(t, value.Matches([]byte("b"), true))
+	assert.False(t, value.Matches(nil, false))
+}
@@ -0,0 +1,19 @@
+package v1alpha1
+
+// KafkaOffsetOutOfRange is what a Kafka source does when a partition's committed offset is out of range, e.g. because
+// the messages after it expired before they were consumed, or the topic was re-created.
+// +kubebuilder:validation:Enum=Fail;Earliest;Latest;DeadLetterQueue
+type KafkaOffsetOutOfRange string
+
+const (
+	// KafkaOffsetOutOfRangeFail stops consuming the partition until its offset is reset, e.g. using the
+	// "dataflow.argoproj.io/reset-offset" annotation.
+	KafkaOffsetOutOfRangeFail KafkaOffsetOutOfRange = "Fail"
+	// KafkaOffsetOutOfRangeEarliest consumes the partition from its earliest offset.
+	KafkaOffsetOutOfRangeEarliest KafkaOffsetOutOfRange = "Earliest"
+	// KafkaOffsetOutOfRangeLatest consumes the partition from its latest offset.
+	KafkaOffsetOutOfRangeLatest KafkaOffsetOutOfRange = "Latest"
+	// KafkaOffsetOutOfRangeDeadLetterQueue consumes the partition from its earliest offset, and writes a note of the
+	// offsets that were out of range to the step's dead-letter queue.
+	KafkaOffsetOutOfRangeDeadLetterQueue KafkaOffsetOutOfRange = "DeadLetterQueue"
+)
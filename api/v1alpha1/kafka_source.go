@@ -1,6 +1,11 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -15,12 +20,64 @@ type KafkaSource struct {
 	FetchWaitMax *metav1.Duration `json:"fetchWaitMax,omitempty" protobuf:"bytes,4,opt,name=fetchWaitMax"`
 	// GroupID is the consumer group ID. If not specified, a unique deterministic group ID is generated.
 	GroupID string `json:"groupId,omitempty" protobuf:"bytes,5,opt,name=groupId"`
+	// StartOffsets are the offsets to start consuming each partition at, keyed by partition, when the consumer group
+	// has not committed an offset for the partition. This is typically set by restoring a snapshot.
+	StartOffsets map[string]int64 `json:"startOffsets,omitempty" protobuf:"bytes,6,rep,name=startOffsets"`
+	// Transactional commits the consumed offsets, and the messages produced to the step's sinks, atomically in Kafka
+	// transactions, so each message is processed exactly once. The source must be the step's only source, and every
+	// sink must be a synchronous Kafka sink on the same cluster.
+	Transactional bool `json:"transactional,omitempty" protobuf:"varint,7,opt,name=transactional"`
+	// Topics are more topics to consume, as well as the topic. A topic that starts with "^" is a regular expression,
+	// and every topic it matches is consumed, including topics created later.
+	Topics []string `json:"topics,omitempty" protobuf:"bytes,8,rep,name=topics"`
+	// MatchHeaders only processes messages whose headers match every rule. Other messages are skipped, without being
+	// decoded or sent to the main container, but are still committed.
+	MatchHeaders []KafkaHeaderMatch `json:"matchHeaders,omitempty" protobuf:"bytes,9,rep,name=matchHeaders"`
+	// OffsetOutOfRange, if specified, is what the source does when a partition's committed offset is out of range,
+	// and records an event on the step when it happens. If not specified, the consumer silently starts the
+	// partition at its startOffset.
+	OffsetOutOfRange KafkaOffsetOutOfRange `json:"offsetOutOfRange,omitempty" protobuf:"bytes,10,opt,name=offsetOutOfRange,casttype=KafkaOffsetOutOfRange"`
+}
+
+// GetTopics returns the topics, and regular expressions matching topics, to consume.
+func (m *KafkaSource) GetTopics() []string {
+	return append([]string{m.Topic}, m.Topics...)
+}
+
+// HasSingleTopic returns whether the source consumes only its topic.
+func (m *KafkaSource) HasSingleTopic() bool {
+	return len(m.Topics) == 0 && !IsTopicPattern(m.Topic)
+}
+
+// Consumes returns whether the source consumes the topic.
+func (m *KafkaSource) Consumes(topic string) bool {
+	for _, t := range m.GetTopics() {
+		if IsTopicPattern(t) {
+			if r, err := regexp.Compile(t); err == nil && r.MatchString(topic) {
+				return true
+			}
+		} else if t == topic {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTopicPattern returns whether the topic is a regular expression, rather than the name of a topic.
+func IsTopicPattern(topic string) bool {
+	return strings.HasPrefix(topic, "^")
 }
 
 func (m *KafkaSource) GetAutoOffsetReset() string {
 	return m.StartOffset.GetAutoOffsetReset()
 }
 
+// GetStartOffset returns the offset to start consuming the partition at, if there is one.
+func (m *KafkaSource) GetStartOffset(partition int32) (int64, bool) {
+	offset, ok := m.StartOffsets[strconv.Itoa(int(partition))]
+	return offset, ok
+}
+
 func (m *KafkaSource) GetFetchMinBytes() int {
 	return int(m.FetchMin.Value())
 }
@@ -29,6 +86,12 @@ func (m *KafkaSource) GetFetchWaitMaxMs() int {
 	return int(m.FetchWaitMax.Milliseconds())
 }
 
+// GetTransactionalID returns the transactional ID of the replica's producer. It is stable, so a restarted replica fences
+// its previous instance.
+func (m *KafkaSource) GetTransactionalID(groupID string, replica int) string {
+	return fmt.Sprintf("%s/%d", groupID, replica)
+}
+
 func (m *KafkaSource) GetGroupID(defaultGroupID string) string {
 	if m.GroupID != "" {
 		return m.GroupID
@@ -35,3 +35,27 @@ func TestKafkaSource_GetGroupID(t *testing.T) {
 		assert.Equal(t, "bar", s.GetGroupID("foo"))
 	})
 }
+
+func TestKafkaSource_GetStartOffset(t *testing.T) {
+	s := &KafkaSource{StartOffsets: map[string]int64{"1": 10}}
+	offset, ok := s.GetStartOffset(1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(10), offset)
+	_, ok = s.GetStartOffset(0)
+	assert.False(t, ok)
+}
+
+func TestKafkaSource_GetTopics(t *testing.T) {
+	s := &KafkaSource{Kafka: Kafka{Topic: "a"}}
+	assert.Equal(t, []string{"a"}, s.GetTopics())
+	assert.True(t, s.HasSingleTopic())
+	assert.True(t, s.Consumes("a"))
+	assert.False(t, s.Consumes("b"))
+	s.Topics = []string{"b", "^c-.*"}
+	assert.Equal(t, []string{"a", "b", "^c-.*"}, s.GetTopics())
+	assert.False(t, s.HasSingleTopic())
+	assert.True(t, s.Consumes("b"))
+	assert.True(t, s.Consumes("c-1"))
+	assert.False(t, s.Consumes("d-c-1"))
+	assert.False(t, (&KafkaSource{Kafka: Kafka{Topic: "^a"}}).HasSingleTopic())
+}
@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetTransactionalSource returns the step's transactional Kafka source, or nil if it does not have one. It returns an
+// error if the step cannot process the source's messages in Kafka transactions.
+func (in StepSpec) GetTransactionalSource() (*Source, error) {
+	var source *Source
+	for i, s := range in.Sources {
+		if x := s.Kafka; x != nil && x.Transactional {
+			source = &in.Sources[i]
+		}
+	}
+	if source == nil {
+		return nil, nil
+	}
+	if len(in.Sources) != 1 {
+		return source, fmt.Errorf("source %q is transactional, so it must be the only source", source.Name)
+	}
+	for _, s := range in.Sinks {
+		if s.Kafka == nil {
+			return source, fmt.Errorf("sink %q must be a Kafka sink, as source %q is transactional", s.Name, source.Name)
+		}
+		if !source.Kafka.Kafka.sameCluster(s.Kafka.Kafka) {
+			return source, fmt.Errorf("sink %q must use the same Kafka cluster as transactional source %q", s.Name, source.Name)
+		}
+		if s.Kafka.Async || s.Buffer != nil || s.Parallelism > 0 {
+			return source, fmt.Errorf("sink %q must not be async, buffered or parallel, as source %q is transactional", s.Name, source.Name)
+		}
+	}
+	return source, nil
+}
+
+func (in Kafka) sameCluster(other Kafka) bool {
+	name := func(k Kafka) string {
+		if k.Name == "" {
+			return "default"
+		}
+		return k.Name
+	}
+	strimziCluster := func(k Kafka) string {
+		if k.Strimzi == nil {
+			return ""
+		}
+		return k.Strimzi.Cluster
+	}
+	return name(in) == name(other) &&
+		strings.Join(in.Brokers, ",") == strings.Join(other.Brokers, ",") &&
+		strimziCluster(in) == strimziCluster(other)
+}
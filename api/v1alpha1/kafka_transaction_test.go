@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStepSpec_GetTransactionalSource(t *testing.T) {
+	transactional := Source{Name: "in", Kafka: &KafkaSource{Kafka: Kafka{Topic: "in"}, Transactional: true}}
+	kafkaSink := func(name string, k Kafka) Sink { return Sink{Name: name, Kafka: &KafkaSink{Kafka: k}} }
+	t.Run("None", func(t *testing.T) {
+		source, err := StepSpec{Sources: []Source{{Kafka: &KafkaSource{}}}, Sinks: []Sink{{Log: &Log{}}}}.GetTransactionalSource()
+		assert.NoError(t, err)
+		assert.Nil(t, source)
+	})
+	t.Run("Valid", func(t *testing.T) {
+		source, err := StepSpec{Sources: []Source{transactional}, Sinks: []Sink{kafkaSink("out", Kafka{Name: "default", Topic: "out"})}}.GetTransactionalSource()
+		assert.NoError(t, err)
+		assert.Equal(t, "in", source.Name)
+	})
+	t.Run("OtherSource", func(t *testing.T) {
+		_, err := StepSpec{Sources: []Source{transactional, {Name: "cron", Cron: &Cron{}}}}.GetTransactionalSource()
+		assert.EqualError(t, err, `source "in" is transactional, so it must be the only source`)
+	})
+	t.Run("OtherSink", func(t *testing.T) {
+		_, err := StepSpec{Sources: []Source{transactional}, Sinks: []Sink{{Name: "log", Log: &Log{}}}}.GetTransactionalSource()
+		assert.EqualError(t, err, `sink "log" must be a Kafka sink, as source "in" is transactional`)
+	})
+	t.Run("OtherCluster", func(t *testing.T) {
+		_, err := StepSpec{Sources: []Source{transactional}, Sinks: []Sink{kafkaSink("out", Kafka{Name: "other", Topic: "out"})}}.GetTransactionalSource()
+		assert.EqualError(t, err, `sink "out" must use the same Kafka cluster as transactional source "in"`)
+	})
+	t.Run("Async", func(t *testing.T) {
+		sink := kafkaSink("out", Kafka{Topic: "out"})
+		sink.Kafka.Async = true
+		_, err := StepSpec{Sources: []Source{transactional}, Sinks: []Sink{sink}}.GetTransactionalSource()
+		assert.EqualError(t, err, `sink "out" must not be async, buffered or parallel, as source "in" is transactional`)
+	})
+}
+
+func TestKafkaSource_GetTransactionalID(t *testing.T) {
+	assert.Equal(t, "my-group/1", (&KafkaSource{}).GetTransactionalID("my-group", 1))
+}
@@ -0,0 +1,71 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KinesisSource reads records from an Amazon Kinesis data stream. Each of the stream's shards is read by one replica,
+// and the sequence number of the last record processed from each shard is committed to the step's status, so a
+// replica that restarts, or takes over the shard, resumes from there.
+type KinesisSource struct {
+	// Name of the "dataflow-kinesis-{name}" secret, whose "region", credentials, and "endpoint.url" are used if not
+	// specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// Stream is the stream's name.
+	Stream string `json:"stream" protobuf:"bytes,2,opt,name=stream"`
+	// Region is the stream's region, e.g. "us-west-2".
+	Region string `json:"region,omitempty" protobuf:"bytes,3,opt,name=region"`
+	// Credentials, if not specified, are those of the web identity injected by IAM Roles for Service Accounts (IRSA).
+	Credentials *AWSCredentials `json:"credentials,omitempty" protobuf:"bytes,4,opt,name=credentials"`
+	// Endpoint, if specified, is where requests are sent instead of the region's endpoint, e.g. for LocalStack.
+	Endpoint *AWSEndpoint `json:"endpoint,omitempty" protobuf:"bytes,5,opt,name=endpoint"`
+	// StartPosition is where shards without a committed sequence number are read from: "Latest", only records added
+	// after the source starts, or "TrimHorizon", the oldest record in the shard.
+	// +kubebuilder:default=Latest
+	StartPosition KinesisStartPosition `json:"startPosition,omitempty" protobuf:"bytes,6,opt,name=startPosition,casttype=KinesisStartPosition"`
+	// PollPeriod is how long each shard waits before getting more records, once it has read all of them. Kinesis allows
+	// five reads per second per shard, shared by every consumer.
+	// +kubebuilder:default="1s"
+	PollPeriod *metav1.Duration `json:"pollPeriod,omitempty" protobuf:"bytes,7,opt,name=pollPeriod"`
+	// Limit is the most records each read returns, at most 10000.
+	// +kubebuilder:default=1000
+	Limit uint32 `json:"limit,omitempty" protobuf:"varint,8,opt,name=limit"`
+}
+
+// +kubebuilder:validation:Enum=Latest;TrimHorizon
+type KinesisStartPosition string
+
+const (
+	KinesisStartPositionLatest      KinesisStartPosition = "Latest"
+	KinesisStartPositionTrimHorizon KinesisStartPosition = "TrimHorizon"
+)
+
+// GetShardIteratorType returns the Kinesis shard iterator type of the start position.
+func (in KinesisStartPosition) GetShardIteratorType() string {
+	if in == KinesisStartPositionTrimHorizon {
+		return "TRIM_HORIZON"
+	}
+	return "LATEST"
+}
+
+func (in KinesisSource) GetPollPeriod() time.Duration {
+	if in.PollPeriod != nil {
+		return in.PollPeriod.Duration
+	}
+	return time.Second
+}
+
+func (in KinesisSource) GetLimit() uint32 {
+	if in.Limit > 0 {
+		return in.Limit
+	}
+	return 1000
+}
+
+func (in KinesisSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:kinesis:%s:%s", in.Region, in.Stream)
+}
@@ -0,0 +1,17 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKinesisSource(t *testing.T) {
+	x := KinesisSource{Region: "us-west-2", Stream: "my-stream"}
+	assert.Equal(t, "urn:dataflow:kinesis:us-west-2:my-stream", x.GenURN(cluster, namespace))
+	assert.Equal(t, time.Second, x.GetPollPeriod())
+	assert.Equal(t, uint32(1000), x.GetLimit())
+	assert.Equal(t, "LATEST", x.StartPosition.GetShardIteratorType())
+	assert.Equal(t, "TRIM_HORIZON", KinesisStartPositionTrimHorizon.GetShardIteratorType())
+}
@@ -0,0 +1,51 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Lateness is how a windowed step handles late messages, i.e. messages whose event time is behind their source's
+// watermark by more than is allowed. Sources must have an eventTime for their messages to be late.
+type Lateness struct {
+	// Allowed is how far behind the watermark a message's event time may be before it is late.
+	// +kubebuilder:default="0s"
+	Allowed *metav1.Duration `json:"allowed,omitempty" protobuf:"bytes,1,opt,name=allowed"`
+	// Policy is what happens to late messages.
+	// +kubebuilder:default=Drop
+	Policy LatePolicy `json:"policy,omitempty" protobuf:"bytes,2,opt,name=policy,casttype=LatePolicy"`
+	// Sink is the name of the sink late messages are sent to, for the Sink policy. The sink only receives late messages.
+	Sink string `json:"sink,omitempty" protobuf:"bytes,3,opt,name=sink"`
+}
+
+// +kubebuilder:validation:Enum=Drop;Emit;Sink
+type LatePolicy string
+
+const (
+	// LateDrop drops late messages, and counts them in the sources_late_dropped metric.
+	LateDrop LatePolicy = "Drop"
+	// LateEmit processes late messages like any other, e.g. a join emits an updated result if it has a match.
+	LateEmit LatePolicy = "Emit"
+	// LateSink sends late messages, as they are, to the lateness sink, rather than processing them.
+	LateSink LatePolicy = "Sink"
+)
+
+func (in *Lateness) GetAllowed() time.Duration {
+	if in != nil && in.Allowed != nil {
+		return in.Allowed.Duration
+	}
+	return 0
+}
+
+func (in *Lateness) GetPolicy() LatePolicy {
+	if in == nil || in.Policy == "" {
+		return LateDrop
+	}
+	return in.Policy
+}
+
+// IsLate returns true if a message with the event time is late, given its source's watermark.
+func (in *Lateness) IsLate(eventTime, watermark time.Time) bool {
+	return in != nil && eventTime.Before(watermark.Add(-in.GetAllowed()))
+}
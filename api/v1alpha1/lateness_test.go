@@ -0,0 +1,24 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestLateness_GetPolicy(t *testing.T) {
+	assert.Equal(t, LateDrop, (*Lateness)(nil).GetPolicy())
+	assert.Equal(t, LateSink, (&Lateness{Policy: LateSink}).GetPolicy())
+}
+
+func TestLateness_IsLate(t *testing.T) {
+	watermark := time.Now()
+	assert.False(t, (*Lateness)(nil).IsLate(watermark.Add(-time.Hour), watermark))
+	x := &Lateness{Allowed: &metav1.Duration{Duration: time.Minute}}
+	assert.False(t, x.IsLate(watermark.Add(time.Second), watermark))
+	assert.False(t, x.IsLate(watermark.Add(-time.Minute), watermark))
+	assert.True(t, x.IsLate(watermark.Add(-time.Minute-time.Second), watermark))
+	assert.True(t, (&Lateness{}).IsLate(watermark.Add(-time.Second), watermark))
+}
@@ -0,0 +1,45 @@
+package v1alpha1
+
+import corev1 "k8s.io/api/core/v1"
+
+// Lifecycle are commands run in the main container, as it starts and before it stops, coordinated with the sidecar.
+type Lifecycle struct {
+	// PostStart is run in the main container when it starts, e.g. to warm up a cache. The sidecar does not send the main
+	// container messages until it has succeeded. If it fails, the main container is restarted.
+	PostStart []string `json:"postStart,omitempty" protobuf:"bytes,1,rep,name=postStart"`
+	// PreStop is run in the main container before it stops, once the sidecar has closed its sources, so no more
+	// messages are sent to it, e.g. to flush buffered messages. The sidecar keeps running until it has completed, so
+	// messages it returns or writes are sent to the sinks. It must complete within the pod's termination grace period.
+	PreStop []string `json:"preStop,omitempty" protobuf:"bytes,2,rep,name=preStop"`
+}
+
+func (in *Lifecycle) GetPostStart() []string {
+	if in == nil {
+		return nil
+	}
+	return in.PostStart
+}
+
+func (in *Lifecycle) GetPreStop() []string {
+	if in == nil {
+		return nil
+	}
+	return in.PreStop
+}
+
+// getMainLifecycle returns the main container's lifecycle handlers. They run the step's commands using binaries that
+// coordinate with the sidecar: the pre-stop handler always asks the sidecar to close its sources first, and then runs
+// the step's pre-stop command, if any.
+func (in StepSpec) getMainLifecycle() *corev1.Lifecycle {
+	x := &corev1.Lifecycle{
+		PreStop: &corev1.Handler{
+			Exec: &corev1.ExecAction{Command: append([]string{PathPreStop}, in.Lifecycle.GetPreStop()...)},
+		},
+	}
+	if postStart := in.Lifecycle.GetPostStart(); len(postStart) > 0 {
+		x.PostStart = &corev1.Handler{
+			Exec: &corev1.ExecAction{Command: append([]string{PathPostStart}, postStart...)},
+		}
+	}
+	return x
+}
@@ -0,0 +1,21 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestStepSpec_getMainLifecycle(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		x := StepSpec{}.getMainLifecycle()
+		assert.Nil(t, x.PostStart)
+		assert.Equal(t, &corev1.ExecAction{Command: []string{PathPreStop}}, x.PreStop.Exec)
+	})
+	t.Run("Lifecycle", func(t *testing.T) {
+		x := StepSpec{Lifecycle: &Lifecycle{PostStart: []string{"warm-up", "--cache"}, PreStop: []string{"flush"}}}.getMainLifecycle()
+		assert.Equal(t, &corev1.ExecAction{Command: []string{PathPostStart, "warm-up", "--cache"}}, x.PostStart.Exec)
+		assert.Equal(t, &corev1.ExecAction{Command: []string{PathPreStop, "flush"}}, x.PreStop.Exec)
+	})
+}
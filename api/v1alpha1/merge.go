@@ -0,0 +1,38 @@
+package v1alpha1
+
+// Merge governs how messages from a step's sources are interleaved into the main container. Messages are sent to the
+// main container through a fixed number of slots. When a slot is free and more than one source has messages waiting
+// for one, the policy picks the source that gets it.
+type Merge struct {
+	// Policy is "RoundRobin", which takes a message from each waiting source in turn, "Weighted", which takes messages
+	// from waiting sources in proportion to their weights, or "Priority", which always takes a message from the waiting
+	// source with the highest weight, so lower weight sources only get slots that it does not need.
+	// +kubebuilder:default=RoundRobin
+	Policy MergePolicy `json:"policy,omitempty" protobuf:"bytes,1,opt,name=policy,casttype=MergePolicy"`
+	// Concurrency is the number of messages sent to the main container at once.
+	// +kubebuilder:default=1
+	Concurrency uint32 `json:"concurrency,omitempty" protobuf:"varint,2,opt,name=concurrency"`
+}
+
+// +kubebuilder:validation:Enum=RoundRobin;Weighted;Priority
+type MergePolicy string
+
+const (
+	MergeRoundRobin MergePolicy = "RoundRobin"
+	MergeWeighted   MergePolicy = "Weighted"
+	MergePriority   MergePolicy = "Priority"
+)
+
+func (in Merge) GetPolicy() MergePolicy {
+	if in.Policy == "" {
+		return MergeRoundRobin
+	}
+	return in.Policy
+}
+
+func (in Merge) GetConcurrency() int {
+	if in.Concurrency > 0 {
+		return int(in.Concurrency)
+	}
+	return 1
+}
@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"net/url"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// MQTTSource subscribes to an MQTT topic filter, e.g. to ingest IoT telemetry, using MQTT 3.1.1 or MQTT 5.
+// https://mqtt.org/
+type MQTTSource struct {
+	// Name of the "dataflow-mqtt-{name}" secret, whose "url", "username" and "password" are used if not specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// URL of the broker, e.g. "tcp://mosquitto:1883", or "ssl://mosquitto:8883" for TLS.
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// Topic is the topic filter to subscribe to, e.g. "sensors/+/temperature". Every replica receives every message,
+	// unless it is a shared subscription, e.g. "$share/my-group/sensors/#", and the broker supports them.
+	Topic string `json:"topic" protobuf:"bytes,3,opt,name=topic"`
+	// QoS is the subscription's quality of service: 0 (at most once), 1 (at least once), or 2 (exactly once).
+	// +kubebuilder:default=1
+	QoS uint32 `json:"qos" protobuf:"varint,4,opt,name=qos"`
+	// Username to authenticate with.
+	Username string `json:"username,omitempty" protobuf:"bytes,5,opt,name=username"`
+	// PasswordSecret is the secret selector to the user's password.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,6,opt,name=passwordSecret"`
+	// TLS configures the CA certificate to verify the broker with, and the client certificate and key to authenticate
+	// with, from secrets.
+	TLS *TLS `json:"tls,omitempty" protobuf:"bytes,7,opt,name=tls"`
+	// ProtocolVersion is the version of MQTT to use: 4 (MQTT 3.1.1), or 5 (MQTT 5).
+	// +kubebuilder:default=4
+	ProtocolVersion uint32 `json:"protocolVersion,omitempty" protobuf:"varint,8,opt,name=protocolVersion"`
+}
+
+// getDefaultPort returns the port of URLs that do not specify one.
+func (in MQTTSource) getDefaultPort() int32 {
+	u, err := url.Parse(in.URL)
+	if err != nil {
+		return 1883
+	}
+	switch u.Scheme {
+	case "ssl", "tls", "mqtts", "mqtt+ssl", "tcps":
+		return 8883
+	case "ws":
+		return 80
+	case "wss":
+		return 443
+	}
+	return 1883
+}
+
+func (in MQTTSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:mqtt:%s:%s", in.URL, in.Topic)
+}
@@ -0,0 +1,18 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMQTTSource_getDefaultPort(t *testing.T) {
+	assert.Equal(t, int32(1883), MQTTSource{URL: "tcp://mosquitto"}.getDefaultPort())
+	assert.Equal(t, int32(8883), MQTTSource{URL: "ssl://mosquitto"}.getDefaultPort())
+	assert.Equal(t, int32(443), MQTTSource{URL: "wss://mosquitto"}.getDefaultPort())
+}
+
+func TestMQTTSource_GenURN(t *testing.T) {
+	x := MQTTSource{URL: "tcp://mosquitto:1883", Topic: "sensors/#"}
+	assert.Equal(t, "urn:dataflow:mqtt:tcp://mosquitto:1883:sensors/#", x.GenURN(cluster, namespace))
+}
@@ -0,0 +1,222 @@
+package v1alpha1
+
+import (
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// GetNetworkPolicyObj returns a network policy that only allows the step's pods to:
+//
+// * receive traffic from pods in the same pipeline, and on the sidecar's port (e.g. metrics, HTTP sources),
+// * send traffic to pods in the same pipeline, DNS, the Kubernetes API, and the ports of their sources and sinks.
+//
+// Broker addresses are often only known at runtime (e.g. from a secret), so egress is restricted by port, not host.
+// Egress is not restricted for steps that use Dapr. If the controller is configured to read secrets from Vault, egress to
+// its address is also allowed.
+func (in Step) GetNetworkPolicyObj(pipelineName, vaultAddr string) *networkingv1.NetworkPolicy {
+	tcp, udp := corev1.ProtocolTCP, corev1.ProtocolUDP
+	port := func(protocol corev1.Protocol, port int) networkingv1.NetworkPolicyPort {
+		p := intstr.FromInt(port)
+		return networkingv1.NetworkPolicyPort{Protocol: &protocol, Port: &p}
+	}
+	samePipeline := []networkingv1.NetworkPolicyPeer{{
+		PodSelector: &metav1.LabelSelector{MatchLabels: map[string]string{KeyPipelineName: pipelineName}},
+	}}
+	egressPorts := []networkingv1.NetworkPolicyPort{
+		port(udp, 53), port(tcp, 53), // DNS
+		port(tcp, 443), port(tcp, 6443), // Kubernetes API
+	}
+	for _, p := range in.Spec.getEgressPorts() {
+		egressPorts = append(egressPorts, port(tcp, int(p)))
+	}
+	if vaultAddr != "" {
+		egressPorts = append(egressPorts, port(tcp, int(portOf(vaultAddr, 8200))))
+	}
+	egress := []networkingv1.NetworkPolicyEgressRule{
+		{To: samePipeline},
+		{Ports: egressPorts},
+	}
+	if in.Spec.usesDapr() {
+		// Dapr's sidecar connects to its control plane and components, whose ports we cannot know
+		egress = []networkingv1.NetworkPolicyEgressRule{{}}
+	}
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       in.Namespace,
+			Name:            in.Name,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(in.GetObjectMeta(), StepGroupVersionKind)},
+			Labels:          in.GetObjectLabels(pipelineName),
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					KeyPipelineName: pipelineName,
+					KeyStepName:     in.Spec.Name,
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{From: samePipeline},
+				{Ports: []networkingv1.NetworkPolicyPort{port(tcp, 3570)}},
+			},
+			Egress: egress,
+		},
+	}
+}
+
+func (in StepSpec) getEgressPorts() []int32 {
+	ports := map[int32]bool{}
+	add := func(address string, defaultPort int32) {
+		ports[portOf(address, defaultPort)] = true
+	}
+	addKafka := func(x Kafka) {
+		for _, b := range x.Brokers {
+			add(b, 9092)
+		}
+		if len(x.Brokers) == 0 {
+			if s := x.Strimzi; s != nil {
+				add(s.GetBootstrapServer(), 9093)
+			} else if e := x.EventHubs; e != nil {
+				add(e.GetBootstrapServer(), 9093)
+			} else {
+				ports[9092] = true
+			}
+		}
+	}
+	addSTAN := func(x STAN) {
+		add(x.NATSURL, 4222)
+		add(x.NATSMonitoringURL, 8222)
+	}
+	addS3 := func(x S3) {
+		if e := x.Endpoint; e != nil {
+			add(e.URL, 443)
+		} else {
+			ports[443] = true
+		}
+	}
+	for _, s := range in.Sources {
+		if x := s.Kafka; x != nil {
+			addKafka(x.Kafka)
+		} else if x := s.STAN; x != nil {
+			addSTAN(*x)
+		} else if x := s.JetStream; x != nil {
+			add(x.NATSURL, 4222)
+		} else if x := s.ArgoEvents; x != nil {
+			add(x.GetNATSURL(), 4222)
+		} else if x := s.S3; x != nil {
+			addS3(x.S3)
+		} else if x := s.DB; x != nil {
+			ports[x.getDefaultPort()] = true
+		} else if x := s.Redis; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.RedisStream; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.Elasticsearch; x != nil {
+			add(x.URL, 9200)
+		} else if x := s.SQS; x != nil {
+			if e := x.Endpoint; e != nil {
+				add(e.URL, 443)
+			} else if x.QueueURL != "" {
+				add(x.QueueURL, 443)
+			} else {
+				ports[443] = true
+			}
+		} else if x := s.Kinesis; x != nil {
+			if e := x.Endpoint; e != nil {
+				add(e.URL, 443)
+			} else {
+				ports[443] = true
+			}
+		} else if x := s.AMQP; x != nil {
+			add(x.URL, x.getDefaultPort())
+		} else if x := s.MQTT; x != nil {
+			add(x.URL, x.getDefaultPort())
+		}
+	}
+	for _, s := range in.Sinks {
+		if x := s.Kafka; x != nil {
+			addKafka(x.Kafka)
+		} else if x := s.STAN; x != nil {
+			addSTAN(*x)
+		} else if x := s.JetStream; x != nil {
+			add(x.NATSURL, 4222)
+		} else if x := s.S3; x != nil {
+			addS3(x.S3)
+		} else if x := s.DB; x != nil {
+			ports[x.getDefaultPort()] = true
+		} else if x := s.HTTP; x != nil {
+			add(x.URL, 80)
+		} else if x := s.CloudEvents; x != nil {
+			add(x.GetURL(""), 80) // the namespace does not change the port
+		} else if x := s.Redis; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.RedisStream; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.Snowflake; x != nil {
+			ports[443] = true
+		} else if x := s.File; x != nil && x.OnRotate != nil {
+			add(x.OnRotate.URL, 80)
+		} else if x := s.AMQP; x != nil {
+			add(x.URL, x.getDefaultPort())
+		}
+	}
+	delete(ports, 0)
+	var result []int32
+	for p := range ports {
+		result = append(result, p)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+// portOf returns the port of a "host:port" address or a URL, using the scheme's port if there is one.
+func portOf(address string, defaultPort int32) int32 {
+	if address == "" {
+		return defaultPort
+	}
+	if strings.Contains(address, "://") {
+		u, err := url.Parse(address)
+		if err != nil {
+			return defaultPort
+		}
+		if p := u.Port(); p != "" {
+			return parsePort(p, defaultPort)
+		}
+		switch u.Scheme {
+		case "http":
+			return 80
+		case "https":
+			return 443
+		}
+		return defaultPort
+	}
+	if _, p, err := net.SplitHostPort(address); err == nil {
+		return parsePort(p, defaultPort)
+	}
+	return defaultPort
+}
+
+func parsePort(s string, defaultPort int32) int32 {
+	if p, err := strconv.ParseInt(s, 10, 32); err == nil {
+		return int32(p)
+	}
+	return defaultPort
+}
+
+func (in Database) getDefaultPort() int32 {
+	switch in.Driver {
+	case "mysql":
+		return 3306
+	case "postgres", "pgx":
+		return 5432
+	}
+	return 0
+}
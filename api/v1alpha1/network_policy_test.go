@@ -0,0 +1,54 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+func TestStep_GetNetworkPolicyObj(t *testing.T) {
+	step := Step{
+		Spec: StepSpec{
+			Name: "main",
+			Sources: []Source{
+				{Kafka: &KafkaSource{Kafka: Kafka{KafkaConfig: KafkaConfig{Brokers: []string{"kafka-broker:9093"}}}}},
+				{STAN: &STAN{NATSURL: "nats://nats:4222"}},
+				{Elasticsearch: &ElasticsearchSource{}},
+			},
+			Sinks: []Sink{
+				{HTTP: &HTTPSink{URL: "https://my-svc/foo"}},
+				{DB: &DBSink{Database: Database{Driver: "mysql"}}},
+				{Redis: &RedisSink{}},
+				{File: &FileSink{OnRotate: &HTTPSink{URL: "http://my-svc:8080/rotated"}}},
+			},
+		},
+	}
+	obj := step.GetNetworkPolicyObj("my-pl", "")
+	assert.Equal(t, "my-pl", obj.Spec.PodSelector.MatchLabels[KeyPipelineName])
+	assert.Equal(t, "main", obj.Spec.PodSelector.MatchLabels[KeyStepName])
+	assert.Equal(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, obj.Spec.PolicyTypes)
+	assert.Len(t, obj.Spec.Ingress, 2)
+	if assert.Len(t, obj.Spec.Egress, 2) {
+		var ports []int
+		for _, p := range obj.Spec.Egress[1].Ports {
+			ports = append(ports, p.Port.IntValue())
+		}
+		assert.Equal(t, []int{53, 53, 443, 6443, 443, 3306, 4222, 6379, 8080, 8222, 9093, 9200}, ports)
+	}
+	t.Run("Vault", func(t *testing.T) {
+		obj := Step{}.GetNetworkPolicyObj("my-pl", "https://vault:8200")
+		ports := obj.Spec.Egress[1].Ports
+		assert.Equal(t, 8200, ports[len(ports)-1].Port.IntValue())
+	})
+}
+
+func Test_portOf(t *testing.T) {
+	assert.Equal(t, int32(9092), portOf("", 9092))
+	assert.Equal(t, int32(9093), portOf("kafka:9093", 9092))
+	assert.Equal(t, int32(9092), portOf("kafka", 9092))
+	assert.Equal(t, int32(4223), portOf("nats://nats:4223", 4222))
+	assert.Equal(t, int32(4222), portOf("nats://nats", 4222))
+	assert.Equal(t, int32(443), portOf("https://my-svc/foo", 80))
+	assert.Equal(t, int32(80), portOf("http://my-svc/foo", 443))
+}
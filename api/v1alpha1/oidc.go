@@ -0,0 +1,9 @@
+package v1alpha1
+
+type OIDC struct {
+	// Issuer is the URL of the OpenID Connect issuer, e.g. "https://accounts.google.com". The issuer's signing keys
+	// are discovered from "${issuer}/.well-known/openid-configuration".
+	Issuer string `json:"issuer" protobuf:"bytes,1,opt,name=issuer"`
+	// Audience that the token must be issued for, i.e. it must be in the "aud" claim.
+	Audience string `json:"audience" protobuf:"bytes,2,opt,name=audience"`
+}
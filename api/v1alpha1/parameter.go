@@ -0,0 +1,40 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+// Parameter is a value substituted for `{{name}}` in the pipeline's steps and defaults.
+type Parameter struct {
+	Name  string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+}
+
+var (
+	ParameterNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_.-]*$`)
+	parameterRefRegexp  = regexp.MustCompile(`{{\s*([a-zA-Z_][a-zA-Z0-9_.-]*)\s*}}`)
+)
+
+// substituteParameters replaces references to the parameters in the JSON representation of in, and unmarshals the
+// result into out. References to anything else (e.g. a template in a step's code) are left as they are.
+func substituteParameters(parameters []Parameter, in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	values := make(map[string]string, len(parameters))
+	for _, p := range parameters {
+		// values are substituted into JSON strings, so must be escaped
+		v, _ := json.Marshal(p.Value)
+		values[p.Name] = strings.TrimSuffix(strings.TrimPrefix(string(v), `"`), `"`)
+	}
+	data = parameterRefRegexp.ReplaceAllFunc(data, func(ref []byte) []byte {
+		if v, ok := values[string(parameterRefRegexp.FindSubmatch(ref)[1])]; ok {
+			return []byte(v)
+		}
+		return ref
+	})
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,5 @@
+package v1alpha1
+
+// Passthrough routes messages from the sources directly to the sinks inside the sidecar. No main container is created,
+// so there is no HTTP hop. This is useful for bridge steps, e.g. replicating Kafka to STAN.
+type Passthrough struct{}
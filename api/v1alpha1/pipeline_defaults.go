@@ -0,0 +1,150 @@
+package v1alpha1
+
+import (
+	"reflect"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PipelineDefaults are inherited by all of the pipeline's steps, unless a step specifies its own value.
+type PipelineDefaults struct {
+	// Kafka is used by Kafka sources and sinks that do not specify brokers, net or maxMessageBytes.
+	Kafka *KafkaConfig `json:"kafka,omitempty" protobuf:"bytes,1,opt,name=kafka"`
+	// STAN is used by STAN sources and sinks that do not specify a connection.
+	STAN *STANDefaults `json:"stan,omitempty" protobuf:"bytes,2,opt,name=stan"`
+	// Retry is used by sources that have the default retry.
+	Retry *Backoff `json:"retry,omitempty" protobuf:"bytes,3,opt,name=retry"`
+	// Resources are used by main containers that have the default resources, or, for `container` steps, no resources.
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty" protobuf:"bytes,4,opt,name=resources"`
+}
+
+type STANDefaults struct {
+	NATSURL           string    `json:"natsUrl,omitempty" protobuf:"bytes,1,opt,name=natsUrl"`
+	NATSMonitoringURL string    `json:"natsMonitoringUrl,omitempty" protobuf:"bytes,2,opt,name=natsMonitoringUrl"`
+	ClusterID         string    `json:"clusterId,omitempty" protobuf:"bytes,3,opt,name=clusterId"`
+	Auth              *NATSAuth `json:"auth,omitempty" protobuf:"bytes,4,opt,name=auth"`
+}
+
+// the same as the kubebuilder defaults of sources' retry, and steps' resources
+var (
+	defaultRetry = Backoff{
+		Duration:         &metav1.Duration{Duration: 100 * time.Millisecond},
+		FactorPercentage: 200,
+		Steps:            20,
+		Cap:              &metav1.Duration{},
+		JitterPercentage: 10,
+	}
+	defaultStepResources = corev1.ResourceRequirements{
+		Limits: corev1.ResourceList{
+			"cpu":    resource.MustParse("500m"),
+			"memory": resource.MustParse("256Mi"),
+		},
+		Requests: corev1.ResourceList{
+			"cpu":    resource.MustParse("100m"),
+			"memory": resource.MustParse("64Mi"),
+		},
+	}
+)
+
+// ApplyTo returns a copy of the step, with the defaults used for any value the step does not specify.
+func (in PipelineDefaults) ApplyTo(step StepSpec) StepSpec {
+	x := *step.DeepCopy()
+	for i := range x.Sources {
+		s := &x.Sources[i]
+		if y := s.Kafka; y != nil {
+			in.applyKafka(&y.KafkaConfig)
+		}
+		if y := s.STAN; y != nil {
+			in.applySTAN(y)
+		}
+		if r := in.Retry; r != nil && (isZeroRetry(s.Retry) || equalRetry(s.Retry, defaultRetry)) {
+			s.Retry = *r.DeepCopy()
+		}
+	}
+	for i := range x.Sinks {
+		s := &x.Sinks[i]
+		if y := s.Kafka; y != nil {
+			in.applyKafka(&y.KafkaConfig)
+		}
+		if y := s.STAN; y != nil {
+			in.applySTAN(y)
+		}
+	}
+	if r := in.Resources; r != nil {
+		if y := x.Container; y != nil && isZeroResources(y.Resources) {
+			y.Resources = *r.DeepCopy()
+		} else if y := x.getAbstractStep(); y != nil && (isZeroResources(y.Resources) || equalResources(y.Resources, defaultStepResources)) {
+			y.Resources = *r.DeepCopy()
+		}
+	}
+	return x
+}
+
+func (in PipelineDefaults) applyKafka(x *KafkaConfig) {
+	d := in.Kafka
+	if d == nil {
+		return
+	}
+	x.Brokers = StringsOr(x.Brokers, d.Brokers)
+	if x.NET == nil {
+		x.NET = d.NET.DeepCopy()
+	}
+	if x.MaxMessageBytes == 0 {
+		x.MaxMessageBytes = d.MaxMessageBytes
+	}
+}
+
+func (in PipelineDefaults) applySTAN(x *STAN) {
+	d := in.STAN
+	if d == nil {
+		return
+	}
+	x.NATSURL = StringOr(x.NATSURL, d.NATSURL)
+	x.NATSMonitoringURL = StringOr(x.NATSMonitoringURL, d.NATSMonitoringURL)
+	x.ClusterID = StringOr(x.ClusterID, d.ClusterID)
+	if x.Auth == nil {
+		x.Auth = d.Auth.DeepCopy()
+	}
+}
+
+func isZeroRetry(x Backoff) bool {
+	return reflect.DeepEqual(x, Backoff{})
+}
+
+// equalRetry compares durations by value, as a nil duration is not the same as a zero one.
+func equalRetry(a, b Backoff) bool {
+	duration := func(d *metav1.Duration) time.Duration {
+		if d == nil {
+			return 0
+		}
+		return d.Duration
+	}
+	return duration(a.Duration) == duration(b.Duration) &&
+		a.FactorPercentage == b.FactorPercentage &&
+		a.Steps == b.Steps &&
+		duration(a.Cap) == duration(b.Cap) &&
+		a.JitterPercentage == b.JitterPercentage
+}
+
+func isZeroResources(x corev1.ResourceRequirements) bool {
+	return len(x.Limits) == 0 && len(x.Requests) == 0
+}
+
+// equalResources compares quantities by value, as "0.5" is the same as "500m".
+func equalResources(a, b corev1.ResourceRequirements) bool {
+	equal := func(a, b corev1.ResourceList) bool {
+		if len(a) != len(b) {
+			return false
+		}
+		for k, v := range a {
+			if w, ok := b[k]; !ok || v.Cmp(w) != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return equal(a.Limits, b.Limits) && equal(a.Requests, b.Requests)
+}
@@ -0,0 +1,59 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPipelineDefaults_ApplyTo(t *testing.T) {
+	retry := Backoff{Steps: 3, Duration: &metav1.Duration{Duration: time.Second}, Cap: &metav1.Duration{}}
+	resources := corev1.ResourceRequirements{Requests: corev1.ResourceList{"cpu": resource.MustParse("1")}}
+	defaults := PipelineDefaults{
+		Kafka:     &KafkaConfig{Brokers: []string{"kafka-0"}, NET: &KafkaNET{TLS: &TLS{}}, MaxMessageBytes: 1},
+		STAN:      &STANDefaults{NATSURL: "nats", ClusterID: "stan"},
+		Retry:     &retry,
+		Resources: &resources,
+	}
+	t.Run("Inherited", func(t *testing.T) {
+		step := StepSpec{
+			Cat: &Cat{AbstractStep{Resources: *defaultStepResources.DeepCopy()}},
+			Sources: []Source{
+				{Kafka: &KafkaSource{Kafka: Kafka{Topic: "in"}}, Retry: *defaultRetry.DeepCopy()},
+				{STAN: &STAN{Subject: "in"}},
+			},
+			Sinks: []Sink{{Kafka: &KafkaSink{Kafka: Kafka{Topic: "out"}}}, {STAN: &STAN{Subject: "out"}}},
+		}
+		x := defaults.ApplyTo(step)
+		assert.Equal(t, *defaults.Kafka, x.Sources[0].Kafka.KafkaConfig)
+		assert.Equal(t, retry, x.Sources[0].Retry)
+		assert.Equal(t, "nats", x.Sources[1].STAN.NATSURL)
+		assert.Equal(t, "stan", x.Sources[1].STAN.ClusterID)
+		assert.Equal(t, retry, x.Sources[1].Retry)
+		assert.Equal(t, *defaults.Kafka, x.Sinks[0].Kafka.KafkaConfig)
+		assert.Equal(t, "nats", x.Sinks[1].STAN.NATSURL)
+		assert.Equal(t, resources, x.Cat.Resources)
+		assert.Empty(t, step.Sources[0].Kafka.Brokers, "step is not modified")
+	})
+	t.Run("Overridden", func(t *testing.T) {
+		stepRetry := Backoff{Steps: 1}
+		stepResources := corev1.ResourceRequirements{Limits: corev1.ResourceList{"memory": resource.MustParse("1Gi")}}
+		step := StepSpec{
+			Container: &Container{Resources: stepResources},
+			Sources: []Source{
+				{Kafka: &KafkaSource{Kafka: Kafka{KafkaConfig: KafkaConfig{Brokers: []string{"my-kafka"}}}}, Retry: stepRetry},
+				{STAN: &STAN{NATSURL: "my-nats"}},
+			},
+		}
+		x := defaults.ApplyTo(step)
+		assert.Equal(t, []string{"my-kafka"}, x.Sources[0].Kafka.Brokers)
+		assert.Equal(t, stepRetry, x.Sources[0].Retry)
+		assert.Equal(t, "my-nats", x.Sources[1].STAN.NATSURL)
+		assert.Equal(t, "stan", x.Sources[1].STAN.ClusterID)
+		assert.Equal(t, stepResources, x.Container.Resources)
+	})
+}
@@ -0,0 +1,46 @@
+package v1alpha1
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DefaultBackoffLimit is the number of times a job's failed pods are re-created by default, the same as a Kubernetes Job.
+const DefaultBackoffLimit = 6
+
+// PipelineJob makes a pipeline run like a Kubernetes Job: its steps' pods are not restarted by the kubelet, failed pods
+// are re-created up to a limit, and once the pipeline has Succeeded or Failed, it stays that way.
+type PipelineJob struct {
+	// BackoffLimit is the number of times each step's failed pods are re-created before the step fails. Defaults to 6.
+	BackoffLimit *int32 `json:"backoffLimit,omitempty" protobuf:"varint,1,opt,name=backoffLimit"`
+	// ActiveDeadlineSeconds is how long the pipeline may run for, after which its steps are terminated, and it fails.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" protobuf:"varint,2,opt,name=activeDeadlineSeconds"`
+}
+
+func (in *PipelineJob) GetBackoffLimit() int32 {
+	if in == nil || in.BackoffLimit == nil {
+		return DefaultBackoffLimit
+	}
+	return *in.BackoffLimit
+}
+
+// GetActiveDeadline returns the active deadline, or zero if there is none.
+func (in *PipelineJob) GetActiveDeadline() time.Duration {
+	if in == nil || in.ActiveDeadlineSeconds == nil {
+		return 0
+	}
+	return time.Duration(*in.ActiveDeadlineSeconds) * time.Second
+}
+
+// ApplyTo returns the step as it is run by the job: it is never restarted by the kubelet, and inherits the backoff
+// limit, unless it specifies its own.
+func (in PipelineJob) ApplyTo(step StepSpec) StepSpec {
+	step = *step.DeepCopy()
+	step.RestartPolicy = corev1.RestartPolicyNever
+	if step.BackoffLimit == nil {
+		backoffLimit := in.GetBackoffLimit()
+		step.BackoffLimit = &backoffLimit
+	}
+	return step
+}
@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPipelineJob_GetBackoffLimit(t *testing.T) {
+	var x *PipelineJob
+	assert.Equal(t, int32(DefaultBackoffLimit), x.GetBackoffLimit())
+	backoffLimit := int32(2)
+	assert.Equal(t, int32(2), (&PipelineJob{BackoffLimit: &backoffLimit}).GetBackoffLimit())
+}
+
+func TestPipelineJob_GetActiveDeadline(t *testing.T) {
+	var x *PipelineJob
+	assert.Zero(t, x.GetActiveDeadline())
+	seconds := int64(60)
+	assert.Equal(t, time.Minute, (&PipelineJob{ActiveDeadlineSeconds: &seconds}).GetActiveDeadline())
+}
+
+func TestPipelineJob_ApplyTo(t *testing.T) {
+	t.Run("Inherited", func(t *testing.T) {
+		step := StepSpec{Name: "main", RestartPolicy: corev1.RestartPolicyOnFailure}
+		x := PipelineJob{}.ApplyTo(step)
+		assert.Equal(t, corev1.RestartPolicyNever, x.RestartPolicy)
+		assert.Equal(t, int32(DefaultBackoffLimit), x.GetBackoffLimit())
+		assert.Equal(t, corev1.RestartPolicyOnFailure, step.RestartPolicy, "does not modify the step")
+	})
+	t.Run("Specified", func(t *testing.T) {
+		backoffLimit := int32(1)
+		x := PipelineJob{}.ApplyTo(StepSpec{BackoffLimit: &backoffLimit})
+		assert.Equal(t, int32(1), x.GetBackoffLimit())
+	})
+}
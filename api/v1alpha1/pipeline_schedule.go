@@ -0,0 +1,75 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="";Allow;Forbid;Replace
+type ConcurrencyPolicy string
+
+const (
+	ConcurrencyPolicyAllow   ConcurrencyPolicy = "Allow"   // start a new run, even if the previous run is still running
+	ConcurrencyPolicyForbid  ConcurrencyPolicy = "Forbid"  // skip the new run if the previous run is still running
+	ConcurrencyPolicyReplace ConcurrencyPolicy = "Replace" // delete the previous run if it is still running
+)
+
+const (
+	defaultSuccessfulRunsHistoryLimit = 3
+	defaultFailedRunsHistoryLimit     = 1
+)
+
+var scheduleParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// PipelineSchedule makes the pipeline a template, from which a new pipeline (a run) is created on a schedule, like a
+// Kubernetes CronJob. Runs are named after the pipeline and the time they were scheduled, and should run to completion,
+// see PipelineSpec.Job.
+type PipelineSchedule struct {
+	// Cron is when runs are created, e.g. "0 * * * *" or "@daily".
+	Cron string `json:"cron" protobuf:"bytes,1,opt,name=cron"`
+	// ConcurrencyPolicy is what to do when a run is due, and the previous run is still running: Allow, Forbid or Replace.
+	// Defaults to Allow.
+	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrencyPolicy,omitempty" protobuf:"bytes,2,opt,name=concurrencyPolicy,casttype=ConcurrencyPolicy"`
+	// SuccessfulRunsHistoryLimit is the number of succeeded runs to keep. Defaults to 3.
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty" protobuf:"varint,3,opt,name=successfulRunsHistoryLimit"`
+	// FailedRunsHistoryLimit is the number of failed runs to keep. Defaults to 1.
+	FailedRunsHistoryLimit *int32 `json:"failedRunsHistoryLimit,omitempty" protobuf:"varint,4,opt,name=failedRunsHistoryLimit"`
+	// Suspend stops new runs being created. Runs that have already been created are not affected.
+	Suspend bool `json:"suspend,omitempty" protobuf:"varint,5,opt,name=suspend"`
+}
+
+func (in PipelineSchedule) GetSchedule() (cron.Schedule, error) {
+	s, err := scheduleParser.Parse(in.Cron)
+	if err != nil {
+		return nil, fmt.Errorf("cron: failed to parse %q: %w", in.Cron, err)
+	}
+	return s, nil
+}
+
+func (in PipelineSchedule) GetConcurrencyPolicy() ConcurrencyPolicy {
+	return ConcurrencyPolicy(StringOr(string(in.ConcurrencyPolicy), string(ConcurrencyPolicyAllow)))
+}
+
+func (in PipelineSchedule) GetSuccessfulRunsHistoryLimit() int {
+	if in.SuccessfulRunsHistoryLimit == nil {
+		return defaultSuccessfulRunsHistoryLimit
+	}
+	return int(*in.SuccessfulRunsHistoryLimit)
+}
+
+func (in PipelineSchedule) GetFailedRunsHistoryLimit() int {
+	if in.FailedRunsHistoryLimit == nil {
+		return defaultFailedRunsHistoryLimit
+	}
+	return int(*in.FailedRunsHistoryLimit)
+}
+
+// ScheduleStatus is the status of a scheduled pipeline's runs.
+type ScheduleStatus struct {
+	// LastScheduledTime is when the last run was due, whether or not it was created.
+	LastScheduledTime *metav1.Time `json:"lastScheduledTime,omitempty" protobuf:"bytes,1,opt,name=lastScheduledTime"`
+	// Active is the names of the runs that have not completed.
+	Active []string `json:"active,omitempty" protobuf:"bytes,2,rep,name=active"`
+}
@@ -0,0 +1,26 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineSchedule_GetSchedule(t *testing.T) {
+	_, err := PipelineSchedule{Cron: "@daily"}.GetSchedule()
+	assert.NoError(t, err)
+	_, err = PipelineSchedule{Cron: "x"}.GetSchedule()
+	assert.Error(t, err)
+}
+
+func TestPipelineSchedule(t *testing.T) {
+	x := PipelineSchedule{}
+	assert.Equal(t, ConcurrencyPolicyAllow, x.GetConcurrencyPolicy())
+	assert.Equal(t, 3, x.GetSuccessfulRunsHistoryLimit())
+	assert.Equal(t, 1, x.GetFailedRunsHistoryLimit())
+	limit := int32(0)
+	x = PipelineSchedule{ConcurrencyPolicy: ConcurrencyPolicyForbid, SuccessfulRunsHistoryLimit: &limit, FailedRunsHistoryLimit: &limit}
+	assert.Equal(t, ConcurrencyPolicyForbid, x.GetConcurrencyPolicy())
+	assert.Zero(t, x.GetSuccessfulRunsHistoryLimit())
+	assert.Zero(t, x.GetFailedRunsHistoryLimit())
+}
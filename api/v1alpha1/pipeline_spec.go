@@ -1,6 +1,8 @@
 package v1alpha1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -10,6 +12,141 @@ type PipelineSpec struct {
 	Steps []StepSpec `json:"steps,omitempty" protobuf:"bytes,1,rep,name=steps"`
 	// +kubebuilder:default="72h"
 	DeletionDelay *metav1.Duration `json:"deletionDelay,omitempty" protobuf:"bytes,2,opt,name=deletionDelay"`
+	// Upgrade makes this pipeline the new version of another pipeline.
+	Upgrade *Upgrade `json:"upgrade,omitempty" protobuf:"bytes,3,opt,name=upgrade"`
+	// Defaults are inherited by all steps, unless a step specifies its own value, e.g. so Kafka brokers need only be
+	// specified once.
+	Defaults *PipelineDefaults `json:"defaults,omitempty" protobuf:"bytes,4,opt,name=defaults"`
+	// Parameters are substituted for `{{name}}` in the steps and defaults, so one pipeline can be run with different
+	// values, e.g. topics in different environments.
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	Parameters []Parameter `json:"parameters,omitempty" protobuf:"bytes,5,rep,name=parameters"`
+	// Job makes the pipeline run like a Kubernetes Job, so it ends Succeeded or Failed, e.g. to be waited for by a
+	// workflow or CI. Its steps should run to completion, e.g. using bounded sources.
+	Job *PipelineJob `json:"job,omitempty" protobuf:"bytes,6,opt,name=job"`
+	// Schedule makes the pipeline a template, from which a new pipeline is run on a schedule. It does not run steps
+	// itself.
+	Schedule *PipelineSchedule `json:"schedule,omitempty" protobuf:"bytes,7,opt,name=schedule"`
+	// RevisionHistoryLimit is the number of old revisions of the spec to keep, so it can be rolled back to one of them.
+	// Defaults to 10.
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty" protobuf:"varint,8,opt,name=revisionHistoryLimit"`
+	// Quota limits the throughput of the whole pipeline, e.g. so it cannot starve other pipelines that share a Kafka
+	// cluster.
+	Quota *Quota `json:"quota,omitempty" protobuf:"bytes,9,opt,name=quota"`
+	// SLO are the pipeline's service level objectives, reported as its SLOViolated condition.
+	SLO *SLO `json:"slo,omitempty" protobuf:"bytes,10,opt,name=slo"`
+	// Tenant is the team or customer the pipeline belongs to. It is the `dataflow.argoproj.io/tenant` label of the
+	// pipeline's steps, and of everything the controller creates for them, e.g. for cost attribution or network policies.
+	Tenant string `json:"tenant,omitempty" protobuf:"bytes,11,opt,name=tenant"`
+	// Metadata is the labels and annotations of the pipeline's steps and their pods. Labels are also added to everything
+	// else the controller creates for them, e.g. services. A step's own metadata takes precedence.
+	Metadata *Metadata `json:"metadata,omitempty" protobuf:"bytes,12,opt,name=metadata"`
+	// ServiceAccountName is the service account of the steps that do not specify their own, so each pipeline can have
+	// its own permissions.
+	ServiceAccountName string `json:"serviceAccountName,omitempty" protobuf:"bytes,13,opt,name=serviceAccountName"`
+}
+
+// the kubebuilder default of steps' serviceAccountName
+const defaultServiceAccountName = "pipeline"
+
+const defaultRevisionHistoryLimit = 10
+
+func (in PipelineSpec) GetRevisionHistoryLimit() int {
+	if in.RevisionHistoryLimit == nil {
+		return defaultRevisionHistoryLimit
+	}
+	return int(*in.RevisionHistoryLimit)
+}
+
+// GetSteps returns the steps as they are run, with parameters substituted and defaults applied.
+func (in PipelineSpec) GetSteps() ([]StepSpec, error) {
+	// we must not modify the pipeline's steps
+	steps := append([]StepSpec(nil), in.Steps...)
+	defaults := in.Defaults
+	if len(in.Parameters) > 0 {
+		steps = nil
+		if err := substituteParameters(in.Parameters, in.Steps, &steps); err != nil {
+			return nil, fmt.Errorf("failed to substitute parameters into steps: %w", err)
+		}
+		if in.Defaults != nil {
+			defaults = nil
+			if err := substituteParameters(in.Parameters, in.Defaults, &defaults); err != nil {
+				return nil, fmt.Errorf("failed to substitute parameters into defaults: %w", err)
+			}
+		}
+	}
+	if defaults != nil {
+		for i, step := range steps {
+			steps[i] = defaults.ApplyTo(step)
+		}
+	}
+	if in.Job != nil {
+		for i, step := range steps {
+			steps[i] = in.Job.ApplyTo(step)
+		}
+	}
+	if in.Quota != nil {
+		steps = in.Quota.ApplyTo(steps)
+	}
+	if x := in.GetMetadata(); len(x.Labels) > 0 || len(x.Annotations) > 0 || in.ServiceAccountName != "" {
+		for i, step := range steps {
+			steps[i] = in.applyOwnership(x, step)
+		}
+	}
+	return steps, nil
+}
+
+// GetMetadata returns the metadata of the pipeline's steps, with the tenant label.
+func (in PipelineSpec) GetMetadata() Metadata {
+	x := Metadata{}
+	if in.Metadata != nil {
+		x = *in.Metadata.DeepCopy()
+	}
+	if in.Tenant != "" {
+		if x.Labels == nil {
+			x.Labels = map[string]string{}
+		}
+		x.Labels[KeyTenant] = in.Tenant
+	}
+	return x
+}
+
+// applyOwnership returns a copy of the step, with the pipeline's metadata, and service account if it has the default.
+// The tenant label cannot be changed by the step.
+func (in PipelineSpec) applyOwnership(x Metadata, step StepSpec) StepSpec {
+	step = *step.DeepCopy()
+	if in.ServiceAccountName != "" && (step.ServiceAccountName == "" || step.ServiceAccountName == defaultServiceAccountName) {
+		step.ServiceAccountName = in.ServiceAccountName
+	}
+	if len(x.Labels) == 0 && len(x.Annotations) == 0 {
+		return step
+	}
+	if step.Metadata == nil {
+		step.Metadata = &Metadata{}
+	}
+	step.Metadata.Labels = mergeMaps(step.Metadata.Labels, x.Labels)
+	step.Metadata.Annotations = mergeMaps(step.Metadata.Annotations, x.Annotations)
+	if in.Tenant != "" {
+		step.Metadata.Labels[KeyTenant] = in.Tenant
+	}
+	return step
+}
+
+// mergeMaps returns the map, with the entries of the other map that it does not have.
+func mergeMaps(m, other map[string]string) map[string]string {
+	if len(other) == 0 {
+		return m
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	for k, v := range other {
+		if _, ok := m[k]; !ok {
+			m[k] = v
+		}
+	}
+	return m
 }
 
 func (in *PipelineSpec) HasStep(name string) bool {
@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPipelineSpec_GetSteps(t *testing.T) {
+	t.Run("Parameters", func(t *testing.T) {
+		spec := PipelineSpec{
+			Parameters: []Parameter{{Name: "env", Value: `prod"`}, {Name: "brokers", Value: "kafka-prod"}},
+			Defaults:   &PipelineDefaults{Kafka: &KafkaConfig{Brokers: []string{"{{brokers}}"}}},
+			Steps: []StepSpec{{
+				Name:    "main",
+				Code:    &Code{Source: "{{ env }} {{ other }}"},
+				Sources: []Source{{Kafka: &KafkaSource{Kafka: Kafka{Topic: "input-{{ env }}"}}}},
+			}},
+		}
+		steps, err := spec.GetSteps()
+		assert.NoError(t, err)
+		if assert.Len(t, steps, 1) {
+			assert.Equal(t, `prod" {{ other }}`, steps[0].Code.Source)
+			assert.Equal(t, `input-prod"`, steps[0].Sources[0].Kafka.Topic)
+			assert.Equal(t, []string{"kafka-prod"}, steps[0].Sources[0].Kafka.Brokers)
+		}
+		assert.Equal(t, "input-{{ env }}", spec.Steps[0].Sources[0].Kafka.Topic, "pipeline is not modified")
+		assert.Empty(t, spec.Steps[0].Sources[0].Kafka.Brokers, "pipeline is not modified")
+	})
+	t.Run("Defaults", func(t *testing.T) {
+		spec := PipelineSpec{
+			Defaults: &PipelineDefaults{Kafka: &KafkaConfig{Brokers: []string{"kafka"}}},
+			Steps:    []StepSpec{{Sinks: []Sink{{Kafka: &KafkaSink{}}}}},
+		}
+		steps, err := spec.GetSteps()
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"kafka"}, steps[0].Sinks[0].Kafka.Brokers)
+		assert.Empty(t, spec.Steps[0].Sinks[0].Kafka.Brokers, "pipeline is not modified")
+	})
+	t.Run("Job", func(t *testing.T) {
+		spec := PipelineSpec{Job: &PipelineJob{}, Steps: []StepSpec{{Name: "main"}}}
+		steps, err := spec.GetSteps()
+		assert.NoError(t, err)
+		assert.Equal(t, corev1.RestartPolicyNever, steps[0].RestartPolicy)
+		assert.Equal(t, int32(DefaultBackoffLimit), steps[0].GetBackoffLimit())
+		assert.Nil(t, spec.Steps[0].BackoffLimit, "pipeline is not modified")
+	})
+	t.Run("Ownership", func(t *testing.T) {
+		spec := PipelineSpec{
+			Tenant:             "my-team",
+			Metadata:           &Metadata{Labels: map[string]string{"a": "1", "b": "1"}, Annotations: map[string]string{"c": "1"}},
+			ServiceAccountName: "my-sa",
+			Steps: []StepSpec{
+				{Name: "main", ServiceAccountName: "pipeline", Metadata: &Metadata{Labels: map[string]string{"b": "2", KeyTenant: "other"}}},
+				{Name: "other", ServiceAccountName: "other-sa"},
+			},
+		}
+		steps, err := spec.GetSteps()
+		assert.NoError(t, err)
+		assert.Equal(t, &Metadata{Labels: map[string]string{"a": "1", "b": "2", KeyTenant: "my-team"}, Annotations: map[string]string{"c": "1"}}, steps[0].Metadata)
+		assert.Equal(t, "my-sa", steps[0].ServiceAccountName)
+		assert.Equal(t, "other-sa", steps[1].ServiceAccountName, "the step's own service account takes precedence")
+		assert.Equal(t, map[string]string{"b": "2", KeyTenant: "other"}, spec.Steps[0].Metadata.Labels, "pipeline is not modified")
+	})
+}
+
+func TestPipelineSpec_GetMetadata(t *testing.T) {
+	assert.Equal(t, Metadata{}, PipelineSpec{}.GetMetadata())
+	assert.Equal(t, Metadata{Labels: map[string]string{KeyTenant: "my-team"}}, PipelineSpec{Tenant: "my-team"}.GetMetadata())
+}
+
+func TestPipelineSpec_GetRevisionHistoryLimit(t *testing.T) {
+	assert.Equal(t, 10, PipelineSpec{}.GetRevisionHistoryLimit())
+	x := int32(2)
+	assert.Equal(t, 2, PipelineSpec{RevisionHistoryLimit: &x}.GetRevisionHistoryLimit())
+}
@@ -7,4 +7,15 @@ type PipelineStatus struct {
 	Message     string             `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
 	Conditions  []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,3,rep,name=conditions"`
 	LastUpdated metav1.Time        `json:"lastUpdated,omitempty" protobuf:"bytes,4,opt,name=lastUpdated"`
+	// Upgrade compares the pipeline to the pipeline it replaces, if it is an upgrade.
+	Upgrade *UpgradeStatus `json:"upgrade,omitempty" protobuf:"bytes,5,opt,name=upgrade"`
+	// Schedule is the status of the pipeline's runs, if it is scheduled.
+	Schedule *ScheduleStatus `json:"schedule,omitempty" protobuf:"bytes,6,opt,name=schedule"`
+	// Revision is the number of the revision of the spec that is running. Revisions are stored as ControllerRevisions.
+	Revision int64 `json:"revision,omitempty" protobuf:"varint,7,opt,name=revision"`
+	// ObservedGeneration is the generation of the spec that the controller last reconciled. If it is less than the
+	// pipeline's generation, the controller has not yet acted on the latest change to the spec.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,8,opt,name=observedGeneration"`
+	// SLO is how the pipeline measures up to its service level objectives, if it has any.
+	SLO *SLOStatus `json:"slo,omitempty" protobuf:"bytes,9,opt,name=slo"`
 }
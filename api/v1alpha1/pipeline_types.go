@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"fmt"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -41,6 +43,11 @@ type PipelineList struct {
 	Items           []Pipeline `json:"items" protobuf:"bytes,2,rep,name=items"`
 }
 
+// GetRevisionName returns the name of the ControllerRevision that stores the pipeline's spec with the hash.
+func (in Pipeline) GetRevisionName(hash string) string {
+	return fmt.Sprintf("%s-%.10s", in.Name, hash)
+}
+
 func init() {
 	SchemeBuilder.Register(&Pipeline{}, &PipelineList{})
 }
@@ -0,0 +1,42 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Quota limits the throughput of a whole pipeline, so it cannot starve other pipelines of the capacity of a shared
+// broker, e.g. a Kafka cluster. Every message a step receives from its sources counts towards the quota, so a message
+// that passes through several steps counts once for each of them.
+//
+// The lead replica of the coordinator step divides the quota between the sidecars of every step, according to how
+// much each of them needs, and each sidecar limits itself to its share.
+type Quota struct {
+	// Messages is the most messages per second, across every replica of every step.
+	Messages uint32 `json:"messages,omitempty" protobuf:"varint,1,opt,name=messages"`
+	// Bytes is the most bytes per second, across every replica of every step, e.g. "10Mi".
+	Bytes *resource.Quantity `json:"bytes,omitempty" protobuf:"bytes,2,opt,name=bytes"`
+	// Coordinator is the name of the step whose lead replica divides the quota. Defaults to the pipeline's first step.
+	// It should not be scaled to zero, as sidecars do not process messages until they have been given a share.
+	Coordinator string `json:"coordinator,omitempty" protobuf:"bytes,3,opt,name=coordinator"`
+}
+
+// GetBytes returns the most bytes per second, or zero if bytes are not limited.
+func (in Quota) GetBytes() int64 {
+	if in.Bytes == nil {
+		return 0
+	}
+	return in.Bytes.Value()
+}
+
+// ApplyTo returns the steps with the quota, and its coordinator defaulted.
+func (in Quota) ApplyTo(steps []StepSpec) []StepSpec {
+	x := *in.DeepCopy()
+	if x.Coordinator == "" && len(steps) > 0 {
+		x.Coordinator = steps[0].Name
+	}
+	for i := range steps {
+		steps[i] = *steps[i].DeepCopy()
+		steps[i].Quota = x.DeepCopy()
+	}
+	return steps
+}
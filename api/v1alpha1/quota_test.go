@@ -0,0 +1,15 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuota_ApplyTo(t *testing.T) {
+	steps := Quota{Messages: 100}.ApplyTo([]StepSpec{{Name: "a"}, {Name: "b"}})
+	assert.Equal(t, &Quota{Messages: 100, Coordinator: "a"}, steps[0].Quota)
+	assert.Equal(t, &Quota{Messages: 100, Coordinator: "a"}, steps[1].Quota)
+	steps = Quota{Messages: 100, Coordinator: "b"}.ApplyTo([]StepSpec{{Name: "a"}, {Name: "b"}})
+	assert.Equal(t, "b", steps[0].Quota.Coordinator)
+}
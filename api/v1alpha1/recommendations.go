@@ -0,0 +1,76 @@
+package v1alpha1
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Recommendations makes the controller observe the CPU and memory usage of the step's containers, from the metrics API
+// (i.e. metrics-server), and recommend their requests in the step's status, like the Vertical Pod Autoscaler's
+// recommender, so over- and under-provisioned steps are visible.
+type Recommendations struct {
+	// HalfLife is how quickly the peak usage the recommendations are based on decays, so they follow usage down, as well
+	// as up, default 24h.
+	HalfLife *metav1.Duration `json:"halfLife,omitempty" protobuf:"bytes,1,opt,name=halfLife"`
+	// Apply makes the controller set the containers' requests to the recommendations, re-creating the step's pods, each
+	// time they differ from the requests it last applied by more than 20%. Requests are capped at the containers'
+	// limits, which are not changed.
+	Apply bool `json:"apply,omitempty" protobuf:"varint,2,opt,name=apply"`
+}
+
+func (in Recommendations) GetHalfLife() time.Duration {
+	if in.HalfLife != nil {
+		return in.HalfLife.Duration
+	}
+	return 24 * time.Hour
+}
+
+const (
+	ProvisioningOver  = "Over"  // the container's requests are more than twice the recommendation
+	ProvisioningUnder = "Under" // the container's peak usage is more than its requests
+)
+
+// ContainerRecommendation is the observed usage of one of the step's containers, and its recommended requests.
+type ContainerRecommendation struct {
+	// Name is the container's name, e.g. "main" or "sidecar".
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// PeakUsage is the peak CPU and memory usage of any of the step's replicas, decayed by the half-life.
+	PeakUsage map[string]resource.Quantity `json:"peakUsage,omitempty" protobuf:"bytes,2,rep,name=peakUsage"`
+	// Requests are the recommended requests, i.e. the peak usage with 20% headroom.
+	Requests map[string]resource.Quantity `json:"requests,omitempty" protobuf:"bytes,3,rep,name=requests"`
+	// Applied are the requests the controller last applied, if it applies the recommendations.
+	Applied map[string]resource.Quantity `json:"applied,omitempty" protobuf:"bytes,4,rep,name=applied"`
+	// Provisioning is "Over" if the container's requests are more than twice the recommendation, and "Under" if its peak
+	// usage is more than its requests.
+	Provisioning string `json:"provisioning,omitempty" protobuf:"bytes,5,opt,name=provisioning"`
+}
+
+// RecommendationsStatus is the step's containers' observed usage, and their recommended requests.
+type RecommendationsStatus struct {
+	// ObservedAt is when the usage was last observed.
+	ObservedAt metav1.Time `json:"observedAt,omitempty" protobuf:"bytes,1,opt,name=observedAt"`
+	// Containers are the recommendations for each container, by name.
+	Containers []ContainerRecommendation `json:"containers,omitempty" protobuf:"bytes,2,rep,name=containers"`
+	// Since is when the usage was first observed. Recommendations are not applied until it has been observed for an
+	// hour, so they are not based on a replica that has only just started.
+	Since metav1.Time `json:"since,omitempty" protobuf:"bytes,3,opt,name=since"`
+}
+
+// GetApplied returns the requests applied to each container, by name, or nil if none are.
+func (in *RecommendationsStatus) GetApplied() map[string]map[string]resource.Quantity {
+	if in == nil {
+		return nil
+	}
+	var applied map[string]map[string]resource.Quantity
+	for _, c := range in.Containers {
+		if len(c.Applied) > 0 {
+			if applied == nil {
+				applied = map[string]map[string]resource.Quantity{}
+			}
+			applied[c.Name] = c.Applied
+		}
+	}
+	return applied
+}
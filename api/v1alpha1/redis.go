@@ -0,0 +1,38 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Redis is a Redis pub/sub channel. Messages are not persisted, so they are lost if no subscriber is connected when
+// they are published. Use Redis pub/sub for fire-and-forget fan-out, not where messages must not be lost.
+// https://redis.io/topics/pubsub
+type Redis struct {
+	// Name of the "dataflow-redis-{name}" secret, whose "url" and "password" are used if not specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// URL of the Redis server, e.g. "redis://redis:6379/0".
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// Channel is the channel messages are published to, or subscribed to.
+	Channel string `json:"channel" protobuf:"bytes,3,opt,name=channel"`
+	// PasswordSecret is the secret selector to the server's password.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,4,opt,name=passwordSecret"`
+}
+
+// RedisSource subscribes to a Redis pub/sub channel. Every replica receives every message.
+type RedisSource struct {
+	Redis `json:",inline" protobuf:"bytes,1,opt,name=redis"`
+	// Pattern subscribes to the channels matching the channel as a glob-style pattern, e.g. "orders.*".
+	Pattern bool `json:"pattern,omitempty" protobuf:"varint,2,opt,name=pattern"`
+}
+
+func (r RedisSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:redis:%s:%s", r.URL, r.Channel)
+}
+
+// RedisSink publishes messages to a Redis pub/sub channel.
+type RedisSink struct {
+	Redis `json:",inline" protobuf:"bytes,1,opt,name=redis"`
+}
@@ -0,0 +1,87 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RedisStream is a Redis stream. Unlike pub/sub, a stream persists its entries, so they are not lost if no consumer is
+// connected when they are added.
+// https://redis.io/topics/streams-intro
+type RedisStream struct {
+	// Name of the "dataflow-redis-{name}" secret, whose "url" and "password" are used if not specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// URL of the Redis server, e.g. "redis://redis:6379/0".
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// Stream is the key of the stream.
+	Stream string `json:"stream" protobuf:"bytes,3,opt,name=stream"`
+	// PasswordSecret is the secret selector to the server's password.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,4,opt,name=passwordSecret"`
+	// Field is the field of each entry that holds the message.
+	// +kubebuilder:default=message
+	Field string `json:"field,omitempty" protobuf:"bytes,5,opt,name=field"`
+}
+
+func (in RedisStream) GetField() string {
+	return StringOr(in.Field, "message")
+}
+
+// RedisStreamSource reads a Redis stream as one of a consumer group (XREADGROUP), so the replicas share its entries.
+type RedisStreamSource struct {
+	RedisStream `json:",inline" protobuf:"bytes,1,opt,name=redisStream"`
+	// Group is the consumer group. If not specified, a unique deterministic group is generated. The group is created if
+	// it does not exist.
+	Group string `json:"group,omitempty" protobuf:"bytes,2,opt,name=group"`
+	// StartID is the ID of the entry a new group starts after: "$" (the default) for entries added from now on, or "0"
+	// for every entry in the stream.
+	// +kubebuilder:default=$
+	StartID string `json:"startId,omitempty" protobuf:"bytes,3,opt,name=startId"`
+	// Count is the most entries read at once.
+	// +kubebuilder:default=10
+	Count uint32 `json:"count,omitempty" protobuf:"varint,4,opt,name=count"`
+	// MinIdleTime is how long an entry must have been delivered but not acknowledged, e.g. because it failed to be
+	// processed, or the replica it was delivered to stopped, before it is claimed and retried.
+	// +kubebuilder:default="1m"
+	MinIdleTime *metav1.Duration `json:"minIdleTime,omitempty" protobuf:"bytes,5,opt,name=minIdleTime"`
+	// MaxDeliveries is the most times an entry is delivered. An entry that has been delivered this many times is
+	// acknowledged rather than claimed again, so it is dropped. Zero means it is retried until it is processed.
+	MaxDeliveries uint32 `json:"maxDeliveries,omitempty" protobuf:"varint,6,opt,name=maxDeliveries"`
+}
+
+func (in RedisStreamSource) GetGroup(defaultGroup string) string {
+	return StringOr(in.Group, defaultGroup)
+}
+
+func (in RedisStreamSource) GetStartID() string {
+	return StringOr(in.StartID, "$")
+}
+
+func (in RedisStreamSource) GetCount() int64 {
+	if in.Count > 0 {
+		return int64(in.Count)
+	}
+	return 10
+}
+
+func (in RedisStreamSource) GetMinIdleTime() time.Duration {
+	if in.MinIdleTime != nil {
+		return in.MinIdleTime.Duration
+	}
+	return time.Minute
+}
+
+func (in RedisStreamSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:redis:%s:stream:%s", in.URL, in.Stream)
+}
+
+// RedisStreamSink adds messages to a Redis stream (XADD).
+type RedisStreamSink struct {
+	RedisStream `json:",inline" protobuf:"bytes,1,opt,name=redisStream"`
+	// MaxLen, if specified, trims the stream to about this many entries as messages are added, so it does not grow
+	// without bound.
+	MaxLen uint64 `json:"maxLen,omitempty" protobuf:"varint,2,opt,name=maxLen"`
+}
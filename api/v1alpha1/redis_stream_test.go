@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestRedisStream_GetField(t *testing.T) {
+	assert.Equal(t, "message", RedisStream{}.GetField())
+	assert.Equal(t, "data", RedisStream{Field: "data"}.GetField())
+}
+
+func TestRedisStreamSource_GetGroup(t *testing.T) {
+	assert.Equal(t, "my-default", RedisStreamSource{}.GetGroup("my-default"))
+	assert.Equal(t, "my-group", RedisStreamSource{Group: "my-group"}.GetGroup("my-default"))
+}
+
+func TestRedisStreamSource_GetStartID(t *testing.T) {
+	assert.Equal(t, "$", RedisStreamSource{}.GetStartID())
+	assert.Equal(t, "0", RedisStreamSource{StartID: "0"}.GetStartID())
+}
+
+func TestRedisStreamSource_GetCount(t *testing.T) {
+	assert.Equal(t, int64(10), RedisStreamSource{}.GetCount())
+	assert.Equal(t, int64(100), RedisStreamSource{Count: 100}.GetCount())
+}
+
+func TestRedisStreamSource_GetMinIdleTime(t *testing.T) {
+	assert.Equal(t, time.Minute, RedisStreamSource{}.GetMinIdleTime())
+	assert.Equal(t, time.Second, RedisStreamSource{MinIdleTime: &metav1.Duration{Duration: time.Second}}.GetMinIdleTime())
+}
+
+func TestRedisStreamSource_GenURN(t *testing.T) {
+	x := RedisStreamSource{RedisStream: RedisStream{URL: "redis://redis:6379", Stream: "my-stream"}}
+	assert.Equal(t, "urn:dataflow:redis:redis://redis:6379:stream:my-stream", x.GenURN(cluster, namespace))
+}
@@ -0,0 +1,12 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisSource_GenURN(t *testing.T) {
+	x := RedisSource{Redis: Redis{URL: "redis://redis:6379", Channel: "my-channel"}}
+	assert.Equal(t, "urn:dataflow:redis:redis://redis:6379:my-channel", x.GenURN(cluster, namespace))
+}
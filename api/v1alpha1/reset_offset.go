@@ -0,0 +1,53 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ResetOffset is where to reset a step's Kafka consumer groups and STAN durable queues to, either "earliest", or an
+// RFC3339 timestamp, e.g. "2021-09-01T00:00:00Z". It is requested using the "dataflow.argoproj.io/reset-offset" step
+// annotation.
+type ResetOffset string
+
+const ResetOffsetEarliest ResetOffset = "earliest"
+
+// GetTime returns the time to reset to, or the zero time if it is earliest.
+func (m ResetOffset) GetTime() (time.Time, error) {
+	if m == ResetOffsetEarliest {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, string(m))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reset offset %q must be %q or an RFC3339 timestamp", m, ResetOffsetEarliest)
+	}
+	return t, nil
+}
+
+// +kubebuilder:validation:Enum="";Stopping;Resetting;Succeeded;Failed
+type ResetPhase string
+
+const (
+	ResetStopping  ResetPhase = "Stopping"  // waiting for every pod to be deleted, so the consumer groups are empty
+	ResetResetting ResetPhase = "Resetting" // replica 0 resets the sources when it starts
+	ResetSucceeded ResetPhase = "Succeeded"
+	ResetFailed    ResetPhase = "Failed"
+)
+
+func (p ResetPhase) InProgress() bool {
+	return p == ResetStopping || p == ResetResetting
+}
+
+type ResetStatus struct {
+	Offset      ResetOffset `json:"offset" protobuf:"bytes,1,opt,name=offset,casttype=ResetOffset"`
+	Phase       ResetPhase  `json:"phase,omitempty" protobuf:"bytes,2,opt,name=phase,casttype=ResetPhase"`
+	Message     string      `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+	RequestedAt metav1.Time `json:"requestedAt,omitempty" protobuf:"bytes,4,opt,name=requestedAt"`
+}
+
+// Resetting returns true if the pod must reset the sources when it starts.
+func (m *ResetStatus) Resetting() bool {
+	return m != nil && m.Phase == ResetResetting
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetOffset_GetTime(t *testing.T) {
+	v, err := ResetOffsetEarliest.GetTime()
+	assert.NoError(t, err)
+	assert.True(t, v.IsZero())
+	v, err = ResetOffset("2021-09-01T00:00:00Z").GetTime()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC), v)
+	_, err = ResetOffset("latest").GetTime()
+	assert.EqualError(t, err, `reset offset "latest" must be "earliest" or an RFC3339 timestamp`)
+}
+
+func TestResetStatus_Resetting(t *testing.T) {
+	var s *ResetStatus
+	assert.False(t, s.Resetting())
+	assert.False(t, (&ResetStatus{Phase: ResetStopping}).Resetting())
+	assert.True(t, (&ResetStatus{Phase: ResetResetting}).Resetting())
+	assert.True(t, ResetStopping.InProgress())
+	assert.False(t, ResetSucceeded.InProgress())
+}
@@ -0,0 +1,135 @@
+package v1alpha1
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+// ValidateRestricted returns an error if the step's pods would not comply with the "restricted" Pod Security Standard.
+// https://kubernetes.io/docs/concepts/security/pod-security-standards/#restricted
+func (in Step) ValidateRestricted() error {
+	return validateRestrictedPodSpec(in.GetPodSpec(GetPodSpecReq{Restricted: true}))
+}
+
+// restrictSecurityContext returns a copy of the container's security context, with the settings the "restricted" Pod
+// Security Standard requires added where the user has not set them. Settings the user has set are kept, so that a
+// container that cannot comply (e.g. it is privileged) still fails validation.
+func restrictSecurityContext(in *corev1.SecurityContext) *corev1.SecurityContext {
+	x := in.DeepCopy()
+	if x == nil {
+		x = &corev1.SecurityContext{}
+	}
+	if x.AllowPrivilegeEscalation == nil {
+		x.AllowPrivilegeEscalation = pointer.BoolPtr(false)
+	}
+	if x.RunAsNonRoot == nil {
+		x.RunAsNonRoot = pointer.BoolPtr(true)
+	}
+	if x.Capabilities == nil {
+		x.Capabilities = &corev1.Capabilities{}
+	}
+	if !hasCapability(x.Capabilities.Drop, "ALL") {
+		x.Capabilities.Drop = append(x.Capabilities.Drop, "ALL")
+	}
+	return x
+}
+
+func validateRestrictedPodSpec(spec corev1.PodSpec) error {
+	if spec.HostNetwork || spec.HostPID || spec.HostIPC {
+		return fmt.Errorf("host namespaces are not allowed")
+	}
+	for _, v := range spec.Volumes {
+		if err := validateRestrictedVolume(v); err != nil {
+			return err
+		}
+	}
+	podSecurityContext := spec.SecurityContext
+	if podSecurityContext == nil {
+		podSecurityContext = &corev1.PodSecurityContext{}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		if err := validateRestrictedContainer(c, *podSecurityContext); err != nil {
+			return fmt.Errorf("container %q: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+func validateRestrictedVolume(v corev1.Volume) error {
+	x := v.VolumeSource
+	if x.ConfigMap != nil || x.CSI != nil || x.DownwardAPI != nil || x.EmptyDir != nil || x.Ephemeral != nil ||
+		x.PersistentVolumeClaim != nil || x.Projected != nil || x.Secret != nil {
+		return nil
+	}
+	return fmt.Errorf("volume %q: only configMap, csi, downwardAPI, emptyDir, ephemeral, persistentVolumeClaim, projected and secret volumes are allowed", v.Name)
+}
+
+func validateRestrictedContainer(c corev1.Container, podSecurityContext corev1.PodSecurityContext) error {
+	for _, p := range c.Ports {
+		if p.HostPort != 0 {
+			return fmt.Errorf("host ports are not allowed")
+		}
+	}
+	x := c.SecurityContext
+	if x == nil {
+		x = &corev1.SecurityContext{}
+	}
+	if x.Privileged != nil && *x.Privileged {
+		return fmt.Errorf("privileged containers are not allowed")
+	}
+	if x.AllowPrivilegeEscalation == nil || *x.AllowPrivilegeEscalation {
+		return fmt.Errorf("allowPrivilegeEscalation must be false")
+	}
+	if !isTrue(x.RunAsNonRoot, podSecurityContext.RunAsNonRoot) {
+		return fmt.Errorf("runAsNonRoot must be true")
+	}
+	if isZero(x.RunAsUser, podSecurityContext.RunAsUser) {
+		return fmt.Errorf("runAsUser must not be 0")
+	}
+	if !isRuntimeDefaultOrLocalhost(x.SeccompProfile, podSecurityContext.SeccompProfile) {
+		return fmt.Errorf("seccompProfile must be RuntimeDefault or Localhost")
+	}
+	if x.Capabilities == nil || !hasCapability(x.Capabilities.Drop, "ALL") {
+		return fmt.Errorf("capabilities must drop ALL")
+	}
+	for _, c := range x.Capabilities.Add {
+		if c != "NET_BIND_SERVICE" {
+			return fmt.Errorf("only the NET_BIND_SERVICE capability may be added")
+		}
+	}
+	return nil
+}
+
+// the container's value takes precedence over the pod's
+func isTrue(container, pod *bool) bool {
+	if container != nil {
+		return *container
+	}
+	return pod != nil && *pod
+}
+
+func isZero(container, pod *int64) bool {
+	if container != nil {
+		return *container == 0
+	}
+	return pod != nil && *pod == 0
+}
+
+func isRuntimeDefaultOrLocalhost(container, pod *corev1.SeccompProfile) bool {
+	x := container
+	if x == nil {
+		x = pod
+	}
+	return x != nil && (x.Type == corev1.SeccompProfileTypeRuntimeDefault || x.Type == corev1.SeccompProfileTypeLocalhost)
+}
+
+func hasCapability(capabilities []corev1.Capability, c corev1.Capability) bool {
+	for _, x := range capabilities {
+		if x == c {
+			return true
+		}
+	}
+	return false
+}
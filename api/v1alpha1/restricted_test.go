@@ -0,0 +1,96 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
+)
+
+func TestStep_ValidateRestricted(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		step := Step{Spec: StepSpec{Cat: &Cat{}, Sources: []Source{{Name: "my-source"}}}}
+		assert.NoError(t, step.ValidateRestricted())
+	})
+	t.Run("HostPathVolume", func(t *testing.T) {
+		step := Step{Spec: StepSpec{Cat: &Cat{}, Volumes: []corev1.Volume{{
+			Name:         "my-volume",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}},
+		}}}}
+		assert.EqualError(t, step.ValidateRestricted(), `volume "my-volume": only configMap, csi, downwardAPI, emptyDir, ephemeral, persistentVolumeClaim, projected and secret volumes are allowed`)
+	})
+}
+
+func TestStep_ValidateRestricted_Containers(t *testing.T) {
+	t.Run("Restricted", func(t *testing.T) {
+		step := Step{Spec: StepSpec{Cat: &Cat{}, Containers: []corev1.Container{{Name: "redis", Image: "redis"}}}}
+		assert.NoError(t, step.ValidateRestricted())
+		assert.Nil(t, step.Spec.Containers[0].SecurityContext, "the step is not modified")
+	})
+	t.Run("Privileged", func(t *testing.T) {
+		step := Step{Spec: StepSpec{Cat: &Cat{}, Containers: []corev1.Container{{
+			Name:            "redis",
+			SecurityContext: &corev1.SecurityContext{Privileged: pointer.BoolPtr(true)},
+		}}}}
+		assert.EqualError(t, step.ValidateRestricted(), `container "redis": privileged containers are not allowed`)
+	})
+}
+
+func Test_restrictSecurityContext(t *testing.T) {
+	x := restrictSecurityContext(&corev1.SecurityContext{
+		RunAsUser:    pointer.Int64Ptr(1000),
+		Capabilities: &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}},
+	})
+	assert.Equal(t, &corev1.SecurityContext{
+		RunAsUser:                pointer.Int64Ptr(1000),
+		RunAsNonRoot:             pointer.BoolPtr(true),
+		AllowPrivilegeEscalation: pointer.BoolPtr(false),
+		Capabilities:             &corev1.Capabilities{Add: []corev1.Capability{"NET_BIND_SERVICE"}, Drop: []corev1.Capability{"ALL"}},
+	}, x)
+}
+
+func Test_validateRestrictedContainer(t *testing.T) {
+	pod := corev1.PodSecurityContext{
+		RunAsNonRoot:   pointer.BoolPtr(true),
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	ok := func() *corev1.SecurityContext {
+		return &corev1.SecurityContext{
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			AllowPrivilegeEscalation: pointer.BoolPtr(false),
+		}
+	}
+	t.Run("OK", func(t *testing.T) {
+		assert.NoError(t, validateRestrictedContainer(corev1.Container{SecurityContext: ok()}, pod))
+	})
+	t.Run("NoSecurityContext", func(t *testing.T) {
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{}, pod), "allowPrivilegeEscalation must be false")
+	})
+	t.Run("Privileged", func(t *testing.T) {
+		x := ok()
+		x.Privileged = pointer.BoolPtr(true)
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{SecurityContext: x}, pod), "privileged containers are not allowed")
+	})
+	t.Run("RunAsRoot", func(t *testing.T) {
+		x := ok()
+		x.RunAsUser = pointer.Int64Ptr(0)
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{SecurityContext: x}, pod), "runAsUser must not be 0")
+	})
+	t.Run("NoSeccompProfile", func(t *testing.T) {
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{SecurityContext: ok()}, corev1.PodSecurityContext{RunAsNonRoot: pointer.BoolPtr(true)}), "seccompProfile must be RuntimeDefault or Localhost")
+	})
+	t.Run("LowerCaseAll", func(t *testing.T) {
+		x := ok()
+		x.Capabilities.Drop = []corev1.Capability{"all"}
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{SecurityContext: x}, pod), "capabilities must drop ALL")
+	})
+	t.Run("AddCapability", func(t *testing.T) {
+		x := ok()
+		x.Capabilities.Add = []corev1.Capability{"SYS_ADMIN"}
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{SecurityContext: x}, pod), "only the NET_BIND_SERVICE capability may be added")
+	})
+	t.Run("HostPort", func(t *testing.T) {
+		assert.EqualError(t, validateRestrictedContainer(corev1.Container{Ports: []corev1.ContainerPort{{HostPort: 80}}}, pod), "host ports are not allowed")
+	})
+}
@@ -0,0 +1,65 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Rollout is how the step's pods are updated when its spec changes, e.g. to use a new image. By default, every pod is
+// replaced at once.
+type Rollout struct {
+	// Canary runs the new spec on some of the replicas, while the rest keep running the old spec, then either promotes it
+	// to every replica, or rolls it back.
+	Canary *CanaryRollout `json:"canary,omitempty" protobuf:"bytes,1,opt,name=canary"`
+}
+
+type CanaryRollout struct {
+	// Weight is the percentage of replicas that run the new spec, rounded up. At least one replica runs the new spec
+	// and, if there is more than one replica, at least one keeps running the old spec. Replicas share a consumer group
+	// or queue, so this is also roughly the percentage of Kafka partitions, or messages, that the canary processes.
+	// +kubebuilder:default=20
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	Weight uint32 `json:"weight,omitempty" protobuf:"varint,1,opt,name=weight"`
+	// Duration is how long the canary runs before its error rate is checked.
+	// +kubebuilder:default="5m"
+	Duration *metav1.Duration `json:"duration,omitempty" protobuf:"bytes,2,opt,name=duration"`
+	// MaxErrorRate is the highest ratio of source errors to source messages (i.e. sources_errors/sources_total) of the
+	// canary replicas for it to be promoted, rather than rolled back, e.g. "0.01" is 1%.
+	// +kubebuilder:default="0.01"
+	MaxErrorRate string `json:"maxErrorRate,omitempty" protobuf:"bytes,3,opt,name=maxErrorRate"`
+}
+
+// GetReplicas returns how many of the replicas run the canary.
+func (m CanaryRollout) GetReplicas(replicas int) int {
+	n := int(math.Ceil(float64(replicas) * float64(m.Weight) / 100))
+	if n >= replicas {
+		n = replicas - 1
+	}
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+func (m CanaryRollout) GetDuration() time.Duration {
+	if m.Duration == nil {
+		return 5 * time.Minute
+	}
+	return m.Duration.Duration
+}
+
+func (m CanaryRollout) GetMaxErrorRate() (float64, error) {
+	if m.MaxErrorRate == "" {
+		return 0.01, nil
+	}
+	v, err := strconv.ParseFloat(m.MaxErrorRate, 64)
+	if err != nil || v < 0 || v > 1 {
+		return 0, fmt.Errorf("maxErrorRate %q must be a number between 0 and 1", m.MaxErrorRate)
+	}
+	return v, nil
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:validation:Enum="";Progressing;Promoted;RolledBack
+type RolloutPhase string
+
+const (
+	RolloutProgressing RolloutPhase = "Progressing"
+	RolloutPromoted    RolloutPhase = "Promoted"
+	RolloutRolledBack  RolloutPhase = "RolledBack"
+)
+
+type RolloutStatus struct {
+	Phase   RolloutPhase `json:"phase,omitempty" protobuf:"bytes,1,opt,name=phase,casttype=RolloutPhase"`
+	Message string       `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+	// StableHash is the hash of the spec the stable replicas run. The spec is stored in a ControllerRevision, see
+	// Step.GetRevisionName.
+	StableHash string `json:"stableHash,omitempty" protobuf:"bytes,3,opt,name=stableHash"`
+	// CanaryHash is the hash of the spec the canary replicas run, if a canary is progressing.
+	CanaryHash string `json:"canaryHash,omitempty" protobuf:"bytes,4,opt,name=canaryHash"`
+	// RolledBackHash is the hash of the last spec that was rolled back. It is not rolled out again.
+	RolledBackHash string `json:"rolledBackHash,omitempty" protobuf:"bytes,5,opt,name=rolledBackHash"`
+	// StartedAt is when the current, or last, canary started.
+	StartedAt metav1.Time `json:"startedAt,omitempty" protobuf:"bytes,6,opt,name=startedAt"`
+}
@@ -0,0 +1,35 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCanaryRollout_GetReplicas(t *testing.T) {
+	x := CanaryRollout{Weight: 20}
+	assert.Equal(t, 1, x.GetReplicas(1))
+	assert.Equal(t, 1, x.GetReplicas(2))
+	assert.Equal(t, 1, x.GetReplicas(5))
+	assert.Equal(t, 2, x.GetReplicas(6))
+	x = CanaryRollout{Weight: 100}
+	assert.Equal(t, 4, x.GetReplicas(5))
+}
+
+func TestCanaryRollout_GetDuration(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, CanaryRollout{}.GetDuration())
+	assert.Equal(t, time.Minute, CanaryRollout{Duration: &metav1.Duration{Duration: time.Minute}}.GetDuration())
+}
+
+func TestCanaryRollout_GetMaxErrorRate(t *testing.T) {
+	v, err := CanaryRollout{}.GetMaxErrorRate()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.01, v)
+	v, err = CanaryRollout{MaxErrorRate: "0.5"}.GetMaxErrorRate()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, v)
+	_, err = CanaryRollout{MaxErrorRate: "2"}.GetMaxErrorRate()
+	assert.EqualError(t, err, `maxErrorRate "2" must be a number between 0 and 1`)
+}
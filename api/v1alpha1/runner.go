@@ -0,0 +1,27 @@
+package v1alpha1
+
+import corev1 "k8s.io/api/core/v1"
+
+// Runner overrides the runner image, which is used by the init and sidecar containers, and by the built-in steps (e.g.
+// cat, map, and git). Use it to pull from an air-gapped registry, or to try a new version of the runner on some
+// pipelines.
+type Runner struct {
+	// Image is the runner image, e.g. "my-registry/dataflow-runner:v0.0.1".
+	Image string `json:"image,omitempty" protobuf:"bytes,1,opt,name=image"`
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty" protobuf:"bytes,2,opt,name=imagePullPolicy,casttype=k8s.io/api/core/v1.PullPolicy"`
+}
+
+func (in *Runner) GetImage(def string) string {
+	if in != nil && in.Image != "" {
+		return in.Image
+	}
+	return def
+}
+
+func (in *Runner) GetImagePullPolicy(def corev1.PullPolicy) corev1.PullPolicy {
+	if in != nil && in.ImagePullPolicy != "" {
+		return in.ImagePullPolicy
+	}
+	return def
+}
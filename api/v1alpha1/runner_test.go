@@ -0,0 +1,17 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRunner(t *testing.T) {
+	var x *Runner
+	assert.Equal(t, "def", x.GetImage("def"))
+	assert.Equal(t, corev1.PullIfNotPresent, x.GetImagePullPolicy(corev1.PullIfNotPresent))
+	x = &Runner{Image: "my-image", ImagePullPolicy: corev1.PullAlways}
+	assert.Equal(t, "my-image", x.GetImage("def"))
+	assert.Equal(t, corev1.PullAlways, x.GetImagePullPolicy(corev1.PullIfNotPresent))
+}
@@ -2,4 +2,6 @@ package v1alpha1
 
 type S3Sink struct {
 	S3 `json:",inline" protobuf:"bytes,4,opt,name=s3"`
+	// Encryption encrypts objects before they are uploaded, so they are never stored in plaintext.
+	Encryption *Encryption `json:"encryption,omitempty" protobuf:"bytes,5,opt,name=encryption"`
 }
@@ -1,13 +1,77 @@
 package v1alpha1
 
 import (
+	"strings"
+
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// +kubebuilder:validation:Enum=Path;Object;Lines
+type S3Emit string
+
+const (
+	S3EmitPath   S3Emit = "Path"   // a JSON object of the object's key, and the path of a FIFO its data is streamed to
+	S3EmitObject S3Emit = "Object" // the object's data
+	S3EmitLines  S3Emit = "Lines"  // each line of the object's data
+)
+
 type S3Source struct {
 	S3 `json:",inline" protobuf:"bytes,7,opt,name=s3"`
 	// +kubebuilder:default="1m"
 	PollPeriod *metav1.Duration `json:"pollPeriod,omitempty" protobuf:"bytes,6,opt,name=pollPeriod"`
 	// +kubebuilder:default=1
 	Concurrency uint32 `json:"concurrency,omitempty" protobuf:"varint,8,opt,name=concurrency"`
+	// Prefix, if specified, only processes the objects whose keys start with it, e.g. "incoming/".
+	Prefix string `json:"prefix,omitempty" protobuf:"bytes,9,opt,name=prefix"`
+	// Emit is what each message is: "Path" (the default) is a JSON object of the object's key, and the path of a FIFO
+	// its data is streamed to, "Object" is the object's data, and "Lines" is each line of the object's data.
+	// +kubebuilder:default=Path
+	Emit S3Emit `json:"emit,omitempty" protobuf:"bytes,10,opt,name=emit,casttype=S3Emit"`
+	// OnProcessed is how objects are marked as processed, so they are not processed again. If not specified, they are
+	// deleted.
+	OnProcessed *S3OnProcessed `json:"onProcessed,omitempty" protobuf:"bytes,11,opt,name=onProcessed"`
+	// Notifications, if specified, receives the bucket's event notifications from an SQS queue, so objects are processed
+	// as soon as they are created, rather than when the bucket is next listed.
+	Notifications *S3Notifications `json:"notifications,omitempty" protobuf:"bytes,12,opt,name=notifications"`
+}
+
+func (in S3Source) GetEmit() S3Emit {
+	if in.Emit != "" {
+		return in.Emit
+	}
+	return S3EmitPath
+}
+
+// IsUnprocessed returns whether the key is one the source processes, i.e. it has the prefix, and it is not under the
+// prefix processed objects are moved to.
+func (in S3Source) IsUnprocessed(key string) bool {
+	if !strings.HasPrefix(key, in.Prefix) {
+		return false
+	}
+	if x := in.OnProcessed; x != nil && x.MoveToPrefix != "" && strings.HasPrefix(key, x.MoveToPrefix) {
+		return false
+	}
+	return true
+}
+
+// S3OnProcessed marks processed objects, rather than deleting them.
+type S3OnProcessed struct {
+	// Tag, if specified, tags each object, e.g. "processed=true". Objects with the tag are not processed.
+	Tag *S3Tag `json:"tag,omitempty" protobuf:"bytes,1,opt,name=tag"`
+	// MoveToPrefix, if specified, moves each object to this prefix, e.g. "processed/", by copying it, and deleting the
+	// original. The source's prefix is replaced, e.g. "incoming/a.csv" is moved to "processed/a.csv". Objects under this
+	// prefix are not processed.
+	MoveToPrefix string `json:"moveToPrefix,omitempty" protobuf:"bytes,2,opt,name=moveToPrefix"`
+}
+
+type S3Tag struct {
+	Key   string `json:"key" protobuf:"bytes,1,opt,name=key"`
+	Value string `json:"value,omitempty" protobuf:"bytes,2,opt,name=value"`
+}
+
+// S3Notifications is an SQS queue the bucket sends its "s3:ObjectCreated:*" event notifications to. The queue is
+// accessed with the bucket's credentials, and endpoint, if specified.
+type S3Notifications struct {
+	// QueueURL is the queue's URL, e.g. "https://sqs.us-west-2.amazonaws.com/123456789012/my-queue".
+	QueueURL string `json:"queueUrl" protobuf:"bytes,1,opt,name=queueUrl"`
 }
@@ -0,0 +1,20 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestS3Source_GetEmit(t *testing.T) {
+	assert.Equal(t, S3EmitPath, S3Source{}.GetEmit())
+	assert.Equal(t, S3EmitLines, S3Source{Emit: S3EmitLines}.GetEmit())
+}
+
+func TestS3Source_IsUnprocessed(t *testing.T) {
+	assert.True(t, S3Source{}.IsUnprocessed("a.csv"))
+	x := S3Source{Prefix: "incoming/", OnProcessed: &S3OnProcessed{MoveToPrefix: "incoming/processed/"}}
+	assert.True(t, x.IsUnprocessed("incoming/a.csv"))
+	assert.False(t, x.IsUnprocessed("other/a.csv"))
+	assert.False(t, x.IsUnprocessed("incoming/processed/a.csv"))
+}
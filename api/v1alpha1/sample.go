@@ -0,0 +1,36 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Sample keeps a percentage of messages, and drops the rest, e.g. to build a downsampled branch of a high-volume
+// pipeline for monitoring or debugging.
+type Sample struct {
+	AbstractStep `json:",inline" protobuf:"bytes,1,opt,name=abstractStep"`
+	// Percent is the percentage of messages to keep, e.g. "10" or "0.1".
+	Percent string `json:"percent" protobuf:"bytes,2,opt,name=percent"`
+	// Key, if specified, is an expression that returns the message's key as a string, e.g.
+	// `string(object(msg).customerId)`. Messages are then kept by the hash of their key, so all the messages with a key
+	// are kept, or none of them are, by every replica. Otherwise, messages are kept at random.
+	Key string `json:"key,omitempty" protobuf:"bytes,3,opt,name=key"`
+}
+
+func (m Sample) GetPercent() (float64, error) {
+	v, err := strconv.ParseFloat(m.Percent, 64)
+	if err != nil || v < 0 || v > 100 {
+		return 0, fmt.Errorf("percent %q must be a number between 0 and 100", m.Percent)
+	}
+	return v, nil
+}
+
+func (m Sample) getContainer(req getContainerReq) corev1.Container {
+	return containerBuilder{}.
+		init(req).
+		args("sample", m.Percent, m.Key).
+		resources(m.Resources).
+		build()
+}
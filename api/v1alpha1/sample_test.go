@@ -0,0 +1,30 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSample_GetPercent(t *testing.T) {
+	v, err := Sample{Percent: "0.5"}.GetPercent()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.5, v)
+	_, err = Sample{Percent: "200"}.GetPercent()
+	assert.EqualError(t, err, `percent "200" must be a number between 0 and 100`)
+	_, err = Sample{}.GetPercent()
+	assert.EqualError(t, err, `percent "" must be a number between 0 and 100`)
+}
+
+func TestSample_getContainer(t *testing.T) {
+	x := &Sample{
+		Percent: "10",
+		Key:     "my-key",
+		AbstractStep: AbstractStep{
+			Resources: standardResources,
+		},
+	}
+	c := x.getContainer(getContainerReq{})
+	assert.Equal(t, []string{"sample", "10", "my-key"}, c.Args)
+	assert.Equal(t, c.Resources, standardResources)
+}
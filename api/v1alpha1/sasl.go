@@ -22,6 +22,8 @@ type SASL struct {
 	UserSecret *corev1.SecretKeySelector `json:"userSecret,omitempty" protobuf:"bytes,2,opt,name=user"`
 	// Password for SASL/PLAIN authentication
 	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,3,opt,name=password"`
+	// Username is the authentication identity, used if the user secret is not specified.
+	Username string `json:"username,omitempty" protobuf:"bytes,4,opt,name=username"`
 }
 
 func (s SASL) GetMechanism() SASLMechanism {
@@ -1,5 +1,11 @@
 package v1alpha1
 
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
 type Scale struct {
 	// An expression to determine the number of replicas. Must evaluation to an `int`.
 	DesiredReplicas string `json:"desiredReplicas,omitempty" protobuf:"bytes,1,opt,name=desiredReplicas"`
@@ -9,4 +15,16 @@ type Scale struct {
 	// An expression to determine the delay for scaling. Maybe string or duration, e.g. `"1m"`
 	// +kubebuilder:default="defaultScalingDelay"
 	ScalingDelay string `json:"scalingDelay,omitempty" protobuf:"bytes,3,opt,name=scalingDelay"`
+	// MaxReplicas, if greater than zero, is the most replicas DesiredReplicas can scale the step to.
+	MaxReplicas uint32 `json:"maxReplicas,omitempty" protobuf:"varint,4,opt,name=maxReplicas"`
+	// SlowConsumerDelay is how long the step's pending messages must grow, while it is at MaxReplicas, before it is
+	// marked as a slow consumer, default 10m.
+	SlowConsumerDelay *metav1.Duration `json:"slowConsumerDelay,omitempty" protobuf:"bytes,5,opt,name=slowConsumerDelay"`
+}
+
+func (in Scale) GetSlowConsumerDelay() time.Duration {
+	if in.SlowConsumerDelay != nil {
+		return in.SlowConsumerDelay.Duration
+	}
+	return 10 * time.Minute
 }
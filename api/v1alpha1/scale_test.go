@@ -0,0 +1,14 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestScale_GetSlowConsumerDelay(t *testing.T) {
+	assert.Equal(t, 10*time.Minute, Scale{}.GetSlowConsumerDelay())
+	assert.Equal(t, time.Minute, Scale{SlowConsumerDelay: &metav1.Duration{Duration: time.Minute}}.GetSlowConsumerDelay())
+}
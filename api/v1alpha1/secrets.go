@@ -0,0 +1,182 @@
+package v1alpha1
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+var (
+	secretKeySelectorType = reflect.TypeOf(corev1.SecretKeySelector{})
+	tlsType               = reflect.TypeOf(TLS{})
+)
+
+// getSecretNames returns the names of the secrets the sidecar and init container may need, so they can be mounted
+// rather than read using the Kubernetes API. This includes the secrets used to configure named sources and sinks
+// (e.g. `dataflow-kafka-default`), which may not exist.
+func (in Step) getSecretNames() []string {
+	names := map[string]bool{}
+	for _, s := range in.Spec.Sources {
+		if x := s.Kafka; x != nil {
+			names["dataflow-kafka-"+x.Name] = true
+		} else if x := s.STAN; x != nil {
+			names["dataflow-stan-"+x.Name] = true
+		} else if x := s.S3; x != nil {
+			names["dataflow-s3-"+x.Name] = true
+		} else if x := s.JetStream; x != nil {
+			names["dataflow-jetstream-"+x.Name] = true
+		} else if x := s.ArgoEvents; x != nil {
+			names[x.GetAuthSecretName()] = true
+		} else if x := s.Redis; x != nil {
+			names["dataflow-redis-"+x.Name] = true
+		} else if x := s.RedisStream; x != nil {
+			names["dataflow-redis-"+x.Name] = true
+		} else if x := s.Elasticsearch; x != nil {
+			names["dataflow-elasticsearch-"+x.Name] = true
+		} else if x := s.SQS; x != nil {
+			names["dataflow-sqs-"+x.Name] = true
+		} else if x := s.Kinesis; x != nil {
+			names["dataflow-kinesis-"+x.Name] = true
+		} else if x := s.AMQP; x != nil {
+			names["dataflow-amqp-"+x.Name] = true
+		} else if x := s.MQTT; x != nil {
+			names["dataflow-mqtt-"+x.Name] = true
+		}
+	}
+	for _, s := range in.Spec.Sinks {
+		if x := s.Kafka; x != nil {
+			names["dataflow-kafka-"+x.Name] = true
+		} else if x := s.STAN; x != nil {
+			names["dataflow-stan-"+x.Name] = true
+		} else if x := s.S3; x != nil {
+			names["dataflow-s3-"+x.Name] = true
+		} else if x := s.JetStream; x != nil {
+			names["dataflow-jetstream-"+x.Name] = true
+		} else if x := s.HTTP; x != nil {
+			names["dataflow-http-"+x.Name] = true
+		} else if x := s.Redis; x != nil {
+			names["dataflow-redis-"+x.Name] = true
+		} else if x := s.RedisStream; x != nil {
+			names["dataflow-redis-"+x.Name] = true
+		} else if x := s.Snowflake; x != nil {
+			names["dataflow-snowflake-"+x.Name] = true
+		} else if x := s.File; x != nil && x.OnRotate != nil {
+			names["dataflow-http-"+x.OnRotate.Name] = true
+		} else if x := s.AMQP; x != nil {
+			names["dataflow-amqp-"+x.Name] = true
+		}
+	}
+	for _, s := range in.Spec.Sources {
+		if x := s.Kafka; x != nil && x.Strimzi != nil {
+			names[x.Strimzi.GetCACertSecretName()] = true
+			names[in.GetStrimziUserName(x.Strimzi.Cluster)] = true
+		}
+	}
+	for _, s := range in.Spec.Sinks {
+		if x := s.Kafka; x != nil && x.Strimzi != nil {
+			names[x.Strimzi.GetCACertSecretName()] = true
+			names[in.GetStrimziUserName(x.Strimzi.Cluster)] = true
+		}
+	}
+	if len(in.GetAuthorizationKeys()) > 0 {
+		names[in.Name] = true // created by the controller, contains the HTTP sources' and test sinks' authorization
+	}
+	collectSecretKeySelectors(reflect.ValueOf(in.Spec.Sources), names)
+	collectSecretKeySelectors(reflect.ValueOf(in.Spec.Sinks), names)
+	collectSecretKeySelectors(reflect.ValueOf(in.Spec.Git), names)
+	delete(names, "")
+	var result []string
+	for n := range names {
+		result = append(result, n)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// GetAuthorizationKeys returns the keys of the step's secret, created by the controller, that contain the
+// authorization for its HTTP sources and test sinks.
+func (in Step) GetAuthorizationKeys() []string {
+	var keys []string
+	for _, s := range in.Spec.Sources {
+		keys = append(keys, fmt.Sprintf("sources.%s.http.authorization", s.Name))
+	}
+	for _, s := range in.Spec.Sinks {
+		if s.Test != nil {
+			keys = append(keys, fmt.Sprintf("sinks.%s.test.authorization", s.Name))
+		}
+	}
+	return keys
+}
+
+func collectSecretKeySelectors(v reflect.Value, names map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectSecretKeySelectors(v.Elem(), names)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectSecretKeySelectors(v.Index(i), names)
+		}
+	case reflect.Struct:
+		if v.Type() == secretKeySelectorType {
+			names[v.Interface().(corev1.SecretKeySelector).Name] = true
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			collectSecretKeySelectors(v.Field(i), names)
+		}
+	}
+}
+
+// GetTLSSecretNames returns the names of the secrets containing the certificates and keys used by the step's sources
+// and sinks.
+func (in Step) GetTLSSecretNames() []string {
+	names := map[string]bool{}
+	collectTLSSecretKeySelectors(reflect.ValueOf(in.Spec.Sources), names)
+	collectTLSSecretKeySelectors(reflect.ValueOf(in.Spec.Sinks), names)
+	delete(names, "")
+	var result []string
+	for n := range names {
+		result = append(result, n)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func collectTLSSecretKeySelectors(v reflect.Value, names map[string]bool) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			collectTLSSecretKeySelectors(v.Elem(), names)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectTLSSecretKeySelectors(v.Index(i), names)
+		}
+	case reflect.Struct:
+		if v.Type() == tlsType {
+			collectSecretKeySelectors(v, names)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			collectTLSSecretKeySelectors(v.Field(i), names)
+		}
+	}
+}
+
+// GetSecretMountPath returns where a secret is mounted in the sidecar and init containers.
+func GetSecretMountPath(secretName string) string {
+	return filepath.Join(PathSecrets, secretName)
+}
+
+func secretVolumeName(secretName string) string {
+	// secret names can be longer than volume names, so we use a hash
+	h := sha1.Sum([]byte(secretName))
+	return "secret-" + hex.EncodeToString(h[:])[0:16]
+}
@@ -0,0 +1,34 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestStep_GetTLSSecretNames(t *testing.T) {
+	selector := func(name string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: "my-key"}
+	}
+	step := Step{Spec: StepSpec{
+		Sources: []Source{{Kafka: &KafkaSource{Kafka: Kafka{KafkaConfig: KafkaConfig{NET: &KafkaNET{
+			TLS:  &TLS{CACertSecret: selector("my-ca")},
+			SASL: &SASL{UserSecret: selector("my-user")},
+		}}}}}},
+		Sinks: []Sink{{Kafka: &KafkaSink{Kafka: Kafka{KafkaConfig: KafkaConfig{NET: &KafkaNET{
+			TLS: &TLS{CertSecret: selector("my-cert"), KeySecret: selector("my-cert")},
+		}}}}}},
+	}}
+	assert.Equal(t, []string{"my-ca", "my-cert"}, step.GetTLSSecretNames())
+	assert.Empty(t, Step{}.GetTLSSecretNames())
+}
+
+func TestStep_GetAuthorizationKeys(t *testing.T) {
+	step := Step{Spec: StepSpec{
+		Sources: []Source{{Name: "my-source"}},
+		Sinks:   []Sink{{Name: "my-sink", Test: &TestSink{}}, {Name: "my-log", Log: &Log{}}},
+	}}
+	assert.Equal(t, []string{"sources.my-source.http.authorization", "sinks.my-sink.test.authorization"}, step.GetAuthorizationKeys())
+	assert.Empty(t, Step{}.GetAuthorizationKeys())
+}
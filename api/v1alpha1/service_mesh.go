@@ -0,0 +1,44 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ServiceMesh string
+
+const (
+	ServiceMeshNone  ServiceMesh = ""
+	ServiceMeshIstio ServiceMesh = "istio"
+)
+
+const (
+	CtrIstioProxy = "istio-proxy"
+	// IstioQuitURL is the endpoint that stops the Istio proxy.
+	IstioQuitURL = "http://localhost:15020/quitquitquit"
+)
+
+// GetServiceMeshAnnotations returns the annotations that make the step's pods work with the service mesh:
+//
+// * the main container and sidecar are started only once the proxy is ready,
+// * traffic to brokers (e.g. Kafka, NATS, databases) bypasses the proxy, as their protocols are not HTTP,
+// * the proxy is stopped, rather than killed, when the main container terminates, so the pod can complete.
+func (in Step) GetServiceMeshAnnotations(serviceMesh ServiceMesh) map[string]string {
+	if serviceMesh != ServiceMeshIstio {
+		return nil
+	}
+	annotations := map[string]string{
+		"proxy.istio.io/config":   `{"holdApplicationUntilProxyStarts": true}`,
+		KeyKillCmd(CtrIstioProxy): `["pilot-agent", "request", "POST", "quitquitquit"]`,
+	}
+	var ports []string
+	for _, p := range in.Spec.getEgressPorts() {
+		if p != 80 && p != 443 { // HTTP(S) goes through the mesh
+			ports = append(ports, fmt.Sprint(p))
+		}
+	}
+	if len(ports) > 0 {
+		annotations["traffic.sidecar.istio.io/excludeOutboundPorts"] = strings.Join(ports, ",")
+	}
+	return annotations
+}
@@ -0,0 +1,23 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStep_GetServiceMeshAnnotations(t *testing.T) {
+	s := Step{Spec: StepSpec{
+		Sources: []Source{{Kafka: &KafkaSource{Kafka: Kafka{KafkaConfig: KafkaConfig{Brokers: []string{"kafka:9092"}}}}}},
+		Sinks:   []Sink{{HTTP: &HTTPSink{URL: "http://my-svc"}}},
+	}}
+	t.Run("None", func(t *testing.T) {
+		assert.Nil(t, s.GetServiceMeshAnnotations(ServiceMeshNone))
+	})
+	t.Run("Istio", func(t *testing.T) {
+		annotations := s.GetServiceMeshAnnotations(ServiceMeshIstio)
+		assert.Equal(t, `{"holdApplicationUntilProxyStarts": true}`, annotations["proxy.istio.io/config"])
+		assert.Equal(t, "9092", annotations["traffic.sidecar.istio.io/excludeOutboundPorts"])
+		assert.Equal(t, `["pilot-agent", "request", "POST", "quitquitquit"]`, annotations["dataflow.argoproj.io/kill-cmd.istio-proxy"])
+	})
+}
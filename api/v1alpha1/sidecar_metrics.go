@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type SidecarMetrics struct {
+	// Disabled is a list of metrics that are not exposed, e.g. `sources_totalBytes`.
+	// Disabling `sources_pending` disables scaling based on pending messages.
+	Disabled []string `json:"disabled,omitempty" protobuf:"bytes,1,rep,name=disabled"`
+	// DropLabels is a list of labels removed from all metrics, e.g. `replica`. Series that only differ by a dropped
+	// label are summed.
+	DropLabels []string `json:"dropLabels,omitempty" protobuf:"bytes,2,rep,name=dropLabels"`
+	// Push, if specified, pushes the metrics, as well as exposing them to be scraped, so the metrics of short-lived
+	// steps, e.g. ones that run to completion, are not lost when their pods are deleted before they are scraped.
+	Push *MetricsPush `json:"push,omitempty" protobuf:"bytes,3,opt,name=push"`
+}
+
+// MetricsPush pushes the sidecar's metrics every interval, and when the sidecar stops. Exactly one of Pushgateway or
+// RemoteWrite must be specified. Pushed metrics are labelled with the namespace, pipeline, step and replica.
+type MetricsPush struct {
+	// Pushgateway is the URL of a Prometheus Pushgateway, e.g. "http://pushgateway:9091". Metrics are pushed to the
+	// group of the replica, which is not deleted when the sidecar stops, so the last push remains.
+	Pushgateway string `json:"pushgateway,omitempty" protobuf:"bytes,1,opt,name=pushgateway"`
+	// RemoteWrite is the URL of a Prometheus remote-write endpoint, e.g. "http://prometheus:9090/api/v1/write".
+	RemoteWrite string `json:"remoteWrite,omitempty" protobuf:"bytes,2,opt,name=remoteWrite"`
+	// Interval is how often metrics are pushed.
+	// +kubebuilder:default="15s"
+	Interval *metav1.Duration `json:"interval,omitempty" protobuf:"bytes,3,opt,name=interval"`
+}
+
+func (in MetricsPush) GetInterval() time.Duration {
+	if in.Interval != nil {
+		return in.Interval.Duration
+	}
+	return 15 * time.Second
+}
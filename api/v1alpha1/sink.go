@@ -1,5 +1,7 @@
 package v1alpha1
 
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 type Sink struct {
 	// +kubebuilder:default=default
 	Name            string         `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
@@ -12,4 +14,25 @@ type Sink struct {
 	Volume          *VolumeSink    `json:"volume,omitempty" protobuf:"bytes,8,opt,name=volume"`
 	JetStream       *JetStreamSink `json:"jetstream,omitempty" protobuf:"bytes,9,opt,name=jetstream"`
 	DeadLetterQueue bool           `json:"deadLetterQueue,omitempty" protobuf:"varint,10,opt,name=deadLetterQueue"`
+	// Parallelism is the number of workers writing to this sink. If greater than zero, messages are queued and
+	// written asynchronously, so errors are only reported in the sinks_errors metric, and are not retried.
+	Parallelism uint32 `json:"parallelism,omitempty" protobuf:"varint,11,opt,name=parallelism"`
+	// OrderingKey is an expression that returns a string key for each message. Messages with the same key are written
+	// in order. Only used when parallelism is greater than zero.
+	OrderingKey string `json:"orderingKey,omitempty" protobuf:"bytes,12,opt,name=orderingKey"`
+	// Buffer messages on disk if they cannot be written to the sink, rather than returning them to the source.
+	Buffer      *Buffer          `json:"buffer,omitempty" protobuf:"bytes,13,opt,name=buffer"`
+	CloudEvents *CloudEventsSink `json:"cloudEvents,omitempty" protobuf:"bytes,14,opt,name=cloudEvents"`
+	Dapr        *DaprSink        `json:"dapr,omitempty" protobuf:"bytes,15,opt,name=dapr"`
+	// Timeout is how long to wait for a message to be written to the sink before returning an error. If not
+	// specified, an unresponsive sink blocks the message indefinitely.
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,16,opt,name=timeout"`
+	// Codec, if specified, encodes messages from JSON before they are written.
+	Codec       *Codec           `json:"codec,omitempty" protobuf:"bytes,17,opt,name=codec"`
+	Test        *TestSink        `json:"test,omitempty" protobuf:"bytes,18,opt,name=test"`
+	Redis       *RedisSink       `json:"redis,omitempty" protobuf:"bytes,19,opt,name=redis"`
+	Snowflake   *SnowflakeSink   `json:"snowflake,omitempty" protobuf:"bytes,20,opt,name=snowflake"`
+	File        *FileSink        `json:"file,omitempty" protobuf:"bytes,21,opt,name=file"`
+	AMQP        *AMQPSink        `json:"amqp,omitempty" protobuf:"bytes,22,opt,name=amqp"`
+	RedisStream *RedisStreamSink `json:"redisStream,omitempty" protobuf:"bytes,23,opt,name=redisStream"`
 }
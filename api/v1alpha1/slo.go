@@ -0,0 +1,56 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SLO are the pipeline's service level objectives. The controller evaluates them each time it collects the steps'
+// sidecar metrics, and sets the pipeline's SLOViolated condition, so alerts can be based on the pipeline itself.
+// Objectives that are not specified are always met.
+type SLO struct {
+	// MaxPending is the most pending messages, in total across every step's sources.
+	MaxPending uint64 `json:"maxPending,omitempty" protobuf:"varint,1,opt,name=maxPending"`
+	// MaxLatency is the longest mean time for messages to get through the pipeline, i.e. the sum of the steps' mean
+	// sources_process_latency_seconds since the metrics were last collected. This is the end-to-end latency of a
+	// pipeline whose steps form a line, and more than it otherwise.
+	MaxLatency *metav1.Duration `json:"maxLatency,omitempty" protobuf:"bytes,2,opt,name=maxLatency"`
+	// MaxErrorRate is the highest ratio of source errors to source messages (i.e. sources_errors/sources_total), across
+	// every step, since the metrics were last collected, e.g. "0.01" is 1%.
+	MaxErrorRate string `json:"maxErrorRate,omitempty" protobuf:"bytes,3,opt,name=maxErrorRate"`
+}
+
+// GetMaxLatency returns the longest latency, or zero if latency has no objective.
+func (in SLO) GetMaxLatency() time.Duration {
+	if in.MaxLatency == nil {
+		return 0
+	}
+	return in.MaxLatency.Duration
+}
+
+// GetMaxErrorRate returns the highest error rate, or 1 if the error rate has no objective, as it can be no higher.
+func (in SLO) GetMaxErrorRate() (float64, error) {
+	if in.MaxErrorRate == "" {
+		return 1, nil
+	}
+	v, err := strconv.ParseFloat(in.MaxErrorRate, 64)
+	if err != nil || v < 0 || v > 1 {
+		return 0, fmt.Errorf("maxErrorRate %q must be a number between 0 and 1", in.MaxErrorRate)
+	}
+	return v, nil
+}
+
+// SLOStatus is the pipeline's service level indicators, as last evaluated.
+type SLOStatus struct {
+	// Pending is the total pending messages of every step's sources.
+	Pending uint64 `json:"pending,omitempty" protobuf:"varint,1,opt,name=pending"`
+	// Latency is the sum of the steps' mean latency, unknown if no messages were processed.
+	Latency *metav1.Duration `json:"latency,omitempty" protobuf:"bytes,2,opt,name=latency"`
+	// ErrorRate is the ratio of source errors to source messages, unknown if no messages were processed.
+	ErrorRate string `json:"errorRate,omitempty" protobuf:"bytes,3,opt,name=errorRate"`
+	// Violations are the objectives that are not met, empty if the pipeline is healthy.
+	Violations []string `json:"violations,omitempty" protobuf:"bytes,4,rep,name=violations"`
+}
@@ -0,0 +1,25 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSLO_GetMaxLatency(t *testing.T) {
+	assert.Equal(t, time.Duration(0), SLO{}.GetMaxLatency())
+	assert.Equal(t, time.Minute, SLO{MaxLatency: &metav1.Duration{Duration: time.Minute}}.GetMaxLatency())
+}
+
+func TestSLO_GetMaxErrorRate(t *testing.T) {
+	v, err := SLO{}.GetMaxErrorRate()
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, v)
+	v, err = SLO{MaxErrorRate: "0.01"}.GetMaxErrorRate()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.01, v)
+	_, err = SLO{MaxErrorRate: "x"}.GetMaxErrorRate()
+	assert.EqualError(t, err, `maxErrorRate "x" must be a number between 0 and 1`)
+}
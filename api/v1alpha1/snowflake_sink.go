@@ -0,0 +1,38 @@
+package v1alpha1
+
+import corev1 "k8s.io/api/core/v1"
+
+// SnowflakeSink inserts each message into a Snowflake table, as a row. Messages written at the same time, e.g. because
+// the sink's parallelism is greater than zero, are inserted together, by one statement.
+type SnowflakeSink struct {
+	// Name of the "dataflow-snowflake-{name}" secret, whose "dsn" is used if DSNSecret is not specified.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// DSNSecret is the secret selector to the data source name, e.g.
+	// "my-user:my-password@my-account/my-database/my-schema?warehouse=my-warehouse".
+	DSNSecret *corev1.SecretKeySelector `json:"dsnSecret,omitempty" protobuf:"bytes,2,opt,name=dsnSecret"`
+	// Table is the name of the table, e.g. "events" or "my_database.my_schema.events".
+	Table string `json:"table" protobuf:"bytes,3,opt,name=table"`
+	// Columns are the table's columns to insert, and how to get their values from each message.
+	Columns []SnowflakeColumn `json:"columns" protobuf:"bytes,4,rep,name=columns"`
+	// BatchSize is the most rows inserted by one statement.
+	// +kubebuilder:default=1000
+	BatchSize uint32 `json:"batchSize,omitempty" protobuf:"varint,5,opt,name=batchSize"`
+}
+
+func (in SnowflakeSink) GetBatchSize() int {
+	if in.BatchSize > 0 {
+		return int(in.BatchSize)
+	}
+	return 1000
+}
+
+type SnowflakeColumn struct {
+	// Name of the column.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Value is an expression that returns the column's value, e.g. `object(msg).id`.
+	Value string `json:"value" protobuf:"bytes,2,opt,name=value"`
+	// Variant parses the value as JSON, for VARIANT, OBJECT and ARRAY columns. Values that are not strings are
+	// converted to JSON first, e.g. `object(msg)` inserts the whole message.
+	Variant bool `json:"variant,omitempty" protobuf:"varint,3,opt,name=variant"`
+}
@@ -6,17 +6,47 @@ import (
 
 type Source struct {
 	// +kubebuilder:default=default
-	Name      string           `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
-	Cron      *Cron            `json:"cron,omitempty" protobuf:"bytes,2,opt,name=cron"`
-	STAN      *STAN            `json:"stan,omitempty" protobuf:"bytes,3,opt,name=stan"`
-	Kafka     *KafkaSource     `json:"kafka,omitempty" protobuf:"bytes,4,opt,name=kafka"`
-	HTTP      *HTTPSource      `json:"http,omitempty" protobuf:"bytes,5,opt,name=http"`
-	S3        *S3Source        `json:"s3,omitempty" protobuf:"bytes,8,opt,name=s3"`
-	DB        *DBSource        `json:"db,omitempty" protobuf:"bytes,6,opt,name=db"`
-	Volume    *VolumeSource    `json:"volume,omitempty" protobuf:"bytes,9,opt,name=volume"`
-	JetStream *JetStreamSource `json:"jetstream,omitempty" protobuf:"bytes,10,opt,name=jetstream"`
+	Name       string            `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	Cron       *Cron             `json:"cron,omitempty" protobuf:"bytes,2,opt,name=cron"`
+	STAN       *STAN             `json:"stan,omitempty" protobuf:"bytes,3,opt,name=stan"`
+	Kafka      *KafkaSource      `json:"kafka,omitempty" protobuf:"bytes,4,opt,name=kafka"`
+	HTTP       *HTTPSource       `json:"http,omitempty" protobuf:"bytes,5,opt,name=http"`
+	S3         *S3Source         `json:"s3,omitempty" protobuf:"bytes,8,opt,name=s3"`
+	DB         *DBSource         `json:"db,omitempty" protobuf:"bytes,6,opt,name=db"`
+	Volume     *VolumeSource     `json:"volume,omitempty" protobuf:"bytes,9,opt,name=volume"`
+	JetStream  *JetStreamSource  `json:"jetstream,omitempty" protobuf:"bytes,10,opt,name=jetstream"`
+	ArgoEvents *ArgoEventsSource `json:"argoEvents,omitempty" protobuf:"bytes,11,opt,name=argoEvents"`
+	Dapr       *DaprSource       `json:"dapr,omitempty" protobuf:"bytes,12,opt,name=dapr"`
+	// Codec, if specified, decodes messages into JSON before they are processed.
+	Codec *Codec `json:"codec,omitempty" protobuf:"bytes,13,opt,name=codec"`
+	// Bounded makes the source stop at the end of the messages that existed when it started: a Kafka source's end
+	// offsets, an S3 or volume source's first listing, a generator source's count, or an Elasticsearch source's query.
+	// When all of a step's sources are bounded, the step succeeds once they are drained.
+	Bounded bool `json:"bounded,omitempty" protobuf:"varint,14,opt,name=bounded"`
+	// EventTime, if specified, extracts the time events happened from messages, so the step's watermark and event-time
+	// lag can be monitored.
+	EventTime *EventTime `json:"eventTime,omitempty" protobuf:"bytes,15,opt,name=eventTime"`
 	// +kubebuilder:default={duration: "100ms", steps: 20, factorPercentage: 200, jitterPercentage: 10}
-	Retry Backoff `json:"retry,omitempty" protobuf:"bytes,7,opt,name=retry"`
+	Retry         Backoff              `json:"retry,omitempty" protobuf:"bytes,7,opt,name=retry"`
+	Test          *TestSource          `json:"test,omitempty" protobuf:"bytes,16,opt,name=test"`
+	Generator     *GeneratorSource     `json:"generator,omitempty" protobuf:"bytes,17,opt,name=generator"`
+	Redis         *RedisSource         `json:"redis,omitempty" protobuf:"bytes,18,opt,name=redis"`
+	Elasticsearch *ElasticsearchSource `json:"elasticsearch,omitempty" protobuf:"bytes,19,opt,name=elasticsearch"`
+	SQS           *SQSSource           `json:"sqs,omitempty" protobuf:"bytes,21,opt,name=sqs"`
+	Kinesis       *KinesisSource       `json:"kinesis,omitempty" protobuf:"bytes,22,opt,name=kinesis"`
+	AMQP          *AMQPSource          `json:"amqp,omitempty" protobuf:"bytes,23,opt,name=amqp"`
+	MQTT          *MQTTSource          `json:"mqtt,omitempty" protobuf:"bytes,24,opt,name=mqtt"`
+	RedisStream   *RedisStreamSource   `json:"redisStream,omitempty" protobuf:"bytes,25,opt,name=redisStream"`
+	// Weight of the source, used by the step's merge policy.
+	// +kubebuilder:default=1
+	Weight uint32 `json:"weight,omitempty" protobuf:"varint,20,opt,name=weight"`
+}
+
+func (s Source) GetWeight() int {
+	if s.Weight > 0 {
+		return int(s.Weight)
+	}
+	return 1
 }
 
 func (s Source) get() urner {
@@ -36,6 +66,28 @@ func (s Source) get() urner {
 		return v
 	} else if v := s.JetStream; v != nil {
 		return v
+	} else if v := s.ArgoEvents; v != nil {
+		return v
+	} else if v := s.Dapr; v != nil {
+		return v
+	} else if v := s.Test; v != nil {
+		return v
+	} else if v := s.Generator; v != nil {
+		return v
+	} else if v := s.Redis; v != nil {
+		return v
+	} else if v := s.Elasticsearch; v != nil {
+		return v
+	} else if v := s.SQS; v != nil {
+		return v
+	} else if v := s.Kinesis; v != nil {
+		return v
+	} else if v := s.AMQP; v != nil {
+		return v
+	} else if v := s.MQTT; v != nil {
+		return v
+	} else if v := s.RedisStream; v != nil {
+		return v
 	}
 	panic(fmt.Errorf("invalid source %q", s.Name))
 }
@@ -0,0 +1,30 @@
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+type SourceStatus struct {
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Pending is the number of pending messages, as last reported by the lead replica.
+	Pending uint64 `json:"pending" protobuf:"varint,2,opt,name=pending"`
+	// PartitionPending is the number of pending messages of each partition, e.g. "{topic}-{partition}" for Kafka, as
+	// last reported by the replica the partition is assigned to. A single hot partition shows up here, while it looks
+	// like uniform lag in Pending.
+	PartitionPending map[string]uint64 `json:"partitionPending,omitempty" protobuf:"bytes,3,rep,name=partitionPending"`
+	// PartitionLagSeconds is how far behind the latest record each partition is, in seconds, e.g. by shard ID for
+	// Kinesis, which does not report pending messages, as last reported by the replica the partition is assigned to.
+	PartitionLagSeconds map[string]uint64 `json:"partitionLagSeconds,omitempty" protobuf:"bytes,6,rep,name=partitionLagSeconds"`
+	// Watermark is the latest event time processed, as the earliest of the replicas' watermarks, if the source has
+	// eventTime. Replicas that have not processed a message with an event time are ignored.
+	Watermark *metav1.Time `json:"watermark,omitempty" protobuf:"bytes,4,opt,name=watermark"`
+	// LastError is the most recent error the main container returned for one of the source's messages, from any
+	// replica.
+	LastError *SourceError `json:"lastError,omitempty" protobuf:"bytes,5,opt,name=lastError"`
+}
+
+type SourceError struct {
+	Message string `json:"message" protobuf:"bytes,1,opt,name=message"`
+	// Permanent is true if retrying the message will not help, e.g. because the main container rejected it with an
+	// HTTP 4xx status.
+	Permanent bool        `json:"permanent,omitempty" protobuf:"varint,2,opt,name=permanent"`
+	Time      metav1.Time `json:"time" protobuf:"bytes,3,opt,name=time"`
+}
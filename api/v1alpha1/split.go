@@ -0,0 +1,30 @@
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// Split splits each message into parts, either on a delimiter, or into chunks of a fixed size, so large payloads (e.g.
+// files) can be processed piecewise. Each part is sent to the sinks as a message with the ID "{id}/{index}-of-{count}",
+// where index is zero-based, so parts can be related and ordered downstream. Exactly one of delimiter or chunkSize
+// must be specified.
+type Split struct {
+	AbstractStep `json:",inline" protobuf:"bytes,1,opt,name=abstractStep"`
+	// Delimiter to split messages on, e.g. "\n". Delimiters are not included in parts, and empty parts are dropped.
+	Delimiter string `json:"delimiter,omitempty" protobuf:"bytes,2,opt,name=delimiter"`
+	// ChunkSize is the size of each chunk, e.g. "1Mi". The last chunk may be smaller.
+	ChunkSize *resource.Quantity `json:"chunkSize,omitempty" protobuf:"bytes,3,opt,name=chunkSize"`
+}
+
+func (m Split) getContainer(req getContainerReq) corev1.Container {
+	size := ""
+	if m.ChunkSize != nil {
+		size = m.ChunkSize.String()
+	}
+	return containerBuilder{}.
+		init(req).
+		args("split", m.Delimiter, size).
+		resources(m.Resources).
+		build()
+}
@@ -0,0 +1,28 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func TestSplit_getContainer(t *testing.T) {
+	t.Run("Delimiter", func(t *testing.T) {
+		x := &Split{
+			Delimiter: "\n",
+			AbstractStep: AbstractStep{
+				Resources: standardResources,
+			},
+		}
+		c := x.getContainer(getContainerReq{})
+		assert.Equal(t, []string{"split", "\n", ""}, c.Args)
+		assert.Equal(t, c.Resources, standardResources)
+	})
+	t.Run("ChunkSize", func(t *testing.T) {
+		size := resource.MustParse("1Mi")
+		x := &Split{ChunkSize: &size}
+		c := x.getContainer(getContainerReq{})
+		assert.Equal(t, []string{"split", "", "1Mi"}, c.Args)
+	})
+}
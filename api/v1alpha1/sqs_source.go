@@ -0,0 +1,55 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SQSSource receives messages from an Amazon SQS queue, using long polling. A message is only deleted from the queue
+// once it has been processed, so if it is not processed within its visibility timeout it is received again (or moved
+// to the queue's dead-letter queue, by its redrive policy).
+type SQSSource struct {
+	// Name of the "dataflow-sqs-{name}" secret, whose "queueUrl", "region", credentials, and "endpoint.url" are used if
+	// not specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// QueueURL is the queue's URL, e.g. "https://sqs.us-west-2.amazonaws.com/123456789012/my-queue".
+	QueueURL string `json:"queueUrl,omitempty" protobuf:"bytes,2,opt,name=queueUrl"`
+	// Region is the queue's region, e.g. "us-west-2".
+	Region string `json:"region,omitempty" protobuf:"bytes,3,opt,name=region"`
+	// Credentials, if not specified, are those of the web identity injected by IAM Roles for Service Accounts (IRSA).
+	Credentials *AWSCredentials `json:"credentials,omitempty" protobuf:"bytes,4,opt,name=credentials"`
+	// Endpoint, if specified, is where requests are sent instead of the queue's URL, e.g. for LocalStack.
+	Endpoint *AWSEndpoint `json:"endpoint,omitempty" protobuf:"bytes,5,opt,name=endpoint"`
+	// WaitTime is how long each receive waits for messages to arrive, at most 20s.
+	// +kubebuilder:default="20s"
+	WaitTime *metav1.Duration `json:"waitTime,omitempty" protobuf:"bytes,6,opt,name=waitTime"`
+	// VisibilityTimeout is how long received messages are hidden from other receivers. It should be longer than it takes
+	// to process a batch, otherwise messages may be received, and processed, more than once. If not specified, the
+	// queue's visibility timeout is used.
+	VisibilityTimeout *metav1.Duration `json:"visibilityTimeout,omitempty" protobuf:"bytes,7,opt,name=visibilityTimeout"`
+	// MaxMessages is the most messages each receive returns, between 1 and 10.
+	// +kubebuilder:default=10
+	MaxMessages uint32 `json:"maxMessages,omitempty" protobuf:"varint,8,opt,name=maxMessages"`
+}
+
+func (in SQSSource) GetWaitTime() time.Duration {
+	if in.WaitTime != nil {
+		return in.WaitTime.Duration
+	}
+	return 20 * time.Second
+}
+
+func (in SQSSource) GetMaxMessages() uint32 {
+	if in.MaxMessages > 0 {
+		return in.MaxMessages
+	}
+	return 10
+}
+
+func (in SQSSource) GenURN(cluster, namespace string) string {
+	// a queue's URL includes its account and region, so it is unique
+	return fmt.Sprintf("urn:dataflow:sqs:%s", in.QueueURL)
+}
@@ -0,0 +1,19 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestSQSSource(t *testing.T) {
+	x := SQSSource{QueueURL: "https://sqs.us-west-2.amazonaws.com/123456789012/my-queue"}
+	assert.Equal(t, "urn:dataflow:sqs:https://sqs.us-west-2.amazonaws.com/123456789012/my-queue", x.GenURN(cluster, namespace))
+	assert.Equal(t, 20*time.Second, x.GetWaitTime())
+	assert.Equal(t, uint32(10), x.GetMaxMessages())
+	x = SQSSource{WaitTime: &metav1.Duration{}, MaxMessages: 1}
+	assert.Equal(t, time.Duration(0), x.GetWaitTime())
+	assert.Equal(t, uint32(1), x.GetMaxMessages())
+}
@@ -2,8 +2,10 @@ package v1alpha1
 
 import (
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 type NATSAuthStrategy string
@@ -26,6 +28,61 @@ type STAN struct {
 	// between commits, therefore potential duplicates during disruption
 	// +kubebuilder:default=20
 	MaxInflight uint32 `json:"maxInflight,omitempty" protobuf:"bytes,9,opt,name=maxInflight"`
+	// AckWait is how long the server waits for a source to ack a message, before re-delivering it.
+	// +kubebuilder:default="30s"
+	AckWait *metav1.Duration `json:"ackWait,omitempty" protobuf:"bytes,10,opt,name=ackWait"`
+	// MaxRedeliveries is how many times a source's message is re-delivered, before it is sent to the dead letter
+	// queue instead of being processed. Zero means there is no limit.
+	MaxRedeliveries uint32 `json:"maxRedeliveries,omitempty" protobuf:"varint,11,opt,name=maxRedeliveries"`
+	// Reconnect configures how a lost connection is detected, and re-established.
+	Reconnect *STANReconnect `json:"reconnect,omitempty" protobuf:"bytes,12,opt,name=reconnect"`
+}
+
+type STANReconnect struct {
+	// PingInterval is how often the server is pinged.
+	// +kubebuilder:default="5s"
+	PingInterval *metav1.Duration `json:"pingInterval,omitempty" protobuf:"bytes,1,opt,name=pingInterval"`
+	// PingMaxOut is how many pings can be unanswered, before the connection is considered lost.
+	// +kubebuilder:default=60
+	PingMaxOut uint32 `json:"pingMaxOut,omitempty" protobuf:"varint,2,opt,name=pingMaxOut"`
+	// Backoff is how long to wait before the first attempt to reconnect. It doubles after each failed attempt, up to
+	// MaxBackoff.
+	// +kubebuilder:default="1s"
+	Backoff *metav1.Duration `json:"backoff,omitempty" protobuf:"bytes,3,opt,name=backoff"`
+	// +kubebuilder:default="1m"
+	MaxBackoff *metav1.Duration `json:"maxBackoff,omitempty" protobuf:"bytes,4,opt,name=maxBackoff"`
+}
+
+// GetPingInterval returns the ping interval in whole seconds, at least one.
+func (in STANReconnect) GetPingInterval() int {
+	if in.PingInterval == nil {
+		return 5
+	}
+	if s := int(in.PingInterval.Seconds()); s >= 1 {
+		return s
+	}
+	return 1
+}
+
+func (in STANReconnect) GetPingMaxOut() int {
+	if in.PingMaxOut < 1 {
+		return 60
+	}
+	return int(in.PingMaxOut)
+}
+
+func (in STANReconnect) GetBackoff() time.Duration {
+	if in.Backoff == nil {
+		return time.Second
+	}
+	return in.Backoff.Duration
+}
+
+func (in STANReconnect) GetMaxBackoff() time.Duration {
+	if in.MaxBackoff == nil {
+		return time.Minute
+	}
+	return in.MaxBackoff.Duration
 }
 
 func (s STAN) GenURN(cluster, namespace string) string {
@@ -45,6 +102,20 @@ func (s *STAN) AuthStrategy() NATSAuthStrategy {
 	return NATSAuthNone
 }
 
+func (s *STAN) GetAckWait() time.Duration {
+	if s.AckWait == nil {
+		return 30 * time.Second
+	}
+	return s.AckWait.Duration
+}
+
+func (s *STAN) GetReconnect() STANReconnect {
+	if s.Reconnect == nil {
+		return STANReconnect{}
+	}
+	return *s.Reconnect
+}
+
 func (s *STAN) GetMaxInflight() int {
 	if s.MaxInflight < 1 {
 		return CommitN
@@ -2,11 +2,36 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestSTAN_GenURN(t *testing.T) {
 	urn := STAN{NATSURL: "my-url", Subject: "my-subject"}.GenURN(cluster, namespace)
 	assert.Equal(t, "urn:dataflow:stan:my-url:my-subject", urn)
 }
+
+func TestSTAN_GetAckWait(t *testing.T) {
+	assert.Equal(t, 30*time.Second, (&STAN{}).GetAckWait())
+	assert.Equal(t, time.Minute, (&STAN{AckWait: &metav1.Duration{Duration: time.Minute}}).GetAckWait())
+}
+
+func TestSTAN_GetReconnect(t *testing.T) {
+	r := (&STAN{}).GetReconnect()
+	assert.Equal(t, 5, r.GetPingInterval())
+	assert.Equal(t, 60, r.GetPingMaxOut())
+	assert.Equal(t, time.Second, r.GetBackoff())
+	assert.Equal(t, time.Minute, r.GetMaxBackoff())
+	r = (&STAN{Reconnect: &STANReconnect{
+		PingInterval: &metav1.Duration{Duration: 100 * time.Millisecond},
+		PingMaxOut:   2,
+		Backoff:      &metav1.Duration{Duration: 2 * time.Second},
+		MaxBackoff:   &metav1.Duration{Duration: time.Hour},
+	}}).GetReconnect()
+	assert.Equal(t, 1, r.GetPingInterval())
+	assert.Equal(t, 2, r.GetPingMaxOut())
+	assert.Equal(t, 2*time.Second, r.GetBackoff())
+	assert.Equal(t, time.Hour, r.GetMaxBackoff())
+}
@@ -0,0 +1,95 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+)
+
+// State is where a step's stateful built-in processor, i.e. dedupe or join, keeps its state. At most one backend may
+// be specified. Without one, state is kept in memory, and is lost when the container restarts.
+type State struct {
+	// Memory keeps state in memory. This is the default.
+	Memory *MemoryState `json:"memory,omitempty" protobuf:"bytes,1,opt,name=memory"`
+	// Disk keeps state in an embedded database on a volume, e.g. a persistent volume claim, so it survives restarts.
+	// The database can only be opened by one replica at a time.
+	Disk *DiskState `json:"disk,omitempty" protobuf:"bytes,2,opt,name=disk"`
+	// Redis keeps state in Redis, so it survives restarts, and is shared by the step's replicas.
+	Redis *RedisState `json:"redis,omitempty" protobuf:"bytes,3,opt,name=redis"`
+	// TTL is how long state is kept after it was last written, e.g. how long dedupe remembers a message's UID.
+	// +kubebuilder:default="24h"
+	TTL *metav1.Duration `json:"ttl,omitempty" protobuf:"bytes,4,opt,name=ttl"`
+	// CheckpointInterval, if specified, keeps state in memory, and checkpoints it to the disk or Redis store every
+	// interval, rather than on every change. The positions of sources that only keep them in memory, e.g. a generator's,
+	// are checkpointed too. Both are restored when the step restarts, so a crash loses at most one interval of progress.
+	CheckpointInterval *metav1.Duration `json:"checkpointInterval,omitempty" protobuf:"bytes,5,opt,name=checkpointInterval"`
+}
+
+type MemoryState struct{}
+
+type DiskState struct {
+	// Volume to keep the database on, e.g. a persistent volume claim.
+	Volume AbstractVolumeSource `json:"volume" protobuf:"bytes,1,opt,name=volume"`
+}
+
+type RedisState struct {
+	// Name of the "dataflow-redis-{name}" secret, whose "url" and "password" are used if not specified here.
+	// +kubebuilder:default=default
+	Name string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
+	// URL of the Redis server, e.g. "redis://redis:6379/0".
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+	// PasswordSecret is the secret selector to the server's password.
+	PasswordSecret *corev1.SecretKeySelector `json:"passwordSecret,omitempty" protobuf:"bytes,3,opt,name=passwordSecret"`
+}
+
+func (in *State) GetTTL() time.Duration {
+	if in != nil && in.TTL != nil {
+		return in.TTL.Duration
+	}
+	return 24 * time.Hour
+}
+
+// GetCheckpointInterval returns the checkpoint interval, or zero if state is not checkpointed.
+func (in *State) GetCheckpointInterval() time.Duration {
+	if in != nil && in.CheckpointInterval != nil {
+		return in.CheckpointInterval.Duration
+	}
+	return 0
+}
+
+// IsPersistent returns true if the state survives restarts.
+func (in *State) IsPersistent() bool {
+	return in != nil && (in.Disk != nil || in.Redis != nil)
+}
+
+// getEnv returns the environment variables the stores are configured from. Keys in shared stores are prefixed with
+// the namespace and step, so steps do not see each others state.
+func (in *State) getEnv(namespace, stepName string) []corev1.EnvVar {
+	if in == nil {
+		return nil
+	}
+	data, _ := json.Marshal(in)
+	env := []corev1.EnvVar{
+		{Name: EnvState, Value: string(data)},
+		{Name: EnvStateKeyPrefix, Value: "dataflow:" + namespace + ":" + stepName + ":"},
+	}
+	if x := in.Redis; x != nil {
+		secretName := "dataflow-redis-" + StringOr(x.Name, "default")
+		if x.URL == "" {
+			env = append(env, corev1.EnvVar{Name: EnvStateRedisURL, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  "url",
+				Optional:             pointer.BoolPtr(true),
+			}}})
+		}
+		password := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secretName}, Key: "password", Optional: pointer.BoolPtr(true)}
+		if x.PasswordSecret != nil {
+			password = x.PasswordSecret
+		}
+		env = append(env, corev1.EnvVar{Name: EnvStateRedisPassword, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: password}})
+	}
+	return env
+}
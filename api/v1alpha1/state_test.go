@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestState_GetTTL(t *testing.T) {
+	assert.Equal(t, 24*time.Hour, (*State)(nil).GetTTL())
+	assert.Equal(t, time.Hour, (&State{TTL: &metav1.Duration{Duration: time.Hour}}).GetTTL())
+}
+
+func TestState_GetCheckpointInterval(t *testing.T) {
+	assert.Zero(t, (*State)(nil).GetCheckpointInterval())
+	assert.Equal(t, time.Second, (&State{CheckpointInterval: &metav1.Duration{Duration: time.Second}}).GetCheckpointInterval())
+}
+
+func TestState_IsPersistent(t *testing.T) {
+	assert.False(t, (*State)(nil).IsPersistent())
+	assert.False(t, (&State{Memory: &MemoryState{}}).IsPersistent())
+	assert.True(t, (&State{Redis: &RedisState{}}).IsPersistent())
+}
+
+func TestState_getEnv(t *testing.T) {
+	assert.Empty(t, (*State)(nil).getEnv("my-ns", "my-step"))
+	t.Run("Redis", func(t *testing.T) {
+		env := (&State{Redis: &RedisState{Name: "my-redis"}}).getEnv("my-ns", "my-step")
+		if assert.Len(t, env, 4) {
+			assert.Equal(t, corev1.EnvVar{Name: EnvState, Value: `{"redis":{"name":"my-redis"}}`}, env[0])
+			assert.Equal(t, "dataflow:my-ns:my-step:", env[1].Value)
+			assert.Equal(t, EnvStateRedisURL, env[2].Name)
+			assert.Equal(t, "dataflow-redis-my-redis", env[2].ValueFrom.SecretKeyRef.Name)
+			assert.Equal(t, EnvStateRedisPassword, env[3].Name)
+			assert.Equal(t, "password", env[3].ValueFrom.SecretKeyRef.Key)
+		}
+	})
+	t.Run("RedisURL", func(t *testing.T) {
+		env := (&State{Redis: &RedisState{URL: "redis://redis"}}).getEnv("my-ns", "my-step")
+		assert.Len(t, env, 3)
+	})
+}
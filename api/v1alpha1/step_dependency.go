@@ -0,0 +1,18 @@
+package v1alpha1
+
+// DefaultStepDependencyCondition is true when the step has succeeded, or all of its replicas are ready.
+const DefaultStepDependencyCondition = `phase == "Succeeded" || phase == "Running" && readyReplicas > 0 && readyReplicas >= replicas`
+
+// StepDependency is a step, in the same pipeline, that must be ready before this step's pods are created.
+type StepDependency struct {
+	// Name is the name of the step.
+	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	// Condition is an expression that is true when the step is ready. It can use `phase`, `replicas`,
+	// `readyReplicas` and `step` (the whole step object). Defaults to the step having succeeded, or all of its
+	// replicas being ready.
+	Condition string `json:"condition,omitempty" protobuf:"bytes,2,opt,name=condition"`
+}
+
+func (in StepDependency) GetCondition() string {
+	return StringOr(in.Condition, DefaultStepDependencyCondition)
+}
@@ -15,8 +15,9 @@ func (m StepPhaseMessage) GetReason() string {
 	return strings.Split(string(m), "/")[1]
 }
 
+// GetMessage returns the message, which may itself contain "/", e.g. if it is an error with a URL.
 func (m StepPhaseMessage) GetMessage() string {
-	return strings.Split(string(m), "/")[2]
+	return strings.SplitN(string(m), "/", 3)[2]
 }
 
 func NewStepPhaseMessage(phase StepPhase, reason, message string) StepPhaseMessage {
@@ -12,3 +12,10 @@ func TestMinStepPhaseMessage(t *testing.T) {
 	assert.Equal(t, "baz", x.GetReason())
 	assert.Equal(t, "foo", x.GetMessage())
 }
+
+func TestStepPhaseMessage_GetMessage(t *testing.T) {
+	x := NewStepPhaseMessage(StepPending, "RetryingConnection", "dial tcp: lookup http://kafka-broker/")
+	assert.Equal(t, StepPending, x.GetPhase())
+	assert.Equal(t, "RetryingConnection", x.GetReason())
+	assert.Equal(t, "dial tcp: lookup http://kafka-broker/", x.GetMessage())
+}
@@ -1,7 +1,16 @@
 package v1alpha1
 
 import (
+	"fmt"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	minUpdateInterval = time.Second
+	maxUpdateInterval = 10 * time.Minute
 )
 
 type StepSpec struct {
@@ -18,6 +27,14 @@ type StepSpec struct {
 	Group     *Group     `json:"group,omitempty" protobuf:"bytes,11,opt,name=group"`
 	Code      *Code      `json:"code,omitempty" protobuf:"bytes,7,opt,name=code"`
 	Map       *Map       `json:"map,omitempty" protobuf:"bytes,9,opt,name=map"`
+	Sample    *Sample    `json:"sample,omitempty" protobuf:"bytes,42,opt,name=sample"`
+	Split     *Split     `json:"split,omitempty" protobuf:"bytes,43,opt,name=split"`
+	// Passthrough routes messages from sources to sinks without a main container.
+	Passthrough *Passthrough `json:"passthrough,omitempty" protobuf:"bytes,29,opt,name=passthrough"`
+	// Join correlates messages from two sources by key without a main container.
+	Join *Join `json:"join,omitempty" protobuf:"bytes,44,opt,name=join"`
+	// State is where a dedupe or join step keeps its state, by default in memory.
+	State *State `json:"state,omitempty" protobuf:"bytes,45,opt,name=state"`
 
 	// +kubebuilder:default=1
 	Replicas uint32 `json:"replicas,omitempty" protobuf:"varint,23,opt,name=replicas"`
@@ -50,6 +67,48 @@ type StepSpec struct {
 	// +patchStrategy=merge
 	// +patchMergeKey=name
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,20,opt,name=imagePullSecrets"`
+	// UpdateInterval is how often the sidecar updates metrics that need a call to a remote service, such as pending
+	// messages. Must be between 1s and 10m. Defaults to the controller's ARGO_DATAFLOW_UPDATE_INTERVAL.
+	UpdateInterval *metav1.Duration `json:"updateInterval,omitempty" protobuf:"bytes,30,opt,name=updateInterval"`
+	// WorkloadIdentity allows sources, sinks, and the main container to authenticate using the cloud provider
+	// identity bound to the service account, rather than static keys.
+	WorkloadIdentity *WorkloadIdentity `json:"workloadIdentity,omitempty" protobuf:"bytes,31,opt,name=workloadIdentity"`
+	// Rollout is how the step's pods are updated when its spec changes.
+	Rollout *Rollout `json:"rollout,omitempty" protobuf:"bytes,32,opt,name=rollout"`
+	// Containers are additional containers run in each of the step's pods, alongside the main container, such as a
+	// cache or a model server. They share the pod's network, so they can be reached on localhost, and one of them may
+	// serve the main container's in interface (see `container.in.http.container`). They are killed when the main
+	// container exits.
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	Containers []corev1.Container `json:"containers,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,33,rep,name=containers"`
+	// DependsOn are steps that must be ready before this step's pods are created, e.g. because they populate a cache
+	// this step uses. Pods that already exist are not deleted if a dependency stops being ready.
+	// +patchStrategy=merge
+	// +patchMergeKey=name
+	DependsOn []StepDependency `json:"dependsOn,omitempty" patchStrategy:"merge" patchMergeKey:"name" protobuf:"bytes,34,rep,name=dependsOn"`
+	// Completion is when the step runs to completion, e.g. after a number of messages, rather than forever.
+	Completion *Completion `json:"completion,omitempty" protobuf:"bytes,35,opt,name=completion"`
+	// BackoffLimit is the number of times failed pods are re-created before the step fails. It is only used with
+	// restartPolicy Never, where failed pods are otherwise left as they are.
+	BackoffLimit *int32 `json:"backoffLimit,omitempty" protobuf:"varint,36,opt,name=backoffLimit"`
+	// Audit, if specified, writes a metadata record of every message the step processes to one of its sinks.
+	Audit *Audit `json:"audit,omitempty" protobuf:"bytes,37,opt,name=audit"`
+	// Runner, if specified, overrides the runner image and its pull policy.
+	Runner *Runner `json:"runner,omitempty" protobuf:"bytes,38,opt,name=runner"`
+	// Merge, if specified, governs how messages from the step's sources are interleaved into the main container. If
+	// not specified, each source sends messages to the main container as soon as it receives them, in no particular
+	// order.
+	Merge *Merge `json:"merge,omitempty" protobuf:"bytes,39,opt,name=merge"`
+	// Quota is the pipeline's quota, which the step's sidecars respect collectively with those of the pipeline's other
+	// steps. It is set from PipelineSpec.Quota, rather than specified on a step.
+	Quota *Quota `json:"quota,omitempty" protobuf:"bytes,40,opt,name=quota"`
+	// WaitForBrokers, if specified, makes the init container wait until the step's brokers are reachable.
+	WaitForBrokers *WaitForBrokers `json:"waitForBrokers,omitempty" protobuf:"bytes,41,opt,name=waitForBrokers"`
+	// Lifecycle, if specified, are commands run in the main container as it starts and before it stops.
+	Lifecycle *Lifecycle `json:"lifecycle,omitempty" protobuf:"bytes,46,opt,name=lifecycle"`
+	// Recommendations, if specified, makes the controller recommend the containers' requests from their usage.
+	Recommendations *Recommendations `json:"recommendations,omitempty" protobuf:"bytes,47,opt,name=recommendations"`
 }
 
 func (in StepSpec) GetIn() *Interface {
@@ -59,6 +118,82 @@ func (in StepSpec) GetIn() *Interface {
 	return DefaultInterface
 }
 
+// GetInPort returns the port that the step's HTTP in interface is served on.
+func (in StepSpec) GetInPort() int32 {
+	x := in.GetIn().HTTP
+	if x == nil {
+		return defaultInPort
+	}
+	if x.Port > 0 {
+		return x.Port
+	}
+	for _, c := range in.Containers {
+		if c.Name == x.Container && len(c.Ports) > 0 {
+			return c.Ports[0].ContainerPort
+		}
+	}
+	return defaultInPort
+}
+
+// GetUpdateInterval returns the step's update interval, or the default value if not specified.
+func (in StepSpec) GetUpdateInterval(defaultValue time.Duration) (time.Duration, error) {
+	if in.UpdateInterval == nil {
+		return defaultValue, nil
+	}
+	if v := in.UpdateInterval.Duration; v < minUpdateInterval || v > maxUpdateInterval {
+		return 0, fmt.Errorf("updateInterval %v must be between %v and %v", v, minUpdateInterval, maxUpdateInterval)
+	} else {
+		return v, nil
+	}
+}
+
+// IsBounded returns true if the step has sources, and they are all bounded, so the step completes once they are drained.
+func (in StepSpec) IsBounded() bool {
+	for _, s := range in.Sources {
+		if !s.Bounded {
+			return false
+		}
+	}
+	return len(in.Sources) > 0
+}
+
+// HasEventTime returns true if any of the step's sources extracts event times, so it has watermarks.
+func (in StepSpec) HasEventTime() bool {
+	for _, s := range in.Sources {
+		if s.EventTime != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// GetLateness returns how the step's window handles late messages, or nil if it does not have one.
+func (in StepSpec) GetLateness() *Lateness {
+	if in.Join != nil {
+		return in.Join.Lateness
+	}
+	return nil
+}
+
+// GetBackoffLimit returns the number of times failed pods are re-created, zero if not specified.
+func (in StepSpec) GetBackoffLimit() int32 {
+	if in.BackoffLimit == nil {
+		return 0
+	}
+	return *in.BackoffLimit
+}
+
+// CanComplete returns true if the step runs to completion, because its sources are bounded, or it has completion
+// criteria. Its sidecar exits once it is complete.
+func (in StepSpec) CanComplete() bool {
+	return in.IsBounded() || in.Completion != nil
+}
+
+// HasMainContainer returns false if the step does not run a main container, i.e. it is a passthrough or join step.
+func (in StepSpec) HasMainContainer() bool {
+	return in.Passthrough == nil && in.Join == nil
+}
+
 func (in StepSpec) getType() containerSupplier {
 	if x := in.Cat; x != nil {
 		return x
@@ -80,13 +215,72 @@ func (in StepSpec) getType() containerSupplier {
 		return x
 	} else if x := in.Map; x != nil {
 		return x
+	} else if x := in.Sample; x != nil {
+		return x
+	} else if x := in.Split; x != nil {
+		return x
 	} else {
 		panic("invalid step spec")
 	}
 }
 
+// getAbstractStep returns the step's built-in type's abstract step, or nil if it does not have one.
+func (in *StepSpec) getAbstractStep() *AbstractStep {
+	if x := in.Cat; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Dedupe; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Expand; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Filter; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Flatten; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Map; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Sample; x != nil {
+		return &x.AbstractStep
+	} else if x := in.Split; x != nil {
+		return &x.AbstractStep
+	}
+	return nil
+}
+
 func (in StepSpec) WithOutReplicas() StepSpec {
 	x := *in.DeepCopy()
 	x.Replicas = 0
 	return x
 }
+
+// WithOutRollout returns the spec without its rollout, which does not change the pods.
+func (in StepSpec) WithOutRollout() StepSpec {
+	x := *in.DeepCopy()
+	x.Rollout = nil
+	return x
+}
+
+// WithOutLiveFields returns the spec without the fields that are applied without re-creating the pods: its scale and
+// recommendations, which only the controller uses, and its sources' retry and filter or map expression, which the
+// sidecar watches for.
+func (in StepSpec) WithOutLiveFields() StepSpec {
+	x := *in.DeepCopy()
+	x.Scale = Scale{}
+	x.Recommendations = nil
+	if x.Filter != nil {
+		x.Filter.Expression = ""
+	}
+	if x.Map != nil {
+		x.Map.Expression = ""
+	}
+	for i := range x.Sources {
+		x.Sources[i].Retry = Backoff{}
+	}
+	return x
+}
+
+// WithOutDependsOn returns the spec without its dependencies, which do not change the pods.
+func (in StepSpec) WithOutDependsOn() StepSpec {
+	x := *in.DeepCopy()
+	x.DependsOn = nil
+	return x
+}
@@ -2,8 +2,11 @@ package v1alpha1
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func TestStepSpec_WithOutReplicas(t *testing.T) {
@@ -11,3 +14,65 @@ func TestStepSpec_WithOutReplicas(t *testing.T) {
 	assert.Zero(t, in.Replicas)
 	assert.Equal(t, "foo", in.Name)
 }
+
+func TestStepSpec_WithOutLiveFields(t *testing.T) {
+	in := StepSpec{Name: "foo", Scale: Scale{MaxReplicas: 1}, Sources: []Source{{Name: "a", Retry: Backoff{Steps: 3}}}}
+	out := in.WithOutLiveFields()
+	assert.Equal(t, Scale{}, out.Scale)
+	assert.Equal(t, Backoff{}, out.Sources[0].Retry)
+	assert.Equal(t, "a", out.Sources[0].Name)
+	assert.Equal(t, uint64(3), in.Sources[0].Retry.Steps, "the original is unchanged")
+	t.Run("Expression", func(t *testing.T) {
+		in := StepSpec{Filter: &Filter{Expression: "true"}}
+		assert.Equal(t, "", in.WithOutLiveFields().Filter.Expression)
+		in = StepSpec{Map: &Map{Expression: "msg"}}
+		assert.Equal(t, "", in.WithOutLiveFields().Map.Expression)
+		assert.Equal(t, "msg", in.Map.Expression, "the original is unchanged")
+	})
+}
+
+func TestStepSpec_HasMainContainer(t *testing.T) {
+	assert.True(t, StepSpec{Cat: &Cat{}}.HasMainContainer())
+	assert.False(t, StepSpec{Passthrough: &Passthrough{}}.HasMainContainer())
+}
+
+func TestStepSpec_IsBounded(t *testing.T) {
+	assert.False(t, StepSpec{}.IsBounded())
+	assert.True(t, StepSpec{Sources: Sources{{Bounded: true}, {Bounded: true}}}.IsBounded())
+	assert.False(t, StepSpec{Sources: Sources{{Bounded: true}, {}}}.IsBounded())
+}
+
+func TestStepSpec_CanComplete(t *testing.T) {
+	assert.False(t, StepSpec{}.CanComplete())
+	assert.True(t, StepSpec{Sources: Sources{{Bounded: true}}}.CanComplete())
+	assert.True(t, StepSpec{Completion: &Completion{Messages: 1}}.CanComplete())
+}
+
+func TestStepSpec_GetUpdateInterval(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		v, err := StepSpec{}.GetUpdateInterval(15 * time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, 15*time.Second, v)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		v, err := StepSpec{UpdateInterval: &metav1.Duration{Duration: time.Minute}}.GetUpdateInterval(15 * time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, time.Minute, v)
+	})
+	t.Run("TooShort", func(t *testing.T) {
+		_, err := StepSpec{UpdateInterval: &metav1.Duration{Duration: time.Millisecond}}.GetUpdateInterval(15 * time.Second)
+		assert.Error(t, err)
+	})
+	t.Run("TooLong", func(t *testing.T) {
+		_, err := StepSpec{UpdateInterval: &metav1.Duration{Duration: time.Hour}}.GetUpdateInterval(15 * time.Second)
+		assert.Error(t, err)
+	})
+}
+
+func TestStepSpec_GetInPort(t *testing.T) {
+	assert.Equal(t, int32(8080), StepSpec{}.GetInPort())
+	assert.Equal(t, int32(8080), StepSpec{Container: &Container{In: &Interface{FIFO: true}}}.GetInPort())
+	assert.Equal(t, int32(9090), StepSpec{Container: &Container{In: &Interface{HTTP: &HTTP{Port: 9090}}}}.GetInPort())
+	containers := []corev1.Container{{Name: "model-server", Ports: []corev1.ContainerPort{{ContainerPort: 5000}}}}
+	assert.Equal(t, int32(5000), StepSpec{Container: &Container{In: &Interface{HTTP: &HTTP{Container: "model-server"}}}, Containers: containers}.GetInPort())
+}
@@ -11,6 +11,26 @@ type StepStatus struct {
 	Replicas     uint32      `json:"replicas" protobuf:"varint,3,opt,name=replicas"`
 	Selector     string      `json:"selector,omitempty" protobuf:"bytes,5,opt,name=selector"`
 	LastScaledAt metav1.Time `json:"lastScaledAt,omitempty" protobuf:"bytes,4,opt,name=lastScaledAt"`
+	// Rollout is the status of the step's canary rollout, if it has one.
+	Rollout *RolloutStatus `json:"rollout,omitempty" protobuf:"bytes,7,opt,name=rollout"`
+	// OffsetReset is the status of the last reset of the step's sources, see ResetOffset.
+	OffsetReset *ResetStatus `json:"offsetReset,omitempty" protobuf:"bytes,8,opt,name=offsetReset"`
+	// Sources is the pending messages, watermarks, and last errors of the step's sources, collected from the replicas'
+	// metrics.
+	Sources []SourceStatus `json:"sources,omitempty" protobuf:"bytes,9,rep,name=sources"`
+	// Failures is the number of the step's failed pods that have been re-created, see BackoffLimit.
+	Failures uint32 `json:"failures,omitempty" protobuf:"varint,10,opt,name=failures"`
+	// ObservedGeneration is the generation of the spec that the controller last reconciled. If it is less than the
+	// step's generation, the controller has not yet acted on the latest change to the spec.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,11,opt,name=observedGeneration"`
+	// Conditions, e.g. SlowConsumer if the step cannot keep up with its sources.
+	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,12,rep,name=conditions"`
+	// SourceOffsets is the committed offset of each database source whose offsetStore is Status, by source name, and the
+	// committed sequence number of each Kinesis source's shards, by "{sourceName}/{shardId}". The sidecar updates it,
+	// and resumes from it when it restarts.
+	SourceOffsets map[string]string `json:"sourceOffsets,omitempty" protobuf:"bytes,13,rep,name=sourceOffsets"`
+	// Recommendations are the containers' observed usage and recommended requests, if the step has recommendations.
+	Recommendations *RecommendationsStatus `json:"recommendations,omitempty" protobuf:"bytes,14,opt,name=recommendations"`
 }
 
 func (m StepStatus) GetReplicas() int {
@@ -96,6 +96,55 @@ func (in Step) GetPodSpec(req GetPodSpecReq) corev1.PodSpec {
 			})
 		}
 	}
+	for _, sink := range in.Spec.Sinks {
+		if x := sink.Buffer; x != nil && x.Volume != nil {
+			name := fmt.Sprintf("buffer-%s", sink.Name)
+			volumes = append(volumes, corev1.Volume{
+				Name:         name,
+				VolumeSource: corev1.VolumeSource(*x.Volume),
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      name,
+				MountPath: filepath.Join(PathVarRun, "buffers", sink.Name),
+			})
+		}
+		if x := sink.File; x != nil {
+			name := fmt.Sprintf("files-%s", sink.Name)
+			volumes = append(volumes, corev1.Volume{
+				Name:         name,
+				VolumeSource: corev1.VolumeSource(x.Volume),
+			})
+			volumeMounts = append(volumeMounts, corev1.VolumeMount{
+				Name:      name,
+				MountPath: filepath.Join(PathVarRun, "files", sink.Name),
+			})
+		}
+	}
+	if x := in.Spec.State; x != nil && x.Disk != nil {
+		volumes = append(volumes, corev1.Volume{
+			Name:         "state",
+			VolumeSource: corev1.VolumeSource(x.Disk.Volume),
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "state",
+			MountPath: PathState,
+		})
+	}
+	// secrets are only mounted in the sidecar and init containers, not the main container
+	var secretMounts []corev1.VolumeMount
+	for _, name := range in.getSecretNames() {
+		volumes = append(volumes, corev1.Volume{
+			Name: secretVolumeName(name),
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{SecretName: name, Optional: pointer.BoolPtr(true)},
+			},
+		})
+		secretMounts = append(secretMounts, corev1.VolumeMount{
+			Name:      secretVolumeName(name),
+			ReadOnly:  true,
+			MountPath: GetSecretMountPath(name),
+		})
+	}
 	step, _ := json.Marshal(in.withoutManagedFields())
 	envVars := []corev1.EnvVar{
 		{Name: EnvCluster, Value: req.Cluster},
@@ -107,8 +156,13 @@ func (in Step) GetPodSpec(req GetPodSpecReq) corev1.PodSpec {
 		{Name: EnvUpdateInterval, Value: req.UpdateInterval.String()},
 		{Name: "GODEBUG", Value: os.Getenv("GODEBUG")},
 	}
+	if req.FaultInjection {
+		envVars = append(envVars, corev1.EnvVar{Name: EnvFaultInjection, Value: "true"})
+	}
+	stateEnv := in.Spec.State.getEnv(in.Namespace, in.Name)
+	envVars = append(envVars, stateEnv...)
 
-	for _, n := range []string{EnvDebug, EnvUnixDomainSocket} {
+	for _, n := range []string{EnvDebug, EnvUnixDomainSocket, EnvVaultAddr, EnvVaultRole, EnvVaultAuthPath, EnvVaultPath, EnvServiceMesh} {
 		if value, ok := os.LookupEnv(n); ok {
 			envVars = append(envVars, corev1.EnvVar{Name: n, Value: value})
 		}
@@ -128,24 +182,96 @@ func (in Step) GetPodSpec(req GetPodSpecReq) corev1.PodSpec {
 		},
 		AllowPrivilegeEscalation: pointer.BoolPtr(false),
 	}
+	podSecurityContext := &corev1.PodSecurityContext{
+		RunAsNonRoot: pointer.BoolPtr(true),
+		RunAsUser:    pointer.Int64Ptr(9653),
+	}
+	if in.Spec.WorkloadIdentity != nil {
+		// the projected token must be readable by the non-root user
+		podSecurityContext.FSGroup = pointer.Int64Ptr(9653)
+	}
+	nodeSelector := in.Spec.NodeSelector
+	if x := in.Spec.WorkloadIdentity.getNodeSelector(); x != nil {
+		nodeSelector = map[string]string{}
+		for k, v := range in.Spec.NodeSelector {
+			nodeSelector[k] = v
+		}
+		for k, v := range x {
+			nodeSelector[k] = v
+		}
+	}
+	if req.Restricted {
+		dropAll.Capabilities.Drop = []corev1.Capability{"ALL"} // the Pod Security Standard is case-sensitive
+		dropAll.RunAsNonRoot = pointer.BoolPtr(true)
+		podSecurityContext.SeccompProfile = &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault}
+	}
 	priorityClassName := ""
 	if req.Replica == 0 {
 		priorityClassName = "lead-replica"
 	}
+	containers := []corev1.Container{
+		{
+			Name:            CtrSidecar,
+			Image:           req.RunnerImage,
+			ImagePullPolicy: req.PullPolicy,
+			Args:            []string{"sidecar"},
+			Env:             envVars,
+			VolumeMounts:    append(append([]corev1.VolumeMount{}, volumeMounts...), secretMounts...),
+			Resources:       req.Sidecar.Resources,
+			Ports: []corev1.ContainerPort{
+				{ContainerPort: 3570},
+			},
+			ReadinessProbe: &corev1.Probe{
+				Handler: corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{Scheme: "HTTPS", Path: "/ready", Port: intstr.FromInt(3570)},
+				},
+			},
+			Lifecycle: &corev1.Lifecycle{
+				PreStop: &corev1.Handler{
+					HTTPGet: &corev1.HTTPGetAction{
+						Path:   "/pre-stop?source=kubernetes",
+						Port:   intstr.FromInt(3570),
+						Scheme: "HTTPS",
+					},
+				},
+			},
+			SecurityContext: dropAll,
+		},
+	}
+	if in.Spec.HasMainContainer() {
+		var mainEnv []corev1.EnvVar
+		if in.Spec.Dedupe != nil {
+			mainEnv = stateEnv // the dedupe container runs the runner image, so it may have the state store's secrets
+		}
+		containers = append(containers, in.Spec.getType().getContainer(getContainerReq{
+			env:             mainEnv,
+			imageFormat:     req.ImageFormat,
+			imagePullPolicy: req.PullPolicy,
+			lifecycle:       in.Spec.getMainLifecycle(),
+			runnerImage:     req.RunnerImage,
+			securityContext: dropAll,
+			volumeMounts:    volumeMounts,
+		}))
+	}
+	for _, c := range in.Spec.Containers {
+		// the shared volume has the kill binary, which is used to stop the container
+		c.VolumeMounts = append(append([]corev1.VolumeMount{}, c.VolumeMounts...), corev1.VolumeMount{Name: varVolumeName, MountPath: PathVarRun})
+		if req.Restricted {
+			c.SecurityContext = restrictSecurityContext(c.SecurityContext)
+		}
+		containers = append(containers, c)
+	}
 	return corev1.PodSpec{
 		Hostname:           req.Hostname,
 		Subdomain:          req.Subdomain,
 		Volumes:            append(in.Spec.Volumes, volumes...),
 		RestartPolicy:      in.Spec.RestartPolicy,
-		NodeSelector:       in.Spec.NodeSelector,
+		NodeSelector:       nodeSelector,
 		ServiceAccountName: in.Spec.ServiceAccountName,
-		SecurityContext: &corev1.PodSecurityContext{
-			RunAsNonRoot: pointer.BoolPtr(true),
-			RunAsUser:    pointer.Int64Ptr(9653),
-		},
-		PriorityClassName: priorityClassName,
-		Affinity:          in.Spec.Affinity,
-		Tolerations:       in.Spec.Tolerations,
+		SecurityContext:    podSecurityContext,
+		PriorityClassName:  priorityClassName,
+		Affinity:           in.Spec.Affinity,
+		Tolerations:        in.Spec.Tolerations,
 		InitContainers: []corev1.Container{
 			{
 				Name:            CtrInit,
@@ -153,59 +279,17 @@ func (in Step) GetPodSpec(req GetPodSpecReq) corev1.PodSpec {
 				ImagePullPolicy: req.PullPolicy,
 				Args:            []string{"init"},
 				Env:             envVars,
-				VolumeMounts: append(volumeMounts, corev1.VolumeMount{
+				VolumeMounts: append(append(append([]corev1.VolumeMount{}, volumeMounts...), corev1.VolumeMount{
 					Name:      sshVolumeName,
 					ReadOnly:  true,
 					MountPath: "/.ssh",
-				}),
-				Resources:       standardResources,
+				}), secretMounts...),
+				Resources:       req.getInitResources(),
 				SecurityContext: dropAll,
 			},
 		},
 		ImagePullSecrets: req.ImagePullSecrets,
-		Containers: []corev1.Container{
-			{
-				Name:            CtrSidecar,
-				Image:           req.RunnerImage,
-				ImagePullPolicy: req.PullPolicy,
-				Args:            []string{"sidecar"},
-				Env:             envVars,
-				VolumeMounts:    volumeMounts,
-				Resources:       req.Sidecar.Resources,
-				Ports: []corev1.ContainerPort{
-					{ContainerPort: 3570},
-				},
-				ReadinessProbe: &corev1.Probe{
-					Handler: corev1.Handler{
-						HTTPGet: &corev1.HTTPGetAction{Scheme: "HTTPS", Path: "/ready", Port: intstr.FromInt(3570)},
-					},
-				},
-				Lifecycle: &corev1.Lifecycle{
-					PreStop: &corev1.Handler{
-						HTTPGet: &corev1.HTTPGetAction{
-							Path:   "/pre-stop?source=kubernetes",
-							Port:   intstr.FromInt(3570),
-							Scheme: "HTTPS",
-						},
-					},
-				},
-				SecurityContext: dropAll,
-			},
-			in.Spec.getType().getContainer(getContainerReq{
-				imageFormat:     req.ImageFormat,
-				imagePullPolicy: req.PullPolicy,
-				lifecycle: &corev1.Lifecycle{
-					PreStop: &corev1.Handler{
-						Exec: &corev1.ExecAction{
-							Command: []string{PathPreStop},
-						},
-					},
-				},
-				runnerImage:     req.RunnerImage,
-				securityContext: dropAll,
-				volumeMounts:    volumeMounts,
-			}),
-		},
+		Containers:       containers,
 	}
 }
 
@@ -213,6 +297,18 @@ func (in Step) GetHeadlessServiceName() string {
 	return "step-" + in.Name
 }
 
+// GetObjectLabels returns the labels of the objects the controller creates for the step: the step's own labels, e.g.
+// those of its pipeline's metadata, and its pipeline and step names.
+func (in Step) GetObjectLabels(pipelineName string) map[string]string {
+	x := map[string]string{}
+	for k, v := range in.Labels {
+		x[k] = v
+	}
+	x[KeyStepName] = in.Spec.Name
+	x[KeyPipelineName] = pipelineName
+	return x
+}
+
 func (in Step) GetServiceObj(serviceName, pipelineName string, isHeadless bool) *corev1.Service {
 	svc := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -220,10 +316,7 @@ func (in Step) GetServiceObj(serviceName, pipelineName string, isHeadless bool)
 			Name:            serviceName,
 			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(in.GetObjectMeta(), StepGroupVersionKind)},
 			// useful for auto-detecting the service as exporting Prometheus
-			Labels: map[string]string{
-				KeyStepName:     in.Spec.Name,
-				KeyPipelineName: pipelineName,
-			},
+			Labels: in.GetObjectLabels(pipelineName),
 		},
 		Spec: corev1.ServiceSpec{
 			Ports: []corev1.ServicePort{
@@ -248,6 +341,11 @@ func (in Step) withoutManagedFields() Step {
 	return y
 }
 
+// GetRevisionName returns the name of the ControllerRevision that stores the step's spec with the hash.
+func (in Step) GetRevisionName(hash string) string {
+	return fmt.Sprintf("%s-%.10s", in.Name, hash)
+}
+
 // +kubebuilder:object:root=true
 
 type StepList struct {
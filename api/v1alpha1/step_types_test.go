@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/utils/pointer"
 )
@@ -148,6 +149,40 @@ func TestStep_GetPodSpec(t *testing.T) {
 	}
 }
 
+func TestStep_GetPodSpec_Passthrough(t *testing.T) {
+	step := Step{Spec: StepSpec{Name: "main", Passthrough: &Passthrough{}}}
+	spec := step.GetPodSpec(GetPodSpecReq{RunnerImage: "my-runner"})
+	if assert.Len(t, spec.Containers, 1) {
+		assert.Equal(t, CtrSidecar, spec.Containers[0].Name)
+	}
+	assert.Len(t, spec.InitContainers, 1)
+}
+
+func TestStep_GetPodSpec_Containers(t *testing.T) {
+	step := Step{Spec: StepSpec{Name: "main", Container: &Container{Image: "my-image"}, Containers: []corev1.Container{{Name: "redis", Image: "redis"}}}}
+	spec := step.GetPodSpec(GetPodSpecReq{RunnerImage: "my-runner"})
+	if assert.Len(t, spec.Containers, 3) {
+		c := spec.Containers[2]
+		assert.Equal(t, "redis", c.Name)
+		assert.Equal(t, []corev1.VolumeMount{{Name: "var-run-argo-dataflow", MountPath: PathVarRun}}, c.VolumeMounts)
+	}
+	assert.Empty(t, step.Spec.Containers[0].VolumeMounts, "the step is not modified")
+}
+
+func TestStep_GetPodSpec_FileSink(t *testing.T) {
+	volume := AbstractVolumeSource{PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "my-pvc"}}
+	step := Step{Spec: StepSpec{Name: "main", Cat: &Cat{}, Sinks: []Sink{{Name: "files", File: &FileSink{Volume: volume}}}}}
+	spec := step.GetPodSpec(GetPodSpecReq{RunnerImage: "my-runner"})
+	assert.Contains(t, spec.Volumes, corev1.Volume{Name: "files-files", VolumeSource: corev1.VolumeSource(volume)})
+	assert.Contains(t, spec.Containers[0].VolumeMounts, corev1.VolumeMount{Name: "files-files", MountPath: "/var/run/argo-dataflow/files/files"})
+}
+
+func TestStep_GetObjectLabels(t *testing.T) {
+	step := Step{ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{KeyTenant: "my-team", KeyStepName: "other"}}, Spec: StepSpec{Name: "main"}}
+	assert.Equal(t, map[string]string{KeyTenant: "my-team", KeyStepName: "main", KeyPipelineName: "my-pl"}, step.GetObjectLabels("my-pl"))
+	assert.Equal(t, "other", step.Labels[KeyStepName], "step is not modified")
+}
+
 func TestStep_GetServiceObj(t *testing.T) {
 	step := Step{
 		Spec: StepSpec{
@@ -168,3 +203,34 @@ func TestStep_GetServiceObj(t *testing.T) {
 		assert.Equal(t, int32(443), obj.Spec.Ports[0].Port)
 	})
 }
+
+func TestStep_GetPodSpec_Secrets(t *testing.T) {
+	step := Step{
+		Spec: StepSpec{
+			Name:    "main",
+			Cat:     &Cat{},
+			Sources: []Source{{Name: "default", Kafka: &KafkaSource{Kafka: Kafka{Name: "default"}}}},
+			Sinks: []Sink{{HTTP: &HTTPSink{Name: "default", Headers: []HTTPHeader{{
+				Name: "Authorization",
+				ValueFrom: &HTTPHeaderSource{SecretKeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+					Key:                  "my-key",
+				}},
+			}}}}},
+		},
+	}
+	step.Name = "my-pl-main"
+	assert.Equal(t, []string{"dataflow-http-default", "dataflow-kafka-default", "my-pl-main", "my-secret"}, step.getSecretNames())
+	spec := step.GetPodSpec(GetPodSpecReq{})
+	hasSecretMount := func(c corev1.Container) bool {
+		for _, m := range c.VolumeMounts {
+			if m.MountPath == "/var/run/argo-dataflow/secrets/my-secret" {
+				return true
+			}
+		}
+		return false
+	}
+	assert.True(t, hasSecretMount(spec.InitContainers[0]))
+	assert.True(t, hasSecretMount(spec.Containers[0]))
+	assert.False(t, hasSecretMount(spec.Containers[1]))
+}
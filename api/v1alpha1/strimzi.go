@@ -0,0 +1,168 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	KafkaTopicGroupVersionKind = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaTopic"}
+	KafkaUserGroupVersionKind  = schema.GroupVersionKind{Group: "kafka.strimzi.io", Version: "v1beta2", Kind: "KafkaUser"}
+	invalidNameChars           = regexp.MustCompile(`[^a-z0-9.-]`)
+)
+
+// Strimzi has the controller create the topic, and a user that can only access the step's topics, using the
+// Strimzi operators. The sidecar connects to the cluster's TLS listener as that user.
+// https://strimzi.io/docs/operators/latest/using.html#assembly-using-the-topic-operator-str
+type Strimzi struct {
+	// Cluster is the name of the Strimzi `Kafka`, which must be in the pipeline's namespace.
+	Cluster string `json:"cluster" protobuf:"bytes,1,opt,name=cluster"`
+	// Partitions is the number of partitions the topic is created with.
+	// +kubebuilder:default=1
+	Partitions int32 `json:"partitions,omitempty" protobuf:"varint,2,opt,name=partitions"`
+	// Replicas is the replication factor the topic is created with.
+	// +kubebuilder:default=1
+	Replicas int32 `json:"replicas,omitempty" protobuf:"varint,3,opt,name=replicas"`
+}
+
+func (s Strimzi) GetPartitions() int32 {
+	if s.Partitions < 1 {
+		return 1
+	}
+	return s.Partitions
+}
+
+func (s Strimzi) GetReplicas() int32 {
+	if s.Replicas < 1 {
+		return 1
+	}
+	return s.Replicas
+}
+
+// GetBootstrapServer returns the address of the cluster's TLS listener.
+func (s Strimzi) GetBootstrapServer() string {
+	return fmt.Sprintf("%s-kafka-bootstrap:9093", s.Cluster)
+}
+
+// GetCACertSecretName returns the name of the secret that contains the cluster's CA certificate, in "ca.crt".
+func (s Strimzi) GetCACertSecretName() string {
+	return s.Cluster + "-cluster-ca-cert"
+}
+
+// GetStrimziUserName returns the name of the step's KafkaUser for the cluster. Strimzi creates a secret with the same
+// name, that contains the user's certificate and key, in "user.crt" and "user.key".
+func (in Step) GetStrimziUserName(cluster string) string {
+	return in.Name + "-" + cluster
+}
+
+func getKafkaTopicName(topic string) string {
+	name := invalidNameChars.ReplaceAllString(strings.ToLower(topic), "-")
+	if name != topic {
+		// different topics could have the same name
+		name = name + "-" + sharedutil.MustHash(topic)[0:8]
+	}
+	return name
+}
+
+type strimziACL struct {
+	resourceType, name, patternType string
+	operations                      []string
+}
+
+// GetStrimziObjs returns the KafkaTopic and KafkaUser resources for the step's Strimzi sources and sinks. Topics are
+// not owned by the step, so they (and their messages) are not deleted with the pipeline.
+func (in Step) GetStrimziObjs(cluster string) (topics, users []*unstructured.Unstructured) {
+	acls := map[string][]strimziACL{} // Strimzi cluster -> ACLs
+	strimzis := map[string]Strimzi{}  // topic -> Strimzi
+	for _, s := range in.Spec.Sources {
+		if x := s.Kafka; x != nil && x.Strimzi != nil {
+			groupID := x.GetGroupID(sharedutil.GetSourceUID(cluster, in.Namespace, in.GetLabels()[KeyPipelineName], in.Spec.Name, s.Name))
+			for _, topic := range x.GetTopics() {
+				if IsTopicPattern(topic) {
+					continue // Strimzi cannot create, or authorize, the topics a pattern matches
+				}
+				acls[x.Strimzi.Cluster] = append(acls[x.Strimzi.Cluster], strimziACL{"topic", topic, "literal", []string{"Read", "Describe"}})
+				strimzis[topic] = *x.Strimzi
+			}
+			acls[x.Strimzi.Cluster] = append(acls[x.Strimzi.Cluster], strimziACL{"group", groupID, "literal", []string{"Read", "Describe"}})
+			if x.Transactional {
+				acls[x.Strimzi.Cluster] = append(acls[x.Strimzi.Cluster],
+					strimziACL{"transactionalId", groupID + "/", "prefix", []string{"Write", "Describe"}},
+				)
+			}
+		}
+	}
+	for _, s := range in.Spec.Sinks {
+		if x := s.Kafka; x != nil && x.Strimzi != nil {
+			acls[x.Strimzi.Cluster] = append(acls[x.Strimzi.Cluster],
+				strimziACL{"topic", x.Topic, "literal", []string{"Write", "Describe"}},
+				strimziACL{"cluster", "", "", []string{"IdempotentWrite"}},
+			)
+			strimzis[x.Topic] = *x.Strimzi
+		}
+	}
+	for topic, s := range strimzis {
+		topics = append(topics, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": KafkaTopicGroupVersionKind.GroupVersion().String(),
+			"kind":       KafkaTopicGroupVersionKind.Kind,
+			"metadata": map[string]interface{}{
+				"namespace": in.Namespace,
+				"name":      getKafkaTopicName(topic),
+				"labels":    map[string]interface{}{"strimzi.io/cluster": s.Cluster},
+			},
+			"spec": map[string]interface{}{
+				"topicName":  topic,
+				"partitions": int64(s.GetPartitions()),
+				"replicas":   int64(s.GetReplicas()),
+			},
+		}})
+	}
+	for strimziCluster, x := range acls {
+		var items []interface{}
+		for _, acl := range x {
+			resource := map[string]interface{}{"type": acl.resourceType}
+			if acl.resourceType != "cluster" {
+				resource["name"] = acl.name
+				resource["patternType"] = acl.patternType
+			}
+			var operations []interface{}
+			for _, o := range acl.operations {
+				operations = append(operations, o)
+			}
+			items = append(items, map[string]interface{}{"resource": resource, "operations": operations})
+		}
+		controllerRef := metav1.NewControllerRef(in.GetObjectMeta(), StepGroupVersionKind)
+		users = append(users, &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": KafkaUserGroupVersionKind.GroupVersion().String(),
+			"kind":       KafkaUserGroupVersionKind.Kind,
+			"metadata": map[string]interface{}{
+				"namespace": in.Namespace,
+				"name":      in.GetStrimziUserName(strimziCluster),
+				"labels":    map[string]interface{}{"strimzi.io/cluster": strimziCluster},
+				"ownerReferences": []interface{}{map[string]interface{}{
+					"apiVersion":         controllerRef.APIVersion,
+					"kind":               controllerRef.Kind,
+					"name":               controllerRef.Name,
+					"uid":                string(controllerRef.UID),
+					"controller":         true,
+					"blockOwnerDeletion": true,
+				}},
+			},
+			"spec": map[string]interface{}{
+				"authentication": map[string]interface{}{"type": "tls"},
+				"authorization":  map[string]interface{}{"type": "simple", "acls": items},
+			},
+		}})
+	}
+	// so that the result is deterministic
+	sort.Slice(topics, func(i, j int) bool { return topics[i].GetName() < topics[j].GetName() })
+	sort.Slice(users, func(i, j int) bool { return users[i].GetName() < users[j].GetName() })
+	return topics, users
+}
@@ -0,0 +1,67 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func Test_getKafkaTopicName(t *testing.T) {
+	assert.Equal(t, "my-topic", getKafkaTopicName("my-topic"))
+	assert.Equal(t, "my-topic-36e68ddf", getKafkaTopicName("My_Topic"))
+}
+
+func TestStep_GetStrimziObjs(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		topics, users := Step{}.GetStrimziObjs(cluster)
+		assert.Empty(t, topics)
+		assert.Empty(t, users)
+	})
+	strimzi := &Strimzi{Cluster: "my-kafka", Partitions: 3}
+	step := Step{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "my-pl-my-step", Labels: map[string]string{KeyPipelineName: "my-pl"}},
+		Spec: StepSpec{
+			Name:    "my-step",
+			Sources: []Source{{Name: "my-source", Kafka: &KafkaSource{Kafka: Kafka{Topic: "my-input", Strimzi: strimzi}, GroupID: "my-group"}}},
+			Sinks:   []Sink{{Kafka: &KafkaSink{Kafka: Kafka{Topic: "my-output", Strimzi: strimzi}}}},
+		},
+	}
+	topics, users := step.GetStrimziObjs(cluster)
+	if assert.Len(t, topics, 2) {
+		x := topics[0]
+		assert.Equal(t, "KafkaTopic", x.GetKind())
+		assert.Equal(t, "my-input", x.GetName())
+		assert.Equal(t, "my-kafka", x.GetLabels()["strimzi.io/cluster"])
+		assert.Empty(t, x.GetOwnerReferences())
+		partitions, _, _ := unstructured.NestedInt64(x.Object, "spec", "partitions")
+		assert.Equal(t, int64(3), partitions)
+	}
+	if assert.Len(t, users, 1) {
+		x := users[0]
+		assert.Equal(t, "KafkaUser", x.GetKind())
+		assert.Equal(t, "my-pl-my-step-my-kafka", x.GetName())
+		assert.Len(t, x.GetOwnerReferences(), 1)
+		acls, _, _ := unstructured.NestedSlice(x.Object, "spec", "authorization", "acls")
+		assert.Len(t, acls, 4)
+		assert.Equal(t, map[string]interface{}{
+			"resource":   map[string]interface{}{"type": "group", "name": "my-group", "patternType": "literal"},
+			"operations": []interface{}{"Read", "Describe"},
+		}, acls[1])
+	}
+	t.Run("Transactional", func(t *testing.T) {
+		step := step.DeepCopy()
+		step.Spec.Sources[0].Kafka.Transactional = true
+		_, users := step.GetStrimziObjs(cluster)
+		acls, _, _ := unstructured.NestedSlice(users[0].Object, "spec", "authorization", "acls")
+		assert.Len(t, acls, 5)
+		assert.Equal(t, map[string]interface{}{
+			"resource":   map[string]interface{}{"type": "transactionalId", "name": "my-group/", "patternType": "prefix"},
+			"operations": []interface{}{"Write", "Describe"},
+		}, acls[2])
+	})
+	assert.Contains(t, step.getSecretNames(), "my-kafka-cluster-ca-cert")
+	assert.Contains(t, step.getSecretNames(), "my-pl-my-step-my-kafka")
+	assert.Contains(t, step.Spec.getEgressPorts(), int32(9093))
+}
@@ -0,0 +1,16 @@
+package v1alpha1
+
+// TestSink keeps the messages written to it in memory, so they can be inspected by GETting the sidecar's
+// `/test/sinks/{name}` endpoint.
+type TestSink struct {
+	// Capacity is the most messages kept. Once it is reached, the oldest messages are dropped.
+	// +kubebuilder:default=1000
+	Capacity uint32 `json:"capacity,omitempty" protobuf:"varint,1,opt,name=capacity"`
+}
+
+func (t TestSink) GetCapacity() int {
+	if t.Capacity > 0 {
+		return int(t.Capacity)
+	}
+	return 1000
+}
@@ -0,0 +1,23 @@
+package v1alpha1
+
+import "fmt"
+
+// TestSource reads messages from an in-memory queue, for testing pipelines without deploying a message broker (e.g.
+// in a kind cluster). Messages are added to the queue by POSTing them to the sidecar's `/test/sources/{name}`
+// endpoint, and are lost if the pod is deleted.
+type TestSource struct {
+	// Capacity is the most messages the queue holds. Once it is full, messages are rejected until it has room.
+	// +kubebuilder:default=1000
+	Capacity uint32 `json:"capacity,omitempty" protobuf:"varint,1,opt,name=capacity"`
+}
+
+func (t TestSource) GetCapacity() int {
+	if t.Capacity > 0 {
+		return int(t.Capacity)
+	}
+	return 1000
+}
+
+func (t TestSource) GenURN(cluster, namespace string) string {
+	return fmt.Sprintf("urn:dataflow:test:%s:%s", cluster, namespace)
+}
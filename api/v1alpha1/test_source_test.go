@@ -0,0 +1,21 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestSource_GetCapacity(t *testing.T) {
+	assert.Equal(t, 1000, TestSource{}.GetCapacity())
+	assert.Equal(t, 2, TestSource{Capacity: 2}.GetCapacity())
+}
+
+func TestTestSource_GenURN(t *testing.T) {
+	assert.Equal(t, "urn:dataflow:test:my-cluster:my-ns", Source{Test: &TestSource{}}.GenURN("my-cluster", "my-ns"))
+}
+
+func TestTestSink_GetCapacity(t *testing.T) {
+	assert.Equal(t, 1000, TestSink{}.GetCapacity())
+	assert.Equal(t, 2, TestSink{Capacity: 2}.GetCapacity())
+}
@@ -0,0 +1,29 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Upgrade makes the pipeline the new version of another pipeline (i.e. a blue/green upgrade). The pipelines run
+// alongside each other, each with its own consumer groups, and this pipeline's status compares their outputs. When
+// promoted, this pipeline takes over the replaced pipeline's Kafka consumer groups, and the replaced pipeline is deleted.
+type Upgrade struct {
+	// Replaces is the name of the pipeline, in the same namespace, this pipeline is the new version of.
+	Replaces string `json:"replaces" protobuf:"bytes,1,opt,name=replaces"`
+	// MaxDeviation is the largest difference, between the two versions of a step, in the ratio of messages written to
+	// its sinks to messages read from its sources, for the step to be at parity, e.g. "0.01".
+	// +kubebuilder:default="0.01"
+	MaxDeviation string `json:"maxDeviation,omitempty" protobuf:"bytes,2,opt,name=maxDeviation"`
+}
+
+func (m Upgrade) GetMaxDeviation() (float64, error) {
+	if m.MaxDeviation == "" {
+		return 0.01, nil
+	}
+	v, err := strconv.ParseFloat(m.MaxDeviation, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("maxDeviation %q must be a number greater than or equal to 0", m.MaxDeviation)
+	}
+	return v, nil
+}
@@ -0,0 +1,18 @@
+package v1alpha1
+
+type UpgradeStatus struct {
+	// Parity is true if every step is at parity with the replaced pipeline's step of the same name.
+	Parity  bool         `json:"parity" protobuf:"varint,1,opt,name=parity"`
+	Message string       `json:"message,omitempty" protobuf:"bytes,2,opt,name=message"`
+	Steps   []StepParity `json:"steps,omitempty" protobuf:"bytes,3,rep,name=steps"`
+}
+
+// StepParity compares the ratio of messages written to a step's sinks to messages read from its sources, to the same
+// ratio of the replaced pipeline's step.
+type StepParity struct {
+	Name          string `json:"name" protobuf:"bytes,1,opt,name=name"`
+	Ratio         string `json:"ratio,omitempty" protobuf:"bytes,2,opt,name=ratio"`
+	ReplacedRatio string `json:"replacedRatio,omitempty" protobuf:"bytes,3,opt,name=replacedRatio"`
+	Parity        bool   `json:"parity" protobuf:"varint,4,opt,name=parity"`
+	Message       string `json:"message,omitempty" protobuf:"bytes,5,opt,name=message"`
+}
@@ -0,0 +1,18 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgrade_GetMaxDeviation(t *testing.T) {
+	v, err := Upgrade{}.GetMaxDeviation()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.01, v)
+	v, err = Upgrade{MaxDeviation: "0.1"}.GetMaxDeviation()
+	assert.NoError(t, err)
+	assert.Equal(t, 0.1, v)
+	_, err = Upgrade{MaxDeviation: "-1"}.GetMaxDeviation()
+	assert.EqualError(t, err, `maxDeviation "-1" must be a number greater than or equal to 0`)
+}
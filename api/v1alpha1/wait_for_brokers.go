@@ -0,0 +1,127 @@
+package v1alpha1
+
+import (
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WaitForBrokers makes the init container wait until it can connect to the step's brokers before the sidecar and main
+// containers start. While a cluster is being brought up, the step is then pending, with reason "WaitingForBrokers",
+// rather than crash looping.
+type WaitForBrokers struct {
+	// Timeout is how long to wait before the init container fails, default 5m.
+	Timeout *metav1.Duration `json:"timeout,omitempty" protobuf:"bytes,1,opt,name=timeout"`
+	// Addresses are other "host:port" addresses to wait for, e.g. of a database.
+	Addresses []string `json:"addresses,omitempty" protobuf:"bytes,2,rep,name=addresses"`
+}
+
+func (in WaitForBrokers) GetTimeout() time.Duration {
+	if in.Timeout != nil {
+		return in.Timeout.Duration
+	}
+	return 5 * time.Minute
+}
+
+// GetBrokerAddresses returns the "host:port" addresses of the step's brokers, i.e. of its Kafka, NATS, Redis, AMQP,
+// MQTT and Elasticsearch sources and sinks, and the additional addresses to wait for. Addresses that are only known at
+// runtime, e.g. from a secret, must be set before calling this.
+func (in StepSpec) GetBrokerAddresses() []string {
+	var addresses []string
+	add := func(address string, defaultPort int32) {
+		if x := hostPortOf(address, defaultPort); x != "" {
+			for _, y := range addresses {
+				if x == y {
+					return
+				}
+			}
+			addresses = append(addresses, x)
+		}
+	}
+	addKafka := func(x Kafka) {
+		for _, b := range x.Brokers {
+			add(b, 9092)
+		}
+		if s := x.Strimzi; s != nil && len(x.Brokers) == 0 {
+			add(s.GetBootstrapServer(), 9093)
+		} else if e := x.EventHubs; e != nil && len(x.Brokers) == 0 {
+			add(e.GetBootstrapServer(), 9093)
+		}
+	}
+	for _, s := range in.Sources {
+		if x := s.Kafka; x != nil {
+			addKafka(x.Kafka)
+		} else if x := s.STAN; x != nil {
+			add(x.NATSURL, 4222)
+		} else if x := s.JetStream; x != nil {
+			add(x.NATSURL, 4222)
+		} else if x := s.ArgoEvents; x != nil {
+			add(x.GetNATSURL(), 4222)
+		} else if x := s.Redis; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.RedisStream; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.Elasticsearch; x != nil {
+			add(x.URL, 9200)
+		} else if x := s.AMQP; x != nil {
+			add(x.URL, x.getDefaultPort())
+		} else if x := s.MQTT; x != nil {
+			add(x.URL, x.getDefaultPort())
+		}
+	}
+	for _, s := range in.Sinks {
+		if x := s.Kafka; x != nil {
+			addKafka(x.Kafka)
+		} else if x := s.STAN; x != nil {
+			add(x.NATSURL, 4222)
+		} else if x := s.JetStream; x != nil {
+			add(x.NATSURL, 4222)
+		} else if x := s.Redis; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.RedisStream; x != nil {
+			add(x.URL, 6379)
+		} else if x := s.AMQP; x != nil {
+			add(x.URL, x.getDefaultPort())
+		}
+	}
+	if w := in.WaitForBrokers; w != nil {
+		for _, a := range w.Addresses {
+			add(a, 0)
+		}
+	}
+	return addresses
+}
+
+// hostPortOf returns the "host:port" of a "host:port" address or a URL, or "" if it has no host, or no port can be
+// determined.
+func hostPortOf(address string, defaultPort int32) string {
+	host, port := address, ""
+	if strings.Contains(address, "://") {
+		u, err := url.Parse(address)
+		if err != nil {
+			return ""
+		}
+		host, port = u.Hostname(), u.Port()
+		if port == "" {
+			switch u.Scheme {
+			case "http":
+				port = "80"
+			case "https":
+				port = "443"
+			}
+		}
+	} else if h, p, err := net.SplitHostPort(address); err == nil {
+		host, port = h, p
+	}
+	if port == "" && defaultPort > 0 {
+		port = strconv.Itoa(int(defaultPort))
+	}
+	if host == "" || port == "" {
+		return ""
+	}
+	return net.JoinHostPort(host, port)
+}
@@ -0,0 +1,45 @@
+package v1alpha1
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestWaitForBrokers_GetTimeout(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, WaitForBrokers{}.GetTimeout())
+	assert.Equal(t, time.Minute, WaitForBrokers{Timeout: &metav1.Duration{Duration: time.Minute}}.GetTimeout())
+}
+
+func TestStepSpec_GetBrokerAddresses(t *testing.T) {
+	spec := StepSpec{
+		Sources: []Source{
+			{Kafka: &KafkaSource{Kafka: Kafka{KafkaConfig: KafkaConfig{Brokers: []string{"kafka-0:9093", "kafka-1"}}}}},
+			{STAN: &STAN{NATSURL: "nats://nats"}},
+			{Elasticsearch: &ElasticsearchSource{URL: "https://es:9243"}},
+			{HTTP: &HTTPSource{}},
+			{MQTT: &MQTTSource{URL: "ssl://mosquitto"}},
+		},
+		Sinks: []Sink{
+			{Kafka: &KafkaSink{Kafka: Kafka{KafkaConfig: KafkaConfig{Brokers: []string{"kafka-0:9093"}}}}},
+			{Redis: &RedisSink{}},
+			{RedisStream: &RedisStreamSink{RedisStream: RedisStream{URL: "redis://redis"}}},
+			{HTTP: &HTTPSink{URL: "http://my-svc"}},
+			{Kafka: &KafkaSink{Kafka: Kafka{EventHubs: &EventHubs{Namespace: "my-namespace"}}}},
+			{AMQP: &AMQPSink{AMQP: AMQP{URL: "amqps://rabbitmq"}}},
+		},
+		WaitForBrokers: &WaitForBrokers{Addresses: []string{"mysql:3306", "no-port"}},
+	}
+	assert.Equal(t, []string{"kafka-0:9093", "kafka-1:9092", "nats:4222", "es:9243", "mosquitto:8883", "redis:6379", "my-namespace.servicebus.windows.net:9093", "rabbitmq:5671", "mysql:3306"}, spec.GetBrokerAddresses())
+}
+
+func Test_hostPortOf(t *testing.T) {
+	assert.Equal(t, "", hostPortOf("", 9092))
+	assert.Equal(t, "kafka:9092", hostPortOf("kafka", 9092))
+	assert.Equal(t, "kafka:9093", hostPortOf("kafka:9093", 9092))
+	assert.Equal(t, "redis:6380", hostPortOf("redis://redis:6380/0", 6379))
+	assert.Equal(t, "es:443", hostPortOf("https://es", 9200))
+	assert.Equal(t, "", hostPortOf("mysql", 0))
+}
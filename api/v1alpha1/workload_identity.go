@@ -0,0 +1,33 @@
+package v1alpha1
+
+// +kubebuilder:validation:Enum=AWS;GCP;Azure
+type WorkloadIdentityProvider string
+
+const (
+	WorkloadIdentityAWS   WorkloadIdentityProvider = "AWS"   // IAM Roles for Service Accounts (IRSA)
+	WorkloadIdentityGCP   WorkloadIdentityProvider = "GCP"   // GKE Workload Identity
+	WorkloadIdentityAzure WorkloadIdentityProvider = "Azure" // Azure AD Workload Identity
+)
+
+// WorkloadIdentity configures the step's pods to authenticate to a cloud provider as the identity bound to the
+// step's service account, rather than using static keys.
+type WorkloadIdentity struct {
+	Provider WorkloadIdentityProvider `json:"provider" protobuf:"bytes,1,opt,name=provider,casttype=WorkloadIdentityProvider"`
+}
+
+// GetPodLabels returns the labels the provider's webhook requires on the pod, before it injects the token.
+func (in *WorkloadIdentity) GetPodLabels() map[string]string {
+	if in != nil && in.Provider == WorkloadIdentityAzure {
+		return map[string]string{"azure.workload.identity/use": "true"}
+	}
+	return nil
+}
+
+// getNodeSelector returns the node labels needed for the provider, if any.
+func (in *WorkloadIdentity) getNodeSelector() map[string]string {
+	if in != nil && in.Provider == WorkloadIdentityGCP {
+		// only nodes running the GKE metadata server can exchange the service account's identity
+		return map[string]string{"iam.gke.io/gke-metadata-server-enabled": "true"}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package v1alpha1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/utils/pointer"
+)
+
+func TestWorkloadIdentity_GetPodLabels(t *testing.T) {
+	var x *WorkloadIdentity
+	assert.Nil(t, x.GetPodLabels())
+	assert.Nil(t, (&WorkloadIdentity{Provider: WorkloadIdentityAWS}).GetPodLabels())
+	assert.Equal(t, map[string]string{"azure.workload.identity/use": "true"}, (&WorkloadIdentity{Provider: WorkloadIdentityAzure}).GetPodLabels())
+}
+
+func TestStep_GetPodSpec_WorkloadIdentity(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		spec := Step{Spec: StepSpec{Cat: &Cat{}, NodeSelector: map[string]string{"foo": "bar"}}}.GetPodSpec(GetPodSpecReq{})
+		assert.Nil(t, spec.SecurityContext.FSGroup)
+		assert.Equal(t, map[string]string{"foo": "bar"}, spec.NodeSelector)
+	})
+	t.Run("GCP", func(t *testing.T) {
+		step := Step{Spec: StepSpec{Cat: &Cat{}, NodeSelector: map[string]string{"foo": "bar"}, WorkloadIdentity: &WorkloadIdentity{Provider: WorkloadIdentityGCP}}}
+		spec := step.GetPodSpec(GetPodSpecReq{})
+		assert.Equal(t, pointer.Int64Ptr(9653), spec.SecurityContext.FSGroup)
+		assert.Equal(t, map[string]string{"foo": "bar", "iam.gke.io/gke-metadata-server-enabled": "true"}, spec.NodeSelector)
+		assert.Equal(t, map[string]string{"foo": "bar"}, step.Spec.NodeSelector)
+	})
+}
@@ -23,11 +23,64 @@ package v1alpha1
 
 import (
 	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQP) DeepCopyInto(out *AMQP) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQP.
+func (in *AMQP) DeepCopy() *AMQP {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQP)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPSink) DeepCopyInto(out *AMQPSink) {
+	*out = *in
+	in.AMQP.DeepCopyInto(&out.AMQP)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPSink.
+func (in *AMQPSink) DeepCopy() *AMQPSink {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AMQPSource) DeepCopyInto(out *AMQPSource) {
+	*out = *in
+	in.AMQP.DeepCopyInto(&out.AMQP)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AMQPSource.
+func (in *AMQPSource) DeepCopy() *AMQPSource {
+	if in == nil {
+		return nil
+	}
+	out := new(AMQPSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSCredentials) DeepCopyInto(out *AWSCredentials) {
 	*out = *in
@@ -237,6 +290,51 @@ func (in *AbstractVolumeSource) DeepCopy() *AbstractVolumeSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArgoEventsSource) DeepCopyInto(out *ArgoEventsSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArgoEventsSource.
+func (in *ArgoEventsSource) DeepCopy() *ArgoEventsSource {
+	if in == nil {
+		return nil
+	}
+	out := new(ArgoEventsSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Audit) DeepCopyInto(out *Audit) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Audit.
+func (in *Audit) DeepCopy() *Audit {
+	if in == nil {
+		return nil
+	}
+	out := new(Audit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AvroCodec) DeepCopyInto(out *AvroCodec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AvroCodec.
+func (in *AvroCodec) DeepCopy() *AvroCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(AvroCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backoff) DeepCopyInto(out *Backoff) {
 	*out = *in
@@ -262,6 +360,72 @@ func (in *Backoff) DeepCopy() *Backoff {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Buffer) DeepCopyInto(out *Buffer) {
+	*out = *in
+	out.MaxSize = in.MaxSize.DeepCopy()
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(AbstractVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(Encryption)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Buffer.
+func (in *Buffer) DeepCopy() *Buffer {
+	if in == nil {
+		return nil
+	}
+	out := new(Buffer)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CSVCodec) DeepCopyInto(out *CSVCodec) {
+	*out = *in
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CSVCodec.
+func (in *CSVCodec) DeepCopy() *CSVCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(CSVCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CanaryRollout) DeepCopyInto(out *CanaryRollout) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CanaryRollout.
+func (in *CanaryRollout) DeepCopy() *CanaryRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(CanaryRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cat) DeepCopyInto(out *Cat) {
 	*out = *in
@@ -278,6 +442,21 @@ func (in *Cat) DeepCopy() *Cat {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CloudEventsSink) DeepCopyInto(out *CloudEventsSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CloudEventsSink.
+func (in *CloudEventsSink) DeepCopy() *CloudEventsSink {
+	if in == nil {
+		return nil
+	}
+	out := new(CloudEventsSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Code) DeepCopyInto(out *Code) {
 	*out = *in
@@ -293,6 +472,66 @@ func (in *Code) DeepCopy() *Code {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Codec) DeepCopyInto(out *Codec) {
+	*out = *in
+	if in.JSON != nil {
+		in, out := &in.JSON, &out.JSON
+		*out = new(JSONCodec)
+		**out = **in
+	}
+	if in.CSV != nil {
+		in, out := &in.CSV, &out.CSV
+		*out = new(CSVCodec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MsgPack != nil {
+		in, out := &in.MsgPack, &out.MsgPack
+		*out = new(MsgPackCodec)
+		**out = **in
+	}
+	if in.Avro != nil {
+		in, out := &in.Avro, &out.Avro
+		*out = new(AvroCodec)
+		**out = **in
+	}
+	if in.Protobuf != nil {
+		in, out := &in.Protobuf, &out.Protobuf
+		*out = new(ProtobufCodec)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Codec.
+func (in *Codec) DeepCopy() *Codec {
+	if in == nil {
+		return nil
+	}
+	out := new(Codec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Completion) DeepCopyInto(out *Completion) {
+	*out = *in
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Completion.
+func (in *Completion) DeepCopy() *Completion {
+	if in == nil {
+		return nil
+	}
+	out := new(Completion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Container) DeepCopyInto(out *Container) {
 	*out = *in
@@ -338,6 +577,42 @@ func (in *Container) DeepCopy() *Container {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ContainerRecommendation) DeepCopyInto(out *ContainerRecommendation) {
+	*out = *in
+	if in.PeakUsage != nil {
+		in, out := &in.PeakUsage, &out.PeakUsage
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Requests != nil {
+		in, out := &in.Requests, &out.Requests
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Applied != nil {
+		in, out := &in.Applied, &out.Applied
+		*out = make(map[string]resource.Quantity, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ContainerRecommendation.
+func (in *ContainerRecommendation) DeepCopy() *ContainerRecommendation {
+	if in == nil {
+		return nil
+	}
+	out := new(ContainerRecommendation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cron) DeepCopyInto(out *Cron) {
 	*out = *in
@@ -434,6 +709,43 @@ func (in *DBSource) DeepCopy() *DBSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaprSink) DeepCopyInto(out *DaprSink) {
+	*out = *in
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaprSink.
+func (in *DaprSink) DeepCopy() *DaprSink {
+	if in == nil {
+		return nil
+	}
+	out := new(DaprSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaprSource) DeepCopyInto(out *DaprSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DaprSource.
+func (in *DaprSource) DeepCopy() *DaprSource {
+	if in == nil {
+		return nil
+	}
+	out := new(DaprSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Database) DeepCopyInto(out *Database) {
 	*out = *in
@@ -472,81 +784,213 @@ func (in *Dedupe) DeepCopy() *Dedupe {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Expand) DeepCopyInto(out *Expand) {
+func (in *DiskState) DeepCopyInto(out *DiskState) {
 	*out = *in
-	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+	in.Volume.DeepCopyInto(&out.Volume)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Expand.
-func (in *Expand) DeepCopy() *Expand {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DiskState.
+func (in *DiskState) DeepCopy() *DiskState {
 	if in == nil {
 		return nil
 	}
-	out := new(Expand)
+	out := new(DiskState)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Filter) DeepCopyInto(out *Filter) {
+func (in *ElasticsearchSource) DeepCopyInto(out *ElasticsearchSource) {
 	*out = *in
-	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+	out.KeepAlive = in.KeepAlive
+	if in.UsernameSecret != nil {
+		in, out := &in.UsernameSecret, &out.UsernameSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
-func (in *Filter) DeepCopy() *Filter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ElasticsearchSource.
+func (in *ElasticsearchSource) DeepCopy() *ElasticsearchSource {
 	if in == nil {
 		return nil
 	}
-	out := new(Filter)
+	out := new(ElasticsearchSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Flatten) DeepCopyInto(out *Flatten) {
+func (in *Encryption) DeepCopyInto(out *Encryption) {
 	*out = *in
-	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+	in.KeySecret.DeepCopyInto(&out.KeySecret)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Flatten.
-func (in *Flatten) DeepCopy() *Flatten {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Encryption.
+func (in *Encryption) DeepCopy() *Encryption {
 	if in == nil {
 		return nil
 	}
-	out := new(Flatten)
+	out := new(Encryption)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GetPodSpecReq) DeepCopyInto(out *GetPodSpecReq) {
+func (in *EventHubs) DeepCopyInto(out *EventHubs) {
 	*out = *in
-	in.StepStatus.DeepCopyInto(&out.StepStatus)
-	in.Sidecar.DeepCopyInto(&out.Sidecar)
-	if in.ImagePullSecrets != nil {
-		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
-		*out = make([]v1.LocalObjectReference, len(*in))
-		copy(*out, *in)
-	}
+	in.ConnectionStringSecret.DeepCopyInto(&out.ConnectionStringSecret)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetPodSpecReq.
-func (in *GetPodSpecReq) DeepCopy() *GetPodSpecReq {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventHubs.
+func (in *EventHubs) DeepCopy() *EventHubs {
 	if in == nil {
 		return nil
 	}
-	out := new(GetPodSpecReq)
+	out := new(EventHubs)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Git) DeepCopyInto(out *Git) {
+func (in *EventTime) DeepCopyInto(out *EventTime) {
 	*out = *in
-	if in.Command != nil {
-		in, out := &in.Command, &out.Command
-		*out = make([]string, len(*in))
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTime.
+func (in *EventTime) DeepCopy() *EventTime {
+	if in == nil {
+		return nil
+	}
+	out := new(EventTime)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Expand) DeepCopyInto(out *Expand) {
+	*out = *in
+	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Expand.
+func (in *Expand) DeepCopy() *Expand {
+	if in == nil {
+		return nil
+	}
+	out := new(Expand)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FileSink) DeepCopyInto(out *FileSink) {
+	*out = *in
+	in.Volume.DeepCopyInto(&out.Volume)
+	out.MaxSize = in.MaxSize.DeepCopy()
+	out.MaxAge = in.MaxAge
+	if in.OnRotate != nil {
+		in, out := &in.OnRotate, &out.OnRotate
+		*out = new(HTTPSink)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FileSink.
+func (in *FileSink) DeepCopy() *FileSink {
+	if in == nil {
+		return nil
+	}
+	out := new(FileSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Filter) DeepCopyInto(out *Filter) {
+	*out = *in
+	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Filter.
+func (in *Filter) DeepCopy() *Filter {
+	if in == nil {
+		return nil
+	}
+	out := new(Filter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Flatten) DeepCopyInto(out *Flatten) {
+	*out = *in
+	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Flatten.
+func (in *Flatten) DeepCopy() *Flatten {
+	if in == nil {
+		return nil
+	}
+	out := new(Flatten)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratorSource) DeepCopyInto(out *GeneratorSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratorSource.
+func (in *GeneratorSource) DeepCopy() *GeneratorSource {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratorSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GetPodSpecReq) DeepCopyInto(out *GetPodSpecReq) {
+	*out = *in
+	in.StepStatus.DeepCopyInto(&out.StepStatus)
+	in.Sidecar.DeepCopyInto(&out.Sidecar)
+	if in.ImagePullSecrets != nil {
+		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
+		*out = make([]v1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitResources != nil {
+		in, out := &in.InitResources, &out.InitResources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GetPodSpecReq.
+func (in *GetPodSpecReq) DeepCopy() *GetPodSpecReq {
+	if in == nil {
+		return nil
+	}
+	out := new(GetPodSpecReq)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Git) DeepCopyInto(out *Git) {
+	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 	if in.UsernameSecret != nil {
@@ -654,6 +1098,28 @@ func (in *HTTPHeaderSource) DeepCopy() *HTTPHeaderSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPIngress) DeepCopyInto(out *HTTPIngress) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPIngress.
+func (in *HTTPIngress) DeepCopy() *HTTPIngress {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPIngress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPSink) DeepCopyInto(out *HTTPSink) {
 	*out = *in
@@ -679,6 +1145,26 @@ func (in *HTTPSink) DeepCopy() *HTTPSink {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPSource) DeepCopyInto(out *HTTPSource) {
 	*out = *in
+	if in.OIDC != nil {
+		in, out := &in.OIDC, &out.OIDC
+		*out = new(OIDC)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Ingress != nil {
+		in, out := &in.Ingress, &out.Ingress
+		*out = new(HTTPIngress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TokenSecret != nil {
+		in, out := &in.TokenSecret, &out.TokenSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSource.
@@ -711,6 +1197,21 @@ func (in *Interface) DeepCopy() *Interface {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JSONCodec) DeepCopyInto(out *JSONCodec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JSONCodec.
+func (in *JSONCodec) DeepCopy() *JSONCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(JSONCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JetStream) DeepCopyInto(out *JetStream) {
 	*out = *in
@@ -763,10 +1264,50 @@ func (in *JetStreamSource) DeepCopy() *JetStreamSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Join) DeepCopyInto(out *Join) {
+	*out = *in
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Lateness != nil {
+		in, out := &in.Lateness, &out.Lateness
+		*out = new(Lateness)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Join.
+func (in *Join) DeepCopy() *Join {
+	if in == nil {
+		return nil
+	}
+	out := new(Join)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kafka) DeepCopyInto(out *Kafka) {
 	*out = *in
 	in.KafkaConfig.DeepCopyInto(&out.KafkaConfig)
+	if in.Strimzi != nil {
+		in, out := &in.Strimzi, &out.Strimzi
+		*out = new(Strimzi)
+		**out = **in
+	}
+	if in.CreateTopic != nil {
+		in, out := &in.CreateTopic, &out.CreateTopic
+		*out = new(KafkaCreateTopic)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventHubs != nil {
+		in, out := &in.EventHubs, &out.EventHubs
+		*out = new(EventHubs)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Kafka.
@@ -804,6 +1345,41 @@ func (in *KafkaConfig) DeepCopy() *KafkaConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaCreateTopic) DeepCopyInto(out *KafkaCreateTopic) {
+	*out = *in
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaCreateTopic.
+func (in *KafkaCreateTopic) DeepCopy() *KafkaCreateTopic {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaCreateTopic)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KafkaHeaderMatch) DeepCopyInto(out *KafkaHeaderMatch) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaHeaderMatch.
+func (in *KafkaHeaderMatch) DeepCopy() *KafkaHeaderMatch {
+	if in == nil {
+		return nil
+	}
+	out := new(KafkaHeaderMatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KafkaNET) DeepCopyInto(out *KafkaNET) {
 	*out = *in
@@ -874,6 +1450,23 @@ func (in *KafkaSource) DeepCopyInto(out *KafkaSource) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.StartOffsets != nil {
+		in, out := &in.StartOffsets, &out.StartOffsets
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Topics != nil {
+		in, out := &in.Topics, &out.Topics
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MatchHeaders != nil {
+		in, out := &in.MatchHeaders, &out.MatchHeaders
+		*out = make([]KafkaHeaderMatch, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KafkaSource.
@@ -887,65 +1480,195 @@ func (in *KafkaSource) DeepCopy() *KafkaSource {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Log) DeepCopyInto(out *Log) {
+func (in *KinesisSource) DeepCopyInto(out *KinesisSource) {
 	*out = *in
-	if in.Truncate != nil {
-		in, out := &in.Truncate, &out.Truncate
-		*out = new(uint64)
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(AWSCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(AWSEndpoint)
+		**out = **in
+	}
+	if in.PollPeriod != nil {
+		in, out := &in.PollPeriod, &out.PollPeriod
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Log.
-func (in *Log) DeepCopy() *Log {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KinesisSource.
+func (in *KinesisSource) DeepCopy() *KinesisSource {
 	if in == nil {
 		return nil
 	}
-	out := new(Log)
+	out := new(KinesisSource)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Map) DeepCopyInto(out *Map) {
+func (in *Lateness) DeepCopyInto(out *Lateness) {
 	*out = *in
-	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+	if in.Allowed != nil {
+		in, out := &in.Allowed, &out.Allowed
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Map.
-func (in *Map) DeepCopy() *Map {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lateness.
+func (in *Lateness) DeepCopy() *Lateness {
 	if in == nil {
 		return nil
 	}
-	out := new(Map)
+	out := new(Lateness)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Meta) DeepCopyInto(out *Meta) {
+func (in *Lifecycle) DeepCopyInto(out *Lifecycle) {
 	*out = *in
+	if in.PostStart != nil {
+		in, out := &in.PostStart, &out.PostStart
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Meta.
-func (in *Meta) DeepCopy() *Meta {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Lifecycle.
+func (in *Lifecycle) DeepCopy() *Lifecycle {
 	if in == nil {
 		return nil
 	}
-	out := new(Meta)
+	out := new(Lifecycle)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Metadata) DeepCopyInto(out *Metadata) {
+func (in *Log) DeepCopyInto(out *Log) {
 	*out = *in
-	if in.Annotations != nil {
-		in, out := &in.Annotations, &out.Annotations
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
+	if in.Truncate != nil {
+		in, out := &in.Truncate, &out.Truncate
+		*out = new(uint64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Log.
+func (in *Log) DeepCopy() *Log {
+	if in == nil {
+		return nil
+	}
+	out := new(Log)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MQTTSource) DeepCopyInto(out *MQTTSource) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(TLS)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MQTTSource.
+func (in *MQTTSource) DeepCopy() *MQTTSource {
+	if in == nil {
+		return nil
+	}
+	out := new(MQTTSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Map) DeepCopyInto(out *Map) {
+	*out = *in
+	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Map.
+func (in *Map) DeepCopy() *Map {
+	if in == nil {
+		return nil
+	}
+	out := new(Map)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryState) DeepCopyInto(out *MemoryState) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryState.
+func (in *MemoryState) DeepCopy() *MemoryState {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Merge) DeepCopyInto(out *Merge) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Merge.
+func (in *Merge) DeepCopy() *Merge {
+	if in == nil {
+		return nil
+	}
+	out := new(Merge)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Meta) DeepCopyInto(out *Meta) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Meta.
+func (in *Meta) DeepCopy() *Meta {
+	if in == nil {
+		return nil
+	}
+	out := new(Meta)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Metadata) DeepCopyInto(out *Metadata) {
+	*out = *in
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
 	}
 	if in.Labels != nil {
 		in, out := &in.Labels, &out.Labels
@@ -966,6 +1689,41 @@ func (in *Metadata) DeepCopy() *Metadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetricsPush) DeepCopyInto(out *MetricsPush) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetricsPush.
+func (in *MetricsPush) DeepCopy() *MetricsPush {
+	if in == nil {
+		return nil
+	}
+	out := new(MetricsPush)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MsgPackCodec) DeepCopyInto(out *MsgPackCodec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MsgPackCodec.
+func (in *MsgPackCodec) DeepCopy() *MsgPackCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(MsgPackCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NATSAuth) DeepCopyInto(out *NATSAuth) {
 	*out = *in
@@ -986,6 +1744,51 @@ func (in *NATSAuth) DeepCopy() *NATSAuth {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OIDC) DeepCopyInto(out *OIDC) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OIDC.
+func (in *OIDC) DeepCopy() *OIDC {
+	if in == nil {
+		return nil
+	}
+	out := new(OIDC)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Parameter) DeepCopyInto(out *Parameter) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
+func (in *Parameter) DeepCopy() *Parameter {
+	if in == nil {
+		return nil
+	}
+	out := new(Parameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Passthrough) DeepCopyInto(out *Passthrough) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Passthrough.
+func (in *Passthrough) DeepCopy() *Passthrough {
+	if in == nil {
+		return nil
+	}
+	out := new(Passthrough)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Pipeline) DeepCopyInto(out *Pipeline) {
 	*out = *in
@@ -1013,6 +1816,66 @@ func (in *Pipeline) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineDefaults) DeepCopyInto(out *PipelineDefaults) {
+	*out = *in
+	if in.Kafka != nil {
+		in, out := &in.Kafka, &out.Kafka
+		*out = new(KafkaConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.STAN != nil {
+		in, out := &in.STAN, &out.STAN
+		*out = new(STANDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(Backoff)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineDefaults.
+func (in *PipelineDefaults) DeepCopy() *PipelineDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineJob) DeepCopyInto(out *PipelineJob) {
+	*out = *in
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ActiveDeadlineSeconds != nil {
+		in, out := &in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineJob.
+func (in *PipelineJob) DeepCopy() *PipelineJob {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineList) DeepCopyInto(out *PipelineList) {
 	*out = *in
@@ -1045,6 +1908,31 @@ func (in *PipelineList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSchedule) DeepCopyInto(out *PipelineSchedule) {
+	*out = *in
+	if in.SuccessfulRunsHistoryLimit != nil {
+		in, out := &in.SuccessfulRunsHistoryLimit, &out.SuccessfulRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailedRunsHistoryLimit != nil {
+		in, out := &in.FailedRunsHistoryLimit, &out.FailedRunsHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSchedule.
+func (in *PipelineSchedule) DeepCopy() *PipelineSchedule {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSchedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 	*out = *in
@@ -1060,6 +1948,51 @@ func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(Upgrade)
+		**out = **in
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(PipelineDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		copy(*out, *in)
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(PipelineJob)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(PipelineSchedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(Quota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(SLO)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metadata != nil {
+		in, out := &in.Metadata, &out.Metadata
+		*out = new(Metadata)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
@@ -1083,6 +2016,21 @@ func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
 		}
 	}
 	in.LastUpdated.DeepCopyInto(&out.LastUpdated)
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(UpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(ScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(SLOStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
@@ -1095,6 +2043,286 @@ func (in *PipelineStatus) DeepCopy() *PipelineStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProtobufCodec) DeepCopyInto(out *ProtobufCodec) {
+	*out = *in
+	if in.DescriptorSet != nil {
+		in, out := &in.DescriptorSet, &out.DescriptorSet
+		*out = make([]byte, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProtobufCodec.
+func (in *ProtobufCodec) DeepCopy() *ProtobufCodec {
+	if in == nil {
+		return nil
+	}
+	out := new(ProtobufCodec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Quota) DeepCopyInto(out *Quota) {
+	*out = *in
+	if in.Bytes != nil {
+		in, out := &in.Bytes, &out.Bytes
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Quota.
+func (in *Quota) DeepCopy() *Quota {
+	if in == nil {
+		return nil
+	}
+	out := new(Quota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Recommendations) DeepCopyInto(out *Recommendations) {
+	*out = *in
+	if in.HalfLife != nil {
+		in, out := &in.HalfLife, &out.HalfLife
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Recommendations.
+func (in *Recommendations) DeepCopy() *Recommendations {
+	if in == nil {
+		return nil
+	}
+	out := new(Recommendations)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecommendationsStatus) DeepCopyInto(out *RecommendationsStatus) {
+	*out = *in
+	in.ObservedAt.DeepCopyInto(&out.ObservedAt)
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]ContainerRecommendation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Since.DeepCopyInto(&out.Since)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecommendationsStatus.
+func (in *RecommendationsStatus) DeepCopy() *RecommendationsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RecommendationsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Redis) DeepCopyInto(out *Redis) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Redis.
+func (in *Redis) DeepCopy() *Redis {
+	if in == nil {
+		return nil
+	}
+	out := new(Redis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSink) DeepCopyInto(out *RedisSink) {
+	*out = *in
+	in.Redis.DeepCopyInto(&out.Redis)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSink.
+func (in *RedisSink) DeepCopy() *RedisSink {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisSource) DeepCopyInto(out *RedisSource) {
+	*out = *in
+	in.Redis.DeepCopyInto(&out.Redis)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisSource.
+func (in *RedisSource) DeepCopy() *RedisSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisState) DeepCopyInto(out *RedisState) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisState.
+func (in *RedisState) DeepCopy() *RedisState {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisState)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStream) DeepCopyInto(out *RedisStream) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStream.
+func (in *RedisStream) DeepCopy() *RedisStream {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStream)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamSink) DeepCopyInto(out *RedisStreamSink) {
+	*out = *in
+	in.RedisStream.DeepCopyInto(&out.RedisStream)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamSink.
+func (in *RedisStreamSink) DeepCopy() *RedisStreamSink {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RedisStreamSource) DeepCopyInto(out *RedisStreamSource) {
+	*out = *in
+	in.RedisStream.DeepCopyInto(&out.RedisStream)
+	if in.MinIdleTime != nil {
+		in, out := &in.MinIdleTime, &out.MinIdleTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RedisStreamSource.
+func (in *RedisStreamSource) DeepCopy() *RedisStreamSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RedisStreamSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResetStatus) DeepCopyInto(out *ResetStatus) {
+	*out = *in
+	in.RequestedAt.DeepCopyInto(&out.RequestedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResetStatus.
+func (in *ResetStatus) DeepCopy() *ResetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ResetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Rollout) DeepCopyInto(out *Rollout) {
+	*out = *in
+	if in.Canary != nil {
+		in, out := &in.Canary, &out.Canary
+		*out = new(CanaryRollout)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Rollout.
+func (in *Rollout) DeepCopy() *Rollout {
+	if in == nil {
+		return nil
+	}
+	out := new(Rollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStatus) DeepCopyInto(out *RolloutStatus) {
+	*out = *in
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStatus.
+func (in *RolloutStatus) DeepCopy() *RolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Runner) DeepCopyInto(out *Runner) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Runner.
+func (in *Runner) DeepCopy() *Runner {
+	if in == nil {
+		return nil
+	}
+	out := new(Runner)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *S3) DeepCopyInto(out *S3) {
 	*out = *in
@@ -1115,7 +2343,42 @@ func (in *S3) DeepCopy() *S3 {
 	if in == nil {
 		return nil
 	}
-	out := new(S3)
+	out := new(S3)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Notifications) DeepCopyInto(out *S3Notifications) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Notifications.
+func (in *S3Notifications) DeepCopy() *S3Notifications {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Notifications)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3OnProcessed) DeepCopyInto(out *S3OnProcessed) {
+	*out = *in
+	if in.Tag != nil {
+		in, out := &in.Tag, &out.Tag
+		*out = new(S3Tag)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3OnProcessed.
+func (in *S3OnProcessed) DeepCopy() *S3OnProcessed {
+	if in == nil {
+		return nil
+	}
+	out := new(S3OnProcessed)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1124,6 +2387,11 @@ func (in *S3) DeepCopy() *S3 {
 func (in *S3Sink) DeepCopyInto(out *S3Sink) {
 	*out = *in
 	in.S3.DeepCopyInto(&out.S3)
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(Encryption)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Sink.
@@ -1145,6 +2413,16 @@ func (in *S3Source) DeepCopyInto(out *S3Source) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.OnProcessed != nil {
+		in, out := &in.OnProcessed, &out.OnProcessed
+		*out = new(S3OnProcessed)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Notifications != nil {
+		in, out := &in.Notifications, &out.Notifications
+		*out = new(S3Notifications)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Source.
@@ -1157,6 +2435,21 @@ func (in *S3Source) DeepCopy() *S3Source {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *S3Tag) DeepCopyInto(out *S3Tag) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new S3Tag.
+func (in *S3Tag) DeepCopy() *S3Tag {
+	if in == nil {
+		return nil
+	}
+	out := new(S3Tag)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SASL) DeepCopyInto(out *SASL) {
 	*out = *in
@@ -1182,6 +2475,51 @@ func (in *SASL) DeepCopy() *SASL {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLO) DeepCopyInto(out *SLO) {
+	*out = *in
+	if in.MaxLatency != nil {
+		in, out := &in.MaxLatency, &out.MaxLatency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLO.
+func (in *SLO) DeepCopy() *SLO {
+	if in == nil {
+		return nil
+	}
+	out := new(SLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOStatus) DeepCopyInto(out *SLOStatus) {
+	*out = *in
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Violations != nil {
+		in, out := &in.Violations, &out.Violations
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOStatus.
+func (in *SLOStatus) DeepCopy() *SLOStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SQLAction) DeepCopyInto(out *SQLAction) {
 	*out = *in
@@ -1228,6 +2566,41 @@ func (in *SQLStatement) DeepCopy() *SQLStatement {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SQSSource) DeepCopyInto(out *SQSSource) {
+	*out = *in
+	if in.Credentials != nil {
+		in, out := &in.Credentials, &out.Credentials
+		*out = new(AWSCredentials)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(AWSEndpoint)
+		**out = **in
+	}
+	if in.WaitTime != nil {
+		in, out := &in.WaitTime, &out.WaitTime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.VisibilityTimeout != nil {
+		in, out := &in.VisibilityTimeout, &out.VisibilityTimeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SQSSource.
+func (in *SQSSource) DeepCopy() *SQSSource {
+	if in == nil {
+		return nil
+	}
+	out := new(SQSSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *STAN) DeepCopyInto(out *STAN) {
 	*out = *in
@@ -1236,6 +2609,16 @@ func (in *STAN) DeepCopyInto(out *STAN) {
 		*out = new(NATSAuth)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AckWait != nil {
+		in, out := &in.AckWait, &out.AckWait
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Reconnect != nil {
+		in, out := &in.Reconnect, &out.Reconnect
+		*out = new(STANReconnect)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new STAN.
@@ -1248,9 +2631,80 @@ func (in *STAN) DeepCopy() *STAN {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *STANDefaults) DeepCopyInto(out *STANDefaults) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(NATSAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new STANDefaults.
+func (in *STANDefaults) DeepCopy() *STANDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(STANDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *STANReconnect) DeepCopyInto(out *STANReconnect) {
+	*out = *in
+	if in.PingInterval != nil {
+		in, out := &in.PingInterval, &out.PingInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.MaxBackoff != nil {
+		in, out := &in.MaxBackoff, &out.MaxBackoff
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new STANReconnect.
+func (in *STANReconnect) DeepCopy() *STANReconnect {
+	if in == nil {
+		return nil
+	}
+	out := new(STANReconnect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Sample) DeepCopyInto(out *Sample) {
+	*out = *in
+	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sample.
+func (in *Sample) DeepCopy() *Sample {
+	if in == nil {
+		return nil
+	}
+	out := new(Sample)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Scale) DeepCopyInto(out *Scale) {
 	*out = *in
+	if in.SlowConsumerDelay != nil {
+		in, out := &in.SlowConsumerDelay, &out.SlowConsumerDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Scale.
@@ -1263,10 +2717,39 @@ func (in *Scale) DeepCopy() *Scale {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	if in.LastScheduledTime != nil {
+		in, out := &in.LastScheduledTime, &out.LastScheduledTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sidecar) DeepCopyInto(out *Sidecar) {
 	*out = *in
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(SidecarMetrics)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sidecar.
@@ -1279,6 +2762,36 @@ func (in *Sidecar) DeepCopy() *Sidecar {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SidecarMetrics) DeepCopyInto(out *SidecarMetrics) {
+	*out = *in
+	if in.Disabled != nil {
+		in, out := &in.Disabled, &out.Disabled
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DropLabels != nil {
+		in, out := &in.DropLabels, &out.DropLabels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Push != nil {
+		in, out := &in.Push, &out.Push
+		*out = new(MetricsPush)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SidecarMetrics.
+func (in *SidecarMetrics) DeepCopy() *SidecarMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(SidecarMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Sink) DeepCopyInto(out *Sink) {
 	*out = *in
@@ -1322,6 +2835,61 @@ func (in *Sink) DeepCopyInto(out *Sink) {
 		*out = new(JetStreamSink)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Buffer != nil {
+		in, out := &in.Buffer, &out.Buffer
+		*out = new(Buffer)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CloudEvents != nil {
+		in, out := &in.CloudEvents, &out.CloudEvents
+		*out = new(CloudEventsSink)
+		**out = **in
+	}
+	if in.Dapr != nil {
+		in, out := &in.Dapr, &out.Dapr
+		*out = new(DaprSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Codec != nil {
+		in, out := &in.Codec, &out.Codec
+		*out = new(Codec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Test != nil {
+		in, out := &in.Test, &out.Test
+		*out = new(TestSink)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Snowflake != nil {
+		in, out := &in.Snowflake, &out.Snowflake
+		*out = new(SnowflakeSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.File != nil {
+		in, out := &in.File, &out.File
+		*out = new(FileSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMQP != nil {
+		in, out := &in.AMQP, &out.AMQP
+		*out = new(AMQPSink)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RedisStream != nil {
+		in, out := &in.RedisStream, &out.RedisStream
+		*out = new(RedisStreamSink)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Sink.
@@ -1334,6 +2902,46 @@ func (in *Sink) DeepCopy() *Sink {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeColumn) DeepCopyInto(out *SnowflakeColumn) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeColumn.
+func (in *SnowflakeColumn) DeepCopy() *SnowflakeColumn {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeColumn)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SnowflakeSink) DeepCopyInto(out *SnowflakeSink) {
+	*out = *in
+	if in.DSNSecret != nil {
+		in, out := &in.DSNSecret, &out.DSNSecret
+		*out = new(v1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Columns != nil {
+		in, out := &in.Columns, &out.Columns
+		*out = make([]SnowflakeColumn, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SnowflakeSink.
+func (in *SnowflakeSink) DeepCopy() *SnowflakeSink {
+	if in == nil {
+		return nil
+	}
+	out := new(SnowflakeSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Source) DeepCopyInto(out *Source) {
 	*out = *in
@@ -1355,29 +2963,94 @@ func (in *Source) DeepCopyInto(out *Source) {
 	if in.HTTP != nil {
 		in, out := &in.HTTP, &out.HTTP
 		*out = new(HTTPSource)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.S3 != nil {
 		in, out := &in.S3, &out.S3
 		*out = new(S3Source)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.DB != nil {
-		in, out := &in.DB, &out.DB
-		*out = new(DBSource)
+	if in.DB != nil {
+		in, out := &in.DB, &out.DB
+		*out = new(DBSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Volume != nil {
+		in, out := &in.Volume, &out.Volume
+		*out = new(VolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.JetStream != nil {
+		in, out := &in.JetStream, &out.JetStream
+		*out = new(JetStreamSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArgoEvents != nil {
+		in, out := &in.ArgoEvents, &out.ArgoEvents
+		*out = new(ArgoEventsSource)
+		**out = **in
+	}
+	if in.Dapr != nil {
+		in, out := &in.Dapr, &out.Dapr
+		*out = new(DaprSource)
+		**out = **in
+	}
+	if in.Codec != nil {
+		in, out := &in.Codec, &out.Codec
+		*out = new(Codec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EventTime != nil {
+		in, out := &in.EventTime, &out.EventTime
+		*out = new(EventTime)
+		**out = **in
+	}
+	in.Retry.DeepCopyInto(&out.Retry)
+	if in.Test != nil {
+		in, out := &in.Test, &out.Test
+		*out = new(TestSource)
+		**out = **in
+	}
+	if in.Generator != nil {
+		in, out := &in.Generator, &out.Generator
+		*out = new(GeneratorSource)
+		**out = **in
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Elasticsearch != nil {
+		in, out := &in.Elasticsearch, &out.Elasticsearch
+		*out = new(ElasticsearchSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SQS != nil {
+		in, out := &in.SQS, &out.SQS
+		*out = new(SQSSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Kinesis != nil {
+		in, out := &in.Kinesis, &out.Kinesis
+		*out = new(KinesisSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AMQP != nil {
+		in, out := &in.AMQP, &out.AMQP
+		*out = new(AMQPSource)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.Volume != nil {
-		in, out := &in.Volume, &out.Volume
-		*out = new(VolumeSource)
+	if in.MQTT != nil {
+		in, out := &in.MQTT, &out.MQTT
+		*out = new(MQTTSource)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.JetStream != nil {
-		in, out := &in.JetStream, &out.JetStream
-		*out = new(JetStreamSource)
+	if in.RedisStream != nil {
+		in, out := &in.RedisStream, &out.RedisStream
+		*out = new(RedisStreamSource)
 		(*in).DeepCopyInto(*out)
 	}
-	in.Retry.DeepCopyInto(&out.Retry)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Source.
@@ -1390,6 +3063,60 @@ func (in *Source) DeepCopy() *Source {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceError) DeepCopyInto(out *SourceError) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceError.
+func (in *SourceError) DeepCopy() *SourceError {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SourceStatus) DeepCopyInto(out *SourceStatus) {
+	*out = *in
+	if in.PartitionPending != nil {
+		in, out := &in.PartitionPending, &out.PartitionPending
+		*out = make(map[string]uint64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PartitionLagSeconds != nil {
+		in, out := &in.PartitionLagSeconds, &out.PartitionLagSeconds
+		*out = make(map[string]uint64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Watermark != nil {
+		in, out := &in.Watermark, &out.Watermark
+		*out = (*in).DeepCopy()
+	}
+	if in.LastError != nil {
+		in, out := &in.LastError, &out.LastError
+		*out = new(SourceError)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SourceStatus.
+func (in *SourceStatus) DeepCopy() *SourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(SourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in Sources) DeepCopyInto(out *Sources) {
 	{
@@ -1411,6 +3138,67 @@ func (in Sources) DeepCopy() Sources {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Split) DeepCopyInto(out *Split) {
+	*out = *in
+	in.AbstractStep.DeepCopyInto(&out.AbstractStep)
+	if in.ChunkSize != nil {
+		in, out := &in.ChunkSize, &out.ChunkSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Split.
+func (in *Split) DeepCopy() *Split {
+	if in == nil {
+		return nil
+	}
+	out := new(Split)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *State) DeepCopyInto(out *State) {
+	*out = *in
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(MemoryState)
+		**out = **in
+	}
+	if in.Disk != nil {
+		in, out := &in.Disk, &out.Disk
+		*out = new(DiskState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Redis != nil {
+		in, out := &in.Redis, &out.Redis
+		*out = new(RedisState)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CheckpointInterval != nil {
+		in, out := &in.CheckpointInterval, &out.CheckpointInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new State.
+func (in *State) DeepCopy() *State {
+	if in == nil {
+		return nil
+	}
+	out := new(State)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Step) DeepCopyInto(out *Step) {
 	*out = *in
@@ -1438,6 +3226,21 @@ func (in *Step) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepDependency) DeepCopyInto(out *StepDependency) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepDependency.
+func (in *StepDependency) DeepCopy() *StepDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(StepDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepList) DeepCopyInto(out *StepList) {
 	*out = *in
@@ -1470,6 +3273,21 @@ func (in *StepList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepParity) DeepCopyInto(out *StepParity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepParity.
+func (in *StepParity) DeepCopy() *StepParity {
+	if in == nil {
+		return nil
+	}
+	out := new(StepParity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StepSpec) DeepCopyInto(out *StepSpec) {
 	*out = *in
@@ -1523,7 +3341,32 @@ func (in *StepSpec) DeepCopyInto(out *StepSpec) {
 		*out = new(Map)
 		(*in).DeepCopyInto(*out)
 	}
-	out.Scale = in.Scale
+	if in.Sample != nil {
+		in, out := &in.Sample, &out.Sample
+		*out = new(Sample)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = new(Split)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Passthrough != nil {
+		in, out := &in.Passthrough, &out.Passthrough
+		*out = new(Passthrough)
+		**out = **in
+	}
+	if in.Join != nil {
+		in, out := &in.Join, &out.Join
+		*out = new(Join)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.State != nil {
+		in, out := &in.State, &out.State
+		*out = new(State)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Scale.DeepCopyInto(&out.Scale)
 	if in.Sources != nil {
 		in, out := &in.Sources, &out.Sources
 		*out = make(Sources, len(*in))
@@ -1575,6 +3418,78 @@ func (in *StepSpec) DeepCopyInto(out *StepSpec) {
 		*out = make([]v1.LocalObjectReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.UpdateInterval != nil {
+		in, out := &in.UpdateInterval, &out.UpdateInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.WorkloadIdentity != nil {
+		in, out := &in.WorkloadIdentity, &out.WorkloadIdentity
+		*out = new(WorkloadIdentity)
+		**out = **in
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(Rollout)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Containers != nil {
+		in, out := &in.Containers, &out.Containers
+		*out = make([]v1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]StepDependency, len(*in))
+		copy(*out, *in)
+	}
+	if in.Completion != nil {
+		in, out := &in.Completion, &out.Completion
+		*out = new(Completion)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Audit != nil {
+		in, out := &in.Audit, &out.Audit
+		*out = new(Audit)
+		**out = **in
+	}
+	if in.Runner != nil {
+		in, out := &in.Runner, &out.Runner
+		*out = new(Runner)
+		**out = **in
+	}
+	if in.Merge != nil {
+		in, out := &in.Merge, &out.Merge
+		*out = new(Merge)
+		**out = **in
+	}
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(Quota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WaitForBrokers != nil {
+		in, out := &in.WaitForBrokers, &out.WaitForBrokers
+		*out = new(WaitForBrokers)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(Lifecycle)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = new(Recommendations)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepSpec.
@@ -1591,6 +3506,42 @@ func (in *StepSpec) DeepCopy() *StepSpec {
 func (in *StepStatus) DeepCopyInto(out *StepStatus) {
 	*out = *in
 	in.LastScaledAt.DeepCopyInto(&out.LastScaledAt)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OffsetReset != nil {
+		in, out := &in.OffsetReset, &out.OffsetReset
+		*out = new(ResetStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]SourceStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SourceOffsets != nil {
+		in, out := &in.SourceOffsets, &out.SourceOffsets
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Recommendations != nil {
+		in, out := &in.Recommendations, &out.Recommendations
+		*out = new(RecommendationsStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepStatus.
@@ -1618,6 +3569,21 @@ func (in *Storage) DeepCopy() *Storage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Strimzi) DeepCopyInto(out *Strimzi) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Strimzi.
+func (in *Strimzi) DeepCopy() *Strimzi {
+	if in == nil {
+		return nil
+	}
+	out := new(Strimzi)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TLS) DeepCopyInto(out *TLS) {
 	*out = *in
@@ -1648,6 +3614,71 @@ func (in *TLS) DeepCopy() *TLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestSink) DeepCopyInto(out *TestSink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSink.
+func (in *TestSink) DeepCopy() *TestSink {
+	if in == nil {
+		return nil
+	}
+	out := new(TestSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TestSource) DeepCopyInto(out *TestSource) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TestSource.
+func (in *TestSource) DeepCopy() *TestSource {
+	if in == nil {
+		return nil
+	}
+	out := new(TestSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Upgrade) DeepCopyInto(out *Upgrade) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Upgrade.
+func (in *Upgrade) DeepCopy() *Upgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(Upgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UpgradeStatus) DeepCopyInto(out *UpgradeStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]StepParity, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UpgradeStatus.
+func (in *UpgradeStatus) DeepCopy() *UpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(UpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeSink) DeepCopyInto(out *VolumeSink) {
 	*out = *in
@@ -1684,3 +3715,43 @@ func (in *VolumeSource) DeepCopy() *VolumeSource {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WaitForBrokers) DeepCopyInto(out *WaitForBrokers) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WaitForBrokers.
+func (in *WaitForBrokers) DeepCopy() *WaitForBrokers {
+	if in == nil {
+		return nil
+	}
+	out := new(WaitForBrokers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadIdentity) DeepCopyInto(out *WorkloadIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadIdentity.
+func (in *WorkloadIdentity) DeepCopy() *WorkloadIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,92 @@
+// Package dsl is a fluent API to build pipelines in Go, e.g.
+//
+//	p, err := dsl.Pipeline("my-pipeline").
+//		Describe("copies messages from one topic to another").
+//		Step(dsl.Kafka("input-topic").Cat("main").Kafka("output-topic")).
+//		Build()
+//
+// It is the Go equivalent of the Python DSL.
+package dsl
+
+import (
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+type PipelineBuilder struct {
+	name        string
+	namespace   string
+	annotations map[string]string
+	steps       []*StepBuilder
+}
+
+func Pipeline(name string) *PipelineBuilder {
+	return &PipelineBuilder{name: name, annotations: map[string]string{}}
+}
+
+func (b *PipelineBuilder) Namespace(namespace string) *PipelineBuilder {
+	b.namespace = namespace
+	return b
+}
+
+func (b *PipelineBuilder) Annotate(name, value string) *PipelineBuilder {
+	b.annotations[name] = value
+	return b
+}
+
+func (b *PipelineBuilder) Owner(value string) *PipelineBuilder {
+	return b.Annotate(dfv1.KeyOwner, value)
+}
+
+func (b *PipelineBuilder) Describe(value string) *PipelineBuilder {
+	return b.Annotate(dfv1.KeyDescription, value)
+}
+
+func (b *PipelineBuilder) Step(step *StepBuilder) *PipelineBuilder {
+	b.steps = append(b.steps, step)
+	return b
+}
+
+// Build returns the pipeline, or an error if it is invalid.
+func (b *PipelineBuilder) Build() (*dfv1.Pipeline, error) {
+	if b.name == "" {
+		return nil, fmt.Errorf("pipeline name is required")
+	}
+	if len(b.steps) == 0 {
+		return nil, fmt.Errorf("pipeline %q must have at least one step", b.name)
+	}
+	p := &dfv1.Pipeline{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: dfv1.PipelineGroupVersionKind.GroupVersion().String(),
+			Kind:       dfv1.PipelineGroupVersionKind.Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{Name: b.name, Namespace: b.namespace},
+	}
+	if len(b.annotations) > 0 {
+		p.Annotations = b.annotations
+	}
+	names := map[string]bool{}
+	for _, s := range b.steps {
+		if s.spec.Name == "" {
+			return nil, fmt.Errorf("step name is required")
+		}
+		if names[s.spec.Name] {
+			return nil, fmt.Errorf("duplicate step name %q", s.spec.Name)
+		}
+		names[s.spec.Name] = true
+		p.Spec.Steps = append(p.Spec.Steps, *s.spec.DeepCopy())
+	}
+	return p, nil
+}
+
+// YAML returns the pipeline's manifest, e.g. to `kubectl apply`.
+func (b *PipelineBuilder) YAML() ([]byte, error) {
+	p, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(p)
+}
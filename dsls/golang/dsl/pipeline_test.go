@@ -0,0 +1,59 @@
+package dsl
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeline(t *testing.T) {
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := Pipeline("").Build()
+		assert.EqualError(t, err, "pipeline name is required")
+		_, err = Pipeline("my-pl").Build()
+		assert.EqualError(t, err, `pipeline "my-pl" must have at least one step`)
+		_, err = Pipeline("my-pl").Step(Kafka("a").Cat("main")).Step(Kafka("b").Cat("main")).Build()
+		assert.EqualError(t, err, `duplicate step name "main"`)
+	})
+	t.Run("Valid", func(t *testing.T) {
+		p, err := Pipeline("my-pl").
+			Namespace("my-ns").
+			Owner("my-owner").
+			Describe("my-desc").
+			Step(Kafka("input-topic").
+				STAN("input-subject").
+				Map("main", "bytes(string(msg) + '!')").
+				Kafka("output-topic").
+				Log().
+				Replicas(2)).
+			Build()
+		assert.NoError(t, err)
+		assert.Equal(t, "Pipeline", p.Kind)
+		assert.Equal(t, "dataflow.argoproj.io/v1alpha1", p.APIVersion)
+		assert.Equal(t, "my-pl", p.Name)
+		assert.Equal(t, "my-ns", p.Namespace)
+		assert.Equal(t, "my-owner", p.Annotations[dfv1.KeyOwner])
+		assert.Equal(t, "my-desc", p.Annotations[dfv1.KeyDescription])
+		if assert.Len(t, p.Spec.Steps, 1) {
+			s := p.Spec.Steps[0]
+			assert.Equal(t, "main", s.Name)
+			assert.Equal(t, "bytes(string(msg) + '!')", s.Map.Expression)
+			assert.Equal(t, uint32(2), s.Replicas)
+			if assert.Len(t, s.Sources, 2) {
+				assert.Equal(t, "input-topic", s.Sources[0].Kafka.Topic)
+				assert.Equal(t, "input-subject", s.Sources[1].STAN.Subject)
+			}
+			if assert.Len(t, s.Sinks, 2) {
+				assert.Equal(t, "output-topic", s.Sinks[0].Kafka.Topic)
+				assert.NotNil(t, s.Sinks[1].Log)
+			}
+		}
+	})
+	t.Run("YAML", func(t *testing.T) {
+		data, err := Pipeline("my-pl").Step(Cron("*/3 * * * * *").Cat("main").Log()).YAML()
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "kind: Pipeline")
+		assert.Contains(t, string(data), "schedule: '*/3 * * * * *'")
+	})
+}
@@ -0,0 +1,125 @@
+package dsl
+
+import (
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// SourcesBuilder builds a step's sources. Select the step's processor (e.g. Cat) to start building the step.
+type SourcesBuilder struct {
+	sources []dfv1.Source
+}
+
+// From returns a builder starting with the source, use this for configuration that the builder does not support.
+func From(source dfv1.Source) *SourcesBuilder {
+	return (&SourcesBuilder{}).From(source)
+}
+
+func Cron(schedule string) *SourcesBuilder {
+	return (&SourcesBuilder{}).Cron(schedule)
+}
+
+func HTTP() *SourcesBuilder {
+	return (&SourcesBuilder{}).HTTP()
+}
+
+func Kafka(topic string) *SourcesBuilder {
+	return (&SourcesBuilder{}).Kafka(topic)
+}
+
+func STAN(subject string) *SourcesBuilder {
+	return (&SourcesBuilder{}).STAN(subject)
+}
+
+func JetStream(subject string) *SourcesBuilder {
+	return (&SourcesBuilder{}).JetStream(subject)
+}
+
+// NoSources returns a builder for steps without sources, e.g. generators.
+func NoSources() *SourcesBuilder {
+	return &SourcesBuilder{}
+}
+
+func (b *SourcesBuilder) From(source dfv1.Source) *SourcesBuilder {
+	b.sources = append(b.sources, source)
+	return b
+}
+
+func (b *SourcesBuilder) Cron(schedule string) *SourcesBuilder {
+	return b.From(dfv1.Source{Cron: &dfv1.Cron{Schedule: schedule}})
+}
+
+func (b *SourcesBuilder) HTTP() *SourcesBuilder {
+	return b.From(dfv1.Source{HTTP: &dfv1.HTTPSource{}})
+}
+
+func (b *SourcesBuilder) Kafka(topic string) *SourcesBuilder {
+	return b.From(dfv1.Source{Kafka: &dfv1.KafkaSource{Kafka: dfv1.Kafka{Topic: topic}}})
+}
+
+func (b *SourcesBuilder) STAN(subject string) *SourcesBuilder {
+	return b.From(dfv1.Source{STAN: &dfv1.STAN{Subject: subject}})
+}
+
+func (b *SourcesBuilder) JetStream(subject string) *SourcesBuilder {
+	return b.From(dfv1.Source{JetStream: &dfv1.JetStreamSource{JetStream: dfv1.JetStream{Subject: subject}}})
+}
+
+func (b *SourcesBuilder) step(name string, spec dfv1.StepSpec) *StepBuilder {
+	spec.Name = name
+	spec.Sources = b.sources
+	return &StepBuilder{spec: spec}
+}
+
+func (b *SourcesBuilder) Cat(name string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Cat: &dfv1.Cat{}})
+}
+
+func (b *SourcesBuilder) Passthrough(name string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Passthrough: &dfv1.Passthrough{}})
+}
+
+// Join correlates messages from the named left and right sources by the key expression.
+func (b *SourcesBuilder) Join(name, left, right, key string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Join: &dfv1.Join{Left: left, Right: right, Key: key}})
+}
+
+func (b *SourcesBuilder) Map(name, expression string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Map: &dfv1.Map{Expression: expression}})
+}
+
+func (b *SourcesBuilder) Filter(name, expression string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Filter: &dfv1.Filter{Expression: expression}})
+}
+
+// Sample keeps the percentage of messages, by the hash of the key expression if it is not empty, otherwise at random.
+func (b *SourcesBuilder) Sample(name, percent, key string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Sample: &dfv1.Sample{Percent: percent, Key: key}})
+}
+
+// Split splits messages on the delimiter.
+func (b *SourcesBuilder) Split(name, delimiter string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Split: &dfv1.Split{Delimiter: delimiter}})
+}
+
+// Chunk splits messages into chunks of the size, e.g. "1Mi".
+func (b *SourcesBuilder) Chunk(name, chunkSize string) *StepBuilder {
+	x := resource.MustParse(chunkSize)
+	return b.step(name, dfv1.StepSpec{Split: &dfv1.Split{ChunkSize: &x}})
+}
+
+func (b *SourcesBuilder) Expand(name string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Expand: &dfv1.Expand{}})
+}
+
+func (b *SourcesBuilder) Flatten(name string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Flatten: &dfv1.Flatten{}})
+}
+
+func (b *SourcesBuilder) Dedupe(name, uid string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Dedupe: &dfv1.Dedupe{UID: uid}})
+}
+
+func (b *SourcesBuilder) Container(name, image string, args ...string) *StepBuilder {
+	return b.step(name, dfv1.StepSpec{Container: &dfv1.Container{Image: image, Args: args}})
+}
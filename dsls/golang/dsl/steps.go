@@ -0,0 +1,69 @@
+package dsl
+
+import dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+
+// StepBuilder builds a step's processor and sinks.
+type StepBuilder struct {
+	spec dfv1.StepSpec
+}
+
+// To adds the sink, use this for configuration that the builder does not support.
+func (b *StepBuilder) To(sink dfv1.Sink) *StepBuilder {
+	b.spec.Sinks = append(b.spec.Sinks, sink)
+	return b
+}
+
+func (b *StepBuilder) Log() *StepBuilder {
+	return b.To(dfv1.Sink{Log: &dfv1.Log{}})
+}
+
+func (b *StepBuilder) HTTP(url string) *StepBuilder {
+	return b.To(dfv1.Sink{HTTP: &dfv1.HTTPSink{URL: url}})
+}
+
+func (b *StepBuilder) Kafka(topic string) *StepBuilder {
+	return b.To(dfv1.Sink{Kafka: &dfv1.KafkaSink{Kafka: dfv1.Kafka{Topic: topic}}})
+}
+
+func (b *StepBuilder) STAN(subject string) *StepBuilder {
+	return b.To(dfv1.Sink{STAN: &dfv1.STAN{Subject: subject}})
+}
+
+func (b *StepBuilder) JetStream(subject string) *StepBuilder {
+	return b.To(dfv1.Sink{JetStream: &dfv1.JetStreamSink{JetStream: dfv1.JetStream{Subject: subject}}})
+}
+
+// Replicas sets the number of replicas the step starts with.
+func (b *StepBuilder) Replicas(replicas uint32) *StepBuilder {
+	b.spec.Replicas = replicas
+	return b
+}
+
+// Scale sets the expression that returns the desired number of replicas, e.g. "pending > 0 ? 1 : 0".
+func (b *StepBuilder) Scale(desiredReplicas string) *StepBuilder {
+	b.spec.Scale.DesiredReplicas = desiredReplicas
+	return b
+}
+
+// Terminator makes the pipeline complete when this step completes.
+func (b *StepBuilder) Terminator() *StepBuilder {
+	b.spec.Terminator = true
+	return b
+}
+
+// Completion makes the step run to completion, e.g. after a number of messages, rather than forever.
+func (b *StepBuilder) Completion(x dfv1.Completion) *StepBuilder {
+	b.spec.Completion = &x
+	return b
+}
+
+// State sets where a dedupe or join step keeps its state, e.g. on disk so it survives restarts.
+func (b *StepBuilder) State(x dfv1.State) *StepBuilder {
+	b.spec.State = &x
+	return b
+}
+
+// Spec returns the step's spec, so that fields the builder does not support can be set.
+func (b *StepBuilder) Spec() *dfv1.StepSpec {
+	return &b.spec
+}
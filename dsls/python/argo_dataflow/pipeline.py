@@ -30,6 +30,11 @@ class PipelineBuilder:
         self._namespace = None
         self._annotations = {}
         self._steps = []
+        self._quota = None
+        self._slo = None
+        self._tenant = None
+        self._metadata = None
+        self._serviceAccountName = None
         self.owner(USER)
 
     def annotate(self, name, value):
@@ -50,6 +55,42 @@ class PipelineBuilder:
         self._steps.append(step)
         return self
 
+    def quota(self, messages=None, bytes=None, coordinator=None):
+        self._quota = {}
+        if messages:
+            self._quota['messages'] = messages
+        if bytes:
+            self._quota['bytes'] = bytes
+        if coordinator:
+            self._quota['coordinator'] = coordinator
+        return self
+
+    def slo(self, maxPending=None, maxLatency=None, maxErrorRate=None):
+        self._slo = {}
+        if maxPending:
+            self._slo['maxPending'] = maxPending
+        if maxLatency:
+            self._slo['maxLatency'] = maxLatency
+        if maxErrorRate:
+            self._slo['maxErrorRate'] = maxErrorRate
+        return self
+
+    def tenant(self, tenant):
+        self._tenant = tenant
+        return self
+
+    def metadata(self, labels=None, annotations=None):
+        self._metadata = {}
+        if labels:
+            self._metadata['labels'] = labels
+        if annotations:
+            self._metadata['annotations'] = annotations
+        return self
+
+    def serviceAccount(self, name):
+        self._serviceAccountName = name
+        return self
+
     def dump(self):
         m = {
             'name': self._name,
@@ -59,13 +100,24 @@ class PipelineBuilder:
             m['namespace'] = self._namespace
         if self._resourceVersion:
             m['resourceVersion'] = self._resourceVersion
+        spec = {
+            'steps': [x.dump() for x in self._steps]
+        }
+        if self._quota is not None:
+            spec['quota'] = self._quota
+        if self._slo is not None:
+            spec['slo'] = self._slo
+        if self._tenant:
+            spec['tenant'] = self._tenant
+        if self._metadata is not None:
+            spec['metadata'] = self._metadata
+        if self._serviceAccountName:
+            spec['serviceAccountName'] = self._serviceAccountName
         return {
             'apiVersion': 'dataflow.argoproj.io/v1alpha1',
             'kind': 'Pipeline',
             'metadata': m,
-            'spec': {
-                'steps': [x.dump() for x in self._steps]
-            }
+            'spec': spec
         }
 
     def yaml(self):
@@ -141,11 +193,12 @@ class LogSink(Sink):
 
 
 class HTTPSink(Sink):
-    def __init__(self, url, name=None, insecureSkipVerify=None, headers=None):
+    def __init__(self, url, name=None, insecureSkipVerify=None, headers=None, dedupe=None):
         super().__init__(name)
         self._insecureSkipVerify = insecureSkipVerify
         self._url = url
         self._headers = headers
+        self._dedupe = dedupe
 
     def dump(self):
         x = super().dump()
@@ -154,15 +207,18 @@ class HTTPSink(Sink):
             h['headers'] = self._headers
         if self._insecureSkipVerify:
             h['insecureSkipVerify'] = self._insecureSkipVerify
+        if self._dedupe:
+            h['dedupe'] = True
         x['http'] = h
         return x
 
 
 class KafkaSink(Sink):
     def __init__(self, subject, name=None, a_sync=False, batchSize=None, linger=None, compressionType=None, acks=None,
-                 enableIdempotence=None):
+                 enableIdempotence=None, eventHubs=None):
         super().__init__(name)
         self._subject = subject
+        self._eventHubs = eventHubs
         self._a_sync = a_sync
         self._batchSize = batchSize
         self._linger = linger
@@ -185,6 +241,8 @@ class KafkaSink(Sink):
             y['acks'] = self._acks
         if self._enableIdempotence:
             y['enableIdempotence'] = self._enableIdempotence
+        if self._eventHubs:
+            y['eventHubs'] = self._eventHubs
         x['kafka'] = y
         return x
 
@@ -211,6 +269,174 @@ class JetStreamSink(Sink):
         return x
 
 
+class CloudEventsSink(Sink):
+    def __init__(self, url=None, broker=None, type=None, contentType=None, name=None):
+        super().__init__(name=name)
+        self._url = url
+        self._broker = broker
+        self._type = type
+        self._contentType = contentType
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._url:
+            y['url'] = self._url
+        if self._broker:
+            y['broker'] = self._broker
+        if self._type:
+            y['type'] = self._type
+        if self._contentType:
+            y['contentType'] = self._contentType
+        x['cloudEvents'] = y
+        return x
+
+
+class DaprSink(Sink):
+    def __init__(self, binding, operation=None, metadata=None, name=None):
+        super().__init__(name=name)
+        assert binding
+        self._binding = binding
+        self._operation = operation
+        self._metadata = metadata
+
+    def dump(self):
+        x = super().dump()
+        y = {'binding': self._binding}
+        if self._operation:
+            y['operation'] = self._operation
+        if self._metadata:
+            y['metadata'] = self._metadata
+        x['dapr'] = y
+        return x
+
+
+class RedisSink(Sink):
+    def __init__(self, channel, url=None, name=None):
+        super().__init__(name=name)
+        assert channel
+        self._channel = channel
+        self._url = url
+
+    def dump(self):
+        x = super().dump()
+        y = {'channel': self._channel}
+        if self._url:
+            y['url'] = self._url
+        x['redis'] = y
+        return x
+
+
+class RedisStreamSink(Sink):
+    def __init__(self, stream, url=None, field=None, maxLen=None, name=None):
+        super().__init__(name=name)
+        assert stream
+        self._stream = stream
+        self._url = url
+        self._field = field
+        self._maxLen = maxLen
+
+    def dump(self):
+        x = super().dump()
+        y = {'stream': self._stream}
+        if self._url:
+            y['url'] = self._url
+        if self._field:
+            y['field'] = self._field
+        if self._maxLen:
+            y['maxLen'] = self._maxLen
+        x['redisStream'] = y
+        return x
+
+
+class AMQPSink(Sink):
+    def __init__(self, routingKey=None, exchange=None, url=None, name=None):
+        super().__init__(name=name)
+        assert exchange or routingKey
+        self._routingKey = routingKey
+        self._exchange = exchange
+        self._url = url
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._exchange:
+            y['exchange'] = self._exchange
+        if self._routingKey:
+            y['routingKey'] = self._routingKey
+        if self._url:
+            y['url'] = self._url
+        x['amqp'] = y
+        return x
+
+
+class FileSink(Sink):
+    def __init__(self, volume, partition=None, format=None, parquetSchema=None, maxSize=None, maxAge=None, sync=None,
+                 onRotate=None, name=None):
+        super().__init__(name=name)
+        assert volume
+        self._volume = volume
+        self._partition = partition
+        self._format = format
+        self._parquetSchema = parquetSchema
+        self._maxSize = maxSize
+        self._maxAge = maxAge
+        self._sync = sync
+        self._onRotate = onRotate
+
+    def dump(self):
+        x = super().dump()
+        y = {'volume': self._volume}
+        if self._partition:
+            y['partition'] = self._partition
+        if self._format:
+            y['format'] = self._format
+        if self._parquetSchema:
+            y['parquetSchema'] = self._parquetSchema
+        if self._maxSize:
+            y['maxSize'] = self._maxSize
+        if self._maxAge:
+            y['maxAge'] = self._maxAge
+        if self._sync:
+            y['sync'] = self._sync
+        if self._onRotate:
+            y['onRotate'] = {'url': self._onRotate}
+        x['file'] = y
+        return x
+
+
+class SnowflakeSink(Sink):
+    def __init__(self, table, columns, batchSize=None, name=None):
+        super().__init__(name=name)
+        assert table
+        assert columns
+        self._table = table
+        self._columns = columns
+        self._batchSize = batchSize
+
+    def dump(self):
+        x = super().dump()
+        y = {'table': self._table, 'columns': self._columns}
+        if self._batchSize:
+            y['batchSize'] = self._batchSize
+        x['snowflake'] = y
+        return x
+
+
+class TestSink(Sink):
+    def __init__(self, capacity=None, name=None):
+        super().__init__(name)
+        self._capacity = capacity
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._capacity:
+            y['capacity'] = self._capacity
+        x['test'] = y
+        return x
+
+
 class Step:
     def __init__(self, name, sources=None, sinks=None, volumes=None, terminator=False, sidecarResource=None):
         self._name = name or 'main'
@@ -221,20 +447,26 @@ class Step:
         self._terminator = terminator
         self._annotations = []
         self._sidecarResources = sidecarResource
+        self._merge = None
+        self._waitForBrokers = None
+        self._state = None
+        self._lifecycle = None
+        self._recommendations = None
 
     def log(self, name=None):
         self._sinks.append(LogSink(name=name))
         return self
 
-    def http(self, url, name=None, insecureSkipVerify=None, headers=None):
+    def http(self, url, name=None, insecureSkipVerify=None, headers=None, dedupe=None):
         self._sinks.append(HTTPSink(
-            url, name=name, insecureSkipVerify=insecureSkipVerify, headers=headers))
+            url, name=name, insecureSkipVerify=insecureSkipVerify, headers=headers, dedupe=dedupe))
         return self
 
     def kafka(self, subject, name=None, a_sync=False, batchSize=None, linger=None, compressionType=None, acks=None,
-              enableIdempotence=None):
+              enableIdempotence=None, eventHubs=None):
         self._sinks.append(KafkaSink(subject, name=name, a_sync=a_sync, batchSize=batchSize, linger=linger,
-                                     compressionType=compressionType, acks=acks, enableIdempotence=enableIdempotence))
+                                     compressionType=compressionType, acks=acks, enableIdempotence=enableIdempotence,
+                                     eventHubs=eventHubs))
         return self
 
     def scale(self, desiredReplicas, scalingDelay=None, peekDelay=None):
@@ -255,6 +487,40 @@ class Step:
         self._sinks.append(JetStreamSink(subject, name=name))
         return self
 
+    def cloudEvents(self, url=None, broker=None, type=None, contentType=None, name=None):
+        self._sinks.append(CloudEventsSink(url=url, broker=broker, type=type, contentType=contentType, name=name))
+        return self
+
+    def dapr(self, binding, operation=None, metadata=None, name=None):
+        self._sinks.append(DaprSink(binding, operation=operation, metadata=metadata, name=name))
+        return self
+
+    def redis(self, channel, url=None, name=None):
+        self._sinks.append(RedisSink(channel, url=url, name=name))
+        return self
+
+    def redisStream(self, stream, url=None, field=None, maxLen=None, name=None):
+        self._sinks.append(RedisStreamSink(stream, url=url, field=field, maxLen=maxLen, name=name))
+        return self
+
+    def amqp(self, routingKey=None, exchange=None, url=None, name=None):
+        self._sinks.append(AMQPSink(routingKey=routingKey, exchange=exchange, url=url, name=name))
+        return self
+
+    def file(self, volume, partition=None, format=None, parquetSchema=None, maxSize=None, maxAge=None, sync=None,
+             onRotate=None, name=None):
+        self._sinks.append(FileSink(volume, partition=partition, format=format, parquetSchema=parquetSchema,
+                                    maxSize=maxSize, maxAge=maxAge, sync=sync, onRotate=onRotate, name=name))
+        return self
+
+    def snowflake(self, table, columns, batchSize=None, name=None):
+        self._sinks.append(SnowflakeSink(table, columns, batchSize=batchSize, name=name))
+        return self
+
+    def test(self, capacity=None, name=None):
+        self._sinks.append(TestSink(capacity=capacity, name=name))
+        return self
+
     def terminator(self):
         self._terminator = True
         return self
@@ -263,6 +529,51 @@ class Step:
         self._annotations = annotations
         return self
 
+    def merge(self, policy=None, concurrency=None):
+        self._merge = {}
+        if policy:
+            self._merge['policy'] = policy
+        if concurrency:
+            self._merge['concurrency'] = concurrency
+        return self
+
+    def waitForBrokers(self, timeout=None, addresses=None):
+        self._waitForBrokers = {}
+        if timeout:
+            self._waitForBrokers['timeout'] = timeout
+        if addresses:
+            self._waitForBrokers['addresses'] = addresses
+        return self
+
+    def state(self, disk=None, redis=None, ttl=None, checkpointInterval=None):
+        assert not (disk and redis)
+        self._state = {}
+        if disk:
+            self._state['disk'] = {'volume': disk}
+        if redis:
+            self._state['redis'] = redis
+        if ttl:
+            self._state['ttl'] = ttl
+        if checkpointInterval:
+            self._state['checkpointInterval'] = checkpointInterval
+        return self
+
+    def lifecycle(self, postStart=None, preStop=None):
+        self._lifecycle = {}
+        if postStart:
+            self._lifecycle['postStart'] = postStart
+        if preStop:
+            self._lifecycle['preStop'] = preStop
+        return self
+
+    def recommendations(self, halfLife=None, apply=False):
+        self._recommendations = {}
+        if halfLife:
+            self._recommendations['halfLife'] = halfLife
+        if apply:
+            self._recommendations['apply'] = True
+        return self
+
     def sidecarResources(self, sidecarResources):
         self._sidecarResources = sidecarResources
         return self
@@ -290,6 +601,16 @@ class Step:
             y['sidecar'] = {
                 'resources': self._sidecarResources
             }
+        if self._merge is not None:
+            y['merge'] = self._merge
+        if self._waitForBrokers is not None:
+            y['waitForBrokers'] = self._waitForBrokers
+        if self._state is not None:
+            y['state'] = self._state
+        if self._lifecycle is not None:
+            y['lifecycle'] = self._lifecycle
+        if self._recommendations is not None:
+            y['recommendations'] = self._recommendations
         return y
 
 
@@ -303,6 +624,91 @@ class CatStep(Step):
         return x
 
 
+class PassthroughStep(Step):
+    def __init__(self, name=None, sources=None, sinks=None):
+        super().__init__(name=name, sources=sources, sinks=sinks)
+
+    def dump(self):
+        x = super().dump()
+        x['passthrough'] = {}
+        return x
+
+
+class SampleStep(Step):
+    def __init__(self, name=None, percent=None, key=None, sources=None, sinks=None):
+        super().__init__(name, sources=sources, sinks=sinks)
+        assert percent is not None
+        self._percent = percent
+        self._key = key
+
+    def dump(self):
+        x = super().dump()
+        y = {'percent': str(self._percent)}
+        if self._key:
+            y['key'] = self._key
+        x['sample'] = y
+        return x
+
+
+class SplitStep(Step):
+    def __init__(self, name=None, delimiter=None, chunkSize=None, sources=None, sinks=None):
+        super().__init__(name, sources=sources, sinks=sinks)
+        assert bool(delimiter) != bool(chunkSize)
+        self._delimiter = delimiter
+        self._chunkSize = chunkSize
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._delimiter:
+            y['delimiter'] = self._delimiter
+        if self._chunkSize:
+            y['chunkSize'] = self._chunkSize
+        x['split'] = y
+        return x
+
+
+class JoinStep(Step):
+    def __init__(self, name=None, left=None, right=None, key=None, window=None, unmatched=None, sources=None,
+                 sinks=None):
+        super().__init__(name, sources=sources, sinks=sinks)
+        assert left
+        assert right
+        assert key
+        self._left = left
+        self._right = right
+        self._key = key
+        self._window = window
+        self._unmatched = unmatched
+        self._lateness = None
+
+    def lateness(self, allowed=None, policy=None, sink=None):
+        self._lateness = {}
+        if allowed:
+            self._lateness['allowed'] = allowed
+        if policy:
+            self._lateness['policy'] = policy
+        if sink:
+            self._lateness['sink'] = sink
+        return self
+
+    def dump(self):
+        x = super().dump()
+        y = {
+            'left': self._left,
+            'right': self._right,
+            'key': self._key
+        }
+        if self._window:
+            y['window'] = self._window
+        if self._unmatched:
+            y['unmatched'] = self._unmatched
+        if self._lateness is not None:
+            y['lateness'] = self._lateness
+        x['join'] = y
+        return x
+
+
 class ContainerStep(Step):
     def __init__(self, name=None, image=None, args=None, fifo=False, volumes=None, volumeMounts=None, sources=None,
                  sinks=None,
@@ -490,6 +896,11 @@ class Source:
     def __init__(self, name=None, retry=None):
         self._name = name
         self._retry = retry
+        self._weight = None
+
+    def weight(self, weight):
+        self._weight = weight
+        return self
 
     def dump(self):
         x = {}
@@ -497,6 +908,8 @@ class Source:
             x['name'] = self._name
         if self._retry:
             x['retry'] = self._retry
+        if self._weight:
+            x['weight'] = self._weight
         return x
 
     def cat(self, name=None):
@@ -532,6 +945,15 @@ class Source:
     def map(self, name=None, expression=None):
         return MapStep(name, expression, sources=[self])
 
+    def passthrough(self, name=None):
+        return PassthroughStep(name, sources=[self])
+
+    def sample(self, name=None, percent=None, key=None):
+        return SampleStep(name, percent, key, sources=[self])
+
+    def split(self, name=None, delimiter=None, chunkSize=None):
+        return SplitStep(name, delimiter, chunkSize, sources=[self])
+
 
 def cat(name=None):
     return CatStep(name)
@@ -571,49 +993,77 @@ def handler(name=None, handler=None, code=None, runtime=None):
     return CodeStep(name, handler, code, runtime)
 
 
+def join(name=None, left=None, right=None, key=None, window=None, unmatched=None, sources=None):
+    return JoinStep(name, left, right, key, window, unmatched, sources=sources)
+
+
 def map(name=None, map=None):
     return MapStep(name, map)
 
 
+def passthrough(name=None):
+    return PassthroughStep(name)
+
+
+def sample(name=None, percent=None, key=None):
+    return SampleStep(name, percent, key)
+
+
+def split(name=None, delimiter=None, chunkSize=None):
+    return SplitStep(name, delimiter, chunkSize)
+
+
 class CronSource(Source):
-    def __init__(self, schedule=None, layout=None, name=None, retry=None):
+    def __init__(self, schedule=None, layout=None, name=None, retry=None, template=None):
         super().__init__(name=name, retry=retry)
         assert schedule
         self._schedule = schedule
         self._layout = layout
+        self._template = template
 
     def dump(self):
         x = super().dump()
         y = {'schedule': self._schedule}
         if self._layout:
             y['layout'] = self._layout
+        if self._template:
+            y['template'] = self._template
         x['cron'] = y
         return x
 
 
 class HTTPSource(Source):
-    def __init__(self, name=None, retry=None, serviceName=None):
+    def __init__(self, name=None, retry=None, serviceName=None, oidc=None, tokenSecret=None):
         super().__init__(name=name, retry=retry)
         self._serviceName = serviceName
+        self._oidc = oidc
+        self._tokenSecret = tokenSecret
 
     def dump(self):
         x = super().dump()
         h = {}
         if self._serviceName:
             h['serviceName'] = self._serviceName
+        if self._oidc:
+            h['oidc'] = self._oidc
+        if self._tokenSecret:
+            h['tokenSecret'] = self._tokenSecret
         x['http'] = h
         return x
 
 
 class KafkaSource(Source):
-    def __init__(self, topic, name=None, retry=None, startOffset=None, fetchMin=None, fetchWaitMax=None, groupId=None):
+    def __init__(self, topic, name=None, retry=None, startOffset=None, fetchMin=None, fetchWaitMax=None, groupId=None,
+                 offsetOutOfRange=None, eventHubs=None):
         super().__init__(name=name, retry=retry)
         assert topic
         self._topic = topic
+        self._eventHubs = eventHubs
         self._startOffset = startOffset
         self._fetchMin = fetchMin
         self._fetchWaitMax = fetchWaitMax
         self._groupId = groupId
+        self._offsetOutOfRange = offsetOutOfRange
 
     def dump(self):
         x = super().dump()
@@ -626,6 +1076,10 @@ class KafkaSource(Source):
             y["fetchWaitMax"] = self._fetchWaitMax
         if self._groupId:
             y["groupId"] = self._groupId
+        if self._offsetOutOfRange:
+            y["offsetOutOfRange"] = self._offsetOutOfRange
+        if self._eventHubs:
+            y["eventHubs"] = self._eventHubs
         x['kafka'] = y
         return x
 
@@ -656,17 +1110,295 @@ class JetStreamSource(Source):
         return x
 
 
-def cron(schedule=None, layout=None, name=None, retry=None):
-    return CronSource(schedule, layout=layout, name=name, retry=retry)
+class ArgoEventsSource(Source):
+    def __init__(self, eventSourceName, eventName=None, eventBusName=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert eventSourceName
+        self._eventSourceName = eventSourceName
+        self._eventName = eventName
+        self._eventBusName = eventBusName
 
+    def dump(self):
+        x = super().dump()
+        y = {'eventSourceName': self._eventSourceName}
+        if self._eventName:
+            y['eventName'] = self._eventName
+        if self._eventBusName:
+            y['eventBusName'] = self._eventBusName
+        x['argoEvents'] = y
+        return x
 
-def http(name=None, retry=None, serviceName=None):
-    return HTTPSource(name=name, serviceName=serviceName, retry=retry)
 
+class DaprSource(Source):
+    def __init__(self, binding, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert binding
+        self._binding = binding
+
+    def dump(self):
+        x = super().dump()
+        x['dapr'] = {'binding': self._binding}
+        return x
+
+
+class ElasticsearchSource(Source):
+    def __init__(self, index, url=None, query=None, pageSize=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert index
+        self._index = index
+        self._url = url
+        self._query = query
+        self._pageSize = pageSize
+
+    def dump(self):
+        x = super().dump()
+        y = {'index': self._index}
+        if self._url:
+            y['url'] = self._url
+        if self._query:
+            y['query'] = self._query
+        if self._pageSize:
+            y['pageSize'] = self._pageSize
+        x['elasticsearch'] = y
+        return x
+
+
+class GeneratorSource(Source):
+    def __init__(self, template=None, rate=None, count=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        self._template = template
+        self._rate = rate
+        self._count = count
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._template:
+            y['template'] = self._template
+        if self._rate:
+            y['rate'] = self._rate
+        if self._count:
+            y['count'] = self._count
+        x['generator'] = y
+        return x
+
+
+class RedisSource(Source):
+    def __init__(self, channel, url=None, pattern=False, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert channel
+        self._channel = channel
+        self._url = url
+        self._pattern = pattern
+
+    def dump(self):
+        x = super().dump()
+        y = {'channel': self._channel}
+        if self._url:
+            y['url'] = self._url
+        if self._pattern:
+            y['pattern'] = True
+        x['redis'] = y
+        return x
+
+
+class RedisStreamSource(Source):
+    def __init__(self, stream, url=None, field=None, group=None, startId=None, count=None, minIdleTime=None,
+                 maxDeliveries=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert stream
+        self._stream = stream
+        self._url = url
+        self._field = field
+        self._group = group
+        self._startId = startId
+        self._count = count
+        self._minIdleTime = minIdleTime
+        self._maxDeliveries = maxDeliveries
+
+    def dump(self):
+        x = super().dump()
+        y = {'stream': self._stream}
+        if self._url:
+            y['url'] = self._url
+        if self._field:
+            y['field'] = self._field
+        if self._group:
+            y['group'] = self._group
+        if self._startId:
+            y['startId'] = self._startId
+        if self._count:
+            y['count'] = self._count
+        if self._minIdleTime:
+            y['minIdleTime'] = self._minIdleTime
+        if self._maxDeliveries:
+            y['maxDeliveries'] = self._maxDeliveries
+        x['redisStream'] = y
+        return x
+
+
+class SQSSource(Source):
+    def __init__(self, queueUrl=None, region=None, waitTime=None, visibilityTimeout=None, maxMessages=None, name=None,
+                 retry=None):
+        super().__init__(name=name, retry=retry)
+        self._queueUrl = queueUrl
+        self._region = region
+        self._waitTime = waitTime
+        self._visibilityTimeout = visibilityTimeout
+        self._maxMessages = maxMessages
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._queueUrl:
+            y['queueUrl'] = self._queueUrl
+        if self._region:
+            y['region'] = self._region
+        if self._waitTime:
+            y['waitTime'] = self._waitTime
+        if self._visibilityTimeout:
+            y['visibilityTimeout'] = self._visibilityTimeout
+        if self._maxMessages:
+            y['maxMessages'] = self._maxMessages
+        x['sqs'] = y
+        return x
+
+
+class KinesisSource(Source):
+    def __init__(self, stream, region=None, startPosition=None, pollPeriod=None, limit=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert stream
+        self._stream = stream
+        self._region = region
+        self._startPosition = startPosition
+        self._pollPeriod = pollPeriod
+        self._limit = limit
 
-def kafka(topic=None, name=None, retry=None, startOffset=None, fetchMin=None, fetchWaitMax=None, groupId=None):
+    def dump(self):
+        x = super().dump()
+        y = {'stream': self._stream}
+        if self._region:
+            y['region'] = self._region
+        if self._startPosition:
+            y['startPosition'] = self._startPosition
+        if self._pollPeriod:
+            y['pollPeriod'] = self._pollPeriod
+        if self._limit:
+            y['limit'] = self._limit
+        x['kinesis'] = y
+        return x
+
+
+class AMQPSource(Source):
+    def __init__(self, queue, url=None, prefetch=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert queue
+        self._queue = queue
+        self._url = url
+        self._prefetch = prefetch
+
+    def dump(self):
+        x = super().dump()
+        y = {'queue': self._queue}
+        if self._url:
+            y['url'] = self._url
+        if self._prefetch:
+            y['prefetch'] = self._prefetch
+        x['amqp'] = y
+        return x
+
+
+class MQTTSource(Source):
+    def __init__(self, topic, url=None, qos=None, username=None, tls=None, protocolVersion=None, name=None,
+                 retry=None):
+        super().__init__(name=name, retry=retry)
+        assert topic
+        self._topic = topic
+        self._url = url
+        self._qos = qos
+        self._username = username
+        self._tls = tls
+        self._protocolVersion = protocolVersion
+
+    def dump(self):
+        x = super().dump()
+        y = {'topic': self._topic}
+        if self._url:
+            y['url'] = self._url
+        if self._qos is not None:
+            y['qos'] = self._qos
+        if self._username:
+            y['username'] = self._username
+        if self._tls:
+            y['tls'] = self._tls
+        if self._protocolVersion:
+            y['protocolVersion'] = self._protocolVersion
+        x['mqtt'] = y
+        return x
+
+
+class S3Source(Source):
+    def __init__(self, bucket, region=None, prefix=None, emit=None, pollPeriod=None, concurrency=None, onProcessed=None,
+                 notifications=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        assert bucket
+        self._bucket = bucket
+        self._region = region
+        self._prefix = prefix
+        self._emit = emit
+        self._pollPeriod = pollPeriod
+        self._concurrency = concurrency
+        self._onProcessed = onProcessed
+        self._notifications = notifications
+
+    def dump(self):
+        x = super().dump()
+        y = {'bucket': self._bucket}
+        if self._region:
+            y['region'] = self._region
+        if self._prefix:
+            y['prefix'] = self._prefix
+        if self._emit:
+            y['emit'] = self._emit
+        if self._pollPeriod:
+            y['pollPeriod'] = self._pollPeriod
+        if self._concurrency:
+            y['concurrency'] = self._concurrency
+        if self._onProcessed:
+            y['onProcessed'] = self._onProcessed
+        if self._notifications:
+            y['notifications'] = self._notifications
+        x['s3'] = y
+        return x
+
+
+class TestSource(Source):
+    def __init__(self, capacity=None, name=None, retry=None):
+        super().__init__(name=name, retry=retry)
+        self._capacity = capacity
+
+    def dump(self):
+        x = super().dump()
+        y = {}
+        if self._capacity:
+            y['capacity'] = self._capacity
+        x['test'] = y
+        return x
+
+
+def cron(schedule=None, layout=None, name=None, retry=None, template=None):
+    return CronSource(schedule, layout=layout, name=name, retry=retry, template=template)
+
+
+def http(name=None, retry=None, serviceName=None, oidc=None, tokenSecret=None):
+    return HTTPSource(name=name, serviceName=serviceName, retry=retry, oidc=oidc, tokenSecret=tokenSecret)
+
+
+def kafka(topic=None, name=None, retry=None, startOffset=None, fetchMin=None, fetchWaitMax=None, groupId=None,
+          offsetOutOfRange=None, eventHubs=None):
     return KafkaSource(topic, name=name, retry=retry, startOffset=startOffset, fetchMin=fetchMin,
-                       fetchWaitMax=fetchWaitMax, groupId=groupId)
+                       fetchWaitMax=fetchWaitMax, groupId=groupId, offsetOutOfRange=offsetOutOfRange,
+                       eventHubs=eventHubs)
 
 
 def stan(subject=None, name=None, retry=None):
@@ -675,3 +1407,58 @@ def stan(subject=None, name=None, retry=None):
 
 def jetstream(subject=None, name=None, retry=None):
     return JetStreamSource(subject, name, retry=retry)
+
+
+def dapr(binding=None, name=None, retry=None):
+    return DaprSource(binding, name=name, retry=retry)
+
+
+def elasticsearch(index=None, url=None, query=None, pageSize=None, name=None, retry=None):
+    return ElasticsearchSource(index, url=url, query=query, pageSize=pageSize, name=name, retry=retry)
+
+
+def generator(template=None, rate=None, count=None, name=None, retry=None):
+    return GeneratorSource(template=template, rate=rate, count=count, name=name, retry=retry)
+
+
+def redis(channel=None, url=None, pattern=False, name=None, retry=None):
+    return RedisSource(channel, url=url, pattern=pattern, name=name, retry=retry)
+
+
+def redisStream(stream=None, url=None, field=None, group=None, startId=None, count=None, minIdleTime=None,
+                maxDeliveries=None, name=None, retry=None):
+    return RedisStreamSource(stream, url=url, field=field, group=group, startId=startId, count=count,
+                             minIdleTime=minIdleTime, maxDeliveries=maxDeliveries, name=name, retry=retry)
+
+
+def sqs(queueUrl=None, region=None, waitTime=None, visibilityTimeout=None, maxMessages=None, name=None, retry=None):
+    return SQSSource(queueUrl=queueUrl, region=region, waitTime=waitTime, visibilityTimeout=visibilityTimeout,
+                     maxMessages=maxMessages, name=name, retry=retry)
+
+
+def kinesis(stream=None, region=None, startPosition=None, pollPeriod=None, limit=None, name=None, retry=None):
+    return KinesisSource(stream, region=region, startPosition=startPosition, pollPeriod=pollPeriod, limit=limit,
+                         name=name, retry=retry)
+
+
+def amqp(queue=None, url=None, prefetch=None, name=None, retry=None):
+    return AMQPSource(queue, url=url, prefetch=prefetch, name=name, retry=retry)
+
+
+def mqtt(topic=None, url=None, qos=None, username=None, tls=None, protocolVersion=None, name=None, retry=None):
+    return MQTTSource(topic, url=url, qos=qos, username=username, tls=tls, protocolVersion=protocolVersion, name=name,
+                      retry=retry)
+
+
+def s3(bucket=None, region=None, prefix=None, emit=None, pollPeriod=None, concurrency=None, onProcessed=None,
+       notifications=None, name=None, retry=None):
+    return S3Source(bucket, region=region, prefix=prefix, emit=emit, pollPeriod=pollPeriod, concurrency=concurrency,
+                    onProcessed=onProcessed, notifications=notifications, name=name, retry=retry)
+
+
+def test(capacity=None, name=None, retry=None):
+    return TestSource(capacity=capacity, name=name, retry=retry)
+
+
+def argoEvents(eventSourceName=None, eventName=None, eventBusName=None, name=None, retry=None):
+    return ArgoEventsSource(eventSourceName, eventName=eventName, eventBusName=eventBusName, name=name, retry=retry)
@@ -0,0 +1,16 @@
+from argo_dataflow import generator, pipeline
+
+if __name__ == '__main__':
+    (pipeline("101-generator")
+     .owner('argoproj-labs')
+     .namespace('argo-dataflow-system')
+     .describe("""This example generates fake data, so you can try out a pipeline without a producer.
+
+Each message is a templated JSON object, with a sequence number and a fake name and email.""")
+     .annotate('dataflow.argoproj.io/test', "true")
+     .step(
+        (generator('{"id": {{seq}}, "name": "{{name}}", "email": "{{email}}"}', rate=10)
+         .cat()
+         .log())
+    )
+        .save())
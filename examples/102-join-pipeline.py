@@ -0,0 +1,19 @@
+from argo_dataflow import join, kafka, pipeline
+
+if __name__ == '__main__':
+    (pipeline("102-join")
+     .owner('argoproj-labs')
+     .describe("""This is an example of a built-in join.
+
+It correlates orders with their payments by order ID. Each message waits up to a minute for its match, and
+unmatched messages are sent on by themselves.
+
+Messages wait for their match in memory, so the step must have a single replica.
+
+[Learn about expressions](../docs/EXPRESSIONS.md)""")
+     .step(
+        join(left='orders', right='payments', key='string(object(msg).orderId)', window='1m', unmatched='Emit',
+             sources=[kafka('orders', name='orders'), kafka('payments', name='payments')])
+        .kafka('paid-orders')
+    )
+        .save())
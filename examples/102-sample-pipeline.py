@@ -0,0 +1,19 @@
+from argo_dataflow import kafka, pipeline
+
+if __name__ == '__main__':
+    (pipeline("102-sample")
+     .owner('argoproj-labs')
+     .describe("""This is an example of built-in sampling.
+
+It keeps 10% of messages, e.g. to send a downsampled copy of a high-volume topic to a debug topic.
+
+If you specify a key expression, messages are kept by the hash of their key, so either all the messages with a key
+are kept, or none of them are.
+
+[Learn about expressions](../docs/EXPRESSIONS.md)""")
+     .step(
+        kafka('input-topic')
+        .sample(percent=10, key='string(msg)')
+        .kafka('output-topic')
+    )
+        .save())
@@ -0,0 +1,17 @@
+from argo_dataflow import kafka, pipeline
+
+if __name__ == '__main__':
+    (pipeline("102-split")
+     .owner('argoproj-labs')
+     .describe("""This is an example of built-in splitting.
+
+It splits each message on commas, and sends each part as a message. Alternatively, you can split messages into
+chunks of a fixed size, using `chunkSize`.
+
+Each part has the ID `{id}/{index}-of-{count}`, so you can relate the parts of a message downstream.""")
+     .step(
+        kafka('input-topic')
+        .split(delimiter=',')
+        .kafka('output-topic')
+    )
+        .save())
@@ -0,0 +1,17 @@
+from argo_dataflow import pipeline, redis
+
+if __name__ == '__main__':
+    (pipeline("301-redis")
+     .owner('argoproj-labs')
+     .describe("""This example shows reading and writing to Redis pub/sub channels.
+
+* Messages are not persisted, so they are lost if the step is not subscribed when they are published.
+* Every replica receives every message, so do not add replicas.
+""")
+     .annotate('dataflow.argoproj.io/test', 'false')
+     .step(
+        (redis('input-channel')
+         .cat()
+         .redis('output-channel')
+         ))
+     .save())
@@ -8,7 +8,7 @@ if __name__ == '__main__':
      .step(
         CatStep(
             sources=[KafkaSource(
-                'input-topic'), CronSource(schedule='*/3 * * * * *', layout="15:04:05")],
+                'input-topic', name='kafka'), CronSource(schedule='*/3 * * * * *', layout="15:04:05", name='cron')],
             sinks=[LogSink()]
         )
     )
@@ -0,0 +1,63 @@
+// stepdefaults prints the parts of the step CRD's schema that have defaults, so that the runner can apply the same
+// defaults as the API server when running a step locally.
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+type schema struct {
+	Default    interface{}        `json:"default,omitempty"`
+	Properties map[string]*schema `json:"properties,omitempty"`
+	Items      *schema            `json:"items,omitempty"`
+}
+
+// prune returns nil if neither the schema, nor any of its children, has a default
+func prune(s *schema) *schema {
+	if s == nil {
+		return nil
+	}
+	out := &schema{Default: s.Default, Items: prune(s.Items)}
+	for k, v := range s.Properties {
+		if v := prune(v); v != nil {
+			if out.Properties == nil {
+				out.Properties = map[string]*schema{}
+			}
+			out.Properties[k] = v
+		}
+	}
+	if out.Default == nil && out.Properties == nil && out.Items == nil {
+		return nil
+	}
+	return out
+}
+
+func main() {
+	data, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		panic(err)
+	}
+	crd := struct {
+		Spec struct {
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema schema `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}{}
+	if err := yaml.Unmarshal(data, &crd); err != nil {
+		panic(err)
+	}
+	out, err := json.MarshalIndent(prune(crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"]), "", "  ")
+	if err != nil {
+		panic(err)
+	}
+	if err := ioutil.WriteFile(os.Args[2], append(out, '\n'), 0o644); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// NamespaceLimits bound the resources a namespace's pipelines may use, so platform teams can bound each tenant's
+// usage. They are enforced by the validating webhook, when pipelines are created or updated. Zero is unlimited.
+type NamespaceLimits struct {
+	// MaxPipelines is the most pipelines the namespace may have, including completed ones.
+	MaxPipelines int `json:"maxPipelines,omitempty"`
+	// MaxStepReplicas is the most replicas the steps of the namespace's running pipelines may have in total. A step
+	// counts as its scale.maxReplicas if it has one, otherwise its replicas, so steps that scale must have
+	// scale.maxReplicas.
+	MaxStepReplicas int `json:"maxStepReplicas,omitempty"`
+	// MaxPending is the most pending messages the namespace's steps may have in total before pipelines are admitted
+	// with a warning, e.g. because more pipelines are unlikely to help.
+	MaxPending uint64 `json:"maxPending,omitempty"`
+}
+
+// getNamespaceLimits returns the namespace's limits, or those of "*" if the namespace does not have any.
+func getNamespaceLimits(namespaceLimits map[string]NamespaceLimits, namespace string) (NamespaceLimits, bool) {
+	if x, ok := namespaceLimits[namespace]; ok {
+		return x, true
+	}
+	x, ok := namespaceLimits["*"]
+	return x, ok
+}
+
+// stepReplicas returns the most replicas the pipeline's steps may have in total.
+func stepReplicas(pipeline dfv1.Pipeline) (int, error) {
+	// scheduled pipelines do not run steps themselves, their runs are pipelines that do
+	if pipeline.Spec.Schedule != nil || pipeline.Status.Phase.Completed() {
+		return 0, nil
+	}
+	steps, err := pipeline.Spec.GetSteps()
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, step := range steps {
+		if step.Scale.MaxReplicas > 0 {
+			n += int(step.Scale.MaxReplicas)
+		} else if step.Scale.DesiredReplicas != "" {
+			return 0, fmt.Errorf("step %q must have scale.maxReplicas, as the namespace limits step replicas", step.Name)
+		} else {
+			n += int(step.Replicas)
+		}
+	}
+	return n, nil
+}
+
+// admittedReplicas returns the most replicas the step may have when the namespace limits step replicas, i.e. what it
+// counted as when its pipeline was admitted, so scaling the step (e.g. `kubectl scale step`) cannot bypass the limit.
+// It returns false if the step is not limited, e.g. because it does not have a pipeline.
+func admittedReplicas(step dfv1.Step, pipeline *dfv1.Pipeline) (int, bool) {
+	if pipeline == nil {
+		return 0, false
+	}
+	steps, err := pipeline.Spec.GetSteps()
+	if err != nil {
+		return 0, false
+	}
+	for _, x := range steps {
+		if x.Name != step.Spec.Name {
+			continue
+		}
+		if x.Scale.MaxReplicas > 0 {
+			return int(x.Scale.MaxReplicas), true
+		}
+		return int(x.Replicas), true
+	}
+	return 0, false
+}
+
+// admit returns why the pipeline must not be admitted, or "" if it may be, with any warnings. The pipelines and steps
+// are those already in the namespace, old is the pipeline being updated, if it is being updated.
+func (in NamespaceLimits) admit(pipeline dfv1.Pipeline, old *dfv1.Pipeline, pipelines []dfv1.Pipeline, steps []dfv1.Step) (string, []string) {
+	var others []dfv1.Pipeline
+	for _, x := range pipelines {
+		if x.Name != pipeline.Name {
+			others = append(others, x)
+		}
+	}
+	if in.MaxPipelines > 0 && old == nil && len(others) >= in.MaxPipelines {
+		return fmt.Sprintf("namespace %q may have at most %d pipelines", pipeline.Namespace, in.MaxPipelines), nil
+	}
+	if in.MaxStepReplicas > 0 {
+		n, err := stepReplicas(pipeline)
+		if err != nil {
+			return err.Error(), nil
+		}
+		oldN := 0
+		if old != nil {
+			oldN, _ = stepReplicas(*old)
+		}
+		total := n
+		for _, x := range others {
+			m, _ := stepReplicas(x)
+			total += m
+		}
+		// a namespace may be over its limit, e.g. because it was lowered, so updates that do not add replicas are allowed
+		if total > in.MaxStepReplicas && n > oldN {
+			return fmt.Sprintf("namespace %q may have at most %d step replicas, this pipeline would bring it to %d", pipeline.Namespace, in.MaxStepReplicas, total), nil
+		}
+	}
+	var warnings []string
+	if in.MaxPending > 0 {
+		var pending uint64
+		for _, step := range steps {
+			for _, s := range step.Status.Sources {
+				pending += s.Pending
+			}
+		}
+		if pending > in.MaxPending {
+			warnings = append(warnings, fmt.Sprintf("namespace %q has %d pending messages, more than the %d allowed", pipeline.Namespace, pending, in.MaxPending))
+		}
+	}
+	return "", warnings
+}
+
+// +kubebuilder:webhook:path=/validate-dataflow-argoproj-io-v1alpha1-pipeline,mutating=false,failurePolicy=fail,sideEffects=None,groups=dataflow.argoproj.io,resources=pipelines,verbs=create;update,versions=v1alpha1,name=vpipeline.dataflow.argoproj.io,admissionReviewVersions={v1,v1beta1}
+
+// PipelineValidator is the validating webhook that enforces the namespace limits, see EnvNamespaceLimits.
+type PipelineValidator struct {
+	Client  client.Client
+	decoder *admission.Decoder
+}
+
+func (v *PipelineValidator) InjectDecoder(d *admission.Decoder) error {
+	v.decoder = d
+	return nil
+}
+
+func (v *PipelineValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	limits, ok := getNamespaceLimits(getConfig().namespaceLimits, req.Namespace)
+	if !ok {
+		return admission.Allowed("")
+	}
+	pipeline := dfv1.Pipeline{}
+	if err := v.decoder.Decode(req, &pipeline); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	pipeline.Namespace = req.Namespace
+	var old *dfv1.Pipeline
+	if req.Operation == admissionv1.Update {
+		old = &dfv1.Pipeline{}
+		if err := v.decoder.DecodeRaw(req.OldObject, old); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	pipelines := &dfv1.PipelineList{}
+	if err := v.Client.List(ctx, pipelines, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	steps := &dfv1.StepList{}
+	if err := v.Client.List(ctx, steps, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	if reason, warnings := limits.admit(pipeline, old, pipelines.Items, steps.Items); reason != "" {
+		logger.Info("denying pipeline", "namespace", req.Namespace, "name", pipeline.Name, "reason", reason)
+		return admission.Denied(reason)
+	} else {
+		return admission.Allowed("").WithWarnings(warnings...)
+	}
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newLimitedPipeline(name string, replicas ...uint32) dfv1.Pipeline {
+	pipeline := dfv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: name}}
+	for _, n := range replicas {
+		pipeline.Spec.Steps = append(pipeline.Spec.Steps, dfv1.StepSpec{Name: "main", Replicas: n})
+	}
+	return pipeline
+}
+
+func Test_getNamespaceLimits(t *testing.T) {
+	limits := map[string]NamespaceLimits{"my-ns": {MaxPipelines: 1}, "*": {MaxPipelines: 2}}
+	x, ok := getNamespaceLimits(limits, "my-ns")
+	assert.True(t, ok)
+	assert.Equal(t, 1, x.MaxPipelines)
+	x, ok = getNamespaceLimits(limits, "other-ns")
+	assert.True(t, ok)
+	assert.Equal(t, 2, x.MaxPipelines)
+	_, ok = getNamespaceLimits(nil, "my-ns")
+	assert.False(t, ok)
+}
+
+func Test_stepReplicas(t *testing.T) {
+	t.Run("Replicas", func(t *testing.T) {
+		n, err := stepReplicas(newLimitedPipeline("a", 1, 2))
+		assert.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+	t.Run("MaxReplicas", func(t *testing.T) {
+		pipeline := newLimitedPipeline("a", 1)
+		pipeline.Spec.Steps[0].Scale = dfv1.Scale{DesiredReplicas: "2", MaxReplicas: 4}
+		n, err := stepReplicas(pipeline)
+		assert.NoError(t, err)
+		assert.Equal(t, 4, n)
+	})
+	t.Run("NoMaxReplicas", func(t *testing.T) {
+		pipeline := newLimitedPipeline("a", 1)
+		pipeline.Spec.Steps[0].Scale = dfv1.Scale{DesiredReplicas: "2"}
+		_, err := stepReplicas(pipeline)
+		assert.EqualError(t, err, `step "main" must have scale.maxReplicas, as the namespace limits step replicas`)
+	})
+	t.Run("Completed", func(t *testing.T) {
+		pipeline := newLimitedPipeline("a", 1)
+		pipeline.Status.Phase = dfv1.PipelineSucceeded
+		n, err := stepReplicas(pipeline)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+	t.Run("Scheduled", func(t *testing.T) {
+		pipeline := newLimitedPipeline("a", 1)
+		pipeline.Spec.Schedule = &dfv1.PipelineSchedule{}
+		n, err := stepReplicas(pipeline)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, n)
+	})
+}
+
+func Test_admittedReplicas(t *testing.T) {
+	step := dfv1.Step{Spec: dfv1.StepSpec{Name: "main", Replicas: 10}}
+	_, ok := admittedReplicas(step, nil)
+	assert.False(t, ok, "a step without a pipeline is not limited")
+	pipeline := newLimitedPipeline("a", 2)
+	n, ok := admittedReplicas(step, &pipeline)
+	assert.True(t, ok)
+	assert.Equal(t, 2, n)
+	pipeline.Spec.Steps[0].Scale = dfv1.Scale{DesiredReplicas: "2", MaxReplicas: 4}
+	n, ok = admittedReplicas(step, &pipeline)
+	assert.True(t, ok)
+	assert.Equal(t, 4, n)
+	step.Spec.Name = "other"
+	_, ok = admittedReplicas(step, &pipeline)
+	assert.False(t, ok)
+}
+
+func TestNamespaceLimits_admit(t *testing.T) {
+	t.Run("Unlimited", func(t *testing.T) {
+		reason, warnings := NamespaceLimits{}.admit(newLimitedPipeline("b", 10), nil, []dfv1.Pipeline{newLimitedPipeline("a", 10)}, nil)
+		assert.Empty(t, reason)
+		assert.Empty(t, warnings)
+	})
+	t.Run("MaxPipelines", func(t *testing.T) {
+		limits := NamespaceLimits{MaxPipelines: 1}
+		existing := []dfv1.Pipeline{newLimitedPipeline("a", 1)}
+		reason, _ := limits.admit(newLimitedPipeline("b", 1), nil, existing, nil)
+		assert.Equal(t, `namespace "my-ns" may have at most 1 pipelines`, reason)
+		old := newLimitedPipeline("a", 1)
+		reason, _ = limits.admit(newLimitedPipeline("a", 2), &old, existing, nil)
+		assert.Empty(t, reason)
+	})
+	t.Run("MaxStepReplicas", func(t *testing.T) {
+		limits := NamespaceLimits{MaxStepReplicas: 3}
+		existing := []dfv1.Pipeline{newLimitedPipeline("a", 2)}
+		reason, _ := limits.admit(newLimitedPipeline("b", 1), nil, existing, nil)
+		assert.Empty(t, reason)
+		reason, _ = limits.admit(newLimitedPipeline("b", 2), nil, existing, nil)
+		assert.Equal(t, `namespace "my-ns" may have at most 3 step replicas, this pipeline would bring it to 4`, reason)
+	})
+	t.Run("OverMaxStepReplicas", func(t *testing.T) {
+		limits := NamespaceLimits{MaxStepReplicas: 1}
+		existing := []dfv1.Pipeline{newLimitedPipeline("a", 2), newLimitedPipeline("b", 2)}
+		old := newLimitedPipeline("b", 2)
+		reason, _ := limits.admit(newLimitedPipeline("b", 1), &old, existing, nil)
+		assert.Empty(t, reason)
+		reason, _ = limits.admit(newLimitedPipeline("b", 3), &old, existing, nil)
+		assert.Equal(t, `namespace "my-ns" may have at most 1 step replicas, this pipeline would bring it to 5`, reason)
+	})
+	t.Run("MaxPending", func(t *testing.T) {
+		limits := NamespaceLimits{MaxPending: 10}
+		steps := []dfv1.Step{{Status: dfv1.StepStatus{Sources: []dfv1.SourceStatus{{Pending: 6}, {Pending: 5}}}}}
+		reason, warnings := limits.admit(newLimitedPipeline("b", 1), nil, nil, steps)
+		assert.Empty(t, reason)
+		assert.Equal(t, []string{`namespace "my-ns" has 11 pending messages, more than the 10 allowed`}, warnings)
+	})
+}
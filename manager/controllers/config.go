@@ -1,40 +1,155 @@
 package controllers
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/manager/controllers/scaling"
 	"github.com/argoproj-labs/argo-dataflow/shared/util"
 	corev1 "k8s.io/api/core/v1"
 )
 
+// config is the controller's configuration. Each value is read from the dataflow-controller-config ConfigMap, if it
+// has a key of the same name as the environment variable, otherwise from the environment variable. The ConfigMap is
+// watched, so changes to it take effect at the next reconciliation, without restarting the controller.
+type config struct {
+	imageFormat      string
+	runnerImage      string
+	pullPolicy       corev1.PullPolicy
+	updateInterval   time.Duration
+	imagePullSecrets []string
+	networkPolicy    bool
+	restricted       bool
+	faultInjection   bool
+	podRetention     time.Duration
+	namespaceRunners map[string]dfv1.Runner
+	namespaceLimits  map[string]NamespaceLimits
+	initResources    *corev1.ResourceRequirements
+	scalingDelay     time.Duration
+	peekDelay        time.Duration
+	vaultAddr        string // secrets may be read from Vault, so the network policy must allow egress to it
+}
+
 var (
-	imagePrefix      = os.Getenv(dfv1.EnvImagePrefix)
-	imageFormat      = ""
-	runnerImage      = ""
-	pullPolicy       = corev1.PullPolicy(os.Getenv(dfv1.EnvPullPolicy))
-	updateInterval   = util.GetEnvDuration(dfv1.EnvUpdateInterval, 15*time.Second)
-	logger           = util.NewLogger()
-	imagePullSecrets = util.GetEnvStringArr(dfv1.EnvImagePullSecrets, []string{})
+	logger = util.NewLogger()
+	// the service mesh is also passed to the sidecars as an environment variable, so it cannot be reloaded
+	serviceMesh = dfv1.ServiceMesh(os.Getenv(dfv1.EnvServiceMesh))
+	// the config is reloaded while reconcilers are using it, so it is guarded by a mutex
+	currentConfigMu sync.RWMutex
+	currentConfig   config
 )
 
 func init() {
-	if imagePrefix == "" {
-		imagePrefix = "quay.io/argoprojlabs"
+	x, err := loadConfig(os.LookupEnv)
+	if err != nil {
+		panic(err)
+	}
+	logger.Info("service mesh", "serviceMesh", serviceMesh)
+	setConfig(x)
+}
+
+func getConfig() config {
+	currentConfigMu.RLock()
+	defer currentConfigMu.RUnlock()
+	return currentConfig
+}
+
+func setConfig(x config) {
+	currentConfigMu.Lock()
+	defer currentConfigMu.Unlock()
+	currentConfig = x
+	scaling.SetDefaultDelays(x.scalingDelay, x.peekDelay)
+	logger.Info("reconciler config",
+		"imageFormat", x.imageFormat,
+		"runnerImage", x.runnerImage,
+		"pullPolicy", x.pullPolicy,
+		"updateInterval", x.updateInterval.String(),
+		"imagePullSecrets", x.imagePullSecrets,
+		"networkPolicy", x.networkPolicy,
+		"restricted", x.restricted,
+		"faultInjection", x.faultInjection,
+		"podRetention", x.podRetention.String(),
+		"namespaceRunners", x.namespaceRunners,
+		"namespaceLimits", x.namespaceLimits,
+		"initResources", x.initResources,
+		"scalingDelay", x.scalingDelay.String(),
+		"peekDelay", x.peekDelay.String(),
+		"vaultAddr", x.vaultAddr,
+	)
+}
+
+// loadConfig loads the configuration, looking up each value by the name of its environment variable.
+func loadConfig(lookup func(key string) (string, bool)) (config, error) {
+	var errs []string
+	str := func(key, def string) string {
+		if v, ok := lookup(key); ok {
+			return v
+		}
+		return def
+	}
+	duration := func(key string, def time.Duration) time.Duration {
+		if x, ok := lookup(key); ok {
+			if v, err := time.ParseDuration(x); err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%s; value must be duration: %v", key, x, err))
+			} else {
+				return v
+			}
+		}
+		return def
+	}
+	boolean := func(key string, def bool) bool {
+		if x, ok := lookup(key); ok {
+			if v, err := strconv.ParseBool(x); err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%s; value must be bool: %v", key, x, err))
+			} else {
+				return v
+			}
+		}
+		return def
 	}
+	object := func(key string, v interface{}) {
+		if x, ok := lookup(key); ok {
+			if err := json.Unmarshal([]byte(x), v); err != nil {
+				errs = append(errs, fmt.Sprintf("%s=%s; value must be JSON: %v", key, x, err))
+			}
+		}
+	}
+
+	imagePrefix := str(dfv1.EnvImagePrefix, "quay.io/argoprojlabs")
 	tag := util.Version.Original() // we don't use String() because semantic version do not have "v" prefix
 	if tag == "v0.0.0-latest-0" {
 		tag = "latest"
 	}
-	imageFormat = fmt.Sprintf("%s/%s:%s", imagePrefix, "%s", tag)
-	runnerImage = fmt.Sprintf(imageFormat, "dataflow-runner")
-	logger.Info("reconciler config",
-		"imageFormat", imageFormat,
-		"runnerImage", runnerImage,
-		"pullPolicy", pullPolicy,
-		"updateInterval", updateInterval.String(),
-		"imagePullSecrets", imagePullSecrets,
-	)
+	x := config{
+		imageFormat:      fmt.Sprintf("%s/%s:%s", imagePrefix, "%s", tag),
+		pullPolicy:       corev1.PullPolicy(str(dfv1.EnvPullPolicy, "")),
+		updateInterval:   duration(dfv1.EnvUpdateInterval, 15*time.Second),
+		imagePullSecrets: []string{},
+		networkPolicy:    boolean(dfv1.EnvNetworkPolicy, false),
+		restricted:       boolean(dfv1.EnvRestricted, false),
+		faultInjection:   boolean(dfv1.EnvFaultInjection, false),
+		podRetention:     duration(dfv1.EnvPodRetention, time.Hour),
+		namespaceRunners: map[string]dfv1.Runner{},
+		namespaceLimits:  map[string]NamespaceLimits{},
+		scalingDelay:     duration(dfv1.EnvScalingDelay, time.Minute),
+		peekDelay:        duration(dfv1.EnvPeekDelay, 4*time.Minute),
+		vaultAddr:        str(dfv1.EnvVaultAddr, ""),
+	}
+	x.runnerImage = str(dfv1.EnvRunnerImage, fmt.Sprintf(x.imageFormat, "dataflow-runner"))
+	if v, ok := lookup(dfv1.EnvImagePullSecrets); ok {
+		x.imagePullSecrets = strings.Split(v, ",")
+	}
+	object(dfv1.EnvNamespaceRunners, &x.namespaceRunners)
+	object(dfv1.EnvNamespaceLimits, &x.namespaceLimits)
+	object(dfv1.EnvInitResources, &x.initResources)
+	if len(errs) > 0 {
+		return config{}, fmt.Errorf("invalid config: %s", strings.Join(errs, ", "))
+	}
+	return x, nil
 }
@@ -0,0 +1,81 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func lookupMap(m map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := m[key]
+		return v, ok
+	}
+}
+
+func Test_loadConfig(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		x, err := loadConfig(lookupMap(nil))
+		assert.NoError(t, err)
+		assert.Equal(t, "quay.io/argoprojlabs/%s:latest", x.imageFormat)
+		assert.Equal(t, "quay.io/argoprojlabs/dataflow-runner:latest", x.runnerImage)
+		assert.Equal(t, 15*time.Second, x.updateInterval)
+		assert.Equal(t, time.Hour, x.podRetention)
+		assert.Equal(t, time.Minute, x.scalingDelay)
+		assert.Equal(t, 4*time.Minute, x.peekDelay)
+		assert.Empty(t, x.imagePullSecrets)
+		assert.Nil(t, x.initResources)
+		assert.Empty(t, x.vaultAddr)
+	})
+	t.Run("Overrides", func(t *testing.T) {
+		x, err := loadConfig(lookupMap(map[string]string{
+			dfv1.EnvImagePrefix:      "my-registry",
+			dfv1.EnvPullPolicy:       "Always",
+			dfv1.EnvRestricted:       "true",
+			dfv1.EnvFaultInjection:   "true",
+			dfv1.EnvImagePullSecrets: "a,b",
+			dfv1.EnvNamespaceRunners: `{"my-ns": {"image": "my-image"}}`,
+			dfv1.EnvInitResources:    `{"limits": {"cpu": "1"}}`,
+			dfv1.EnvNamespaceLimits:  `{"*": {"maxPipelines": 2}}`,
+			dfv1.EnvVaultAddr:        "https://vault:8200",
+		}))
+		assert.NoError(t, err)
+		assert.Equal(t, "my-registry/dataflow-runner:latest", x.runnerImage)
+		assert.Equal(t, corev1.PullAlways, x.pullPolicy)
+		assert.True(t, x.restricted)
+		assert.True(t, x.faultInjection)
+		assert.Equal(t, []string{"a", "b"}, x.imagePullSecrets)
+		assert.Equal(t, map[string]dfv1.Runner{"my-ns": {Image: "my-image"}}, x.namespaceRunners)
+		assert.Equal(t, "1", x.initResources.Limits.Cpu().String())
+		assert.Equal(t, map[string]NamespaceLimits{"*": {MaxPipelines: 2}}, x.namespaceLimits)
+		assert.Equal(t, "https://vault:8200", x.vaultAddr)
+	})
+	t.Run("RunnerImage", func(t *testing.T) {
+		x, err := loadConfig(lookupMap(map[string]string{dfv1.EnvRunnerImage: "my-runner"}))
+		assert.NoError(t, err)
+		assert.Equal(t, "my-runner", x.runnerImage)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := loadConfig(lookupMap(map[string]string{
+			dfv1.EnvUpdateInterval:   "soon",
+			dfv1.EnvNetworkPolicy:    "maybe",
+			dfv1.EnvNamespaceRunners: "{",
+		}))
+		assert.EqualError(t, err, `invalid config: ARGO_DATAFLOW_UPDATE_INTERVAL=soon; value must be duration: time: invalid duration "soon", ARGO_DATAFLOW_NETWORK_POLICY=maybe; value must be bool: strconv.ParseBool: parsing "maybe": invalid syntax, ARGO_DATAFLOW_NAMESPACE_RUNNERS={; value must be JSON: unexpected end of JSON input`)
+	})
+}
+
+func TestConfigWatcher_reload(t *testing.T) {
+	defer setConfig(getConfig())
+	w := &ConfigWatcher{Log: ctrl.Log.WithName("test")}
+	w.reload(map[string]string{dfv1.EnvPodRetention: "2h"})
+	assert.Equal(t, 2*time.Hour, getConfig().podRetention)
+	w.reload(map[string]string{dfv1.EnvPodRetention: "never"})
+	assert.Equal(t, 2*time.Hour, getConfig().podRetention, "invalid config is not loaded")
+	w.reload(nil)
+	assert.Equal(t, time.Hour, getConfig().podRetention, "deleted config reverts to the environment")
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"os"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// the name of the ConfigMap, in the controller's namespace, that overrides the controller's environment variables
+const configMapName = "dataflow-controller-config"
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+
+// ConfigWatcher reloads the controller's config whenever its ConfigMap is created, updated, or deleted.
+type ConfigWatcher struct {
+	Clientset kubernetes.Interface
+	Namespace string
+	Log       logr.Logger
+}
+
+// NeedLeaderElection returns false, so that a controller that becomes the leader already has the current config.
+func (w *ConfigWatcher) NeedLeaderElection() bool {
+	return false
+}
+
+func (w *ConfigWatcher) Start(ctx context.Context) error {
+	w.Log.Info("watching config", "configMap", configMapName)
+	factory := informers.NewSharedInformerFactoryWithOptions(w.Clientset, 0,
+		informers.WithNamespace(w.Namespace),
+		informers.WithTweakListOptions(func(o *metav1.ListOptions) { o.FieldSelector = "metadata.name=" + configMapName }),
+	)
+	factory.Core().V1().ConfigMaps().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { w.reload(obj.(*corev1.ConfigMap).Data) },
+		UpdateFunc: func(_, obj interface{}) { w.reload(obj.(*corev1.ConfigMap).Data) },
+		DeleteFunc: func(interface{}) { w.reload(nil) },
+	})
+	factory.Start(ctx.Done())
+	<-ctx.Done()
+	return nil
+}
+
+// reload loads the config from the ConfigMap's data, falling back to the environment variables. An invalid config is
+// not loaded, so the controller keeps running with the current config.
+func (w *ConfigWatcher) reload(data map[string]string) {
+	x, err := loadConfig(func(key string) (string, bool) {
+		if v, ok := data[key]; ok {
+			return v, true
+		}
+		return os.LookupEnv(key)
+	})
+	if err != nil {
+		w.Log.Error(err, "failed to reload config, keeping the current config", "configMap", configMapName)
+		return
+	}
+	w.Log.Info("reloading config", "configMap", configMapName)
+	setConfig(x)
+}
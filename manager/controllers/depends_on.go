@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/antonmedv/expr"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// how often we check dependencies that are not ready, as we do not watch the steps we depend on
+const dependsOnRequeueAfter = 10 * time.Second
+
+// waitingFor returns a message describing the first of the step's dependencies that is not ready, or an empty string
+// if they are all ready.
+func (r *StepReconciler) waitingFor(ctx context.Context, step *dfv1.Step, pipelineName string) (string, error) {
+	for _, d := range step.Spec.DependsOn {
+		dep := &dfv1.Step{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: step.Namespace, Name: pipelineName + "-" + d.Name}, dep); err != nil {
+			if apierr.IsNotFound(err) {
+				return fmt.Sprintf("waiting for step %q to be created", d.Name), nil
+			}
+			return "", err
+		}
+		selector, _ := labels.Parse(dfv1.KeyPipelineName + "=" + pipelineName + "," + dfv1.KeyStepName + "=" + d.Name)
+		pods := &corev1.PodList{}
+		if err := r.Client.List(ctx, pods, &client.ListOptions{Namespace: step.Namespace, LabelSelector: selector}); err != nil {
+			return "", fmt.Errorf("failed to list pods of step %q: %w", d.Name, err)
+		}
+		readyReplicas := 0
+		for _, pod := range pods.Items {
+			if isReady(pod) {
+				readyReplicas++
+			}
+		}
+		if ready, err := dependencyReady(d.GetCondition(), *dep, readyReplicas); err != nil {
+			return "", fmt.Errorf("failed to evaluate condition of dependency %q: %w", d.Name, err)
+		} else if !ready {
+			return fmt.Sprintf("waiting for step %q to be ready", d.Name), nil
+		}
+	}
+	return "", nil
+}
+
+func dependencyReady(condition string, dep dfv1.Step, readyReplicas int) (bool, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&dep)
+	if err != nil {
+		return false, err
+	}
+	r, err := expr.Eval(condition, map[string]interface{}{
+		"phase":         string(dep.Status.Phase),
+		"replicas":      dep.Status.GetReplicas(),
+		"readyReplicas": readyReplicas,
+		"step":          obj,
+	})
+	if err != nil {
+		return false, err
+	}
+	ready, ok := r.(bool)
+	if !ok {
+		return false, fmt.Errorf("expected bool, got %T", r)
+	}
+	return ready, nil
+}
+
+func isReady(pod corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
@@ -0,0 +1,46 @@
+package controllers
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_dependencyReady(t *testing.T) {
+	running := dfv1.Step{Status: dfv1.StepStatus{Phase: dfv1.StepRunning, Replicas: 2}}
+	t.Run("Default", func(t *testing.T) {
+		for _, tt := range []struct {
+			name          string
+			step          dfv1.Step
+			readyReplicas int
+			want          bool
+		}{
+			{"Pending", dfv1.Step{Status: dfv1.StepStatus{Phase: dfv1.StepPending, Replicas: 1}}, 0, false},
+			{"SomeReady", running, 1, false},
+			{"AllReady", running, 2, true},
+			{"Succeeded", dfv1.Step{Status: dfv1.StepStatus{Phase: dfv1.StepSucceeded}}, 0, true},
+		} {
+			t.Run(tt.name, func(t *testing.T) {
+				ready, err := dependencyReady(dfv1.DefaultStepDependencyCondition, tt.step, tt.readyReplicas)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.want, ready)
+			})
+		}
+	})
+	t.Run("Condition", func(t *testing.T) {
+		step := running.DeepCopy()
+		step.Annotations = map[string]string{"cache": "warm"}
+		ready, err := dependencyReady(`readyReplicas > 0 && step.metadata.annotations.cache == "warm"`, *step, 1)
+		assert.NoError(t, err)
+		assert.True(t, ready)
+		ready, err = dependencyReady(`step.metadata.annotations.cache == "warm"`, dfv1.Step{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{"cache": "cold"}}}, 0)
+		assert.NoError(t, err)
+		assert.False(t, ready)
+	})
+	t.Run("NotBool", func(t *testing.T) {
+		_, err := dependencyReady(`phase`, running, 0)
+		assert.EqualError(t, err, "expected bool, got string")
+	})
+}
@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	gcInterval = time.Minute
+	// pods younger than this are never orphans, as their step may not yet be in the cache
+	orphanGracePeriod = time.Minute
+)
+
+// PodGarbageCollector periodically deletes step pods that the step controller will not delete: pods whose step no longer
+// exists, pods with a malformed replica annotation, and pods that have stopped running, once the retention period has
+// passed.
+type PodGarbageCollector struct {
+	client.Client
+	Log logr.Logger
+}
+
+func (r *PodGarbageCollector) Start(ctx context.Context) error {
+	ticker := time.NewTicker(gcInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.sweep(ctx); err != nil {
+				r.Log.Error(err, "failed to garbage collect pods")
+			}
+		}
+	}
+}
+
+func (r *PodGarbageCollector) sweep(ctx context.Context) error {
+	selector, _ := labels.Parse(dfv1.KeyPipelineName + "," + dfv1.KeyStepName)
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return fmt.Errorf("failed to list pods: %w", err)
+	}
+	steps := &dfv1.StepList{}
+	if err := r.Client.List(ctx, steps, &client.ListOptions{LabelSelector: selector}); err != nil {
+		return fmt.Errorf("failed to list steps: %w", err)
+	}
+	stepOf := map[string]*dfv1.Step{}
+	for i, step := range steps.Items {
+		stepOf[stepKey(step.Namespace, step.Labels)] = &steps.Items[i]
+	}
+	now := time.Now()
+	for _, pod := range pods.Items {
+		reason := garbageReason(pod, stepOf[stepKey(pod.Namespace, pod.Labels)], now)
+		if reason == "" {
+			continue
+		}
+		r.Log.Info("deleting pod", "pod", pod.Namespace+"/"+pod.Name, "reason", reason)
+		if err := r.Client.Delete(ctx, &pod); client.IgnoreNotFound(err) != nil {
+			r.Log.Error(err, "failed to delete pod", "pod", pod.Namespace+"/"+pod.Name)
+		}
+	}
+	return nil
+}
+
+func stepKey(namespace string, labels map[string]string) string {
+	return namespace + "/" + labels[dfv1.KeyPipelineName] + "/" + labels[dfv1.KeyStepName]
+}
+
+// garbageReason returns why the pod should be deleted, or "" if it should not be.
+func garbageReason(pod corev1.Pod, step *dfv1.Step, now time.Time) string {
+	if !pod.GetDeletionTimestamp().IsZero() {
+		return ""
+	}
+	if step == nil {
+		if now.Sub(pod.CreationTimestamp.Time) < orphanGracePeriod {
+			return ""
+		}
+		return "step not found"
+	}
+	if replica, err := strconv.Atoi(pod.GetAnnotations()[dfv1.KeyReplica]); err != nil || replica < 0 {
+		return "malformed replica annotation"
+	}
+	podRetention := getConfig().podRetention
+	if stoppedAt := podStoppedAt(pod, step.Spec); !stoppedAt.IsZero() && now.Sub(stoppedAt) >= podRetention {
+		return fmt.Sprintf("%s for more than %v", pod.Status.Phase, podRetention)
+	}
+	return ""
+}
+
+// podStoppedAt returns when the pod stopped, or zero if it is running, or must be kept. The step controller re-creates
+// deleted pods, so we keep the pods of steps that complete (they are deleted with the pipeline), pods that failed with
+// restartPolicy Never (the step controller deletes them until the backoff limit is reached), and pods whose main
+// container succeeded with restartPolicy OnFailure. This leaves pods that stopped unexpectedly, e.g. evicted pods.
+func podStoppedAt(pod corev1.Pod, step dfv1.StepSpec) time.Time {
+	switch {
+	case pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed,
+		step.CanComplete(),
+		step.RestartPolicy == corev1.RestartPolicyNever,
+		step.RestartPolicy == corev1.RestartPolicyOnFailure && pod.Status.Phase == corev1.PodSucceeded:
+		return time.Time{}
+	}
+	// evicted pods may not have terminated containers, but their conditions change when they stop
+	stoppedAt := pod.CreationTimestamp.Time
+	for _, c := range pod.Status.Conditions {
+		if c.LastTransitionTime.After(stoppedAt) {
+			stoppedAt = c.LastTransitionTime.Time
+		}
+	}
+	for _, s := range pod.Status.ContainerStatuses {
+		if t := s.State.Terminated; t != nil && t.FinishedAt.After(stoppedAt) {
+			stoppedAt = t.FinishedAt.Time
+		}
+	}
+	return stoppedAt
+}
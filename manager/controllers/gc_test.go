@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_garbageReason(t *testing.T) {
+	now := time.Now()
+	newPod := func(replica string, phase corev1.PodPhase, age time.Duration) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				CreationTimestamp: metav1.Time{Time: now.Add(-age)},
+				Annotations:       map[string]string{dfv1.KeyReplica: replica},
+			},
+			Status: corev1.PodStatus{Phase: phase},
+		}
+	}
+	step := &dfv1.Step{Spec: dfv1.StepSpec{RestartPolicy: corev1.RestartPolicyOnFailure}}
+	t.Run("Running", func(t *testing.T) {
+		assert.Empty(t, garbageReason(newPod("0", corev1.PodRunning, 2*getConfig().podRetention), step, now))
+	})
+	t.Run("Orphan", func(t *testing.T) {
+		assert.Equal(t, "step not found", garbageReason(newPod("0", corev1.PodRunning, time.Hour), nil, now))
+	})
+	t.Run("NewOrphan", func(t *testing.T) {
+		assert.Empty(t, garbageReason(newPod("0", corev1.PodRunning, time.Second), nil, now))
+	})
+	t.Run("MalformedReplica", func(t *testing.T) {
+		assert.Equal(t, "malformed replica annotation", garbageReason(newPod("", corev1.PodRunning, 0), step, now))
+		assert.Equal(t, "malformed replica annotation", garbageReason(newPod("-1", corev1.PodRunning, 0), step, now))
+	})
+	t.Run("Failed", func(t *testing.T) {
+		assert.Equal(t, "Failed for more than 1h0m0s", garbageReason(newPod("0", corev1.PodFailed, 2*getConfig().podRetention), step, now))
+	})
+	t.Run("RecentlyFailed", func(t *testing.T) {
+		pod := newPod("0", corev1.PodFailed, 2*getConfig().podRetention)
+		pod.Status.ContainerStatuses = []corev1.ContainerStatus{{State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{FinishedAt: metav1.Time{Time: now.Add(-time.Minute)}}}}}
+		assert.Empty(t, garbageReason(pod, step, now))
+	})
+	t.Run("Succeeded", func(t *testing.T) {
+		assert.Empty(t, garbageReason(newPod("0", corev1.PodSucceeded, 2*getConfig().podRetention), step, now), "main container completed")
+	})
+	t.Run("RestartPolicyNever", func(t *testing.T) {
+		step := &dfv1.Step{Spec: dfv1.StepSpec{RestartPolicy: corev1.RestartPolicyNever}}
+		assert.Empty(t, garbageReason(newPod("0", corev1.PodFailed, 2*getConfig().podRetention), step, now))
+	})
+	t.Run("CanComplete", func(t *testing.T) {
+		step := &dfv1.Step{Spec: dfv1.StepSpec{Completion: &dfv1.Completion{Messages: 1}}}
+		assert.Empty(t, garbageReason(newPod("0", corev1.PodFailed, 2*getConfig().podRetention), step, now))
+	})
+}
@@ -1,6 +1,8 @@
 package controllers
 
 import (
+	"strings"
+
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -27,6 +29,10 @@ func inferPhase(pod corev1.Pod) (dfv1.StepPhase, string, string) {
 	for _, s := range pod.Status.InitContainerStatuses {
 		min = dfv1.MinStepPhaseMessage(min, func() dfv1.StepPhaseMessage {
 			if s.State.Running != nil {
+				// the init container annotates its pod while it waits for brokers, see WaitForBrokers
+				if x := pod.GetAnnotations()[dfv1.KeyWaitingForBrokers]; x != "" && s.Name == dfv1.CtrInit {
+					return dfv1.NewStepPhaseMessage(dfv1.StepPending, "WaitingForBrokers", "waiting for brokers to be reachable: "+strings.ReplaceAll(x, ",", ", "))
+				}
 				return dfv1.NewStepPhaseMessage(dfv1.StepRunning, "", "")
 			} else if x := s.State.Waiting; x != nil {
 				if ErrorReasons[x.Reason] {
@@ -47,6 +53,10 @@ func inferPhase(pod corev1.Pod) (dfv1.StepPhase, string, string) {
 					return dfv1.NewStepPhaseMessage(dfv1.StepFailed, x.Reason, x.Message)
 				}
 			} else if s.State.Running != nil {
+				// the sidecar annotates its pod while it retries connecting to its brokers, see connectWithRetry
+				if x := pod.GetAnnotations()[dfv1.KeyConnecting]; x != "" && s.Name == dfv1.CtrSidecar {
+					return dfv1.NewStepPhaseMessage(dfv1.StepPending, "RetryingConnection", "retrying connection to "+x)
+				}
 				return dfv1.NewStepPhaseMessage(dfv1.StepRunning, "", "")
 			} else if x := s.State.Waiting; x != nil {
 				if ErrorReasons[x.Reason] {
@@ -60,3 +70,13 @@ func inferPhase(pod corev1.Pod) (dfv1.StepPhase, string, string) {
 	}
 	return min.GetPhase(), min.GetReason(), min.GetMessage()
 }
+
+// sidecarCtrTerminated returns true if the sidecar container has terminated successfully.
+func sidecarCtrTerminated(pod corev1.Pod) bool {
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.Name == dfv1.CtrSidecar && s.State.Terminated != nil && s.State.Terminated.ExitCode == 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -6,6 +6,7 @@ import (
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_inferPhase(t *testing.T) {
@@ -41,6 +42,44 @@ func Test_inferPhase(t *testing.T) {
 		})
 		assert.Equal(t, p, dfv1.StepRunning)
 	})
+	t.Run("WaitingForBrokers", func(t *testing.T) {
+		p, reason, msg := inferPhase(corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{dfv1.KeyWaitingForBrokers: "kafka-broker:9092,nats:4222"}},
+			Status: corev1.PodStatus{
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: dfv1.CtrInit, State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					}},
+				},
+				ContainerStatuses: []corev1.ContainerStatus{
+					{State: corev1.ContainerState{
+						Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"},
+					}},
+				},
+			},
+		})
+		assert.Equal(t, p, dfv1.StepPending)
+		assert.Equal(t, "WaitingForBrokers", reason)
+		assert.Equal(t, "waiting for brokers to be reachable: kafka-broker:9092, nats:4222", msg)
+	})
+	t.Run("RetryingConnection", func(t *testing.T) {
+		p, reason, msg := inferPhase(corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{dfv1.KeyConnecting: `source "in" (attempt 2/8): connection refused`}},
+			Status: corev1.PodStatus{
+				ContainerStatuses: []corev1.ContainerStatus{
+					{Name: dfv1.CtrSidecar, State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					}},
+					{Name: dfv1.CtrMain, State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					}},
+				},
+			},
+		})
+		assert.Equal(t, p, dfv1.StepPending)
+		assert.Equal(t, "RetryingConnection", reason)
+		assert.Equal(t, `retrying connection to source "in" (attempt 2/8): connection refused`, msg)
+	})
 	t.Run("CrashLoopBackOff", func(t *testing.T) {
 		p, reason, msg := inferPhase(corev1.Pod{
 			Status: corev1.PodStatus{
@@ -120,3 +159,15 @@ func Test_inferPhase(t *testing.T) {
 		assert.Equal(t, p, dfv1.StepSucceeded)
 	})
 }
+
+func Test_sidecarCtrTerminated(t *testing.T) {
+	pod := func(name string, exitCode int32) corev1.Pod {
+		return corev1.Pod{Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{
+			{Name: name, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: exitCode}}},
+		}}}
+	}
+	assert.False(t, sidecarCtrTerminated(corev1.Pod{}))
+	assert.True(t, sidecarCtrTerminated(pod(dfv1.CtrSidecar, 0)))
+	assert.False(t, sidecarCtrTerminated(pod(dfv1.CtrSidecar, 1)))
+	assert.False(t, sidecarCtrTerminated(pod(dfv1.CtrMain, 0)))
+}
@@ -0,0 +1,33 @@
+package controllers
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// hasMetadata returns whether the object has the labels and annotations, e.g. those propagated from its pipeline.
+func hasMetadata(obj metav1.ObjectMeta, labels, annotations map[string]string) bool {
+	for k, v := range labels {
+		if obj.Labels[k] != v {
+			return false
+		}
+	}
+	for k, v := range annotations {
+		if obj.Annotations[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeMetadata returns the labels or annotations, with the other's added, or replaced if they differ. Others are kept,
+// as they may have been added by someone else, e.g. `kubectl annotate`.
+func mergeMetadata(m, other map[string]string) map[string]string {
+	if len(other) == 0 {
+		return m
+	}
+	if m == nil {
+		m = map[string]string{}
+	}
+	for k, v := range other {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,22 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_hasMetadata(t *testing.T) {
+	obj := metav1.ObjectMeta{Labels: map[string]string{"a": "1", "b": "2"}, Annotations: map[string]string{"c": "3"}}
+	assert.True(t, hasMetadata(obj, nil, nil))
+	assert.True(t, hasMetadata(obj, map[string]string{"a": "1"}, map[string]string{"c": "3"}))
+	assert.False(t, hasMetadata(obj, map[string]string{"a": "2"}, nil))
+	assert.False(t, hasMetadata(obj, nil, map[string]string{"d": "4"}))
+}
+
+func Test_mergeMetadata(t *testing.T) {
+	assert.Nil(t, mergeMetadata(nil, nil))
+	assert.Equal(t, map[string]string{"a": "1"}, mergeMetadata(nil, map[string]string{"a": "1"}))
+	assert.Equal(t, map[string]string{"a": "2", "b": "2"}, mergeMetadata(map[string]string{"a": "1", "b": "2"}, map[string]string{"a": "2"}))
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Subsystem: "reconcile",
+		Name:      "duration_seconds",
+		Help:      "How long reconciling took, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#reconcile_duration_seconds",
+	}, []string{"kind", "namespace", "pipelineName"})
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "reconcile",
+		Name:      "errors",
+		Help:      "Number of reconciles that failed, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#reconcile_errors",
+	}, []string{"kind", "namespace", "pipelineName"})
+)
+
+func init() {
+	// registered with the manager's registry, so they are exposed by its metrics endpoint
+	metrics.Registry.MustRegister(reconcileDuration, reconcileErrors)
+}
+
+// observeReconcile records how long reconciling one of the pipeline's objects took, and whether it failed. It is
+// deferred, so it is passed a pointer to the error.
+func observeReconcile(kind, namespace, pipelineName string, start time.Time, err *error) {
+	reconcileDuration.WithLabelValues(kind, namespace, pipelineName).Observe(time.Since(start).Seconds())
+	if *err != nil {
+		reconcileErrors.WithLabelValues(kind, namespace, pipelineName).Inc()
+	}
+}
+
+// forgetReconcile removes a deleted pipeline's metrics, so they do not grow without bound.
+func forgetReconcile(pipeline types.NamespacedName) {
+	for _, kind := range []string{"Pipeline", "Step"} {
+		reconcileDuration.DeleteLabelValues(kind, pipeline.Namespace, pipeline.Name)
+		reconcileErrors.DeleteLabelValues(kind, pipeline.Namespace, pipeline.Name)
+	}
+}
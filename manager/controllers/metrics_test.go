@@ -0,0 +1,23 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func Test_observeReconcile(t *testing.T) {
+	var err error
+	observeReconcile("Pipeline", "my-ns", "my-pl", time.Now(), &err)
+	assert.Equal(t, float64(0), testutil.ToFloat64(reconcileErrors.WithLabelValues("Pipeline", "my-ns", "my-pl")))
+	err = errors.New("failed")
+	observeReconcile("Pipeline", "my-ns", "my-pl", time.Now(), &err)
+	assert.Equal(t, float64(1), testutil.ToFloat64(reconcileErrors.WithLabelValues("Pipeline", "my-ns", "my-pl")))
+	assert.Equal(t, 2, testutil.CollectAndCount(reconcileErrors)+testutil.CollectAndCount(reconcileDuration))
+	forgetReconcile(types.NamespacedName{Namespace: "my-ns", Name: "my-pl"})
+	assert.Equal(t, 0, testutil.CollectAndCount(reconcileErrors)+testutil.CollectAndCount(reconcileDuration))
+}
@@ -32,6 +32,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -42,6 +43,8 @@ type PipelineReconciler struct {
 	Log             logr.Logger
 	Scheme          *runtime.Scheme
 	ContainerKiller containerkiller.Interface
+	Recorder        record.EventRecorder
+	Cluster         string
 }
 
 // +kubebuilder:rbac:groups=dataflow.argoproj.io,resources=pipelines,verbs=get;list;watch;create;update;patch;delete
@@ -51,21 +54,40 @@ type PipelineReconciler struct {
 // +kubebuilder:rbac:groups=,resources=services,verbs=create;get;delete
 // +kubebuilder:rbac:groups=apps,resources=statefulsets,verbs=create;get;delete
 // +kubebuilder:rbac:groups=,resources=secrets,verbs=create;get;delete
-func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;update;delete
+func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	log := r.Log.WithValues("pipeline", req.NamespacedName.String())
 
 	pipeline := &dfv1.Pipeline{}
 	if err := r.Get(ctx, req.NamespacedName, pipeline); err != nil {
+		if apierr.IsNotFound(err) {
+			forgetReconcile(req.NamespacedName)
+		}
 		// we'll ignore not-found errors, since they can't be fixed by an immediate
 		// requeue (we'll need to wait for a new notification), and we can get them
 		// on deleted requests.
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	defer observeReconcile("Pipeline", pipeline.Namespace, pipeline.Name, time.Now(), &err)
+
 	if !pipeline.GetDeletionTimestamp().IsZero() {
 		return ctrl.Result{}, nil
 	}
 
+	if to, ok := pipeline.GetAnnotations()[dfv1.KeyRollback]; ok {
+		return ctrl.Result{}, r.rollback(ctx, pipeline, to)
+	}
+
+	revision, err := r.reconcileRevisions(ctx, pipeline)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if pipeline.Spec.Schedule != nil {
+		return r.reconcileSchedule(ctx, pipeline, revision)
+	}
+
 	if pipeline.Status.Phase.Completed() {
 		deletionDelay := pipeline.Spec.DeletionDelay.Duration
 		deleteAt := pipeline.Status.LastUpdated.Time.Add(deletionDelay)
@@ -75,16 +97,38 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 	}
 
+	// a job's phase is terminal, so it can be waited for
+	if pipeline.Spec.Job != nil && pipeline.Status.Phase.Completed() {
+		return ctrl.Result{}, nil
+	}
+
+	if promoting(pipeline) {
+		log.Info("promoting")
+		return ctrl.Result{}, r.promote(ctx, pipeline)
+	}
+
 	log.Info("reconciling")
 
-	for _, step := range pipeline.Spec.Steps {
+	pipelineSteps, err := pipeline.Spec.GetSteps()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	metadata := pipeline.Spec.GetMetadata()
+	for _, step := range pipelineSteps {
 		stepFullName := pipeline.Name + "-" + step.Name
-		matchLabels := map[string]string{dfv1.KeyPipelineName: pipeline.Name, dfv1.KeyStepName: step.Name}
+		stepLabels := map[string]string{}
+		for k, v := range metadata.Labels {
+			stepLabels[k] = v
+		}
+		stepLabels[dfv1.KeyPipelineName] = pipeline.Name
+		stepLabels[dfv1.KeyStepName] = step.Name
 		obj := &dfv1.Step{
 			ObjectMeta: metav1.ObjectMeta{
-				Namespace: pipeline.Namespace,
-				Name:      stepFullName,
-				Labels:    matchLabels,
+				Namespace:   pipeline.Namespace,
+				Name:        stepFullName,
+				Labels:      stepLabels,
+				Annotations: metadata.Annotations,
 				OwnerReferences: []metav1.OwnerReference{
 					*metav1.NewControllerRef(pipeline.GetObjectMeta(), dfv1.PipelineGroupVersionKind),
 				},
@@ -98,9 +142,11 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 					return ctrl.Result{}, err
 				}
 				step.Replicas = old.Spec.Replicas // copy this field as it should only be modified by `kubectl scale`, edited by the user
-				if notEqual, patch := util.NotEqual(step, old.Spec); notEqual {
-					log.Info("updating step due to changed spec", "patch", patch)
+				if notEqual, patch := util.NotEqual(step, old.Spec); notEqual || !hasMetadata(old.ObjectMeta, stepLabels, metadata.Annotations) {
+					log.Info("updating step due to changed spec or metadata", "patch", patch)
 					old.Spec = step
+					old.Labels = mergeMetadata(old.Labels, stepLabels)
+					old.Annotations = mergeMetadata(old.Annotations, metadata.Annotations)
 					if err := r.Client.Update(ctx, old); util.IgnoreConflict(err) != nil { // ignore conflicts, we will be reconciling again shortly if this happens
 						return ctrl.Result{}, err
 					}
@@ -120,7 +166,9 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	pending, running, succeeded, failed := 0, 0, 0, 0
 	newStatus := *pipeline.Status.DeepCopy()
 	newStatus.Phase = dfv1.PipelineUnknown
-	terminate := false
+	newStatus.Revision = revision
+	newStatus.ObservedGeneration = pipeline.Generation
+	terminate, slowConsumer := false, false
 	for _, step := range steps.Items {
 		stepName := step.Spec.Name
 		if !pipeline.Spec.HasStep(stepName) { // this happens when a pipeline changes and a step is removed
@@ -147,12 +195,25 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 			panic("should never happen")
 		}
 		terminate = terminate || step.Status.Phase.Completed() && step.Spec.Terminator
+		slowConsumer = slowConsumer || meta.IsStatusConditionTrue(step.Status.Conditions, dfv1.ConditionSlowConsumer)
 	}
 
 	if newStatus.Phase.Completed() {
 		terminate = false
 	}
 
+	deadlineExceeded, deadlineRequeueAfter := false, time.Duration(0)
+	if d := pipeline.Spec.Job.GetActiveDeadline(); d > 0 && !newStatus.Phase.Completed() {
+		if remaining := time.Until(pipeline.CreationTimestamp.Add(d)); remaining > 0 {
+			deadlineRequeueAfter = remaining
+		} else {
+			log.Info("job exceeded its active deadline", "activeDeadline", d.String())
+			deadlineExceeded = true
+			newStatus.Phase = dfv1.PipelineFailed
+			terminate = true
+		}
+	}
+
 	var ss []string
 	for s, n := range map[string]int{
 		"pending":   pending,
@@ -167,13 +228,30 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if terminate {
 		ss = append(ss, "terminating")
 	}
+	if deadlineExceeded {
+		ss = append(ss, "active deadline exceeded")
+	}
 
 	newStatus.Message = strings.Join(ss, ", ")
 
+	newStatus.Upgrade = nil
+	if pipeline.Spec.Upgrade != nil {
+		x, err := r.getUpgradeStatus(ctx, pipeline, steps.Items)
+		if err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to get upgrade status: %w", err)
+		}
+		newStatus.Upgrade = x
+	}
+
+	if err := r.reconcileSLO(pipeline, &newStatus, steps.Items); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	for c, ok := range map[string]bool{
-		dfv1.ConditionRunning:     newStatus.Phase == dfv1.PipelineRunning,
-		dfv1.ConditionCompleted:   newStatus.Phase.Completed(),
-		dfv1.ConditionTerminating: terminate,
+		dfv1.ConditionRunning:      newStatus.Phase == dfv1.PipelineRunning,
+		dfv1.ConditionCompleted:    newStatus.Phase.Completed(),
+		dfv1.ConditionTerminating:  terminate,
+		dfv1.ConditionSlowConsumer: slowConsumer,
 	} {
 		if ok {
 			meta.SetStatusCondition(&newStatus.Conditions, metav1.Condition{Type: c, Status: metav1.ConditionTrue, Reason: c})
@@ -210,12 +288,16 @@ func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		}
 	}
 
-	return ctrl.Result{}, nil
+	if pipeline.Spec.Upgrade != nil || pipeline.Spec.SLO != nil {
+		return ctrl.Result{RequeueAfter: getConfig().updateInterval}, nil // the parity and SLO change as messages are processed
+	}
+	return ctrl.Result{RequeueAfter: deadlineRequeueAfter}, nil
 }
 
 func (r *PipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&dfv1.Pipeline{}).
 		Owns(&dfv1.Step{}).
+		Owns(&dfv1.Pipeline{}). // the runs of scheduled pipelines
 		Complete(r)
 }
@@ -0,0 +1,207 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// how often the containers' usage is observed, metrics-server only updates it about this often anyway
+	recommendationsInterval = time.Minute
+	// how long usage must be observed before recommendations are applied
+	recommendationsWarmUp = time.Hour
+	// the recommended requests' headroom over the peak usage, and how much they must differ from the applied requests
+	// to be applied
+	recommendationsHeadroom = 0.2
+)
+
+var recommendedResources = []string{string(corev1.ResourceCPU), string(corev1.ResourceMemory)}
+
+// getContainerUsage returns the highest current usage of each container of any of the pods, by container name, from
+// the metrics API.
+var getContainerUsage = func(ctx context.Context, c client.Client, namespace string, selector labels.Selector) (map[string]map[string]resource.Quantity, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"})
+	if err := c.List(ctx, list, &client.ListOptions{Namespace: namespace, LabelSelector: selector}); err != nil {
+		return nil, fmt.Errorf("failed to list pod metrics: %w", err)
+	}
+	usage := map[string]map[string]resource.Quantity{}
+	for _, item := range list.Items {
+		containers, _, _ := unstructured.NestedSlice(item.Object, "containers")
+		for _, x := range containers {
+			ctr, _ := x.(map[string]interface{})
+			name, _, _ := unstructured.NestedString(ctr, "name")
+			values, _, _ := unstructured.NestedStringMap(ctr, "usage")
+			for _, r := range recommendedResources {
+				q, err := resource.ParseQuantity(values[r])
+				if err != nil {
+					continue
+				}
+				if usage[name] == nil {
+					usage[name] = map[string]resource.Quantity{}
+				}
+				if v, ok := usage[name][r]; !ok || q.Cmp(v) > 0 {
+					usage[name][r] = q
+				}
+			}
+		}
+	}
+	return usage, nil
+}
+
+// getContainerRequests returns the CPU and memory requests of each container of any of the pods, by container name.
+func getContainerRequests(pods []corev1.Pod) map[string]map[string]resource.Quantity {
+	requests := map[string]map[string]resource.Quantity{}
+	for _, pod := range pods {
+		for _, c := range pod.Spec.Containers {
+			for _, r := range recommendedResources {
+				if q, ok := c.Resources.Requests[corev1.ResourceName(r)]; ok {
+					if requests[c.Name] == nil {
+						requests[c.Name] = map[string]resource.Quantity{}
+					}
+					requests[c.Name][r] = q
+				}
+			}
+		}
+	}
+	return requests
+}
+
+// amount returns the quantity in millicores for CPU, and bytes for memory.
+func amount(r string, q resource.Quantity) float64 {
+	if r == string(corev1.ResourceCPU) {
+		return float64(q.MilliValue())
+	}
+	return float64(q.Value())
+}
+
+// newQuantity returns the amount as a quantity, rounded up to the millicore for CPU, and the mebibyte for memory.
+func newQuantity(r string, v float64) resource.Quantity {
+	if r == string(corev1.ResourceCPU) {
+		return *resource.NewMilliQuantity(int64(math.Ceil(v)), resource.DecimalSI)
+	}
+	const mebibyte = 1 << 20
+	return *resource.NewQuantity(int64(math.Ceil(v/mebibyte))*mebibyte, resource.BinarySI)
+}
+
+// recommend returns the recommendations, updated with the containers' current usage, and compared with their
+// requests. The peak usage decays by the half-life since it was last observed, so it follows usage down, as well as up.
+func recommend(x dfv1.Recommendations, old *dfv1.RecommendationsStatus, usage, requests map[string]map[string]resource.Quantity, now time.Time) *dfv1.RecommendationsStatus {
+	s := &dfv1.RecommendationsStatus{ObservedAt: metav1.Time{Time: now}, Since: metav1.Time{Time: now}}
+	decay := float64(0)
+	previous := map[string]dfv1.ContainerRecommendation{}
+	if old != nil {
+		s.Since = old.Since
+		decay = math.Pow(0.5, now.Sub(old.ObservedAt.Time).Seconds()/x.GetHalfLife().Seconds())
+		for _, c := range old.Containers {
+			previous[c.Name] = c
+		}
+	}
+	var names []string
+	for name := range usage {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := previous[name]
+		c := dfv1.ContainerRecommendation{Name: name, PeakUsage: map[string]resource.Quantity{}, Requests: map[string]resource.Quantity{}}
+		for _, r := range recommendedResources {
+			q, ok := usage[name][r]
+			if !ok {
+				continue
+			}
+			peak := amount(r, q)
+			if v, ok := p.PeakUsage[r]; ok {
+				peak = math.Max(peak, amount(r, v)*decay)
+			}
+			c.PeakUsage[r] = newQuantity(r, peak)
+			c.Requests[r] = newQuantity(r, peak*(1+recommendationsHeadroom))
+		}
+		if x.Apply {
+			c.Applied = p.Applied
+			if now.Sub(s.Since.Time) >= recommendationsWarmUp && differ(c.Applied, c.Requests) {
+				c.Applied = c.Requests
+			}
+		}
+		c.Provisioning = provisioning(c, requests[name])
+		s.Containers = append(s.Containers, c)
+	}
+	return s
+}
+
+// differ returns whether any of the recommended requests differs from the applied request by more than the headroom.
+func differ(applied, requests map[string]resource.Quantity) bool {
+	for r, q := range requests {
+		v, ok := applied[r]
+		if !ok || math.Abs(amount(r, q)-amount(r, v)) > amount(r, v)*recommendationsHeadroom {
+			return true
+		}
+	}
+	return false
+}
+
+func provisioning(c dfv1.ContainerRecommendation, requests map[string]resource.Quantity) string {
+	over := false
+	for r, q := range requests {
+		if v, ok := c.PeakUsage[r]; ok && v.Cmp(q) > 0 {
+			return dfv1.ProvisioningUnder
+		}
+		if v, ok := c.Requests[r]; ok && amount(r, q) > 2*amount(r, v) {
+			over = true
+		}
+	}
+	if over {
+		return dfv1.ProvisioningOver
+	}
+	return ""
+}
+
+// applyRequests sets the containers' requests to those applied, capped at their limits.
+func applyRequests(spec corev1.PodSpec, applied map[string]map[string]resource.Quantity) corev1.PodSpec {
+	for i, c := range spec.Containers {
+		for r, q := range applied[c.Name] {
+			name := corev1.ResourceName(r)
+			if limit, ok := c.Resources.Limits[name]; ok && q.Cmp(limit) > 0 {
+				q = limit
+			}
+			if spec.Containers[i].Resources.Requests == nil {
+				spec.Containers[i].Resources.Requests = corev1.ResourceList{}
+			}
+			spec.Containers[i].Resources.Requests[name] = q
+		}
+	}
+	return spec
+}
+
+// reconcileRecommendations observes the step's containers' usage, at most every interval, and updates their
+// recommendations.
+func (r *StepReconciler) reconcileRecommendations(ctx context.Context, step *dfv1.Step, selector labels.Selector, pods []corev1.Pod) {
+	x := step.Spec.Recommendations
+	if x == nil {
+		step.Status.Recommendations = nil
+		return
+	}
+	now := time.Now()
+	if old := step.Status.Recommendations; len(pods) == 0 || old != nil && now.Sub(old.ObservedAt.Time) < recommendationsInterval {
+		return
+	}
+	usage, err := getContainerUsage(ctx, r.Client, step.Namespace, selector)
+	if err != nil {
+		// e.g. metrics-server is not installed, the recommendations are only informative, so this is not an error
+		r.Log.Info("failed to get container usage", "step", step.Name, "err", err.Error())
+		return
+	}
+	step.Status.Recommendations = recommend(*x, step.Status.Recommendations, usage, getContainerRequests(pods), now)
+}
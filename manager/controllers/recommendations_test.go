@@ -0,0 +1,82 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func quantities(cpu, memory string) map[string]resource.Quantity {
+	return map[string]resource.Quantity{"cpu": resource.MustParse(cpu), "memory": resource.MustParse(memory)}
+}
+
+func toStrings(m map[string]resource.Quantity) map[string]string {
+	s := map[string]string{}
+	for k, v := range m {
+		s[k] = v.String()
+	}
+	return s
+}
+
+func Test_recommend(t *testing.T) {
+	now := time.Now()
+	x := dfv1.Recommendations{HalfLife: &metav1.Duration{Duration: time.Hour}}
+	t.Run("First", func(t *testing.T) {
+		s := recommend(x, nil, map[string]map[string]resource.Quantity{"main": quantities("100m", "100Mi")}, map[string]map[string]resource.Quantity{"main": quantities("1", "128Mi")}, now)
+		assert.Equal(t, now, s.Since.Time)
+		if assert.Len(t, s.Containers, 1) {
+			c := s.Containers[0]
+			assert.Equal(t, "main", c.Name)
+			assert.Equal(t, "100m", toStrings(c.PeakUsage)["cpu"])
+			assert.Equal(t, "120m", toStrings(c.Requests)["cpu"])
+			assert.Equal(t, "120Mi", toStrings(c.Requests)["memory"])
+			assert.Empty(t, c.Applied)
+			assert.Equal(t, dfv1.ProvisioningOver, c.Provisioning)
+		}
+	})
+	old := &dfv1.RecommendationsStatus{
+		ObservedAt: metav1.Time{Time: now.Add(-time.Hour)},
+		Since:      metav1.Time{Time: now.Add(-2 * time.Hour)},
+		Containers: []dfv1.ContainerRecommendation{{Name: "main", PeakUsage: quantities("400m", "100Mi"), Applied: quantities("100m", "100Mi")}},
+	}
+	t.Run("Decayed", func(t *testing.T) {
+		s := recommend(x, old, map[string]map[string]resource.Quantity{"main": quantities("100m", "200Mi")}, map[string]map[string]resource.Quantity{"main": quantities("250m", "128Mi")}, now)
+		assert.Equal(t, old.Since, s.Since)
+		c := s.Containers[0]
+		assert.Equal(t, "200m", toStrings(c.PeakUsage)["cpu"], "halved")
+		assert.Equal(t, "200Mi", toStrings(c.PeakUsage)["memory"], "higher than decayed")
+		assert.Empty(t, c.Applied, "not applying")
+		assert.Equal(t, dfv1.ProvisioningUnder, c.Provisioning)
+	})
+	t.Run("Apply", func(t *testing.T) {
+		x := x
+		x.Apply = true
+		s := recommend(x, old, map[string]map[string]resource.Quantity{"main": quantities("100m", "100Mi")}, nil, now)
+		c := s.Containers[0]
+		assert.Equal(t, map[string]string{"cpu": "240m", "memory": "120Mi"}, toStrings(c.Applied))
+		assert.Empty(t, c.Provisioning)
+		s = recommend(x, s, map[string]map[string]resource.Quantity{"main": quantities("210m", "100Mi")}, nil, now.Add(time.Minute))
+		assert.Equal(t, map[string]string{"cpu": "240m", "memory": "120Mi"}, toStrings(s.Containers[0].Applied), "within headroom")
+	})
+	t.Run("WarmingUp", func(t *testing.T) {
+		x := x
+		x.Apply = true
+		s := recommend(x, nil, map[string]map[string]resource.Quantity{"main": quantities("100m", "100Mi")}, nil, now)
+		assert.Empty(t, s.Containers[0].Applied)
+	})
+}
+
+func Test_applyRequests(t *testing.T) {
+	spec := corev1.PodSpec{Containers: []corev1.Container{
+		{Name: "main", Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{"memory": resource.MustParse("64Mi")}}},
+		{Name: "sidecar"},
+	}}
+	spec = applyRequests(spec, map[string]map[string]resource.Quantity{"main": quantities("100m", "100Mi")})
+	assert.Equal(t, corev1.ResourceList{"cpu": resource.MustParse("100m"), "memory": resource.MustParse("64Mi")}, spec.Containers[0].Resources.Requests)
+	assert.Nil(t, spec.Containers[1].Resources.Requests)
+}
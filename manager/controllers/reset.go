@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileReset progresses a reset of the step's sources, requested using the reset-offset annotation, updating the
+// step's offset reset status. To reset safely, every pod is stopped, so the consumer groups and durable queues are
+// empty. Then only replica 0 is started, which resets the sources before it starts consuming. Once it is ready, the
+// rest of the replicas are started. It returns the specs of the replicas, and how many replicas may be created.
+func (r *StepReconciler) reconcileReset(ctx context.Context, step *dfv1.Step, specs replicaSpecs, podsToCreate int, pods []corev1.Pod) (replicaSpecs, int, error) {
+	log := r.Log.WithValues("step", step.Namespace+"/"+step.Name)
+	s := step.Status.OffsetReset
+	if v, ok := step.GetAnnotations()[dfv1.KeyResetOffset]; ok {
+		removeAnnotation := func() error {
+			patch := util.MustJSON(map[string]interface{}{"metadata": map[string]interface{}{"annotations": map[string]interface{}{dfv1.KeyResetOffset: nil}}})
+			// patch a copy, so we do not lose the status changes made while reconciling
+			x := step.DeepCopy()
+			if err := r.Client.Patch(ctx, x, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+				return fmt.Errorf("failed to remove reset offset annotation: %w", err)
+			}
+			step.Annotations, step.ResourceVersion = x.Annotations, x.ResourceVersion
+			return nil
+		}
+		// once the request is in the status (or another reset is in progress), the annotation is no longer needed
+		if s != nil && s.Phase.InProgress() {
+			if err := removeAnnotation(); err != nil {
+				return nil, 0, err
+			}
+		} else if _, err := dfv1.ResetOffset(v).GetTime(); err != nil {
+			r.Recorder.Eventf(step, "Warning", "ResetOffsetInvalid", "Invalid reset offset: %v", err)
+			s = &dfv1.ResetStatus{Offset: dfv1.ResetOffset(v), Phase: dfv1.ResetFailed, Message: err.Error(), RequestedAt: metav1.Now()}
+			step.Status.OffsetReset = s
+			if err := removeAnnotation(); err != nil {
+				return nil, 0, err
+			}
+		} else {
+			log.Info("resetting sources", "offset", v)
+			r.Recorder.Eventf(step, "Normal", "ResetOffset", "Resetting sources to %s", v)
+			s = &dfv1.ResetStatus{Offset: dfv1.ResetOffset(v), Phase: dfv1.ResetStopping, Message: "stopping pods", RequestedAt: metav1.Now()}
+			step.Status.OffsetReset = s
+		}
+	}
+	if s == nil || !s.Phase.InProgress() {
+		return specs, podsToCreate, nil
+	}
+	if s.Phase == dfv1.ResetStopping {
+		if len(pods) > 0 {
+			s.Message = fmt.Sprintf("waiting for %d pods to stop", len(pods))
+			return func(int) (string, dfv1.StepSpec) { return "", step.Spec }, 0, nil // no pod matches, so every pod is deleted
+		}
+		s.Phase, s.Message = dfv1.ResetResetting, "resetting sources"
+	}
+	// e.g. the spec is invalid
+	if step.Status.Phase == dfv1.StepFailed {
+		return specs, 0, nil
+	}
+	hash, spec := specs(0)
+	resetHash := util.MustHash([]string{hash, string(s.Offset), s.RequestedAt.String()})
+	for _, pod := range pods {
+		if pod.GetAnnotations()[dfv1.KeyHash] != resetHash {
+			continue
+		}
+		if phase, reason, message := inferPhase(pod); phase == dfv1.StepFailed {
+			log.Info("failed to reset sources", "pod", pod.Name)
+			s.Phase, s.Message = dfv1.ResetFailed, fmt.Sprintf("pod %s failed: %s %s", pod.Name, reason, message)
+			r.Recorder.Eventf(step, "Warning", "ResetOffsetFailed", "Failed to reset sources to %s: %s", s.Offset, s.Message)
+			return specs, podsToCreate, nil
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				log.Info("reset sources", "offset", s.Offset)
+				s.Phase, s.Message = dfv1.ResetSucceeded, fmt.Sprintf("reset sources to %s", s.Offset)
+				r.Recorder.Eventf(step, "Normal", "ResetOffsetSucceeded", "Reset sources to %s", s.Offset)
+				return specs, podsToCreate, nil
+			}
+		}
+	}
+	return func(replica int) (string, dfv1.StepSpec) {
+		if replica == 0 {
+			return resetHash, spec
+		}
+		return "", spec
+	}, 1, nil
+}
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func Test_reconcileReset(t *testing.T) {
+	ctx := context.Background()
+	newStep := func(offset string) *dfv1.Step {
+		return &dfv1.Step{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-main", Annotations: map[string]string{dfv1.KeyResetOffset: offset}},
+			Spec:       dfv1.StepSpec{Name: "main", Replicas: 2},
+		}
+	}
+	newReconciler := func(step *dfv1.Step) *StepReconciler {
+		return &StepReconciler{Client: newFakeClient(t, step.DeepCopy()), Log: ctrl.Log.WithName("test"), Recorder: record.NewFakeRecorder(10)}
+	}
+	specs := allReplicas("my-hash", dfv1.StepSpec{Name: "main"})
+	running := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pl-main-0", Annotations: map[string]string{dfv1.KeyHash: "my-hash"}}}
+	annotations := func(r *StepReconciler) map[string]string {
+		x := &dfv1.Step{}
+		assert.NoError(t, r.Get(ctx, client.ObjectKey{Namespace: "my-ns", Name: "my-pl-main"}, x))
+		return x.Annotations
+	}
+	t.Run("None", func(t *testing.T) {
+		step := &dfv1.Step{}
+		_, podsToCreate, err := newReconciler(step).reconcileReset(ctx, step, specs, 2, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, podsToCreate)
+		assert.Nil(t, step.Status.OffsetReset)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		step := newStep("latest")
+		r := newReconciler(step)
+		_, podsToCreate, err := r.reconcileReset(ctx, step, specs, 2, []corev1.Pod{running})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, podsToCreate)
+		assert.Equal(t, dfv1.ResetFailed, step.Status.OffsetReset.Phase)
+		assert.NotContains(t, annotations(r), dfv1.KeyResetOffset)
+	})
+	t.Run("Succeeded", func(t *testing.T) {
+		step := newStep("earliest")
+		r := newReconciler(step)
+		// stop every pod
+		x, podsToCreate, err := r.reconcileReset(ctx, step, specs, 2, []corev1.Pod{running})
+		assert.NoError(t, err)
+		assert.Zero(t, podsToCreate)
+		hash, _ := x(0)
+		assert.Empty(t, hash)
+		assert.Equal(t, dfv1.ResetStopping, step.Status.OffsetReset.Phase)
+		assert.Equal(t, "waiting for 1 pods to stop", step.Status.OffsetReset.Message)
+		assert.Contains(t, annotations(r), dfv1.KeyResetOffset, "kept until the status is updated")
+		// start replica 0
+		x, podsToCreate, err = r.reconcileReset(ctx, step, specs, 2, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, podsToCreate)
+		assert.Equal(t, dfv1.ResetResetting, step.Status.OffsetReset.Phase)
+		assert.NotContains(t, annotations(r), dfv1.KeyResetOffset)
+		resetHash, _ := x(0)
+		assert.NotEqual(t, "my-hash", resetHash)
+		// replica 0 is not ready yet
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "my-pl-main-0", Annotations: map[string]string{dfv1.KeyHash: resetHash}}}
+		_, podsToCreate, err = r.reconcileReset(ctx, step, specs, 2, []corev1.Pod{pod})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, podsToCreate)
+		// replica 0 is ready
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		x, podsToCreate, err = r.reconcileReset(ctx, step, specs, 2, []corev1.Pod{pod})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, podsToCreate)
+		hash, _ = x(0)
+		assert.Equal(t, "my-hash", hash)
+		assert.Equal(t, dfv1.ResetSucceeded, step.Status.OffsetReset.Phase)
+		assert.Equal(t, "reset sources to earliest", step.Status.OffsetReset.Message)
+	})
+	t.Run("Failed", func(t *testing.T) {
+		step := newStep("2021-09-01T00:00:00Z")
+		step.Status.OffsetReset = &dfv1.ResetStatus{Offset: "2021-09-01T00:00:00Z", Phase: dfv1.ResetResetting}
+		r := newReconciler(step)
+		x, _, err := r.reconcileReset(ctx, step, specs, 2, nil)
+		assert.NoError(t, err)
+		resetHash, _ := x(0)
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pl-main-0", Annotations: map[string]string{dfv1.KeyHash: resetHash}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{ExitCode: 1, Reason: "Error", Message: "failed to reset"}},
+			}}},
+		}
+		_, podsToCreate, err := r.reconcileReset(ctx, step, specs, 2, []corev1.Pod{pod})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, podsToCreate)
+		assert.Equal(t, dfv1.ResetFailed, step.Status.OffsetReset.Phase)
+		assert.Equal(t, "pod my-pl-main-0 failed: Error failed to reset", step.Status.OffsetReset.Message)
+	})
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/util"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// the pipeline's revisions, rather than its steps' revisions, which have a step name
+func revisionSelector(pipelineName string) labels.Selector {
+	selector, _ := labels.Parse(dfv1.KeyPipelineName + "=" + pipelineName + ",!" + dfv1.KeyStepName)
+	return selector
+}
+
+// listRevisions returns the pipeline's revisions, oldest first.
+func (r *PipelineReconciler) listRevisions(ctx context.Context, pipeline *dfv1.Pipeline) ([]appsv1.ControllerRevision, error) {
+	list := &appsv1.ControllerRevisionList{}
+	if err := r.Client.List(ctx, list, &client.ListOptions{Namespace: pipeline.Namespace, LabelSelector: revisionSelector(pipeline.Name)}); err != nil {
+		return nil, fmt.Errorf("failed to list revisions: %w", err)
+	}
+	sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].Revision < list.Items[j].Revision })
+	return list.Items, nil
+}
+
+// reconcileRevisions stores the pipeline's spec as its latest revision, and deletes the oldest revisions beyond the
+// history limit. It returns the number of the latest revision. A spec that is the same as an old revision (e.g. it was
+// rolled back) makes that revision the latest.
+func (r *PipelineReconciler) reconcileRevisions(ctx context.Context, pipeline *dfv1.Pipeline) (int64, error) {
+	revisions, err := r.listRevisions(ctx, pipeline)
+	if err != nil {
+		return 0, err
+	}
+	name := pipeline.GetRevisionName(util.MustHash(pipeline.Spec))
+	latest := int64(0)
+	var current *appsv1.ControllerRevision
+	for i, x := range revisions {
+		if x.Name == name {
+			current = &revisions[i]
+		}
+		latest = x.Revision
+	}
+	if current == nil {
+		data, err := json.Marshal(pipeline.Spec)
+		if err != nil {
+			return 0, err
+		}
+		obj := appsv1.ControllerRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       pipeline.Namespace,
+				Name:            name,
+				Labels:          map[string]string{dfv1.KeyPipelineName: pipeline.Name},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(pipeline.GetObjectMeta(), dfv1.PipelineGroupVersionKind)},
+			},
+			Data:     runtime.RawExtension{Raw: data},
+			Revision: latest + 1,
+		}
+		if err := r.Client.Create(ctx, &obj); err != nil {
+			return 0, fmt.Errorf("failed to create revision: %w", err)
+		}
+		revisions = append(revisions, obj)
+		current = &revisions[len(revisions)-1]
+	} else if current.Revision != latest {
+		current.Revision = latest + 1
+		if err := r.Client.Update(ctx, current); err != nil {
+			return 0, fmt.Errorf("failed to update revision: %w", err)
+		}
+	}
+	old := 0
+	for _, x := range revisions {
+		if x.Name != current.Name {
+			old++
+		}
+	}
+	for _, x := range revisions {
+		if old <= pipeline.Spec.GetRevisionHistoryLimit() {
+			break
+		}
+		if x.Name == current.Name {
+			continue
+		}
+		if err := r.Client.Delete(ctx, &x); client.IgnoreNotFound(err) != nil {
+			return 0, fmt.Errorf("failed to delete old revision: %w", err)
+		}
+		old--
+	}
+	return current.Revision, nil
+}
+
+// rollback restores the pipeline's spec from a revision, either its number, or "previous" for the revision before the
+// running one, and removes the rollback annotation.
+func (r *PipelineReconciler) rollback(ctx context.Context, pipeline *dfv1.Pipeline, to string) error {
+	log := r.Log.WithValues("pipeline", pipeline.Namespace+"/"+pipeline.Name)
+	revisions, err := r.listRevisions(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	if x, err := findRevision(revisions, to, pipeline.Status.Revision); err != nil {
+		log.Error(err, "failed to roll back", "to", to)
+	} else {
+		spec := dfv1.PipelineSpec{}
+		if err := json.Unmarshal(x.Data.Raw, &spec); err != nil {
+			return fmt.Errorf("failed to unmarshal revision %q: %w", x.Name, err)
+		}
+		log.Info("rolling back", "revision", x.Revision)
+		pipeline.Spec = spec
+	}
+	delete(pipeline.Annotations, dfv1.KeyRollback)
+	return r.Client.Update(ctx, pipeline)
+}
+
+// findRevision returns the revision with the number, or, for "previous", the latest revision before the current one.
+func findRevision(revisions []appsv1.ControllerRevision, to string, current int64) (*appsv1.ControllerRevision, error) {
+	if to == "previous" {
+		for i := len(revisions) - 1; i >= 0; i-- {
+			if x := revisions[i]; x.Revision < current {
+				return &x, nil
+			}
+		}
+		return nil, fmt.Errorf("there is no revision before revision %d", current)
+	}
+	n, err := strconv.ParseInt(to, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("revision %q must be a number or \"previous\"", to)
+	}
+	for _, x := range revisions {
+		if x.Revision == n {
+			return &x, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d not found", n)
+}
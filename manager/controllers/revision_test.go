@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func Test_findRevision(t *testing.T) {
+	revisions := []appsv1.ControllerRevision{{Revision: 1}, {Revision: 3}, {Revision: 4}}
+	t.Run("Previous", func(t *testing.T) {
+		x, err := findRevision(revisions, "previous", 4)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(3), x.Revision)
+		}
+	})
+	t.Run("NoPrevious", func(t *testing.T) {
+		_, err := findRevision(revisions, "previous", 1)
+		assert.EqualError(t, err, "there is no revision before revision 1")
+	})
+	t.Run("Number", func(t *testing.T) {
+		x, err := findRevision(revisions, "1", 4)
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(1), x.Revision)
+		}
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := findRevision(revisions, "2", 4)
+		assert.EqualError(t, err, "revision 2 not found")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := findRevision(revisions, "latest", 4)
+		assert.EqualError(t, err, `revision "latest" must be a number or "previous"`)
+	})
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/manager/controllers/scaling"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var getSourceTotals = scaling.GetSourceTotals
+
+// replicaSpecs returns the hash and spec of the pod each replica must run.
+type replicaSpecs func(replica int) (string, dfv1.StepSpec)
+
+func allReplicas(hash string, spec dfv1.StepSpec) replicaSpecs {
+	return func(int) (string, dfv1.StepSpec) { return hash, spec }
+}
+
+// reconcileRollout progresses the step's canary rollout, updating the step's rollout status. A canary starts when the
+// spec's hash differs from the stable hash. The canary runs on the highest replicas, and, once it has run for its
+// duration, it is promoted if the error rate of those replicas is acceptable, otherwise it is rolled back. It is rolled
+// back immediately if any of its pods fail.
+func (r *StepReconciler) reconcileRollout(ctx context.Context, step *dfv1.Step, hash string, pods []corev1.Pod) (replicaSpecs, time.Duration, error) {
+	log := r.Log.WithValues("step", step.Namespace+"/"+step.Name)
+	x := step.Spec.Rollout.Canary
+	s := step.Status.Rollout
+	if s == nil {
+		s = &dfv1.RolloutStatus{}
+		step.Status.Rollout = s
+	}
+	if hash == s.StableHash {
+		if s.Phase == dfv1.RolloutProgressing {
+			s.Phase, s.Message, s.CanaryHash = dfv1.RolloutPromoted, "canary reverted", ""
+		}
+		return allReplicas(hash, step.Spec), 0, nil
+	}
+	stableSpec, err := r.getRevision(ctx, step, s.StableHash)
+	// the first spec, e.g. the step has just been created, is stable
+	if apierr.IsNotFound(err) {
+		log.Info("no stable revision, replacing all replicas", "stableHash", s.StableHash, "hash", hash)
+		if err := r.createRevision(ctx, step, hash); err != nil {
+			return nil, 0, err
+		}
+		s.StableHash, s.CanaryHash = hash, ""
+		return allReplicas(hash, step.Spec), 0, nil
+	} else if err != nil {
+		return nil, 0, err
+	}
+	stableSpec.Replicas = step.Spec.Replicas
+	stable := allReplicas(s.StableHash, *stableSpec)
+	if hash == s.RolledBackHash {
+		return stable, 0, nil
+	}
+	if hash != s.CanaryHash {
+		log.Info("starting canary", "stableHash", s.StableHash, "canaryHash", hash)
+		s.Phase, s.Message, s.CanaryHash, s.StartedAt = dfv1.RolloutProgressing, "canary started", hash, metav1.Now()
+		r.Recorder.Eventf(step, "Normal", "CanaryStarted", "Started canary %.10s", hash)
+	}
+	replicas := int(step.Spec.Replicas)
+	firstCanaryReplica := replicas - x.GetReplicas(replicas)
+	canary := func(replica int) (string, dfv1.StepSpec) {
+		if replica >= firstCanaryReplica {
+			return hash, step.Spec
+		}
+		return stable(replica)
+	}
+	rollback := func(message string) (replicaSpecs, time.Duration, error) {
+		log.Info("rolling back canary", "canaryHash", hash, "message", message)
+		s.Phase, s.Message, s.CanaryHash, s.RolledBackHash = dfv1.RolloutRolledBack, message, "", hash
+		r.Recorder.Eventf(step, "Warning", "CanaryRolledBack", "Rolled back canary %.10s: %s", hash, message)
+		return stable, 0, nil
+	}
+	for _, pod := range pods {
+		if pod.GetAnnotations()[dfv1.KeyHash] != hash {
+			continue
+		}
+		if phase, reason, message := inferPhase(pod); phase == dfv1.StepFailed {
+			return rollback(fmt.Sprintf("pod %s failed: %s %s", pod.Name, reason, message))
+		}
+	}
+	if remaining := x.GetDuration() - time.Since(s.StartedAt.Time); remaining > 0 {
+		return canary, remaining, nil
+	}
+	if replicas == 0 {
+		s.Message = "waiting for the canary to be scaled up"
+		return canary, getConfig().updateInterval, nil
+	}
+	maxErrorRate, err := x.GetMaxErrorRate()
+	if err != nil {
+		return nil, 0, err
+	}
+	total, errs := float64(0), float64(0)
+	for replica := firstCanaryReplica; replica < replicas; replica++ {
+		t, e, err := getSourceTotals(*step, replica)
+		if err != nil {
+			s.Message = fmt.Sprintf("failed to get canary metrics: %v", err)
+			return canary, getConfig().updateInterval, nil
+		}
+		total, errs = total+t, errs+e
+	}
+	errorRate := float64(0)
+	if total > 0 {
+		errorRate = errs / total
+	}
+	if errorRate > maxErrorRate {
+		return rollback(fmt.Sprintf("error rate %.4f of %.0f messages is more than %v", errorRate, total, maxErrorRate))
+	}
+	if err := r.createRevision(ctx, step, hash); err != nil {
+		return nil, 0, err
+	}
+	if err := r.Client.Delete(ctx, &appsv1.ControllerRevision{ObjectMeta: metav1.ObjectMeta{Namespace: step.Namespace, Name: step.GetRevisionName(s.StableHash)}}); client.IgnoreNotFound(err) != nil {
+		return nil, 0, fmt.Errorf("failed to delete old revision: %w", err)
+	}
+	message := fmt.Sprintf("error rate %.4f of %.0f messages", errorRate, total)
+	log.Info("promoting canary", "canaryHash", hash, "message", message)
+	s.Phase, s.Message, s.StableHash, s.CanaryHash = dfv1.RolloutPromoted, message, hash, ""
+	r.Recorder.Eventf(step, "Normal", "CanaryPromoted", "Promoted canary %.10s: %s", hash, message)
+	return allReplicas(hash, step.Spec), 0, nil
+}
+
+// createRevision stores the step's spec, so that pods with that spec can be created while a canary runs.
+func (r *StepReconciler) createRevision(ctx context.Context, step *dfv1.Step, hash string) error {
+	data, err := json.Marshal(step.Spec)
+	if err != nil {
+		return err
+	}
+	err = r.Client.Create(ctx, &appsv1.ControllerRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       step.Namespace,
+			Name:            step.GetRevisionName(hash),
+			Labels:          map[string]string{dfv1.KeyPipelineName: step.GetLabels()[dfv1.KeyPipelineName], dfv1.KeyStepName: step.Spec.Name},
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(step.GetObjectMeta(), dfv1.StepGroupVersionKind)},
+		},
+		Data:     runtime.RawExtension{Raw: data},
+		Revision: step.Generation,
+	})
+	if apierr.IsAlreadyExists(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to create revision: %w", err)
+	}
+	return nil
+}
+
+func (r *StepReconciler) getRevision(ctx context.Context, step *dfv1.Step, hash string) (*dfv1.StepSpec, error) {
+	if hash == "" {
+		return nil, apierr.NewNotFound(appsv1.Resource("controllerrevisions"), "")
+	}
+	obj := &appsv1.ControllerRevision{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: step.Namespace, Name: step.GetRevisionName(hash)}, obj); err != nil {
+		return nil, err
+	}
+	spec := &dfv1.StepSpec{}
+	if err := json.Unmarshal(obj.Data.Raw, spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal revision %q: %w", obj.Name, err)
+	}
+	return spec, nil
+}
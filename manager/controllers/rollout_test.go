@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newFakeClient(t *testing.T, objs ...runtime.Object) client.Client {
+	scheme := runtime.NewScheme()
+	assert.NoError(t, corev1.AddToScheme(scheme))
+	assert.NoError(t, appsv1.AddToScheme(scheme))
+	assert.NoError(t, dfv1.AddToScheme(scheme))
+	return fake.NewFakeClientWithScheme(scheme, objs...)
+}
+
+func newRolloutReconciler(t *testing.T) *StepReconciler {
+	return &StepReconciler{
+		Client:   newFakeClient(t),
+		Log:      ctrl.Log.WithName("test"),
+		Recorder: record.NewFakeRecorder(10),
+	}
+}
+
+func newRolloutStep(image string) *dfv1.Step {
+	return &dfv1.Step{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-main", Labels: map[string]string{dfv1.KeyPipelineName: "my-pl"}},
+		Spec: dfv1.StepSpec{
+			Name:      "main",
+			Replicas:  5,
+			Container: &dfv1.Container{Image: image},
+			Rollout:   &dfv1.Rollout{Canary: &dfv1.CanaryRollout{Weight: 20, Duration: &metav1.Duration{Duration: time.Minute}}},
+		},
+	}
+}
+
+func images(specs replicaSpecs, replicas int) []string {
+	var images []string
+	for replica := 0; replica < replicas; replica++ {
+		_, spec := specs(replica)
+		images = append(images, spec.Container.Image)
+	}
+	return images
+}
+
+func Test_reconcileRollout(t *testing.T) {
+	defer func(f func(dfv1.Step, int) (float64, float64, error)) { getSourceTotals = f }(getSourceTotals)
+	ctx := context.Background()
+	// start a canary of v2, with v1 stable
+	start := func(t *testing.T) (*StepReconciler, *dfv1.Step) {
+		r := newRolloutReconciler(t)
+		step := newRolloutStep("v1")
+		specs, requeueAfter, err := r.reconcileRollout(ctx, step, "hash-v1", nil)
+		assert.NoError(t, err)
+		assert.Zero(t, requeueAfter)
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v1"}, images(specs, 5))
+		assert.Equal(t, "hash-v1", step.Status.Rollout.StableHash)
+		assert.Empty(t, step.Status.Rollout.Phase)
+		step.Spec.Container.Image = "v2"
+		specs, requeueAfter, err = r.reconcileRollout(ctx, step, "hash-v2", nil)
+		assert.NoError(t, err)
+		assert.Greater(t, int64(requeueAfter), int64(59*time.Second))
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v2"}, images(specs, 5))
+		hash, _ := specs(4)
+		assert.Equal(t, "hash-v2", hash)
+		assert.Equal(t, dfv1.RolloutProgressing, step.Status.Rollout.Phase)
+		assert.Equal(t, "hash-v2", step.Status.Rollout.CanaryHash)
+		return r, step
+	}
+	t.Run("Revert", func(t *testing.T) {
+		r, step := start(t)
+		step.Spec.Container.Image = "v1"
+		specs, _, err := r.reconcileRollout(ctx, step, "hash-v1", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v1"}, images(specs, 5))
+		assert.Equal(t, dfv1.RolloutPromoted, step.Status.Rollout.Phase)
+		assert.Equal(t, "canary reverted", step.Status.Rollout.Message)
+	})
+	t.Run("PodFailed", func(t *testing.T) {
+		r, step := start(t)
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-pl-main-4", Annotations: map[string]string{dfv1.KeyHash: "hash-v2"}},
+			Status: corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{{
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "not found"}},
+			}}},
+		}
+		specs, _, err := r.reconcileRollout(ctx, step, "hash-v2", []corev1.Pod{pod})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v1"}, images(specs, 5))
+		assert.Equal(t, dfv1.RolloutRolledBack, step.Status.Rollout.Phase)
+		assert.Equal(t, "pod my-pl-main-4 failed: ImagePullBackOff not found", step.Status.Rollout.Message)
+		assert.Equal(t, "hash-v2", step.Status.Rollout.RolledBackHash)
+		// the rolled back spec is not retried
+		specs, _, err = r.reconcileRollout(ctx, step, "hash-v2", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v1"}, images(specs, 5))
+		assert.Equal(t, dfv1.RolloutRolledBack, step.Status.Rollout.Phase)
+	})
+	t.Run("MetricsError", func(t *testing.T) {
+		r, step := start(t)
+		step.Status.Rollout.StartedAt = metav1.NewTime(time.Now().Add(-time.Hour))
+		getSourceTotals = func(dfv1.Step, int) (float64, float64, error) { return 0, 0, fmt.Errorf("no metrics") }
+		specs, requeueAfter, err := r.reconcileRollout(ctx, step, "hash-v2", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, getConfig().updateInterval, requeueAfter)
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v2"}, images(specs, 5))
+		assert.Equal(t, dfv1.RolloutProgressing, step.Status.Rollout.Phase)
+		assert.Equal(t, "failed to get canary metrics: no metrics", step.Status.Rollout.Message)
+	})
+	t.Run("RolledBack", func(t *testing.T) {
+		r, step := start(t)
+		step.Status.Rollout.StartedAt = metav1.NewTime(time.Now().Add(-time.Hour))
+		getSourceTotals = func(dfv1.Step, int) (float64, float64, error) { return 100, 2, nil }
+		specs, _, err := r.reconcileRollout(ctx, step, "hash-v2", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1", "v1", "v1", "v1", "v1"}, images(specs, 5))
+		assert.Equal(t, dfv1.RolloutRolledBack, step.Status.Rollout.Phase)
+		assert.Equal(t, "error rate 0.0200 of 100 messages is more than 0.01", step.Status.Rollout.Message)
+	})
+	t.Run("Promoted", func(t *testing.T) {
+		r, step := start(t)
+		step.Status.Rollout.StartedAt = metav1.NewTime(time.Now().Add(-time.Hour))
+		getSourceTotals = func(dfv1.Step, int) (float64, float64, error) { return 100, 1, nil }
+		specs, _, err := r.reconcileRollout(ctx, step, "hash-v2", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v2", "v2", "v2", "v2", "v2"}, images(specs, 5))
+		assert.Equal(t, dfv1.RolloutPromoted, step.Status.Rollout.Phase)
+		assert.Equal(t, "hash-v2", step.Status.Rollout.StableHash)
+		assert.Empty(t, step.Status.Rollout.CanaryHash)
+		revisions := &appsv1.ControllerRevisionList{}
+		assert.NoError(t, r.Client.List(ctx, revisions))
+		if assert.Len(t, revisions.Items, 1) {
+			assert.Equal(t, step.GetRevisionName("hash-v2"), revisions.Items[0].Name)
+		}
+	})
+}
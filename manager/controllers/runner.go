@@ -0,0 +1,13 @@
+package controllers
+
+import (
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// getRunner returns the runner image and pull policy of a step: the step's own, otherwise its namespace's, otherwise
+// the controller's.
+func getRunner(cfg config, namespace string, spec dfv1.StepSpec) (string, corev1.PullPolicy) {
+	x := cfg.namespaceRunners[namespace]
+	return spec.Runner.GetImage(x.GetImage(cfg.runnerImage)), spec.Runner.GetImagePullPolicy(x.GetImagePullPolicy(cfg.pullPolicy))
+}
@@ -0,0 +1,32 @@
+package controllers
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_getRunner(t *testing.T) {
+	cfg := config{
+		runnerImage:      "my-image",
+		pullPolicy:       corev1.PullIfNotPresent,
+		namespaceRunners: map[string]dfv1.Runner{"my-ns": {Image: "my-ns-image"}},
+	}
+	t.Run("Controller", func(t *testing.T) {
+		image, policy := getRunner(cfg, "other-ns", dfv1.StepSpec{})
+		assert.Equal(t, "my-image", image)
+		assert.Equal(t, corev1.PullIfNotPresent, policy)
+	})
+	t.Run("Namespace", func(t *testing.T) {
+		image, policy := getRunner(cfg, "my-ns", dfv1.StepSpec{})
+		assert.Equal(t, "my-ns-image", image)
+		assert.Equal(t, corev1.PullIfNotPresent, policy)
+	})
+	t.Run("Step", func(t *testing.T) {
+		image, policy := getRunner(cfg, "my-ns", dfv1.StepSpec{Runner: &dfv1.Runner{Image: "my-step-image", ImagePullPolicy: corev1.PullAlways}})
+		assert.Equal(t, "my-step-image", image)
+		assert.Equal(t, corev1.PullAlways, policy)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +18,7 @@ import (
 	pmodel "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -191,7 +193,7 @@ func (m *MetricsCacheHandler) pullMetrics(ctx context.Context, id int, keyCh <-c
 			logger.Info(fmt.Sprintf("stopped metrics cache worker %v", id))
 			return
 		case key := <-keyCh:
-			if pending, err := getPendingMetric(key); err != nil {
+			if metrics, err := getMetrics(key, 0); err != nil {
 				if errors.Is(err, errMetricsEndpointUnavailable) {
 					logger.Info("metrics endpoint unavailable, might have been scaled to 0", "key", key)
 					if v, existing := m.deadKeys.LoadOrStore(key, 1); existing {
@@ -201,17 +203,35 @@ func (m *MetricsCacheHandler) pullMetrics(ctx context.Context, id int, keyCh <-c
 					logger.Error(err, "failed to get pending messages", "key", key)
 				}
 			} else {
-				pendingKey := key + "/pending"
-				lastPendingKey := key + "/last-pending"
-				if d, ok := metricsCache.Peek(pendingKey); ok {
-					_ = metricsCache.Add(lastPendingKey, d)
+				recordPending(key, getPendingMetric(metrics), time.Now())
+				if replicas, err := getReplicaMetrics(key, metrics); err != nil {
+					logger.Error(err, "failed to get sources' pending messages", "key", key)
+				} else {
+					_ = metricsCache.Add(key+"/sources", getSourceStatuses(replicas))
+					recordSourceTotals(key, getSourceTotals(replicas))
 				}
-				_ = metricsCache.Add(pendingKey, pending)
 			}
 		}
 	}
 }
 
+// recordPending records the step's pending messages, the previously recorded pending messages, and when they started
+// growing, if they have grown every time since.
+func recordPending(key string, pending int64, now time.Time) {
+	pendingKey := key + "/pending"
+	lastPendingKey := key + "/last-pending"
+	growingSinceKey := key + "/growing-since"
+	if d, ok := metricsCache.Peek(pendingKey); ok {
+		_ = metricsCache.Add(lastPendingKey, d)
+		if pending <= d.(int64) {
+			metricsCache.Remove(growingSinceKey)
+		} else if !metricsCache.Contains(growingSinceKey) {
+			_ = metricsCache.Add(growingSinceKey, now)
+		}
+	}
+	_ = metricsCache.Add(pendingKey, pending)
+}
+
 func getMetrics(key string, replica int) (map[string]*pmodel.MetricFamily, error) {
 	// namespace/name/headless-svc-name
 	s := strings.Split(key, "/")
@@ -236,20 +256,146 @@ func getMetrics(key string, replica int) (map[string]*pmodel.MetricFamily, error
 	return mf, nil
 }
 
-func getPendingMetric(key string) (int64, error) {
-	metrics, err := getMetrics(key, 0)
-	if err != nil {
-		return 0, err
-	}
-	if f, ok := metrics["sources_pending"]; !ok {
-		return 0, nil
-	} else {
-		result := float64(0)
+func getPendingMetric(metrics map[string]*pmodel.MetricFamily) int64 {
+	result := float64(0)
+	if f, ok := metrics["sources_pending"]; ok {
 		for _, m := range f.Metric {
 			result += m.GetGauge().GetValue()
 		}
-		return int64(result), nil
 	}
+	return int64(result)
+}
+
+// getReplicaMetrics returns the metrics of every replica, starting with the lead replica, whose metrics we already have.
+func getReplicaMetrics(key string, leadMetrics map[string]*pmodel.MetricFamily) ([]map[string]*pmodel.MetricFamily, error) {
+	replicas := []map[string]*pmodel.MetricFamily{leadMetrics}
+	for replica := 1; ; replica++ {
+		metrics, err := getMetrics(key, replica)
+		if errors.Is(err, errMetricsEndpointUnavailable) {
+			return replicas, nil // we have seen every replica
+		} else if err != nil {
+			return nil, err
+		}
+		replicas = append(replicas, metrics)
+	}
+}
+
+// getSourceStatuses returns the pending messages of each source, as reported by the lead replica, and the pending
+// messages or lag of each partition, as reported by the replica the partition is assigned to. The source's watermark is the earliest of the
+// replicas' watermarks.
+func getSourceStatuses(replicas []map[string]*pmodel.MetricFamily) []dfv1.SourceStatus {
+	statuses := map[string]*dfv1.SourceStatus{}
+	status := func(sourceName string) *dfv1.SourceStatus {
+		if _, ok := statuses[sourceName]; !ok {
+			statuses[sourceName] = &dfv1.SourceStatus{Name: sourceName}
+		}
+		return statuses[sourceName]
+	}
+	for replica, metrics := range replicas {
+		if f, ok := metrics["sources_pending"]; ok && replica == 0 {
+			for _, m := range f.Metric {
+				status(getLabel(m, "sourceName")).Pending = uint64(m.GetGauge().GetValue())
+			}
+		}
+		if f, ok := metrics["sources_partition_pending"]; ok {
+			for _, m := range f.Metric {
+				x := status(getLabel(m, "sourceName"))
+				if x.PartitionPending == nil {
+					x.PartitionPending = map[string]uint64{}
+				}
+				x.PartitionPending[getLabel(m, "partition")] = uint64(m.GetGauge().GetValue())
+			}
+		}
+		if f, ok := metrics["sources_partition_lag_seconds"]; ok {
+			for _, m := range f.Metric {
+				x := status(getLabel(m, "sourceName"))
+				if x.PartitionLagSeconds == nil {
+					x.PartitionLagSeconds = map[string]uint64{}
+				}
+				x.PartitionLagSeconds[getLabel(m, "partition")] = uint64(m.GetGauge().GetValue())
+			}
+		}
+		if f, ok := metrics["sources_watermark"]; ok {
+			for _, m := range f.Metric {
+				x := status(getLabel(m, "sourceName"))
+				t := time.Unix(0, int64(m.GetGauge().GetValue()*float64(time.Second)))
+				if x.Watermark == nil || t.Before(x.Watermark.Time) {
+					x.Watermark = &metav1.Time{Time: t}
+				}
+			}
+		}
+		if f, ok := metrics["sources_last_error"]; ok {
+			for _, m := range f.Metric {
+				x := status(getLabel(m, "sourceName"))
+				t := time.Unix(0, int64(m.GetGauge().GetValue()*float64(time.Second)))
+				if x.LastError == nil || t.After(x.LastError.Time.Time) {
+					x.LastError = &dfv1.SourceError{Message: getLabel(m, "message"), Permanent: getLabel(m, "class") == "permanent", Time: metav1.Time{Time: t}}
+				}
+			}
+		}
+	}
+	var result []dfv1.SourceStatus
+	for _, x := range statuses {
+		result = append(result, *x)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// SourceTotals are the totals of a step's source metrics, across every replica.
+type SourceTotals struct {
+	Messages float64 // sources_total
+	Errors   float64 // sources_errors
+	// LatencySum and LatencyCount are the sum and count of sources_process_latency_seconds.
+	LatencySum   float64
+	LatencyCount float64
+}
+
+func (x SourceTotals) sub(y SourceTotals) SourceTotals {
+	return SourceTotals{
+		Messages:     x.Messages - y.Messages,
+		Errors:       x.Errors - y.Errors,
+		LatencySum:   x.LatencySum - y.LatencySum,
+		LatencyCount: x.LatencyCount - y.LatencyCount,
+	}
+}
+
+func getSourceTotals(replicas []map[string]*pmodel.MetricFamily) SourceTotals {
+	var x SourceTotals
+	for _, metrics := range replicas {
+		x.Messages += sumCounters(metrics, "sources_total")
+		x.Errors += sumCounters(metrics, "sources_errors")
+		if f, ok := metrics["sources_process_latency_seconds"]; ok {
+			for _, m := range f.Metric {
+				x.LatencySum += m.GetHistogram().GetSampleSum()
+				x.LatencyCount += float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return x
+}
+
+// recordSourceTotals records the step's source totals, and their change since they were last recorded. There is no
+// change if the totals went down, i.e. a replica restarted, or was scaled down, and its counters were lost.
+func recordSourceTotals(key string, totals SourceTotals) {
+	totalsKey := key + "/source-totals"
+	recentKey := key + "/recent-source-totals"
+	metricsCache.Remove(recentKey)
+	if d, ok := metricsCache.Peek(totalsKey); ok {
+		if x := totals.sub(d.(SourceTotals)); x.Messages >= 0 && x.Errors >= 0 && x.LatencySum >= 0 && x.LatencyCount >= 0 {
+			_ = metricsCache.Add(recentKey, x)
+		}
+	}
+	_ = metricsCache.Add(totalsKey, totals)
+}
+
+func getLabel(m *pmodel.Metric, name string) string {
+	for _, l := range m.Label {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
 }
 
 func GetPending(step dfv1.Step) (int64, bool) {
@@ -269,3 +415,62 @@ func GetLastPending(step dfv1.Step) (int64, bool) {
 		return p, yes
 	}
 }
+
+// GetPendingGrowingSince returns when the step's pending messages started growing, if they have grown every time they
+// were collected since.
+func GetPendingGrowingSince(step dfv1.Step) (time.Time, bool) {
+	if d, ok := metricsCache.Get(fmt.Sprintf("%s/%s/%s/growing-since", step.Namespace, step.Name, step.GetHeadlessServiceName())); !ok {
+		return time.Time{}, false
+	} else {
+		t, yes := d.(time.Time)
+		return t, yes
+	}
+}
+
+// GetSourceStatuses returns the status of the step's sources, as last collected.
+func GetSourceStatuses(step dfv1.Step) ([]dfv1.SourceStatus, bool) {
+	if d, ok := metricsCache.Get(fmt.Sprintf("%s/%s/%s/sources", step.Namespace, step.Name, step.GetHeadlessServiceName())); !ok {
+		return nil, false
+	} else {
+		p, yes := d.([]dfv1.SourceStatus)
+		return p, yes
+	}
+}
+
+// GetRecentSourceTotals returns the change in the step's source totals between the last two times they were collected.
+func GetRecentSourceTotals(step dfv1.Step) (SourceTotals, bool) {
+	if d, ok := metricsCache.Get(fmt.Sprintf("%s/%s/%s/recent-source-totals", step.Namespace, step.Name, step.GetHeadlessServiceName())); !ok {
+		return SourceTotals{}, false
+	} else {
+		x, yes := d.(SourceTotals)
+		return x, yes
+	}
+}
+
+// GetSourceTotals returns the total number of messages and errors of the replica's sources, since the replica started.
+func GetSourceTotals(step dfv1.Step, replica int) (total, errs float64, err error) {
+	metrics, err := getMetrics(fmt.Sprintf("%s/%s/%s", step.Namespace, step.Name, step.GetHeadlessServiceName()), replica)
+	if err != nil {
+		return 0, 0, err
+	}
+	return sumCounters(metrics, "sources_total"), sumCounters(metrics, "sources_errors"), nil
+}
+
+// GetSinkTotal returns the total number of messages written to the replica's sinks, since the replica started.
+func GetSinkTotal(step dfv1.Step, replica int) (float64, error) {
+	metrics, err := getMetrics(fmt.Sprintf("%s/%s/%s", step.Namespace, step.Name, step.GetHeadlessServiceName()), replica)
+	if err != nil {
+		return 0, err
+	}
+	return sumCounters(metrics, "sinks_total"), nil
+}
+
+func sumCounters(metrics map[string]*pmodel.MetricFamily, name string) float64 {
+	v := float64(0)
+	if f, ok := metrics[name]; ok {
+		for _, m := range f.Metric {
+			v += m.GetCounter().GetValue()
+		}
+	}
+	return v
+}
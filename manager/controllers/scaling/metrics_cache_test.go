@@ -0,0 +1,92 @@
+package scaling
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	pmodel "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getSourceStatuses(t *testing.T) {
+	var parser expfmt.TextParser
+	metrics, err := parser.TextToMetricFamilies(strings.NewReader(`# TYPE sources_pending gauge
+sources_pending{sourceName="a"} 5
+sources_pending{sourceName="b"} 2
+# TYPE sources_partition_pending gauge
+sources_partition_pending{partition="my-topic-0",sourceName="a"} 1
+sources_partition_pending{partition="my-topic-1",sourceName="a"} 4
+# TYPE sources_partition_lag_seconds gauge
+sources_partition_lag_seconds{partition="shardId-000000000000",sourceName="c"} 12.5
+# TYPE sources_watermark gauge
+sources_watermark{replica="0",sourceName="a"} 1.6304976e+09
+# TYPE sources_last_error gauge
+sources_last_error{class="permanent",message="HTTP request failed: \"400 Bad Request\" \"\"",replica="0",sourceName="b"} 1.6304976e+09
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), getPendingMetric(metrics))
+	// the other replicas cannot be found, so only the lead replica's metrics are used
+	replicas, err := getReplicaMetrics("my-ns/my-step/my-svc.invalid", metrics)
+	assert.NoError(t, err)
+	assert.Len(t, replicas, 1)
+	sources := getSourceStatuses(replicas)
+	assert.Equal(t, []dfv1.SourceStatus{
+		{Name: "a", Pending: 5, PartitionPending: map[string]uint64{"my-topic-0": 1, "my-topic-1": 4}, Watermark: &metav1.Time{Time: time.Unix(1630497600, 0)}},
+		{Name: "b", Pending: 2, LastError: &dfv1.SourceError{Message: `HTTP request failed: "400 Bad Request" ""`, Permanent: true, Time: metav1.Time{Time: time.Unix(1630497600, 0)}}},
+		{Name: "c", PartitionLagSeconds: map[string]uint64{"shardId-000000000000": 12}},
+	}, sources)
+}
+
+func Test_recordPending(t *testing.T) {
+	step := dfv1.Step{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-growing"}}
+	key := "my-ns/my-pl-growing/" + step.GetHeadlessServiceName()
+	now := time.Now()
+	recordPending(key, 1, now)
+	_, ok := GetPendingGrowingSince(step)
+	assert.False(t, ok, "one sample is not growth")
+	recordPending(key, 2, now.Add(time.Minute))
+	recordPending(key, 3, now.Add(2*time.Minute))
+	since, ok := GetPendingGrowingSince(step)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(time.Minute), since)
+	lastPending, _ := GetLastPending(step)
+	assert.Equal(t, int64(2), lastPending)
+	recordPending(key, 3, now.Add(3*time.Minute))
+	_, ok = GetPendingGrowingSince(step)
+	assert.False(t, ok, "not growing")
+}
+
+func Test_getSourceTotals(t *testing.T) {
+	var parser expfmt.TextParser
+	metrics, err := parser.TextToMetricFamilies(strings.NewReader(`# TYPE sources_total counter
+sources_total{replica="0",sourceName="a"} 10
+sources_total{replica="0",sourceName="b"} 5
+# TYPE sources_errors counter
+sources_errors{replica="0",sourceName="a"} 1
+# TYPE sources_process_latency_seconds histogram
+sources_process_latency_seconds_bucket{replica="0",sourceName="a",le="+Inf"} 15
+sources_process_latency_seconds_sum{replica="0",sourceName="a"} 30
+sources_process_latency_seconds_count{replica="0",sourceName="a"} 15
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, SourceTotals{Messages: 30, Errors: 2, LatencySum: 60, LatencyCount: 30}, getSourceTotals([]map[string]*pmodel.MetricFamily{metrics, metrics}))
+}
+
+func Test_recordSourceTotals(t *testing.T) {
+	step := dfv1.Step{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-totals"}}
+	key := "my-ns/my-pl-totals/" + step.GetHeadlessServiceName()
+	recordSourceTotals(key, SourceTotals{Messages: 10, Errors: 1, LatencySum: 5, LatencyCount: 10})
+	_, ok := GetRecentSourceTotals(step)
+	assert.False(t, ok, "one sample has no change")
+	recordSourceTotals(key, SourceTotals{Messages: 30, Errors: 2, LatencySum: 25, LatencyCount: 30})
+	recent, ok := GetRecentSourceTotals(step)
+	assert.True(t, ok)
+	assert.Equal(t, SourceTotals{Messages: 20, Errors: 1, LatencySum: 20, LatencyCount: 20}, recent)
+	recordSourceTotals(key, SourceTotals{Messages: 5})
+	_, ok = GetRecentSourceTotals(step)
+	assert.False(t, ok, "a replica restarted")
+}
@@ -3,6 +3,7 @@ package scaling
 import (
 	"fmt"
 	"math/rand"
+	"sync/atomic"
 	"time"
 
 	"github.com/antonmedv/expr"
@@ -11,29 +12,38 @@ import (
 )
 
 var (
-	logger              = sharedutil.NewLogger()
-	defaultScalingDelay = sharedutil.GetEnvDuration(dfv1.EnvScalingDelay, time.Minute)
-	defaultPeekDelay    = sharedutil.GetEnvDuration(dfv1.EnvPeekDelay, 4*time.Minute)
+	logger = sharedutil.NewLogger()
+	// the default delays are set by the controller's config, which can change at any time
+	defaultScalingDelay = int64(time.Minute)
+	defaultPeekDelay    = int64(4 * time.Minute)
 )
 
-func init() {
-	logger.Info("scaling config",
-		"defaultScalingDelay", defaultScalingDelay.String(),
-		"defaultPeekDelay", defaultPeekDelay.String(),
-	)
+// SetDefaultDelays sets the default scaling and peek delays, available to expressions as `defaultScalingDelay` and
+// `defaultPeekDelay`.
+func SetDefaultDelays(scalingDelay, peekDelay time.Duration) {
+	atomic.StoreInt64(&defaultScalingDelay, int64(scalingDelay))
+	atomic.StoreInt64(&defaultPeekDelay, int64(peekDelay))
+}
+
+func getDefaultScalingDelay() time.Duration {
+	return time.Duration(atomic.LoadInt64(&defaultScalingDelay))
+}
+
+func getDefaultPeekDelay() time.Duration {
+	return time.Duration(atomic.LoadInt64(&defaultPeekDelay))
 }
 
 func GetDesiredReplicas(step dfv1.Step) (int, error) {
 	currentReplicas := int(step.Status.Replicas)
 	lastScaledAt := time.Since(step.Status.LastScaledAt.Time)
 	scale := step.Spec.Scale
-	scalingDelay, err := evalAsDuration(scale.ScalingDelay, map[string]interface{}{"defaultScalingDelay": defaultScalingDelay})
+	scalingDelay, err := evalAsDuration(scale.ScalingDelay, map[string]interface{}{"defaultScalingDelay": getDefaultScalingDelay()})
 	if err != nil {
 		return 0, fmt.Errorf("failed to evaluate %q: %w", scale.ScalingDelay, err)
 	} else if lastScaledAt < scalingDelay {
 		return currentReplicas, nil
 	}
-	peekDelay, err := evalAsDuration(scale.PeekDelay, map[string]interface{}{"defaultPeekDelay": defaultPeekDelay})
+	peekDelay, err := evalAsDuration(scale.PeekDelay, map[string]interface{}{"defaultPeekDelay": getDefaultPeekDelay()})
 	if err != nil {
 		return 0, fmt.Errorf("failed to evaluate %q: %w", scale.PeekDelay, err)
 	}
@@ -68,6 +78,9 @@ func GetDesiredReplicas(step dfv1.Step) (int, error) {
 			return 0, fmt.Errorf("failed to evaluate %q as int, got %T", scale.DesiredReplicas, r)
 		}
 	}
+	if m := int(scale.MaxReplicas); m > 0 && desiredReplicas > m {
+		desiredReplicas = m
+	}
 	logger.Info("desired replicas", "expr", scale.DesiredReplicas, "currentReplicas", currentReplicas, "pending", pending, "pendingDelta", pendingDelta, "desiredReplicas", desiredReplicas, "scalingDelay", scalingDelay.String(), "peekDelay", peekDelay.String())
 	// do we need to peek? currentReplicas and desiredReplicas must both be zero
 	if currentReplicas <= 0 && desiredReplicas == 0 && lastScaledAt > peekDelay {
@@ -76,6 +89,22 @@ func GetDesiredReplicas(step dfv1.Step) (int, error) {
 	return desiredReplicas, nil
 }
 
+// IsSlowConsumer returns true if the step is at its maxReplicas, and its pending messages have grown for its
+// slowConsumerDelay since it got there.
+func IsSlowConsumer(step dfv1.Step, now time.Time) bool {
+	if m := step.Spec.Scale.MaxReplicas; m == 0 || step.Spec.Replicas < m {
+		return false
+	}
+	since, ok := GetPendingGrowingSince(step)
+	if !ok {
+		return false
+	}
+	if t := step.Status.LastScaledAt.Time; t.After(since) {
+		since = t
+	}
+	return now.Sub(since) >= step.Spec.Scale.GetSlowConsumerDelay()
+}
+
 func evalAsDuration(input string, env map[string]interface{}) (time.Duration, error) {
 	if r, err := expr.Eval(input, env); err != nil {
 		return 0, err
@@ -97,7 +126,7 @@ func RequeueAfter(step dfv1.Step) (time.Duration, error) {
 		return 0, nil
 	}
 	if scalingDelay, err := evalAsDuration(scale.ScalingDelay, map[string]interface{}{
-		"defaultScalingDelay": defaultScalingDelay,
+		"defaultScalingDelay": getDefaultScalingDelay(),
 	}); err != nil {
 		return 0, fmt.Errorf("failed to evaluate %q: %w", scale.ScalingDelay, err)
 	} else {
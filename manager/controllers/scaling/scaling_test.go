@@ -68,6 +68,58 @@ func TestGetDesiredReplicas(t *testing.T) {
 	})
 }
 
+func TestGetDesiredReplicas_MaxReplicas(t *testing.T) {
+	step := dfv1.Step{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-max"},
+		Spec: dfv1.StepSpec{
+			Scale: dfv1.Scale{
+				PeekDelay:       `defaultPeekDelay`,
+				ScalingDelay:    "defaultScalingDelay",
+				DesiredReplicas: "5",
+				MaxReplicas:     3,
+			},
+		},
+		Status: dfv1.StepStatus{Replicas: 1},
+	}
+	key := "my-ns/my-pl-max/" + step.GetHeadlessServiceName()
+	recordPending(key, 1, time.Now())
+	recordPending(key, 2, time.Now())
+	replicas, err := GetDesiredReplicas(step)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, replicas)
+}
+
+func TestIsSlowConsumer(t *testing.T) {
+	now := time.Now()
+	step := dfv1.Step{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-slow"},
+		Spec:       dfv1.StepSpec{Replicas: 2, Scale: dfv1.Scale{MaxReplicas: 2}},
+		Status:     dfv1.StepStatus{LastScaledAt: metav1.Time{Time: now.Add(-time.Hour)}},
+	}
+	key := "my-ns/my-pl-slow/" + step.GetHeadlessServiceName()
+	recordPending(key, 1, now.Add(-20*time.Minute))
+	assert.False(t, IsSlowConsumer(step, now), "not growing")
+	recordPending(key, 2, now.Add(-15*time.Minute))
+	recordPending(key, 3, now.Add(-10*time.Minute))
+	assert.True(t, IsSlowConsumer(step, now))
+	assert.False(t, IsSlowConsumer(step, now.Add(-10*time.Minute)), "not for long enough")
+	t.Run("BelowMaxReplicas", func(t *testing.T) {
+		step := *step.DeepCopy()
+		step.Spec.Replicas = 1
+		assert.False(t, IsSlowConsumer(step, now))
+	})
+	t.Run("NoMaxReplicas", func(t *testing.T) {
+		step := *step.DeepCopy()
+		step.Spec.Scale.MaxReplicas = 0
+		assert.False(t, IsSlowConsumer(step, now))
+	})
+	t.Run("RecentlyScaled", func(t *testing.T) {
+		step := *step.DeepCopy()
+		step.Status.LastScaledAt = metav1.Time{Time: now.Add(-time.Minute)}
+		assert.False(t, IsSlowConsumer(step, now))
+	})
+}
+
 func TestRequeueAfter(t *testing.T) {
 	t.Run("ScaledUp", func(t *testing.T) {
 		requeueAfter, err := RequeueAfter(dfv1.Step{})
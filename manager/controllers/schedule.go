@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxMissedRuns is how many missed runs we look through for the latest, e.g. after the controller has been down.
+const maxMissedRuns = 100
+
+// reconcileSchedule creates the scheduled pipeline's runs when they are due, and deletes old runs.
+func (r *PipelineReconciler) reconcileSchedule(ctx context.Context, pipeline *dfv1.Pipeline, revision int64) (ctrl.Result, error) {
+	log := r.Log.WithValues("pipeline", pipeline.Namespace+"/"+pipeline.Name)
+	x := *pipeline.Spec.Schedule
+	newStatus := *pipeline.Status.DeepCopy()
+	newStatus.Revision = revision
+	newStatus.ObservedGeneration = pipeline.Generation
+	if newStatus.Schedule == nil {
+		newStatus.Schedule = &dfv1.ScheduleStatus{}
+	}
+	schedule, err := x.GetSchedule()
+	if err != nil {
+		newStatus.Phase, newStatus.Message = dfv1.PipelineFailed, err.Error()
+		return ctrl.Result{}, r.updateStatus(ctx, pipeline, newStatus)
+	}
+
+	runs := &dfv1.PipelineList{}
+	selector, _ := labels.Parse(dfv1.KeyScheduleName + "=" + pipeline.Name)
+	if err := r.Client.List(ctx, runs, &client.ListOptions{Namespace: pipeline.Namespace, LabelSelector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list runs: %w", err)
+	}
+	sort.Slice(runs.Items, func(i, j int) bool {
+		return runs.Items[i].CreationTimestamp.Before(&runs.Items[j].CreationTimestamp)
+	})
+	var active, succeeded, failed []dfv1.Pipeline
+	for _, run := range runs.Items {
+		switch run.Status.Phase {
+		case dfv1.PipelineSucceeded:
+			succeeded = append(succeeded, run)
+		case dfv1.PipelineFailed:
+			failed = append(failed, run)
+		default:
+			active = append(active, run)
+		}
+	}
+	for _, old := range append(excessRuns(succeeded, x.GetSuccessfulRunsHistoryLimit()), excessRuns(failed, x.GetFailedRunsHistoryLimit())...) {
+		log.Info("deleting old run", "run", old.Name, "phase", old.Status.Phase)
+		if err := r.Client.Delete(ctx, &old); client.IgnoreNotFound(err) != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to delete old run %s: %w", old.Name, err)
+		}
+	}
+
+	last := pipeline.CreationTimestamp.Time
+	if t := newStatus.Schedule.LastScheduledTime; t != nil {
+		last = t.Time
+	}
+	now := time.Now()
+	due, next := getScheduledTime(schedule, last, now)
+	if !due.IsZero() {
+		newStatus.Schedule.LastScheduledTime = &metav1.Time{Time: due}
+		if x.Suspend {
+			log.Info("skipping run, schedule is suspended", "scheduledTime", due)
+		} else if policy := x.GetConcurrencyPolicy(); policy == dfv1.ConcurrencyPolicyForbid && len(active) > 0 {
+			log.Info("skipping run, previous run is still running", "scheduledTime", due)
+		} else {
+			if policy == dfv1.ConcurrencyPolicyReplace {
+				for _, run := range active {
+					log.Info("deleting run, it is replaced", "run", run.Name)
+					if err := r.Client.Delete(ctx, &run); client.IgnoreNotFound(err) != nil {
+						return ctrl.Result{}, fmt.Errorf("failed to delete replaced run %s: %w", run.Name, err)
+					}
+				}
+				active = nil
+			}
+			run, err := r.createRun(ctx, pipeline, due)
+			if err != nil {
+				return ctrl.Result{}, err
+			}
+			log.Info("created run", "run", run.Name, "scheduledTime", due)
+			active = append(active, *run)
+		}
+	}
+
+	newStatus.Schedule.Active = nil
+	for _, run := range active {
+		newStatus.Schedule.Active = append(newStatus.Schedule.Active, run.Name)
+	}
+	newStatus.Phase = dfv1.PipelineRunning
+	ss := []string{fmt.Sprintf("%d active", len(active))}
+	if x.Suspend {
+		ss = append(ss, "suspended")
+	} else {
+		ss = append(ss, "next run at "+next.UTC().Format(time.RFC3339))
+	}
+	newStatus.Message = strings.Join(ss, ", ")
+	if err := r.updateStatus(ctx, pipeline, newStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: time.Until(next)}, nil
+}
+
+// createRun creates a run of the scheduled pipeline, named after the time it was due, so it is only created once.
+func (r *PipelineReconciler) createRun(ctx context.Context, pipeline *dfv1.Pipeline, due time.Time) (*dfv1.Pipeline, error) {
+	spec := *pipeline.Spec.DeepCopy()
+	spec.Schedule = nil
+	run := &dfv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pipeline.Namespace,
+			Name:      fmt.Sprintf("%s-%d", pipeline.Name, due.Unix()),
+			Labels:    map[string]string{dfv1.KeyScheduleName: pipeline.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(pipeline.GetObjectMeta(), dfv1.PipelineGroupVersionKind),
+			},
+		},
+		Spec: spec,
+	}
+	if err := r.Client.Create(ctx, run); util.IgnoreAlreadyExists(err) != nil {
+		return nil, fmt.Errorf("failed to create run %s: %w", run.Name, err)
+	}
+	return run, nil
+}
+
+func (r *PipelineReconciler) updateStatus(ctx context.Context, pipeline *dfv1.Pipeline, newStatus dfv1.PipelineStatus) error {
+	if notEqual, patch := util.NotEqual(pipeline.Status, newStatus); notEqual {
+		r.Log.Info("updating pipeline status", "pipeline", pipeline.Namespace+"/"+pipeline.Name, "patch", patch)
+		newStatus.LastUpdated = metav1.Now()
+		pipeline.Status = newStatus
+		if err := r.Status().Update(ctx, pipeline); util.IgnoreConflict(err) != nil { // conflict is ok, we will reconcile again soon
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+	}
+	return nil
+}
+
+// getScheduledTime returns the latest time a run was due, after the last scheduled time and at or before now (zero if
+// none was due), and when the next run is due. Only the latest of many missed runs is due.
+func getScheduledTime(schedule cron.Schedule, last, now time.Time) (due, next time.Time) {
+	next = schedule.Next(last)
+	for i := 0; !next.After(now) && i < maxMissedRuns; i++ {
+		due, next = next, schedule.Next(next)
+	}
+	if !next.After(now) { // too many missed runs to look through, so we run now
+		due, next = now, schedule.Next(now)
+	}
+	return due, next
+}
+
+// excessRuns returns the oldest runs beyond the limit, the runs must be sorted oldest first.
+func excessRuns(runs []dfv1.Pipeline, limit int) []dfv1.Pipeline {
+	if limit < 0 || len(runs) <= limit {
+		return nil
+	}
+	return runs[:len(runs)-limit]
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_getScheduledTime(t *testing.T) {
+	schedule, err := dfv1.PipelineSchedule{Cron: "0 * * * *"}.GetSchedule()
+	assert.NoError(t, err)
+	at := func(hour, minute int) time.Time { return time.Date(2021, 1, 1, hour, minute, 0, 0, time.UTC) }
+	t.Run("NotDue", func(t *testing.T) {
+		due, next := getScheduledTime(schedule, at(1, 0), at(1, 30))
+		assert.True(t, due.IsZero())
+		assert.Equal(t, at(2, 0), next)
+	})
+	t.Run("Due", func(t *testing.T) {
+		due, next := getScheduledTime(schedule, at(1, 0), at(2, 0))
+		assert.Equal(t, at(2, 0), due)
+		assert.Equal(t, at(3, 0), next)
+	})
+	t.Run("Missed", func(t *testing.T) {
+		due, next := getScheduledTime(schedule, at(1, 0), at(5, 30))
+		assert.Equal(t, at(5, 0), due)
+		assert.Equal(t, at(6, 0), next)
+	})
+	t.Run("TooManyMissed", func(t *testing.T) {
+		now := at(1, 30).AddDate(0, 1, 0)
+		due, next := getScheduledTime(schedule, at(1, 0), now)
+		assert.Equal(t, now, due)
+		assert.Equal(t, now.Add(30*time.Minute), next)
+	})
+}
+
+func Test_excessRuns(t *testing.T) {
+	runs := []dfv1.Pipeline{{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, {ObjectMeta: metav1.ObjectMeta{Name: "b"}}, {ObjectMeta: metav1.ObjectMeta{Name: "c"}}}
+	assert.Empty(t, excessRuns(runs, 3))
+	assert.Equal(t, runs[:1], excessRuns(runs, 2))
+	assert.Equal(t, runs, excessRuns(runs, 0))
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestStepReconciler_createSecret(t *testing.T) {
+	ctx := context.Background()
+	r := &StepReconciler{Client: newFakeClient(t)}
+	step := &dfv1.Step{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-main"},
+		Spec:       dfv1.StepSpec{Sources: []dfv1.Source{{Name: "a"}}},
+	}
+	key := client.ObjectKey{Namespace: "my-ns", Name: "my-pl-main"}
+	assert.NoError(t, r.createSecret(ctx, step, nil))
+	secret := &corev1.Secret{}
+	assert.NoError(t, r.Client.Get(ctx, key, secret))
+	a := string(secret.Data["sources.a.http.authorization"])
+	assert.Regexp(t, "^Bearer .+", a)
+	t.Run("SourceAdded", func(t *testing.T) {
+		step.Spec.Sources = append(step.Spec.Sources, dfv1.Source{Name: "b"})
+		assert.NoError(t, r.createSecret(ctx, step, nil))
+		assert.NoError(t, r.Client.Get(ctx, key, secret))
+		assert.Equal(t, a, string(secret.Data["sources.a.http.authorization"]), "existing authorization unchanged")
+		assert.Regexp(t, "^Bearer .+", string(secret.Data["sources.b.http.authorization"]))
+	})
+}
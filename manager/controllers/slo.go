@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/manager/controllers/scaling"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	getPending            = scaling.GetPending
+	getRecentSourceTotals = scaling.GetRecentSourceTotals
+)
+
+// evaluateSLO returns the pipeline's service level indicators, from its steps' metrics as last collected, and the
+// objectives they do not meet.
+func evaluateSLO(x dfv1.SLO, steps []dfv1.Step) (*dfv1.SLOStatus, error) {
+	maxErrorRate, err := x.GetMaxErrorRate()
+	if err != nil {
+		return nil, err
+	}
+	s := &dfv1.SLOStatus{}
+	messages, errs, latency, latencyKnown := float64(0), float64(0), float64(0), false
+	for _, step := range steps {
+		if pending, ok := getPending(step); ok && pending > 0 {
+			s.Pending += uint64(pending)
+		}
+		if t, ok := getRecentSourceTotals(step); ok {
+			messages, errs = messages+t.Messages, errs+t.Errors
+			if t.LatencyCount > 0 {
+				latency += t.LatencySum / t.LatencyCount
+				latencyKnown = true
+			}
+		}
+	}
+	if x.MaxPending > 0 && s.Pending > x.MaxPending {
+		s.Violations = append(s.Violations, fmt.Sprintf("pending %d is more than %d", s.Pending, x.MaxPending))
+	}
+	if latencyKnown {
+		s.Latency = &metav1.Duration{Duration: time.Duration(latency * float64(time.Second)).Round(time.Millisecond)}
+		if maxLatency := x.GetMaxLatency(); maxLatency > 0 && s.Latency.Duration > maxLatency {
+			s.Violations = append(s.Violations, fmt.Sprintf("latency %v is more than %v", s.Latency.Duration, maxLatency))
+		}
+	}
+	if messages > 0 {
+		errorRate := errs / messages
+		s.ErrorRate = fmt.Sprintf("%.4f", errorRate)
+		if errorRate > maxErrorRate {
+			s.Violations = append(s.Violations, fmt.Sprintf("error rate %.4f of %.0f messages is more than %v", errorRate, messages, maxErrorRate))
+		}
+	}
+	return s, nil
+}
+
+// reconcileSLO evaluates the pipeline's SLO, and sets its SLOViolated condition, recording an event when it changes.
+func (r *PipelineReconciler) reconcileSLO(pipeline *dfv1.Pipeline, status *dfv1.PipelineStatus, steps []dfv1.Step) error {
+	if pipeline.Spec.SLO == nil {
+		status.SLO = nil
+		if len(status.Conditions) > 0 { // guard only needed because RemoveStatusCondition panics on zero length conditions
+			meta.RemoveStatusCondition(&status.Conditions, dfv1.ConditionSLOViolated)
+		}
+		return nil
+	}
+	s, err := evaluateSLO(*pipeline.Spec.SLO, steps)
+	if err != nil {
+		return fmt.Errorf("failed to evaluate slo: %w", err)
+	}
+	status.SLO = s
+	violated := meta.IsStatusConditionTrue(status.Conditions, dfv1.ConditionSLOViolated)
+	if len(s.Violations) > 0 {
+		message := strings.Join(s.Violations, ", ")
+		if !violated {
+			r.Recorder.Event(pipeline, "Warning", dfv1.ConditionSLOViolated, message)
+		}
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: dfv1.ConditionSLOViolated, Status: metav1.ConditionTrue, Reason: "ObjectivesNotMet", Message: message})
+	} else {
+		if violated {
+			r.Recorder.Event(pipeline, "Normal", "SLOMet", "all objectives are met")
+		}
+		meta.SetStatusCondition(&status.Conditions, metav1.Condition{Type: dfv1.ConditionSLOViolated, Status: metav1.ConditionFalse, Reason: "ObjectivesMet"})
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/manager/controllers/scaling"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_reconcileSLO(t *testing.T) {
+	defer func() { getPending, getRecentSourceTotals = scaling.GetPending, scaling.GetRecentSourceTotals }()
+	pending := map[string]int64{"a": 10, "b": 5}
+	totals := map[string]scaling.SourceTotals{
+		"a": {Messages: 100, Errors: 1, LatencySum: 50, LatencyCount: 100},
+		"b": {Messages: 100, Errors: 0, LatencySum: 100, LatencyCount: 100},
+	}
+	getPending = func(step dfv1.Step) (int64, bool) {
+		p, ok := pending[step.Name]
+		return p, ok
+	}
+	getRecentSourceTotals = func(step dfv1.Step) (scaling.SourceTotals, bool) {
+		x, ok := totals[step.Name]
+		return x, ok
+	}
+	steps := []dfv1.Step{{ObjectMeta: metav1.ObjectMeta{Name: "a"}}, {ObjectMeta: metav1.ObjectMeta{Name: "b"}}}
+	recorder := record.NewFakeRecorder(10)
+	r := &PipelineReconciler{Recorder: recorder}
+	pl := &dfv1.Pipeline{}
+	status := &dfv1.PipelineStatus{}
+	t.Run("NoSLO", func(t *testing.T) {
+		assert.NoError(t, r.reconcileSLO(pl, status, steps))
+		assert.Nil(t, status.SLO)
+		assert.Empty(t, status.Conditions)
+	})
+	t.Run("Met", func(t *testing.T) {
+		pl.Spec.SLO = &dfv1.SLO{MaxPending: 15, MaxLatency: &metav1.Duration{Duration: 2 * time.Second}, MaxErrorRate: "0.01"}
+		assert.NoError(t, r.reconcileSLO(pl, status, steps))
+		assert.Equal(t, &dfv1.SLOStatus{Pending: 15, Latency: &metav1.Duration{Duration: 1500 * time.Millisecond}, ErrorRate: "0.0050"}, status.SLO)
+		assert.True(t, meta.IsStatusConditionFalse(status.Conditions, dfv1.ConditionSLOViolated))
+		assert.Empty(t, recorder.Events)
+	})
+	t.Run("Violated", func(t *testing.T) {
+		pending["a"] = 11
+		totals["a"] = scaling.SourceTotals{Messages: 100, Errors: 3, LatencySum: 200, LatencyCount: 100}
+		assert.NoError(t, r.reconcileSLO(pl, status, steps))
+		assert.Equal(t, []string{
+			"pending 16 is more than 15",
+			"latency 3s is more than 2s",
+			"error rate 0.0150 of 200 messages is more than 0.01",
+		}, status.SLO.Violations)
+		assert.True(t, meta.IsStatusConditionTrue(status.Conditions, dfv1.ConditionSLOViolated))
+		assert.Equal(t, "Warning SLOViolated pending 16 is more than 15, latency 3s is more than 2s, error rate 0.0150 of 200 messages is more than 0.01", <-recorder.Events)
+		assert.NoError(t, r.reconcileSLO(pl, status, steps))
+		assert.Empty(t, recorder.Events, "only one event")
+	})
+	t.Run("NoMessages", func(t *testing.T) {
+		delete(pending, "a")
+		totals = nil
+		assert.NoError(t, r.reconcileSLO(pl, status, steps))
+		assert.Equal(t, &dfv1.SLOStatus{Pending: 5}, status.SLO)
+		assert.True(t, meta.IsStatusConditionFalse(status.Conditions, dfv1.ConditionSLOViolated))
+		assert.Equal(t, "Normal SLOMet all objectives are met", <-recorder.Events)
+	})
+	t.Run("Removed", func(t *testing.T) {
+		pl.Spec.SLO = nil
+		assert.NoError(t, r.reconcileSLO(pl, status, steps))
+		assert.Nil(t, status.SLO)
+		assert.Empty(t, status.Conditions)
+	})
+}
@@ -0,0 +1,28 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// how often to check steps with maxReplicas, if they are not otherwise requeued
+const slowConsumerRequeueAfter = time.Minute
+
+// reconcileSlowConsumer sets the step's SlowConsumer condition, and records an event when it becomes true.
+func (r *StepReconciler) reconcileSlowConsumer(step *dfv1.Step, slow bool) {
+	if !slow {
+		if len(step.Status.Conditions) > 0 { // guard only needed because RemoveStatusCondition panics on zero length conditions
+			meta.RemoveStatusCondition(&step.Status.Conditions, dfv1.ConditionSlowConsumer)
+		}
+		return
+	}
+	message := fmt.Sprintf("pending messages have grown for more than %v at maxReplicas (%d), the step cannot keep up with its sources", step.Spec.Scale.GetSlowConsumerDelay(), step.Spec.Scale.MaxReplicas)
+	if !meta.IsStatusConditionTrue(step.Status.Conditions, dfv1.ConditionSlowConsumer) {
+		r.Recorder.Event(step, "Warning", dfv1.ConditionSlowConsumer, message)
+	}
+	meta.SetStatusCondition(&step.Status.Conditions, metav1.Condition{Type: dfv1.ConditionSlowConsumer, Status: metav1.ConditionTrue, Reason: "PendingGrowing", Message: message})
+}
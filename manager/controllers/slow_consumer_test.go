@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/tools/record"
+)
+
+func Test_reconcileSlowConsumer(t *testing.T) {
+	recorder := record.NewFakeRecorder(10)
+	r := &StepReconciler{Recorder: recorder}
+	step := &dfv1.Step{Spec: dfv1.StepSpec{Scale: dfv1.Scale{MaxReplicas: 2}}}
+	r.reconcileSlowConsumer(step, false)
+	assert.Empty(t, step.Status.Conditions)
+	r.reconcileSlowConsumer(step, true)
+	assert.True(t, meta.IsStatusConditionTrue(step.Status.Conditions, dfv1.ConditionSlowConsumer))
+	assert.Equal(t, "Warning SlowConsumer pending messages have grown for more than 10m0s at maxReplicas (2), the step cannot keep up with its sources", <-recorder.Events)
+	r.reconcileSlowConsumer(step, true)
+	assert.Empty(t, recorder.Events, "only one event")
+	r.reconcileSlowConsumer(step, false)
+	assert.Empty(t, step.Status.Conditions)
+}
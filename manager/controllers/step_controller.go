@@ -28,8 +28,11 @@ import (
 	"github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -57,6 +60,8 @@ type StepReconciler struct {
 type hash struct {
 	RunnerImage string        `json:"runnerImage"`
 	StepSpec    dfv1.StepSpec `json:"stepSpec"`
+	// Requests are the applied recommendations, omitted if there are none, so the hash of other steps does not change
+	Requests map[string]map[string]resource.Quantity `json:"requests,omitempty"`
 }
 
 // +kubebuilder:rbac:groups=dataflow.argoproj.io,resources=steps,verbs=get;list;watch;create;update;patch;delete
@@ -64,7 +69,14 @@ type hash struct {
 // +kubebuilder:rbac:groups=,resources=pods,verbs=get;watch;list;create
 // +kubebuilder:rbac:groups=,resources=services,verbs=get;watch;list;create
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
-func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;get;update
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;create;update
+// +kubebuilder:rbac:groups=apps,resources=controllerrevisions,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkatopics,verbs=create
+// +kubebuilder:rbac:groups=kafka.strimzi.io,resources=kafkausers,verbs=get;create;update
+// +kubebuilder:rbac:groups=metrics.k8s.io,resources=pods,verbs=list
+func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, err error) {
 	log := r.Log.WithValues("step", req.NamespacedName.String())
 	step := &dfv1.Step{}
 	if err := r.Get(ctx, req.NamespacedName, step); err != nil {
@@ -87,13 +99,22 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	pipelineName := step.GetLabels()[dfv1.KeyPipelineName]
 	stepName := step.Spec.Name
 
+	defer observeReconcile("Step", step.Namespace, pipelineName, time.Now(), &err)
+
 	log.Info("reconciling")
 
+	// we use the same config for the whole reconciliation, even if it is reloaded meanwhile
+	cfg := getConfig()
+
 	currentReplicas := int(step.Status.Replicas)
-	if step.Spec.Scale.DesiredReplicas != "" {
+	// we collect the metrics of steps that scale, or have sources, to report their status
+	collectMetrics := step.Spec.Scale.DesiredReplicas != "" || len(step.Spec.Sources) > 0
+	if collectMetrics {
 		if err := r.startMetricsCacheLoop(step); err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to start metrics cache loop: %w", err)
 		}
+	}
+	if step.Spec.Scale.DesiredReplicas != "" {
 		desiredReplicas, err := scaling.GetDesiredReplicas(*step)
 		if err != nil {
 			return ctrl.Result{}, err
@@ -123,8 +144,24 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	}
 
 	desiredReplicas := int(step.Spec.Replicas)
+	// the validating webhook only admits pipelines, so we also limit steps that are scaled directly
+	if limits, ok := getNamespaceLimits(cfg.namespaceLimits, step.Namespace); ok && limits.MaxStepReplicas > 0 {
+		var pipeline *dfv1.Pipeline
+		x := &dfv1.Pipeline{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: step.Namespace, Name: pipelineName}, x); err == nil {
+			pipeline = x
+		} else if !apierr.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("failed to get pipeline: %w", err)
+		}
+		if n, ok := admittedReplicas(*step, pipeline); ok && desiredReplicas > n {
+			log.Info("limiting replicas", "replicas", desiredReplicas, "admittedReplicas", n)
+			r.Recorder.Eventf(step, "Warning", "ReplicasLimited", "Limited to %d replicas, as the namespace limits step replicas", n)
+			desiredReplicas = n
+		}
+	}
 
 	oldStatus := step.Status.DeepCopy()
+	step.Status.ObservedGeneration = step.Generation
 	if currentReplicas != desiredReplicas || step.Status.Selector == "" {
 		log.Info("replicas changed", "currentReplicas", currentReplicas, "desiredReplicas", desiredReplicas)
 		step.Status.Replicas = uint32(desiredReplicas)
@@ -133,18 +170,97 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	}
 
 	selector, _ := labels.Parse(dfv1.KeyPipelineName + "=" + pipelineName + "," + dfv1.KeyStepName + "=" + stepName)
-	hash := util.MustHash(hash{runnerImage, step.Spec.WithOutReplicas()}) // we must remove data (e.g. replicas) which does not change the pod, otherwise it would cause the pod to be re-created all the time
+	image, _ := getRunner(cfg, step.Namespace, step.Spec)
+	// the recommendations applied when they were last observed, which the pods are re-created with
+	var applied map[string]map[string]resource.Quantity
+	if x := step.Spec.Recommendations; x != nil && x.Apply {
+		applied = step.Status.Recommendations.GetApplied()
+	}
+	hash := util.MustHash(hash{image, step.Spec.WithOutReplicas().WithOutRollout().WithOutDependsOn().WithOutLiveFields(), applied}) // we must remove data (e.g. replicas) which does not change the pod, otherwise it would cause the pod to be re-created all the time
 	step.Status.Phase, step.Status.Reason, step.Status.Message = dfv1.StepUnknown, "", ""
 	step.Status.Selector = selector.String()
+	if !collectMetrics {
+		step.Status.Sources = nil
+	} else if sources, ok := scaling.GetSourceStatuses(*step); ok {
+		step.Status.Sources = sources
+	}
+	r.reconcileSlowConsumer(step, scaling.IsSlowConsumer(*step, time.Now()))
 
 	ownerReferences := []metav1.OwnerReference{*metav1.NewControllerRef(step.GetObjectMeta(), dfv1.StepGroupVersionKind)}
 	headlessSvcName := step.GetHeadlessServiceName()
 
-	for replica := 0; replica < desiredReplicas; replica++ {
+	// if the spec is invalid, we do not create any pods until it is fixed
+	podsToCreate := desiredReplicas
+	stepUpdateInterval, err := step.Spec.GetUpdateInterval(cfg.updateInterval)
+	if err != nil {
+		step.Status.Phase, step.Status.Reason, step.Status.Message = dfv1.StepFailed, "", err.Error()
+		podsToCreate = 0
+	}
+	if cfg.restricted {
+		if err := step.ValidateRestricted(); err != nil {
+			step.Status.Phase, step.Status.Reason, step.Status.Message = dfv1.StepFailed, "", fmt.Sprintf("step violates the restricted Pod Security Standard: %v", err)
+			podsToCreate = 0
+		}
+	}
+
+	// until the dependencies are ready, we do not create any pods, but we do not delete them either
+	dependsOnRequeue := false
+	if len(step.Spec.DependsOn) > 0 {
+		if waitingFor, err := r.waitingFor(ctx, step, pipelineName); err != nil {
+			x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "", err.Error()))
+			step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+			podsToCreate = 0
+		} else if waitingFor != "" {
+			log.Info("waiting for dependencies", "message", waitingFor)
+			x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepPending, "WaitingForDependencies", waitingFor))
+			step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+			podsToCreate = 0
+			dependsOnRequeue = true
+		}
+	}
+
+	// the sidecar reads this secret from a volume, so it must exist before the pods are created
+	if len(step.GetAuthorizationKeys()) > 0 {
+		if err := r.createSecret(ctx, step, ownerReferences); err != nil {
+			x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "", fmt.Sprintf("failed to create secret %s: %v", step.Name, err)))
+			step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+		}
+	}
+
+	if err := r.applyStrimziObjs(ctx, step); err != nil {
+		x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "", fmt.Sprintf("failed to apply Strimzi resources: %v", err)))
+		step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+	}
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(ctx, pods, &client.ListOptions{Namespace: step.Namespace, LabelSelector: selector}); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to list pods: %w", err)
+	}
+	r.reconcileRecommendations(ctx, step, selector, pods.Items)
+
+	// by default, every replica runs the current spec
+	specs := allReplicas(hash, step.Spec)
+	rolloutRequeueAfter := time.Duration(0)
+	if x := step.Spec.Rollout; x != nil && x.Canary != nil {
+		if specs, rolloutRequeueAfter, err = r.reconcileRollout(ctx, step, hash, pods.Items); err != nil {
+			return ctrl.Result{}, fmt.Errorf("failed to reconcile rollout: %w", err)
+		}
+	} else {
+		step.Status.Rollout = nil
+	}
+	if specs, podsToCreate, err = r.reconcileReset(ctx, step, specs, podsToCreate, pods.Items); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to reconcile offset reset: %w", err)
+	}
+
+	for replica := 0; replica < podsToCreate; replica++ {
 		podName := fmt.Sprintf("%s-%d", step.Name, replica)
+		podHash, podSpec := specs(replica)
+		podStep := step.DeepCopy()
+		podStep.Spec = podSpec
+		podImage, podPullPolicy := getRunner(cfg, step.Namespace, podSpec)
 		_labels := map[string]string{}
 		annotations := map[string]string{}
-		if x := step.Spec.Metadata; x != nil {
+		if x := podStep.Spec.Metadata; x != nil {
 			for k, v := range x.Annotations {
 				annotations[k] = v
 			}
@@ -152,20 +268,36 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 				_labels[k] = v
 			}
 		}
+		for k, v := range podStep.Spec.WorkloadIdentity.GetPodLabels() {
+			_labels[k] = v
+		}
+		for k, v := range podStep.GetDaprAnnotations() {
+			annotations[k] = v
+		}
+		for k, v := range podStep.GetServiceMeshAnnotations(serviceMesh) {
+			annotations[k] = v
+		}
 		_labels[dfv1.KeyStepName] = stepName
 		_labels[dfv1.KeyPipelineName] = pipelineName
 		annotations[dfv1.KeyReplica] = strconv.Itoa(replica)
-		annotations[dfv1.KeyHash] = hash
-		annotations[dfv1.KeyDefaultContainer] = dfv1.CtrMain
-		annotations[dfv1.KeyKillCmd(dfv1.CtrMain)] = util.MustJSON([]string{dfv1.PathKill, "1"})
+		annotations[dfv1.KeyHash] = podHash
+		if podStep.Spec.HasMainContainer() {
+			annotations[dfv1.KeyDefaultContainer] = dfv1.CtrMain
+			annotations[dfv1.KeyKillCmd(dfv1.CtrMain)] = util.MustJSON([]string{dfv1.PathKill, "1"})
+		} else {
+			annotations[dfv1.KeyDefaultContainer] = dfv1.CtrSidecar
+		}
 		annotations[dfv1.KeyKillCmd(dfv1.CtrSidecar)] = util.MustJSON([]string{dfv1.PathKill, "1"})
+		for _, c := range podStep.Spec.Containers {
+			annotations[dfv1.KeyKillCmd(c.Name)] = util.MustJSON([]string{dfv1.PathKill, "1"})
+		}
 
 		var reqImagePullSecrets []corev1.LocalObjectReference
 
-		if len(step.Spec.ImagePullSecrets) > 0 {
-			reqImagePullSecrets = step.Spec.ImagePullSecrets
-		} else if len(imagePullSecrets) > 0 {
-			for _, element := range imagePullSecrets {
+		if len(podStep.Spec.ImagePullSecrets) > 0 {
+			reqImagePullSecrets = podStep.Spec.ImagePullSecrets
+		} else if len(cfg.imagePullSecrets) > 0 {
+			for _, element := range cfg.imagePullSecrets {
 				reqImagePullSecrets = append(reqImagePullSecrets, corev1.LocalObjectReference{Name: element})
 			}
 		}
@@ -180,22 +312,25 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 					Annotations:     annotations,
 					OwnerReferences: ownerReferences,
 				},
-				Spec: step.GetPodSpec(
+				Spec: applyRequests(podStep.GetPodSpec(
 					dfv1.GetPodSpecReq{
 						Cluster:          r.Cluster,
 						PipelineName:     pipelineName,
 						Replica:          int32(replica),
-						ImageFormat:      imageFormat,
-						RunnerImage:      runnerImage,
-						PullPolicy:       pullPolicy,
-						UpdateInterval:   updateInterval,
+						ImageFormat:      cfg.imageFormat,
+						RunnerImage:      podImage,
+						PullPolicy:       podPullPolicy,
+						UpdateInterval:   stepUpdateInterval,
 						StepStatus:       step.Status,
-						Sidecar:          step.Spec.Sidecar,
+						Sidecar:          podStep.Spec.Sidecar,
 						ImagePullSecrets: reqImagePullSecrets,
 						Hostname:         podName,
 						Subdomain:        headlessSvcName,
+						Restricted:       cfg.restricted,
+						FaultInjection:   cfg.faultInjection,
+						InitResources:    cfg.initResources,
 					},
-				),
+				), applied),
 			},
 		); apierr.IsAlreadyExists(err) {
 			// ignore
@@ -209,13 +344,19 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 
 	serviceObjMap := make(map[string]*corev1.Service)
 	serviceObjMap[headlessSvcName] = step.GetServiceObj(headlessSvcName, pipelineName, true)
+	var ingressObjs []*networkingv1.Ingress
 	for _, s := range step.Spec.Sources {
 		serviceName := pipelineName + "-" + stepName
 		if x := s.HTTP; x != nil {
 			if n := x.ServiceName; n != "" {
 				serviceName = n
 			}
-			serviceObjMap[serviceName] = step.GetServiceObj(serviceName, pipelineName, false)
+			obj := step.GetServiceObj(serviceName, pipelineName, false)
+			obj.Spec.Type = x.ServiceType
+			serviceObjMap[serviceName] = obj
+			if x.Ingress != nil {
+				ingressObjs = append(ingressObjs, step.GetIngressObj(serviceName, pipelineName, *x.Ingress))
+			}
 		} else if x := s.S3; x != nil {
 			serviceObjMap[serviceName] = step.GetServiceObj(serviceName, pipelineName, false)
 		} else if x := s.Volume; x != nil {
@@ -230,9 +371,18 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		}
 	}
 
-	pods := &corev1.PodList{}
-	if err := r.Client.List(ctx, pods, &client.ListOptions{Namespace: step.Namespace, LabelSelector: selector}); err != nil {
-		return ctrl.Result{}, fmt.Errorf("failed to list pods: %w", err)
+	for _, obj := range ingressObjs {
+		if err := r.applyIngress(ctx, obj); err != nil {
+			x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "", fmt.Sprintf("failed to apply ingress %s: %v", obj.Name, err)))
+			step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+		}
+	}
+
+	if cfg.networkPolicy {
+		if err := r.applyNetworkPolicy(ctx, step.GetNetworkPolicyObj(pipelineName, cfg.vaultAddr)); err != nil {
+			x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "", fmt.Sprintf("failed to apply network policy %s: %v", step.Name, err)))
+			step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+		}
 	}
 
 	for _, pod := range pods.Items {
@@ -240,12 +390,26 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("failed to parse replica of pod %q: %w", pod.Name, err)
 		}
-		if replica >= desiredReplicas || hash != pod.GetAnnotations()[dfv1.KeyHash] {
+		if podHash, _ := specs(replica); replica >= desiredReplicas && replica >= podsToCreate || podHash != pod.GetAnnotations()[dfv1.KeyHash] {
 			log.Info("deleting excess pod", "podName", pod.Name)
 			if err := r.Client.Delete(ctx, &pod); client.IgnoreNotFound(err) != nil {
 				x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "", fmt.Sprintf("failed to delete excess pod %s: %v", pod.Name, err)))
 				step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
 			}
+		} else if step.Spec.RestartPolicy == corev1.RestartPolicyNever && pod.Status.Phase == corev1.PodFailed {
+			// the kubelet does not restart the pod, so we re-create it, until the backoff limit is reached
+			if limit := step.Spec.GetBackoffLimit(); int32(step.Status.Failures) < limit {
+				log.Info("re-creating failed pod", "pod", pod.Name, "failures", step.Status.Failures, "backoffLimit", limit)
+				if err := r.Client.Delete(ctx, &pod); client.IgnoreNotFound(err) != nil {
+					return ctrl.Result{}, fmt.Errorf("failed to delete failed pod %s: %w", pod.Name, err)
+				}
+				step.Status.Failures++
+				x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepPending, "BackOff", fmt.Sprintf("pod %s failed, re-creating it (%d/%d)", pod.Name, step.Status.Failures, limit)))
+				step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+			} else {
+				x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(dfv1.StepFailed, "BackoffLimitExceeded", fmt.Sprintf("pod %s failed, and the step has reached its backoff limit of %d", pod.Name, limit)))
+				step.Status.Phase, step.Status.Reason, step.Status.Message = x.GetPhase(), x.GetReason(), x.GetMessage()
+			}
 		} else {
 			phase, reason, message := inferPhase(pod)
 			x := dfv1.MinStepPhaseMessage(dfv1.NewStepPhaseMessage(step.Status.Phase, step.Status.Reason, step.Status.Message), dfv1.NewStepPhaseMessage(phase, reason, message))
@@ -266,6 +430,14 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 					}
 				}
 			}
+
+			// if the sidecar of a step that runs to completion has completed, kill the main container, so the pod completes
+			if step.Spec.CanComplete() && !mainCtrTerminated && sidecarCtrTerminated(pod) {
+				log.Info("step completed, killing main container", "pod", pod.Name)
+				if err := r.ContainerKiller.KillContainer(pod, dfv1.CtrMain); err != nil {
+					log.Error(err, "failed to kill container", "pod", pod.Name, "container", dfv1.CtrMain)
+				}
+			}
 		}
 	}
 
@@ -284,12 +456,134 @@ func (r *StepReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 	if err != nil {
 		return ctrl.Result{}, err
 	}
+	if rolloutRequeueAfter > 0 && (requeueAfter == 0 || rolloutRequeueAfter < requeueAfter) {
+		requeueAfter = rolloutRequeueAfter
+	}
+	if dependsOnRequeue && (requeueAfter == 0 || dependsOnRequeueAfter < requeueAfter) {
+		requeueAfter = dependsOnRequeueAfter
+	}
+	if step.Spec.Scale.MaxReplicas > 0 && (requeueAfter == 0 || slowConsumerRequeueAfter < requeueAfter) {
+		requeueAfter = slowConsumerRequeueAfter
+	}
+	if step.Spec.Recommendations != nil && (requeueAfter == 0 || recommendationsInterval < requeueAfter) {
+		requeueAfter = recommendationsInterval
+	}
 	if requeueAfter > 0 {
 		log.Info("requeue", "requeueAfter", requeueAfter.String())
 	}
 	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
+// createSecret creates the secret containing the authorization for HTTP sources and test sinks, or adds the
+// authorization for sources and sinks added since it was created. Existing authorizations are never changed, so they do
+// not change while the step exists.
+func (r *StepReconciler) createSecret(ctx context.Context, step *dfv1.Step, ownerReferences []metav1.OwnerReference) error {
+	data := map[string][]byte{}
+	for _, key := range step.GetAuthorizationKeys() {
+		data[key] = []byte(fmt.Sprintf("Bearer %s", util.RandString()))
+	}
+	obj := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       step.Namespace,
+			Name:            step.Name,
+			Labels:          step.GetObjectLabels(step.GetLabels()[dfv1.KeyPipelineName]),
+			OwnerReferences: ownerReferences,
+		},
+		Data: data,
+	}
+	if err := r.Client.Create(ctx, obj); !apierr.IsAlreadyExists(err) {
+		return err
+	}
+	old := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), old); err != nil {
+		return err
+	}
+	if old.Data == nil {
+		old.Data = map[string][]byte{}
+	}
+	changed := false
+	for k, v := range data {
+		if _, ok := old.Data[k]; !ok {
+			old.Data[k] = v
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return r.Client.Update(ctx, old)
+}
+
+// applyStrimziObjs creates the step's KafkaTopics, unless they already exist, and creates or updates its KafkaUsers.
+func (r *StepReconciler) applyStrimziObjs(ctx context.Context, step *dfv1.Step) error {
+	topics, users := step.GetStrimziObjs(r.Cluster)
+	for _, obj := range topics {
+		if err := r.Client.Create(ctx, obj); util.IgnoreAlreadyExists(err) != nil {
+			return fmt.Errorf("failed to create KafkaTopic %s: %w", obj.GetName(), err)
+		}
+	}
+	for _, obj := range users {
+		err := r.Client.Create(ctx, obj)
+		if err == nil {
+			continue
+		} else if !apierr.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create KafkaUser %s: %w", obj.GetName(), err)
+		}
+		old := &unstructured.Unstructured{}
+		old.SetGroupVersionKind(dfv1.KafkaUserGroupVersionKind)
+		if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), old); err != nil {
+			return err
+		}
+		if notEqual, _ := util.NotEqual(old.Object["spec"], obj.Object["spec"]); !notEqual {
+			continue
+		}
+		old.Object["spec"] = obj.Object["spec"]
+		if err := r.Client.Update(ctx, old); err != nil {
+			return fmt.Errorf("failed to update KafkaUser %s: %w", obj.GetName(), err)
+		}
+	}
+	return nil
+}
+
+// applyNetworkPolicy creates the network policy, or updates it if the step's sources or sinks have changed.
+func (r *StepReconciler) applyNetworkPolicy(ctx context.Context, obj *networkingv1.NetworkPolicy) error {
+	err := r.Client.Create(ctx, obj)
+	if !apierr.IsAlreadyExists(err) {
+		return err
+	}
+	old := &networkingv1.NetworkPolicy{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), old); err != nil {
+		return err
+	}
+	if notEqual, _ := util.NotEqual(old.Spec, obj.Spec); !notEqual {
+		return nil
+	}
+	old.Spec = obj.Spec
+	return r.Client.Update(ctx, old)
+}
+
+// applyIngress creates the ingress, or updates it if the HTTP source's ingress has changed.
+func (r *StepReconciler) applyIngress(ctx context.Context, obj *networkingv1.Ingress) error {
+	err := r.Client.Create(ctx, obj)
+	if !apierr.IsAlreadyExists(err) {
+		return err
+	}
+	old := &networkingv1.Ingress{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(obj), old); err != nil {
+		return err
+	}
+	if notEqual, _ := util.NotEqual(ingress{old.Annotations, old.Spec}, ingress{obj.Annotations, obj.Spec}); !notEqual {
+		return nil
+	}
+	old.Annotations, old.Spec = obj.Annotations, obj.Spec
+	return r.Client.Update(ctx, old)
+}
+
+type ingress struct {
+	Annotations map[string]string        `json:"annotations"`
+	Spec        networkingv1.IngressSpec `json:"spec"`
+}
+
 func (r *StepReconciler) startMetricsCacheLoop(step *dfv1.Step) error {
 	key := fmt.Sprintf("%s/%s/%s", step.Namespace, step.Name, step.GetHeadlessServiceName())
 	if r.MetricsCacheHandler.Contains(key) {
@@ -77,6 +77,7 @@ var _ = BeforeSuite(func(done Done) {
 		Scheme:          k8sManager.GetScheme(),
 		Log:             ctrl.Log.WithName("controllers").WithName("Pipeline"),
 		ContainerKiller: ck,
+		Recorder:        record.NewFakeRecorder(1),
 	}).SetupWithManager(k8sManager)
 	Expect(err).ToNot(HaveOccurred())
 
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/manager/controllers/scaling"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var getSinkTotal = scaling.GetSinkTotal
+
+// getUpgradeStatus compares each of the pipeline's steps to the replaced pipeline's step of the same name.
+func (r *PipelineReconciler) getUpgradeStatus(ctx context.Context, pipeline *dfv1.Pipeline, steps []dfv1.Step) (*dfv1.UpgradeStatus, error) {
+	x := pipeline.Spec.Upgrade
+	maxDeviation, err := x.GetMaxDeviation()
+	if err != nil {
+		return &dfv1.UpgradeStatus{Message: err.Error()}, nil
+	}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: pipeline.Namespace, Name: x.Replaces}, &dfv1.Pipeline{}); apierr.IsNotFound(err) {
+		return &dfv1.UpgradeStatus{Message: fmt.Sprintf("replaced pipeline %q not found", x.Replaces)}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	s := &dfv1.UpgradeStatus{Parity: true}
+	n := 0
+	for _, step := range steps {
+		p := dfv1.StepParity{Name: step.Spec.Name}
+		replaced := &dfv1.Step{}
+		if err := r.Get(ctx, client.ObjectKey{Namespace: pipeline.Namespace, Name: x.Replaces + "-" + step.Spec.Name}, replaced); apierr.IsNotFound(err) {
+			p.Parity, p.Message = true, "step not in replaced pipeline"
+		} else if err != nil {
+			return nil, err
+		} else if ratio, err := getRatio(step); err != nil {
+			p.Message = err.Error()
+		} else if replacedRatio, err := getRatio(*replaced); err != nil {
+			p.Ratio, p.Message = fmt.Sprintf("%.4f", ratio), "replaced step: "+err.Error()
+		} else {
+			p.Ratio, p.ReplacedRatio = fmt.Sprintf("%.4f", ratio), fmt.Sprintf("%.4f", replacedRatio)
+			p.Parity = math.Abs(ratio-replacedRatio) <= maxDeviation
+		}
+		if p.Parity {
+			n++
+		}
+		s.Parity = s.Parity && p.Parity
+		s.Steps = append(s.Steps, p)
+	}
+	s.Message = fmt.Sprintf("%d/%d steps at parity with %q", n, len(steps), x.Replaces)
+	return s, nil
+}
+
+// getRatio returns the ratio of messages written to the step's sinks to messages read from its sources.
+func getRatio(step dfv1.Step) (float64, error) {
+	sources, sinks := float64(0), float64(0)
+	for replica := 0; replica < step.Status.GetReplicas(); replica++ {
+		total, _, err := getSourceTotals(step, replica)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get metrics: %w", err)
+		}
+		sink, err := getSinkTotal(step, replica)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get metrics: %w", err)
+		}
+		sources, sinks = sources+total, sinks+sink
+	}
+	if sources == 0 {
+		return 0, fmt.Errorf("no messages yet")
+	}
+	return sinks / sources, nil
+}
+
+// promoting returns true if the pipeline is an upgrade that is annotated to be promoted, and either every step is at
+// parity with the replaced pipeline, or the promotion is forced.
+func promoting(pipeline *dfv1.Pipeline) bool {
+	if pipeline.Spec.Upgrade == nil {
+		return false
+	}
+	switch pipeline.GetAnnotations()[dfv1.KeyPromote] {
+	case "true":
+		return pipeline.Status.Upgrade != nil && pipeline.Status.Upgrade.Parity
+	case "force":
+		return true
+	default:
+		return false
+	}
+}
+
+// promote cuts over from the replaced pipeline to this pipeline. Each Kafka source, that reads the same topic as the
+// replaced pipeline's source of the same name, takes over that source's consumer group, so it continues from the
+// replaced pipeline's committed offsets. Then the replaced pipeline is deleted.
+func (r *PipelineReconciler) promote(ctx context.Context, pipeline *dfv1.Pipeline) error {
+	log := r.Log.WithValues("pipeline", pipeline.Namespace+"/"+pipeline.Name)
+	replacedName := pipeline.Spec.Upgrade.Replaces
+	replaced := &dfv1.Pipeline{}
+	if err := r.Get(ctx, client.ObjectKey{Namespace: pipeline.Namespace, Name: replacedName}, replaced); err != nil {
+		return fmt.Errorf("failed to get replaced pipeline %q: %w", replacedName, err)
+	}
+	for _, step := range pipeline.Spec.Steps {
+		for _, source := range step.Sources {
+			x := source.Kafka
+			if x == nil || x.GroupID != "" {
+				continue
+			}
+			for _, replacedStep := range replaced.Spec.Steps {
+				for _, replacedSource := range replacedStep.Sources {
+					if y := replacedSource.Kafka; y != nil && replacedStep.Name == step.Name && replacedSource.Name == source.Name && y.Topic == x.Topic {
+						x.GroupID = y.GetGroupID(sharedutil.GetSourceUID(r.Cluster, pipeline.Namespace, replacedName, step.Name, source.Name))
+						log.Info("taking over consumer group", "step", step.Name, "source", source.Name, "groupId", x.GroupID)
+					}
+				}
+			}
+		}
+	}
+	pipeline.Spec.Upgrade = nil
+	delete(pipeline.Annotations, dfv1.KeyPromote)
+	if err := r.Update(ctx, pipeline); err != nil {
+		return fmt.Errorf("failed to promote pipeline: %w", err)
+	}
+	log.Info("promoted pipeline, deleting replaced pipeline", "replaced", replacedName)
+	if err := r.Delete(ctx, replaced); client.IgnoreNotFound(err) != nil {
+		return fmt.Errorf("failed to delete replaced pipeline %q: %w", replacedName, err)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newUpgradeStep(pipelineName string) *dfv1.Step {
+	return &dfv1.Step{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: pipelineName + "-main"},
+		Spec:       dfv1.StepSpec{Name: "main"},
+		Status:     dfv1.StepStatus{Replicas: 1},
+	}
+}
+
+func Test_getUpgradeStatus(t *testing.T) {
+	defer func(f func(dfv1.Step, int) (float64, float64, error)) { getSourceTotals = f }(getSourceTotals)
+	defer func(f func(dfv1.Step, int) (float64, error)) { getSinkTotal = f }(getSinkTotal)
+	ctx := context.Background()
+	pl := &dfv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-v2"},
+		Spec:       dfv1.PipelineSpec{Upgrade: &dfv1.Upgrade{Replaces: "my-pl"}},
+	}
+	steps := []dfv1.Step{*newUpgradeStep("my-pl-v2")}
+	t.Run("NotFound", func(t *testing.T) {
+		r := &PipelineReconciler{Client: newFakeClient(t)}
+		s, err := r.getUpgradeStatus(ctx, pl, steps)
+		assert.NoError(t, err)
+		assert.False(t, s.Parity)
+		assert.Equal(t, `replaced pipeline "my-pl" not found`, s.Message)
+	})
+	r := &PipelineReconciler{Client: newFakeClient(t,
+		&dfv1.Pipeline{ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl"}},
+		newUpgradeStep("my-pl"),
+	)}
+	getSourceTotals = func(dfv1.Step, int) (float64, float64, error) { return 100, 0, nil }
+	t.Run("Parity", func(t *testing.T) {
+		getSinkTotal = func(step dfv1.Step, _ int) (float64, error) {
+			return map[string]float64{"my-pl-main": 50, "my-pl-v2-main": 50.5}[step.Name], nil
+		}
+		s, err := r.getUpgradeStatus(ctx, pl, steps)
+		assert.NoError(t, err)
+		assert.True(t, s.Parity)
+		assert.Equal(t, `1/1 steps at parity with "my-pl"`, s.Message)
+		assert.Equal(t, []dfv1.StepParity{{Name: "main", Ratio: "0.5050", ReplacedRatio: "0.5000", Parity: true}}, s.Steps)
+	})
+	t.Run("NoParity", func(t *testing.T) {
+		getSinkTotal = func(step dfv1.Step, _ int) (float64, error) {
+			return map[string]float64{"my-pl-main": 50, "my-pl-v2-main": 60}[step.Name], nil
+		}
+		s, err := r.getUpgradeStatus(ctx, pl, steps)
+		assert.NoError(t, err)
+		assert.False(t, s.Parity)
+		assert.Equal(t, `0/1 steps at parity with "my-pl"`, s.Message)
+	})
+	t.Run("MetricsError", func(t *testing.T) {
+		getSinkTotal = func(dfv1.Step, int) (float64, error) { return 0, fmt.Errorf("no metrics") }
+		s, err := r.getUpgradeStatus(ctx, pl, steps)
+		assert.NoError(t, err)
+		assert.False(t, s.Parity)
+		assert.Equal(t, "failed to get metrics: no metrics", s.Steps[0].Message)
+	})
+}
+
+func Test_promoting(t *testing.T) {
+	newPipeline := func(promote string, status *dfv1.UpgradeStatus) *dfv1.Pipeline {
+		return &dfv1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{dfv1.KeyPromote: promote}},
+			Spec:       dfv1.PipelineSpec{Upgrade: &dfv1.Upgrade{Replaces: "my-pl"}},
+			Status:     dfv1.PipelineStatus{Upgrade: status},
+		}
+	}
+	assert.False(t, promoting(&dfv1.Pipeline{}), "not an upgrade")
+	assert.False(t, promoting(newPipeline("", &dfv1.UpgradeStatus{Parity: true})), "not annotated")
+	assert.False(t, promoting(newPipeline("true", nil)), "not compared yet")
+	assert.False(t, promoting(newPipeline("true", &dfv1.UpgradeStatus{})), "not at parity")
+	assert.True(t, promoting(newPipeline("true", &dfv1.UpgradeStatus{Parity: true})))
+	assert.True(t, promoting(newPipeline("force", &dfv1.UpgradeStatus{})))
+}
+
+func Test_promote(t *testing.T) {
+	ctx := context.Background()
+	kafkaSource := func(topic string) dfv1.Source {
+		return dfv1.Source{Name: "kafka", Kafka: &dfv1.KafkaSource{Kafka: dfv1.Kafka{Topic: topic}}}
+	}
+	replaced := &dfv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl"},
+		Spec:       dfv1.PipelineSpec{Steps: []dfv1.StepSpec{{Name: "main", Sources: []dfv1.Source{kafkaSource("my-topic")}}, {Name: "other", Sources: []dfv1.Source{kafkaSource("my-topic")}}}},
+	}
+	pl := &dfv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-v2", Annotations: map[string]string{dfv1.KeyPromote: "true"}},
+		Spec: dfv1.PipelineSpec{
+			Upgrade: &dfv1.Upgrade{Replaces: "my-pl"},
+			Steps:   []dfv1.StepSpec{{Name: "main", Sources: []dfv1.Source{kafkaSource("my-topic")}}, {Name: "other", Sources: []dfv1.Source{kafkaSource("other-topic")}}},
+		},
+	}
+	r := &PipelineReconciler{Client: newFakeClient(t, replaced, pl), Log: ctrl.Log.WithName("test"), Cluster: "my-cluster"}
+	assert.NoError(t, r.promote(ctx, pl.DeepCopy()))
+	promoted := &dfv1.Pipeline{}
+	assert.NoError(t, r.Get(ctx, client.ObjectKeyFromObject(pl), promoted))
+	assert.Nil(t, promoted.Spec.Upgrade)
+	assert.NotContains(t, promoted.Annotations, dfv1.KeyPromote)
+	assert.Equal(t, util.GetSourceUID("my-cluster", "my-ns", "my-pl", "main", "kafka"), promoted.Spec.Steps[0].Sources[0].Kafka.GroupID)
+	assert.Empty(t, promoted.Spec.Steps[1].Sources[0].Kafka.GroupID, "different topic")
+	assert.Error(t, r.Get(ctx, client.ObjectKeyFromObject(replaced), &dfv1.Pipeline{}))
+}
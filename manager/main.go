@@ -26,12 +26,15 @@ import (
 	"github.com/argoproj-labs/argo-dataflow/manager/controllers/scaling"
 	"github.com/argoproj-labs/argo-dataflow/shared/containerkiller"
 	"github.com/argoproj-labs/argo-dataflow/shared/util"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var (
@@ -65,6 +68,8 @@ func main() {
 		LeaderElection:     enableLeaderElection,
 		LeaderElectionID:   "1c03be80.my.domain",
 		Namespace:          os.Getenv(dfv1.EnvNamespace),
+		// the step controller only reads its own secrets, so it does not need to cache (and list) every secret
+		ClientDisableCacheFor: []client.Object{&corev1.Secret{}},
 	})
 	if err != nil {
 		panic(fmt.Errorf("unable to start manager: %w", err))
@@ -77,7 +82,9 @@ func main() {
 		Client:          mgr.GetClient(),
 		Log:             ctrl.Log.WithName("controllers").WithName("Pipeline"),
 		Scheme:          mgr.GetScheme(),
+		Recorder:        mgr.GetEventRecorderFor("pipeline-reconciler"),
 		ContainerKiller: containerKiller,
+		Cluster:         os.Getenv(dfv1.EnvCluster),
 	}).SetupWithManager(mgr); err != nil {
 		panic(fmt.Errorf("unable to create controller manager: %w", err))
 	}
@@ -96,6 +103,29 @@ func main() {
 	}).SetupWithManager(mgr); err != nil {
 		panic(fmt.Errorf("unable to create controller manager: %w", err))
 	}
+
+	if err = mgr.Add(&controllers.ConfigWatcher{
+		Clientset: clientset,
+		Namespace: os.Getenv(dfv1.EnvNamespace),
+		Log:       ctrl.Log.WithName("controllers").WithName("ConfigWatcher"),
+	}); err != nil {
+		panic(fmt.Errorf("unable to create config watcher: %w", err))
+	}
+
+	if err = mgr.Add(&controllers.PodGarbageCollector{
+		Client: mgr.GetClient(),
+		Log:    ctrl.Log.WithName("controllers").WithName("PodGarbageCollector"),
+	}); err != nil {
+		panic(fmt.Errorf("unable to create pod garbage collector: %w", err))
+	}
+
+	// the webhook server needs a certificate, e.g. from cert-manager, so it is only started if enabled
+	if os.Getenv(dfv1.EnvWebhook) == "true" {
+		setupLog.Info("serving validating webhook")
+		mgr.GetWebhookServer().Register("/validate-dataflow-argoproj-io-v1alpha1-pipeline", &webhook.Admission{
+			Handler: &controllers.PipelineValidator{Client: mgr.GetClient()},
+		})
+	}
 	// +kubebuilder:scaffold:builder
 
 	ctx := ctrl.SetupSignalHandler()
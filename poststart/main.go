@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+)
+
+// the main container's post-start hook: it runs the step's lifecycle.postStart command, and, if it succeeds, creates a
+// file so the sidecar knows it can start sending messages
+func main() {
+	cmd := exec.Command(os.Args[1], os.Args[2:]...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		panic(err)
+	}
+	if err := os.WriteFile("/var/run/argo-dataflow/post-started", nil, 0o600); err != nil {
+		panic(err)
+	}
+}
@@ -1,9 +1,29 @@
 package main
 
-import "net/http"
+import (
+	"net/http"
+	"os"
+	"os/exec"
+)
 
+// the main container's pre-stop hook: it asks the sidecar to close its sources, then runs the step's lifecycle.preStop
+// command, if any, and tells the sidecar it is done, so the sidecar does not stop while the command runs
 func main() {
-	resp, err := http.Get("http://localhost:3569/pre-stop?source=main")
+	get("http://localhost:3569/pre-stop?source=main")
+	if len(os.Args) < 2 {
+		return
+	}
+	cmd := exec.Command(os.Args[1], os.Args[2:]...)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	err := cmd.Run()
+	get("http://localhost:3569/pre-stop-done")
+	if err != nil {
+		panic(err)
+	}
+}
+
+func get(url string) {
+	resp, err := http.Get(url)
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,156 @@
+package init
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"strings"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// waitForBrokers waits, with backoff, until it can connect to each of the step's brokers, or the timeout elapses.
+// While it waits, it annotates its pod with the brokers it cannot connect to, so the controller can report them in the
+// step's status.
+func waitForBrokers(ctx context.Context, secrets corev1.SecretInterface, pods corev1.PodInterface, podName string, spec dfv1.StepSpec) error {
+	spec, err := brokersFromSecrets(ctx, secrets, spec)
+	if err != nil {
+		return err
+	}
+	addresses := spec.GetBrokerAddresses()
+	logger.Info("waiting for brokers", "addresses", addresses, "timeout", spec.WaitForBrokers.GetTimeout())
+	ctx, cancel := context.WithTimeout(ctx, spec.WaitForBrokers.GetTimeout())
+	defer cancel()
+	backoff := wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: math.MaxInt32, Cap: 30 * time.Second}
+	annotation := ""
+	for {
+		var unreachable []string
+		for _, address := range addresses {
+			if err := dial(ctx, address); err != nil {
+				logger.Info("broker unreachable", "address", address, "err", err.Error())
+				unreachable = append(unreachable, address)
+			}
+		}
+		// the annotation is only informative, so we do not fail if we cannot update it
+		if x := strings.Join(unreachable, ","); x != annotation {
+			if err := annotateWaitingFor(ctx, pods, podName, x); err != nil {
+				logger.Error(err, "failed to annotate pod with the brokers it is waiting for")
+			} else {
+				annotation = x
+			}
+		}
+		if len(unreachable) == 0 {
+			logger.Info("brokers reachable")
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for brokers to be reachable: %s", strings.Join(unreachable, ", "))
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+func dial(ctx context.Context, address string) error {
+	conn, err := (&net.Dialer{Timeout: 5 * time.Second}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+func annotateWaitingFor(ctx context.Context, pods corev1.PodInterface, podName, addresses string) error {
+	var value interface{} = addresses
+	if addresses == "" {
+		value = nil // removes the annotation
+	}
+	data := sharedutil.MustJSON(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{dfv1.KeyWaitingForBrokers: value}},
+	})
+	_, err := pods.Patch(ctx, podName, types.MergePatchType, []byte(data), metav1.PatchOptions{})
+	return err
+}
+
+// brokersFromSecrets returns the spec with the addresses of named brokers that are not in the spec read from their
+// secrets, like the sidecar does.
+func brokersFromSecrets(ctx context.Context, secrets corev1.SecretInterface, spec dfv1.StepSpec) (dfv1.StepSpec, error) {
+	spec = *spec.DeepCopy()
+	fromSecret := func(name, key string) (string, error) {
+		secret, err := secrets.Get(ctx, name, metav1.GetOptions{})
+		if apierr.IsNotFound(err) {
+			return "", nil
+		} else if err != nil {
+			return "", fmt.Errorf("failed to get secret %q: %w", name, err)
+		}
+		return string(secret.Data[key]), nil
+	}
+	kafka := func(x *dfv1.Kafka) error {
+		if len(x.Brokers) > 0 || x.Strimzi != nil || x.EventHubs != nil {
+			return nil
+		}
+		v, err := fromSecret("dataflow-kafka-"+x.Name, "brokers")
+		if v != "" {
+			x.Brokers = strings.Split(v, ",")
+		}
+		return err
+	}
+	address := func(secretName, key string, x *string) error {
+		if *x != "" {
+			return nil
+		}
+		v, err := fromSecret(secretName, key)
+		*x = v
+		return err
+	}
+	for _, s := range spec.Sources {
+		var err error
+		if x := s.Kafka; x != nil {
+			err = kafka(&x.Kafka)
+		} else if x := s.STAN; x != nil {
+			err = address("dataflow-stan-"+x.Name, "natsUrl", &x.NATSURL)
+		} else if x := s.JetStream; x != nil {
+			err = address("dataflow-jetstream-"+x.Name, "natsUrl", &x.NATSURL)
+		} else if x := s.Redis; x != nil {
+			err = address("dataflow-redis-"+x.Name, "url", &x.URL)
+		} else if x := s.RedisStream; x != nil {
+			err = address("dataflow-redis-"+x.Name, "url", &x.URL)
+		} else if x := s.Elasticsearch; x != nil {
+			err = address("dataflow-elasticsearch-"+x.Name, "url", &x.URL)
+		} else if x := s.AMQP; x != nil {
+			err = address("dataflow-amqp-"+x.Name, "url", &x.URL)
+		} else if x := s.MQTT; x != nil {
+			err = address("dataflow-mqtt-"+x.Name, "url", &x.URL)
+		}
+		if err != nil {
+			return spec, err
+		}
+	}
+	for _, s := range spec.Sinks {
+		var err error
+		if x := s.Kafka; x != nil {
+			err = kafka(&x.Kafka)
+		} else if x := s.STAN; x != nil {
+			err = address("dataflow-stan-"+x.Name, "natsUrl", &x.NATSURL)
+		} else if x := s.JetStream; x != nil {
+			err = address("dataflow-jetstream-"+x.Name, "natsUrl", &x.NATSURL)
+		} else if x := s.Redis; x != nil {
+			err = address("dataflow-redis-"+x.Name, "url", &x.URL)
+		} else if x := s.RedisStream; x != nil {
+			err = address("dataflow-redis-"+x.Name, "url", &x.URL)
+		} else if x := s.AMQP; x != nil {
+			err = address("dataflow-amqp-"+x.Name, "url", &x.URL)
+		}
+		if err != nil {
+			return spec, err
+		}
+	}
+	return spec, nil
+}
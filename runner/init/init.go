@@ -10,6 +10,7 @@ import (
 	"syscall"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -28,7 +29,7 @@ var logger = sharedutil.NewLogger()
 // due to main container crashing, the init container may be started many times, so each operation we perform should be
 // idempontent, i.e. if we copy a file to shared volume, and it already exists, we should ignore that error.
 func Exec(ctx context.Context) error {
-	for _, name := range []string{dfv1.PathKill, dfv1.PathPreStop} {
+	for _, name := range []string{dfv1.PathKill, dfv1.PathPostStart, dfv1.PathPreStop, dfv1.PathStdio} {
 		logger.Info("copying binary", "name", name)
 		a := filepath.Join("/bin", filepath.Base(name))
 		src, err := os.Open(a)
@@ -46,20 +47,17 @@ func Exec(ctx context.Context) error {
 	}
 	step := dfv1.Step{}
 	sharedutil.MustUnJSON(os.Getenv(dfv1.EnvStep), &step)
-	logger.Info("creating authorization file")
-	if err := os.WriteFile(dfv1.PathAuthorization, []byte(sharedutil.RandString()), 0o600); sharedutil.IgnoreExist(err) != nil {
-		return fmt.Errorf("failed to create authorization file: %w", err)
+	if err := CreateFiles(step.Spec); err != nil {
+		return err
 	}
-	if step.Spec.GetIn().FIFO {
-		logger.Info("creating in fifo")
-		if err := syscall.Mkfifo(dfv1.PathFIFOIn, 0o600); sharedutil.IgnoreExist(err) != nil {
-			return fmt.Errorf("failed to create input FIFO: %w", err)
+	if step.Spec.WaitForBrokers != nil {
+		kubernetesInterface := kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie())
+		namespace := os.Getenv(dfv1.EnvNamespace)
+		secretInterface := util.NewSecretInterface(kubernetesInterface.CoreV1().Secrets(namespace))
+		if err := waitForBrokers(ctx, secretInterface, kubernetesInterface.CoreV1().Pods(namespace), os.Getenv(dfv1.EnvPod), step.Spec); err != nil {
+			return err
 		}
 	}
-	logger.Info("creating out fifo")
-	if err := syscall.Mkfifo(dfv1.PathFIFOOut, 0o600); sharedutil.IgnoreExist(err) != nil {
-		return fmt.Errorf("failed to create output FIFO: %w", err)
-	}
 	if g := step.Spec.Git; g != nil {
 		logger.Info("cloning", "url", g.URL, "checkout", dfv1.PathCheckout)
 		var auth transport.AuthMethod
@@ -67,7 +65,7 @@ func Exec(ctx context.Context) error {
 		if k := g.UsernameSecret; k != nil {
 			if v := g.PasswordSecret; v != nil {
 				logger.Info("getting secret for auth", "UsernameSecret", k, "PasswordSecret", v)
-				secretInterface := kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie()).CoreV1().Secrets(os.Getenv(dfv1.EnvNamespace))
+				secretInterface := util.NewSecretInterface(kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie()).CoreV1().Secrets(os.Getenv(dfv1.EnvNamespace)))
 
 				if usernameSecret, err := secretInterface.Get(ctx, k.Name, metav1.GetOptions{}); err != nil {
 					return fmt.Errorf("failed to get secret %q: %w", k.Name, err)
@@ -86,7 +84,7 @@ func Exec(ctx context.Context) error {
 
 		if k := g.SSHPrivateKeySecret; k != nil {
 			logger.Info("getting secret for auth", "SSHPrivateKeySecret", k)
-			secretInterface := kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie()).CoreV1().Secrets(os.Getenv(dfv1.EnvNamespace))
+			secretInterface := util.NewSecretInterface(kubernetes.NewForConfigOrDie(ctrl.GetConfigOrDie()).CoreV1().Secrets(os.Getenv(dfv1.EnvNamespace)))
 
 			if sshPrivateKey, err := secretInterface.Get(ctx, k.Name, metav1.GetOptions{}); err != nil {
 				return fmt.Errorf("failed to get secret %q: %w", k.Name, err)
@@ -128,3 +126,22 @@ func Exec(ctx context.Context) error {
 	}
 	return nil
 }
+
+// CreateFiles creates the authorization file and FIFOs that the sidecar and main container use to communicate.
+func CreateFiles(spec dfv1.StepSpec) error {
+	logger.Info("creating authorization file")
+	if err := os.WriteFile(dfv1.PathAuthorization, []byte(sharedutil.RandString()), 0o600); sharedutil.IgnoreExist(err) != nil {
+		return fmt.Errorf("failed to create authorization file: %w", err)
+	}
+	if spec.GetIn().FIFO {
+		logger.Info("creating in fifo")
+		if err := syscall.Mkfifo(dfv1.PathFIFOIn, 0o600); sharedutil.IgnoreExist(err) != nil {
+			return fmt.Errorf("failed to create input FIFO: %w", err)
+		}
+	}
+	logger.Info("creating out fifo")
+	if err := syscall.Mkfifo(dfv1.PathFIFOOut, 0o600); sharedutil.IgnoreExist(err) != nil {
+		return fmt.Errorf("failed to create output FIFO: %w", err)
+	}
+	return nil
+}
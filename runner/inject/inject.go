@@ -0,0 +1,63 @@
+package inject
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// the sidecar's HTTPS server, which uses a self-signed certificate
+const defaultURL = "https://localhost:3570/inject"
+
+// Exec sends a message to the sidecar's /inject endpoint, as if it came from the named source, and writes the
+// messages written to the sinks to out. It is run in the sidecar container, e.g. using `kubectl exec`.
+func Exec(ctx context.Context, sourceName string, msg []byte, out io.Writer) error {
+	step := dfv1.Step{}
+	sharedutil.MustUnJSON(os.Getenv(dfv1.EnvStep), &step)
+	secretName := os.Getenv(dfv1.EnvPipelineName) + "-" + step.Spec.Name
+	// the step's secret is mounted in the sidecar, so we do not need the Kubernetes API
+	secret, err := util.NewSecretInterface(nil).Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get secret %q: %w", secretName, err)
+	}
+	authorization := string(secret.Data[fmt.Sprintf("sources.%s.http.authorization", sourceName)])
+	client := &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+	}
+	return post(ctx, client, defaultURL, sourceName, authorization, msg, out)
+}
+
+func post(ctx context.Context, client *http.Client, u, sourceName, authorization string, msg []byte, out io.Writer) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", u+"?source="+url.QueryEscape(sourceName), bytes.NewReader(msg))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to inject message: %q %q", resp.Status, body)
+	}
+	_, err = out.Write(body)
+	return err
+}
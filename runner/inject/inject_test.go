@@ -0,0 +1,35 @@
+package inject
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_post(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(403)
+			return
+		}
+		assert.Equal(t, "my-source", r.URL.Query().Get("source"))
+		msg, _ := ioutil.ReadAll(r.Body)
+		w.WriteHeader(200)
+		_, _ = w.Write(append(msg, '\n'))
+	}))
+	defer ts.Close()
+	t.Run("OK", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		assert.NoError(t, post(context.Background(), ts.Client(), ts.URL, "my-source", "Bearer my-token", []byte("my-msg"), out))
+		assert.Equal(t, "my-msg\n", out.String())
+	})
+	t.Run("Forbidden", func(t *testing.T) {
+		err := post(context.Background(), ts.Client(), ts.URL, "my-source", "", []byte("my-msg"), &bytes.Buffer{})
+		assert.EqualError(t, err, `failed to inject message: "403 Forbidden" ""`)
+	})
+}
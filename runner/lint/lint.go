@@ -0,0 +1,1003 @@
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/antonmedv/expr"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/codec"
+	amqpsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/amqp"
+	crons "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/cron"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/generator"
+	"github.com/robfig/cron/v3"
+	"github.com/xitongsys/parquet-go/schema"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	k8syaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// the same parser as the cron source
+var cronParser = cron.NewParser(cron.SecondOptional | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+var secretKeySelectorType = reflect.TypeOf(corev1.SecretKeySelector{})
+
+// Exec lints the pipeline manifests in each of the files, printing the problems found, and returns an error if there
+// are any, so that it can be used in CI.
+func Exec(paths []string) error {
+	if len(paths) == 0 {
+		return fmt.Errorf("usage: lint FILE...")
+	}
+	n := 0
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, problem := range Lint(data) {
+			fmt.Printf("%s: %s\n", path, problem)
+			n++
+		}
+	}
+	if n > 0 {
+		return fmt.Errorf("%d problem(s) found", n)
+	}
+	return nil
+}
+
+// Lint returns the problems found in the manifest, which may contain several YAML documents. Documents that are not
+// pipelines are ignored.
+func Lint(data []byte) []string {
+	var problems []string
+	r := k8syaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	for i := 0; ; i++ {
+		doc, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return append(problems, fmt.Sprintf("document %d: %v", i, err))
+		}
+		if len(bytes.TrimSpace(doc)) == 0 {
+			continue
+		}
+		typeMeta := struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}{}
+		if err := yaml.Unmarshal(doc, &typeMeta); err != nil {
+			problems = append(problems, fmt.Sprintf("document %d: %v", i, err))
+			continue
+		}
+		if typeMeta.APIVersion != dfv1.GroupVersion.String() || typeMeta.Kind != "Pipeline" {
+			continue
+		}
+		pl := dfv1.Pipeline{}
+		if err := yaml.UnmarshalStrict(doc, &pl); err != nil {
+			problems = append(problems, fmt.Sprintf("document %d: %v", i, err))
+			continue
+		}
+		for _, problem := range lintPipeline(pl) {
+			problems = append(problems, fmt.Sprintf("pipeline %q: %s", pl.Name, problem))
+		}
+	}
+	return problems
+}
+
+func lintPipeline(pl dfv1.Pipeline) []string {
+	var problems []string
+	if pl.Name == "" {
+		problems = append(problems, "metadata.name is required")
+	} else {
+		for _, msg := range validation.IsDNS1123Subdomain(pl.Name) {
+			problems = append(problems, "metadata.name: "+msg)
+		}
+	}
+	if len(pl.Spec.Steps) == 0 {
+		problems = append(problems, "at least one step is required")
+	}
+	if x := pl.Spec.Upgrade; x != nil {
+		if x.Replaces == "" || x.Replaces == pl.Name {
+			problems = append(problems, "upgrade.replaces must be the name of another pipeline")
+		}
+		if _, err := x.GetMaxDeviation(); err != nil {
+			problems = append(problems, "upgrade."+err.Error())
+		}
+	}
+	if x := pl.Spec.Job; x != nil {
+		if x.BackoffLimit != nil && *x.BackoffLimit < 0 {
+			problems = append(problems, "job.backoffLimit must not be negative")
+		}
+		if x.ActiveDeadlineSeconds != nil && *x.ActiveDeadlineSeconds <= 0 {
+			problems = append(problems, "job.activeDeadlineSeconds must be greater than zero")
+		}
+	}
+	if x := pl.Spec.Schedule; x != nil {
+		if _, err := x.GetSchedule(); err != nil {
+			problems = append(problems, "schedule."+err.Error())
+		}
+		switch x.GetConcurrencyPolicy() {
+		case dfv1.ConcurrencyPolicyAllow, dfv1.ConcurrencyPolicyForbid, dfv1.ConcurrencyPolicyReplace:
+		default:
+			problems = append(problems, fmt.Sprintf("schedule.concurrencyPolicy %q must be Allow, Forbid or Replace", x.ConcurrencyPolicy))
+		}
+		if x.GetSuccessfulRunsHistoryLimit() < 0 || x.GetFailedRunsHistoryLimit() < 0 {
+			problems = append(problems, "schedule: history limits must not be negative")
+		}
+		if pl.Spec.Job == nil {
+			problems = append(problems, "schedule can only be used with job, as runs must complete")
+		}
+	}
+	if x := pl.Spec.RevisionHistoryLimit; x != nil && *x < 0 {
+		problems = append(problems, "revisionHistoryLimit must not be negative")
+	}
+	if x := pl.Spec.Quota; x != nil {
+		if x.GetBytes() < 0 {
+			problems = append(problems, "quota.bytes must not be negative")
+		} else if x.Messages == 0 && x.GetBytes() == 0 {
+			problems = append(problems, "quota must limit messages or bytes")
+		}
+		if x.Coordinator != "" && !pl.Spec.HasStep(x.Coordinator) {
+			problems = append(problems, fmt.Sprintf("quota.coordinator %q must be the name of a step", x.Coordinator))
+		}
+	}
+	if x := pl.Spec.SLO; x != nil {
+		if x.MaxPending == 0 && x.MaxLatency == nil && x.MaxErrorRate == "" {
+			problems = append(problems, "slo must have at least one of maxPending, maxLatency or maxErrorRate")
+		}
+		if x.MaxLatency != nil && x.GetMaxLatency() <= 0 {
+			problems = append(problems, "slo.maxLatency must be greater than zero")
+		}
+		if _, err := x.GetMaxErrorRate(); err != nil {
+			problems = append(problems, "slo."+err.Error())
+		}
+	}
+	if x := pl.Spec.Tenant; x != "" {
+		for _, msg := range validation.IsValidLabelValue(x) {
+			problems = append(problems, "tenant: "+msg)
+		}
+	}
+	if x := pl.Spec.Metadata; x != nil {
+		for _, key := range []string{dfv1.KeyPipelineName, dfv1.KeyStepName, dfv1.KeyTenant} {
+			if _, ok := x.Labels[key]; ok {
+				problems = append(problems, fmt.Sprintf("metadata.labels %q is set by the controller", key))
+			}
+		}
+	}
+	for _, step := range pl.Spec.Steps {
+		if step.Quota != nil {
+			problems = append(problems, fmt.Sprintf("step %q: quota must be specified on the pipeline, not a step", step.Name))
+		}
+	}
+	parameters := map[string]bool{}
+	for i, p := range pl.Spec.Parameters {
+		if !dfv1.ParameterNameRegexp.MatchString(p.Name) {
+			problems = append(problems, fmt.Sprintf("parameters[%d].name %q must match %q", i, p.Name, dfv1.ParameterNameRegexp.String()))
+		} else if parameters[p.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate parameter name %q", p.Name))
+		}
+		parameters[p.Name] = true
+	}
+	steps, err := pl.Spec.GetSteps()
+	if err != nil {
+		return append(problems, err.Error())
+	}
+	names := map[string]bool{}
+	for _, step := range steps {
+		name := nameOrDefault(step.Name)
+		if names[name] {
+			problems = append(problems, fmt.Sprintf("duplicate step name %q", name))
+		}
+		names[name] = true
+		for _, problem := range lintStep(step) {
+			problems = append(problems, fmt.Sprintf("step %q: %s", name, problem))
+		}
+	}
+	problems = append(problems, lintDependsOn(steps)...)
+	return append(problems, lintDAG(steps)...)
+}
+
+func lintStep(step dfv1.StepSpec) []string {
+	var problems []string
+	for _, msg := range validation.IsDNS1123Label(nameOrDefault(step.Name)) {
+		problems = append(problems, "name: "+msg)
+	}
+	if n := count(step.Cat != nil, step.Code != nil, step.Container != nil, step.Dedupe != nil, step.Expand != nil,
+		step.Filter != nil, step.Flatten != nil, step.Git != nil, step.Group != nil, step.Map != nil, step.Join != nil, step.Passthrough != nil, step.Sample != nil, step.Split != nil); n != 1 {
+		problems = append(problems, fmt.Sprintf("must have exactly one of cat, code, container, dedupe, expand, filter, flatten, git, group, join, map, passthrough, sample or split, got %d", n))
+	}
+	compile := func(field, expression string) {
+		if expression == "" {
+			return
+		}
+		if _, err := expr.Compile(expression); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: failed to compile %q: %v", field, expression, err))
+		}
+	}
+	if x := step.Container; x != nil && x.In != nil && x.In.Stdio {
+		if x.In.FIFO || x.In.HTTP != nil {
+			problems = append(problems, "container.in.stdio cannot be used with fifo or http")
+		}
+		if len(x.Command) == 0 {
+			problems = append(problems, "container.command is required with container.in.stdio")
+		}
+	}
+	containerNames := map[string]bool{}
+	for i, c := range step.Containers {
+		if c.Name == dfv1.CtrMain || c.Name == dfv1.CtrSidecar || c.Name == dfv1.CtrInit {
+			problems = append(problems, fmt.Sprintf("containers[%d].name %q is reserved", i, c.Name))
+		} else if containerNames[c.Name] {
+			problems = append(problems, fmt.Sprintf("duplicate container name %q", c.Name))
+		}
+		for _, msg := range validation.IsDNS1123Label(c.Name) {
+			problems = append(problems, fmt.Sprintf("containers[%d].name: %s", i, msg))
+		}
+		containerNames[c.Name] = true
+	}
+	if x := step.GetIn().HTTP; x != nil && x.Container != "" && x.Container != dfv1.CtrMain && !containerNames[x.Container] {
+		problems = append(problems, fmt.Sprintf("container.in.http.container %q must be main or the name of one of the step's containers", x.Container))
+	}
+	if x := step.Map; x != nil {
+		compile("map.expression", x.Expression)
+	}
+	if x := step.Filter; x != nil {
+		compile("filter.expression", x.Expression)
+	}
+	if x := step.Dedupe; x != nil {
+		compile("dedupe.uid", x.UID)
+	}
+	if x := step.Group; x != nil {
+		compile("group.key", x.Key)
+		compile("group.endOfGroup", x.EndOfGroup)
+	}
+	if x := step.Sample; x != nil {
+		if _, err := x.GetPercent(); err != nil {
+			problems = append(problems, "sample."+err.Error())
+		}
+		compile("sample.key", x.Key)
+	}
+	if x := step.Split; x != nil && (x.Delimiter == "") == (x.ChunkSize == nil || x.ChunkSize.Value() <= 0) {
+		problems = append(problems, "split must have exactly one of delimiter or a positive chunkSize")
+	}
+	compile("scale.desiredReplicas", step.Scale.DesiredReplicas)
+	compile("scale.peekDelay", step.Scale.PeekDelay)
+	compile("scale.scalingDelay", step.Scale.ScalingDelay)
+	if step.Scale.SlowConsumerDelay != nil && step.Scale.MaxReplicas == 0 {
+		problems = append(problems, "scale.slowConsumerDelay has no effect without scale.maxReplicas")
+	}
+	if x := step.Sidecar.Metrics; x != nil && x.Push != nil {
+		if (x.Push.Pushgateway == "") == (x.Push.RemoteWrite == "") {
+			problems = append(problems, "sidecar.metrics.push must have exactly one of pushgateway or remoteWrite")
+		}
+		if x.Push.GetInterval() <= 0 {
+			problems = append(problems, "sidecar.metrics.push.interval must be greater than zero")
+		}
+	}
+	if step.UpdateInterval != nil {
+		if _, err := step.GetUpdateInterval(time.Minute); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if x := step.Rollout; x != nil && x.Canary != nil {
+		if _, err := x.Canary.GetMaxErrorRate(); err != nil {
+			problems = append(problems, "rollout.canary."+err.Error())
+		}
+	}
+	if _, err := withDefaultNames(step).GetTransactionalSource(); err != nil {
+		problems = append(problems, err.Error())
+	}
+	sourceNames := map[string]bool{}
+	for _, source := range step.Sources {
+		name := nameOrDefault(source.Name)
+		if sourceNames[name] {
+			problems = append(problems, fmt.Sprintf("duplicate source name %q", name))
+		}
+		sourceNames[name] = true
+		for _, problem := range lintSource(source) {
+			problems = append(problems, fmt.Sprintf("source %q: %s", name, problem))
+		}
+	}
+	if n := count(sourceBounded(step.Sources)...); n > 0 && n < len(step.Sources) {
+		problems = append(problems, "sources must all be bounded, or none of them")
+	}
+	if x := step.Merge; x != nil {
+		switch x.GetPolicy() {
+		case dfv1.MergeRoundRobin, dfv1.MergeWeighted, dfv1.MergePriority:
+		default:
+			problems = append(problems, fmt.Sprintf("merge.policy %q must be RoundRobin, Weighted or Priority", x.Policy))
+		}
+		if len(step.Sources) < 2 {
+			problems = append(problems, "merge has no effect with fewer than two sources")
+		}
+	}
+	if x := step.Lifecycle; x != nil {
+		if len(x.PostStart) == 0 && len(x.PreStop) == 0 {
+			problems = append(problems, "lifecycle must have postStart or preStop")
+		}
+		if !step.HasMainContainer() {
+			problems = append(problems, "lifecycle has no effect without a main container")
+		}
+	}
+	if x := step.Recommendations; x != nil && x.HalfLife != nil && x.HalfLife.Duration <= 0 {
+		problems = append(problems, "recommendations.halfLife must be greater than zero")
+	}
+	if x := step.Join; x != nil {
+		if x.Left == x.Right {
+			problems = append(problems, "join.left and join.right must be different sources")
+		}
+		if !sourceNames[x.Left] {
+			problems = append(problems, fmt.Sprintf("join.left %q must be the name of one of the step's sources", x.Left))
+		}
+		if !sourceNames[x.Right] {
+			problems = append(problems, fmt.Sprintf("join.right %q must be the name of one of the step's sources", x.Right))
+		}
+		if x.Key == "" {
+			problems = append(problems, "join.key is required")
+		}
+		compile("join.key", x.Key)
+		if x.GetUnmatched() == dfv1.JoinUnmatchedDeadLetterQueue && !hasDeadLetterQueue(step) {
+			problems = append(problems, "join.unmatched DeadLetterQueue requires a dead-letter queue sink")
+		}
+	}
+	if x := step.State; x != nil {
+		if step.Dedupe == nil && step.Join == nil && x.CheckpointInterval == nil && !hasDedupeSink(step) {
+			problems = append(problems, "state has no effect without dedupe, join, an http.dedupe sink or checkpointInterval")
+		}
+		if count(x.Memory != nil, x.Disk != nil, x.Redis != nil) > 1 {
+			problems = append(problems, "state must have at most one of memory, disk or redis")
+		}
+		if x.Disk != nil && (step.Replicas > 1 || step.Scale.MaxReplicas > 1) {
+			problems = append(problems, "state.disk can only be used by a step with one replica")
+		}
+		if x.GetTTL() <= 0 {
+			problems = append(problems, "state.ttl must be greater than zero")
+		} else if y := step.Join; y != nil && x.GetTTL() < y.GetWindow() {
+			problems = append(problems, fmt.Sprintf("state.ttl %v must not be shorter than join.window %v", x.GetTTL(), y.GetWindow()))
+		}
+		if x.CheckpointInterval != nil {
+			if x.GetCheckpointInterval() <= 0 {
+				problems = append(problems, "state.checkpointInterval must be greater than zero")
+			} else if !x.IsPersistent() {
+				problems = append(problems, "state.checkpointInterval requires disk or redis, as memory is not kept on restart")
+			}
+		}
+	}
+	if step.CanComplete() && step.RestartPolicy == corev1.RestartPolicyAlways {
+		problems = append(problems, "restartPolicy Always cannot be used with bounded sources or completion, as the step would never complete")
+	}
+	if x := step.BackoffLimit; x != nil {
+		if *x < 0 {
+			problems = append(problems, "backoffLimit must not be negative")
+		}
+		if step.RestartPolicy != corev1.RestartPolicyNever {
+			problems = append(problems, "backoffLimit can only be used with restartPolicy Never")
+		}
+	}
+	if x := step.Completion; x != nil {
+		if x.Messages == 0 && x.Duration == nil && !x.Drained {
+			problems = append(problems, "completion must have at least one of messages, duration or drained")
+		}
+		if x.Duration != nil && x.Duration.Duration <= 0 {
+			problems = append(problems, fmt.Sprintf("completion.duration %q must be greater than zero", x.Duration.Duration))
+		}
+		if x.Drained {
+			if len(step.Sources) == 0 {
+				problems = append(problems, "completion.drained requires sources")
+			}
+			for _, source := range step.Sources {
+				if source.Kafka == nil && source.STAN == nil && source.JetStream == nil && !source.Bounded {
+					problems = append(problems, fmt.Sprintf("completion.drained is only supported by kafka, stan and jetstream sources, or bounded sources, not source %q", nameOrDefault(source.Name)))
+				}
+			}
+		}
+	}
+	sinkNames := map[string]bool{}
+	for _, sink := range step.Sinks {
+		name := nameOrDefault(sink.Name)
+		if sinkNames[name] {
+			problems = append(problems, fmt.Sprintf("duplicate sink name %q", name))
+		}
+		sinkNames[name] = true
+		for _, problem := range lintSink(sink) {
+			problems = append(problems, fmt.Sprintf("sink %q: %s", name, problem))
+		}
+	}
+	for _, source := range step.Sources {
+		if x := source.Kafka; x != nil && x.OffsetOutOfRange == dfv1.KafkaOffsetOutOfRangeDeadLetterQueue && !hasDeadLetterQueue(step) {
+			problems = append(problems, fmt.Sprintf("source %q: kafka.offsetOutOfRange DeadLetterQueue requires a dead-letter queue sink", nameOrDefault(source.Name)))
+		}
+	}
+	if x := step.Audit; x != nil {
+		found := false
+		for _, sink := range step.Sinks {
+			if nameOrDefault(sink.Name) == nameOrDefault(x.Sink) {
+				found = true
+				if sink.DeadLetterQueue {
+					problems = append(problems, fmt.Sprintf("audit.sink %q cannot be a dead-letter queue", x.Sink))
+				}
+			}
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("audit.sink %q must be the name of one of the step's sinks", x.Sink))
+		}
+	}
+	if x := step.Join; x != nil && x.Lateness != nil {
+		for _, source := range step.Sources {
+			if name := nameOrDefault(source.Name); (name == x.Left || name == x.Right) && source.EventTime == nil {
+				problems = append(problems, fmt.Sprintf("join.lateness requires source %q to have an eventTime", name))
+			}
+		}
+		if x.Lateness.GetAllowed() < 0 {
+			problems = append(problems, "join.lateness.allowed must not be negative")
+		}
+		if x.Lateness.GetPolicy() == dfv1.LateSink {
+			found := false
+			for _, sink := range step.Sinks {
+				if nameOrDefault(sink.Name) == nameOrDefault(x.Lateness.Sink) {
+					found = true
+					if sink.DeadLetterQueue || (step.Audit != nil && nameOrDefault(step.Audit.Sink) == nameOrDefault(x.Lateness.Sink)) {
+						problems = append(problems, fmt.Sprintf("join.lateness.sink %q cannot be a dead-letter queue or audit sink", x.Lateness.Sink))
+					}
+				}
+			}
+			if !found {
+				problems = append(problems, fmt.Sprintf("join.lateness.sink %q must be the name of one of the step's sinks", x.Lateness.Sink))
+			}
+		} else if x.Lateness.Sink != "" {
+			problems = append(problems, "join.lateness.sink has no effect without the Sink policy")
+		}
+	}
+	return append(problems, lintSecretKeySelectors("", reflect.ValueOf(step))...)
+}
+
+func hasDeadLetterQueue(step dfv1.StepSpec) bool {
+	for _, sink := range step.Sinks {
+		if sink.DeadLetterQueue {
+			return true
+		}
+	}
+	return false
+}
+
+func hasDedupeSink(step dfv1.StepSpec) bool {
+	for _, sink := range step.Sinks {
+		if sink.HTTP != nil && sink.HTTP.Dedupe {
+			return true
+		}
+	}
+	return false
+}
+
+func lintSource(source dfv1.Source) []string {
+	var problems []string
+	if n := count(source.ArgoEvents != nil, source.Cron != nil, source.Dapr != nil, source.DB != nil, source.Elasticsearch != nil,
+		source.Generator != nil, source.HTTP != nil, source.JetStream != nil, source.Kafka != nil, source.Kinesis != nil, source.Redis != nil, source.S3 != nil, source.SQS != nil, source.STAN != nil,
+		source.Test != nil, source.Volume != nil, source.AMQP != nil, source.MQTT != nil, source.RedisStream != nil); n != 1 {
+		problems = append(problems, fmt.Sprintf("must have exactly one type of source, got %d", n))
+	}
+	if x := source.Cron; x != nil {
+		if _, err := cronParser.Parse(x.Schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("cron.schedule: failed to parse %q: %v", x.Schedule, err))
+		}
+		if x.Template != "" {
+			if _, err := crons.ParseTemplate(x.Template); err != nil {
+				problems = append(problems, fmt.Sprintf("cron.template: %v", err))
+			}
+		}
+	}
+	if x := source.Kafka; x != nil {
+		if x.Topic == "" {
+			problems = append(problems, "kafka.topic is required")
+		}
+		for _, topic := range x.GetTopics() {
+			if !dfv1.IsTopicPattern(topic) {
+				continue
+			}
+			if _, err := regexp.Compile(topic); err != nil {
+				problems = append(problems, fmt.Sprintf("kafka: failed to compile topic pattern %q: %v", topic, err))
+			}
+			if x.Strimzi != nil {
+				problems = append(problems, fmt.Sprintf("kafka: topic pattern %q cannot be used with strimzi", topic))
+			}
+		}
+		for i, m := range x.MatchHeaders {
+			if m.Key == "" {
+				problems = append(problems, fmt.Sprintf("kafka.matchHeaders[%d].key is required", i))
+			}
+		}
+		problems = append(problems, lintKafkaCreateTopic(x.Kafka)...)
+		problems = append(problems, lintKafkaEventHubs(x.Kafka)...)
+		if len(x.StartOffsets) > 0 && !x.HasSingleTopic() {
+			problems = append(problems, "kafka.startOffsets can only be used with a single topic")
+		}
+		var partitions []string
+		for partition := range x.StartOffsets {
+			partitions = append(partitions, partition)
+		}
+		sort.Strings(partitions)
+		for _, partition := range partitions {
+			if p, err := strconv.Atoi(partition); err != nil || p < 0 {
+				problems = append(problems, fmt.Sprintf("kafka.startOffsets: %q is not a partition", partition))
+			} else if x.StartOffsets[partition] < 0 {
+				problems = append(problems, fmt.Sprintf("kafka.startOffsets: partition %q offset must not be negative", partition))
+			}
+		}
+	}
+	if x := source.HTTP; x != nil && x.OIDC != nil && x.TokenSecret != nil {
+		problems = append(problems, "http: oidc and tokenSecret cannot both be specified")
+	}
+	if x := source.HTTP; x != nil && x.TLS != nil {
+		if x.TLS.CertSecret == nil || x.TLS.KeySecret == nil {
+			problems = append(problems, "http.tls: clientCertSecret and clientKeySecret are required")
+		}
+		if x.TLS.CACertSecret != nil {
+			problems = append(problems, "http.tls: caCertSecret is not supported")
+		}
+	}
+	if x := source.STAN; x != nil && x.Subject == "" {
+		problems = append(problems, "stan.subject is required")
+	}
+	if x := source.JetStream; x != nil && x.Subject == "" {
+		problems = append(problems, "jetstream.subject is required")
+	}
+	if x := source.Generator; x != nil {
+		if _, err := generator.ParseTemplate(x.GetTemplate()); err != nil {
+			problems = append(problems, fmt.Sprintf("generator.template: %v", err))
+		}
+		if source.Bounded && x.Count == 0 {
+			problems = append(problems, "generator.count is required for a bounded generator source")
+		}
+	}
+	if x := source.Elasticsearch; x != nil {
+		if x.Index == "" {
+			problems = append(problems, "elasticsearch.index is required")
+		}
+		if !json.Valid([]byte(x.GetQuery())) {
+			problems = append(problems, "elasticsearch.query must be JSON")
+		}
+	}
+	if x := source.SQS; x != nil {
+		if v := x.GetWaitTime(); v < 0 || v > 20*time.Second {
+			problems = append(problems, fmt.Sprintf("sqs.waitTime %v must be between 0s and 20s", v))
+		}
+		if x.MaxMessages > 10 {
+			problems = append(problems, fmt.Sprintf("sqs.maxMessages %d must be between 1 and 10", x.MaxMessages))
+		}
+		if v := x.VisibilityTimeout; v != nil && (v.Duration < 0 || v.Duration > 12*time.Hour) {
+			problems = append(problems, fmt.Sprintf("sqs.visibilityTimeout %v must be between 0s and 12h", v.Duration))
+		}
+	}
+	if x := source.Kinesis; x != nil {
+		if x.Stream == "" {
+			problems = append(problems, "kinesis.stream is required")
+		}
+		if x.Limit > 10000 {
+			problems = append(problems, fmt.Sprintf("kinesis.limit %d must be at most 10000", x.Limit))
+		}
+		if x.GetPollPeriod() <= 0 {
+			problems = append(problems, "kinesis.pollPeriod must be greater than zero")
+		}
+	}
+	if x := source.AMQP; x != nil && x.Queue == "" {
+		problems = append(problems, "amqp.queue is required")
+	}
+	if x := source.MQTT; x != nil {
+		if x.Topic == "" {
+			problems = append(problems, "mqtt.topic is required")
+		}
+		if x.QoS > 2 {
+			problems = append(problems, fmt.Sprintf("mqtt.qos %d must be 0, 1 or 2", x.QoS))
+		}
+		if v := x.ProtocolVersion; v != 0 && v != 4 && v != 5 {
+			problems = append(problems, fmt.Sprintf("mqtt.protocolVersion %d must be 4 or 5", v))
+		}
+		if t := x.TLS; t != nil && (t.CertSecret == nil) != (t.KeySecret == nil) {
+			problems = append(problems, "mqtt.tls: clientCertSecret and clientKeySecret must both be specified, or neither")
+		}
+	}
+	if x := source.RedisStream; x != nil && x.Stream == "" {
+		problems = append(problems, "redisStream.stream is required")
+	}
+	if x := source.S3; x != nil {
+		if y := x.OnProcessed; y != nil {
+			if y.Tag != nil && y.MoveToPrefix != "" {
+				problems = append(problems, "s3.onProcessed: tag and moveToPrefix cannot both be specified")
+			}
+			if y.Tag != nil && y.Tag.Key == "" {
+				problems = append(problems, "s3.onProcessed.tag.key is required")
+			}
+			if y.MoveToPrefix != "" && strings.HasPrefix(x.Prefix, y.MoveToPrefix) {
+				problems = append(problems, fmt.Sprintf("s3.onProcessed.moveToPrefix %q must not be a prefix of s3.prefix %q, as no objects would be processed", y.MoveToPrefix, x.Prefix))
+			}
+		}
+		if y := x.Notifications; y != nil && y.QueueURL == "" {
+			problems = append(problems, "s3.notifications.queueUrl is required")
+		}
+	}
+	if source.Bounded && source.Elasticsearch == nil && source.Generator == nil && source.Kafka == nil && source.S3 == nil && source.Volume == nil {
+		problems = append(problems, "bounded is only supported by elasticsearch, generator, kafka, s3 and volume sources")
+	}
+	problems = append(problems, lintCodec(source.Codec)...)
+	if x := source.EventTime; x != nil {
+		if n := count(x.JSONPath != "", x.Header != ""); n != 1 {
+			problems = append(problems, fmt.Sprintf("eventTime: must have exactly one of jsonPath or header, got %d", n))
+		} else if x.JSONPath != "" {
+			if _, err := x.GetPath(); err != nil {
+				problems = append(problems, "eventTime."+err.Error())
+			}
+		} else if source.Kafka == nil && source.HTTP == nil && source.SQS == nil && source.AMQP == nil {
+			problems = append(problems, "eventTime.header is only supported by kafka, http, sqs and amqp sources")
+		}
+		switch x.GetFormat() {
+		case dfv1.EventTimeRFC3339, dfv1.EventTimeUnix, dfv1.EventTimeUnixMilli:
+		default:
+			problems = append(problems, fmt.Sprintf("eventTime.format %q must be RFC3339, Unix or UnixMilli", x.Format))
+		}
+	}
+	return problems
+}
+
+func lintSink(sink dfv1.Sink) []string {
+	var problems []string
+	if n := count(sink.CloudEvents != nil, sink.Dapr != nil, sink.DB != nil, sink.HTTP != nil, sink.JetStream != nil,
+		sink.Kafka != nil, sink.Log != nil, sink.Redis != nil, sink.S3 != nil, sink.Snowflake != nil, sink.STAN != nil, sink.Test != nil, sink.Volume != nil, sink.File != nil, sink.AMQP != nil, sink.RedisStream != nil); n != 1 {
+		problems = append(problems, fmt.Sprintf("must have exactly one type of sink, got %d", n))
+	}
+	if sink.OrderingKey != "" {
+		if _, err := expr.Compile(sink.OrderingKey); err != nil {
+			problems = append(problems, fmt.Sprintf("orderingKey: failed to compile %q: %v", sink.OrderingKey, err))
+		}
+	}
+	if x := sink.Timeout; x != nil && x.Duration <= 0 {
+		problems = append(problems, fmt.Sprintf("timeout %q must be greater than zero", x.Duration))
+	}
+	if x := sink.Kafka; x != nil && x.Topic == "" {
+		problems = append(problems, "kafka.topic is required")
+	}
+	if x := sink.Kafka; x != nil {
+		problems = append(problems, lintKafkaCreateTopic(x.Kafka)...)
+		problems = append(problems, lintKafkaEventHubs(x.Kafka)...)
+	}
+	if x := sink.STAN; x != nil && x.Subject == "" {
+		problems = append(problems, "stan.subject is required")
+	}
+	if x := sink.JetStream; x != nil && x.Subject == "" {
+		problems = append(problems, "jetstream.subject is required")
+	}
+	if x := sink.RedisStream; x != nil && x.Stream == "" {
+		problems = append(problems, "redisStream.stream is required")
+	}
+	if x := sink.AMQP; x != nil {
+		if x.Exchange == "" && x.RoutingKey == "" {
+			problems = append(problems, "amqp.routingKey is required for the default exchange, as it is the name of the queue")
+		}
+		if _, err := amqpsink.ParseRoutingKey(x.RoutingKey); err != nil {
+			problems = append(problems, fmt.Sprintf("amqp.routingKey: %v", err))
+		}
+	}
+	if x := sink.Snowflake; x != nil {
+		problems = append(problems, lintSnowflake(*x)...)
+	}
+	if x := sink.File; x != nil {
+		problems = append(problems, lintFile(*x)...)
+	}
+	problems = append(problems, lintCodec(sink.Codec)...)
+	return problems
+}
+
+func lintSnowflake(x dfv1.SnowflakeSink) []string {
+	var problems []string
+	if x.Table == "" {
+		problems = append(problems, "snowflake.table is required")
+	}
+	if len(x.Columns) == 0 {
+		problems = append(problems, "snowflake.columns: at least one column is required")
+	}
+	for i, c := range x.Columns {
+		if c.Name == "" || c.Value == "" {
+			problems = append(problems, fmt.Sprintf("snowflake.columns[%d]: name and value are required", i))
+		} else if _, err := expr.Compile(c.Value); err != nil {
+			problems = append(problems, fmt.Sprintf("snowflake.columns[%d].value: failed to compile %q: %v", i, c.Value, err))
+		}
+	}
+	return problems
+}
+
+func lintFile(x dfv1.FileSink) []string {
+	var problems []string
+	if x.Volume == (dfv1.AbstractVolumeSource{}) {
+		problems = append(problems, "file.volume is required")
+	}
+	if x.Partition != "" {
+		if _, err := expr.Compile(x.Partition); err != nil {
+			problems = append(problems, fmt.Sprintf("file.partition: failed to compile %q: %v", x.Partition, err))
+		}
+	}
+	if x.GetFormat() == dfv1.FileFormatParquet {
+		if x.ParquetSchema == "" {
+			problems = append(problems, "file.parquetSchema is required for Parquet files")
+		} else if _, err := schema.NewSchemaHandlerFromJSON(x.ParquetSchema); err != nil {
+			problems = append(problems, fmt.Sprintf("file.parquetSchema: %v", err))
+		}
+		if x.GetSync() == dfv1.FileSyncAlways {
+			problems = append(problems, "file.sync Always cannot be used with Parquet files, as they can only be synced when they are rotated")
+		}
+	}
+	if x.MaxSize.Sign() < 0 || x.MaxAge.Duration < 0 {
+		problems = append(problems, "file: maxSize and maxAge must not be negative")
+	}
+	if y := x.OnRotate; y != nil && y.URL == "" {
+		problems = append(problems, "file.onRotate.url is required")
+	}
+	return problems
+}
+
+func lintCodec(x *dfv1.Codec) []string {
+	if x == nil {
+		return nil
+	}
+	if n := count(x.JSON != nil, x.CSV != nil, x.MsgPack != nil, x.Avro != nil, x.Protobuf != nil); n != 1 {
+		return []string{fmt.Sprintf("codec: must have exactly one format, got %d", n)}
+	}
+	var problems []string
+	if y := x.CSV; y != nil && len([]rune(y.Delimiter)) > 1 {
+		problems = append(problems, fmt.Sprintf("codec.csv.delimiter %q must be a single character", y.Delimiter))
+	}
+	if y := x.Avro; y != nil && y.Schema == "" {
+		problems = append(problems, "codec.avro.schema is required")
+	}
+	if y := x.Protobuf; y != nil && (len(y.DescriptorSet) == 0 || y.MessageName == "") {
+		problems = append(problems, "codec.protobuf: descriptorSet and messageName are required")
+	}
+	if len(problems) > 0 {
+		return problems
+	}
+	if _, err := codec.New(*x); err != nil {
+		problems = append(problems, fmt.Sprintf("codec: %v", err))
+	}
+	return problems
+}
+
+func lintKafkaCreateTopic(x dfv1.Kafka) []string {
+	var problems []string
+	if c := x.CreateTopic; c != nil {
+		if x.Strimzi != nil {
+			problems = append(problems, "kafka.createTopic cannot be used with strimzi, which creates the topic")
+		}
+		if c.Partitions < 0 || c.ReplicationFactor < 0 {
+			problems = append(problems, "kafka.createTopic: partitions and replicationFactor must not be negative")
+		}
+		if r := c.Retention; r != nil && r.Duration <= 0 {
+			problems = append(problems, fmt.Sprintf("kafka.createTopic: retention %q must be greater than zero", r.Duration))
+		}
+	}
+	return problems
+}
+
+func lintKafkaEventHubs(x dfv1.Kafka) []string {
+	var problems []string
+	if e := x.EventHubs; e != nil {
+		if e.Namespace == "" {
+			problems = append(problems, "kafka.eventHubs.namespace is required")
+		}
+		if x.Strimzi != nil {
+			problems = append(problems, "kafka.eventHubs cannot be used with strimzi")
+		}
+	}
+	return problems
+}
+
+// lintSecretKeySelectors returns a problem for every secret reference without a name or key.
+func lintSecretKeySelectors(path string, v reflect.Value) []string {
+	var problems []string
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			problems = append(problems, lintSecretKeySelectors(path, v.Elem())...)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			problems = append(problems, lintSecretKeySelectors(fmt.Sprintf("%s[%d]", path, i), v.Index(i))...)
+		}
+	case reflect.Struct:
+		if v.Type() == secretKeySelectorType {
+			x := v.Interface().(corev1.SecretKeySelector)
+			if x.Name == "" || x.Key == "" {
+				problems = append(problems, fmt.Sprintf("%s: secret reference must have a name and key", path))
+			}
+			return problems
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			problems = append(problems, lintSecretKeySelectors(joinPath(path, f), v.Field(i))...)
+		}
+	}
+	return problems
+}
+
+func joinPath(path string, f reflect.StructField) string {
+	if f.Anonymous {
+		return path
+	}
+	name := f.Name
+	if tag := f.Tag.Get("json"); tag != "" {
+		name = strings.Split(tag, ",")[0]
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// lintDAG returns a problem for each cycle of steps, where steps are connected by a sink and a source that use the
+// same Kafka topic, STAN subject or JetStream subject.
+func lintDAG(steps []dfv1.StepSpec) []string {
+	writers := map[string][]string{} // topic -> steps
+	for _, step := range steps {
+		for _, sink := range step.Sinks {
+			if t := sinkTopic(sink); t != "" {
+				writers[t] = append(writers[t], nameOrDefault(step.Name))
+			}
+		}
+	}
+	edges := map[string]map[string]bool{} // step -> downstream steps
+	for _, step := range steps {
+		for _, source := range step.Sources {
+			for topic, froms := range writers {
+				if !sourceConsumes(source, topic) {
+					continue
+				}
+				for _, from := range froms {
+					if edges[from] == nil {
+						edges[from] = map[string]bool{}
+					}
+					edges[from][nameOrDefault(step.Name)] = true
+				}
+			}
+		}
+	}
+	return lintCycles("steps form a cycle", steps, edges)
+}
+
+// lintDependsOn returns problems with the steps' dependencies, which must be other steps, and must not form a cycle,
+// as none of the steps in the cycle would ever start.
+func lintDependsOn(steps []dfv1.StepSpec) []string {
+	names := map[string]bool{}
+	for _, step := range steps {
+		names[nameOrDefault(step.Name)] = true
+	}
+	var problems []string
+	edges := map[string]map[string]bool{} // step -> steps that depend on it
+	for _, step := range steps {
+		name := nameOrDefault(step.Name)
+		for i, d := range step.DependsOn {
+			if d.Name == name || !names[d.Name] {
+				problems = append(problems, fmt.Sprintf("step %q: dependsOn[%d].name %q must be the name of another step", name, i, d.Name))
+				continue
+			}
+			if _, err := expr.Compile(d.GetCondition()); err != nil {
+				problems = append(problems, fmt.Sprintf("step %q: dependsOn[%d].condition: failed to compile %q: %v", name, i, d.Condition, err))
+			}
+			if edges[d.Name] == nil {
+				edges[d.Name] = map[string]bool{}
+			}
+			edges[d.Name][name] = true
+		}
+	}
+	return append(problems, lintCycles("dependsOn forms a cycle", steps, edges)...)
+}
+
+func lintCycles(problem string, steps []dfv1.StepSpec, edges map[string]map[string]bool) []string {
+	var problems []string
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var visit func(name string, path []string)
+	visit = func(name string, path []string) {
+		switch state[name] {
+		case visiting:
+			for i, x := range path {
+				if x == name {
+					problems = append(problems, fmt.Sprintf("%s: %v", problem, append(path[i:], name)))
+				}
+			}
+			return
+		case visited:
+			return
+		}
+		state[name] = visiting
+		for _, next := range sortedKeys(edges[name]) {
+			visit(next, append(path, name))
+		}
+		state[name] = visited
+	}
+	for _, step := range steps {
+		visit(nameOrDefault(step.Name), nil)
+	}
+	return problems
+}
+
+// sourceConsumes returns whether the source consumes the topic returned by sinkTopic.
+func sourceConsumes(source dfv1.Source, topic string) bool {
+	if x := source.Kafka; x != nil {
+		return strings.HasPrefix(topic, "kafka:") && x.Consumes(strings.TrimPrefix(topic, "kafka:"))
+	} else if x := source.STAN; x != nil {
+		return topic == "stan:"+x.Subject
+	} else if x := source.JetStream; x != nil {
+		return topic == "jetstream:"+x.Subject
+	}
+	return false
+}
+
+func sinkTopic(sink dfv1.Sink) string {
+	if x := sink.Kafka; x != nil {
+		return "kafka:" + x.Topic
+	} else if x := sink.STAN; x != nil {
+		return "stan:" + x.Subject
+	} else if x := sink.JetStream; x != nil {
+		return "jetstream:" + x.Subject
+	}
+	return ""
+}
+
+func sourceBounded(sources []dfv1.Source) []bool {
+	bounded := make([]bool, len(sources))
+	for i, source := range sources {
+		bounded[i] = source.Bounded
+	}
+	return bounded
+}
+
+func sortedKeys(m map[string]bool) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// withDefaultNames returns a copy of the step with the default names of its sources and sinks, as the controller sets.
+func withDefaultNames(step dfv1.StepSpec) dfv1.StepSpec {
+	x := *step.DeepCopy()
+	for i := range x.Sources {
+		x.Sources[i].Name = nameOrDefault(x.Sources[i].Name)
+	}
+	for i := range x.Sinks {
+		x.Sinks[i].Name = nameOrDefault(x.Sinks[i].Name)
+	}
+	return x
+}
+
+// names are defaulted by the API server, but not when linting
+func nameOrDefault(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return name
+}
+
+func count(xs ...bool) int {
+	n := 0
+	for _, x := range xs {
+		if x {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,446 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	t.Run("Examples", func(t *testing.T) {
+		paths, err := filepath.Glob("../../examples/*.yaml")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, paths)
+		for _, path := range paths {
+			data, err := os.ReadFile(path)
+			assert.NoError(t, err)
+			assert.Empty(t, Lint(data), path)
+		}
+	})
+	t.Run("IgnoresOtherKinds", func(t *testing.T) {
+		assert.Empty(t, Lint([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+`)))
+	})
+	t.Run("UnknownField", func(t *testing.T) {
+		problems := Lint([]byte(`
+apiVersion: dataflow.argoproj.io/v1alpha1
+kind: Pipeline
+metadata:
+  name: my-pl
+spec:
+  steps:
+  - name: main
+    cat: {}
+    sinkz: []
+`))
+		if assert.Len(t, problems, 1) {
+			assert.Contains(t, problems[0], `unknown field "sinkz"`)
+		}
+	})
+	t.Run("Problems", func(t *testing.T) {
+		problems := Lint([]byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: my-cm
+---
+apiVersion: dataflow.argoproj.io/v1alpha1
+kind: Pipeline
+metadata:
+  name: my-pl
+spec:
+  upgrade:
+    replaces: my-pl
+    maxDeviation: x
+  parameters:
+  - name: schedule
+  - name: schedule
+    value: not a schedule
+  - name: 1x
+  steps:
+  - name: a
+    map:
+      expression: bytes(
+    merge:
+      policy: Random
+    updateInterval: 1ms
+    scale:
+      slowConsumerDelay: 5m
+    rollout:
+      canary:
+        maxErrorRate: "2"
+    sources:
+    - cron:
+        schedule: "{{ schedule }}"
+        template: "{{ .Time"
+    - name: in
+      stan:
+        subject: b-out
+      bounded: true
+      eventTime:
+        header: time
+        format: Weekly
+    - name: generator
+      generator:
+        template: "{{ seq"
+      bounded: true
+    - name: elasticsearch
+      elasticsearch:
+        query: "{"
+      bounded: true
+    - name: kafka
+      kafka:
+        topic: c
+        topics:
+        - ^d-(
+        matchHeaders:
+        - value: x
+        startOffsets:
+          "0": -1
+          x: 1
+        offsetOutOfRange: DeadLetterQueue
+    sinks:
+    - kafka:
+        topic: a-out
+        createTopic:
+          retention: 0s
+        eventHubs:
+          connectionStringSecret:
+            name: my-secret
+            key: connectionString
+      codec:
+        json: {}
+        csv: {}
+      timeout: -1s
+  - name: b
+    cat: {}
+    filter:
+      expression: "true"
+    restartPolicy: Always
+    sources:
+    - kafka:
+        topic: b-in
+        topics:
+        - ^a-.*
+        transactional: true
+      bounded: true
+    sinks:
+    - stan:
+        subject: b-out
+      orderingKey: '"'
+    - name: http
+      http:
+        url: http://my-url
+        headers:
+        - name: Authorization
+          valueFrom:
+            secretKeyRef:
+              name: my-secret
+    - name: snowflake
+      snowflake:
+        columns:
+        - name: id
+        - name: payload
+          value: 'string(msg'
+    - name: file
+      file:
+        partition: '"a" +'
+        format: Parquet
+        sync: Always
+        maxAge: -1s
+        onRotate: {}
+  - name: b
+    cat: {}
+  - name: c
+    container:
+      image: my-image
+      in:
+        fifo: true
+        stdio: true
+    dependsOn:
+    - name: c
+    - name: d
+      condition: (
+    completion: {}
+    backoffLimit: 1
+  - name: d
+    dependsOn:
+    - name: c
+    completion:
+      duration: -1s
+      drained: true
+    audit:
+      sink: audit
+    container:
+      image: my-image
+      in:
+        http:
+          container: cache
+    sources:
+    - http:
+        tls:
+          caCertSecret:
+            name: my-secret
+            key: ca.crt
+        oidc:
+          issuer: https://accounts.google.com
+        tokenSecret:
+          name: my-secret
+          key: token
+    containers:
+    - name: sidecar
+      image: my-image
+    - name: redis
+      image: redis
+    - name: redis
+      image: redis
+  - name: e
+    sample:
+      percent: "200"
+      key: (
+    state: {}
+    sources:
+    - sqs:
+        queueUrl: https://sqs.us-west-2.amazonaws.com/123456789012/my-queue
+        waitTime: 30s
+        visibilityTimeout: 24h
+        maxMessages: 11
+    - name: kinesis
+      kinesis:
+        limit: 20000
+        pollPeriod: 0s
+    - name: amqp
+      amqp: {}
+    - name: mqtt
+      mqtt:
+        qos: 3
+        protocolVersion: 3
+        tls:
+          clientCertSecret:
+            name: my-secret
+            key: tls.crt
+    - name: redis-stream
+      redisStream: {}
+    - name: s3
+      s3:
+        bucket: my-bucket
+        prefix: in/done/
+        onProcessed:
+          tag: {}
+          moveToPrefix: in/
+        notifications: {}
+    sinks:
+    - amqp:
+        exchange: my-exchange
+        routingKey: "{{"
+    - name: amqp
+      amqp: {}
+    - name: redis-stream
+      redisStream: {}
+  - name: f
+    split:
+      delimiter: ","
+      chunkSize: 1Mi
+    sidecar:
+      metrics:
+        push:
+          interval: 0s
+    state:
+      checkpointInterval: 10s
+  - name: g
+    lifecycle: {}
+    recommendations:
+      halfLife: 0s
+    join:
+      left: payments
+      right: payments
+      key: (
+      unmatched: DeadLetterQueue
+      lateness:
+        allowed: -1s
+        policy: Sink
+        sink: late
+    replicas: 2
+    state:
+      disk:
+        volume:
+          emptyDir: {}
+      redis: {}
+      ttl: 1s
+    sources:
+    - name: orders
+      kafka:
+        topic: orders
+---
+apiVersion: dataflow.argoproj.io/v1alpha1
+kind: Pipeline
+metadata:
+  name: my-job
+spec:
+  revisionHistoryLimit: -1
+  tenant: my team
+  metadata:
+    labels:
+      dataflow.argoproj.io/tenant: other
+  quota:
+    coordinator: other
+  slo:
+    maxLatency: 0s
+    maxErrorRate: "2"
+  schedule:
+    cron: every day
+    concurrencyPolicy: Sometimes
+    failedRunsHistoryLimit: -1
+  job:
+    backoffLimit: -1
+    activeDeadlineSeconds: 0
+  steps:
+  - name: main
+    cat: {}
+    merge: {}
+    quota:
+      messages: 1
+`))
+		assert.ElementsMatch(t, []string{
+			`pipeline "my-pl": duplicate parameter name "schedule"`,
+			`pipeline "my-pl": parameters[2].name "1x" must match "^[a-zA-Z_][a-zA-Z0-9_.-]*$"`,
+			`pipeline "my-pl": step "a": map.expression: failed to compile "bytes(": unexpected token EOF (1:6)
+ | bytes(
+ | .....^`,
+			`pipeline "my-pl": step "a": updateInterval 1ms must be between 1s and 10m0s`,
+			`pipeline "my-pl": step "a": scale.slowConsumerDelay has no effect without scale.maxReplicas`,
+			`pipeline "my-pl": step "a": rollout.canary.maxErrorRate "2" must be a number between 0 and 1`,
+			`pipeline "my-pl": step "a": source "default": cron.schedule: failed to parse "not a schedule": expected 5 to 6 fields, found 3: [not a schedule]`,
+			`pipeline "my-pl": step "a": source "default": cron.template: template: cron:1: unclosed action`,
+			"pipeline \"my-pl\": step \"a\": source \"kafka\": kafka: failed to compile topic pattern \"^d-(\": error parsing regexp: missing closing ): `^d-(`",
+			`pipeline "my-pl": step "a": source "kafka": kafka.matchHeaders[0].key is required`,
+			`pipeline "my-pl": step "a": source "kafka": kafka.startOffsets can only be used with a single topic`,
+			`pipeline "my-pl": step "a": source "kafka": kafka.startOffsets: partition "0" offset must not be negative`,
+			`pipeline "my-pl": step "a": source "kafka": kafka.startOffsets: "x" is not a partition`,
+			`pipeline "my-pl": step "a": source "kafka": kafka.offsetOutOfRange DeadLetterQueue requires a dead-letter queue sink`,
+			`pipeline "my-pl": step "a": source "in": bounded is only supported by elasticsearch, generator, kafka, s3 and volume sources`,
+			`pipeline "my-pl": step "a": source "generator": generator.template: template: generator:1: unclosed action`,
+			`pipeline "my-pl": step "a": source "generator": generator.count is required for a bounded generator source`,
+			`pipeline "my-pl": step "a": source "elasticsearch": elasticsearch.index is required`,
+			`pipeline "my-pl": step "a": source "elasticsearch": elasticsearch.query must be JSON`,
+			`pipeline "my-pl": step "a": source "in": eventTime.header is only supported by kafka, http, sqs and amqp sources`,
+			`pipeline "my-pl": step "a": source "in": eventTime.format "Weekly" must be RFC3339, Unix or UnixMilli`,
+			`pipeline "my-pl": step "a": sources must all be bounded, or none of them`,
+			`pipeline "my-pl": step "a": merge.policy "Random" must be RoundRobin, Weighted or Priority`,
+			`pipeline "my-pl": step "b": restartPolicy Always cannot be used with bounded sources or completion, as the step would never complete`,
+			`pipeline "my-pl": step "c": backoffLimit can only be used with restartPolicy Never`,
+			`pipeline "my-pl": step "c": completion must have at least one of messages, duration or drained`,
+			`pipeline "my-pl": step "d": completion.duration "-1s" must be greater than zero`,
+			`pipeline "my-pl": step "d": completion.drained is only supported by kafka, stan and jetstream sources, or bounded sources, not source "default"`,
+			`pipeline "my-pl": step "d": audit.sink "audit" must be the name of one of the step's sinks`,
+			`pipeline "my-pl": step "b": must have exactly one of cat, code, container, dedupe, expand, filter, flatten, git, group, join, map, passthrough, sample or split, got 2`,
+			`pipeline "my-pl": step "a": sink "default": timeout "-1s" must be greater than zero`,
+			`pipeline "my-pl": step "a": sink "default": kafka.createTopic: retention "0s" must be greater than zero`,
+			`pipeline "my-pl": step "a": sink "default": kafka.eventHubs.namespace is required`,
+			`pipeline "my-pl": step "a": sink "default": codec: must have exactly one format, got 2`,
+			`pipeline "my-pl": step "b": sink "default" must be a Kafka sink, as source "default" is transactional`,
+			`pipeline "my-pl": step "b": sink "default": orderingKey: failed to compile "\"": literal not terminated (1:2)
+ | "
+ | .^`,
+			`pipeline "my-pl": step "b": sinks[1].http.headers[0].valueFrom.secretKeyRef: secret reference must have a name and key`,
+			`pipeline "my-pl": step "b": sink "snowflake": snowflake.table is required`,
+			`pipeline "my-pl": step "b": sink "file": file.volume is required`,
+			`pipeline "my-pl": step "b": sink "file": file.partition: failed to compile "\"a\" +": unexpected token EOF (1:5)
+ | "a" +
+ | ....^`,
+			`pipeline "my-pl": step "b": sink "file": file.parquetSchema is required for Parquet files`,
+			`pipeline "my-pl": step "b": sink "file": file.sync Always cannot be used with Parquet files, as they can only be synced when they are rotated`,
+			`pipeline "my-pl": step "b": sink "file": file: maxSize and maxAge must not be negative`,
+			`pipeline "my-pl": step "b": sink "file": file.onRotate.url is required`,
+			`pipeline "my-pl": step "b": sink "snowflake": snowflake.columns[0]: name and value are required`,
+			`pipeline "my-pl": step "b": sink "snowflake": snowflake.columns[1].value: failed to compile "string(msg": unexpected token EOF (1:10)
+ | string(msg
+ | .........^`,
+			`pipeline "my-pl": step "c": container.in.stdio cannot be used with fifo or http`,
+			`pipeline "my-pl": step "c": container.command is required with container.in.stdio`,
+			`pipeline "my-pl": step "d": containers[0].name "sidecar" is reserved`,
+			`pipeline "my-pl": step "d": duplicate container name "redis"`,
+			`pipeline "my-pl": step "d": container.in.http.container "cache" must be main or the name of one of the step's containers`,
+			`pipeline "my-pl": step "d": source "default": http.tls: clientCertSecret and clientKeySecret are required`,
+			`pipeline "my-pl": step "d": source "default": http.tls: caCertSecret is not supported`,
+			`pipeline "my-pl": step "d": source "default": http: oidc and tokenSecret cannot both be specified`,
+			`pipeline "my-pl": step "e": source "default": sqs.waitTime 30s must be between 0s and 20s`,
+			`pipeline "my-pl": step "e": source "default": sqs.maxMessages 11 must be between 1 and 10`,
+			`pipeline "my-pl": step "e": source "default": sqs.visibilityTimeout 24h0m0s must be between 0s and 12h`,
+			`pipeline "my-pl": step "e": source "kinesis": kinesis.stream is required`,
+			`pipeline "my-pl": step "e": source "kinesis": kinesis.limit 20000 must be at most 10000`,
+			`pipeline "my-pl": step "e": source "kinesis": kinesis.pollPeriod must be greater than zero`,
+			`pipeline "my-pl": step "e": source "amqp": amqp.queue is required`,
+			`pipeline "my-pl": step "e": source "mqtt": mqtt.topic is required`,
+			`pipeline "my-pl": step "e": source "mqtt": mqtt.qos 3 must be 0, 1 or 2`,
+			`pipeline "my-pl": step "e": source "mqtt": mqtt.protocolVersion 3 must be 4 or 5`,
+			`pipeline "my-pl": step "e": source "mqtt": mqtt.tls: clientCertSecret and clientKeySecret must both be specified, or neither`,
+			`pipeline "my-pl": step "e": source "redis-stream": redisStream.stream is required`,
+			`pipeline "my-pl": step "e": source "s3": s3.onProcessed: tag and moveToPrefix cannot both be specified`,
+			`pipeline "my-pl": step "e": source "s3": s3.onProcessed.tag.key is required`,
+			`pipeline "my-pl": step "e": source "s3": s3.onProcessed.moveToPrefix "in/" must not be a prefix of s3.prefix "in/done/", as no objects would be processed`,
+			`pipeline "my-pl": step "e": source "s3": s3.notifications.queueUrl is required`,
+			`pipeline "my-pl": step "e": sink "default": amqp.routingKey: template: routingKey:1: unclosed action`,
+			`pipeline "my-pl": step "e": sink "amqp": amqp.routingKey is required for the default exchange, as it is the name of the queue`,
+			`pipeline "my-pl": step "e": sink "redis-stream": redisStream.stream is required`,
+			`pipeline "my-pl": step "g": lifecycle must have postStart or preStop`,
+			`pipeline "my-pl": step "g": lifecycle has no effect without a main container`,
+			`pipeline "my-pl": step "g": recommendations.halfLife must be greater than zero`,
+			`pipeline "my-pl": step "g": join.left and join.right must be different sources`,
+			`pipeline "my-pl": step "g": join.left "payments" must be the name of one of the step's sources`,
+			`pipeline "my-pl": step "g": join.right "payments" must be the name of one of the step's sources`,
+			`pipeline "my-pl": step "g": join.key: failed to compile "(": unexpected token EOF (1:1)
+ | (
+ | ^`,
+			`pipeline "my-pl": step "g": join.unmatched DeadLetterQueue requires a dead-letter queue sink`,
+			`pipeline "my-pl": step "g": state must have at most one of memory, disk or redis`,
+			`pipeline "my-pl": step "g": state.disk can only be used by a step with one replica`,
+			`pipeline "my-pl": step "g": state.ttl 1s must not be shorter than join.window 1m0s`,
+			`pipeline "my-pl": step "g": join.lateness.allowed must not be negative`,
+			`pipeline "my-pl": step "g": join.lateness.sink "late" must be the name of one of the step's sinks`,
+			`pipeline "my-pl": step "f": split must have exactly one of delimiter or a positive chunkSize`,
+			`pipeline "my-pl": step "f": sidecar.metrics.push must have exactly one of pushgateway or remoteWrite`,
+			`pipeline "my-pl": step "f": sidecar.metrics.push.interval must be greater than zero`,
+			`pipeline "my-pl": step "f": state.checkpointInterval requires disk or redis, as memory is not kept on restart`,
+			`pipeline "my-pl": step "e": sample.percent "200" must be a number between 0 and 100`,
+			`pipeline "my-pl": step "e": sample.key: failed to compile "(": unexpected token EOF (1:1)
+ | (
+ | ^`,
+			`pipeline "my-pl": step "e": state has no effect without dedupe, join, an http.dedupe sink or checkpointInterval`,
+			`pipeline "my-pl": upgrade.replaces must be the name of another pipeline`,
+			`pipeline "my-pl": upgrade.maxDeviation "x" must be a number greater than or equal to 0`,
+			`pipeline "my-pl": duplicate step name "b"`,
+			`pipeline "my-pl": step "c": dependsOn[0].name "c" must be the name of another step`,
+			"pipeline \"my-pl\": step \"c\": dependsOn[1].condition: failed to compile \"(\": unexpected token EOF (1:1)\n | (\n | ^",
+			`pipeline "my-pl": dependsOn forms a cycle: [c d c]`,
+			`pipeline "my-pl": steps form a cycle: [a b a]`,
+			`pipeline "my-job": job.backoffLimit must not be negative`,
+			`pipeline "my-job": schedule.cron: failed to parse "every day": expected 5 to 6 fields, found 2: [every day]`,
+			`pipeline "my-job": schedule.concurrencyPolicy "Sometimes" must be Allow, Forbid or Replace`,
+			`pipeline "my-job": schedule: history limits must not be negative`,
+			`pipeline "my-job": job.activeDeadlineSeconds must be greater than zero`,
+			`pipeline "my-job": revisionHistoryLimit must not be negative`,
+			`pipeline "my-job": quota must limit messages or bytes`,
+			`pipeline "my-job": quota.coordinator "other" must be the name of a step`,
+			`pipeline "my-job": slo.maxLatency must be greater than zero`,
+			`pipeline "my-job": slo.maxErrorRate "2" must be a number between 0 and 1`,
+			"pipeline \"my-job\": tenant: a valid label must be an empty string or consist of alphanumeric characters, '-', '_' or '.', and must start and end with an alphanumeric character (e.g. 'MyValue',  or 'my_value',  or '12345', regex used for validation is '(([A-Za-z0-9][-A-Za-z0-9_.]*)?[A-Za-z0-9])?')",
+			`pipeline "my-job": metadata.labels "dataflow.argoproj.io/tenant" is set by the controller`,
+			`pipeline "my-job": step "main": quota must be specified on the pipeline, not a step`,
+			`pipeline "my-job": step "main": backoffLimit must not be negative`,
+			`pipeline "my-job": step "main": merge has no effect with fewer than two sources`,
+		}, problems)
+	})
+}
+
+func TestExec(t *testing.T) {
+	assert.Error(t, Exec(nil))
+	assert.NoError(t, Exec([]string{"../../examples/101-hello-pipeline.yaml"}))
+	assert.Error(t, Exec([]string{"not-found.yaml"}))
+}
@@ -3,17 +3,23 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/http/pprof"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 	_init "github.com/argoproj-labs/argo-dataflow/runner/init"
+	"github.com/argoproj-labs/argo-dataflow/runner/inject"
+	"github.com/argoproj-labs/argo-dataflow/runner/lint"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar"
+	"github.com/argoproj-labs/argo-dataflow/runner/snapshot"
 	"github.com/argoproj-labs/argo-dataflow/sdks/golang"
 	"github.com/argoproj-labs/argo-dataflow/shared/builtin"
 	"github.com/argoproj-labs/argo-dataflow/shared/builtin/cat"
@@ -23,13 +29,19 @@ import (
 	"github.com/argoproj-labs/argo-dataflow/shared/builtin/flatten"
 	"github.com/argoproj-labs/argo-dataflow/shared/builtin/group"
 	_map "github.com/argoproj-labs/argo-dataflow/shared/builtin/map"
+	"github.com/argoproj-labs/argo-dataflow/shared/builtin/sample"
+	"github.com/argoproj-labs/argo-dataflow/shared/builtin/split"
 	"github.com/argoproj-labs/argo-dataflow/shared/debug"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
-var logger = sharedutil.NewLogger()
+var (
+	logger           = sharedutil.NewLogger()
+	errNotCLICommand = errors.New("not a CLI command")
+)
 
 func init() {
 	// https://mmcloughlin.com/posts/your-pprof-is-showing
@@ -47,9 +59,51 @@ func init() {
 }
 
 func main() {
-	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
 	defer stop()
 
+	// commands run by users, rather than as containers, so errors are not written to the termination-log
+	if err := func() error {
+		switch os.Args[1] {
+		case "bench":
+			flags := flag.NewFlagSet("bench", flag.ContinueOnError)
+			opts := sidecar.BenchOptions{}
+			flags.Uint64Var(&opts.Count, "count", 10000, "number of messages sent by each source")
+			flags.IntVar(&opts.Sources, "sources", 1, "number of sources, sending messages concurrently")
+			rate := flags.Uint("rate", 100000, "most messages sent per second by each source")
+			flags.IntVar(&opts.Size, "size", 100, "size of each message in bytes")
+			if err := flags.Parse(os.Args[2:]); err != nil {
+				return err
+			}
+			opts.Rate = uint32(*rate)
+			return sidecar.ExecBench(ctx, opts, os.Stdout)
+		case "inject":
+			if len(os.Args) < 3 {
+				return fmt.Errorf("usage: inject SOURCE [MESSAGE]")
+			}
+			msg := []byte(strings.Join(os.Args[3:], " "))
+			if len(os.Args) == 3 {
+				var err error
+				if msg, err = ioutil.ReadAll(os.Stdin); err != nil {
+					return err
+				}
+			}
+			return inject.Exec(ctx, os.Args[2], msg, os.Stdout)
+		case "lint":
+			return lint.Exec(os.Args[2:])
+		case "snapshot":
+			return snapshot.Exec(ctx, os.Stdout)
+		default:
+			return errNotCLICommand
+		}
+	}(); err != errNotCLICommand {
+		if err != nil {
+			_, _ = fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	start := func(f builtin.Process) error {
 		return golang.StartWithContext(ctx, f)
 	}
@@ -64,7 +118,17 @@ func main() {
 			if err != nil {
 				return fmt.Errorf("failed to parse %q as resource quanity: %w", x, err)
 			}
-			p, err := dedupe.New(ctx, os.Args[2], maxSize)
+			// without a persistent state, the dedupe keeps its own, size-bounded, in-memory state
+			var store state.Store
+			if x, err := state.FromEnv(); err != nil {
+				return err
+			} else if x.IsPersistent() {
+				if store, err = state.NewFromEnv(ctx, "dedupe"); err != nil {
+					return err
+				}
+				defer func() { _ = store.Close() }()
+			}
+			p, err := dedupe.New(ctx, os.Args[2], maxSize, store)
 			if err != nil {
 				return err
 			}
@@ -73,7 +137,7 @@ func main() {
 		case "expand":
 			return start(expand.New())
 		case "filter":
-			p, err := filter.New(os.Args[2])
+			p, err := builtin.Reload(ctx, dfv1.PathExpression, os.Args[2], filter.New, 3*time.Second)
 			if err != nil {
 				return err
 			}
@@ -88,14 +152,47 @@ func main() {
 			return start(p)
 		case "init":
 			return _init.Exec(ctx)
+		case "local":
+			if len(os.Args) < 4 {
+				return fmt.Errorf("usage: local STEP FILE...")
+			}
+			return sidecar.ExecLocal(ctx, os.Args[2], os.Args[3:])
 		case "map":
-			p, err := _map.New(os.Args[2])
+			p, err := builtin.Reload(ctx, dfv1.PathExpression, os.Args[2], _map.New, 3*time.Second)
+			if err != nil {
+				return err
+			}
+			return start(p)
+		case "sample":
+			percent, err := dfv1.Sample{Percent: os.Args[2]}.GetPercent()
+			if err != nil {
+				return err
+			}
+			p, err := sample.New(percent, os.Args[3])
 			if err != nil {
 				return err
 			}
 			return start(p)
 		case "sidecar":
 			return sidecar.Exec(ctx)
+		case "split":
+			var size int64
+			if x := os.Args[3]; x != "" {
+				q, err := resource.ParseQuantity(x)
+				if err != nil {
+					return fmt.Errorf("failed to parse %q as resource quanity: %w", x, err)
+				}
+				size = q.Value()
+			}
+			send, err := split.NewSidecarSend()
+			if err != nil {
+				return err
+			}
+			p, err := split.New(os.Args[2], size, send)
+			if err != nil {
+				return err
+			}
+			return start(p)
 		default:
 			return fmt.Errorf("unknown comand")
 		}
@@ -0,0 +1,32 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func amqpFromSecret(x *dfv1.AMQP, secret *corev1.Secret) {
+	x.URL = dfv1.StringOr(x.URL, string(secret.Data["url"]))
+	if _, ok := secret.Data["password"]; ok && x.PasswordSecret == nil {
+		x.PasswordSecret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Key:                  "password",
+		}
+	}
+}
+
+func enrichAMQP(ctx context.Context, x *dfv1.AMQP) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-amqp-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		amqpFromSecret(x, secret)
+	}
+	return nil
+}
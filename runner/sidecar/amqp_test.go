@@ -0,0 +1,30 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_amqpFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-amqp-default"},
+		Data:       map[string][]byte{"url": []byte("amqp://my-user@rabbitmq:5672"), "password": []byte("my-password")},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.AMQP{}
+		amqpFromSecret(x, secret)
+		assert.Equal(t, "amqp://my-user@rabbitmq:5672", x.URL)
+		assert.Equal(t, &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-amqp-default"}, Key: "password"}, x.PasswordSecret)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		passwordSecret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "my-key"}
+		x := &dfv1.AMQP{URL: "amqp://my-user@my-rabbitmq:5672", PasswordSecret: passwordSecret}
+		amqpFromSecret(x, secret)
+		assert.Equal(t, "amqp://my-user@my-rabbitmq:5672", x.URL)
+		assert.Equal(t, passwordSecret, x.PasswordSecret)
+	})
+}
@@ -0,0 +1,51 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+// auditRecord is the metadata of a processed message, without its payload.
+type auditRecord struct {
+	ID         string    `json:"id"`
+	Source     string    `json:"source"`
+	SourceName string    `json:"sourceName"`
+	Pipeline   string    `json:"pipeline"`
+	Step       string    `json:"step"`
+	Replica    int       `json:"replica"`
+	Size       int       `json:"size"`
+	Time       time.Time `json:"time"`
+	StartedAt  time.Time `json:"startedAt"`
+	FinishedAt time.Time `json:"finishedAt"`
+	Outcome    string    `json:"outcome"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// auditMessage writes a record of the processed message, if the step has an audit sink. An audit record that cannot be
+// written is logged, rather than failing the message, as that would process it again.
+func auditMessage(ctx context.Context, audit func(context.Context, []byte) error, sourceName string, size int, startedAt time.Time, err error) {
+	x := auditRecord{
+		SourceName: sourceName,
+		Pipeline:   pipelineName,
+		Step:       stepName,
+		Replica:    replica,
+		Size:       size,
+		StartedAt:  startedAt,
+		FinishedAt: time.Now(),
+		Outcome:    "succeeded",
+	}
+	if m, err := dfv1.MetaFromContext(ctx); err == nil {
+		x.ID, x.Source, x.Time = m.ID, m.Source, time.Unix(m.Time, 0)
+	}
+	if err != nil {
+		x.Outcome, x.Error = "failed", err.Error()
+	}
+	data, _ := json.Marshal(x)
+	// the message's context may have been cancelled, but the record must still be written
+	if err := audit(context.Background(), data); err != nil {
+		logger.Error(err, "failed to write audit record", "id", x.ID)
+	}
+}
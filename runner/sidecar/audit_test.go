@@ -0,0 +1,39 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_auditMessage(t *testing.T) {
+	var records []auditRecord
+	audit := func(ctx context.Context, data []byte) error {
+		x := auditRecord{}
+		assert.NoError(t, json.Unmarshal(data, &x))
+		records = append(records, x)
+		return nil
+	}
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "urn:dataflow:kafka:my-topic", ID: "0-1", Time: 1630497600})
+	startedAt := time.Now()
+	auditMessage(ctx, audit, "in", 3, startedAt, nil)
+	auditMessage(ctx, audit, "in", 5, startedAt, errors.New("failed"))
+	if assert.Len(t, records, 2) {
+		x := records[0]
+		assert.Equal(t, "0-1", x.ID)
+		assert.Equal(t, "urn:dataflow:kafka:my-topic", x.Source)
+		assert.Equal(t, "in", x.SourceName)
+		assert.Equal(t, 3, x.Size)
+		assert.True(t, time.Unix(1630497600, 0).Equal(x.Time))
+		assert.False(t, x.FinishedAt.Before(x.StartedAt))
+		assert.Equal(t, "succeeded", x.Outcome)
+		assert.Empty(t, x.Error)
+		assert.Equal(t, "failed", records[1].Outcome)
+		assert.Equal(t, "failed", records[1].Error)
+	}
+}
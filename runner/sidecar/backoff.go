@@ -1,7 +1,12 @@
 package sidecar
 
 import (
+	"context"
+	"math"
+	"time"
+
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
@@ -14,3 +19,34 @@ func newBackoff(backoff dfv1.Backoff) wait.Backoff {
 		Cap:      backoff.Cap.Duration,
 	}
 }
+
+// newFailureBackoff returns a backoff for a periodic task, which runs every interval while it succeeds, and backs off
+// exponentially (up to ten times the interval) while it fails.
+func newFailureBackoff(interval time.Duration) wait.Backoff {
+	return wait.Backoff{
+		Duration: interval,
+		Factor:   2,
+		Jitter:   0.2,
+		Steps:    math.MaxInt32,
+		Cap:      10 * interval,
+	}
+}
+
+// untilWithFailureBackoff runs f periodically until the context is done, backing off while f returns errors.
+func untilWithFailureBackoff(ctx context.Context, f func(ctx context.Context) error, interval time.Duration) {
+	defer runtime.HandleCrash()
+	backoff := newFailureBackoff(interval)
+	for {
+		delay := wait.Jitter(interval, 0.2)
+		if err := f(ctx); err != nil {
+			delay = backoff.Step()
+		} else {
+			backoff = newFailureBackoff(interval)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
@@ -41,3 +41,14 @@ func Test_newBackoff(t *testing.T) {
 		})
 	}
 }
+
+func Test_newFailureBackoff(t *testing.T) {
+	b := newFailureBackoff(time.Second)
+	b.Jitter = 0
+	assert.Equal(t, time.Second, b.Step())
+	assert.Equal(t, 2*time.Second, b.Step())
+	for i := 0; i < 10; i++ {
+		b.Step()
+	}
+	assert.Equal(t, 10*time.Second, b.Step())
+}
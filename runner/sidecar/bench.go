@@ -0,0 +1,160 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	"github.com/argoproj-labs/argo-dataflow/sdks/golang"
+	"github.com/argoproj-labs/argo-dataflow/shared/builtin/cat"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// BenchOptions are the options for ExecBench.
+type BenchOptions struct {
+	// Count is the number of messages sent by each source.
+	Count uint64
+	// Sources is the number of generator sources, which send messages concurrently.
+	Sources int
+	// Rate is the most messages each source sends per second.
+	Rate uint32
+	// Size is the size of each message, in bytes.
+	Size int
+}
+
+// benchRecorder is the bench's sink, it records the latency of each message, from when it was generated.
+type benchRecorder struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	last      time.Time
+}
+
+func (r *benchRecorder) sink(_ context.Context, msg []byte) error {
+	now := time.Now()
+	i := bytes.IndexByte(msg, ' ')
+	if i < 0 {
+		return fmt.Errorf("message %q does not start with a time", msg)
+	}
+	t, err := strconv.ParseInt(string(msg[:i]), 10, 64)
+	if err != nil {
+		return fmt.Errorf("message %q does not start with a time: %w", msg, err)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies = append(r.latencies, now.Sub(time.Unix(0, t)))
+	r.last = now
+	return nil
+}
+
+// ExecBench benchmarks the sidecar's path, without Kubernetes. Generator sources send messages to a cat handler,
+// running in the same process, using the HTTP in interface, and the handler's responses are written to a sink that
+// records their latency. The throughput and latency percentiles are written to out.
+func ExecBench(ctx context.Context, opts BenchOptions, out io.Writer) error {
+	if opts.Count == 0 || opts.Sources <= 0 || opts.Rate == 0 || opts.Size < 0 {
+		return fmt.Errorf("count, sources and rate must be greater than zero, and size must not be negative")
+	}
+	cluster, namespace, pod = "bench", "default", "bench"
+	pipelineName, stepName = "bench", "main"
+	updateInterval = time.Second
+	kubernetesInterface = fake.NewSimpleClientset()
+	secretInterface = util.NewSecretInterface(kubernetesInterface.CoreV1().Secrets(namespace))
+	// each message starts with the time it was generated, padded to the size
+	template := "{{now.UnixNano}} " + strings.Repeat("x", opts.Size)
+	var sources []dfv1.Source
+	for i := 0; i < opts.Sources; i++ {
+		sources = append(sources, dfv1.Source{
+			Name:      fmt.Sprintf("bench-%d", i),
+			Generator: &dfv1.GeneratorSource{Rate: opts.Rate, Template: template, Count: opts.Count},
+			Bounded:   true,
+		})
+	}
+	pl := &dfv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: pipelineName},
+		Spec:       dfv1.PipelineSpec{Steps: []dfv1.StepSpec{{Name: stepName, Cat: &dfv1.Cat{}, Sources: sources}}},
+	}
+	// round-trip via JSON, so the step has the same defaults as a local one
+	data, err := json.Marshal(pl)
+	if err != nil {
+		return err
+	}
+	un := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, &un.Object); err != nil {
+		return err
+	}
+	if step, err = getLocalStep(un, stepName); err != nil {
+		return err
+	}
+	live.update(step.Spec)
+
+	if err := os.MkdirAll(dfv1.PathVarRun, 0o700); err != nil {
+		return fmt.Errorf("failed to create %q, you may need to create it and make it writable: %w", dfv1.PathVarRun, err)
+	}
+	handlerCtx, stopHandler := context.WithCancel(ctx)
+	defer stopHandler()
+	go func() {
+		if err := golang.StartWithContext(handlerCtx, cat.New()); err != nil {
+			logger.Error(err, "failed to start handler")
+		}
+	}()
+
+	r := &benchRecorder{}
+	process, err := connectIn(ctx, r.sink)
+	if err != nil {
+		return err
+	}
+	noop := func(context.Context, []byte) error { return nil }
+	ctx, complete := context.WithCancel(ctx)
+	defer complete()
+	failed := make(chan error, 1)
+	fail := func(err error) {
+		select {
+		case failed <- err:
+		default:
+		}
+		complete()
+	}
+	start := time.Now()
+	if err := connectSources(ctx, process, noop, noop, noop, complete, fail); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	select {
+	case err := <-failed:
+		return err
+	default:
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n := uint64(len(r.latencies)); n < opts.Count*uint64(opts.Sources) {
+		return fmt.Errorf("only %d of %d messages were processed", n, opts.Count*uint64(opts.Sources))
+	}
+	_, err = fmt.Fprint(out, benchReport(r.latencies, r.last.Sub(start)))
+	return err
+}
+
+// benchReport returns the throughput and latency percentiles of the messages. It sorts the latencies.
+func benchReport(latencies []time.Duration, elapsed time.Duration) string {
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	percentile := func(p float64) time.Duration {
+		return latencies[int(p*float64(len(latencies)-1))]
+	}
+	return fmt.Sprintf("messages:   %d\nduration:   %v\nthroughput: %.0f msgs/sec\nlatency:    p50=%v p90=%v p99=%v max=%v\n",
+		len(latencies),
+		elapsed.Round(time.Millisecond),
+		float64(len(latencies))/elapsed.Seconds(),
+		percentile(0.5), percentile(0.9), percentile(0.99), latencies[len(latencies)-1],
+	)
+}
@@ -0,0 +1,31 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_benchRecorder(t *testing.T) {
+	r := &benchRecorder{}
+	assert.NoError(t, r.sink(context.Background(), []byte(fmt.Sprintf("%d xx", time.Now().Add(-time.Second).UnixNano()))))
+	assert.Len(t, r.latencies, 1)
+	assert.GreaterOrEqual(t, r.latencies[0], time.Second)
+	assert.Error(t, r.sink(context.Background(), []byte("foo")))
+	assert.Error(t, r.sink(context.Background(), []byte("foo bar")))
+}
+
+func Test_benchReport(t *testing.T) {
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, `messages:   100
+duration:   2s
+throughput: 50 msgs/sec
+latency:    p50=50ms p90=90ms p99=99ms max=100ms
+`, benchReport(latencies, 2*time.Second))
+}
@@ -0,0 +1,88 @@
+package sidecar
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// errCertificatesChanged is returned when a mounted certificate changes. The sidecar exits with an error, so that
+// Kubernetes restarts the sidecar container, but not the pod, and every client is re-created with the new certificates.
+var errCertificatesChanged = errors.New("certificates changed, restarting to reload them")
+
+// certificatesChanged is set (to 1) if the sidecar is stopping because certificates changed, in which case the main
+// container keeps running. It is written by the watcher's goroutine, so it must be accessed atomically.
+var certificatesChanged int32
+
+func setCertificatesChanged() { atomic.StoreInt32(&certificatesChanged, 1) }
+
+func isCertificatesChanged() bool { return atomic.LoadInt32(&certificatesChanged) == 1 }
+
+// canRestartSidecar returns true if Kubernetes restarts the sidecar container when it exits with an error. With
+// restartPolicy Never the whole pod would fail, so changed certificates are not reloaded until the pod is replaced.
+func canRestartSidecar(restartPolicy corev1.RestartPolicy) bool {
+	return restartPolicy != corev1.RestartPolicyNever
+}
+
+// hashSecrets returns a hash of the keys and values of the secrets mounted in the directories.
+func hashSecrets(dirs []string) (string, error) {
+	h := sha256.New()
+	for _, dir := range dirs {
+		items, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return "", err
+		}
+		for _, item := range items {
+			// Kubernetes atomically swaps the hidden `..data` directory, and each key is a symlink into it
+			if strings.HasPrefix(item.Name(), ".") || item.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, item.Name()))
+			if err != nil {
+				return "", err
+			}
+			_, _ = fmt.Fprintf(h, "%s/%s=%d:", dir, item.Name(), len(data))
+			_, _ = h.Write(data)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// watchCertificates calls onChange once, if the secrets mounted in the directories change. The kubelet updates mounted
+// secrets periodically (about once a minute), so the period does not need to be short.
+func watchCertificates(ctx context.Context, dirs []string, period time.Duration, onChange func()) error {
+	initial, err := hashSecrets(dirs)
+	if err != nil {
+		return fmt.Errorf("failed to hash certificates: %w", err)
+	}
+	go func() {
+		defer runtime.HandleCrash()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(period):
+				if h, err := hashSecrets(dirs); err != nil {
+					logger.Error(err, "failed to hash certificates")
+				} else if h != initial {
+					logger.Info("certificates changed", "dirs", dirs)
+					onChange()
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
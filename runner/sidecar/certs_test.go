@@ -0,0 +1,50 @@
+package sidecar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_hashSecrets(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "..data"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), []byte("a"), 0o600))
+	a, err := hashSecrets([]string{dir, filepath.Join(dir, "not-mounted")})
+	assert.NoError(t, err)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), []byte("b"), 0o600))
+	b, err := hashSecrets([]string{dir})
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, b)
+}
+
+func Test_watchCertificates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), []byte("a"), 0o600))
+	changed := make(chan struct{}, 1)
+	assert.NoError(t, watchCertificates(ctx, []string{dir}, time.Millisecond, func() { changed <- struct{}{} }))
+	select {
+	case <-changed:
+		assert.Fail(t, "unexpected change")
+	case <-time.After(20 * time.Millisecond):
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), []byte("b"), 0o600))
+	select {
+	case <-changed:
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "expected change")
+	}
+}
+
+func Test_canRestartSidecar(t *testing.T) {
+	assert.True(t, canRestartSidecar(""))
+	assert.True(t, canRestartSidecar(corev1.RestartPolicyOnFailure))
+	assert.False(t, canRestartSidecar(corev1.RestartPolicyNever))
+}
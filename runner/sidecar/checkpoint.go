@@ -0,0 +1,71 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// checkpointer checkpoints the positions of the sources that only keep them in memory, see source.Checkpointable.
+// Positions are per replica, as each replica's sources have their own.
+type checkpointer struct {
+	store state.Store
+}
+
+// newCheckpointer returns a checkpointer, or nil if the step's state is not checkpointed.
+func newCheckpointer(ctx context.Context) (*checkpointer, error) {
+	x := step.Spec.State
+	if x.GetCheckpointInterval() <= 0 || !x.IsPersistent() {
+		return nil, nil
+	}
+	store, err := state.Open(ctx, x, "sources")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open checkpoint store: %w", err)
+	}
+	return &checkpointer{store: store}, nil
+}
+
+func (c *checkpointer) key(sourceName string) string {
+	return fmt.Sprintf("%s/%d", sourceName, replica)
+}
+
+// position returns the source's checkpointed position, or "" if there is none.
+func (c *checkpointer) position(ctx context.Context, sourceName string) (string, error) {
+	if c == nil {
+		return "", nil
+	}
+	v, err := c.store.Get(ctx, c.key(sourceName))
+	if err != nil {
+		return "", fmt.Errorf("failed to get source %q checkpoint: %w", sourceName, err)
+	}
+	logger.Info("restored source position", "source", sourceName, "position", string(v))
+	return string(v), nil
+}
+
+func (c *checkpointer) checkpoint(ctx context.Context, sources map[string]source.Interface) {
+	for sourceName, s := range sources {
+		if x, ok := s.(source.Checkpointable); ok {
+			if err := c.store.Set(ctx, c.key(sourceName), []byte(x.GetPosition())); err != nil {
+				logger.Error(err, "failed to checkpoint source position", "source", sourceName)
+			}
+		}
+	}
+}
+
+// run checkpoints the sources' positions every interval, and once they are closed.
+func (c *checkpointer) run(ctx context.Context, sources map[string]source.Interface) {
+	if c == nil {
+		return
+	}
+	interval := step.Spec.State.GetCheckpointInterval()
+	logger.Info("checkpointing source positions", "interval", interval)
+	go wait.UntilWithContext(ctx, func(ctx context.Context) { c.checkpoint(ctx, sources) }, interval)
+	// stop hooks run after the pre-stop hooks that close the sources
+	addStopHook(func(ctx context.Context) error {
+		c.checkpoint(ctx, sources)
+		return c.store.Close()
+	})
+}
@@ -0,0 +1,36 @@
+package sidecar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	"github.com/stretchr/testify/assert"
+)
+
+type testCheckpointable struct{ source.Interface }
+
+func (testCheckpointable) GetPosition() string { return "3" }
+
+func Test_checkpointer(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Nil", func(t *testing.T) {
+		var c *checkpointer
+		position, err := c.position(ctx, "my-source")
+		assert.NoError(t, err)
+		assert.Empty(t, position)
+	})
+	t.Run("Checkpoint", func(t *testing.T) {
+		c := &checkpointer{store: state.NewMemory(ctx, time.Minute)}
+		defer func() { _ = c.store.Close() }()
+		c.checkpoint(ctx, map[string]source.Interface{"my-source": testCheckpointable{}, "other": nil})
+		position, err := c.position(ctx, "my-source")
+		assert.NoError(t, err)
+		assert.Equal(t, "3", position)
+		position, err = c.position(ctx, "other")
+		assert.NoError(t, err)
+		assert.Empty(t, position)
+	})
+}
@@ -0,0 +1,70 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+var (
+	inFlight  int64 // messages being processed, across all sources
+	processed int64 // messages processed, across all sources
+)
+
+// connectCompletion calls complete once the step has run for its completion duration, or its sources are drained and no
+// messages are being processed. Completion after a number of messages is checked as each message is processed.
+func connectCompletion(ctx context.Context, sources map[string]source.Interface, complete func()) {
+	if d := step.Spec.Completion.GetDuration(); d > 0 {
+		logger.Info("starting completion timer", "duration", d.String())
+		go func() {
+			defer runtime.HandleCrash()
+			select {
+			case <-ctx.Done():
+			case <-time.After(d):
+				logger.Info("completion duration elapsed, completing", "duration", d.String())
+				complete()
+			}
+		}()
+	}
+	if !step.Spec.IsBounded() && !step.Spec.Completion.GetDrained() {
+		return
+	}
+	logger.Info("starting drained loop", "updateInterval", updateInterval.String())
+	go untilWithFailureBackoff(ctx, func(ctx context.Context) error {
+		for sourceName, s := range sources {
+			if drained, err := isDrained(ctx, s); err != nil {
+				if !errors.Is(err, source.ErrPendingUnavailable) {
+					logger.Error(err, "failed to get drained", "source", sourceName)
+				}
+				return err
+			} else if !drained {
+				return nil
+			}
+		}
+		if n := atomic.LoadInt64(&inFlight); n > 0 {
+			logger.Info("sources drained, waiting for in-flight messages", "inFlight", n)
+			return nil
+		}
+		logger.Info("sources drained, completing")
+		complete()
+		return nil
+	}, updateInterval)
+}
+
+// isDrained returns true if a bounded source has processed the messages that existed when it started, or any other
+// source has no pending messages.
+func isDrained(ctx context.Context, s source.Interface) (bool, error) {
+	if x, ok := s.(source.Drainable); ok && step.Spec.IsBounded() {
+		return x.IsDrained(ctx)
+	}
+	if x, ok := s.(source.HasPending); ok {
+		pending, err := x.GetPending(ctx)
+		return pending == 0, err
+	}
+	return false, fmt.Errorf("source cannot be drained")
+}
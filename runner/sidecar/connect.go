@@ -0,0 +1,69 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// connectBackoff is how connecting to a source or sink's broker is retried at startup, for about 2m in total, so a
+// broker that is briefly unavailable does not make the sidecar crash loop.
+var connectBackoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 8, Cap: 30 * time.Second}
+
+// annotateConnecting sets the pod's connecting annotation, or removes it if the message is empty.
+var annotateConnecting = func(ctx context.Context, message string) error {
+	var value interface{} = message
+	if message == "" {
+		value = nil // removes the annotation
+	}
+	data := sharedutil.MustJSON(map[string]interface{}{
+		"metadata": map[string]interface{}{"annotations": map[string]interface{}{dfv1.KeyConnecting: value}},
+	})
+	_, err := kubernetesInterface.CoreV1().Pods(namespace).Patch(ctx, pod, types.MergePatchType, []byte(data), metav1.PatchOptions{})
+	return err
+}
+
+// connectWithRetry calls connect until it succeeds, backing off exponentially, and returns its last error once
+// connectBackoff's steps are exhausted. While it retries, it annotates its pod with the last error, so the controller can
+// report it in the step's status.
+func connectWithRetry(ctx context.Context, what string, connect func() error) error {
+	backoff := connectBackoff
+	annotated := false
+	// the annotation is only informative, so we do not fail if we cannot update it
+	annotate := func(message string) {
+		if err := annotateConnecting(ctx, message); err != nil {
+			logger.Error(err, "failed to annotate pod with connection retry")
+		} else {
+			annotated = message != ""
+		}
+	}
+	for attempt := 1; ; attempt++ {
+		err := connect()
+		if err == nil {
+			if annotated {
+				annotate("")
+			}
+			return nil
+		}
+		if attempt >= connectBackoff.Steps {
+			if annotated {
+				annotate("") // the sidecar exits, and the container's status reports the error
+			}
+			return fmt.Errorf("failed to connect %s after %d attempts: %w", what, attempt, err)
+		}
+		message := fmt.Sprintf("%s (attempt %d/%d): %v", what, attempt, connectBackoff.Steps, err)
+		logger.Info("failed to connect, retrying", "what", what, "attempt", attempt, "err", err.Error())
+		annotate(message)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to connect %s: %w", what, ctx.Err())
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+func Test_connectWithRetry(t *testing.T) {
+	ctx := context.Background()
+	defer func(b wait.Backoff, f func(context.Context, string) error) { connectBackoff, annotateConnecting = b, f }(connectBackoff, annotateConnecting)
+	connectBackoff = wait.Backoff{Duration: time.Millisecond, Factor: 2, Steps: 3}
+	var annotations []string
+	annotateConnecting = func(_ context.Context, message string) error {
+		annotations = append(annotations, message)
+		return nil
+	}
+	t.Run("Connected", func(t *testing.T) {
+		annotations = nil
+		assert.NoError(t, connectWithRetry(ctx, `source "in"`, func() error { return nil }))
+		assert.Empty(t, annotations)
+	})
+	t.Run("Retried", func(t *testing.T) {
+		annotations = nil
+		attempts := 0
+		assert.NoError(t, connectWithRetry(ctx, `source "in"`, func() error {
+			if attempts++; attempts < 2 {
+				return errors.New("connection refused")
+			}
+			return nil
+		}))
+		assert.Equal(t, []string{`source "in" (attempt 1/3): connection refused`, ""}, annotations)
+	})
+	t.Run("GaveUp", func(t *testing.T) {
+		annotations = nil
+		err := connectWithRetry(ctx, `source "in"`, func() error { return errors.New("connection refused") })
+		assert.EqualError(t, err, `failed to connect source "in" after 3 attempts: connection refused`)
+		assert.Len(t, annotations, 3)
+		assert.Empty(t, annotations[2])
+	})
+}
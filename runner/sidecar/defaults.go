@@ -0,0 +1,49 @@
+package sidecar
+
+import (
+	_ "embed"
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+//go:generate go run ../../hack/stepdefaults ../../config/crd/bases/dataflow.argoproj.io_steps.yaml step_defaults.json
+
+//go:embed step_defaults.json
+var stepDefaultsJSON []byte
+
+type defaultsSchema struct {
+	Default    interface{}                `json:"default,omitempty"`
+	Properties map[string]*defaultsSchema `json:"properties,omitempty"`
+	Items      *defaultsSchema            `json:"items,omitempty"`
+}
+
+// applyDefaults sets missing fields to their default values, in the same way as the API server does for the CRD.
+func applyDefaults(x interface{}, s *defaultsSchema) {
+	if s == nil {
+		return
+	}
+	switch x := x.(type) {
+	case map[string]interface{}:
+		for k, p := range s.Properties {
+			if _, ok := x[k]; !ok && p.Default != nil {
+				x[k] = runtime.DeepCopyJSONValue(p.Default)
+			}
+			if v, ok := x[k]; ok {
+				applyDefaults(v, p)
+			}
+		}
+	case []interface{}:
+		for _, v := range x {
+			applyDefaults(v, s.Items)
+		}
+	}
+}
+
+func stepDefaults() *defaultsSchema {
+	s := &defaultsSchema{}
+	if err := json.Unmarshal(stepDefaultsJSON, s); err != nil {
+		panic(err)
+	}
+	return s
+}
@@ -0,0 +1,38 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func elasticsearchFromSecret(x *dfv1.ElasticsearchSource, secret *corev1.Secret) {
+	x.URL = dfv1.StringOr(x.URL, string(secret.Data["url"]))
+	selector := func(key string) *corev1.SecretKeySelector {
+		if _, ok := secret.Data[key]; !ok {
+			return nil
+		}
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name}, Key: key}
+	}
+	if x.UsernameSecret == nil {
+		x.UsernameSecret = selector("username")
+	}
+	if x.PasswordSecret == nil {
+		x.PasswordSecret = selector("password")
+	}
+}
+
+func enrichElasticsearch(ctx context.Context, x *dfv1.ElasticsearchSource) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-elasticsearch-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		elasticsearchFromSecret(x, secret)
+	}
+	return nil
+}
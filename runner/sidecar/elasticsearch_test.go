@@ -0,0 +1,31 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_elasticsearchFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-elasticsearch-default"},
+		Data:       map[string][]byte{"url": []byte("http://elasticsearch:9200"), "password": []byte("my-password")},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.ElasticsearchSource{}
+		elasticsearchFromSecret(x, secret)
+		assert.Equal(t, "http://elasticsearch:9200", x.URL)
+		assert.Nil(t, x.UsernameSecret)
+		assert.Equal(t, &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-elasticsearch-default"}, Key: "password"}, x.PasswordSecret)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		passwordSecret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "my-key"}
+		x := &dfv1.ElasticsearchSource{URL: "http://my-elasticsearch:9200", PasswordSecret: passwordSecret}
+		elasticsearchFromSecret(x, secret)
+		assert.Equal(t, "http://my-elasticsearch:9200", x.URL)
+		assert.Equal(t, passwordSecret, x.PasswordSecret)
+	})
+}
@@ -0,0 +1,37 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// recordEvent records an event on the step, e.g. when a source resets its offsets.
+var recordEvent = func(eventType, reason, message string) {}
+
+// connectEvents starts recording events. They are recorded asynchronously, and may be lost if the sidecar stops.
+func connectEvents() {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubernetesInterface.CoreV1().Events(namespace)})
+	addStopHook(func(context.Context) error {
+		broadcaster.Shutdown()
+		return nil
+	})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: "dataflow-sidecar", Host: pod})
+	// the step is updated as it is watched, so we refer to it, rather than share it
+	ref := &corev1.ObjectReference{
+		APIVersion: dfv1.GroupVersion.String(),
+		Kind:       dfv1.StepGroupVersionKind.Kind,
+		Namespace:  step.Namespace,
+		Name:       step.Name,
+		UID:        step.UID,
+	}
+	recordEvent = func(eventType, reason, message string) {
+		logger.Info("recording event", "type", eventType, "reason", reason, "message", message)
+		recorder.Event(ref, eventType, reason, message)
+	}
+}
@@ -0,0 +1,35 @@
+package sidecar
+
+import (
+	"context"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/uber/jaeger-client-go"
+)
+
+// tracing is true if traces are reported, so exemplars can link to them
+var tracing = false
+
+// observe records the value, with the trace ID of the context's span as an exemplar, so a graph of the histogram can
+// link to an example trace. Exemplars are only recorded for sampled spans, as other traces are not reported.
+func observe(ctx context.Context, o prometheus.Observer, v float64) {
+	if x, ok := o.(prometheus.ExemplarObserver); ok && tracing {
+		if id := sampledTraceID(ctx); id != "" {
+			x.ObserveWithExemplar(v, prometheus.Labels{"traceID": id})
+			return
+		}
+	}
+	o.Observe(v)
+}
+
+func sampledTraceID(ctx context.Context) string {
+	span := opentracing.SpanFromContext(ctx)
+	if span == nil {
+		return ""
+	}
+	if x, ok := span.Context().(jaeger.SpanContext); ok && x.IsSampled() {
+		return x.TraceID().String()
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package sidecar
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opentracing/opentracing-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/uber/jaeger-client-go"
+)
+
+func Test_observe(t *testing.T) {
+	tracer, closer := jaeger.NewTracer("test", jaeger.NewConstSampler(true), jaeger.NewNullReporter())
+	defer func() { _ = closer.Close() }()
+	span := tracer.StartSpan("test")
+	ctx := opentracing.ContextWithSpan(context.Background(), span)
+	traceID := span.Context().(jaeger.SpanContext).TraceID().String()
+
+	r := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "input_message_time_seconds", Buckets: []float64{1}}, []string{"replica"})
+	r.MustRegister(histogram)
+	exemplar := func(replica string) string {
+		families, err := r.Gather()
+		assert.NoError(t, err)
+		for _, m := range families[0].Metric {
+			if m.Label[0].GetValue() == replica {
+				if e := m.GetHistogram().Bucket[0].GetExemplar(); e != nil {
+					return e.Label[0].GetValue()
+				}
+			}
+		}
+		return ""
+	}
+
+	t.Run("NotTracing", func(t *testing.T) {
+		observe(ctx, histogram.WithLabelValues("0"), 0.5)
+		assert.Empty(t, exemplar("0"))
+	})
+	tracing = true
+	defer func() { tracing = false }()
+	t.Run("NoSpan", func(t *testing.T) {
+		observe(context.Background(), histogram.WithLabelValues("1"), 0.5)
+		assert.Empty(t, exemplar("1"))
+	})
+	t.Run("Span", func(t *testing.T) {
+		observe(ctx, histogram.WithLabelValues("2"), 0.5)
+		assert.Equal(t, traceID, exemplar("2"))
+	})
+}
@@ -0,0 +1,136 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// faults are only injected if the controller allows it, so that an annotation cannot break a production pipeline
+	faultInjection    = os.Getenv(dfv1.EnvFaultInjection) == "true"
+	errInjectedFault  = errors.New("injected fault")
+	errDroppedAck     = errors.New("injected fault: dropped ack")
+	faultsCounter     *prometheus.CounterVec
+	faultsProbability = rand.Float64
+)
+
+// faults are the faults injected into the sidecar, each as the probability of it happening to a message.
+type faults struct {
+	// SinkError fails writes to the sinks, before the message is written.
+	SinkError float64
+	// Latency delays writes to the sinks by LatencyDuration.
+	Latency         float64
+	LatencyDuration time.Duration
+	// DropAck fails messages after they are processed, so the source does not acknowledge them.
+	DropAck float64
+}
+
+// parseFaults parses the value of the faults annotation, e.g. "sink-error=0.1,latency=0.5,latency-duration=2s".
+func parseFaults(text string) (faults, error) {
+	x := faults{LatencyDuration: time.Second}
+	for _, s := range strings.Split(text, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		parts := strings.SplitN(s, "=", 2)
+		if len(parts) != 2 {
+			return faults{}, fmt.Errorf("fault %q must be key=value", s)
+		}
+		key, value := parts[0], parts[1]
+		if key == "latency-duration" {
+			d, err := time.ParseDuration(value)
+			if err != nil {
+				return faults{}, fmt.Errorf("fault %q: %w", s, err)
+			}
+			x.LatencyDuration = d
+			continue
+		}
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil || p < 0 || p > 1 {
+			return faults{}, fmt.Errorf("fault %q must be a probability between 0 and 1", s)
+		}
+		switch key {
+		case "sink-error":
+			x.SinkError = p
+		case "latency":
+			x.Latency = p
+		case "drop-ack":
+			x.DropAck = p
+		default:
+			return faults{}, fmt.Errorf("unknown fault %q, must be one of sink-error, latency, latency-duration or drop-ack", key)
+		}
+	}
+	return x, nil
+}
+
+func connectFaults() {
+	if !faultInjection {
+		return
+	}
+	logger.Info("fault injection enabled")
+	faultsCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sidecar",
+		Name:      "faults_injected",
+		Help:      "Number of faults injected, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sidecar_faults_injected",
+	}, []string{"fault", "replica"})
+}
+
+// injectFault returns true, and counts the fault, with the probability p.
+func injectFault(fault string, p float64) bool {
+	if p <= 0 || faultsProbability() >= p {
+		return false
+	}
+	if faultsCounter != nil {
+		faultsCounter.WithLabelValues(fault, fmt.Sprint(replica)).Inc()
+	}
+	return true
+}
+
+// injectSinkFaults wraps the sink, so the live faults delay or fail writes to it.
+func injectSinkFaults(sink func(context.Context, []byte) error) func(context.Context, []byte) error {
+	if !faultInjection {
+		return sink
+	}
+	return func(ctx context.Context, msg []byte) error {
+		x := live.getFaults()
+		if injectFault("latency", x.Latency) {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(x.LatencyDuration):
+			}
+		}
+		if injectFault("sink-error", x.SinkError) {
+			return errInjectedFault
+		}
+		return sink(ctx, msg)
+	}
+}
+
+// injectAckFaults wraps a source's process func, so the live faults fail messages that were processed successfully.
+// The source does not acknowledge them, so they may be redelivered.
+func injectAckFaults(process func(context.Context, []byte) error) func(context.Context, []byte) error {
+	if !faultInjection {
+		return process
+	}
+	return func(ctx context.Context, msg []byte) error {
+		if err := process(ctx, msg); err != nil {
+			return err
+		}
+		if injectFault("drop-ack", live.getFaults().DropAck) {
+			return errDroppedAck
+		}
+		return nil
+	}
+}
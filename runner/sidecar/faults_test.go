@@ -0,0 +1,86 @@
+package sidecar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_parseFaults(t *testing.T) {
+	x, err := parseFaults("")
+	assert.NoError(t, err)
+	assert.Equal(t, faults{LatencyDuration: time.Second}, x)
+	x, err = parseFaults("sink-error=0.1, latency=0.5,latency-duration=2s,drop-ack=1")
+	assert.NoError(t, err)
+	assert.Equal(t, faults{SinkError: 0.1, Latency: 0.5, LatencyDuration: 2 * time.Second, DropAck: 1}, x)
+	_, err = parseFaults("sink-error")
+	assert.EqualError(t, err, `fault "sink-error" must be key=value`)
+	_, err = parseFaults("sink-error=2")
+	assert.EqualError(t, err, `fault "sink-error=2" must be a probability between 0 and 1`)
+	_, err = parseFaults("latency-duration=foo")
+	assert.Error(t, err)
+	_, err = parseFaults("foo=0.1")
+	assert.EqualError(t, err, `unknown fault "foo", must be one of sink-error, latency, latency-duration or drop-ack`)
+}
+
+func withFaults(t *testing.T, x faults, p float64) {
+	oldEnabled, oldProbability := faultInjection, faultsProbability
+	faultInjection, faultsProbability = true, func() float64 { return p }
+	live.faults = x
+	t.Cleanup(func() {
+		faultInjection, faultsProbability = oldEnabled, oldProbability
+		live.faults = faults{}
+	})
+}
+
+func Test_injectSinkFaults(t *testing.T) {
+	ctx := context.Background()
+	var msgs int
+	sink := func(context.Context, []byte) error {
+		msgs++
+		return nil
+	}
+	t.Run("Disabled", func(t *testing.T) {
+		live.faults = faults{SinkError: 1}
+		defer func() { live.faults = faults{} }()
+		assert.NoError(t, injectSinkFaults(sink)(ctx, nil))
+	})
+	t.Run("SinkError", func(t *testing.T) {
+		withFaults(t, faults{SinkError: 0.5}, 0.4)
+		msgs = 0
+		assert.Equal(t, errInjectedFault, injectSinkFaults(sink)(ctx, nil))
+		assert.Equal(t, 0, msgs)
+	})
+	t.Run("NotInjected", func(t *testing.T) {
+		withFaults(t, faults{SinkError: 0.5}, 0.6)
+		msgs = 0
+		assert.NoError(t, injectSinkFaults(sink)(ctx, nil))
+		assert.Equal(t, 1, msgs)
+	})
+	t.Run("Latency", func(t *testing.T) {
+		withFaults(t, faults{Latency: 1, LatencyDuration: 50 * time.Millisecond}, 0)
+		start := time.Now()
+		assert.NoError(t, injectSinkFaults(sink)(ctx, nil))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	})
+	t.Run("LatencyCancelled", func(t *testing.T) {
+		withFaults(t, faults{Latency: 1, LatencyDuration: time.Hour}, 0)
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.Equal(t, context.Canceled, injectSinkFaults(sink)(ctx, nil))
+	})
+}
+
+func Test_injectAckFaults(t *testing.T) {
+	ctx := context.Background()
+	var msgs int
+	process := func(context.Context, []byte) error {
+		msgs++
+		return nil
+	}
+	withFaults(t, faults{DropAck: 1}, 0)
+	assert.Equal(t, errDroppedAck, injectAckFaults(process)(ctx, nil))
+	assert.Equal(t, 1, msgs, "the message is processed")
+}
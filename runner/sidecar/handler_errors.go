@@ -0,0 +1,64 @@
+package sidecar
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	errorClassPermanent = "permanent"
+	errorClassRetryable = "retryable"
+	// the error message is a metric label, so we keep it short
+	maxErrorMessageLength = 256
+)
+
+// permanentError is an error returned by the main container that will recur if the message is retried.
+type permanentError struct{ error }
+
+func (e permanentError) Unwrap() error { return e.error }
+
+// permanentStatus returns true if an HTTP status means the main container rejected the message itself. Timeouts and
+// rate-limiting might succeed on retry.
+func permanentStatus(code int) bool {
+	return code >= 400 && code < 500 && code != http.StatusRequestTimeout && code != http.StatusTooManyRequests
+}
+
+func errorClass(err error) string {
+	if errors.As(err, &permanentError{}) {
+		return errorClassPermanent
+	}
+	return errorClassRetryable
+}
+
+// lastErrors records the most recent error of each source as a gauge, whose value is the time of the error, so the
+// controller can include it in the step's status.
+type lastErrors struct {
+	gauge  *prometheus.GaugeVec
+	mu     sync.Mutex
+	labels map[string]prometheus.Labels // by source name
+}
+
+func newLastErrors(gauge *prometheus.GaugeVec) *lastErrors {
+	return &lastErrors{gauge: gauge, labels: map[string]prometheus.Labels{}}
+}
+
+func (l *lastErrors) set(sourceName string, err error, now time.Time) {
+	msg := err.Error()
+	if len(msg) > maxErrorMessageLength {
+		msg = strings.ToValidUTF8(msg[:maxErrorMessageLength-3], "") + "..."
+	}
+	labels := prometheus.Labels{"sourceName": sourceName, "replica": fmt.Sprint(replica), "class": errorClass(err), "message": msg}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if old, ok := l.labels[sourceName]; ok {
+		l.gauge.Delete(old)
+	}
+	l.labels[sourceName] = labels
+	l.gauge.With(labels).Set(float64(now.UnixNano()) / float64(time.Second))
+}
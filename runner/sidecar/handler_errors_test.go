@@ -0,0 +1,38 @@
+package sidecar
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_permanentStatus(t *testing.T) {
+	assert.False(t, permanentStatus(201))
+	assert.True(t, permanentStatus(400))
+	assert.False(t, permanentStatus(408))
+	assert.False(t, permanentStatus(429))
+	assert.False(t, permanentStatus(500))
+}
+
+func Test_errorClass(t *testing.T) {
+	assert.Equal(t, errorClassRetryable, errorClass(errors.New("foo")))
+	assert.Equal(t, errorClassPermanent, errorClass(permanentError{errors.New("foo")}))
+	assert.Equal(t, errorClassPermanent, errorClass(fmt.Errorf("bar: %w", permanentError{errors.New("foo")})))
+}
+
+func Test_lastErrors(t *testing.T) {
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "last_error"}, []string{"sourceName", "replica", "class", "message"})
+	l := newLastErrors(gauge)
+	now := time.Unix(1630497600, 0)
+	l.set("a", errors.New("foo"), now)
+	l.set("b", errors.New("bar"), now)
+	l.set("a", permanentError{errors.New(strings.Repeat("x", 1000))}, now.Add(time.Second))
+	assert.Equal(t, 2, testutil.CollectAndCount(gauge), "only the most recent error of each source")
+	assert.Equal(t, float64(1630497601), testutil.ToFloat64(gauge.WithLabelValues("a", "0", errorClassPermanent, strings.Repeat("x", 253)+"...")))
+}
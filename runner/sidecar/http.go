@@ -0,0 +1,56 @@
+package sidecar
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func httpFromSecret(x *dfv1.HTTPSink, secret *corev1.Secret) {
+	specified := map[string]bool{}
+	for _, h := range x.Headers {
+		specified[http.CanonicalHeaderKey(h.Name)] = true
+	}
+	var keys []string
+	for k := range secret.Data {
+		if strings.HasPrefix(k, "headers.") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys) // deterministic order
+	for _, k := range keys {
+		name := strings.TrimPrefix(k, "headers.")
+		if name == "" || specified[http.CanonicalHeaderKey(name)] {
+			continue
+		}
+		x.Headers = append(x.Headers, dfv1.HTTPHeader{
+			Name: name,
+			ValueFrom: &dfv1.HTTPHeaderSource{
+				SecretKeyRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: secret.Name,
+					},
+					Key: k,
+				},
+			},
+		})
+	}
+}
+
+func enrichHTTP(ctx context.Context, x *dfv1.HTTPSink) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-http-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		httpFromSecret(x, secret)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package sidecar
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_httpFromSecret(t *testing.T) {
+	t.Run("Empty", func(t *testing.T) {
+		x := &dfv1.HTTPSink{}
+		httpFromSecret(x, &corev1.Secret{})
+		assert.Empty(t, x.Headers)
+	})
+	t.Run("Headers", func(t *testing.T) {
+		x := &dfv1.HTTPSink{Headers: []dfv1.HTTPHeader{{Name: "x-api-key", Value: "my-key"}}}
+		httpFromSecret(x, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "dataflow-http-default"},
+			Data: map[string][]byte{
+				"headers.Authorization": []byte("Bearer my-token"),
+				"headers.X-Api-Key":     []byte("other-key"),
+				"url":                   []byte("http://my-svc"),
+			},
+		})
+		if assert.Len(t, x.Headers, 2) {
+			assert.Equal(t, "my-key", x.Headers[0].Value)
+			h := x.Headers[1]
+			assert.Equal(t, "Authorization", h.Name)
+			if assert.NotNil(t, h.ValueFrom) {
+				assert.Equal(t, "dataflow-http-default", h.ValueFrom.SecretKeyRef.Name)
+				assert.Equal(t, "headers.Authorization", h.ValueFrom.SecretKeyRef.Key)
+			}
+		}
+	})
+}
+
+func Test_enrichHTTP(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		k := fake.NewSimpleClientset()
+		secretInterface = k.CoreV1().Secrets("")
+		x := &dfv1.HTTPSink{}
+		err := enrichHTTP(context.Background(), x)
+		assert.NoError(t, err)
+		assert.Empty(t, x.Headers)
+	})
+	t.Run("Found", func(t *testing.T) {
+		k := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "dataflow-http-foo",
+			},
+			Data: map[string][]byte{
+				"headers.Authorization": []byte("Bearer my-token"),
+			},
+		})
+		secretInterface = k.CoreV1().Secrets("")
+		x := &dfv1.HTTPSink{Name: "foo"}
+		err := enrichHTTP(context.Background(), x)
+		assert.NoError(t, err)
+		assert.Len(t, x.Headers, 1)
+	})
+}
@@ -25,6 +25,7 @@ var (
 		Transport: httpTransport,
 		Timeout:   10 * time.Second,
 	}
+	inURL = "http://127.0.0.1:8080" // the URL of the HTTP in interface
 )
 
 func init() {
@@ -47,6 +48,13 @@ func connectIn(ctx context.Context, sink func(context.Context, []byte) error) (f
 		ConstLabels: map[string]string{"replica": strconv.Itoa(replica)},
 	})
 	in := step.Spec.GetIn()
+	if !step.Spec.HasMainContainer() {
+		logger.Info("no main container, messages are sent directly to sinks")
+		return sink, nil
+	}
+	if err := waitPostStart(ctx); err != nil {
+		return nil, err
+	}
 	if in == nil {
 		logger.Info("no in interface configured")
 		return func(context.Context, []byte) error {
@@ -75,8 +83,9 @@ func connectIn(ctx context.Context, sink func(context.Context, []byte) error) (f
 			}
 			return nil
 		}, nil
-	} else if in.HTTP != nil {
-		logger.Info("HTTP in interface configured")
+	} else if in.HTTP != nil || in.Stdio {
+		inURL = fmt.Sprintf("http://127.0.0.1:%d", step.Spec.GetInPort())
+		logger.Info("HTTP in interface configured", "url", inURL, "stdio", in.Stdio)
 		if len(step.Spec.Sources) > 0 {
 			if err := waitReady(ctx); err != nil {
 				return nil, err
@@ -91,8 +100,8 @@ func connectIn(ctx context.Context, sink func(context.Context, []byte) error) (f
 			inFlight.Inc()
 			defer inFlight.Dec()
 			start := time.Now()
-			defer func() { messageTimeSeconds.Observe(time.Since(start).Seconds()) }()
-			req, err := http.NewRequestWithContext(ctx, "POST", "http://127.0.0.1:8080/messages", bytes.NewBuffer(data))
+			defer func() { observe(ctx, messageTimeSeconds, time.Since(start).Seconds()) }()
+			req, err := http.NewRequestWithContext(ctx, "POST", inURL+"/messages", bytes.NewBuffer(data))
 			if err != nil {
 				return err
 			}
@@ -108,7 +117,11 @@ func connectIn(ctx context.Context, sink func(context.Context, []byte) error) (f
 				body, _ := ioutil.ReadAll(resp.Body)
 				_ = resp.Body.Close()
 				if resp.StatusCode >= 300 {
-					return fmt.Errorf("HTTP request failed: %q %q", resp.Status, body)
+					err := fmt.Errorf("HTTP request failed: %q %q", resp.Status, body)
+					if permanentStatus(resp.StatusCode) {
+						return permanentError{err}
+					}
+					return err
 				}
 				if resp.StatusCode == 201 {
 					return sink(ctx, body)
@@ -136,7 +149,7 @@ func waitReady(ctx context.Context) error {
 				}
 			}
 			logger.Info("waiting for HTTP in interface to be ready")
-			if resp, err := httpClient.Get("http://127.0.0.1:8080/ready"); err == nil {
+			if resp, err := httpClient.Get(inURL + "/ready"); err == nil {
 				_, _ = io.Copy(io.Discard, resp.Body)
 				_ = resp.Body.Close()
 				if resp.StatusCode < 300 {
@@ -150,13 +163,16 @@ func waitReady(ctx context.Context) error {
 }
 
 func waitUnready(ctx context.Context) error {
+	if isCertificatesChanged() {
+		return nil // only the sidecar is restarting, so the main container stays ready
+	}
 	for {
 		select {
 		case <-ctx.Done():
 			return fmt.Errorf("failed to wait for un-ready: %w", ctx.Err())
 		default:
 			logger.Info("waiting for HTTP in interface to be unready")
-			if resp, err := httpClient.Get("http://127.0.0.1:8080/ready"); err != nil {
+			if resp, err := httpClient.Get(inURL + "/ready"); err != nil {
 				logger.Info("HTTP in interface unready")
 				return nil
 			} else {
@@ -0,0 +1,140 @@
+package sidecar
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/google/uuid"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type injectedKey struct{}
+
+// injected records the messages written to the sinks while processing an injected message.
+type injected struct {
+	mu   sync.Mutex
+	msgs [][]byte
+}
+
+// recordInjected wraps the sink, so messages written while processing an injected message are returned to the caller.
+// Only messages written in the same request are recorded, i.e. not those written asynchronously by the main container.
+func recordInjected(sink func(context.Context, []byte) error) func(context.Context, []byte) error {
+	return func(ctx context.Context, msg []byte) error {
+		if err := sink(ctx, msg); err != nil {
+			return err
+		}
+		if x, ok := ctx.Value(injectedKey{}).(*injected); ok {
+			x.mu.Lock()
+			x.msgs = append(x.msgs, append([]byte(nil), msg...))
+			x.mu.Unlock()
+		}
+		return nil
+	}
+}
+
+// sourceAuthorizations returns the authorization each source's requests must present, from the step's secret, or nil
+// if the step does not have a secret. Steps without sources or test sinks do not have one.
+func sourceAuthorizations(ctx context.Context) (map[string]string, error) {
+	if len(step.GetAuthorizationKeys()) == 0 {
+		return nil, nil
+	}
+	secretName := pipelineName + "-" + stepName
+	secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q: %w", secretName, err)
+	}
+	authorizations := map[string]string{} // source name -> authorization
+	for _, s := range step.Spec.Sources {
+		authorizations[s.Name] = string(secret.Data[fmt.Sprintf("sources.%s.http.authorization", s.Name)])
+	}
+	return authorizations, nil
+}
+
+// authorizeSource returns the name of the source in the request's query, if the request is a POST that presents the
+// source's authorization. Otherwise, it writes the error response, and returns false.
+func authorizeSource(w http.ResponseWriter, r *http.Request, authorizations map[string]string) (string, bool) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(405)
+		return "", false
+	}
+	sourceName := r.URL.Query().Get("source")
+	authorization, ok := authorizations[sourceName]
+	if !ok {
+		w.WriteHeader(404)
+		_, _ = w.Write([]byte(fmt.Sprintf("source %q not found", sourceName)))
+		return "", false
+	}
+	if authorization == "" || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte(authorization)) != 1 {
+		w.WriteHeader(403)
+		return "", false
+	}
+	return sourceName, true
+}
+
+// connectInject adds the /inject endpoint, which sends a message to main and then the sinks, as if it came from the
+// source. Requests must present the source's bearer token.
+func connectInject(ctx context.Context, process func(context.Context, []byte) error) error {
+	authorizations, err := sourceAuthorizations(ctx)
+	if err != nil {
+		return err
+	} else if authorizations == nil {
+		logger.Info("step secret not found, not enabling injection")
+		return nil
+	}
+	sourceURNs := map[string]string{}
+	for _, s := range step.Spec.Sources {
+		sourceURNs[s.Name] = s.GenURN(cluster, namespace)
+	}
+	http.HandleFunc("/inject", func(w http.ResponseWriter, r *http.Request) {
+		sourceName, ok := authorizeSource(w, r, authorizations)
+		if !ok {
+			return
+		}
+		if !ready {
+			w.WriteHeader(503)
+			_, _ = w.Write([]byte("not ready"))
+			return
+		}
+		msg, err := ioutil.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		x := &injected{}
+		ctx, cancel := context.WithTimeout(context.WithValue(r.Context(), injectedKey{}, x), 15*time.Second)
+		defer cancel()
+		logger.Info("injecting message", "source", sourceName)
+		if err := process(dfv1.ContextWithMeta(ctx, dfv1.Meta{
+			Source: sourceURNs[sourceName],
+			ID:     "inject-" + uuid.New().String(),
+			Time:   time.Now().Unix(),
+		}), msg); err != nil {
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		x.mu.Lock()
+		defer x.mu.Unlock()
+		if len(x.msgs) == 0 {
+			w.WriteHeader(204)
+			return
+		}
+		w.WriteHeader(200)
+		for _, m := range x.msgs {
+			_, _ = w.Write(m)
+			_, _ = w.Write([]byte("\n"))
+		}
+	})
+	return nil
+}
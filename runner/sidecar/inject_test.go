@@ -0,0 +1,107 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func Test_connectInject(t *testing.T) {
+	http.DefaultServeMux = http.NewServeMux()
+	pipelineName = "my-pl"
+	stepName = "my-step"
+	namespace = "my-ns"
+	cluster = "my-cluster"
+	step = dfv1.Step{Spec: dfv1.StepSpec{Sources: []dfv1.Source{
+		{Name: "my-source", Cron: &dfv1.Cron{Schedule: "* * * * *"}},
+		{Name: "new-source", Cron: &dfv1.Cron{Schedule: "* * * * *"}},
+	}}}
+	secretInterface = fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "my-pl-my-step"},
+		Data:       map[string][]byte{"sources.my-source.http.authorization": []byte("Bearer my-token")},
+	}).CoreV1().Secrets(namespace)
+	ready = true
+	defer func() { ready = false }()
+
+	sink := recordInjected(func(ctx context.Context, msg []byte) error {
+		if string(msg) == "error" {
+			return fmt.Errorf("failed")
+		}
+		return nil
+	})
+	assert.NoError(t, connectInject(context.Background(), func(ctx context.Context, msg []byte) error {
+		meta, err := dfv1.MetaFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		if meta.Source != "urn:dataflow:cron:* * * * *" {
+			return fmt.Errorf("unexpected source %q", meta.Source)
+		}
+		if string(msg) == "filtered" {
+			return nil
+		}
+		return sink(ctx, msg)
+	}))
+
+	inject := func(source, authorization, msg string) (int, string) {
+		r := httptest.NewRequest("POST", "/inject?source="+source, bytes.NewBufferString(msg))
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(w, r)
+		return w.Code, w.Body.String()
+	}
+	t.Run("OK", func(t *testing.T) {
+		code, body := inject("my-source", "Bearer my-token", "my-msg")
+		assert.Equal(t, 200, code)
+		assert.Equal(t, "my-msg\n", body)
+	})
+	t.Run("Filtered", func(t *testing.T) {
+		code, _ := inject("my-source", "Bearer my-token", "filtered")
+		assert.Equal(t, 204, code)
+	})
+	t.Run("Error", func(t *testing.T) {
+		code, body := inject("my-source", "Bearer my-token", "error")
+		assert.Equal(t, 500, code)
+		assert.Equal(t, "failed", body)
+	})
+	t.Run("Forbidden", func(t *testing.T) {
+		code, _ := inject("my-source", "Bearer not-my-token", "my-msg")
+		assert.Equal(t, 403, code)
+	})
+	t.Run("NoAuthorization", func(t *testing.T) {
+		code, _ := inject("new-source", "", "my-msg")
+		assert.Equal(t, 403, code)
+	})
+	t.Run("NotFound", func(t *testing.T) {
+		code, _ := inject("not-found", "Bearer my-token", "my-msg")
+		assert.Equal(t, 404, code)
+	})
+}
+
+func Test_sourceAuthorizations(t *testing.T) {
+	pipelineName = "my-pl"
+	stepName = "my-step"
+	step = dfv1.Step{}
+	authorizations, err := sourceAuthorizations(context.Background())
+	assert.NoError(t, err)
+	assert.Nil(t, authorizations, "a step without sources does not have a secret")
+	step = dfv1.Step{Spec: dfv1.StepSpec{Sources: []dfv1.Source{{Name: "my-source"}}}}
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("get", "secrets", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, fmt.Errorf("forbidden")
+	})
+	secretInterface = clientset.CoreV1().Secrets("my-ns")
+	_, err = sourceAuthorizations(context.Background())
+	assert.EqualError(t, err, `failed to get secret "my-pl-my-step": forbidden`)
+}
@@ -0,0 +1,188 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// joiner correlates messages from the join's left and right sources by key. Each message either matches the oldest
+// waiting message with the same key from the other source, or waits for a match until the window elapses. Waiting
+// messages are kept in the state store, under "{sourceName}/{key}", so they may survive restarts.
+type joiner struct {
+	spec  dfv1.Join
+	key   *vm.Program
+	store state.Store
+	send  func(context.Context, []byte) error // sends merged messages to the sinks
+	dlq   func(context.Context, []byte) error
+	mu    sync.Mutex
+}
+
+type joinMessage struct {
+	Meta      dfv1.Meta `json:"meta"`
+	Msg       []byte    `json:"msg"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+func newJoiner(x dfv1.Join, store state.Store, send, dlq func(context.Context, []byte) error) (*joiner, error) {
+	prog, err := expr.Compile(x.Key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %q: %w", x.Key, err)
+	}
+	return &joiner{spec: x, key: prog, store: store, send: send, dlq: dlq}, nil
+}
+
+// process matches the message from the source, and sends the merged message if there is a match.
+func (j *joiner) process(ctx context.Context, sourceName string, msg []byte) error {
+	other := j.spec.Left
+	if sourceName == j.spec.Left {
+		other = j.spec.Right
+	} else if sourceName != j.spec.Right {
+		return permanentError{fmt.Errorf("source %q is not joined", sourceName)}
+	}
+	env, err := util.ExprEnv(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to create expr env: %w", err)
+	}
+	res, err := expr.Run(j.key, env)
+	if err != nil {
+		return permanentError{fmt.Errorf("failed to run key expression: %w", err)}
+	}
+	key, ok := res.(string)
+	if !ok {
+		return permanentError{fmt.Errorf("key expression must return a string, got %T", res)}
+	}
+	meta, err := dfv1.MetaFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	waiting, err := j.get(ctx, other, key)
+	if err != nil {
+		return err
+	}
+	if len(waiting) == 0 {
+		mine, err := j.get(ctx, sourceName, key)
+		if err != nil {
+			return err
+		}
+		return j.put(ctx, sourceName, key, append(mine, joinMessage{Meta: meta, Msg: msg, ExpiresAt: time.Now().Add(j.spec.GetWindow())}))
+	}
+	left, right := waiting[0].Msg, msg
+	if sourceName == j.spec.Left {
+		left, right = right, left
+	}
+	// the match is only removed once it is sent, so it can match this message when it is retried
+	if err := j.send(ctx, joined(key, left, right)); err != nil {
+		return err
+	}
+	return j.put(ctx, other, key, waiting[1:])
+}
+
+// get returns the messages waiting from the source with the key, oldest first. The caller must hold the lock.
+func (j *joiner) get(ctx context.Context, sourceName, key string) ([]joinMessage, error) {
+	data, err := j.store.Get(ctx, sourceName+"/"+key)
+	if err != nil || data == nil {
+		return nil, err
+	}
+	var msgs []joinMessage
+	if err := json.Unmarshal(data, &msgs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal waiting messages: %w", err)
+	}
+	return msgs, nil
+}
+
+// put sets the messages waiting from the source with the key. The caller must hold the lock.
+func (j *joiner) put(ctx context.Context, sourceName, key string, msgs []joinMessage) error {
+	if len(msgs) == 0 {
+		return j.store.Delete(ctx, sourceName+"/"+key)
+	}
+	data, err := json.Marshal(msgs)
+	if err != nil {
+		return err
+	}
+	return j.store.Set(ctx, sourceName+"/"+key, data)
+}
+
+// expire removes messages that have waited longer than the window, and handles them as the join specifies.
+func (j *joiner) expire(ctx context.Context, now time.Time) {
+	type expired struct {
+		sourceName, key string
+		joinMessage
+	}
+	var msgs []expired
+	j.mu.Lock()
+	for _, sourceName := range []string{j.spec.Left, j.spec.Right} {
+		keys, err := j.store.Keys(ctx, sourceName+"/")
+		if err != nil {
+			logger.Error(err, "failed to list waiting messages", "source", sourceName)
+			continue
+		}
+		for _, k := range keys {
+			key := strings.TrimPrefix(k, sourceName+"/")
+			waiting, err := j.get(ctx, sourceName, key)
+			if err != nil {
+				logger.Error(err, "failed to get waiting messages", "source", sourceName, "key", key)
+				continue
+			}
+			i := 0
+			for ; i < len(waiting) && !waiting[i].ExpiresAt.After(now); i++ {
+				msgs = append(msgs, expired{sourceName, key, waiting[i]})
+			}
+			if i == 0 {
+				continue
+			}
+			if err := j.put(ctx, sourceName, key, waiting[i:]); err != nil {
+				logger.Error(err, "failed to remove expired messages", "source", sourceName, "key", key)
+			}
+		}
+	}
+	j.mu.Unlock()
+	for _, x := range msgs {
+		ctx := dfv1.ContextWithMeta(ctx, x.Meta)
+		var err error
+		switch j.spec.GetUnmatched() {
+		case dfv1.JoinUnmatchedEmit:
+			if x.sourceName == j.spec.Left {
+				err = j.send(ctx, joined(x.key, x.Msg, nil))
+			} else {
+				err = j.send(ctx, joined(x.key, nil, x.Msg))
+			}
+		case dfv1.JoinUnmatchedDeadLetterQueue:
+			err = j.dlq(ctx, x.Msg)
+		}
+		if err != nil {
+			logger.Error(err, "failed to handle unmatched message", "source", x.sourceName, "key", x.key, "unmatched", j.spec.GetUnmatched())
+		}
+	}
+}
+
+func (j *joiner) run(ctx context.Context) {
+	go wait.UntilWithContext(ctx, func(ctx context.Context) { j.expire(ctx, time.Now()) }, time.Second)
+}
+
+// joined returns the merged message. A nil message is a missing side.
+func joined(key string, left, right []byte) []byte {
+	data, _ := json.Marshal(map[string]interface{}{"key": key, "left": joinValue(left), "right": joinValue(right)})
+	return data
+}
+
+func joinValue(msg []byte) interface{} {
+	if msg == nil {
+		return nil
+	} else if json.Valid(msg) {
+		return json.RawMessage(msg)
+	}
+	return string(msg)
+}
@@ -0,0 +1,108 @@
+package sidecar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_joiner(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id", Time: 0})
+	newTestJoiner := func(t *testing.T, unmatched dfv1.JoinUnmatched) (*joiner, *[]string, *[]string) {
+		var sent, dlq []string
+		j, err := newJoiner(dfv1.Join{Left: "orders", Right: "payments", Key: "string(object(msg).id)", Unmatched: unmatched}, state.NewMemory(ctx, time.Hour),
+			func(ctx context.Context, msg []byte) error {
+				sent = append(sent, string(msg))
+				return nil
+			},
+			func(ctx context.Context, msg []byte) error {
+				dlq = append(dlq, string(msg))
+				return nil
+			})
+		assert.NoError(t, err)
+		return j, &sent, &dlq
+	}
+	t.Run("Match", func(t *testing.T) {
+		j, sent, _ := newTestJoiner(t, "")
+		assert.NoError(t, j.process(ctx, "payments", []byte(`{"id":"1","amount":2}`)))
+		assert.Empty(t, *sent)
+		assert.NoError(t, j.process(ctx, "orders", []byte(`{"id":"1"}`)))
+		assert.Equal(t, []string{`{"key":"1","left":{"id":"1"},"right":{"id":"1","amount":2}}`}, *sent)
+		assert.Empty(t, waiting(t, j, "payments", "1"))
+	})
+	t.Run("OldestFirst", func(t *testing.T) {
+		j, sent, _ := newTestJoiner(t, "")
+		assert.NoError(t, j.process(ctx, "orders", []byte(`{"id":"1","n":1}`)))
+		assert.NoError(t, j.process(ctx, "orders", []byte(`{"id":"1","n":2}`)))
+		assert.NoError(t, j.process(ctx, "payments", []byte(`{"id":"1"}`)))
+		assert.Equal(t, []string{`{"key":"1","left":{"id":"1","n":1},"right":{"id":"1"}}`}, *sent)
+		assert.Len(t, waiting(t, j, "orders", "1"), 1)
+	})
+	t.Run("NotJoined", func(t *testing.T) {
+		j, _, _ := newTestJoiner(t, "")
+		err := j.process(ctx, "other", []byte(`{"id":"1"}`))
+		assert.EqualError(t, err, `source "other" is not joined`)
+		assert.Equal(t, errorClassPermanent, errorClass(err))
+	})
+	t.Run("KeyNotString", func(t *testing.T) {
+		j, err := newJoiner(dfv1.Join{Left: "orders", Right: "payments", Key: "object(msg).id"}, state.NewMemory(ctx, time.Hour), nil, nil)
+		assert.NoError(t, err)
+		err = j.process(ctx, "orders", []byte(`{"id":1}`))
+		assert.EqualError(t, err, "key expression must return a string, got float64")
+	})
+	t.Run("SendFailed", func(t *testing.T) {
+		j, err := newJoiner(dfv1.Join{Left: "orders", Right: "payments", Key: "string(msg)"}, state.NewMemory(ctx, time.Hour),
+			func(ctx context.Context, msg []byte) error { return errors.New("failed") }, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, j.process(ctx, "orders", []byte("1")))
+		assert.EqualError(t, j.process(ctx, "payments", []byte("1")), "failed")
+		assert.Len(t, waiting(t, j, "orders", "1"), 1, "the match waits for the message to be retried")
+	})
+	t.Run("SurvivesRestart", func(t *testing.T) {
+		store := state.NewMemory(ctx, time.Hour)
+		j, _, _ := newTestJoiner(t, "")
+		j.store = store
+		assert.NoError(t, j.process(ctx, "orders", []byte(`{"id":"1"}`)))
+		j, sent, _ := newTestJoiner(t, "")
+		j.store = store
+		assert.NoError(t, j.process(ctx, "payments", []byte(`{"id":"1"}`)))
+		assert.Equal(t, []string{`{"key":"1","left":{"id":"1"},"right":{"id":"1"}}`}, *sent)
+	})
+	t.Run("Drop", func(t *testing.T) {
+		j, sent, dlq := newTestJoiner(t, dfv1.JoinUnmatchedDrop)
+		assert.NoError(t, j.process(ctx, "orders", []byte(`{"id":"1"}`)))
+		j.expire(ctx, time.Now())
+		assert.Len(t, waiting(t, j, "orders", "1"), 1, "not expired yet")
+		j.expire(ctx, time.Now().Add(time.Minute))
+		assert.Empty(t, waiting(t, j, "orders", "1"))
+		assert.Empty(t, *sent)
+		assert.Empty(t, *dlq)
+	})
+	t.Run("Emit", func(t *testing.T) {
+		j, sent, _ := newTestJoiner(t, dfv1.JoinUnmatchedEmit)
+		assert.NoError(t, j.process(ctx, "payments", []byte(`{"id":"1"}`)))
+		j.expire(ctx, time.Now().Add(time.Minute))
+		assert.Equal(t, []string{`{"key":"1","left":null,"right":{"id":"1"}}`}, *sent)
+	})
+	t.Run("DeadLetterQueue", func(t *testing.T) {
+		j, _, dlq := newTestJoiner(t, dfv1.JoinUnmatchedDeadLetterQueue)
+		assert.NoError(t, j.process(ctx, "orders", []byte(`{"id":"1"}`)))
+		j.expire(ctx, time.Now().Add(time.Minute))
+		assert.Equal(t, []string{`{"id":"1"}`}, *dlq)
+	})
+}
+
+func waiting(t *testing.T, j *joiner, sourceName, key string) []joinMessage {
+	msgs, err := j.get(context.Background(), sourceName, key)
+	assert.NoError(t, err)
+	return msgs
+}
+
+func Test_joined(t *testing.T) {
+	assert.Equal(t, `{"key":"k","left":"not json","right":null}`, string(joined("k", []byte("not json"), nil)))
+}
@@ -2,14 +2,70 @@ package sidecar
 
 import (
 	"context"
+	"fmt"
 	"strings"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedkafka "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/kafka"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// transactionalProducer is shared by the step's transactional Kafka source and its sinks, or nil if the step does
+// not have a transactional source.
+var transactionalProducer *kafka.Producer
+
+func connectTransactionalProducer(ctx context.Context) error {
+	source, err := step.Spec.GetTransactionalSource()
+	if err != nil || source == nil {
+		return err
+	}
+	x := source.Kafka
+	groupID := x.GetGroupID(sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, source.Name))
+	logger.Info("connecting transactional producer", "source", source.Name)
+	if transactionalProducer, err = sharedkafka.NewTransactionalProducer(ctx, secretInterface, x.KafkaConfig, x.GetTransactionalID(groupID, replica)); err != nil {
+		return fmt.Errorf("failed to connect transactional producer for source %q: %w", source.Name, err)
+	}
+	addStopHook(func(context.Context) error {
+		logger.Info("closing transactional producer")
+		transactionalProducer.Close()
+		return nil
+	})
+	return nil
+}
+
+// createKafkaTopics creates the topics of the step's Kafka sources and sinks that have createTopic, if they do not
+// exist. Topic patterns are not created.
+func createKafkaTopics(ctx context.Context) error {
+	var kafkas []dfv1.Kafka
+	for _, s := range step.Spec.Sources {
+		if x := s.Kafka; x != nil && x.CreateTopic != nil {
+			for _, topic := range x.GetTopics() {
+				if !dfv1.IsTopicPattern(topic) {
+					k := x.Kafka
+					k.Topic = topic
+					kafkas = append(kafkas, k)
+				}
+			}
+		}
+	}
+	for _, s := range step.Spec.Sinks {
+		if x := s.Kafka; x != nil && x.CreateTopic != nil {
+			kafkas = append(kafkas, x.Kafka)
+		}
+	}
+	for _, k := range kafkas {
+		logger.Info("creating Kafka topic", "topic", k.Topic)
+		if err := sharedkafka.CreateTopic(ctx, secretInterface, k.KafkaConfig, k.Topic, *k.CreateTopic); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func kafkaFromSecret(k *dfv1.Kafka, secret *corev1.Secret) error {
 	k.Brokers = dfv1.StringsOr(k.Brokers, strings.Split(string(secret.Data["brokers"]), ","))
 
@@ -107,5 +163,37 @@ func enrichKafka(ctx context.Context, x *dfv1.Kafka) error {
 	} else if err := kafkaFromSecret(x, secret); err != nil {
 		return err
 	}
+	if s := x.Strimzi; s != nil {
+		kafkaFromStrimzi(x, *s, step.GetStrimziUserName(s.Cluster))
+	}
+	if e := x.EventHubs; e != nil {
+		kafkaFromEventHubs(x, *e)
+	}
 	return nil
 }
+
+// kafkaFromStrimzi connects to the cluster's TLS listener as the step's KafkaUser, unless the brokers or network
+// configuration are specified.
+func kafkaFromStrimzi(k *dfv1.Kafka, s dfv1.Strimzi, userName string) {
+	k.Brokers = dfv1.StringsOr(k.Brokers, []string{s.GetBootstrapServer()})
+	if k.NET == nil {
+		k.NET = &dfv1.KafkaNET{TLS: &dfv1.TLS{
+			CACertSecret: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: s.GetCACertSecretName()}, Key: "ca.crt"},
+			CertSecret:   &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: userName}, Key: "user.crt"},
+			KeySecret:    &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: userName}, Key: "user.key"},
+		}}
+	}
+}
+
+// kafkaFromEventHubs connects to the namespace's Kafka endpoint, authenticating with the connection string, unless the
+// brokers or network configuration are specified.
+func kafkaFromEventHubs(k *dfv1.Kafka, e dfv1.EventHubs) {
+	k.Brokers = dfv1.StringsOr(k.Brokers, []string{e.GetBootstrapServer()})
+	if k.NET == nil {
+		connectionString := e.ConnectionStringSecret
+		k.NET = &dfv1.KafkaNET{
+			TLS:  &dfv1.TLS{},
+			SASL: &dfv1.SASL{Mechanism: dfv1.PLAIN, Username: "$ConnectionString", PasswordSecret: &connectionString},
+		}
+	}
+}
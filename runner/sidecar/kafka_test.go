@@ -76,3 +76,49 @@ func Test_enrichKafka(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func Test_kafkaFromStrimzi(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		x := &dfv1.Kafka{}
+		kafkaFromStrimzi(x, dfv1.Strimzi{Cluster: "my-cluster"}, "my-user")
+		assert.Equal(t, []string{"my-cluster-kafka-bootstrap:9093"}, x.Brokers)
+		if assert.NotNil(t, x.NET) && assert.NotNil(t, x.NET.TLS) {
+			assert.Equal(t, "my-cluster-cluster-ca-cert", x.NET.TLS.CACertSecret.Name)
+			assert.Equal(t, "ca.crt", x.NET.TLS.CACertSecret.Key)
+			assert.Equal(t, "my-user", x.NET.TLS.CertSecret.Name)
+			assert.Equal(t, "user.crt", x.NET.TLS.CertSecret.Key)
+			assert.Equal(t, "my-user", x.NET.TLS.KeySecret.Name)
+			assert.Equal(t, "user.key", x.NET.TLS.KeySecret.Key)
+		}
+	})
+	t.Run("Specified", func(t *testing.T) {
+		x := &dfv1.Kafka{KafkaConfig: dfv1.KafkaConfig{Brokers: []string{"my-broker:9092"}, NET: &dfv1.KafkaNET{}}}
+		kafkaFromStrimzi(x, dfv1.Strimzi{Cluster: "my-cluster"}, "my-user")
+		assert.Equal(t, []string{"my-broker:9092"}, x.Brokers)
+		assert.Nil(t, x.NET.TLS)
+	})
+}
+
+func Test_kafkaFromEventHubs(t *testing.T) {
+	e := dfv1.EventHubs{
+		Namespace:              "my-namespace",
+		ConnectionStringSecret: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "connectionString"},
+	}
+	t.Run("Default", func(t *testing.T) {
+		x := &dfv1.Kafka{}
+		kafkaFromEventHubs(x, e)
+		assert.Equal(t, []string{"my-namespace.servicebus.windows.net:9093"}, x.Brokers)
+		if assert.NotNil(t, x.NET) && assert.NotNil(t, x.NET.SASL) {
+			assert.Equal(t, "sasl_ssl", x.NET.GetSecurityProtocol())
+			assert.Equal(t, dfv1.PLAIN, x.NET.SASL.Mechanism)
+			assert.Equal(t, "$ConnectionString", x.NET.SASL.Username)
+			assert.Equal(t, &e.ConnectionStringSecret, x.NET.SASL.PasswordSecret)
+		}
+	})
+	t.Run("Specified", func(t *testing.T) {
+		x := &dfv1.Kafka{KafkaConfig: dfv1.KafkaConfig{Brokers: []string{"my-broker:9092"}, NET: &dfv1.KafkaNET{}}}
+		kafkaFromEventHubs(x, e)
+		assert.Equal(t, []string{"my-broker:9092"}, x.Brokers)
+		assert.Nil(t, x.NET.SASL)
+	})
+}
@@ -0,0 +1,33 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func kinesisFromSecret(x *dfv1.KinesisSource, secret *corev1.Secret) {
+	x.Region = dfv1.StringOr(x.Region, string(secret.Data["region"]))
+	// without credentials, we use workload identity
+	if x.Credentials == nil {
+		x.Credentials = awsCredentialsFromSecret(secret)
+	}
+	if v, ok := secret.Data["endpoint.url"]; ok && x.Endpoint == nil {
+		x.Endpoint = &dfv1.AWSEndpoint{URL: string(v)}
+	}
+}
+
+func enrichKinesis(ctx context.Context, x *dfv1.KinesisSource) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-kinesis-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		kinesisFromSecret(x, secret)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_kinesisFromSecret(t *testing.T) {
+	x := &dfv1.KinesisSource{Stream: "my-stream"}
+	kinesisFromSecret(x, &corev1.Secret{Data: map[string][]byte{"region": []byte("us-west-2"), "endpoint.url": []byte("http://localstack:4566")}})
+	assert.Equal(t, "us-west-2", x.Region)
+	assert.Nil(t, x.Credentials, "without credentials, we use workload identity")
+	assert.Equal(t, &dfv1.AWSEndpoint{URL: "http://localstack:4566"}, x.Endpoint)
+}
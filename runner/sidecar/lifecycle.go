@@ -2,9 +2,12 @@ package sidecar
 
 import (
 	"context"
+	"fmt"
+	"os"
 	"sync"
 	"time"
 
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 )
 
@@ -14,6 +17,9 @@ var (
 	preStopHooks []hook // should be closed before main container exits
 	stopHooks    []hook // should be close after the main container exits
 	preStopMu    = sync.Mutex{}
+	// closed once the main container's pre-stop hook has run the step's lifecycle.preStop command
+	mainPreStopDone     = make(chan struct{})
+	mainPreStopDoneOnce = sync.Once{}
 )
 
 func addPreStopHook(x hook) {
@@ -33,6 +39,45 @@ func preStop(source string) {
 	logger.Info("pre-stop done", "source", source)
 }
 
+// waitMainPreStop waits for the main container's pre-stop hook to run the step's lifecycle.preStop command, if it has
+// one, so the sidecar is not stopped while the command is flushing messages to it.
+func waitMainPreStop(ctx context.Context) {
+	if len(step.Spec.Lifecycle.GetPreStop()) == 0 {
+		return
+	}
+	logger.Info("waiting for the main container's pre-stop command")
+	select {
+	case <-ctx.Done():
+		logger.Info("stopped waiting for the main container's pre-stop command", "error", ctx.Err().Error())
+	case <-mainPreStopDone:
+		logger.Info("main container's pre-stop command done")
+	}
+}
+
+func mainPreStopCompleted() {
+	mainPreStopDoneOnce.Do(func() { close(mainPreStopDone) })
+}
+
+// waitPostStart waits for the main container's post-start hook to run the step's lifecycle.postStart command, if it
+// has one, e.g. so messages are not sent to the main container until it has warmed up.
+func waitPostStart(ctx context.Context) error {
+	if len(step.Spec.Lifecycle.GetPostStart()) == 0 {
+		return nil
+	}
+	for {
+		if _, err := os.Stat(dfv1.PathPostStarted); err == nil {
+			logger.Info("main container's post-start command done")
+			return nil
+		}
+		logger.Info("waiting for the main container's post-start command")
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("failed to wait for post-start: %w", ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
 func stop() {
 	logger.Info("stop")
 	runHooks(stopHooks)
@@ -0,0 +1,29 @@
+package sidecar
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_waitPostStart(t *testing.T) {
+	step = dfv1.Step{}
+	assert.NoError(t, waitPostStart(context.Background()))
+	step = dfv1.Step{Spec: dfv1.StepSpec{Lifecycle: &dfv1.Lifecycle{PostStart: []string{"warm-up"}}}}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.EqualError(t, waitPostStart(ctx), "failed to wait for post-start: context canceled")
+}
+
+func Test_waitMainPreStop(t *testing.T) {
+	step = dfv1.Step{Spec: dfv1.StepSpec{Lifecycle: &dfv1.Lifecycle{PreStop: []string{"flush"}}}}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	waitMainPreStop(ctx) // times out
+	go mainPreStopCompleted()
+	waitMainPreStop(context.Background())
+	mainPreStopCompleted() // can be called more than once
+}
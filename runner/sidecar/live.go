@@ -0,0 +1,249 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+// liveSpec is the part of the step's spec that the sidecar applies as it changes, without the pod being re-created.
+// See StepSpec.WithOutLiveFields. It also has the faults and paused sources from the step's annotations, and the
+// replicas from its status.
+type liveSpec struct {
+	mu            sync.RWMutex
+	retries       map[string]dfv1.Backoff // by source name
+	faults        faults
+	paused        map[string]chan struct{} // by source name, each closed when the source is resumed
+	pausedSources string                   // the paused sources annotation that was last applied
+	replicas      int
+	expression    string // the filter or map expression that was last written
+}
+
+var live = &liveSpec{}
+
+// getRetry returns the source's retry.
+func (l *liveSpec) getRetry(sourceName string) dfv1.Backoff {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.retries[sourceName]
+}
+
+// update applies the spec, returning true if it changed the live spec.
+func (l *liveSpec) update(spec dfv1.StepSpec) bool {
+	retries := map[string]dfv1.Backoff{}
+	for _, s := range spec.Sources {
+		retries[s.Name] = s.Retry
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if notEqual, _ := sharedutil.NotEqual(l.retries, retries); !notEqual {
+		return false
+	}
+	l.retries = retries
+	return true
+}
+
+// getReplicas returns the step's replicas, as last scaled by the controller.
+func (l *liveSpec) getReplicas() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.replicas
+}
+
+// updateReplicas applies the step's replicas, returning true if they changed.
+func (l *liveSpec) updateReplicas(status dfv1.StepStatus) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.replicas == status.GetReplicas() {
+		return false
+	}
+	l.replicas = status.GetReplicas()
+	return true
+}
+
+// updateExpression writes the step's filter or map expression to the file at path, which the main container watches,
+// returning true if it changed. The file is always written the first time, as it may have been written by a previous
+// sidecar.
+func (l *liveSpec) updateExpression(path string, spec dfv1.StepSpec) (bool, error) {
+	var expression string
+	if x := spec.Filter; x != nil {
+		expression = x.Expression
+	} else if x := spec.Map; x != nil {
+		expression = x.Expression
+	} else {
+		return false, nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if expression == l.expression {
+		return false, nil
+	}
+	// write and rename, so the main container never reads a partly written expression
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(expression), 0o644); err != nil {
+		return false, fmt.Errorf("failed to write expression: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return false, fmt.Errorf("failed to rename expression: %w", err)
+	}
+	l.expression = expression
+	return true, nil
+}
+
+func (l *liveSpec) getFaults() faults {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.faults
+}
+
+// updateFaults applies the step's faults annotation, returning true if it changed the faults. Invalid faults are
+// logged, and the current faults kept.
+func (l *liveSpec) updateFaults(annotations map[string]string) bool {
+	if !faultInjection {
+		return false
+	}
+	x, err := parseFaults(annotations[dfv1.KeyFaults])
+	if err != nil {
+		logger.Error(err, "failed to parse faults, ignoring")
+		return false
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.faults == x {
+		return false
+	}
+	l.faults = x
+	return true
+}
+
+func (l *liveSpec) isPaused(sourceName string) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	_, ok := l.paused[sourceName]
+	return ok
+}
+
+// setPaused pauses or resumes the source, returning true if it changed whether the source is paused.
+func (l *liveSpec) setPaused(sourceName string, paused bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.setPausedLocked(sourceName, paused)
+}
+
+func (l *liveSpec) setPausedLocked(sourceName string, paused bool) bool {
+	resumed, ok := l.paused[sourceName]
+	if ok == paused {
+		return false
+	}
+	if paused {
+		if l.paused == nil {
+			l.paused = map[string]chan struct{}{}
+		}
+		l.paused[sourceName] = make(chan struct{})
+	} else {
+		close(resumed)
+		delete(l.paused, sourceName)
+	}
+	if pausedGauge != nil {
+		pausedGauge.WithLabelValues(sourceName, fmt.Sprint(replica)).Set(map[bool]float64{false: 0, true: 1}[paused])
+	}
+	return true
+}
+
+// waitUntilResumed returns once the source is not paused, or the context is done.
+func (l *liveSpec) waitUntilResumed(ctx context.Context, sourceName string) error {
+	l.mu.RLock()
+	resumed, ok := l.paused[sourceName]
+	l.mu.RUnlock()
+	if !ok {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// updatePaused applies the step's paused sources annotation, returning true if it changed which sources are paused.
+// The annotation is only applied when it changes, so a source paused or resumed using the endpoint stays that way until
+// then.
+func (l *liveSpec) updatePaused(annotations map[string]string) bool {
+	text := annotations[dfv1.KeyPausedSources]
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if text == l.pausedSources {
+		return false
+	}
+	l.pausedSources = text
+	paused := map[string]bool{}
+	for _, s := range strings.Split(text, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			paused[s] = true
+		}
+	}
+	changed := false
+	for sourceName := range l.paused {
+		if !paused[sourceName] && l.setPausedLocked(sourceName, false) {
+			changed = true
+		}
+	}
+	for sourceName := range paused {
+		if l.setPausedLocked(sourceName, true) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+// watchStep watches the sidecar's step, and applies changes to its live spec.
+func watchStep(ctx context.Context, dynamicInterface dynamic.Interface) {
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicInterface, 0, namespace, func(o *metav1.ListOptions) {
+		o.FieldSelector = "metadata.name=" + step.Name
+	})
+	apply := func(obj interface{}) {
+		un, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+		x := &dfv1.Step{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(un.Object, x); err != nil {
+			logger.Error(err, "failed to convert step")
+			return
+		}
+		if live.update(x.Spec) {
+			logger.Info("applied live spec change", "generation", x.Generation)
+		}
+		if changed, err := live.updateExpression(dfv1.PathExpression, x.Spec); err != nil {
+			logger.Error(err, "failed to apply expression change")
+		} else if changed {
+			logger.Info("applied expression change", "generation", x.Generation)
+		}
+		if live.updateFaults(x.Annotations) {
+			logger.Info("applied faults change", "faults", live.getFaults())
+		}
+		if live.updatePaused(x.Annotations) {
+			logger.Info("applied paused sources change", "pausedSources", x.Annotations[dfv1.KeyPausedSources])
+		}
+		if live.updateReplicas(x.Status) {
+			logger.Info("applied replicas change", "replicas", live.getReplicas())
+		}
+	}
+	factory.ForResource(dfv1.StepGroupVersionResource).Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    apply,
+		UpdateFunc: func(_, obj interface{}) { apply(obj) },
+	})
+	factory.Start(ctx.Done())
+}
@@ -0,0 +1,103 @@
+package sidecar
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_liveSpec(t *testing.T) {
+	l := &liveSpec{}
+	spec := dfv1.StepSpec{Sources: []dfv1.Source{{Name: "a", Retry: dfv1.Backoff{Steps: 3}}}}
+	assert.True(t, l.update(spec))
+	assert.Equal(t, uint64(3), l.getRetry("a").Steps)
+	assert.False(t, l.update(spec), "unchanged")
+	spec.Sources[0].Retry.Steps = 5
+	assert.True(t, l.update(spec))
+	assert.Equal(t, uint64(5), l.getRetry("a").Steps)
+}
+
+func Test_liveSpec_updateReplicas(t *testing.T) {
+	l := &liveSpec{}
+	assert.True(t, l.updateReplicas(dfv1.StepStatus{Replicas: 2}))
+	assert.Equal(t, 2, l.getReplicas())
+	assert.False(t, l.updateReplicas(dfv1.StepStatus{Replicas: 2}), "unchanged")
+}
+
+func Test_liveSpec_updateFaults(t *testing.T) {
+	l := &liveSpec{}
+	annotations := map[string]string{dfv1.KeyFaults: "sink-error=0.1"}
+	assert.False(t, l.updateFaults(annotations), "disabled")
+	withFaults(t, faults{}, 0)
+	assert.True(t, l.updateFaults(annotations))
+	assert.Equal(t, 0.1, l.getFaults().SinkError)
+	assert.False(t, l.updateFaults(annotations), "unchanged")
+	assert.False(t, l.updateFaults(map[string]string{dfv1.KeyFaults: "foo"}), "invalid")
+	assert.Equal(t, 0.1, l.getFaults().SinkError)
+	assert.True(t, l.updateFaults(nil))
+	assert.Equal(t, faults{LatencyDuration: time.Second}, l.getFaults())
+}
+
+func Test_liveSpec_updatePaused(t *testing.T) {
+	l := &liveSpec{}
+	assert.False(t, l.updatePaused(nil))
+	assert.True(t, l.updatePaused(map[string]string{dfv1.KeyPausedSources: "a, b"}))
+	assert.True(t, l.isPaused("a"))
+	assert.True(t, l.isPaused("b"))
+	assert.False(t, l.isPaused("c"))
+	assert.True(t, l.setPaused("a", false), "resumed using the endpoint")
+	assert.False(t, l.updatePaused(map[string]string{dfv1.KeyPausedSources: "a, b"}), "unchanged")
+	assert.False(t, l.isPaused("a"))
+	assert.True(t, l.updatePaused(map[string]string{dfv1.KeyPausedSources: "a"}))
+	assert.True(t, l.isPaused("a"))
+	assert.False(t, l.isPaused("b"))
+	assert.True(t, l.updatePaused(nil))
+	assert.False(t, l.isPaused("a"))
+}
+
+func Test_liveSpec_waitUntilResumed(t *testing.T) {
+	l := &liveSpec{}
+	assert.NoError(t, l.waitUntilResumed(context.Background(), "a"))
+	assert.True(t, l.setPaused("a", true))
+	assert.False(t, l.setPaused("a", true), "already paused")
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.waitUntilResumed(ctx, "a"))
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		time.Sleep(10 * time.Millisecond)
+		l.setPaused("a", false)
+	}()
+	assert.NoError(t, l.waitUntilResumed(context.Background(), "a"))
+	<-done
+}
+
+func Test_liveSpec_updateExpression(t *testing.T) {
+	l := &liveSpec{}
+	path := filepath.Join(t.TempDir(), "expression")
+	changed, err := l.updateExpression(path, dfv1.StepSpec{Cat: &dfv1.Cat{}})
+	assert.NoError(t, err)
+	assert.False(t, changed, "not a filter or map")
+	spec := dfv1.StepSpec{Filter: &dfv1.Filter{Expression: "true"}}
+	changed, err = l.updateExpression(path, spec)
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "true", string(data))
+	changed, err = l.updateExpression(path, spec)
+	assert.NoError(t, err)
+	assert.False(t, changed, "unchanged")
+	changed, err = l.updateExpression(path, dfv1.StepSpec{Filter: &dfv1.Filter{Expression: "false"}})
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	data, err = os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "false", string(data))
+}
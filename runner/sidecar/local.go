@@ -0,0 +1,127 @@
+package sidecar
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	_init "github.com/argoproj-labs/argo-dataflow/runner/init"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// ExecLocal runs the sidecar for a step as a plain process, without Kubernetes, so that a handler running on the
+// same machine (listening on :8080) can be developed without deploying it. The step is read from the pipeline in the
+// files, and any secrets in the files (e.g. `dataflow-kafka-default`) are used instead of the cluster's secrets.
+func ExecLocal(ctx context.Context, name string, paths []string) error {
+	cluster = sharedutil.GetEnvString(dfv1.EnvCluster, "local")
+	namespace = sharedutil.GetEnvString(dfv1.EnvNamespace, "default")
+	pod = "local"
+	pl, secrets, err := readLocalFiles(paths)
+	if err != nil {
+		return err
+	}
+	if pl == nil {
+		return fmt.Errorf("no pipeline found in %v", paths)
+	}
+	pipelineName = pl.GetName()
+	if step, err = getLocalStep(pl, name); err != nil {
+		return err
+	}
+	stepName = step.Spec.Name
+	if updateInterval, err = step.Spec.GetUpdateInterval(15 * time.Second); err != nil {
+		return err
+	}
+	kubernetesInterface = fake.NewSimpleClientset(secrets...)
+	secretInterface = util.NewSecretInterface(kubernetesInterface.CoreV1().Secrets(namespace))
+
+	logger.Info("running locally", "cluster", cluster, "namespace", namespace, "pipeline", pipelineName, "step", stepName, "secrets", len(secrets))
+
+	if err := os.MkdirAll(dfv1.PathVarRun, 0o700); err != nil {
+		return fmt.Errorf("failed to create %q, you may need to create it and make it writable: %w", dfv1.PathVarRun, err)
+	}
+	if err := _init.CreateFiles(step.Spec); err != nil {
+		return err
+	}
+	return run(ctx)
+}
+
+func readLocalFiles(paths []string) (*unstructured.Unstructured, []runtime.Object, error) {
+	var pl *unstructured.Unstructured
+	var secrets []runtime.Object
+	for _, path := range paths {
+		list, err := sharedutil.ReadFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %q: %w", path, err)
+		}
+		for i := range list.Items {
+			item := list.Items[i]
+			switch item.GetObjectKind().GroupVersionKind() {
+			case dfv1.PipelineGroupVersionKind:
+				if pl != nil {
+					return nil, nil, fmt.Errorf("more than one pipeline found")
+				}
+				pl = &item
+			case corev1.SchemeGroupVersion.WithKind("Secret"):
+				secret := &corev1.Secret{}
+				if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, secret); err != nil {
+					return nil, nil, fmt.Errorf("failed to convert secret %q: %w", item.GetName(), err)
+				}
+				// the API server does this conversion
+				for k, v := range secret.StringData {
+					if secret.Data == nil {
+						secret.Data = map[string][]byte{}
+					}
+					secret.Data[k] = []byte(v)
+				}
+				secret.Namespace = namespace
+				secrets = append(secrets, secret)
+			}
+		}
+	}
+	return pl, secrets, nil
+}
+
+// getLocalStep returns the named step, with the defaults the API server would have applied.
+func getLocalStep(pl *unstructured.Unstructured, name string) (dfv1.Step, error) {
+	steps, _, err := unstructured.NestedSlice(pl.Object, "spec", "steps")
+	if err != nil {
+		return dfv1.Step{}, err
+	}
+	defaults := stepDefaults()
+	var names []string
+	for _, x := range steps {
+		applyDefaults(x, defaults)
+		spec := dfv1.StepSpec{}
+		data, err := json.Marshal(x)
+		if err != nil {
+			return dfv1.Step{}, err
+		}
+		if err := json.Unmarshal(data, &spec); err != nil {
+			return dfv1.Step{}, fmt.Errorf("failed to unmarshal step: %w", err)
+		}
+		if spec.Name == name {
+			return dfv1.Step{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: namespace,
+					Name:      pl.GetName() + "-" + spec.Name,
+					Labels: map[string]string{
+						dfv1.KeyPipelineName: pl.GetName(),
+						dfv1.KeyStepName:     spec.Name,
+					},
+				},
+				Spec: spec,
+			}, nil
+		}
+		names = append(names, spec.Name)
+	}
+	return dfv1.Step{}, fmt.Errorf("step %q not found in pipeline %q, steps are %v", name, pl.GetName(), names)
+}
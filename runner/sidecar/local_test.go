@@ -0,0 +1,87 @@
+package sidecar
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_applyDefaults(t *testing.T) {
+	s := &defaultsSchema{Properties: map[string]*defaultsSchema{
+		"a": {Default: "x"},
+		"b": {Items: &defaultsSchema{Properties: map[string]*defaultsSchema{"c": {Default: float64(1)}}}},
+	}}
+	x := map[string]interface{}{"b": []interface{}{map[string]interface{}{}, map[string]interface{}{"c": float64(2)}}}
+	applyDefaults(x, s)
+	assert.Equal(t, map[string]interface{}{
+		"a": "x",
+		"b": []interface{}{map[string]interface{}{"c": float64(1)}, map[string]interface{}{"c": float64(2)}},
+	}, x)
+	applyDefaults(map[string]interface{}{}, nil)
+}
+
+func Test_readLocalFiles(t *testing.T) {
+	namespace = "my-ns"
+	path := filepath.Join(t.TempDir(), "pipeline.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+apiVersion: dataflow.argoproj.io/v1alpha1
+kind: Pipeline
+metadata:
+  name: my-pl
+spec:
+  steps:
+  - name: my-step
+    cat: {}
+    sources:
+    - kafka:
+        topic: my-topic
+    sinks:
+    - log: {}
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: dataflow-kafka-default
+stringData:
+  brokers: localhost:9092
+`), 0o600))
+	pl, secrets, err := readLocalFiles([]string{path})
+	assert.NoError(t, err)
+	if assert.Len(t, secrets, 1) {
+		secret := secrets[0].(*corev1.Secret)
+		assert.Equal(t, "my-ns", secret.Namespace)
+		assert.Equal(t, "localhost:9092", string(secret.Data["brokers"]))
+	}
+	if assert.NotNil(t, pl) {
+		t.Run("Step", func(t *testing.T) {
+			step, err := getLocalStep(pl, "my-step")
+			assert.NoError(t, err)
+			assert.Equal(t, "my-pl-my-step", step.Name)
+			assert.Equal(t, "my-ns", step.Namespace)
+			assert.Equal(t, "my-pl", step.Labels[dfv1.KeyPipelineName])
+			assert.Equal(t, uint32(1), step.Spec.Replicas)
+			assert.Equal(t, resource.MustParse("500m"), step.Spec.Sidecar.Resources.Limits[corev1.ResourceCPU])
+			if assert.Len(t, step.Spec.Sources, 1) {
+				source := step.Spec.Sources[0]
+				assert.Equal(t, "default", source.Name)
+				assert.Equal(t, uint64(20), source.Retry.Steps)
+				if assert.NotNil(t, source.Kafka) {
+					assert.Equal(t, dfv1.KafkaOffset("Last"), source.Kafka.StartOffset)
+					assert.NotNil(t, source.Kafka.FetchMin)
+				}
+			}
+			if assert.Len(t, step.Spec.Sinks, 1) {
+				assert.Equal(t, "default", step.Spec.Sinks[0].Name)
+			}
+		})
+		t.Run("NotFound", func(t *testing.T) {
+			_, err := getLocalStep(pl, "not-found")
+			assert.EqualError(t, err, `step "not-found" not found in pipeline "my-pl", steps are [my-step]`)
+		})
+	}
+}
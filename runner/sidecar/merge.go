@@ -0,0 +1,123 @@
+package sidecar
+
+import (
+	"context"
+	"sync"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+// merger interleaves messages from the step's sources into the main container. A message must acquire one of a fixed
+// number of slots before it is sent, and when a slot is released, the merge policy picks which waiting source gets it.
+type merger struct {
+	policy  dfv1.MergePolicy
+	mu      sync.Mutex
+	free    int                        // slots not held by any message
+	names   []string                   // source names, in the order they are specified
+	weights map[string]int             // by source name
+	waiting map[string][]chan struct{} // messages waiting for a slot, by source name, oldest first
+	next    int                        // the index of the source round-robin looks at first
+	current map[string]int             // each source's current weight, for smooth weighted round-robin
+}
+
+func newMerger(x dfv1.Merge, sources []dfv1.Source) *merger {
+	m := &merger{
+		policy:  x.GetPolicy(),
+		free:    x.GetConcurrency(),
+		weights: map[string]int{},
+		waiting: map[string][]chan struct{}{},
+		current: map[string]int{},
+	}
+	for _, s := range sources {
+		m.names = append(m.names, s.Name)
+		m.weights[s.Name] = s.GetWeight()
+	}
+	return m
+}
+
+// acquire waits for a slot for a message from the source. The slot must be released once the message is processed.
+func (m *merger) acquire(ctx context.Context, sourceName string) error {
+	m.mu.Lock()
+	if m.free > 0 {
+		m.free--
+		m.mu.Unlock()
+		return nil
+	}
+	granted := make(chan struct{})
+	m.waiting[sourceName] = append(m.waiting[sourceName], granted)
+	m.mu.Unlock()
+	select {
+	case <-granted:
+		return nil
+	case <-ctx.Done():
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		for i, c := range m.waiting[sourceName] {
+			if c == granted {
+				m.waiting[sourceName] = append(m.waiting[sourceName][:i], m.waiting[sourceName][i+1:]...)
+				return ctx.Err()
+			}
+		}
+		// the slot was granted as the context was done, so it must be passed on
+		m.releaseLocked()
+		return ctx.Err()
+	}
+}
+
+func (m *merger) release() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releaseLocked()
+}
+
+func (m *merger) releaseLocked() {
+	sourceName := m.pick()
+	if sourceName == "" {
+		m.free++
+		return
+	}
+	granted := m.waiting[sourceName][0]
+	m.waiting[sourceName] = m.waiting[sourceName][1:]
+	close(granted)
+}
+
+// pick returns the waiting source that gets the next slot, or "" if no source is waiting.
+func (m *merger) pick() string {
+	switch m.policy {
+	case dfv1.MergePriority:
+		best := ""
+		for _, name := range m.names {
+			if len(m.waiting[name]) > 0 && (best == "" || m.weights[name] > m.weights[best]) {
+				best = name
+			}
+		}
+		return best
+	case dfv1.MergeWeighted:
+		// smooth weighted round-robin, as used by nginx, over the waiting sources: each gains its weight, the one with
+		// the most is picked, and loses the total, so picks are spread out in proportion to the weights
+		best, total := "", 0
+		for _, name := range m.names {
+			if len(m.waiting[name]) == 0 {
+				continue
+			}
+			m.current[name] += m.weights[name]
+			total += m.weights[name]
+			if best == "" || m.current[name] > m.current[best] {
+				best = name
+			}
+		}
+		if best != "" {
+			m.current[best] -= total
+		}
+		return best
+	default:
+		for i := range m.names {
+			j := (m.next + i) % len(m.names)
+			if name := m.names[j]; len(m.waiting[name]) > 0 {
+				m.next = j + 1
+				return name
+			}
+		}
+		return ""
+	}
+}
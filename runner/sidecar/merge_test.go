@@ -0,0 +1,74 @@
+package sidecar
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+// mergeOrder returns the order the messages waiting from each source get the merger's only slot
+func mergeOrder(t *testing.T, policy dfv1.MergePolicy, sources []dfv1.Source, waiting map[string]int) []string {
+	m := newMerger(dfv1.Merge{Policy: policy}, sources)
+	ctx := context.Background()
+	assert.NoError(t, m.acquire(ctx, "")) // hold the only slot, so the messages wait
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	n := 0
+	for name, count := range waiting {
+		for i := 0; i < count; i++ {
+			n++
+			wg.Add(1)
+			go func(name string) {
+				defer wg.Done()
+				assert.NoError(t, m.acquire(ctx, name))
+				mu.Lock()
+				order = append(order, name)
+				mu.Unlock()
+				m.release()
+			}(name)
+		}
+	}
+	assert.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		total := 0
+		for _, w := range m.waiting {
+			total += len(w)
+		}
+		return total == n
+	}, time.Second, time.Millisecond)
+	m.release()
+	wg.Wait()
+	return order
+}
+
+func Test_merger(t *testing.T) {
+	t.Run("RoundRobin", func(t *testing.T) {
+		sources := []dfv1.Source{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+		assert.Equal(t, []string{"a", "b", "c", "a", "c", "a"}, mergeOrder(t, dfv1.MergeRoundRobin, sources, map[string]int{"a": 3, "b": 1, "c": 2}))
+	})
+	t.Run("Weighted", func(t *testing.T) {
+		sources := []dfv1.Source{{Name: "a", Weight: 3}, {Name: "b"}}
+		assert.Equal(t, []string{"a", "a", "b", "a", "a", "b", "b", "b"}, mergeOrder(t, dfv1.MergeWeighted, sources, map[string]int{"a": 4, "b": 4}))
+	})
+	t.Run("Priority", func(t *testing.T) {
+		sources := []dfv1.Source{{Name: "a"}, {Name: "b", Weight: 5}}
+		assert.Equal(t, []string{"b", "b", "a", "a"}, mergeOrder(t, dfv1.MergePriority, sources, map[string]int{"a": 2, "b": 2}))
+	})
+	t.Run("Concurrency", func(t *testing.T) {
+		m := newMerger(dfv1.Merge{Concurrency: 2}, []dfv1.Source{{Name: "a"}})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		assert.NoError(t, m.acquire(ctx, "a"))
+		assert.NoError(t, m.acquire(ctx, "a"))
+		assert.Equal(t, context.DeadlineExceeded, m.acquire(ctx, "a"))
+		assert.Empty(t, m.waiting["a"], "a message that gives up no longer waits")
+		m.release()
+		assert.NoError(t, m.acquire(context.Background(), "a"))
+	})
+}
@@ -0,0 +1,36 @@
+package sidecar
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+// quitServiceMesh stops the service mesh's proxy once we have exited cleanly, otherwise a pod whose main container
+// has completed never completes.
+func quitServiceMesh() {
+	if dfv1.ServiceMesh(os.Getenv(dfv1.EnvServiceMesh)) != dfv1.ServiceMeshIstio {
+		return
+	}
+	logger.Info("stopping Istio proxy")
+	if err := postQuit(dfv1.IstioQuitURL); err != nil {
+		logger.Error(err, "failed to stop Istio proxy")
+	}
+}
+
+func postQuit(url string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "POST", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
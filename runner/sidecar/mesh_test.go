@@ -0,0 +1,20 @@
+package sidecar
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_postQuit(t *testing.T) {
+	var method, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+	}))
+	defer server.Close()
+	assert.NoError(t, postQuit(server.URL+"/quitquitquit"))
+	assert.Equal(t, "POST", method)
+	assert.Equal(t, "/quitquitquit", path)
+}
@@ -0,0 +1,120 @@
+package sidecar
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// metricsGatherer removes disabled metric families and dropped labels from the metrics we expose. Series that only
+// differed by a dropped label are summed.
+type metricsGatherer struct {
+	prometheus.Gatherer
+	disabled   map[string]bool
+	dropLabels map[string]bool
+}
+
+func newMetricsGatherer(g prometheus.Gatherer, disabled, dropLabels []string) prometheus.Gatherer {
+	if len(disabled) == 0 && len(dropLabels) == 0 {
+		return g
+	}
+	x := metricsGatherer{Gatherer: g, disabled: map[string]bool{}, dropLabels: map[string]bool{}}
+	for _, n := range disabled {
+		x.disabled[n] = true
+	}
+	for _, n := range dropLabels {
+		x.dropLabels[n] = true
+	}
+	return x
+}
+
+func (g metricsGatherer) Gather() ([]*dto.MetricFamily, error) {
+	families, err := g.Gatherer.Gather()
+	var result []*dto.MetricFamily
+	for _, f := range families {
+		if g.disabled[f.GetName()] {
+			continue
+		}
+		if len(g.dropLabels) > 0 {
+			f.Metric = g.aggregate(f.Metric)
+		}
+		result = append(result, f)
+	}
+	return result, err
+}
+
+func (g metricsGatherer) aggregate(metrics []*dto.Metric) []*dto.Metric {
+	var keys []string
+	byKey := map[string]*dto.Metric{}
+	for _, m := range metrics {
+		var labels []*dto.LabelPair
+		var parts []string
+		for _, l := range m.Label {
+			if !g.dropLabels[l.GetName()] {
+				labels = append(labels, l)
+				parts = append(parts, l.GetName()+"="+l.GetValue())
+			}
+		}
+		m.Label = labels
+		key := strings.Join(parts, ",")
+		if y, ok := byKey[key]; ok {
+			mergeMetric(y, m)
+		} else {
+			byKey[key] = m
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	result := make([]*dto.Metric, len(keys))
+	for i, k := range keys {
+		result[i] = byKey[k]
+	}
+	return result
+}
+
+func mergeMetric(x, y *dto.Metric) {
+	if x.Counter != nil && y.Counter != nil {
+		x.Counter.Value = sum(x.Counter.Value, y.Counter.Value)
+	}
+	if x.Gauge != nil && y.Gauge != nil {
+		x.Gauge.Value = sum(x.Gauge.Value, y.Gauge.Value)
+	}
+	if x.Untyped != nil && y.Untyped != nil {
+		x.Untyped.Value = sum(x.Untyped.Value, y.Untyped.Value)
+	}
+	if x.Histogram != nil && y.Histogram != nil {
+		count := x.Histogram.GetSampleCount() + y.Histogram.GetSampleCount()
+		x.Histogram.SampleCount = &count
+		x.Histogram.SampleSum = sum(x.Histogram.SampleSum, y.Histogram.SampleSum)
+		// buckets share the same bounds, because they are series of the same histogram vector
+		for i, b := range x.Histogram.Bucket {
+			if i < len(y.Histogram.Bucket) {
+				c := b.GetCumulativeCount() + y.Histogram.Bucket[i].GetCumulativeCount()
+				b.CumulativeCount = &c
+				// keep the latest exemplar, so it links to a recent trace
+				if e := y.Histogram.Bucket[i].Exemplar; e != nil && (b.Exemplar == nil || e.GetTimestamp().AsTime().After(b.Exemplar.GetTimestamp().AsTime())) {
+					b.Exemplar = e
+				}
+			}
+		}
+	}
+	if x.Summary != nil && y.Summary != nil {
+		count := x.Summary.GetSampleCount() + y.Summary.GetSampleCount()
+		x.Summary.SampleCount = &count
+		x.Summary.SampleSum = sum(x.Summary.SampleSum, y.Summary.SampleSum)
+		x.Summary.Quantile = nil // quantiles cannot be summed
+	}
+}
+
+func sum(a, b *float64) *float64 {
+	v := 0.0
+	if a != nil {
+		v += *a
+	}
+	if b != nil {
+		v += *b
+	}
+	return &v
+}
@@ -0,0 +1,176 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	dto "github.com/prometheus/client_model/go"
+	"google.golang.org/protobuf/encoding/protowire"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const metricsPushJob = "dataflow-sidecar"
+
+// connectMetricsPush pushes the metrics every interval, and once more when the sidecar stops, so the final values of a
+// step that runs to completion are not lost, see MetricsPush.
+func connectMetricsPush(ctx context.Context, gatherer prometheus.Gatherer, x dfv1.MetricsPush) {
+	httpClient := &http.Client{Timeout: 10 * time.Second}
+	labels := map[string]string{
+		"namespace": namespace,
+		"pipeline":  pipelineName,
+		"step":      stepName,
+		"replica":   fmt.Sprint(replica),
+	}
+	var pushMetrics func(ctx context.Context) error
+	if x.Pushgateway != "" {
+		pusher := push.New(x.Pushgateway, metricsPushJob).Gatherer(gatherer).Client(httpClient)
+		for _, name := range []string{"namespace", "pipeline", "step", "replica"} {
+			pusher = pusher.Grouping(name, labels[name])
+		}
+		pushMetrics = func(context.Context) error { return pusher.Push() }
+	} else {
+		labels["job"] = metricsPushJob
+		pushMetrics = func(ctx context.Context) error {
+			families, err := gatherer.Gather()
+			if err != nil {
+				return fmt.Errorf("failed to gather metrics: %w", err)
+			}
+			return remoteWrite(ctx, httpClient, x.RemoteWrite, families, labels, time.Now())
+		}
+	}
+	logger.Info("pushing metrics", "pushgateway", x.Pushgateway, "remoteWrite", x.RemoteWrite, "interval", x.GetInterval())
+	go wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := pushMetrics(ctx); err != nil {
+			logger.Error(err, "failed to push metrics")
+		}
+	}, x.GetInterval())
+	addStopHook(func(ctx context.Context) error {
+		logger.Info("pushing final metrics")
+		return pushMetrics(ctx)
+	})
+}
+
+// remoteWrite sends the metrics to a Prometheus remote-write endpoint, as a snappy-compressed WriteRequest.
+func remoteWrite(ctx context.Context, httpClient *http.Client, url string, families []*dto.MetricFamily, labels map[string]string, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(snappy.Encode(nil, encodeWriteRequest(families, labels, now))))
+	if err != nil {
+		return fmt.Errorf("failed to create remote-write request: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send remote-write request: %w", err)
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	_ = resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to send remote-write request: %q %q", resp.Status, body)
+	}
+	return nil
+}
+
+type sample struct {
+	labels [][2]string // sorted by name
+	value  float64
+}
+
+// samples flattens the metric families into one sample per series, as Prometheus would scrape them, e.g. a histogram
+// has a "_bucket" series for each bucket, and "_sum" and "_count" series. The labels are added to each series,
+// unless it already has them.
+func samples(families []*dto.MetricFamily, labels map[string]string) []sample {
+	var result []sample
+	for _, f := range families {
+		for _, m := range f.Metric {
+			add := func(suffix string, value float64, extra ...string) {
+				l := map[string]string{"__name__": f.GetName() + suffix}
+				for k, v := range labels {
+					l[k] = v
+				}
+				for _, p := range m.Label {
+					l[p.GetName()] = p.GetValue()
+				}
+				for i := 0; i < len(extra); i += 2 {
+					l[extra[i]] = extra[i+1]
+				}
+				s := sample{value: value}
+				for k, v := range l {
+					s.labels = append(s.labels, [2]string{k, v})
+				}
+				sort.Slice(s.labels, func(i, j int) bool { return s.labels[i][0] < s.labels[j][0] })
+				result = append(result, s)
+			}
+			switch {
+			case m.Counter != nil:
+				add("", m.Counter.GetValue())
+			case m.Gauge != nil:
+				add("", m.Gauge.GetValue())
+			case m.Untyped != nil:
+				add("", m.Untyped.GetValue())
+			case m.Histogram != nil:
+				h := m.Histogram
+				for _, b := range h.Bucket {
+					if !math.IsInf(b.GetUpperBound(), +1) {
+						add("_bucket", float64(b.GetCumulativeCount()), "le", strconv.FormatFloat(b.GetUpperBound(), 'g', -1, 64))
+					}
+				}
+				add("_bucket", float64(h.GetSampleCount()), "le", "+Inf")
+				add("_sum", h.GetSampleSum())
+				add("_count", float64(h.GetSampleCount()))
+			case m.Summary != nil:
+				s := m.Summary
+				for _, q := range s.Quantile {
+					add("", q.GetValue(), "quantile", strconv.FormatFloat(q.GetQuantile(), 'g', -1, 64))
+				}
+				add("_sum", s.GetSampleSum())
+				add("_count", float64(s.GetSampleCount()))
+			}
+		}
+	}
+	return result
+}
+
+// encodeWriteRequest encodes the samples as a remote-write WriteRequest protobuf, i.e.
+//
+//	message WriteRequest { repeated TimeSeries timeseries = 1; }
+//	message TimeSeries { repeated Label labels = 1; repeated Sample samples = 2; }
+//	message Label { string name = 1; string value = 2; }
+//	message Sample { double value = 1; int64 timestamp = 2; }
+func encodeWriteRequest(families []*dto.MetricFamily, labels map[string]string, now time.Time) []byte {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+	var data []byte
+	for _, s := range samples(families, labels) {
+		var series []byte
+		for _, l := range s.labels {
+			var label []byte
+			label = protowire.AppendTag(label, 1, protowire.BytesType)
+			label = protowire.AppendString(label, l[0])
+			label = protowire.AppendTag(label, 2, protowire.BytesType)
+			label = protowire.AppendString(label, l[1])
+			series = protowire.AppendTag(series, 1, protowire.BytesType)
+			series = protowire.AppendBytes(series, label)
+		}
+		var value []byte
+		value = protowire.AppendTag(value, 1, protowire.Fixed64Type)
+		value = protowire.AppendFixed64(value, math.Float64bits(s.value))
+		value = protowire.AppendTag(value, 2, protowire.VarintType)
+		value = protowire.AppendVarint(value, uint64(timestamp))
+		series = protowire.AppendTag(series, 2, protowire.BytesType)
+		series = protowire.AppendBytes(series, value)
+		data = protowire.AppendTag(data, 1, protowire.BytesType)
+		data = protowire.AppendBytes(data, series)
+	}
+	return data
+}
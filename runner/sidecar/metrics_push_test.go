@@ -0,0 +1,81 @@
+package sidecar
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func Test_samples(t *testing.T) {
+	r := prometheus.NewRegistry()
+	total := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "sources_total"}, []string{"sourceName", "replica"})
+	latency := prometheus.NewHistogram(prometheus.HistogramOpts{Name: "latency", Buckets: []float64{1}})
+	r.MustRegister(total, latency)
+	total.WithLabelValues("a", "0").Add(2)
+	latency.Observe(0.5)
+	latency.Observe(2)
+	families, err := r.Gather()
+	assert.NoError(t, err)
+	assert.Equal(t, []sample{
+		{labels: [][2]string{{"__name__", "latency_bucket"}, {"le", "1"}, {"replica", "1"}}, value: 1},
+		{labels: [][2]string{{"__name__", "latency_bucket"}, {"le", "+Inf"}, {"replica", "1"}}, value: 2},
+		{labels: [][2]string{{"__name__", "latency_sum"}, {"replica", "1"}}, value: 2.5},
+		{labels: [][2]string{{"__name__", "latency_count"}, {"replica", "1"}}, value: 2},
+		{labels: [][2]string{{"__name__", "sources_total"}, {"replica", "0"}, {"sourceName", "a"}}, value: 2},
+	}, samples(families, map[string]string{"replica": "1"}), "the series' own labels are kept")
+}
+
+func Test_remoteWrite(t *testing.T) {
+	r := prometheus.NewRegistry()
+	total := prometheus.NewCounter(prometheus.CounterOpts{Name: "sources_total"})
+	r.MustRegister(total)
+	total.Add(1)
+	families, err := r.Gather()
+	assert.NoError(t, err)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "snappy", r.Header.Get("Content-Encoding"))
+		data, _ := io.ReadAll(r.Body)
+		body, err = snappy.Decode(nil, data)
+		assert.NoError(t, err)
+		w.WriteHeader(204)
+	}))
+	defer server.Close()
+	assert.NoError(t, remoteWrite(context.Background(), server.Client(), server.URL, families, map[string]string{"job": "my-job"}, time.Unix(1, 0)))
+	// WriteRequest.timeseries
+	num, typ, n := protowire.ConsumeTag(body)
+	assert.Equal(t, protowire.Number(1), num)
+	assert.Equal(t, protowire.BytesType, typ)
+	series, m := protowire.ConsumeBytes(body[n:])
+	assert.Equal(t, len(body), n+m, "one time series")
+	var labels []string
+	for len(series) > 0 {
+		num, _, n := protowire.ConsumeTag(series)
+		v, m := protowire.ConsumeBytes(series[n:])
+		series = series[n+m:]
+		if num == 1 { // TimeSeries.labels
+			_, _, n := protowire.ConsumeTag(v)
+			name, m := protowire.ConsumeString(v[n:])
+			v = v[n+m:]
+			_, _, n = protowire.ConsumeTag(v)
+			value, _ := protowire.ConsumeString(v[n:])
+			labels = append(labels, name+"="+value)
+		} else { // TimeSeries.samples
+			_, _, n := protowire.ConsumeTag(v)
+			value, m := protowire.ConsumeFixed64(v[n:])
+			assert.Equal(t, uint64(0x3ff0000000000000), value) // 1.0
+			_, _, n2 := protowire.ConsumeTag(v[n+m:])
+			timestamp, _ := protowire.ConsumeVarint(v[n+m+n2:])
+			assert.Equal(t, uint64(1000), timestamp)
+		}
+	}
+	assert.Equal(t, []string{"__name__=sources_total", "job=my-job"}, labels)
+}
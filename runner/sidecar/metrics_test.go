@@ -0,0 +1,36 @@
+package sidecar
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newMetricsGatherer(t *testing.T) {
+	r := prometheus.NewRegistry()
+	total := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "sources_total"}, []string{"sourceName", "replica"})
+	bytes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "sources_totalBytes"}, []string{"sourceName", "replica"})
+	r.MustRegister(total, bytes)
+	total.WithLabelValues("a", "0").Add(1)
+	total.WithLabelValues("a", "1").Add(2)
+	total.WithLabelValues("b", "1").Add(4)
+	bytes.WithLabelValues("a", "0").Add(1)
+
+	t.Run("Unchanged", func(t *testing.T) {
+		assert.Equal(t, r, newMetricsGatherer(r, nil, nil))
+	})
+	t.Run("DisabledAndDropLabels", func(t *testing.T) {
+		families, err := newMetricsGatherer(r, []string{"sources_totalBytes"}, []string{"replica"}).Gather()
+		assert.NoError(t, err)
+		if assert.Len(t, families, 1) {
+			f := families[0]
+			assert.Equal(t, "sources_total", f.GetName())
+			if assert.Len(t, f.Metric, 2) {
+				assert.Len(t, f.Metric[0].Label, 1)
+				assert.Equal(t, 3.0, f.Metric[0].GetCounter().GetValue())
+				assert.Equal(t, 4.0, f.Metric[1].GetCounter().GetValue())
+			}
+		}
+	})
+}
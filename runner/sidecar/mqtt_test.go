@@ -0,0 +1,32 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_mqttFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-mqtt-default"},
+		Data:       map[string][]byte{"url": []byte("tcp://mosquitto:1883"), "username": []byte("my-user"), "password": []byte("my-password")},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.MQTTSource{}
+		mqttFromSecret(x, secret)
+		assert.Equal(t, "tcp://mosquitto:1883", x.URL)
+		assert.Equal(t, "my-user", x.Username)
+		assert.Equal(t, &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-mqtt-default"}, Key: "password"}, x.PasswordSecret)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		passwordSecret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "my-key"}
+		x := &dfv1.MQTTSource{URL: "ssl://my-mosquitto:8883", Username: "other", PasswordSecret: passwordSecret}
+		mqttFromSecret(x, secret)
+		assert.Equal(t, "ssl://my-mosquitto:8883", x.URL)
+		assert.Equal(t, "other", x.Username)
+		assert.Equal(t, passwordSecret, x.PasswordSecret)
+	})
+}
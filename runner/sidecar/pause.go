@@ -0,0 +1,54 @@
+package sidecar
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var pausedGauge *prometheus.GaugeVec
+
+// connectPause adds the /pause and /resume endpoints, which pause and resume one of this replica's sources. Requests
+// must present the source's bearer token. A paused source's messages wait before they are processed, and sources only
+// fetch more messages once they have processed the ones they have, so the source stops pulling messages, but keeps its
+// connections.
+func connectPause(ctx context.Context) error {
+	pausedGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "sources",
+		Name:      "paused",
+		Help:      "Whether the source is paused, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_paused",
+	}, []string{"sourceName", "replica"})
+	for _, s := range step.Spec.Sources {
+		if live.isPaused(s.Name) {
+			logger.Info("source paused", "source", s.Name)
+			pausedGauge.WithLabelValues(s.Name, fmt.Sprint(replica)).Set(1)
+		} else {
+			pausedGauge.WithLabelValues(s.Name, fmt.Sprint(replica)).Set(0)
+		}
+	}
+	authorizations, err := sourceAuthorizations(ctx)
+	if err != nil {
+		return err
+	} else if authorizations == nil {
+		logger.Info("step secret not found, not enabling pause endpoints")
+		return nil
+	}
+	handle := func(paused bool) func(w http.ResponseWriter, r *http.Request) {
+		return func(w http.ResponseWriter, r *http.Request) {
+			sourceName, ok := authorizeSource(w, r, authorizations)
+			if !ok {
+				return
+			}
+			if live.setPaused(sourceName, paused) {
+				logger.Info("source paused or resumed using endpoint", "source", sourceName, "paused", paused)
+			}
+			w.WriteHeader(204)
+		}
+	}
+	http.HandleFunc("/pause", handle(true))
+	http.HandleFunc("/resume", handle(false))
+	return nil
+}
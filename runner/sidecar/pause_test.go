@@ -0,0 +1,44 @@
+package sidecar
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_connectPause(t *testing.T) {
+	http.DefaultServeMux = http.NewServeMux()
+	pipelineName = "my-pl"
+	stepName = "my-step"
+	namespace = "my-ns"
+	step = dfv1.Step{Spec: dfv1.StepSpec{Sources: []dfv1.Source{{Name: "my-source", Cron: &dfv1.Cron{Schedule: "* * * * *"}}}}}
+	secretInterface = fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: "my-pl-my-step"},
+		Data:       map[string][]byte{"sources.my-source.http.authorization": []byte("Bearer my-token")},
+	}).CoreV1().Secrets(namespace)
+	defer live.setPaused("my-source", false)
+
+	assert.NoError(t, connectPause(context.Background()))
+
+	post := func(path, authorization string) int {
+		r := httptest.NewRequest("POST", path, nil)
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(w, r)
+		return w.Code
+	}
+	assert.Equal(t, 404, post("/pause?source=other-source", "Bearer my-token"))
+	assert.Equal(t, 403, post("/pause?source=my-source", "Bearer wrong-token"))
+	assert.False(t, live.isPaused("my-source"))
+	assert.Equal(t, 204, post("/pause?source=my-source", "Bearer my-token"))
+	assert.True(t, live.isPaused("my-source"))
+	assert.Equal(t, 204, post("/resume?source=my-source", "Bearer my-token"))
+	assert.False(t, live.isPaused("my-source"))
+}
@@ -0,0 +1,337 @@
+package sidecar
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// quotaInterval is how often each sidecar reports what it needs to the coordinator, and is given its share
+const quotaInterval = time.Second
+
+var (
+	quota            *quotaLimiter // nil if the pipeline does not have a quota
+	quotaWaitCounter prometheus.Counter
+)
+
+// quotaDemand is what a sidecar reports to the coordinator.
+type quotaDemand struct {
+	ID        string  `json:"id"`        // "{stepName}/{replica}"
+	Messages  float64 `json:"messages"`  // per second, received since the last report
+	Bytes     float64 `json:"bytes"`     // per second, received since the last report
+	Saturated bool    `json:"saturated"` // messages waited for the quota, so the sidecar needs more than its share
+}
+
+// quotaShare is a sidecar's share of the quota. Zero is unlimited, e.g. if the quota does not limit bytes.
+type quotaShare struct {
+	Messages float64 `json:"messages"` // per second
+	Bytes    float64 `json:"bytes"`    // per second
+}
+
+// divideQuota divides the limit between the sidecars using max-min fairness: sidecars that need less than an equal
+// share are given what they need, and the rest is divided equally between the others. What is left, because every
+// sidecar needs less than it was given, is divided equally, so sidecars can grow into it.
+func divideQuota(limit float64, needs map[string]float64) map[string]float64 {
+	var ids []string
+	for id := range needs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return needs[ids[i]] < needs[ids[j]] })
+	shares := map[string]float64{}
+	remaining := limit
+	for i, id := range ids {
+		share := math.Min(needs[id], remaining/float64(len(ids)-i))
+		shares[id] = share
+		remaining -= share
+	}
+	for id := range shares {
+		shares[id] += remaining / float64(len(ids))
+	}
+	return shares
+}
+
+// quotaCoordinator divides the quota between the sidecars that have recently reported what they need. It runs in the
+// lead replica of the coordinator step.
+type quotaCoordinator struct {
+	x        dfv1.Quota
+	mu       sync.Mutex
+	demands  map[string]quotaDemand // by ID
+	reported map[string]time.Time   // by ID
+}
+
+func newQuotaCoordinator(x dfv1.Quota) *quotaCoordinator {
+	return &quotaCoordinator{x: x, demands: map[string]quotaDemand{}, reported: map[string]time.Time{}}
+}
+
+// share records what the sidecar needs, and returns its share.
+func (c *quotaCoordinator) share(d quotaDemand, now time.Time) quotaShare {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.demands[d.ID] = d
+	c.reported[d.ID] = now
+	// sidecars that stop reporting, e.g. because they were scaled down, no longer need a share
+	for id, t := range c.reported {
+		if now.Sub(t) > 3*quotaInterval {
+			delete(c.demands, id)
+			delete(c.reported, id)
+		}
+	}
+	share := func(limit float64, need func(quotaDemand) float64) float64 {
+		if limit <= 0 {
+			return 0
+		}
+		needs := map[string]float64{}
+		for id, d := range c.demands {
+			if d.Saturated {
+				needs[id] = math.Inf(1)
+			} else {
+				needs[id] = need(d)
+			}
+		}
+		return divideQuota(limit, needs)[d.ID]
+	}
+	return quotaShare{
+		Messages: share(float64(c.x.Messages), func(d quotaDemand) float64 { return d.Messages }),
+		Bytes:    share(float64(c.x.GetBytes()), func(d quotaDemand) float64 { return d.Bytes }),
+	}
+}
+
+// quotaLimiter limits the messages the sidecar receives from its sources to its share of the quota.
+type quotaLimiter struct {
+	messages *rate.Limiter // nil if messages are not limited
+	bytes    *rate.Limiter // nil if bytes are not limited
+	shared   chan struct{} // closed once the sidecar has been given its first share
+	once     sync.Once
+	mu       sync.Mutex
+	received quotaDemand // since the last report, as totals rather than rates
+	since    time.Time
+}
+
+func newQuotaLimiter(x dfv1.Quota) *quotaLimiter {
+	q := &quotaLimiter{shared: make(chan struct{}), since: time.Now()}
+	// the limits are set by the first share, which is waited for
+	if x.Messages > 0 {
+		q.messages = rate.NewLimiter(rate.Limit(x.Messages), 1)
+	}
+	if x.GetBytes() > 0 {
+		q.bytes = rate.NewLimiter(rate.Limit(x.GetBytes()), 1)
+	}
+	return q
+}
+
+// quotaBurst is the most tokens that can be used at once, a tenth of a second's worth, so that the bursts of many
+// sidecars do not add up to much more than the quota.
+func quotaBurst(limit float64) int {
+	return int(math.Max(1, math.Ceil(limit/10)))
+}
+
+func (q *quotaLimiter) update(s quotaShare) {
+	for _, x := range []struct {
+		lim   *rate.Limiter
+		limit float64
+	}{{q.messages, s.Messages}, {q.bytes, s.Bytes}} {
+		if x.lim != nil {
+			// the limit must not be zero, or waiting for it would never end
+			limit := math.Max(x.limit, 0.01)
+			x.lim.SetLimit(rate.Limit(limit))
+			x.lim.SetBurst(quotaBurst(limit))
+		}
+	}
+	q.once.Do(func() { close(q.shared) })
+}
+
+// wait returns once the message can be received, returning how long it waited for the quota.
+func (q *quotaLimiter) wait(ctx context.Context, size int) (time.Duration, error) {
+	start := time.Now()
+	select {
+	case <-q.shared:
+	case <-ctx.Done():
+		return time.Since(start), ctx.Err()
+	}
+	q.mu.Lock()
+	q.received.Messages++
+	q.received.Bytes += float64(size)
+	q.mu.Unlock()
+	var limited bool
+	if q.messages != nil {
+		if d, err := waitTokens(ctx, q.messages, 1); err != nil {
+			return time.Since(start), err
+		} else if d > 0 {
+			limited = true
+		}
+	}
+	// a message larger than the burst waits for its bytes a burst at a time
+	for n := size; q.bytes != nil && n > 0; {
+		m := n
+		if b := q.bytes.Burst(); m > b {
+			m = b
+		}
+		if d, err := waitTokens(ctx, q.bytes, m); err != nil {
+			return time.Since(start), err
+		} else if d > 0 {
+			limited = true
+		}
+		n -= m
+	}
+	if limited {
+		q.mu.Lock()
+		q.received.Saturated = true
+		q.mu.Unlock()
+	}
+	return time.Since(start), nil
+}
+
+// waitTokens waits for n tokens, which must be no more than the burst, returning how long it had to wait.
+func waitTokens(ctx context.Context, lim *rate.Limiter, n int) (time.Duration, error) {
+	var waited time.Duration
+	for {
+		r := lim.ReserveN(time.Now(), n)
+		if !r.OK() {
+			// the burst was reduced by a new share while we waited
+			return waited, nil
+		}
+		d := r.Delay()
+		if d == 0 {
+			return waited, nil
+		}
+		// a sidecar that has needed little is given a small share, so a long delay is cancelled after an interval, and
+		// the tokens reserved again, as the sidecar's share is likely to have grown
+		retry := d > quotaInterval
+		if retry {
+			r.Cancel()
+			d = quotaInterval
+		}
+		t := time.NewTimer(d)
+		select {
+		case <-t.C:
+			waited += d
+		case <-ctx.Done():
+			t.Stop()
+			r.Cancel()
+			return waited, ctx.Err()
+		}
+		if !retry {
+			return waited, nil
+		}
+	}
+}
+
+// demand returns what the sidecar has needed since the last time it was called.
+func (q *quotaLimiter) demand(id string, now time.Time) quotaDemand {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	seconds := now.Sub(q.since).Seconds()
+	if seconds <= 0 {
+		seconds = quotaInterval.Seconds()
+	}
+	d := quotaDemand{ID: id, Messages: q.received.Messages / seconds, Bytes: q.received.Bytes / seconds, Saturated: q.received.Saturated}
+	q.received = quotaDemand{}
+	q.since = now
+	return d
+}
+
+// connectQuota starts reporting what the sidecar needs to the coordinator, and limits the messages the sidecar
+// receives to its share. If this is the coordinator, it adds the /quota endpoint, which the other sidecars report to.
+// The endpoint is not authenticated, but a sidecar can only take some of the others' shares, not exceed the quota.
+func connectQuota(ctx context.Context) {
+	x := step.Spec.Quota
+	if x == nil {
+		return
+	}
+	quota = newQuotaLimiter(*x)
+	id := fmt.Sprintf("%s/%d", stepName, replica)
+	var getShare func(ctx context.Context, d quotaDemand) (quotaShare, error)
+	if stepName == x.Coordinator && leadReplica() {
+		logger.Info("coordinating quota", "messages", x.Messages, "bytes", x.GetBytes())
+		c := newQuotaCoordinator(*x)
+		getShare = func(_ context.Context, d quotaDemand) (quotaShare, error) { return c.share(d, time.Now()), nil }
+		http.HandleFunc("/quota", func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodPost {
+				w.WriteHeader(405)
+				return
+			}
+			d := quotaDemand{}
+			if err := json.NewDecoder(r.Body).Decode(&d); err != nil || d.ID == "" {
+				w.WriteHeader(400)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(sharedutil.MustJSON(c.share(d, time.Now()))))
+		})
+	} else {
+		coordinator := dfv1.Step{ObjectMeta: metav1.ObjectMeta{Name: pipelineName + "-" + x.Coordinator}}
+		u := fmt.Sprintf("https://%s-0.%s.%s.svc:3570/quota", coordinator.Name, coordinator.GetHeadlessServiceName(), namespace)
+		logger.Info("reporting to quota coordinator", "url", u)
+		// sidecars use self-signed certificates
+		client := &http.Client{
+			Timeout:   quotaInterval,
+			Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}},
+		}
+		getShare = func(ctx context.Context, d quotaDemand) (quotaShare, error) {
+			return postQuotaDemand(ctx, client, u, d)
+		}
+	}
+	quotaWaitCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sources",
+		Name:      "quota_wait_seconds",
+		Help:      "Time messages waited for the pipeline's quota, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_quota_wait_seconds",
+	}, []string{"replica"}).WithLabelValues(fmt.Sprint(replica))
+	go wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+		// if the coordinator cannot be reached, the sidecar keeps its last share
+		if s, err := getShare(ctx, quota.demand(id, time.Now())); err != nil {
+			logger.Info("failed to get quota share", "err", err.Error())
+		} else {
+			quota.update(s)
+		}
+	}, quotaInterval, 0, true)
+}
+
+// waitForQuota returns once the message can be received within the sidecar's share of the quota.
+func waitForQuota(ctx context.Context, size int) error {
+	if quota == nil {
+		return nil
+	}
+	waited, err := quota.wait(ctx, size)
+	if quotaWaitCounter != nil {
+		quotaWaitCounter.Add(waited.Seconds())
+	}
+	return err
+}
+
+func postQuotaDemand(ctx context.Context, client *http.Client, u string, d quotaDemand) (quotaShare, error) {
+	s := quotaShare{}
+	req, err := http.NewRequestWithContext(ctx, "POST", u, bytes.NewBufferString(sharedutil.MustJSON(d)))
+	if err != nil {
+		return s, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return s, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return s, err
+	}
+	if resp.StatusCode != 200 {
+		return s, fmt.Errorf("failed to report quota demand: %q %q", resp.Status, body)
+	}
+	return s, json.Unmarshal(body, &s)
+}
@@ -0,0 +1,108 @@
+package sidecar
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func Test_divideQuota(t *testing.T) {
+	assert.Empty(t, divideQuota(100, nil))
+	assert.Equal(t, map[string]float64{"a": 100}, divideQuota(100, map[string]float64{"a": 10}), "the rest is left for a to grow into")
+	assert.Equal(t, map[string]float64{"a": 50, "b": 50}, divideQuota(100, map[string]float64{"a": 80, "b": 90}))
+	assert.Equal(t, map[string]float64{"a": 10, "b": 45, "c": 45}, divideQuota(100, map[string]float64{"a": 10, "b": math.Inf(1), "c": math.Inf(1)}))
+	assert.Equal(t, map[string]float64{"a": 20, "b": 40, "c": 40}, divideQuota(100, map[string]float64{"a": 10, "b": 30, "c": 30}))
+}
+
+func Test_quotaCoordinator(t *testing.T) {
+	c := newQuotaCoordinator(dfv1.Quota{Messages: 100})
+	now := time.Now()
+	assert.Equal(t, quotaShare{Messages: 100}, c.share(quotaDemand{ID: "a/0", Messages: 10}, now))
+	assert.Equal(t, quotaShare{Messages: 90}, c.share(quotaDemand{ID: "b/0", Messages: 90, Saturated: true}, now))
+	assert.Equal(t, quotaShare{Messages: 50}, c.share(quotaDemand{ID: "a/0", Messages: 10, Saturated: true}, now))
+	assert.Equal(t, quotaShare{Messages: 100}, c.share(quotaDemand{ID: "a/0", Messages: 10, Saturated: true}, now.Add(5*time.Second)), "b stopped reporting")
+	q := resource.MustParse("1Ki")
+	c = newQuotaCoordinator(dfv1.Quota{Bytes: &q})
+	assert.Equal(t, quotaShare{Bytes: 1024}, c.share(quotaDemand{ID: "a/0", Messages: 10, Bytes: 100}, now))
+}
+
+func Test_quotaLimiter(t *testing.T) {
+	t.Run("WaitsForShare", func(t *testing.T) {
+		q := newQuotaLimiter(dfv1.Quota{Messages: 100})
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, err := q.wait(ctx, 1)
+		assert.Equal(t, context.DeadlineExceeded, err)
+		q.update(quotaShare{Messages: 100})
+		_, err = q.wait(context.Background(), 1)
+		assert.NoError(t, err)
+	})
+	t.Run("Messages", func(t *testing.T) {
+		q := newQuotaLimiter(dfv1.Quota{Messages: 100})
+		q.update(quotaShare{Messages: 100})
+		start := time.Now()
+		for i := 0; i < 30; i++ {
+			_, err := q.wait(context.Background(), 1)
+			assert.NoError(t, err)
+		}
+		// the burst is 10 messages, the other 20 take 200ms
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+		d := q.demand("a/0", time.Now())
+		assert.Equal(t, "a/0", d.ID)
+		assert.True(t, d.Saturated)
+		assert.Greater(t, d.Messages, 0.0)
+		assert.Equal(t, quotaDemand{ID: "a/0"}, q.demand("a/0", time.Now()), "reset")
+	})
+	t.Run("Bytes", func(t *testing.T) {
+		q := newQuotaLimiter(dfv1.Quota{Bytes: resource.NewQuantity(1000, resource.DecimalSI)})
+		q.update(quotaShare{Bytes: 1000})
+		start := time.Now()
+		// larger than the burst of 100 bytes
+		waited, err := q.wait(context.Background(), 300)
+		assert.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+		assert.Greater(t, waited, time.Duration(0))
+	})
+	t.Run("SmallShare", func(t *testing.T) {
+		q := newQuotaLimiter(dfv1.Quota{Messages: 100})
+		q.update(quotaShare{Messages: 0.01})
+		_, err := q.wait(context.Background(), 1) // the burst
+		assert.NoError(t, err)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			q.update(quotaShare{Messages: 100})
+		}()
+		start := time.Now()
+		_, err = q.wait(context.Background(), 1)
+		assert.NoError(t, err)
+		assert.Less(t, time.Since(start), 2*quotaInterval, "the long delay is re-evaluated")
+	})
+}
+
+func Test_connectQuota(t *testing.T) {
+	http.DefaultServeMux = http.NewServeMux()
+	stepName = "main"
+	replica = 0
+	step = dfv1.Step{Spec: dfv1.StepSpec{Quota: &dfv1.Quota{Messages: 100, Coordinator: "main"}}}
+	defer func() { quota, quotaWaitCounter = nil, nil }()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	connectQuota(ctx)
+	assert.NoError(t, waitForQuota(ctx, 1), "the coordinator gives itself a share")
+
+	server := httptest.NewServer(http.DefaultServeMux)
+	defer server.Close()
+	s, err := postQuotaDemand(ctx, server.Client(), server.URL+"/quota", quotaDemand{ID: "other/0", Messages: 10})
+	assert.NoError(t, err)
+	assert.Greater(t, s.Messages, 10.0)
+	_, err = postQuotaDemand(ctx, server.Client(), server.URL+"/quota", quotaDemand{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,55 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func redisFromSecret(x *dfv1.Redis, secret *corev1.Secret) {
+	x.URL = dfv1.StringOr(x.URL, string(secret.Data["url"]))
+	if _, ok := secret.Data["password"]; ok && x.PasswordSecret == nil {
+		x.PasswordSecret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Key:                  "password",
+		}
+	}
+}
+
+func enrichRedis(ctx context.Context, x *dfv1.Redis) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-redis-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		redisFromSecret(x, secret)
+	}
+	return nil
+}
+
+func redisStreamFromSecret(x *dfv1.RedisStream, secret *corev1.Secret) {
+	x.URL = dfv1.StringOr(x.URL, string(secret.Data["url"]))
+	if _, ok := secret.Data["password"]; ok && x.PasswordSecret == nil {
+		x.PasswordSecret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Key:                  "password",
+		}
+	}
+}
+
+// enrichRedisStream uses the same "dataflow-redis-{name}" secret as pub/sub, as both are on the same server.
+func enrichRedisStream(ctx context.Context, x *dfv1.RedisStream) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-redis-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		redisStreamFromSecret(x, secret)
+	}
+	return nil
+}
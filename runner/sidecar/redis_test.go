@@ -0,0 +1,50 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_redisFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-redis-default"},
+		Data:       map[string][]byte{"url": []byte("redis://redis:6379"), "password": []byte("my-password")},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.Redis{}
+		redisFromSecret(x, secret)
+		assert.Equal(t, "redis://redis:6379", x.URL)
+		assert.Equal(t, &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-redis-default"}, Key: "password"}, x.PasswordSecret)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		passwordSecret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "my-key"}
+		x := &dfv1.Redis{URL: "redis://my-redis:6379", PasswordSecret: passwordSecret}
+		redisFromSecret(x, secret)
+		assert.Equal(t, "redis://my-redis:6379", x.URL)
+		assert.Equal(t, passwordSecret, x.PasswordSecret)
+	})
+}
+
+func Test_redisStreamFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-redis-default"},
+		Data:       map[string][]byte{"url": []byte("redis://redis:6379"), "password": []byte("my-password")},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.RedisStream{}
+		redisStreamFromSecret(x, secret)
+		assert.Equal(t, "redis://redis:6379", x.URL)
+		assert.Equal(t, &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-redis-default"}, Key: "password"}, x.PasswordSecret)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		passwordSecret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "my-key"}
+		x := &dfv1.RedisStream{URL: "redis://my-redis:6379", PasswordSecret: passwordSecret}
+		redisStreamFromSecret(x, secret)
+		assert.Equal(t, "redis://my-redis:6379", x.URL)
+		assert.Equal(t, passwordSecret, x.PasswordSecret)
+	})
+}
@@ -0,0 +1,34 @@
+package sidecar
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+)
+
+// offsetReset returns the reset of the source to do as the sidecar starts, or nil if there is none, and a func to
+// record that it was done. Only replica 0 resets the sources. Each reset request is done once, even if the sidecar is
+// restarted after it has started consuming, because it is recorded in a file in dir, which lives as long as the pod.
+func offsetReset(dir, sourceName string) (*dfv1.ResetStatus, func() error) {
+	r := step.Status.OffsetReset
+	if !r.Resetting() || replica != 0 {
+		return nil, nil
+	}
+	path := filepath.Join(dir, "resets", sourceName+"-"+sharedutil.MustHash([]string{string(r.Offset), r.RequestedAt.String()})[:16])
+	if _, err := os.Stat(path); err == nil {
+		logger.Info("source already reset", "source", sourceName, "offset", r.Offset)
+		return nil, nil
+	}
+	return r, func() error {
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return fmt.Errorf("failed to record reset of source %q: %w", sourceName, err)
+		}
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			return fmt.Errorf("failed to record reset of source %q: %w", sourceName, err)
+		}
+		return nil
+	}
+}
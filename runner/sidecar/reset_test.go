@@ -0,0 +1,35 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_offsetReset(t *testing.T) {
+	dir := t.TempDir()
+	replica = 0
+	step = dfv1.Step{}
+	r, _ := offsetReset(dir, "my-source")
+	assert.Nil(t, r, "not resetting")
+	requestedAt := metav1.Now()
+	step.Status.OffsetReset = &dfv1.ResetStatus{Offset: dfv1.ResetOffsetEarliest, Phase: dfv1.ResetResetting, RequestedAt: requestedAt}
+	replica = 1
+	r, _ = offsetReset(dir, "my-source")
+	assert.Nil(t, r, "only replica 0 resets")
+	replica = 0
+	r, done := offsetReset(dir, "my-source")
+	if assert.NotNil(t, r) {
+		assert.Equal(t, dfv1.ResetOffsetEarliest, r.Offset)
+		assert.NoError(t, done())
+	}
+	r, _ = offsetReset(dir, "my-source")
+	assert.Nil(t, r, "already reset, e.g. the sidecar restarted")
+	r, _ = offsetReset(dir, "other-source")
+	assert.NotNil(t, r, "each source is reset")
+	step.Status.OffsetReset = &dfv1.ResetStatus{Offset: dfv1.ResetOffsetEarliest, Phase: dfv1.ResetResetting, RequestedAt: metav1.NewTime(requestedAt.Add(1e9))}
+	r, _ = offsetReset(dir, "my-source")
+	assert.NotNil(t, r, "a new reset request")
+}
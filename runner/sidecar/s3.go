@@ -9,31 +9,40 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// awsCredentialsFromSecret returns the credentials referenced by the secret, or nil if it does not reference any.
+func awsCredentialsFromSecret(secret *corev1.Secret) *dfv1.AWSCredentials {
+	if _, ok := secret.Data["credentials.accessKeyId.name"]; !ok {
+		return nil
+	}
+	return &dfv1.AWSCredentials{
+		AccessKeyID: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: string(secret.Data["credentials.accessKeyId.name"]),
+			},
+			Key: string(secret.Data["credentials.accessKeyId.key"]),
+		},
+		SecretAccessKey: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: string(secret.Data["credentials.secretAccessKey.name"]),
+			},
+			Key: string(secret.Data["credentials.secretAccessKey.key"]),
+		},
+		SessionToken: corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{
+				Name: string(secret.Data["credentials.sessionToken.name"]),
+			},
+			Key: string(secret.Data["credentials.sessionToken.key"]),
+		},
+	}
+}
+
 func s3FromSecret(x *dfv1.S3, secret *corev1.Secret) error {
 	if x.Region == "" {
 		x.Region = string(secret.Data["region"])
 	}
+	// without credentials, we use workload identity
 	if x.Credentials == nil {
-		x.Credentials = &dfv1.AWSCredentials{
-			AccessKeyID: corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{
-					Name: string(secret.Data["credentials.accessKeyId.name"]),
-				},
-				Key: string(secret.Data["credentials.accessKeyId.key"]),
-			},
-			SecretAccessKey: corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{
-					Name: string(secret.Data["credentials.secretAccessKey.name"]),
-				},
-				Key: string(secret.Data["credentials.secretAccessKey.key"]),
-			},
-			SessionToken: corev1.SecretKeySelector{
-				LocalObjectReference: corev1.LocalObjectReference{
-					Name: string(secret.Data["credentials.sessionToken.name"]),
-				},
-				Key: string(secret.Data["credentials.sessionToken.key"]),
-			},
-		}
+		x.Credentials = awsCredentialsFromSecret(secret)
 	}
 	if v, ok := secret.Data["endpoint.url"]; ok && x.Endpoint == nil {
 		x.Endpoint = &dfv1.AWSEndpoint{URL: string(v)}
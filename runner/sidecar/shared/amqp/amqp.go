@@ -0,0 +1,47 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	amqp "github.com/rabbitmq/amqp091-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// GetURL returns the broker's URL, with the password in the secret, if specified, rather than any in the URL.
+func GetURL(ctx context.Context, secretInterface corev1.SecretInterface, x dfv1.AMQP) (string, error) {
+	s := x.PasswordSecret
+	if s == nil {
+		return x.URL, nil
+	}
+	u, err := url.Parse(x.URL)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse AMQP URL: %w", err)
+	}
+	secret, err := secretInterface.Get(ctx, s.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %q: %w", s.Name, err)
+	}
+	password, ok := secret.Data[s.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %q", s.Key, s.Name)
+	}
+	u.User = url.UserPassword(u.User.Username(), string(password))
+	return u.String(), nil
+}
+
+// Dial connects to the broker.
+func Dial(ctx context.Context, secretInterface corev1.SecretInterface, x dfv1.AMQP) (*amqp.Connection, error) {
+	addr, err := GetURL(ctx, secretInterface, x)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := amqp.Dial(addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %q: %w", x.GetAddress(), err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,84 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// GetCredentials returns a provider for the credentials in the secrets, or, if there are none, for the web identity
+// injected by IAM Roles for Service Accounts (IRSA).
+func GetCredentials(ctx context.Context, secretInterface corev1.SecretInterface, x *dfv1.AWSCredentials) (aws.CredentialsProvider, error) {
+	if x == nil {
+		roleARN, tokenFile := os.Getenv("AWS_ROLE_ARN"), os.Getenv("AWS_WEB_IDENTITY_TOKEN_FILE")
+		if roleARN == "" || tokenFile == "" {
+			return nil, fmt.Errorf("no credentials were specified, and AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE are not set")
+		}
+		sessionName := dfv1.StringOr(os.Getenv(dfv1.EnvPod), "argo-dataflow")
+		options := sts.Options{Region: stsRegion(), HTTPClient: &http.Client{Timeout: 10 * time.Second}}
+		return aws.NewCredentialsCache(newWebIdentityProvider(roleARN, tokenFile, sessionName, options)), nil
+	}
+	var accessKeyID string
+	{
+		secretName := x.AccessKeyID.Name
+		secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %q: %w", secretName, err)
+		}
+		accessKeyID = string(secret.Data[x.AccessKeyID.Key])
+	}
+	var secretAccessKey string
+	{
+		secretName := x.SecretAccessKey.Name
+		secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %q: %w", secretName, err)
+		}
+		secretAccessKey = string(secret.Data[x.SecretAccessKey.Key])
+	}
+	var sessionToken string
+	{
+		secretName := x.SessionToken.Name
+		secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+		if err == nil {
+			sessionToken = string(secret.Data[x.SessionToken.Key])
+		} else {
+			// it is okay for sessionToken to be missing
+			if !apierr.IsNotFound(err) {
+				return nil, err
+			}
+		}
+	}
+	return aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken}, nil
+	}), nil
+}
+
+// stsRegion returns the region, so the regional STS endpoint is used as IRSA recommends, or, if the region is not
+// known, the global endpoint.
+func stsRegion() string {
+	for _, k := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if region := os.Getenv(k); region != "" {
+			return region
+		}
+	}
+	return "aws-global"
+}
+
+// newWebIdentityProvider returns a provider that exchanges the projected service account token for temporary
+// credentials. The token is re-read each time, because the kubelet rotates it.
+func newWebIdentityProvider(roleARN, tokenFile, sessionName string, options sts.Options) aws.CredentialsProvider {
+	return stscreds.NewWebIdentityRoleProvider(sts.New(options), roleARN, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+		o.RoleSessionName = sessionName
+	})
+}
@@ -0,0 +1,66 @@
+package aws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetCredentials(t *testing.T) {
+	secretInterface := fake.NewSimpleClientset().CoreV1().Secrets("")
+	_ = os.Unsetenv("AWS_ROLE_ARN")
+	_, err := GetCredentials(context.Background(), secretInterface, nil)
+	assert.EqualError(t, err, "no credentials were specified, and AWS_ROLE_ARN and AWS_WEB_IDENTITY_TOKEN_FILE are not set")
+}
+
+func TestWebIdentityProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		q := r.Form
+		if q.Get("Action") != "AssumeRoleWithWebIdentity" || q.Get("RoleArn") != "my-role" || q.Get("WebIdentityToken") != "my-token" || q.Get("RoleSessionName") != "my-pod" {
+			w.WriteHeader(403)
+			return
+		}
+		_, _ = w.Write([]byte(`<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>my-access-key-id</AccessKeyId>
+      <SecretAccessKey>my-secret-access-key</SecretAccessKey>
+      <SessionToken>my-session-token</SessionToken>
+      <Expiration>2030-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`))
+	}))
+	defer server.Close()
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("my-token"), 0o600))
+	options := sts.Options{
+		Region:     "us-west-2",
+		HTTPClient: server.Client(),
+		EndpointResolver: sts.EndpointResolverFunc(func(region string, options sts.EndpointResolverOptions) (aws.Endpoint, error) {
+			return aws.Endpoint{URL: server.URL, SigningRegion: region}, nil
+		}),
+	}
+	p := newWebIdentityProvider("my-role", tokenFile, "my-pod", options)
+	c, err := p.Retrieve(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, "my-access-key-id", c.AccessKeyID)
+	assert.Equal(t, "my-secret-access-key", c.SecretAccessKey)
+	assert.Equal(t, "my-session-token", c.SessionToken)
+	assert.True(t, c.CanExpire)
+	assert.Equal(t, time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), c.Expires)
+	_, err = newWebIdentityProvider("other", tokenFile, "my-pod", options).Retrieve(context.Background())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "StatusCode: 403")
+	}
+}
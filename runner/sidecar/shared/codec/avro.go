@@ -0,0 +1,461 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+// avroCodec converts the Avro binary encoding, see https://avro.apache.org/docs/current/spec.html#binary_encoding
+type avroCodec struct {
+	schema *avroSchema
+}
+
+type avroSchema struct {
+	typ      string // null, boolean, int, long, float, double, bytes, string, record, enum, array, map, fixed or union
+	fields   []avroField
+	symbols  []string
+	items    *avroSchema // array items or map values
+	branches []*avroSchema
+	size     int
+}
+
+type avroField struct {
+	name       string
+	schema     *avroSchema
+	def        interface{}
+	hasDefault bool
+}
+
+func newAvroCodec(x dfv1.AvroCodec) (Interface, error) {
+	v, err := unmarshalJSON([]byte(x.Schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal schema: %w", err)
+	}
+	s, err := parseAvroSchema(v, map[string]*avroSchema{}, "")
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema: %w", err)
+	}
+	return avroCodec{s}, nil
+}
+
+func parseAvroSchema(v interface{}, names map[string]*avroSchema, namespace string) (*avroSchema, error) {
+	switch x := v.(type) {
+	case string:
+		switch x {
+		case "null", "boolean", "int", "long", "float", "double", "bytes", "string":
+			return &avroSchema{typ: x}, nil
+		}
+		if s, ok := names[x]; ok {
+			return s, nil
+		}
+		if s, ok := names[namespace+"."+x]; ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("unknown type %q", x)
+	case []interface{}:
+		s := &avroSchema{typ: "union"}
+		for _, y := range x {
+			b, err := parseAvroSchema(y, names, namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.branches = append(s.branches, b)
+		}
+		return s, nil
+	case map[string]interface{}:
+		typ, _ := x["type"].(string)
+		s := &avroSchema{typ: typ}
+		switch typ {
+		case "record", "error", "enum", "fixed":
+			if typ == "error" {
+				s.typ = "record"
+			}
+			name, _ := x["name"].(string)
+			if name == "" {
+				return nil, fmt.Errorf("%s must have a name", typ)
+			}
+			if ns, ok := x["namespace"].(string); ok {
+				namespace = ns
+			}
+			// register before parsing fields, so records can refer to themselves
+			names[name] = s
+			if namespace != "" {
+				names[namespace+"."+name] = s
+			}
+		}
+		switch s.typ {
+		case "record":
+			fields, _ := x["fields"].([]interface{})
+			for _, f := range fields {
+				y, _ := f.(map[string]interface{})
+				name, _ := y["name"].(string)
+				if name == "" {
+					return nil, fmt.Errorf("record fields must have a name")
+				}
+				t, err := parseAvroSchema(y["type"], names, namespace)
+				if err != nil {
+					return nil, fmt.Errorf("field %q: %w", name, err)
+				}
+				def, hasDefault := y["default"]
+				s.fields = append(s.fields, avroField{name: name, schema: t, def: def, hasDefault: hasDefault})
+			}
+		case "enum":
+			symbols, _ := x["symbols"].([]interface{})
+			for _, y := range symbols {
+				symbol, _ := y.(string)
+				s.symbols = append(s.symbols, symbol)
+			}
+		case "fixed":
+			size, _ := x["size"].(json.Number)
+			n, err := size.Int64()
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("fixed must have a size")
+			}
+			s.size = int(n)
+		case "array", "map":
+			key := map[string]string{"array": "items", "map": "values"}[s.typ]
+			t, err := parseAvroSchema(x[key], names, namespace)
+			if err != nil {
+				return nil, err
+			}
+			s.items = t
+		default:
+			return parseAvroSchema(x["type"], names, namespace)
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unexpected schema %v", v)
+	}
+}
+
+func (c avroCodec) Decode(msg []byte) ([]byte, error) {
+	r := &avroReader{data: msg}
+	v, err := r.read(c.schema)
+	if err != nil {
+		return nil, err
+	}
+	if r.pos != len(r.data) {
+		return nil, fmt.Errorf("%d unexpected trailing bytes", len(r.data)-r.pos)
+	}
+	return json.Marshal(v)
+}
+
+type avroReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *avroReader) next(n int) ([]byte, error) {
+	if n < 0 || r.pos+n > len(r.data) {
+		return nil, fmt.Errorf("unexpected end of message")
+	}
+	b := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return b, nil
+}
+
+func (r *avroReader) long() (int64, error) {
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.pos += n
+	return v, nil
+}
+
+// count returns the number of items in the next block of an array or map
+func (r *avroReader) count() (int64, error) {
+	n, err := r.long()
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		// the block size follows a negative count, we don't need it
+		if _, err := r.long(); err != nil {
+			return 0, err
+		}
+		n = -n
+	}
+	return n, nil
+}
+
+func (r *avroReader) read(s *avroSchema) (interface{}, error) {
+	switch s.typ {
+	case "null":
+		return nil, nil
+	case "boolean":
+		b, err := r.next(1)
+		if err != nil {
+			return nil, err
+		}
+		return b[0] != 0, nil
+	case "int", "long":
+		return r.long()
+	case "float":
+		b, err := r.next(4)
+		if err != nil {
+			return nil, err
+		}
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "double":
+		b, err := r.next(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "bytes", "string":
+		n, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		b, err := r.next(int(n))
+		if err != nil {
+			return nil, err
+		}
+		if s.typ == "string" {
+			return string(b), nil
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "fixed":
+		b, err := r.next(s.size)
+		if err != nil {
+			return nil, err
+		}
+		return base64.StdEncoding.EncodeToString(b), nil
+	case "enum":
+		i, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.symbols) {
+			return nil, fmt.Errorf("enum index %d out of range", i)
+		}
+		return s.symbols[i], nil
+	case "union":
+		i, err := r.long()
+		if err != nil {
+			return nil, err
+		}
+		if i < 0 || int(i) >= len(s.branches) {
+			return nil, fmt.Errorf("union index %d out of range", i)
+		}
+		return r.read(s.branches[i])
+	case "record":
+		v := make(map[string]interface{}, len(s.fields))
+		for _, f := range s.fields {
+			x, err := r.read(f.schema)
+			if err != nil {
+				return nil, fmt.Errorf("field %q: %w", f.name, err)
+			}
+			v[f.name] = x
+		}
+		return v, nil
+	case "array":
+		v := make([]interface{}, 0)
+		for {
+			n, err := r.count()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return v, nil
+			}
+			for ; n > 0; n-- {
+				x, err := r.read(s.items)
+				if err != nil {
+					return nil, err
+				}
+				v = append(v, x)
+			}
+		}
+	case "map":
+		v := make(map[string]interface{})
+		for {
+			n, err := r.count()
+			if err != nil {
+				return nil, err
+			}
+			if n == 0 {
+				return v, nil
+			}
+			for ; n > 0; n-- {
+				k, err := r.read(&avroSchema{typ: "string"})
+				if err != nil {
+					return nil, err
+				}
+				x, err := r.read(s.items)
+				if err != nil {
+					return nil, err
+				}
+				v[k.(string)] = x
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %q", s.typ)
+	}
+}
+
+func (c avroCodec) Encode(msg []byte) ([]byte, error) {
+	v, err := unmarshalJSON(msg)
+	if err != nil {
+		return nil, err
+	}
+	w := &bytes.Buffer{}
+	if err := writeAvro(w, c.schema, v); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func writeLong(w *bytes.Buffer, v int64) {
+	b := make([]byte, binary.MaxVarintLen64)
+	w.Write(b[:binary.PutVarint(b, v)])
+}
+
+func writeAvro(w *bytes.Buffer, s *avroSchema, v interface{}) error {
+	if !avroMatches(s, v) {
+		return fmt.Errorf("expected %s, got %v", s.typ, v)
+	}
+	switch s.typ {
+	case "null":
+	case "boolean":
+		if v.(bool) {
+			w.WriteByte(1)
+		} else {
+			w.WriteByte(0)
+		}
+	case "int", "long":
+		i, _ := v.(json.Number).Int64()
+		writeLong(w, i)
+	case "float":
+		f, _ := v.(json.Number).Float64()
+		b := make([]byte, 4)
+		binary.LittleEndian.PutUint32(b, math.Float32bits(float32(f)))
+		w.Write(b)
+	case "double":
+		f, _ := v.(json.Number).Float64()
+		b := make([]byte, 8)
+		binary.LittleEndian.PutUint64(b, math.Float64bits(f))
+		w.Write(b)
+	case "string":
+		writeLong(w, int64(len(v.(string))))
+		w.WriteString(v.(string))
+	case "bytes", "fixed":
+		b, err := base64.StdEncoding.DecodeString(v.(string))
+		if err != nil {
+			return err
+		}
+		if s.typ == "bytes" {
+			writeLong(w, int64(len(b)))
+		} else if len(b) != s.size {
+			return fmt.Errorf("expected %d bytes, got %d", s.size, len(b))
+		}
+		w.Write(b)
+	case "enum":
+		for i, symbol := range s.symbols {
+			if symbol == v {
+				writeLong(w, int64(i))
+			}
+		}
+	case "union":
+		for i, b := range s.branches {
+			if avroMatches(b, v) {
+				writeLong(w, int64(i))
+				return writeAvro(w, b, v)
+			}
+		}
+	case "record":
+		m := v.(map[string]interface{})
+		for _, f := range s.fields {
+			x, ok := m[f.name]
+			if !ok {
+				if !f.hasDefault {
+					return fmt.Errorf("field %q is missing", f.name)
+				}
+				x = f.def
+			}
+			if err := writeAvro(w, f.schema, x); err != nil {
+				return fmt.Errorf("field %q: %w", f.name, err)
+			}
+		}
+	case "array":
+		a := v.([]interface{})
+		if len(a) > 0 {
+			writeLong(w, int64(len(a)))
+			for _, x := range a {
+				if err := writeAvro(w, s.items, x); err != nil {
+					return err
+				}
+			}
+		}
+		writeLong(w, 0)
+	case "map":
+		m := v.(map[string]interface{})
+		if len(m) > 0 {
+			writeLong(w, int64(len(m)))
+			for k, x := range m {
+				writeLong(w, int64(len(k)))
+				w.WriteString(k)
+				if err := writeAvro(w, s.items, x); err != nil {
+					return err
+				}
+			}
+		}
+		writeLong(w, 0)
+	default:
+		return fmt.Errorf("unsupported type %q", s.typ)
+	}
+	return nil
+}
+
+// avroMatches returns true if the JSON value can be written using the schema.
+func avroMatches(s *avroSchema, v interface{}) bool {
+	switch s.typ {
+	case "null":
+		return v == nil
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "int", "long":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := n.Int64()
+		return err == nil
+	case "float", "double":
+		_, ok := v.(json.Number)
+		return ok
+	case "string", "bytes", "fixed":
+		_, ok := v.(string)
+		return ok
+	case "enum":
+		for _, symbol := range s.symbols {
+			if symbol == v {
+				return true
+			}
+		}
+		return false
+	case "union":
+		for _, b := range s.branches {
+			if avroMatches(b, v) {
+				return true
+			}
+		}
+		return false
+	case "record", "map":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,58 @@
+package codec
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAvroSchema = `{
+  "type": "record",
+  "name": "Example",
+  "namespace": "my.ns",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "name", "type": "string"},
+    {"name": "score", "type": "double"},
+    {"name": "active", "type": "boolean", "default": true},
+    {"name": "color", "type": {"type": "enum", "name": "Color", "symbols": ["RED", "GREEN"]}},
+    {"name": "tags", "type": {"type": "array", "items": "string"}},
+    {"name": "counts", "type": {"type": "map", "values": "int"}},
+    {"name": "data", "type": "bytes"},
+    {"name": "next", "type": ["null", "Example"]}
+  ]
+}`
+
+func Test_avroCodec(t *testing.T) {
+	c, err := newAvroCodec(dfv1.AvroCodec{Schema: testAvroSchema})
+	assert.NoError(t, err)
+	t.Run("Roundtrip", func(t *testing.T) {
+		in := `{"color":"GREEN","counts":{"a":-2},"data":"AQI=","id":1,"name":"foo","next":{"active":false,"color":"RED","counts":{},"data":"","id":2,"name":"bar","next":null,"score":0,"tags":[]},"score":1.5,"tags":["x","y"]}`
+		data, err := c.Encode([]byte(in))
+		assert.NoError(t, err)
+		data, err = c.Decode(data)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"active":true,"color":"GREEN","counts":{"a":-2},"data":"AQI=","id":1,"name":"foo","next":{"active":false,"color":"RED","counts":{},"data":"","id":2,"name":"bar","next":null,"score":0,"tags":[]},"score":1.5,"tags":["x","y"]}`, string(data))
+	})
+	t.Run("Decode", func(t *testing.T) {
+		c, err := newAvroCodec(dfv1.AvroCodec{Schema: `{"type":"record","name":"R","fields":[{"name":"a","type":"int"},{"name":"b","type":"string"}]}`})
+		assert.NoError(t, err)
+		// a=-1 (zigzag 0x01), b="hi"
+		data, err := c.Decode([]byte{0x01, 0x04, 'h', 'i'})
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":-1,"b":"hi"}`, string(data))
+		_, err = c.Decode([]byte{0x01, 0x04, 'h'})
+		assert.Error(t, err)
+		_, err = c.Decode([]byte{0x01, 0x04, 'h', 'i', 0})
+		assert.EqualError(t, err, "1 unexpected trailing bytes")
+	})
+	t.Run("MissingField", func(t *testing.T) {
+		_, err := c.Encode([]byte(`{}`))
+		assert.EqualError(t, err, `field "id" is missing`)
+	})
+	t.Run("InvalidSchema", func(t *testing.T) {
+		_, err := newAvroCodec(dfv1.AvroCodec{Schema: `"Unknown"`})
+		assert.EqualError(t, err, `invalid schema: unknown type "Unknown"`)
+	})
+}
@@ -0,0 +1,30 @@
+package codec
+
+import (
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+// Interface converts messages between a format and JSON.
+type Interface interface {
+	// Decode converts a message in the format to JSON.
+	Decode(msg []byte) ([]byte, error)
+	// Encode converts a JSON message to the format.
+	Encode(msg []byte) ([]byte, error)
+}
+
+func New(x dfv1.Codec) (Interface, error) {
+	if x.JSON != nil {
+		return jsonCodec{}, nil
+	} else if y := x.CSV; y != nil {
+		return csvCodec{*y}, nil
+	} else if x.MsgPack != nil {
+		return newMsgPackCodec(), nil
+	} else if y := x.Avro; y != nil {
+		return newAvroCodec(*y)
+	} else if y := x.Protobuf; y != nil {
+		return newProtobufCodec(*y)
+	}
+	return nil, fmt.Errorf("codec misconfigured")
+}
@@ -0,0 +1,81 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+type csvCodec struct {
+	dfv1.CSVCodec
+}
+
+func (c csvCodec) Decode(msg []byte) ([]byte, error) {
+	r := csv.NewReader(bytes.NewReader(msg))
+	r.Comma = c.GetDelimiter()
+	r.FieldsPerRecord = len(c.Columns)
+	record, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.Read(); err != io.EOF {
+		return nil, fmt.Errorf("expected a single CSV record")
+	}
+	if len(c.Columns) == 0 {
+		return json.Marshal(record)
+	}
+	object := make(map[string]string, len(record))
+	for i, column := range c.Columns {
+		object[column] = record[i]
+	}
+	return json.Marshal(object)
+}
+
+func (c csvCodec) Encode(msg []byte) ([]byte, error) {
+	var record []string
+	if len(c.Columns) == 0 {
+		var array []interface{}
+		if err := json.Unmarshal(msg, &array); err != nil {
+			return nil, fmt.Errorf("expected a JSON array: %w", err)
+		}
+		for _, v := range array {
+			record = append(record, csvField(v))
+		}
+	} else {
+		var object map[string]interface{}
+		if err := json.Unmarshal(msg, &object); err != nil {
+			return nil, fmt.Errorf("expected a JSON object: %w", err)
+		}
+		for _, column := range c.Columns {
+			record = append(record, csvField(object[column]))
+		}
+	}
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+	w.Comma = c.GetDelimiter()
+	if err := w.Write(record); err != nil {
+		return nil, err
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// csvField returns strings as they are, and other values (e.g. numbers) as JSON. Missing values are empty.
+func csvField(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return x
+	default:
+		data, _ := json.Marshal(x)
+		return string(data)
+	}
+}
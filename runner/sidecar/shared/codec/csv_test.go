@@ -0,0 +1,33 @@
+package codec
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_csvCodec(t *testing.T) {
+	t.Run("Columns", func(t *testing.T) {
+		c := csvCodec{dfv1.CSVCodec{Columns: []string{"a", "b"}, Delimiter: ";"}}
+		data, err := c.Decode([]byte("1;x y"))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":"1","b":"x y"}`, string(data))
+		data, err = c.Encode([]byte(`{"a":1,"b":"x;y"}`))
+		assert.NoError(t, err)
+		assert.Equal(t, `1;"x;y"`, string(data))
+		_, err = c.Decode([]byte("1"))
+		assert.Error(t, err)
+	})
+	t.Run("NoColumns", func(t *testing.T) {
+		c := csvCodec{}
+		data, err := c.Decode([]byte("1,2,3"))
+		assert.NoError(t, err)
+		assert.Equal(t, `["1","2","3"]`, string(data))
+		data, err = c.Encode([]byte(`["1",2,null]`))
+		assert.NoError(t, err)
+		assert.Equal(t, `1,2,`, string(data))
+		_, err = c.Decode([]byte("1\n2"))
+		assert.EqualError(t, err, "expected a single CSV record")
+	})
+}
@@ -0,0 +1,24 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Decode(msg []byte) ([]byte, error) {
+	return compact(msg)
+}
+
+func (jsonCodec) Encode(msg []byte) ([]byte, error) {
+	return compact(msg)
+}
+
+func compact(msg []byte) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, msg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,16 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_jsonCodec(t *testing.T) {
+	c := jsonCodec{}
+	data, err := c.Decode([]byte(`{ "a": 1 }`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(data))
+	_, err = c.Encode([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/hashicorp/go-msgpack/codec"
+)
+
+type msgPackCodec struct {
+	h *codec.MsgpackHandle
+}
+
+func newMsgPackCodec() Interface {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	h.RawToString = true
+	return msgPackCodec{h}
+}
+
+func (c msgPackCodec) Decode(msg []byte) ([]byte, error) {
+	var v interface{}
+	if err := codec.NewDecoderBytes(msg, c.h).Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (c msgPackCodec) Encode(msg []byte) ([]byte, error) {
+	v, err := unmarshalJSON(msg)
+	if err != nil {
+		return nil, err
+	}
+	var out []byte
+	if err := codec.NewEncoderBytes(&out, c.h).Encode(numbersOf(v)); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// unmarshalJSON keeps numbers as json.Number, so integers do not lose precision.
+func unmarshalJSON(msg []byte) (interface{}, error) {
+	d := json.NewDecoder(bytes.NewReader(msg))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	if d.More() {
+		return nil, fmt.Errorf("expected a single JSON value")
+	}
+	return v, nil
+}
+
+// numbersOf replaces json.Number with int64 or float64.
+func numbersOf(v interface{}) interface{} {
+	switch x := v.(type) {
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return i
+		}
+		f, _ := x.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range x {
+			x[k] = numbersOf(e)
+		}
+	case []interface{}:
+		for i, e := range x {
+			x[i] = numbersOf(e)
+		}
+	}
+	return v
+}
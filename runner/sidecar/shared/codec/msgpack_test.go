@@ -0,0 +1,16 @@
+package codec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_msgPackCodec(t *testing.T) {
+	c := newMsgPackCodec()
+	data, err := c.Encode([]byte(`{"a":[1,1.5,"x",true,null]}`))
+	assert.NoError(t, err)
+	data, err = c.Decode(data)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":[1,1.5,"x",true,null]}`, string(data))
+}
@@ -0,0 +1,58 @@
+package codec
+
+import (
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+type protobufCodec struct {
+	desc protoreflect.MessageDescriptor
+}
+
+func newProtobufCodec(x dfv1.ProtobufCodec) (Interface, error) {
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(x.DescriptorSet, set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal descriptor set: %w", err)
+	}
+	files, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create files from descriptor set: %w", err)
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(x.MessageName))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find message %q: %w", x.MessageName, err)
+	}
+	desc, ok := d.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%q is not a message", x.MessageName)
+	}
+	return protobufCodec{desc}, nil
+}
+
+func (c protobufCodec) Decode(msg []byte) ([]byte, error) {
+	m := dynamicpb.NewMessage(c.desc)
+	if err := proto.Unmarshal(msg, m); err != nil {
+		return nil, err
+	}
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	// protojson output is deliberately unstable, so we compact it
+	return compact(data)
+}
+
+func (c protobufCodec) Encode(msg []byte) ([]byte, error) {
+	m := dynamicpb.NewMessage(c.desc)
+	if err := protojson.Unmarshal(msg, m); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(m)
+}
@@ -0,0 +1,34 @@
+package codec
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+func Test_protobufCodec(t *testing.T) {
+	descriptorSet, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{protodesc.ToFileDescriptorProto(durationpb.File_google_protobuf_duration_proto)},
+	})
+	assert.NoError(t, err)
+	c, err := newProtobufCodec(dfv1.ProtobufCodec{DescriptorSet: descriptorSet, MessageName: "google.protobuf.Duration"})
+	assert.NoError(t, err)
+	msg, err := proto.Marshal(durationpb.New(1500000000))
+	assert.NoError(t, err)
+	data, err := c.Decode(msg)
+	assert.NoError(t, err)
+	assert.Equal(t, `"1.500s"`, string(data))
+	data, err = c.Encode(data)
+	assert.NoError(t, err)
+	// fields may be encoded in any order
+	d := &durationpb.Duration{}
+	assert.NoError(t, proto.Unmarshal(data, d))
+	assert.True(t, proto.Equal(durationpb.New(1500000000), d))
+	_, err = newProtobufCodec(dfv1.ProtobufCodec{DescriptorSet: descriptorSet, MessageName: "google.protobuf.Unknown"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,49 @@
+package encryption
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNew(t *testing.T) {
+	ctx := context.Background()
+	secretInterface := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-secret"},
+		Data: map[string][]byte{
+			"key":       []byte("0123456789abcdef0123456789abcdef"),
+			"short-key": []byte("012345"),
+		},
+	}).CoreV1().Secrets("")
+	selector := func(key string) dfv1.Encryption {
+		return dfv1.Encryption{KeySecret: corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: key}}
+	}
+	t.Run("SealOpen", func(t *testing.T) {
+		aead, err := New(ctx, secretInterface, selector("key"))
+		assert.NoError(t, err)
+		data, err := Seal(aead, []byte("my-msg"))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "my-msg")
+		plaintext, err := Open(aead, data)
+		assert.NoError(t, err)
+		assert.Equal(t, "my-msg", string(plaintext))
+		data[len(data)-1] ^= 1
+		_, err = Open(aead, data)
+		assert.Error(t, err)
+		_, err = Open(aead, []byte("short"))
+		assert.EqualError(t, err, "encrypted data is too short")
+	})
+	t.Run("MissingKey", func(t *testing.T) {
+		_, err := New(ctx, secretInterface, selector("missing"))
+		assert.EqualError(t, err, "key missing not found in secret my-secret")
+	})
+	t.Run("InvalidKey", func(t *testing.T) {
+		_, err := New(ctx, secretInterface, selector("short-key"))
+		assert.EqualError(t, err, "failed to create cipher: crypto/aes: invalid key size 6")
+	})
+}
@@ -44,10 +44,12 @@ func GetConfig(ctx context.Context, secretInterface corev1.SecretInterface, k df
 			}
 		}
 		if sasl := k.NET.SASL; sasl != nil {
-			if sasl.UserSecret == nil || sasl.PasswordSecret == nil {
+			if sasl.UserSecret == nil && sasl.Username == "" || sasl.PasswordSecret == nil {
 				return nil, fmt.Errorf("invalid sasl config, user secret or password secret not configured")
 			}
-			if userSecret, err := secretInterface.Get(ctx, sasl.UserSecret.Name, metav1.GetOptions{}); err != nil {
+			if sasl.UserSecret == nil {
+				cm["sasl.username"] = sasl.Username
+			} else if userSecret, err := secretInterface.Get(ctx, sasl.UserSecret.Name, metav1.GetOptions{}); err != nil {
 				return nil, fmt.Errorf("failed to get user secret, %w", err)
 			} else {
 				if d, ok := userSecret.Data[sasl.UserSecret.Key]; !ok {
@@ -109,3 +111,31 @@ func getTLSConfig(ctx context.Context, secretInterface corev1.SecretInterface, k
 	}
 	return nil
 }
+
+// CreateTopic creates the topic, unless it already exists.
+func CreateTopic(ctx context.Context, secretInterface corev1.SecretInterface, k dfv1.KafkaConfig, topic string, x dfv1.KafkaCreateTopic) error {
+	config, err := GetConfig(ctx, secretInterface, k)
+	if err != nil {
+		return err
+	}
+	admin, err := kafka.NewAdminClient(&config)
+	if err != nil {
+		return err
+	}
+	defer admin.Close()
+	results, err := admin.CreateTopics(ctx, []kafka.TopicSpecification{{
+		Topic:             topic,
+		NumPartitions:     x.GetPartitions(),
+		ReplicationFactor: x.GetReplicationFactor(),
+		Config:            x.GetConfig(),
+	}})
+	if err != nil {
+		return fmt.Errorf("failed to create topic %q: %w", topic, err)
+	}
+	for _, r := range results {
+		if c := r.Error.Code(); c != kafka.ErrNoError && c != kafka.ErrTopicAlreadyExists {
+			return fmt.Errorf("failed to create topic %q: %w", topic, r.Error)
+		}
+	}
+	return nil
+}
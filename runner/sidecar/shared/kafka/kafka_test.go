@@ -83,3 +83,31 @@ func TestGetConfig(t *testing.T) {
 		}, cm)
 	}
 }
+
+func TestGetConfig_Username(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-secret"},
+		Data:       map[string][]byte{"password": []byte("my-password")},
+	})
+	cm, err := GetConfig(
+		context.Background(),
+		client.CoreV1().Secrets(""),
+		dfv1.KafkaConfig{
+			Brokers: []string{"kafka-broker"},
+			NET: &dfv1.KafkaNET{
+				SASL: &dfv1.SASL{
+					Username: "my-username",
+					PasswordSecret: &corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"},
+						Key:                  "password",
+					},
+				},
+			},
+		},
+	)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "my-username", cm["sasl.username"])
+		assert.Equal(t, "my-password", cm["sasl.password"])
+		assert.Equal(t, "PLAIN", cm["sasl.mechanism"])
+	}
+}
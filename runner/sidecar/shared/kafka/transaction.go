@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// NewTransactionalProducer returns a producer with the transactional ID, that has initialized its transactions, which
+// fences any previous producer with the same ID. It is shared by a transactional source and the step's sinks, so the
+// consumed offsets and produced messages are committed in the same transaction.
+func NewTransactionalProducer(ctx context.Context, secretInterface corev1.SecretInterface, k dfv1.KafkaConfig, transactionalID string) (*kafka.Producer, error) {
+	logger := sharedutil.NewLogger().WithValues("transactionalID", transactionalID)
+	config, err := GetConfig(ctx, secretInterface, k)
+	if err != nil {
+		return nil, err
+	}
+	config["go.logs.channel.enable"] = true
+	config["transactional.id"] = transactionalID
+	config["enable.idempotence"] = true
+	config["acks"] = "all"
+	logger.Info("kafka config", "config", sharedutil.MustJSON(RedactConfigMap(config)))
+	producer, err := kafka.NewProducer(&config)
+	if err != nil {
+		return nil, err
+	}
+	go wait.JitterUntilWithContext(ctx, func(context.Context) {
+		logger.Info("consuming Kafka logs")
+		for e := range producer.Logs() {
+			logger.WithValues("name", e.Name, "tag", e.Tag).Info(e.Message)
+		}
+	}, 3*time.Second, 1.2, true)
+	// a failed delivery fails the transaction's commit, so we only need to log it
+	go wait.JitterUntilWithContext(ctx, func(context.Context) {
+		logger.Info("starting producer event consuming loop")
+		for e := range producer.Events() {
+			if ev, ok := e.(*kafka.Message); ok && ev.TopicPartition.Error != nil {
+				logger.Info("failed to deliver message in transaction", "topic", *ev.TopicPartition.Topic, "error", ev.TopicPartition.Error.Error())
+			}
+		}
+	}, time.Second, 1.2, true)
+	if err := producer.InitTransactions(ctx); err != nil {
+		producer.Close()
+		return nil, fmt.Errorf("failed to init transactions: %w", err)
+	}
+	return producer, nil
+}
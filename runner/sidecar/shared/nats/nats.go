@@ -13,7 +13,7 @@ import (
 
 var logger = util.NewLogger()
 
-func ConnectNATS(ctx context.Context, secretInterface corev1.SecretInterface, natsURL string, x *dfv1.NATSAuth) (*nats.Conn, error) {
+func ConnectNATS(ctx context.Context, secretInterface corev1.SecretInterface, natsURL string, x *dfv1.NATSAuth, extraOpts ...nats.Option) (*nats.Conn, error) {
 	opts := []nats.Option{
 		nats.DisconnectErrHandler(func(nc *nats.Conn, err error) {
 			if err != nil {
@@ -36,6 +36,7 @@ func ConnectNATS(ctx context.Context, secretInterface corev1.SecretInterface, na
 		opts = append(opts, nats.Token(token))
 	default:
 	}
+	opts = append(opts, extraOpts...)
 	logger.Info("nats auth strategy: " + string(authStrategy))
 	if nc, err := nats.Connect(natsURL, opts...); err != nil {
 		return nil, fmt.Errorf("failed to connect to nats url=%s: %w", natsURL, err)
@@ -0,0 +1,36 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-redis/redis/v8"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// NewClient returns a client for the server, using the password in the secret, if specified, rather than any in the URL.
+func NewClient(ctx context.Context, secretInterface corev1.SecretInterface, url string, passwordSecret *apiv1.SecretKeySelector) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+	if s := passwordSecret; s != nil {
+		secret, err := secretInterface.Get(ctx, s.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %q: %w", s.Name, err)
+		}
+		password, ok := secret.Data[s.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found in secret %q", s.Key, s.Name)
+		}
+		opts.Password = string(password)
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return client, nil
+}
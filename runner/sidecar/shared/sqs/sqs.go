@@ -0,0 +1,125 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Client receives and deletes the messages of one queue.
+type Client struct {
+	queueURL string
+	client   *sqs.Client
+}
+
+// NewClient returns a client for the queue, in the region of its URL if the region is not specified, sending requests
+// to the endpoint, if specified. The timeout must be longer than any receive's wait time.
+func NewClient(queueURL, region string, credentials aws.CredentialsProvider, endpoint *dfv1.AWSEndpoint, timeout time.Duration) *Client {
+	options := sqs.Options{
+		Region:      dfv1.StringOr(region, regionOf(queueURL)),
+		Credentials: credentials,
+		HTTPClient:  &http.Client{Timeout: timeout},
+	}
+	if e := endpoint; e != nil {
+		options.EndpointResolver = sqs.EndpointResolverFunc(func(region string, options sqs.EndpointResolverOptions) (aws.Endpoint, error) {
+			return aws.Endpoint{URL: e.URL, SigningRegion: region, HostnameImmutable: true}, nil
+		})
+	}
+	return &Client{queueURL: queueURL, client: sqs.New(options)}
+}
+
+type Message struct{ types.Message }
+
+// Header returns the string value of one of the message's attributes, or "" if it does not have the attribute.
+func (m Message) Header(key string) string {
+	return aws.ToString(m.MessageAttributes[key].StringValue)
+}
+
+// SentAt returns when the message was sent, or now if that is not known.
+func (m Message) SentAt() time.Time {
+	if ms, err := strconv.ParseInt(m.Attributes[string(types.MessageSystemAttributeNameSentTimestamp)], 10, 64); err == nil {
+		return time.Unix(0, ms*int64(time.Millisecond))
+	}
+	return time.Now()
+}
+
+// regionOf returns the region of a queue's URL, e.g. "us-west-2" for
+// "https://sqs.us-west-2.amazonaws.com/123456789012/my-queue", or "" if it is not an AWS URL.
+func regionOf(queueURL string) string {
+	u, err := url.Parse(queueURL)
+	if err != nil {
+		return ""
+	}
+	parts := strings.Split(u.Hostname(), ".")
+	if len(parts) >= 4 && parts[0] == "sqs" && parts[2] == "amazonaws" {
+		return parts[1]
+	}
+	return ""
+}
+
+// Receive returns up to max messages, waiting up to waitTime for them to arrive.
+func (c *Client) Receive(ctx context.Context, max uint32, waitTime, visibilityTimeout time.Duration) ([]Message, error) {
+	output, err := c.client.ReceiveMessage(ctx, &sqs.ReceiveMessageInput{
+		QueueUrl:              aws.String(c.queueURL),
+		MaxNumberOfMessages:   int32(max),
+		WaitTimeSeconds:       int32(waitTime.Seconds()),
+		VisibilityTimeout:     int32(visibilityTimeout.Seconds()),
+		AttributeNames:        []types.QueueAttributeName{types.QueueAttributeName(types.MessageSystemAttributeNameSentTimestamp)},
+		MessageAttributeNames: []string{"All"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive messages: %w", err)
+	}
+	messages := make([]Message, len(output.Messages))
+	for i, m := range output.Messages {
+		messages[i] = Message{m}
+	}
+	return messages, nil
+}
+
+// Delete deletes the messages, which must be at most 10, from the queue.
+func (c *Client) Delete(ctx context.Context, messages []Message) error {
+	entries := make([]types.DeleteMessageBatchRequestEntry, len(messages))
+	for i, m := range messages {
+		entries[i] = types.DeleteMessageBatchRequestEntry{Id: aws.String(fmt.Sprint(i)), ReceiptHandle: m.ReceiptHandle}
+	}
+	output, err := c.client.DeleteMessageBatch(ctx, &sqs.DeleteMessageBatchInput{QueueUrl: aws.String(c.queueURL), Entries: entries})
+	if err != nil {
+		return fmt.Errorf("failed to delete messages: %w", err)
+	}
+	if len(output.Failed) > 0 {
+		f := output.Failed[0]
+		return fmt.Errorf("failed to delete %d of %d messages, e.g. %s: %s", len(output.Failed), len(messages), aws.ToString(f.Code), aws.ToString(f.Message))
+	}
+	return nil
+}
+
+// GetApproximateNumberOfMessages returns the approximate number of messages available to receive.
+func (c *Client) GetApproximateNumberOfMessages(ctx context.Context) (uint64, error) {
+	output, err := c.client.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(c.queueURL),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameApproximateNumberOfMessages},
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get queue attributes: %w", err)
+	}
+	v, ok := output.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)]
+	if !ok {
+		return 0, source.ErrPendingUnavailable
+	}
+	n, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse ApproximateNumberOfMessages %q: %w", v, err)
+	}
+	return n, nil
+}
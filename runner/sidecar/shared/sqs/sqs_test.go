@@ -0,0 +1,46 @@
+package sqs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_regionOf(t *testing.T) {
+	assert.Equal(t, "us-west-2", regionOf("https://sqs.us-west-2.amazonaws.com/123456789012/my-queue"))
+	assert.Equal(t, "", regionOf("http://localstack:4566/000000000000/my-queue"))
+}
+
+func TestMessage(t *testing.T) {
+	m := Message{types.Message{
+		Attributes:        map[string]string{"SentTimestamp": "1630461784000"},
+		MessageAttributes: map[string]types.MessageAttributeValue{"my-header": {StringValue: aws.String("my-value")}},
+	}}
+	assert.Equal(t, "my-value", m.Header("my-header"))
+	assert.Equal(t, "", m.Header("other"))
+	assert.Equal(t, int64(1630461784), m.SentAt().Unix())
+}
+
+func TestClient_error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte(`<ErrorResponse><Error><Type>Sender</Type><Code>InvalidAction</Code><Message>no such action</Message></Error></ErrorResponse>`))
+	}))
+	defer server.Close()
+	credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "my-access-key-id", SecretAccessKey: "my-secret-access-key"}, nil
+	})
+	c := NewClient("my-queue-url", "us-west-2", credentials, &dfv1.AWSEndpoint{URL: server.URL}, time.Minute)
+	_, err := c.GetApproximateNumberOfMessages(context.Background())
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to get queue attributes")
+		assert.Contains(t, err.Error(), "InvalidAction: no such action")
+	}
+}
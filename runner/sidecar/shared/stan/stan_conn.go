@@ -86,7 +86,8 @@ func ConnectSTAN(ctx context.Context, secretInterface corev1.SecretInterface, x
 	conn.nc = nc
 	conn.natsConnected = true
 
-	sc, err := stan.Connect(x.ClusterID, clientID, stan.NatsConn(nc), stan.Pings(5, 60),
+	r := x.GetReconnect()
+	sc, err := stan.Connect(x.ClusterID, clientID, stan.NatsConn(nc), stan.Pings(r.GetPingInterval(), r.GetPingMaxOut()),
 		stan.SetConnectionLostHandler(func(_ stan.Conn, reason error) {
 			conn.stanConnected = false
 			if reason != nil {
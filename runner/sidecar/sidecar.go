@@ -9,10 +9,13 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	httpsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/http"
 	tls2 "github.com/argoproj-labs/argo-dataflow/runner/sidecar/tls"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/argoproj-labs/argo-dataflow/shared/util/retry"
 	"github.com/opentracing/opentracing-go"
@@ -23,6 +26,7 @@ import (
 	jaegerlog "github.com/uber/jaeger-client-go/log"
 	"github.com/uber/jaeger-lib/metrics"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -36,6 +40,7 @@ var (
 	pipelineName        = os.Getenv(dfv1.EnvPipelineName)
 	ready               = false // we are ready to serve HTTP requests, also updates pod status condition
 	kubernetesInterface kubernetes.Interface
+	dynamicInterface    dynamic.Interface
 	secretInterface     corev1.SecretInterface
 	replica             int
 	step                dfv1.Step // this is updated on start, and then periodically as we update the status
@@ -51,7 +56,7 @@ func becomeUnreadyHook(context.Context) error {
 func Exec(ctx context.Context) error {
 	restConfig := ctrl.GetConfigOrDie()
 	kubernetesInterface = kubernetes.NewForConfigOrDie(restConfig)
-	secretInterface = kubernetesInterface.CoreV1().Secrets(namespace)
+	secretInterface = util.NewSecretInterface(kubernetesInterface.CoreV1().Secrets(namespace))
 
 	sharedutil.MustUnJSON(os.Getenv(dfv1.EnvStep), &step)
 
@@ -76,6 +81,13 @@ func Exec(ctx context.Context) error {
 		updateInterval = v
 	}
 
+	dynamicInterface = dynamic.NewForConfigOrDie(restConfig)
+	watchStep(ctx, dynamicInterface)
+
+	return run(ctx)
+}
+
+func run(ctx context.Context) (err error) {
 	cfg, err := (&jaegercfg.Configuration{
 		Disabled:    true,
 		ServiceName: fmt.Sprintf("dataflow-step-%s-%s", pipelineName, stepName),
@@ -96,33 +108,103 @@ func Exec(ctx context.Context) error {
 	defer func() { _ = closer.Close() }()
 
 	opentracing.SetGlobalTracer(tracer)
+	tracing = !cfg.Disabled
 
 	addStopHook(logMetrics)
 
+	live.update(step.Spec)
+	connectEvents()
+	connectFaults()
+	if live.updateFaults(step.Annotations) {
+		logger.Info("injecting faults", "faults", live.getFaults())
+	}
+	live.updatePaused(step.Annotations)
+	live.updateReplicas(step.Status)
+
 	if err := enrichSpec(ctx); err != nil {
 		return err
 	}
 
+	// if certificates are rotated (e.g. by cert-manager), we restart, rather than use expired certificates
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// a source that cannot continue (e.g. its transactional producer was fenced) stops the sidecar gracefully, and it
+	// exits with the error
+	var fatalErr error
+	var fatalOnce sync.Once
+	fail := func(err error) {
+		fatalOnce.Do(func() {
+			logger.Error(err, "stopping")
+			fatalErr = err
+			cancel()
+		})
+	}
+	if names := step.GetTLSSecretNames(); len(names) > 0 && !canRestartSidecar(step.Spec.RestartPolicy) {
+		logger.Info("not watching certificates, because restartPolicy is Never, changed certificates are used once the pod is replaced")
+	} else if len(names) > 0 {
+		var dirs []string
+		for _, name := range names {
+			dirs = append(dirs, dfv1.GetSecretMountPath(name))
+		}
+		if err := watchCertificates(ctx, dirs, 10*time.Second, func() {
+			setCertificatesChanged()
+			cancel()
+		}); err != nil {
+			return err
+		}
+	}
+
+	// HTTP sources may serve their own certificates, which are chosen using SNI, otherwise we use a self-signed one
+	var certificates []tls.Certificate
+	for _, s := range step.Spec.Sources {
+		if x := s.HTTP; x != nil && x.TLS != nil {
+			logger.Info("loading HTTP source certificate", "source", s.Name)
+			cer, err := httpsource.LoadCertificate(ctx, secretInterface, *x.TLS)
+			if err != nil {
+				return fmt.Errorf("failed to load certificate for source %q: %w", s.Name, err)
+			}
+			certificates = append(certificates, *cer)
+		}
+	}
 	logger.Info("generating self-signed certificate")
 	cer, err := tls2.GenerateX509KeyPair()
 	if err != nil {
 		return fmt.Errorf("failed to generate cert: %w", err)
 	}
+	certificates = append(certificates, *cer)
 
 	logger.Info("sidecar config", "stepName", stepName, "pipelineName", pipelineName, "replica", replica, "updateInterval", updateInterval.String())
 
 	defer logger.Info("done")
+	defer func() {
+		if err == nil {
+			quitServiceMesh()
+		}
+	}()
 	defer stop()
 	defer preStop("defer")
 
-	sink, dlq, err := connectSinks(ctx)
+	sink, dlq, audit, late, err := connectSinks(ctx)
 	if err != nil {
 		return err
 	}
+	sink = recordInjected(injectSinkFaults(sink))
 
-	http.Handle("/metrics", promhttp.Handler())
+	m := dfv1.SidecarMetrics{}
+	if x := step.Spec.Sidecar.Metrics; x != nil {
+		m = *x
+	}
+	gatherer := newMetricsGatherer(prometheus.DefaultGatherer, m.Disabled, m.DropLabels)
+	http.Handle("/metrics", promhttp.InstrumentMetricHandler(
+		prometheus.DefaultRegisterer,
+		promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{EnableOpenMetrics: true}), // exemplars are only exposed as OpenMetrics
+	))
+	if x := m.Push; x != nil {
+		connectMetricsPush(ctx, gatherer, *x)
+	}
 	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
-		if ready {
+		if ready && sourcesConnected() {
 			w.WriteHeader(204)
 		} else {
 			w.WriteHeader(503)
@@ -155,14 +237,23 @@ func Exec(ctx context.Context) error {
 		}, func() float64 { return float64(sharedutil.Version.Patch()) })
 	}
 
-	// we listen to this message, but it does not come from Kubernetes, it actually comes from the main container's
-	// pre-stop hook
+	// we listen to this message, both from Kubernetes, and from the main container's pre-stop hook
 	http.HandleFunc("/pre-stop", func(w http.ResponseWriter, r *http.Request) {
-		preStop(r.URL.Query().Get("source"))
+		source := r.URL.Query().Get("source")
+		preStop(source)
+		if source == "kubernetes" {
+			waitMainPreStop(r.Context())
+		}
+		w.WriteHeader(204)
+	})
+	http.HandleFunc("/pre-stop-done", func(w http.ResponseWriter, r *http.Request) {
+		mainPreStopCompleted()
 		w.WriteHeader(204)
 	})
 
-	connectOut(ctx, sink)
+	if step.Spec.HasMainContainer() {
+		connectOut(ctx, sink)
+	}
 
 	server := &http.Server{Addr: "localhost:3569"}
 	addStopHook(func(ctx context.Context) error {
@@ -177,7 +268,7 @@ func Exec(ctx context.Context) error {
 		}
 		logger.Info("HTTP server shutdown")
 	}()
-	httpServer := &http.Server{Addr: ":3570", TLSConfig: &tls.Config{Certificates: []tls.Certificate{*cer}, MinVersion: tls.VersionTLS12}}
+	httpServer := &http.Server{Addr: ":3570", TLSConfig: &tls.Config{Certificates: certificates, MinVersion: tls.VersionTLS12}}
 	addStopHook(func(ctx context.Context) error {
 		logger.Info("closing HTTPS server")
 		return httpServer.Shutdown(context.Background())
@@ -196,13 +287,30 @@ func Exec(ctx context.Context) error {
 		return err
 	}
 
-	if err := connectSources(ctx, process, dlq); err != nil {
+	if err := connectInject(ctx, process); err != nil {
+		return err
+	}
+
+	if err := connectPause(ctx); err != nil {
+		return err
+	}
+
+	connectQuota(ctx)
+
+	// steps that run to completion exit once complete, and the controller terminates the main container
+	if err := connectSources(ctx, process, dlq, audit, late, cancel, fail); err != nil {
 		return err
 	}
 
 	ready = true
 	logger.Info("ready")
 	<-ctx.Done()
+	if fatalErr != nil {
+		return fatalErr
+	}
+	if isCertificatesChanged() {
+		return errCertificatesChanged
+	}
 	return nil
 }
 
@@ -268,6 +376,41 @@ func enrichSources(ctx context.Context) error {
 				return err
 			}
 			source.JetStream = x
+		} else if x := source.Redis; x != nil {
+			if err := enrichRedis(ctx, &x.Redis); err != nil {
+				return err
+			}
+			source.Redis = x
+		} else if x := source.RedisStream; x != nil {
+			if err := enrichRedisStream(ctx, &x.RedisStream); err != nil {
+				return err
+			}
+			source.RedisStream = x
+		} else if x := source.Elasticsearch; x != nil {
+			if err := enrichElasticsearch(ctx, x); err != nil {
+				return err
+			}
+			source.Elasticsearch = x
+		} else if x := source.SQS; x != nil {
+			if err := enrichSQS(ctx, x); err != nil {
+				return err
+			}
+			source.SQS = x
+		} else if x := source.Kinesis; x != nil {
+			if err := enrichKinesis(ctx, x); err != nil {
+				return err
+			}
+			source.Kinesis = x
+		} else if x := source.AMQP; x != nil {
+			if err := enrichAMQP(ctx, &x.AMQP); err != nil {
+				return err
+			}
+			source.AMQP = x
+		} else if x := source.MQTT; x != nil {
+			if err := enrichMQTT(ctx, x); err != nil {
+				return err
+			}
+			source.MQTT = x
 		}
 		step.Spec.Sources[i] = source
 	}
@@ -296,6 +439,36 @@ func enrichSinks(ctx context.Context) error {
 				return err
 			}
 			sink.JetStream = x
+		} else if x := sink.HTTP; x != nil {
+			if err := enrichHTTP(ctx, x); err != nil {
+				return err
+			}
+			sink.HTTP = x
+		} else if x := sink.Redis; x != nil {
+			if err := enrichRedis(ctx, &x.Redis); err != nil {
+				return err
+			}
+			sink.Redis = x
+		} else if x := sink.RedisStream; x != nil {
+			if err := enrichRedisStream(ctx, &x.RedisStream); err != nil {
+				return err
+			}
+			sink.RedisStream = x
+		} else if x := sink.Snowflake; x != nil {
+			if err := enrichSnowflake(ctx, x); err != nil {
+				return err
+			}
+			sink.Snowflake = x
+		} else if x := sink.File; x != nil && x.OnRotate != nil {
+			if err := enrichHTTP(ctx, x.OnRotate); err != nil {
+				return err
+			}
+			sink.File = x
+		} else if x := sink.AMQP; x != nil {
+			if err := enrichAMQP(ctx, &x.AMQP); err != nil {
+				return err
+			}
+			sink.AMQP = x
 		}
 		step.Spec.Sinks[i] = sink
 	}
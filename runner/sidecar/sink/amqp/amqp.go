@@ -0,0 +1,131 @@
+package amqp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"text/template"
+
+	"github.com/Masterminds/sprig"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedamqp "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/amqp"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/opentracing/opentracing-go"
+	amqp "github.com/rabbitmq/amqp091-go"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type amqpSink struct {
+	sinkName   string
+	exchange   string
+	routingKey *template.Template
+	dial       func() (*amqp.Connection, error)
+	mu         sync.Mutex
+	conn       *amqp.Connection // guarded by mu
+	ch         *amqp.Channel    // guarded by mu
+}
+
+// ParseRoutingKey parses a sink's routing key template, so it can be checked before the sink is created.
+func ParseRoutingKey(text string) (*template.Template, error) {
+	return template.New("routingKey").Funcs(sprig.TxtFuncMap()).Option("missingkey=zero").Parse(text)
+}
+
+// New creates a sink that publishes to the exchange. The channel is in confirm mode, so a message is only written once
+// the broker confirms it.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sinkName string, x dfv1.AMQPSink) (sink.Interface, error) {
+	routingKey, err := ParseRoutingKey(x.RoutingKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse routing key: %w", err)
+	}
+	s := &amqpSink{
+		sinkName:   sinkName,
+		exchange:   x.Exchange,
+		routingKey: routingKey,
+		dial: func() (*amqp.Connection, error) {
+			return sharedamqp.Dial(ctx, secretInterface, x.AMQP)
+		},
+	}
+	if _, err := s.channel(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// channel returns the channel, re-opening it, and reconnecting, if it was closed, e.g. because the connection was lost,
+// or the broker closed it because the exchange does not exist.
+func (s *amqpSink) channel() (*amqp.Channel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ch != nil && !s.ch.IsClosed() {
+		return s.ch, nil
+	}
+	if s.conn == nil || s.conn.IsClosed() {
+		conn, err := s.dial()
+		if err != nil {
+			return nil, err
+		}
+		s.conn = conn
+	}
+	ch, err := s.conn.Channel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := ch.Confirm(false); err != nil {
+		_ = ch.Close()
+		return nil, fmt.Errorf("failed to put channel into confirm mode: %w", err)
+	}
+	s.ch = ch
+	return ch, nil
+}
+
+// routingKeyOf returns the message's routing key.
+func (s *amqpSink) routingKeyOf(ctx context.Context, msg []byte) (string, error) {
+	meta, err := dfv1.MetaFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	var object interface{}
+	_ = json.Unmarshal(msg, &object) // the message need not be JSON
+	w := &bytes.Buffer{}
+	if err := s.routingKey.Execute(w, map[string]interface{}{"Msg": string(msg), "Object": object, "Meta": meta}); err != nil {
+		return "", fmt.Errorf("failed to execute routing key template: %w", err)
+	}
+	return w.String(), nil
+}
+
+func (s *amqpSink) Sink(ctx context.Context, msg []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("amqp-sink-%s", s.sinkName))
+	defer span.Finish()
+	routingKey, err := s.routingKeyOf(ctx, msg)
+	if err != nil {
+		return err
+	}
+	ch, err := s.channel()
+	if err != nil {
+		return err
+	}
+	confirmation, err := ch.PublishWithDeferredConfirm(s.exchange, routingKey, false, false, amqp.Publishing{DeliveryMode: amqp.Persistent, Body: msg})
+	if err != nil {
+		return fmt.Errorf("failed to publish message: %w", err)
+	}
+	// not confirmed if the broker nacks the message, or the channel is closed
+	if !confirmation.Wait() {
+		return fmt.Errorf("broker did not confirm message")
+	}
+	return nil
+}
+
+func (s *amqpSink) Close() error {
+	logger.Info("closing amqp sink connection")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.conn == nil || s.conn.IsClosed() {
+		return nil
+	}
+	return s.conn.Close()
+}
@@ -0,0 +1,39 @@
+package amqp
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_routingKeyOf(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id", Time: 1})
+	routingKeyOf := func(text string, msg string) (string, error) {
+		tmpl, err := ParseRoutingKey(text)
+		if err != nil {
+			return "", err
+		}
+		return (&amqpSink{routingKey: tmpl}).routingKeyOf(ctx, []byte(msg))
+	}
+	t.Run("Literal", func(t *testing.T) {
+		v, err := routingKeyOf("orders", "{}")
+		assert.NoError(t, err)
+		assert.Equal(t, "orders", v)
+	})
+	t.Run("Object", func(t *testing.T) {
+		v, err := routingKeyOf("orders.{{ .Object.region | lower }}", `{"region": "EU"}`)
+		assert.NoError(t, err)
+		assert.Equal(t, "orders.eu", v)
+	})
+	t.Run("NotJSON", func(t *testing.T) {
+		v, err := routingKeyOf("{{ .Msg }}.{{ .Meta.ID }}", "foo")
+		assert.NoError(t, err)
+		assert.Equal(t, "foo.my-id", v)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ParseRoutingKey("{{")
+		assert.Error(t, err)
+	})
+}
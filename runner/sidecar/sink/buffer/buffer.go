@@ -0,0 +1,214 @@
+package buffer
+
+import (
+	"context"
+	"crypto/cipher"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/encryption"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+var logger = sharedutil.NewLogger()
+
+type entry struct {
+	Meta dfv1.Meta `json:"meta"`
+	Msg  []byte    `json:"msg"`
+}
+
+type bufferSink struct {
+	sinkName    string
+	sink        sink.Interface
+	dir         string
+	maxSize     int64
+	retryPeriod time.Duration
+	bufferBytes prometheus.Gauge
+	aead        cipher.AEAD // nil if buffered messages are not encrypted
+
+	mu    sync.Mutex
+	size  int64
+	files []string // oldest first
+	seq   uint64
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New returns a sink that writes messages to the given sink, but if that fails, stores the message in a directory
+// and retries it in the background. Once any message is buffered, new messages are buffered too, so that messages
+// are written in order. Messages buffered before a restart are recovered from the directory. If aead is not nil,
+// buffered messages are encrypted.
+func New(ctx context.Context, sinkName string, s sink.Interface, dir string, maxSize int64, bufferBytes prometheus.Gauge, aead cipher.AEAD) (sink.Interface, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create buffer dir %q: %w", dir, err)
+	}
+	b := &bufferSink{
+		sinkName:    sinkName,
+		sink:        s,
+		dir:         dir,
+		maxSize:     maxSize,
+		retryPeriod: 3 * time.Second,
+		bufferBytes: bufferBytes,
+		aead:        aead,
+		done:        make(chan struct{}),
+	}
+	if err := b.recover(); err != nil {
+		return nil, err
+	}
+	ctx, b.cancel = context.WithCancel(ctx)
+	go b.drain(ctx)
+	return b, nil
+}
+
+func (b *bufferSink) recover() error {
+	items, err := os.ReadDir(b.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read buffer dir %q: %w", b.dir, err)
+	}
+	for _, item := range items {
+		seq, err := strconv.ParseUint(item.Name(), 10, 64)
+		if err != nil || item.IsDir() {
+			continue
+		}
+		info, err := item.Info()
+		if err != nil {
+			return err
+		}
+		b.files = append(b.files, item.Name())
+		b.size += info.Size()
+		if seq >= b.seq {
+			b.seq = seq + 1
+		}
+	}
+	sort.Strings(b.files)
+	b.bufferBytes.Set(float64(b.size))
+	if len(b.files) > 0 {
+		logger.Info("recovered buffered messages", "sink", b.sinkName, "count", len(b.files), "size", b.size)
+	}
+	return nil
+}
+
+func (b *bufferSink) Sink(ctx context.Context, msg []byte) error {
+	b.mu.Lock()
+	empty := len(b.files) == 0
+	b.mu.Unlock()
+	if empty {
+		err := b.sink.Sink(ctx, msg)
+		if err == nil {
+			return nil
+		}
+		logger.Info("failed to write message, buffering", "sink", b.sinkName, "error", err.Error())
+	}
+	m, err := dfv1.MetaFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry{Meta: m, Msg: msg})
+	if err != nil {
+		return err
+	}
+	if b.aead != nil {
+		if data, err = encryption.Seal(b.aead, data); err != nil {
+			return err
+		}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.size+int64(len(data)) > b.maxSize {
+		return fmt.Errorf("buffer for sink %q is full (%d bytes)", b.sinkName, b.size)
+	}
+	// zero-padded so that the lexical order is the order messages were buffered
+	name := fmt.Sprintf("%020d", b.seq)
+	if err := os.WriteFile(filepath.Join(b.dir, name), data, 0o600); err != nil {
+		return fmt.Errorf("failed to buffer message: %w", err)
+	}
+	b.seq++
+	b.files = append(b.files, name)
+	b.size += int64(len(data))
+	b.bufferBytes.Set(float64(b.size))
+	return nil
+}
+
+func (b *bufferSink) drain(ctx context.Context) {
+	defer runtime.HandleCrash()
+	defer close(b.done)
+	for {
+		if err := b.writeOldest(ctx); err != nil {
+			logger.Info("failed to write buffered message, will retry", "sink", b.sinkName, "error", err.Error())
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(b.retryPeriod):
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// writeOldest writes the oldest buffered message to the sink, or waits a short time if there is nothing buffered.
+func (b *bufferSink) writeOldest(ctx context.Context) error {
+	b.mu.Lock()
+	if len(b.files) == 0 {
+		b.mu.Unlock()
+		select {
+		case <-ctx.Done():
+		case <-time.After(time.Second):
+		}
+		return nil
+	}
+	name := b.files[0]
+	b.mu.Unlock()
+	path := filepath.Join(b.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	plaintext := data
+	if b.aead != nil {
+		if plaintext, err = encryption.Open(b.aead, data); err != nil {
+			return fmt.Errorf("failed to decrypt buffered message %q: %w", path, err)
+		}
+	}
+	e := entry{}
+	if err := json.Unmarshal(plaintext, &e); err != nil {
+		return fmt.Errorf("failed to decode buffered message %q: %w", path, err)
+	}
+	if err := b.sink.Sink(dfv1.ContextWithMeta(ctx, e.Meta), e.Msg); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.files = b.files[1:]
+	b.size -= int64(len(data))
+	b.bufferBytes.Set(float64(b.size))
+	return nil
+}
+
+// Close stops retrying buffered messages, which remain on disk, and closes the underlying sink if it is a closer.
+func (b *bufferSink) Close() error {
+	b.cancel()
+	<-b.done
+	if closer, ok := b.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package buffer
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+type flakySink struct {
+	mu      sync.Mutex
+	failing bool
+	msgs    []string
+}
+
+func (f *flakySink) Sink(_ context.Context, msg []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failing {
+		return errors.New("unavailable")
+	}
+	f.msgs = append(f.msgs, string(msg))
+	return nil
+}
+
+func (f *flakySink) setFailing(v bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failing = v
+}
+
+func (f *flakySink) getMsgs() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.msgs...)
+}
+
+func TestNew(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "1"})
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{})
+	t.Run("Full", func(t *testing.T) {
+		f := &flakySink{failing: true}
+		s, err := New(ctx, "my-sink", f, t.TempDir(), 10, gauge, nil)
+		assert.NoError(t, err)
+		defer func() { _ = s.(*bufferSink).Close() }()
+		assert.Error(t, s.Sink(ctx, []byte("too-big-for-the-buffer")))
+	})
+	t.Run("Retry", func(t *testing.T) {
+		dir := t.TempDir()
+		f := &flakySink{failing: true}
+		s, err := New(ctx, "my-sink", f, dir, 1024, gauge, nil)
+		assert.NoError(t, err)
+		s.(*bufferSink).retryPeriod = 10 * time.Millisecond
+		assert.NoError(t, s.Sink(ctx, []byte("a")))
+		assert.NoError(t, s.Sink(ctx, []byte("b")))
+		f.setFailing(false)
+		assert.Eventually(t, func() bool { return len(f.getMsgs()) == 2 }, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"a", "b"}, f.getMsgs())
+		assert.NoError(t, s.(*bufferSink).Close())
+		items, err := os.ReadDir(dir)
+		assert.NoError(t, err)
+		assert.Empty(t, items)
+	})
+	t.Run("Recover", func(t *testing.T) {
+		dir := t.TempDir()
+		f := &flakySink{failing: true}
+		s, err := New(ctx, "my-sink", f, dir, 1024, gauge, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Sink(ctx, []byte("a")))
+		assert.NoError(t, s.(*bufferSink).Close())
+		s, err = New(ctx, "my-sink", f, dir, 1024, gauge, nil)
+		assert.NoError(t, err)
+		defer func() { _ = s.(*bufferSink).Close() }()
+		assert.Len(t, s.(*bufferSink).files, 1)
+	})
+	t.Run("Encrypted", func(t *testing.T) {
+		dir := t.TempDir()
+		block, err := aes.NewCipher([]byte("0123456789abcdef"))
+		assert.NoError(t, err)
+		aead, err := cipher.NewGCM(block)
+		assert.NoError(t, err)
+		f := &flakySink{failing: true}
+		s, err := New(ctx, "my-sink", f, dir, 1024, gauge, aead)
+		assert.NoError(t, err)
+		s.(*bufferSink).retryPeriod = 10 * time.Millisecond
+		assert.NoError(t, s.Sink(ctx, []byte("my-secret-msg")))
+		data, err := os.ReadFile(filepath.Join(dir, s.(*bufferSink).files[0]))
+		assert.NoError(t, err)
+		assert.NotContains(t, string(data), "my-secret-msg")
+		f.setFailing(false)
+		assert.Eventually(t, func() bool { return len(f.getMsgs()) == 1 }, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, []string{"my-secret-msg"}, f.getMsgs())
+		assert.NoError(t, s.(*bufferSink).Close())
+	})
+}
@@ -0,0 +1,98 @@
+package cloudevents
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	"github.com/opentracing/opentracing-go"
+)
+
+// the Knative SinkBinding convention
+// https://knative.dev/docs/eventing/custom-event-source/sinkbinding/
+const (
+	envKSink        = "K_SINK"
+	envKCEOverrides = "K_CE_OVERRIDES"
+)
+
+type cloudEventsSink struct {
+	sinkName string
+	header   http.Header
+	client   *http.Client
+	url      string
+}
+
+func New(sinkName, namespace string, x dfv1.CloudEventsSink) (sink.Interface, error) {
+	url := x.GetURL(namespace)
+	if url == "" {
+		url = os.Getenv(envKSink)
+	}
+	if url == "" {
+		return nil, fmt.Errorf("neither url or broker are specified, and %s is not set", envKSink)
+	}
+	header := http.Header{}
+	header.Set("Content-Type", x.GetContentType())
+	header.Set("Ce-Specversion", "1.0")
+	header.Set("Ce-Type", x.GetType())
+	if v := os.Getenv(envKCEOverrides); v != "" {
+		overrides := struct {
+			Extensions map[string]string `json:"extensions"`
+		}{}
+		if err := json.Unmarshal([]byte(v), &overrides); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", envKCEOverrides, err)
+		}
+		for k, v := range overrides.Extensions {
+			header.Set("Ce-"+k, v)
+		}
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.MaxIdleConns = 32
+	t.MaxConnsPerHost = 32
+	t.MaxIdleConnsPerHost = 32
+	t.TLSClientConfig.InsecureSkipVerify = x.InsecureSkipVerify
+	return cloudEventsSink{
+		sinkName,
+		header,
+		&http.Client{Timeout: 10 * time.Second, Transport: t},
+		url,
+	}, nil
+}
+
+func (c cloudEventsSink) Sink(ctx context.Context, msg []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("cloudevents-sink-%s", c.sinkName))
+	defer span.Finish()
+	m, err := dfv1.MetaFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(msg))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header = c.header.Clone() // must clone to prevent concurrency issues
+	req.Header.Set("Ce-Id", m.ID)
+	req.Header.Set("Ce-Source", m.Source)
+	if m.Time > 0 {
+		req.Header.Set("Ce-Time", time.Unix(m.Time, 0).UTC().Format(time.RFC3339))
+	}
+	if err := opentracing.GlobalTracer().Inject(span.Context(), opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(req.Header)); err != nil {
+		return fmt.Errorf("failed to inject tracing headers: %w", err)
+	}
+	if resp, err := c.client.Do(req); err != nil {
+		return fmt.Errorf("failed to send CloudEvent: %w", err)
+	} else {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("failed to send CloudEvent: %q", resp.Status)
+		}
+	}
+	return nil
+}
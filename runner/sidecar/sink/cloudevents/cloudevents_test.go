@@ -0,0 +1,44 @@
+package cloudevents
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	_ = os.Unsetenv(envKSink)
+	_, err := New("my-sink", "my-ns", dfv1.CloudEventsSink{})
+	assert.EqualError(t, err, "neither url or broker are specified, and K_SINK is not set")
+}
+
+func TestCloudEventsSink(t *testing.T) {
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(202)
+	}))
+	defer server.Close()
+	t.Setenv(envKSink, server.URL)
+	t.Setenv(envKCEOverrides, `{"extensions": {"foo": "bar"}}`)
+	s, err := New("my-sink", "my-ns", dfv1.CloudEventsSink{})
+	assert.NoError(t, err)
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id", Time: 1})
+	assert.NoError(t, s.Sink(ctx, []byte("my-msg")))
+	assert.Equal(t, "my-msg", string(body))
+	assert.Equal(t, "1.0", header.Get("Ce-Specversion"))
+	assert.Equal(t, "io.argoproj.dataflow.message", header.Get("Ce-Type"))
+	assert.Equal(t, "my-source", header.Get("Ce-Source"))
+	assert.Equal(t, "my-id", header.Get("Ce-Id"))
+	assert.Equal(t, "1970-01-01T00:00:01Z", header.Get("Ce-Time"))
+	assert.Equal(t, "bar", header.Get("Ce-Foo"))
+	assert.Equal(t, "application/octet-stream", header.Get("Content-Type"))
+}
@@ -0,0 +1,37 @@
+package codec
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/codec"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+)
+
+type codecSink struct {
+	sinkName string
+	sink     sink.Interface
+	encoder  codec.Interface
+}
+
+// New wraps a sink so that each message is encoded from JSON before it is written.
+func New(sinkName string, s sink.Interface, encoder codec.Interface) sink.Interface {
+	return &codecSink{sinkName: sinkName, sink: s, encoder: encoder}
+}
+
+func (c *codecSink) Sink(ctx context.Context, msg []byte) error {
+	data, err := c.encoder.Encode(msg)
+	if err != nil {
+		return fmt.Errorf("failed to encode message for sink %q: %w", c.sinkName, err)
+	}
+	return c.sink.Sink(ctx, data)
+}
+
+// Close closes the underlying sink if it is a closer.
+func (c *codecSink) Close() error {
+	if closer, ok := c.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
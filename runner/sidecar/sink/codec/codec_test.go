@@ -0,0 +1,29 @@
+package codec
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/codec"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	msgs []string
+}
+
+func (r *recordingSink) Sink(_ context.Context, msg []byte) error {
+	r.msgs = append(r.msgs, string(msg))
+	return nil
+}
+
+func TestCodecSink_Sink(t *testing.T) {
+	encoder, err := codec.New(dfv1.Codec{CSV: &dfv1.CSVCodec{Columns: []string{"a"}}})
+	assert.NoError(t, err)
+	r := &recordingSink{}
+	s := New("my-sink", r, encoder)
+	assert.NoError(t, s.Sink(context.Background(), []byte(`{"a":"x"}`)))
+	assert.Equal(t, []string{"x"}, r.msgs)
+	assert.Error(t, s.Sink(context.Background(), []byte(`[`)))
+}
@@ -0,0 +1,36 @@
+package dapr
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaprSink(t *testing.T) {
+	var path string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	t.Setenv("DAPR_HTTP_PORT", u.Port())
+	s, err := New("my-sink", dfv1.DaprSink{Binding: "my-binding", Metadata: map[string]string{"key": "my-key"}})
+	assert.NoError(t, err)
+	t.Run("Text", func(t *testing.T) {
+		assert.NoError(t, s.Sink(context.Background(), []byte("my-msg")))
+		assert.Equal(t, "/v1.0/bindings/my-binding", path)
+		assert.JSONEq(t, `{"data": "my-msg", "metadata": {"key": "my-key"}, "operation": "create"}`, string(body))
+	})
+	t.Run("JSON", func(t *testing.T) {
+		assert.NoError(t, s.Sink(context.Background(), []byte(`{"foo":1}`)))
+		assert.JSONEq(t, `{"data": {"foo": 1}, "metadata": {"key": "my-key"}, "operation": "create"}`, string(body))
+	})
+}
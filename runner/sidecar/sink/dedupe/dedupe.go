@@ -0,0 +1,63 @@
+package dedupe
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var logger = sharedutil.NewLogger()
+
+type dedupeSink struct {
+	sinkName   string
+	sink       sink.Interface
+	store      state.Store
+	duplicates prometheus.Counter
+}
+
+// New wraps a sink so that each message is only written once. The source and ID of each message written are
+// recorded in the store, and messages that were already written, e.g. because their source redelivered them after a
+// restart, are skipped. A message is recorded after it is written, so one that is written concurrently with its
+// redelivery, or whose record is lost, may still be written twice.
+func New(sinkName string, s sink.Interface, store state.Store, duplicates prometheus.Counter) sink.Interface {
+	return &dedupeSink{sinkName: sinkName, sink: s, store: store, duplicates: duplicates}
+}
+
+func (d *dedupeSink) Sink(ctx context.Context, msg []byte) error {
+	m, err := dfv1.MetaFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	key := m.Source + "/" + m.ID
+	if v, err := d.store.Get(ctx, key); err != nil {
+		return fmt.Errorf("failed to get whether message %q was written to sink %q: %w", key, d.sinkName, err)
+	} else if v != nil {
+		d.duplicates.Inc()
+		return nil
+	}
+	if err := d.sink.Sink(ctx, msg); err != nil {
+		return err
+	}
+	// the message was written, so returning an error would only have it written again
+	if err := d.store.Set(ctx, key, []byte{1}); err != nil {
+		logger.Error(err, "failed to record message was written", "sink", d.sinkName, "key", key)
+	}
+	return nil
+}
+
+// Close closes the store, and the underlying sink if it is a closer.
+func (d *dedupeSink) Close() error {
+	err := d.store.Close()
+	if closer, ok := d.sink.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
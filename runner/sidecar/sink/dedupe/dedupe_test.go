@@ -0,0 +1,52 @@
+package dedupe
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingSink struct {
+	n   int
+	err error
+}
+
+func (s *countingSink) Sink(context.Context, []byte) error {
+	s.n++
+	return s.err
+}
+
+func TestDedupeSink(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "1"})
+	t.Run("Duplicate", func(t *testing.T) {
+		s := &countingSink{}
+		duplicates := prometheus.NewCounter(prometheus.CounterOpts{})
+		d := New("my-sink", s, state.NewMemory(ctx, time.Minute), duplicates)
+		assert.NoError(t, d.Sink(ctx, []byte("a")))
+		assert.NoError(t, d.Sink(ctx, []byte("a")))
+		assert.Equal(t, 1, s.n)
+		assert.Equal(t, float64(1), testutil.ToFloat64(duplicates))
+		other := dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: "my-source", ID: "2"})
+		assert.NoError(t, d.Sink(other, []byte("a")))
+		assert.Equal(t, 2, s.n)
+		assert.NoError(t, d.(*dedupeSink).Close())
+	})
+	t.Run("Failed", func(t *testing.T) {
+		s := &countingSink{err: errors.New("failed")}
+		d := New("my-sink", s, state.NewMemory(ctx, time.Minute), prometheus.NewCounter(prometheus.CounterOpts{}))
+		assert.EqualError(t, d.Sink(ctx, []byte("a")), "failed")
+		assert.EqualError(t, d.Sink(ctx, []byte("a")), "failed", "failed messages are not recorded")
+		assert.Equal(t, 2, s.n)
+	})
+	t.Run("NoMeta", func(t *testing.T) {
+		d := New("my-sink", &countingSink{}, state.NewMemory(ctx, time.Minute), prometheus.NewCounter(prometheus.CounterOpts{}))
+		assert.Error(t, d.Sink(context.Background(), []byte("a")))
+	})
+}
@@ -0,0 +1,322 @@
+package file
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	httpsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/http"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+// inProgress is the suffix of files that are being written to
+const inProgress = ".inprogress"
+
+type file struct {
+	path     string // relative to the sink's directory, without the in-progress suffix
+	f        *os.File
+	w        writer
+	messages int64
+	size     int64
+	opened   time.Time
+}
+
+// rotated describes a file that has been rotated, it is sent to the rotation callback
+type rotated struct {
+	Path     string `json:"path"`
+	Messages int64  `json:"messages"`
+	Size     int64  `json:"size"`
+}
+
+type fileSink struct {
+	sinkName  string
+	sinkURN   string
+	dir       string
+	prefix    string
+	x         dfv1.FileSink
+	partition *vm.Program
+	onRotate  sink.Interface
+	mu        sync.Mutex
+	files     map[string]*file // by partition
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// New creates a sink that writes to files in dir. Each file is named "{prefix}-{unixNano}.{ext}", the prefix must be
+// unique to the replica, as in-progress files with the prefix are assumed to have been left by a previous run of the
+// replica: NDJSON files are truncated to their last complete line and rotated, Parquet files are unreadable without
+// their footer, so they are removed.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sinkName, sinkURN, dir, prefix string, x dfv1.FileSink) (sink.Interface, error) {
+	s := &fileSink{
+		sinkName: sinkName,
+		sinkURN:  sinkURN,
+		dir:      dir,
+		prefix:   prefix,
+		x:        x,
+		files:    map[string]*file{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if x.Partition != "" {
+		prog, err := expr.Compile(x.Partition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile partition %q: %w", x.Partition, err)
+		}
+		s.partition = prog
+	}
+	if y := x.OnRotate; y != nil {
+		onRotate, err := httpsink.New(ctx, sinkName, secretInterface, *y)
+		if err != nil {
+			return nil, err
+		}
+		s.onRotate = onRotate
+	}
+	recovered, err := s.recover()
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover in-progress files: %w", err)
+	}
+	s.notify(recovered)
+	go s.rotateOld()
+	return s, nil
+}
+
+func (s *fileSink) ext() string {
+	return strings.ToLower(string(s.x.GetFormat()))
+}
+
+func (s *fileSink) recover() ([]rotated, error) {
+	var recovered []rotated
+	err := filepath.Walk(s.dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !s.isInProgress(info.Name()) {
+			return err
+		}
+		rel, err := filepath.Rel(s.dir, strings.TrimSuffix(path, inProgress))
+		if err != nil {
+			return err
+		}
+		if s.x.GetFormat() == dfv1.FileFormatParquet {
+			logger.Info("removing incomplete Parquet file", "sink", s.sinkName, "path", rel)
+			return os.Remove(path)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		data = data[:bytes.LastIndexByte(data, '\n')+1]
+		if len(data) == 0 {
+			return os.Remove(path)
+		}
+		if err := os.Truncate(path, int64(len(data))); err != nil {
+			return err
+		}
+		if err := os.Rename(path, strings.TrimSuffix(path, inProgress)); err != nil {
+			return err
+		}
+		recovered = append(recovered, rotated{Path: rel, Messages: int64(bytes.Count(data, []byte("\n"))), Size: int64(len(data))})
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return recovered, err
+}
+
+// isInProgress returns true if the file is one of the replica's in-progress files, i.e. the prefix is followed only by
+// the timestamp, so another replica's prefix that starts with this one does not match.
+func (s *fileSink) isInProgress(name string) bool {
+	suffix := "." + s.ext() + inProgress
+	if !strings.HasPrefix(name, s.prefix+"-") || !strings.HasSuffix(name, suffix) {
+		return false
+	}
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, s.prefix+"-"), suffix)
+	return timestamp != "" && strings.Trim(timestamp, "0123456789") == ""
+}
+
+func (s *fileSink) Sink(ctx context.Context, msg []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("file-sink-%s", s.sinkName))
+	defer span.Finish()
+	partition, err := s.partitionOf(ctx, msg)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	rotated, err := s.write(partition, msg)
+	s.mu.Unlock()
+	s.notify(rotated)
+	return err
+}
+
+// partitionOf returns the directory the message is written to, it is always within the sink's directory.
+func (s *fileSink) partitionOf(ctx context.Context, msg []byte) (string, error) {
+	if s.partition == nil {
+		return "", nil
+	}
+	env, err := util.ExprEnv(ctx, msg)
+	if err != nil {
+		return "", fmt.Errorf("failed to create expr env: %w", err)
+	}
+	v, err := expr.Run(s.partition, env)
+	if err != nil {
+		return "", fmt.Errorf("failed to evaluate partition: %w", err)
+	}
+	p, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("partition must be a string, got %T", v)
+	}
+	return strings.TrimPrefix(filepath.Clean("/"+p), "/"), nil
+}
+
+func (s *fileSink) write(partition string, msg []byte) ([]rotated, error) {
+	f, ok := s.files[partition]
+	if !ok {
+		var err error
+		if f, err = s.open(partition); err != nil {
+			return nil, err
+		}
+		s.files[partition] = f
+	}
+	n, err := f.w.write(msg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to write to %q: %w", f.path, err)
+	}
+	f.messages++
+	f.size += int64(n)
+	if s.x.GetSync() == dfv1.FileSyncAlways {
+		if err := f.f.Sync(); err != nil {
+			return nil, fmt.Errorf("failed to sync %q: %w", f.path, err)
+		}
+	}
+	if f.size < s.x.GetMaxSize() {
+		return nil, nil
+	}
+	r, err := s.rotate(partition)
+	if err != nil || r == nil {
+		return nil, err
+	}
+	return []rotated{*r}, nil
+}
+
+func (s *fileSink) open(partition string) (*file, error) {
+	if err := os.MkdirAll(filepath.Join(s.dir, partition), 0o755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(partition, fmt.Sprintf("%s-%d.%s", s.prefix, time.Now().UnixNano(), s.ext()))
+	f, err := os.OpenFile(filepath.Join(s.dir, path)+inProgress, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newWriter(s.x, f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return &file{path: path, f: f, w: w, opened: time.Now()}, nil
+}
+
+// rotate finishes and closes the partition's file, then removes its in-progress suffix. It returns nil if the file was
+// empty, e.g. because its only message failed, as the file is removed rather than rotated.
+func (s *fileSink) rotate(partition string) (*rotated, error) {
+	f := s.files[partition]
+	delete(s.files, partition)
+	err := f.w.finish()
+	if err == nil {
+		err = f.f.Sync()
+	}
+	if closeErr := f.f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to finish %q: %w", f.path, err)
+	}
+	path := filepath.Join(s.dir, f.path)
+	if f.messages == 0 {
+		return nil, os.Remove(path + inProgress)
+	}
+	if err := os.Rename(path+inProgress, path); err != nil {
+		return nil, fmt.Errorf("failed to rotate %q: %w", f.path, err)
+	}
+	return &rotated{Path: f.path, Messages: f.messages, Size: f.size}, nil
+}
+
+func (s *fileSink) rotateOld() {
+	defer runtime.HandleCrash()
+	defer close(s.done)
+	interval := s.x.GetMaxAge()
+	if interval > time.Second {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+		var old []rotated
+		s.mu.Lock()
+		for partition, f := range s.files {
+			if time.Since(f.opened) < s.x.GetMaxAge() {
+				continue
+			}
+			r, err := s.rotate(partition)
+			if err != nil {
+				logger.Error(err, "failed to rotate", "sink", s.sinkName)
+			} else if r != nil {
+				old = append(old, *r)
+			}
+		}
+		s.mu.Unlock()
+		s.notify(old)
+	}
+}
+
+// notify logs each rotated file, and sends it to the rotation callback. A failed callback is only logged, as the file's
+// messages have been written.
+func (s *fileSink) notify(rotated []rotated) {
+	for _, r := range rotated {
+		logger.Info("rotated file", "sink", s.sinkName, "path", r.Path, "messages", r.Messages, "size", r.Size)
+		if s.onRotate == nil {
+			continue
+		}
+		ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: s.sinkURN, ID: r.Path, Time: time.Now().Unix()})
+		if err := s.onRotate.Sink(ctx, []byte(sharedutil.MustJSON(r))); err != nil {
+			logger.Error(err, "failed to send rotation callback", "sink", s.sinkName, "path", r.Path)
+		}
+	}
+}
+
+// Close rotates every file, so none are left in progress.
+func (s *fileSink) Close() error {
+	close(s.stop)
+	<-s.done
+	var closed []rotated
+	var lastErr error
+	s.mu.Lock()
+	for partition := range s.files {
+		r, err := s.rotate(partition)
+		if err != nil {
+			lastErr = err
+		} else if r != nil {
+			closed = append(closed, *r)
+		}
+	}
+	s.mu.Unlock()
+	s.notify(closed)
+	return lastErr
+}
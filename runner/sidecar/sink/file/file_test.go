@@ -0,0 +1,145 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/antonmedv/expr"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var secretInterface = fake.NewSimpleClientset().CoreV1().Secrets("")
+
+// files returns the files in dir, relative to it
+func files(t *testing.T, dir string) []string {
+	var names []string
+	assert.NoError(t, filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			names = append(names, rel)
+		}
+		return err
+	}))
+	sort.Strings(names)
+	return names
+}
+
+func TestFileSink(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id", Time: 1634284800})
+	t.Run("NDJSON", func(t *testing.T) {
+		dir := t.TempDir()
+		var mu sync.Mutex
+		var callbacks []rotated
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			x := rotated{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&x))
+			mu.Lock()
+			callbacks = append(callbacks, x)
+			mu.Unlock()
+		}))
+		defer server.Close()
+		s, err := New(ctx, secretInterface, "my-sink", "my-urn", dir, "my-pl-main-0", dfv1.FileSink{
+			Partition: `"date=" + ctx.time[0:10]`,
+			MaxSize:   resource.MustParse("20"),
+			OnRotate:  &dfv1.HTTPSink{URL: server.URL},
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a": 1}`)))
+		names := files(t, dir)
+		if assert.Len(t, names, 1) {
+			assert.Regexp(t, `^date=2021-10-15/my-pl-main-0-[0-9]+\.ndjson\.inprogress$`, names[0])
+		}
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a": 2}`)))
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a": 3}`)))
+		assert.Regexp(t, `^failed to write to "date=2021-10-15/my-pl-main-0-[0-9]+\.ndjson": message is not JSON: invalid character 'o' in literal null \(expecting 'u'\)$`, s.Sink(ctx, []byte(`not json`)).Error())
+		assert.NoError(t, s.(*fileSink).Close())
+		names = files(t, dir)
+		if assert.Len(t, names, 1, "the file with only the failed message is removed") {
+			data, err := os.ReadFile(filepath.Join(dir, names[0]))
+			assert.NoError(t, err)
+			assert.Equal(t, "{\"a\":1}\n{\"a\":2}\n{\"a\":3}\n", string(data), "rotated once it reaches 20 bytes")
+		}
+		assert.Equal(t, []rotated{{Path: names[0], Messages: 3, Size: 24}}, callbacks)
+	})
+	t.Run("MaxAge", func(t *testing.T) {
+		dir := t.TempDir()
+		s, err := New(ctx, secretInterface, "my-sink", "my-urn", dir, "my-pl-main-0", dfv1.FileSink{
+			MaxAge: metav1.Duration{Duration: 10 * time.Millisecond},
+			Sync:   dfv1.FileSyncAlways,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a": 1}`)))
+		assert.Eventually(t, func() bool {
+			names := files(t, dir)
+			return len(names) == 1 && filepath.Ext(names[0]) == ".ndjson"
+		}, time.Second, 10*time.Millisecond)
+		assert.NoError(t, s.(*fileSink).Close())
+	})
+	t.Run("Recover", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(dir, "p"), 0o755))
+		for name, data := range map[string]string{
+			"p/my-pl-main-0-1.ndjson.inprogress":   "{\"a\":1}\n{\"a\"",
+			"p/my-pl-main-0-2.ndjson.inprogress":   "{\"a\"",
+			"p/my-pl-main-0-1-1.ndjson.inprogress": "{\"a\":1}\n", // step "main-0"
+		} {
+			assert.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+		}
+		s, err := New(ctx, secretInterface, "my-sink", "my-urn", dir, "my-pl-main-0", dfv1.FileSink{})
+		assert.NoError(t, err)
+		assert.NoError(t, s.(*fileSink).Close())
+		assert.Equal(t, []string{"p/my-pl-main-0-1-1.ndjson.inprogress", "p/my-pl-main-0-1.ndjson"}, files(t, dir))
+		data, err := os.ReadFile(filepath.Join(dir, "p/my-pl-main-0-1.ndjson"))
+		assert.NoError(t, err)
+		assert.Equal(t, "{\"a\":1}\n", string(data), "truncated to the last complete line")
+	})
+	t.Run("Parquet", func(t *testing.T) {
+		dir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, "my-pl-main-0-1.parquet.inprogress"), []byte("PAR1"), 0o644))
+		s, err := New(ctx, secretInterface, "my-sink", "my-urn", dir, "my-pl-main-0", dfv1.FileSink{
+			Format:        dfv1.FileFormatParquet,
+			ParquetSchema: `{"Tag": "name=parquet_go_root", "Fields": [{"Tag": "name=a, type=INT64"}]}`,
+		})
+		assert.NoError(t, err)
+		assert.Empty(t, files(t, dir), "incomplete files are removed")
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a": 1}`)))
+		assert.Error(t, s.Sink(ctx, []byte(`{"a": "x"}`)))
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a": 2}`)))
+		assert.NoError(t, s.(*fileSink).Close())
+		names := files(t, dir)
+		if assert.Len(t, names, 1) {
+			assert.Regexp(t, `^my-pl-main-0-[0-9]+\.parquet$`, names[0])
+			data, err := os.ReadFile(filepath.Join(dir, names[0]))
+			assert.NoError(t, err)
+			assert.Equal(t, "PAR1", string(data[:4]))
+			assert.Equal(t, "PAR1", string(data[len(data)-4:]), "the footer is written")
+		}
+	})
+	t.Run("Partition", func(t *testing.T) {
+		s := &fileSink{}
+		for expression, want := range map[string]string{
+			`"a/b"`:      "a/b",
+			`"../../a"`:  "a",
+			`"/a/../.."`: "",
+		} {
+			prog, err := expr.Compile(expression)
+			assert.NoError(t, err)
+			s.partition = prog
+			got, err := s.partitionOf(ctx, nil)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got, expression)
+		}
+	})
+}
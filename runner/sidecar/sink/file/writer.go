@@ -0,0 +1,67 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/xitongsys/parquet-go/marshal"
+	parquetwriter "github.com/xitongsys/parquet-go/writer"
+)
+
+type writer interface {
+	// write writes the message, returning the number of bytes it counts towards the file's size
+	write(msg []byte) (int, error)
+	// finish writes anything buffered, e.g. Parquet's footer, it does not close the file
+	finish() error
+}
+
+func newWriter(x dfv1.FileSink, w io.Writer) (writer, error) {
+	switch x.GetFormat() {
+	case dfv1.FileFormatNDJSON:
+		return &ndjsonWriter{w: w}, nil
+	case dfv1.FileFormatParquet:
+		pw, err := parquetwriter.NewJSONWriterFromWriter(x.ParquetSchema, w, 1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Parquet writer: %w", err)
+		}
+		return &parquetWriter{pw}, nil
+	default:
+		return nil, fmt.Errorf("format %q not supported", x.Format)
+	}
+}
+
+type ndjsonWriter struct {
+	w   io.Writer
+	buf bytes.Buffer
+}
+
+// write writes the message as a single line, in a single write, so a crash cannot leave a partial line followed by
+// another message
+func (w *ndjsonWriter) write(msg []byte) (int, error) {
+	w.buf.Reset()
+	if err := json.Compact(&w.buf, msg); err != nil {
+		return 0, fmt.Errorf("message is not JSON: %w", err)
+	}
+	w.buf.WriteByte('\n')
+	return w.w.Write(w.buf.Bytes())
+}
+
+func (w *ndjsonWriter) finish() error { return nil }
+
+type parquetWriter struct {
+	pw *parquetwriter.JSONWriter
+}
+
+// write buffers the message. Messages are marshalled when a page is flushed, so it is marshalled on its own first,
+// otherwise a message that does not match the schema would fail the messages buffered with it.
+func (w *parquetWriter) write(msg []byte) (int, error) {
+	if _, err := marshal.MarshalJSON([]interface{}{string(msg)}, w.pw.SchemaHandler); err != nil {
+		return 0, fmt.Errorf("message does not match the Parquet schema: %w", err)
+	}
+	return len(msg), w.pw.Write(string(msg))
+}
+
+func (w *parquetWriter) finish() error { return w.pw.WriteStop() }
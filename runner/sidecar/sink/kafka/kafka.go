@@ -20,10 +20,11 @@ import (
 var logger = sharedutil.NewLogger()
 
 type kafkaSink struct {
-	sinkName string
-	producer *kafka.Producer
-	topic    string
-	async    bool
+	sinkName      string
+	producer      *kafka.Producer
+	topic         string
+	async         bool
+	transactional bool
 }
 
 func New(ctx context.Context, sinkName string, secretInterface corev1.SecretInterface, x dfv1.KafkaSink, errorsCounter prometheus.Counter) (sink.Interface, error) {
@@ -73,7 +74,13 @@ func New(ctx context.Context, sinkName string, secretInterface corev1.SecretInte
 		}
 	}, time.Second, 1.2, true)
 
-	return &kafkaSink{sinkName, producer, x.Topic, x.Async}, nil
+	return &kafkaSink{sinkName: sinkName, producer: producer, topic: x.Topic, async: x.Async}, nil
+}
+
+// NewTransactional returns a sink that produces messages in the transactional producer's current transaction. The
+// producer is shared, and is not closed by the sink.
+func NewTransactional(sinkName string, producer *kafka.Producer, x dfv1.KafkaSink) sink.Interface {
+	return &kafkaSink{sinkName: sinkName, producer: producer, topic: x.Topic, transactional: true}
 }
 
 func (h *kafkaSink) Sink(ctx context.Context, msg []byte) error {
@@ -84,7 +91,8 @@ func (h *kafkaSink) Sink(ctx context.Context, msg []byte) error {
 		return err
 	}
 	var deliveryChan chan kafka.Event
-	if !h.async {
+	// messages produced in a transaction are delivered when the transaction is committed
+	if !h.async && !h.transactional {
 		deliveryChan = make(chan kafka.Event)
 		defer close(deliveryChan)
 	}
@@ -115,6 +123,9 @@ func (h *kafkaSink) Sink(ctx context.Context, msg []byte) error {
 }
 
 func (h *kafkaSink) Close() error {
+	if h.transactional {
+		return nil
+	}
 	logger.Info("flushing producer")
 	unflushedMessages := h.producer.Flush(15 * 1000)
 	if unflushedMessages > 0 {
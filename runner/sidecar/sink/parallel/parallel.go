@@ -0,0 +1,132 @@
+package parallel
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+var logger = sharedutil.NewLogger()
+
+type item struct {
+	ctx  context.Context
+	msg  []byte
+	span opentracing.Span
+	done chan error
+}
+
+type parallelSink struct {
+	sinkName string
+	sink     sink.Interface
+	prog     *vm.Program
+	queues   []chan item
+	next     uint64
+	wg       sync.WaitGroup
+}
+
+// New wraps a sink with a pool of workers. Each worker has its own bounded queue, so a slow sink applies back-pressure
+// rather than accepting an unbounded number of messages. Each write waits for its worker to write the message, so
+// errors are returned to the source, and the message is only acknowledged once it is written. If an ordering key
+// expression is given, messages with the same key are always written by the same worker, and are therefore written in
+// order.
+func New(sinkName string, s sink.Interface, parallelism int, orderingKey string) (sink.Interface, error) {
+	if parallelism < 1 {
+		return nil, fmt.Errorf("parallelism must be greater than zero")
+	}
+	var prog *vm.Program
+	if orderingKey != "" {
+		var err error
+		prog, err = expr.Compile(orderingKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compile %q: %w", orderingKey, err)
+		}
+	}
+	p := &parallelSink{
+		sinkName: sinkName,
+		sink:     s,
+		prog:     prog,
+		queues:   make([]chan item, parallelism),
+	}
+	for i := range p.queues {
+		queue := make(chan item, parallelism)
+		p.queues[i] = queue
+		p.wg.Add(1)
+		go func() {
+			defer runtime.HandleCrash()
+			defer p.wg.Done()
+			for x := range queue {
+				err := p.sink.Sink(x.ctx, x.msg)
+				x.span.Finish()
+				x.done <- err
+			}
+		}()
+	}
+	return p, nil
+}
+
+func (p *parallelSink) Sink(ctx context.Context, msg []byte) error {
+	i, err := p.queueIndex(ctx, msg)
+	if err != nil {
+		return err
+	}
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("parallel-sink-%s", p.sinkName))
+	x := item{ctx: ctx, msg: msg, span: span, done: make(chan error, 1)}
+	select {
+	case <-ctx.Done():
+		span.Finish()
+		return ctx.Err()
+	case p.queues[i] <- x:
+	}
+	// the worker finishes the span once it has written the message
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-x.done:
+		return err
+	}
+}
+
+func (p *parallelSink) queueIndex(ctx context.Context, msg []byte) (int, error) {
+	n := uint64(len(p.queues))
+	if p.prog == nil {
+		return int(atomic.AddUint64(&p.next, 1) % n), nil
+	}
+	env, err := util.ExprEnv(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create expr env: %w", err)
+	}
+	res, err := expr.Run(p.prog, env)
+	if err != nil {
+		return 0, fmt.Errorf("failed to run program: %w", err)
+	}
+	key, ok := res.(string)
+	if !ok {
+		return 0, fmt.Errorf("ordering key expression must return a string")
+	}
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return int(uint64(h.Sum32()) % n), nil
+}
+
+// Close waits for queued messages to be written, then closes the underlying sink if it is a closer.
+func (p *parallelSink) Close() error {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+	if closer, ok := p.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package parallel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingSink struct {
+	mu   sync.Mutex
+	msgs []string
+}
+
+func (r *recordingSink) Sink(_ context.Context, msg []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.msgs = append(r.msgs, string(msg))
+	return nil
+}
+
+type errorSink struct{}
+
+func (errorSink) Sink(context.Context, []byte) error { return errors.New("failed") }
+
+type blockingSink struct{}
+
+func (blockingSink) Sink(ctx context.Context, _ []byte) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestNew(t *testing.T) {
+	t.Run("InvalidParallelism", func(t *testing.T) {
+		_, err := New("my-sink", &recordingSink{}, 0, "")
+		assert.Error(t, err)
+	})
+	t.Run("InvalidOrderingKey", func(t *testing.T) {
+		_, err := New("my-sink", &recordingSink{}, 1, "(")
+		assert.Error(t, err)
+	})
+	t.Run("OrderingKey", func(t *testing.T) {
+		r := &recordingSink{}
+		s, err := New("my-sink", r, 4, `"same"`)
+		assert.NoError(t, err)
+		ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "1"})
+		for _, msg := range []string{"a", "b", "c", "d"} {
+			assert.NoError(t, s.Sink(ctx, []byte(msg)))
+		}
+		assert.NoError(t, s.(*parallelSink).Close())
+		assert.Equal(t, []string{"a", "b", "c", "d"}, r.msgs)
+	})
+	t.Run("Error", func(t *testing.T) {
+		s, err := New("my-sink", errorSink{}, 2, "")
+		assert.NoError(t, err)
+		assert.EqualError(t, s.Sink(context.Background(), []byte("a")), "failed")
+		assert.NoError(t, s.(*parallelSink).Close())
+	})
+	t.Run("Cancelled", func(t *testing.T) {
+		s, err := New("my-sink", blockingSink{}, 1, "")
+		assert.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.Equal(t, context.Canceled, s.Sink(ctx, []byte("a")))
+	})
+}
@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedredis "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/redis"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type redisSink struct {
+	sinkName string
+	channel  string
+	client   *redis.Client
+}
+
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sinkName string, x dfv1.RedisSink) (sink.Interface, error) {
+	client, err := sharedredis.NewClient(ctx, secretInterface, x.URL, x.PasswordSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &redisSink{sinkName: sinkName, channel: x.Channel, client: client}, nil
+}
+
+// Sink publishes the message. Redis does not persist it, so it is lost if there are no subscribers.
+func (r redisSink) Sink(ctx context.Context, msg []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("redis-sink-%s", r.sinkName))
+	defer span.Finish()
+	return r.client.Publish(ctx, r.channel, msg).Err()
+}
+
+func (r redisSink) Close() error {
+	logger.Info("closing redis sink connection")
+	return r.client.Close()
+}
@@ -0,0 +1,51 @@
+package redisstream
+
+import (
+	"context"
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedredis "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/redis"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type redisStreamSink struct {
+	sinkName string
+	client   *redis.Client
+	x        dfv1.RedisStreamSink
+}
+
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sinkName string, x dfv1.RedisStreamSink) (sink.Interface, error) {
+	client, err := sharedredis.NewClient(ctx, secretInterface, x.URL, x.PasswordSecret)
+	if err != nil {
+		return nil, err
+	}
+	return &redisStreamSink{sinkName: sinkName, client: client, x: x}, nil
+}
+
+// Sink adds the message to the stream, as the value of the entry's field, trimming the stream if it has a max length.
+func (r redisStreamSink) Sink(ctx context.Context, msg []byte) error {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("redis-stream-sink-%s", r.sinkName))
+	defer span.Finish()
+	return r.client.XAdd(ctx, argsOf(r.x, msg)).Err()
+}
+
+func argsOf(x dfv1.RedisStreamSink, msg []byte) *redis.XAddArgs {
+	return &redis.XAddArgs{
+		Stream: x.Stream,
+		MaxLen: int64(x.MaxLen),
+		Approx: x.MaxLen > 0, // trimming exactly is much slower
+		Values: map[string]interface{}{x.GetField(): msg},
+	}
+}
+
+func (r redisStreamSink) Close() error {
+	logger.Info("closing redis stream sink connection")
+	return r.client.Close()
+}
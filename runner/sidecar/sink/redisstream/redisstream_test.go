@@ -0,0 +1,20 @@
+package redisstream
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_argsOf(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		x := dfv1.RedisStreamSink{RedisStream: dfv1.RedisStream{Stream: "my-stream"}}
+		assert.Equal(t, &redis.XAddArgs{Stream: "my-stream", Values: map[string]interface{}{"message": []byte("my-msg")}}, argsOf(x, []byte("my-msg")))
+	})
+	t.Run("MaxLen", func(t *testing.T) {
+		x := dfv1.RedisStreamSink{RedisStream: dfv1.RedisStream{Stream: "my-stream", Field: "data"}, MaxLen: 1000}
+		assert.Equal(t, &redis.XAddArgs{Stream: "my-stream", MaxLen: 1000, Approx: true, Values: map[string]interface{}{"data": []byte("my-msg")}}, argsOf(x, []byte("my-msg")))
+	})
+}
@@ -0,0 +1,215 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	"github.com/opentracing/opentracing-go"
+	_ "github.com/snowflakedb/gosnowflake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type exec func(ctx context.Context, query string, args ...interface{}) error
+
+// row is a message's values, waiting to be inserted
+type row struct {
+	values []interface{}
+	err    chan error
+}
+
+type snowflakeSink struct {
+	sinkName  string
+	table     string
+	columns   []dfv1.SnowflakeColumn
+	progs     []*vm.Program
+	batchSize int
+	exec      exec
+	close     func() error
+	rows      chan *row
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// New creates a sink that inserts each message into the table. Rows are inserted by a single goroutine: rows that
+// arrive while a statement is running are inserted together by the next one, so batches grow with the number of
+// concurrent writes, without delaying a lone write.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sinkName string, x dfv1.SnowflakeSink) (sink.Interface, error) {
+	s := x.DSNSecret
+	if s == nil {
+		return nil, fmt.Errorf("dsnSecret is required")
+	}
+	secret, err := secretInterface.Get(ctx, s.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q: %w", s.Name, err)
+	}
+	dsn, ok := secret.Data[s.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in secret %q", s.Key, s.Name)
+	}
+	db, err := sql.Open("snowflake", string(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Snowflake connection: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to connect to Snowflake: %w", err)
+	}
+	return newSink(sinkName, x, func(ctx context.Context, query string, args ...interface{}) error {
+		_, err := db.ExecContext(ctx, query, args...)
+		return err
+	}, db.Close)
+}
+
+func newSink(sinkName string, x dfv1.SnowflakeSink, exec exec, close func() error) (*snowflakeSink, error) {
+	var progs []*vm.Program
+	for _, c := range x.Columns {
+		prog, err := expr.Compile(c.Value)
+		if err != nil {
+			return nil, fmt.Errorf("column %q: failed to compile %q: %w", c.Name, c.Value, err)
+		}
+		progs = append(progs, prog)
+	}
+	s := &snowflakeSink{
+		sinkName:  sinkName,
+		table:     x.Table,
+		columns:   x.Columns,
+		progs:     progs,
+		batchSize: x.GetBatchSize(),
+		exec:      exec,
+		close:     close,
+		rows:      make(chan *row),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.insertRows()
+	return s, nil
+}
+
+func (s *snowflakeSink) Sink(ctx context.Context, msg []byte) error {
+	env, err := util.ExprEnv(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("failed to create expr env: %w", err)
+	}
+	values := make([]interface{}, len(s.columns))
+	for i, c := range s.columns {
+		v, err := expr.Run(s.progs[i], env)
+		if err != nil {
+			return fmt.Errorf("column %q: %w", c.Name, err)
+		}
+		if values[i], err = bindValue(v, c.Variant); err != nil {
+			return fmt.Errorf("column %q: %w", c.Name, err)
+		}
+	}
+	r := &row{values: values, err: make(chan error, 1)}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-s.stop:
+		return fmt.Errorf("sink %q is closed", s.sinkName)
+	case s.rows <- r:
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-r.err:
+		return err
+	}
+}
+
+// bindValue returns a value the driver can bind: bytes become strings, and values of variant columns become JSON.
+func bindValue(v interface{}, variant bool) (interface{}, error) {
+	switch x := v.(type) {
+	case []byte:
+		return string(x), nil
+	case string, nil:
+		return x, nil
+	}
+	if !variant {
+		return v, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (s *snowflakeSink) insertRows() {
+	defer runtime.HandleCrash()
+	defer close(s.done)
+	for {
+		var batch []*row
+		select {
+		case <-s.stop:
+			return
+		case r := <-s.rows:
+			batch = append(batch, r)
+		}
+	more:
+		for len(batch) < s.batchSize {
+			select {
+			case r := <-s.rows:
+				batch = append(batch, r)
+			default:
+				break more
+			}
+		}
+		err := s.insert(batch)
+		for _, r := range batch {
+			r.err <- err
+		}
+	}
+}
+
+func (s *snowflakeSink) insert(batch []*row) error {
+	// the batch's rows are written by different callers, so the statement is not cancelled if one of them gives up
+	span, ctx := opentracing.StartSpanFromContext(context.Background(), fmt.Sprintf("snowflake-sink-%s", s.sinkName))
+	defer span.Finish()
+	var args []interface{}
+	for _, r := range batch {
+		args = append(args, r.values...)
+	}
+	query := insertStatement(s.table, s.columns, len(batch))
+	if err := s.exec(ctx, query, args...); err != nil {
+		return fmt.Errorf("failed to insert %d rows into %q: %w", len(batch), s.table, err)
+	}
+	return nil
+}
+
+// insertStatement returns a statement that inserts n rows. It selects from VALUES, rather than inserting them
+// directly, as variant columns must be parsed using PARSE_JSON, which cannot be used in VALUES.
+func insertStatement(table string, columns []dfv1.SnowflakeColumn, n int) string {
+	names := make([]string, len(columns))
+	selects := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, c := range columns {
+		names[i] = c.Name
+		selects[i] = fmt.Sprintf("column%d", i+1)
+		if c.Variant {
+			selects[i] = fmt.Sprintf("PARSE_JSON(column%d)", i+1)
+		}
+		placeholders[i] = "?"
+	}
+	values := make([]string, n)
+	for i := range values {
+		values[i] = "(" + strings.Join(placeholders, ", ") + ")"
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM VALUES %s", table, strings.Join(names, ", "), strings.Join(selects, ", "), strings.Join(values, ", "))
+}
+
+// Close stops inserting rows, once the statement being run is done, and closes the connection.
+func (s *snowflakeSink) Close() error {
+	close(s.stop)
+	<-s.done
+	return s.close()
+}
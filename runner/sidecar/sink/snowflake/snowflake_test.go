@@ -0,0 +1,108 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+var columns = []dfv1.SnowflakeColumn{
+	{Name: "id", Value: "string(msg)"},
+	{Name: "payload", Value: "object(msg)", Variant: true},
+}
+
+func Test_insertStatement(t *testing.T) {
+	assert.Equal(t,
+		"INSERT INTO events (id, payload) SELECT column1, PARSE_JSON(column2) FROM VALUES (?, ?), (?, ?)",
+		insertStatement("events", columns, 2),
+	)
+}
+
+func Test_bindValue(t *testing.T) {
+	for _, tt := range []struct {
+		v       interface{}
+		variant bool
+		want    interface{}
+	}{
+		{[]byte("foo"), false, "foo"},
+		{"foo", true, "foo"},
+		{nil, true, nil},
+		{1, false, 1},
+		{map[string]interface{}{"a": 1}, true, `{"a":1}`},
+	} {
+		got, err := bindValue(tt.v, tt.variant)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestSnowflakeSink(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id"})
+	t.Run("Insert", func(t *testing.T) {
+		var query string
+		var args []interface{}
+		s, err := newSink("my-sink", dfv1.SnowflakeSink{Table: "events", Columns: columns}, func(ctx context.Context, q string, a ...interface{}) error {
+			query, args = q, a
+			return nil
+		}, func() error { return nil })
+		assert.NoError(t, err)
+		assert.NoError(t, s.Sink(ctx, []byte(`{"a":1}`)))
+		assert.NoError(t, s.Close())
+		assert.Equal(t, "INSERT INTO events (id, payload) SELECT column1, PARSE_JSON(column2) FROM VALUES (?, ?)", query)
+		assert.Equal(t, []interface{}{`{"a":1}`, `{"a":1}`}, args)
+	})
+	t.Run("Batch", func(t *testing.T) {
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var mu sync.Mutex
+		var batches []int
+		s, err := newSink("my-sink", dfv1.SnowflakeSink{Table: "events", Columns: columns, BatchSize: 2}, func(ctx context.Context, q string, a ...interface{}) error {
+			mu.Lock()
+			batches = append(batches, len(a)/len(columns))
+			first := len(batches) == 1
+			mu.Unlock()
+			if first {
+				close(started)
+				<-release
+			}
+			return nil
+		}, func() error { return nil })
+		assert.NoError(t, err)
+		var wg sync.WaitGroup
+		sink := func(i int) {
+			defer wg.Done()
+			assert.NoError(t, s.Sink(ctx, []byte(fmt.Sprintf(`{"i":%d}`, i))))
+		}
+		wg.Add(1)
+		go sink(0)
+		<-started
+		// these rows queue up while the first statement is running
+		for i := 1; i <= 4; i++ {
+			wg.Add(1)
+			go sink(i)
+		}
+		time.Sleep(100 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.NoError(t, s.Close())
+		assert.Equal(t, []int{1, 2, 2}, batches)
+	})
+	t.Run("Error", func(t *testing.T) {
+		s, err := newSink("my-sink", dfv1.SnowflakeSink{Table: "events", Columns: columns}, func(ctx context.Context, q string, a ...interface{}) error {
+			return fmt.Errorf("table does not exist")
+		}, func() error { return nil })
+		assert.NoError(t, err)
+		assert.EqualError(t, s.Sink(ctx, []byte(`{}`)), `failed to insert 1 rows into "events": table does not exist`)
+		assert.NoError(t, s.Close())
+		assert.EqualError(t, s.Sink(ctx, []byte(`{}`)), `sink "my-sink" is closed`)
+	})
+	t.Run("CompileError", func(t *testing.T) {
+		_, err := newSink("my-sink", dfv1.SnowflakeSink{Columns: []dfv1.SnowflakeColumn{{Name: "id", Value: "("}}}, nil, nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"sync"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	"github.com/opentracing/opentracing-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type testSink struct {
+	sinkName string
+	capacity int
+	mu       sync.Mutex
+	msgs     [][]byte
+}
+
+// New creates a sink that keeps messages in memory. They are returned, each followed by a new line, by GETting
+// `/test/sinks/{sinkName}`, and cleared by DELETE-ing it, presenting the sink's bearer token.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sinkName string, x dfv1.TestSink) (sink.Interface, error) {
+	secret, err := secretInterface.Get(ctx, pipelineName+"-"+stepName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q: %w", stepName, err)
+	}
+	// sinks added after the step was created do not have an authorization
+	authorization := secret.Data[fmt.Sprintf("sinks.%s.test.authorization", sinkName)]
+	s := &testSink{sinkName: sinkName, capacity: x.GetCapacity()}
+	http.HandleFunc("/test/sinks/"+sinkName, func(w http.ResponseWriter, r *http.Request) {
+		if len(authorization) == 0 || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), authorization) != 1 {
+			w.WriteHeader(403)
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			msgs := s.messages()
+			if len(msgs) == 0 {
+				w.WriteHeader(204)
+				return
+			}
+			w.WriteHeader(200)
+			for _, m := range msgs {
+				_, _ = w.Write(m)
+				_, _ = w.Write([]byte("\n"))
+			}
+		case http.MethodDelete:
+			s.clear()
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(405)
+		}
+	})
+	return s, nil
+}
+
+func (s *testSink) Sink(ctx context.Context, msg []byte) error {
+	span, _ := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("test-sink-%s", s.sinkName))
+	defer span.Finish()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = append(s.msgs, append([]byte(nil), msg...))
+	if n := len(s.msgs) - s.capacity; n > 0 {
+		s.msgs = s.msgs[n:]
+	}
+	return nil
+}
+
+func (s *testSink) messages() [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([][]byte(nil), s.msgs...)
+}
+
+func (s *testSink) clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.msgs = nil
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTestSink(t *testing.T) {
+	secretInterface := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pl-my-step"},
+		Data:       map[string][]byte{"sinks.my-sink.test.authorization": []byte("Bearer my-token")},
+	}).CoreV1().Secrets("")
+	s, err := New(context.Background(), secretInterface, "my-pl", "my-step", "my-sink", dfv1.TestSink{Capacity: 2})
+	assert.NoError(t, err)
+	do := func(method, authorization string) (int, string) {
+		r := httptest.NewRequest(method, "/test/sinks/my-sink", nil)
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(w, r)
+		return w.Code, w.Body.String()
+	}
+	code, _ := do("GET", "Bearer other")
+	assert.Equal(t, 403, code)
+	code, _ = do("GET", "Bearer my-token")
+	assert.Equal(t, 204, code)
+	for _, msg := range []string{"my-msg-0", "my-msg-1", "my-msg-2"} {
+		assert.NoError(t, s.Sink(context.Background(), []byte(msg)))
+	}
+	code, body := do("GET", "Bearer my-token")
+	assert.Equal(t, 200, code)
+	assert.Equal(t, "my-msg-1\nmy-msg-2\n", body, "the oldest message is dropped")
+	code, _ = do("DELETE", "Bearer my-token")
+	assert.Equal(t, 204, code)
+	code, _ = do("GET", "Bearer my-token")
+	assert.Equal(t, 204, code)
+	code, _ = do("POST", "Bearer my-token")
+	assert.Equal(t, 405, code)
+}
@@ -0,0 +1,58 @@
+package timeout
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+type timeoutSink struct {
+	sinkName        string
+	sink            sink.Interface
+	timeout         time.Duration
+	timeoutsCounter prometheus.Counter
+}
+
+// New wraps a sink so that each write is given a deadline. Sinks that honour the context (e.g. HTTP and Kafka) stop
+// waiting at the deadline. For other sinks, the write continues in the background, but the message is returned to
+// its source as an error, so an unresponsive sink cannot block the step indefinitely.
+func New(sinkName string, s sink.Interface, timeout time.Duration, timeoutsCounter prometheus.Counter) (sink.Interface, error) {
+	if timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be greater than zero")
+	}
+	return &timeoutSink{sinkName: sinkName, sink: s, timeout: timeout, timeoutsCounter: timeoutsCounter}, nil
+}
+
+func (t *timeoutSink) Sink(ctx context.Context, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+	errs := make(chan error, 1)
+	go func() {
+		defer runtime.HandleCrash()
+		errs <- t.sink.Sink(ctx, msg)
+	}()
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil && ctx.Err() == context.DeadlineExceeded {
+		t.timeoutsCounter.Inc()
+		return fmt.Errorf("timed out after %v writing to sink %q", t.timeout, t.sinkName)
+	}
+	return err
+}
+
+// Close closes the underlying sink if it is a closer.
+func (t *timeoutSink) Close() error {
+	if closer, ok := t.sink.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package timeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type sleepingSink struct {
+	sleep     time.Duration
+	honourCtx bool
+}
+
+func (s *sleepingSink) Sink(ctx context.Context, _ []byte) error {
+	if s.honourCtx {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(s.sleep):
+			return nil
+		}
+	}
+	time.Sleep(s.sleep)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	_, err := New("my-sink", &sleepingSink{}, 0, prometheus.NewCounter(prometheus.CounterOpts{}))
+	assert.Error(t, err)
+}
+
+func TestTimeoutSink_Sink(t *testing.T) {
+	t.Run("InTime", func(t *testing.T) {
+		timeouts := prometheus.NewCounter(prometheus.CounterOpts{})
+		s, err := New("my-sink", &sleepingSink{}, time.Second, timeouts)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Sink(context.Background(), []byte("foo")))
+		assert.Equal(t, float64(0), testutil.ToFloat64(timeouts))
+	})
+	t.Run("HonoursContext", func(t *testing.T) {
+		timeouts := prometheus.NewCounter(prometheus.CounterOpts{})
+		x := &sleepingSink{sleep: time.Minute, honourCtx: true}
+		s, err := New("my-sink", x, 10*time.Millisecond, timeouts)
+		assert.NoError(t, err)
+		assert.EqualError(t, s.Sink(context.Background(), []byte("foo")), `timed out after 10ms writing to sink "my-sink"`)
+		assert.Equal(t, float64(1), testutil.ToFloat64(timeouts))
+	})
+	t.Run("IgnoresContext", func(t *testing.T) {
+		timeouts := prometheus.NewCounter(prometheus.CounterOpts{})
+		s, err := New("my-sink", &sleepingSink{sleep: time.Second}, 10*time.Millisecond, timeouts)
+		assert.NoError(t, err)
+		start := time.Now()
+		assert.Error(t, s.Sink(context.Background(), []byte("foo")))
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, float64(1), testutil.ToFloat64(timeouts))
+	})
+}
@@ -2,26 +2,49 @@ package sidecar
 
 import (
 	"context"
+	"crypto/cipher"
 	"fmt"
 	"io"
+	"path/filepath"
 
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/codec"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/encryption"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink"
+	amqpsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/amqp"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/buffer"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/cloudevents"
+	codecsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/codec"
+	daprsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/dapr"
 	dbsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/db"
+	dedupesink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/dedupe"
+	filesink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/file"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/http"
 	jssink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/jetstream"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/kafka"
 	logsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/log"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/parallel"
+	redissink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/redis"
+	redisstreamsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/redisstream"
 	s3sink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/s3"
+	snowflakesink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/snowflake"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/stan"
+	testsink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/test"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/timeout"
 	volumesink "github.com/argoproj-labs/argo-dataflow/runner/sidecar/sink/volume"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 )
 
-func connectSinks(ctx context.Context) (func(context.Context, []byte) error, func(context.Context, []byte) error, error) {
+func connectSinks(ctx context.Context) (func(context.Context, []byte) error, func(context.Context, []byte) error, func(context.Context, []byte) error, func(context.Context, []byte) error, error) {
 	sinks := map[string]sink.Interface{}
 	dlqSlink := map[string]sink.Interface{}
+	auditSinkName := ""
+	var auditSink sink.Interface
+	lateSinkName := ""
+	var lateSink sink.Interface
 	totalCounter := promauto.NewCounterVec(prometheus.CounterOpts{
 		Subsystem: "sinks",
 		Name:      "total",
@@ -32,6 +55,28 @@ func connectSinks(ctx context.Context) (func(context.Context, []byte) error, fun
 		Name:      "errors",
 		Help:      "Total number of errors, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sinks_errors",
 	}, []string{"sinkName", "replica", "dlq"})
+	bufferBytesGauge := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "sinks",
+		Name:      "buffer_bytes",
+		Help:      "Number of bytes buffered, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sinks_buffer_bytes",
+	}, []string{"sinkName", "replica"})
+	timeoutsCounter := promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sinks",
+		Name:      "timeouts",
+		Help:      "Total number of writes that timed out, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sinks_timeouts",
+	}, []string{"sinkName", "replica"})
+	duplicatesCounter := promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sinks",
+		Name:      "duplicates",
+		Help:      "Total number of messages not written as they already were, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sinks_duplicates",
+	}, []string{"sinkName", "replica"})
+
+	if err := createKafkaTopics(ctx); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	if err := connectTransactionalProducer(ctx); err != nil {
+		return nil, nil, nil, nil, err
+	}
 
 	for _, s := range step.Spec.Sinks {
 		logger.Info("connecting sink", "sink", sharedutil.MustJSON(s))
@@ -39,49 +84,158 @@ func connectSinks(ctx context.Context) (func(context.Context, []byte) error, fun
 		var err error
 		var sink sink.Interface
 		if _, exists := sinks[sinkName]; exists {
-			return nil, nil, fmt.Errorf("duplicate sink named %q", sinkName)
+			return nil, nil, nil, nil, fmt.Errorf("duplicate sink named %q", sinkName)
 		}
 		if x := s.STAN; x != nil {
-			if sink, err = stan.New(ctx, secretInterface, namespace, pipelineName, stepName, replica, sinkName, *x); err != nil {
-				return nil, nil, err
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = stan.New(ctx, secretInterface, namespace, pipelineName, stepName, replica, sinkName, *x)
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
 			}
+		} else if x := s.Kafka; x != nil && transactionalProducer != nil {
+			sink = kafka.NewTransactional(sinkName, transactionalProducer, *x)
 		} else if x := s.Kafka; x != nil {
-			if sink, err = kafka.New(ctx, sinkName, secretInterface, *x, errorsCounter.WithLabelValues(sinkName, fmt.Sprint(replica), fmt.Sprint(s.DeadLetterQueue))); err != nil {
-				return nil, nil, err
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = kafka.New(ctx, sinkName, secretInterface, *x, errorsCounter.WithLabelValues(sinkName, fmt.Sprint(replica), fmt.Sprint(s.DeadLetterQueue)))
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
 			}
 		} else if x := s.Log; x != nil {
 			sink = logsink.New(sinkName, *x)
 		} else if x := s.HTTP; x != nil {
 			if sink, err = http.New(ctx, sinkName, secretInterface, *x); err != nil {
-				return nil, nil, err
+				return nil, nil, nil, nil, err
+			}
+			if x.Dedupe {
+				logger.Info("adding dedupe", "sink", sinkName)
+				store, err := state.NewFromEnv(ctx, "sink-"+sinkName)
+				if err != nil {
+					return nil, nil, nil, nil, fmt.Errorf("failed to create state store for sink %q: %w", sinkName, err)
+				}
+				sink = dedupesink.New(sinkName, sink, store, duplicatesCounter.WithLabelValues(sinkName, fmt.Sprint(replica)))
 			}
 		} else if x := s.S3; x != nil {
 			if sink, err = s3sink.New(ctx, sinkName, secretInterface, *x); err != nil {
-				return nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		} else if x := s.DB; x != nil {
-			if sink, err = dbsink.New(ctx, sinkName, secretInterface, *x); err != nil {
-				return nil, nil, err
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = dbsink.New(ctx, sinkName, secretInterface, *x)
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
 			}
 		} else if x := s.Volume; x != nil {
 			if sink, err = volumesink.New(sinkName); err != nil {
-				return nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		} else if x := s.JetStream; x != nil {
-			if sink, err = jssink.New(ctx, secretInterface, namespace, pipelineName, stepName, replica, sinkName, *x); err != nil {
-				return nil, nil, err
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = jssink.New(ctx, secretInterface, namespace, pipelineName, stepName, replica, sinkName, *x)
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.CloudEvents; x != nil {
+			if sink, err = cloudevents.New(sinkName, namespace, *x); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.Dapr; x != nil {
+			if sink, err = daprsink.New(sinkName, *x); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.Test; x != nil {
+			if sink, err = testsink.New(ctx, secretInterface, pipelineName, stepName, sinkName, *x); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.Redis; x != nil {
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = redissink.New(ctx, secretInterface, sinkName, *x)
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.RedisStream; x != nil {
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = redisstreamsink.New(ctx, secretInterface, sinkName, *x)
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.Snowflake; x != nil {
+			if sink, err = snowflakesink.New(ctx, secretInterface, sinkName, *x); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.File; x != nil {
+			dir := filepath.Join(dfv1.PathVarRun, "files", sinkName)
+			prefix := fmt.Sprintf("%s-%s-%d", pipelineName, stepName, replica)
+			if sink, err = filesink.New(ctx, secretInterface, sinkName, x.Volume.GenURN(cluster, namespace), dir, prefix, *x); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		} else if x := s.AMQP; x != nil {
+			if err = connectWithRetry(ctx, fmt.Sprintf("sink %q", sinkName), func() (err error) {
+				sink, err = amqpsink.New(ctx, secretInterface, sinkName, *x)
+				return err
+			}); err != nil {
+				return nil, nil, nil, nil, err
 			}
 		} else {
-			return nil, nil, fmt.Errorf("sink misconfigured")
+			return nil, nil, nil, nil, fmt.Errorf("sink misconfigured")
+		}
+
+		if x := s.Codec; x != nil {
+			logger.Info("adding codec", "sink", sinkName)
+			encoder, err := codec.New(*x)
+			if err != nil {
+				return nil, nil, nil, nil, fmt.Errorf("failed to create codec for sink %q: %w", sinkName, err)
+			}
+			sink = codecsink.New(sinkName, sink, encoder)
+		}
+
+		if x := s.Timeout; x != nil {
+			logger.Info("adding timeout", "sink", sinkName, "timeout", x.Duration.String())
+			if sink, err = timeout.New(sinkName, sink, x.Duration, timeoutsCounter.WithLabelValues(sinkName, fmt.Sprint(replica))); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		}
+
+		if x := s.Buffer; x != nil {
+			dir := filepath.Join(dfv1.PathVarRun, "buffers", sinkName)
+			logger.Info("adding buffer", "sink", sinkName, "dir", dir, "maxSize", x.MaxSize.String(), "encrypted", x.Encryption != nil)
+			var aead cipher.AEAD
+			if e := x.Encryption; e != nil {
+				if aead, err = encryption.New(ctx, secretInterface, *e); err != nil {
+					return nil, nil, nil, nil, err
+				}
+			}
+			if sink, err = buffer.New(ctx, sinkName, sink, dir, x.MaxSize.Value(), bufferBytesGauge.WithLabelValues(sinkName, fmt.Sprint(replica)), aead); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		}
+
+		if s.Parallelism > 0 {
+			logger.Info("adding parallel workers", "sink", sinkName, "parallelism", s.Parallelism, "orderingKey", s.OrderingKey)
+			if sink, err = parallel.New(sinkName, sink, int(s.Parallelism), s.OrderingKey); err != nil {
+				return nil, nil, nil, nil, err
+			}
 		}
 
 		if s.DeadLetterQueue {
 			logger.Info("adding DLQ sink", "sink", sinkName)
 			dlqSlink[sinkName] = sink
+		} else if x := step.Spec.Audit; x != nil && x.Sink == sinkName {
+			logger.Info("adding audit sink", "sink", sinkName)
+			auditSinkName, auditSink = sinkName, sink
+		} else if x := step.Spec.GetLateness(); x.GetPolicy() == dfv1.LateSink && x.Sink == sinkName {
+			logger.Info("adding late sink", "sink", sinkName)
+			lateSinkName, lateSink = sinkName, sink
 		} else {
 			sinks[sinkName] = sink
 		}
-		if closer, ok := sinks[sinkName].(io.Closer); ok {
+		// every sink is closed, including DLQ, audit and late sinks, so that buffered or queued messages are written
+		if closer, ok := sink.(io.Closer); ok {
 			logger.Info("adding stop hook", "sink", sinkName)
 			addStopHook(func(ctx context.Context) error {
 				logger.Info("closing", "sink", sinkName)
@@ -108,5 +262,25 @@ func connectSinks(ctx context.Context) (func(context.Context, []byte) error, fun
 				}
 			}
 			return nil
+		}, func(ctx context.Context, msg []byte) error {
+			if auditSink == nil {
+				return nil
+			}
+			totalCounter.WithLabelValues(auditSinkName, fmt.Sprint(replica), "false").Inc()
+			if err := auditSink.Sink(ctx, msg); err != nil {
+				errorsCounter.WithLabelValues(auditSinkName, fmt.Sprint(replica), "false").Inc()
+				return err
+			}
+			return nil
+		}, func(ctx context.Context, msg []byte) error {
+			if lateSink == nil {
+				return nil
+			}
+			totalCounter.WithLabelValues(lateSinkName, fmt.Sprint(replica), "false").Inc()
+			if err := lateSink.Sink(ctx, msg); err != nil {
+				errorsCounter.WithLabelValues(lateSinkName, fmt.Sprint(replica), "false").Inc()
+				return err
+			}
+			return nil
 		}, nil
 }
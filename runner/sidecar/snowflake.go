@@ -0,0 +1,31 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func snowflakeFromSecret(x *dfv1.SnowflakeSink, secret *corev1.Secret) {
+	if _, ok := secret.Data["dsn"]; ok && x.DSNSecret == nil {
+		x.DSNSecret = &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: secret.Name},
+			Key:                  "dsn",
+		}
+	}
+}
+
+func enrichSnowflake(ctx context.Context, x *dfv1.SnowflakeSink) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-snowflake-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		snowflakeFromSecret(x, secret)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_snowflakeFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-snowflake-default"},
+		Data:       map[string][]byte{"dsn": []byte("user:password@account/db/schema")},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.SnowflakeSink{}
+		snowflakeFromSecret(x, secret)
+		assert.Equal(t, &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-snowflake-default"}, Key: "dsn"}, x.DSNSecret)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		dsnSecret := &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-secret"}, Key: "my-key"}
+		x := &dfv1.SnowflakeSink{DSNSecret: dsnSecret}
+		snowflakeFromSecret(x, secret)
+		assert.Equal(t, dsnSecret, x.DSNSecret)
+	})
+}
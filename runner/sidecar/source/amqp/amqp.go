@@ -0,0 +1,171 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedamqp "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/amqp"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type amqpSource struct {
+	consumer string
+	queue    string
+	mu       sync.Mutex
+	conn     *amqp.Connection // guarded by mu, replaced when the source reconnects
+	ch       *amqp.Channel    // guarded by mu, the consumer's channel
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// New creates a source that consumes from the queue. If the connection is lost, it reconnects, and the broker
+// redelivers the messages that were not acknowledged.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sourceName, sourceURN string, x dfv1.AMQPSource, process source.Process) (source.HasPending, error) {
+	s := &amqpSource{consumer: sourceName, queue: x.Queue, done: make(chan struct{})}
+	connect := func() (<-chan amqp.Delivery, error) {
+		conn, err := sharedamqp.Dial(ctx, secretInterface, x.AMQP)
+		if err != nil {
+			return nil, err
+		}
+		ch, deliveries, err := consume(conn, sourceName, x)
+		if err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+		s.mu.Lock()
+		s.conn, s.ch = conn, ch
+		s.mu.Unlock()
+		return deliveries, nil
+	}
+	deliveries, err := connect()
+	if err != nil {
+		return nil, err
+	}
+	consumeCtx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(s.done)
+		for {
+			// deliveries is closed when the consumer is cancelled, or the connection is lost
+			for d := range deliveries {
+				handle(ctx, sourceName, sourceURN, d, process)
+			}
+			for {
+				select {
+				case <-consumeCtx.Done():
+					return
+				case <-time.After(time.Second):
+				}
+				logger.Info("reconnecting", "source", sourceName)
+				if deliveries, err = connect(); err != nil {
+					logger.Error(err, "failed to reconnect", "source", sourceName)
+				} else if consumeCtx.Err() != nil {
+					return // closed while we were reconnecting
+				} else {
+					break
+				}
+			}
+		}
+	}()
+	return s, nil
+}
+
+func consume(conn *amqp.Connection, sourceName string, x dfv1.AMQPSource) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	ch, err := conn.Channel()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := ch.Qos(x.GetPrefetch(), 0, false); err != nil {
+		return nil, nil, fmt.Errorf("failed to set prefetch: %w", err)
+	}
+	deliveries, err := ch.Consume(x.Queue, sourceName, false, false, false, false, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to consume from queue %q: %w", x.Queue, err)
+	}
+	return ch, deliveries, nil
+}
+
+// handle processes the message, and acknowledges it, or rejects it without re-queuing it if it fails to be processed,
+// as the source has already retried it.
+func handle(ctx context.Context, sourceName, sourceURN string, d amqp.Delivery, process source.Process) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("amqp-source-%s", sourceName))
+	defer span.Finish()
+	id := dfv1.StringOr(d.MessageId, uuid.New().String())
+	t := d.Timestamp
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if err := process(
+		source.ContextWithHeader(dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: id, Time: t.Unix()}), header(d.Headers)),
+		d.Body,
+	); err != nil {
+		logger.Error(err, "failed to process message", "source", sourceName, "id", id)
+		if err := d.Reject(false); err != nil {
+			logger.Error(err, "failed to reject message", "source", sourceName, "id", id)
+		}
+	} else if err := d.Ack(false); err != nil {
+		logger.Error(err, "failed to acknowledge message", "source", sourceName, "id", id)
+	}
+}
+
+// header returns a function that returns the value of one of the headers, formatted if it is not a string.
+func header(headers amqp.Table) func(key string) string {
+	return func(key string) string {
+		switch v := headers[key].(type) {
+		case nil:
+			return ""
+		case string:
+			return v
+		case []byte:
+			return string(v)
+		default:
+			return fmt.Sprint(v)
+		}
+	}
+}
+
+// Close stops consuming, once the message being processed is done. Messages that were delivered, but not processed,
+// are redelivered.
+func (s *amqpSource) Close() error {
+	logger.Info("closing amqp source connection")
+	s.cancel()
+	s.mu.Lock()
+	if err := s.ch.Cancel(s.consumer, false); err != nil {
+		logger.Error(err, "failed to cancel consumer")
+	}
+	s.mu.Unlock()
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.Close()
+}
+
+// GetPending returns the number of messages in the queue that are ready to be delivered.
+func (s *amqpSource) GetPending(context.Context) (uint64, error) {
+	s.mu.Lock()
+	conn := s.conn
+	s.mu.Unlock()
+	// inspecting a queue that does not exist closes the channel, so we do not use the consumer's
+	ch, err := conn.Channel()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = ch.Close() }()
+	q, err := ch.QueueInspect(s.queue)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(q.Messages), nil
+}
@@ -0,0 +1,68 @@
+package amqp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+type acknowledger struct {
+	acked, rejected, requeued bool
+}
+
+func (a *acknowledger) Ack(uint64, bool) error { a.acked = true; return nil }
+
+func (a *acknowledger) Nack(_ uint64, _ bool, requeue bool) error {
+	a.rejected, a.requeued = true, requeue
+	return nil
+}
+
+func (a *acknowledger) Reject(_ uint64, requeue bool) error {
+	a.rejected, a.requeued = true, requeue
+	return nil
+}
+
+func Test_handle(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Processed", func(t *testing.T) {
+		a := &acknowledger{}
+		d := amqp.Delivery{Acknowledger: a, MessageId: "my-id", Timestamp: time.Unix(1, 0), Headers: amqp.Table{"my-header": "my-value"}, Body: []byte("my-msg")}
+		handle(ctx, "my-source", "my-urn", d, func(ctx context.Context, msg []byte) error {
+			m, err := dfv1.MetaFromContext(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, dfv1.Meta{Source: "my-urn", ID: "my-id", Time: 1}, m)
+			assert.Equal(t, "my-value", source.HeaderFromContext(ctx, "my-header"))
+			assert.Equal(t, "my-msg", string(msg))
+			return nil
+		})
+		assert.True(t, a.acked)
+		assert.False(t, a.rejected)
+	})
+	t.Run("Failed", func(t *testing.T) {
+		a := &acknowledger{}
+		handle(ctx, "my-source", "my-urn", amqp.Delivery{Acknowledger: a}, func(ctx context.Context, msg []byte) error {
+			m, err := dfv1.MetaFromContext(ctx)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, m.ID)
+			assert.NotZero(t, m.Time)
+			return errors.New("failed")
+		})
+		assert.False(t, a.acked)
+		assert.True(t, a.rejected)
+		assert.False(t, a.requeued)
+	})
+}
+
+func Test_header(t *testing.T) {
+	h := header(amqp.Table{"string": "a", "bytes": []byte("b"), "int": int32(1)})
+	assert.Equal(t, "a", h("string"))
+	assert.Equal(t, "b", h("bytes"))
+	assert.Equal(t, "1", h("int"))
+	assert.Equal(t, "", h("missing"))
+}
@@ -0,0 +1,63 @@
+package argoevents
+
+import (
+	"context"
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharednats "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/nats"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	jssource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/jetstream"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/nats-io/nats.go"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+var logger = sharedutil.NewLogger()
+
+// the key in the EventBus's client auth secret, its value is YAML, e.g. "username: foo\npassword: bar"
+const clientAuthKey = "client-auth"
+
+type clientAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// New creates a source that consumes Argo Events events from the EventBus's JetStream stream. Each message is a
+// CloudEvent, encoded as JSON.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, namespace, pipelineName, stepName, sourceURN, sourceName string, x dfv1.ArgoEventsSource, process source.Process) (source.Interface, error) {
+	var opts []nats.Option
+	if auth, err := getClientAuth(ctx, secretInterface, x.GetAuthSecretName()); err != nil {
+		return nil, err
+	} else if auth != nil {
+		opts = append(opts, nats.UserInfo(auth.Username, auth.Password))
+	}
+	conn, err := sharednats.ConnectNATS(ctx, secretInterface, x.GetNATSURL(), nil, opts...)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("consuming Argo Events events", "source", sourceName, "eventBus", x.GetEventBusName(), "subject", x.GetSubject())
+	return jssource.Subscribe(ctx, conn, cluster, namespace, pipelineName, stepName, sourceURN, sourceName, x.GetSubject(), process)
+}
+
+// getClientAuth returns nil if the EventBus does not have client auth.
+func getClientAuth(ctx context.Context, secretInterface corev1.SecretInterface, secretName string) (*clientAuth, error) {
+	secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	data, ok := secret.Data[clientAuthKey]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in secret %s", clientAuthKey, secretName)
+	}
+	auth := &clientAuth{}
+	if err := yaml.Unmarshal(data, auth); err != nil {
+		return nil, fmt.Errorf("failed to parse %s in secret %s: %w", clientAuthKey, secretName, err)
+	}
+	return auth, nil
+}
@@ -0,0 +1,37 @@
+package argoevents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_getClientAuth(t *testing.T) {
+	ctx := context.Background()
+	t.Run("NotFound", func(t *testing.T) {
+		secretInterface := fake.NewSimpleClientset().CoreV1().Secrets("")
+		auth, err := getClientAuth(ctx, secretInterface, "eventbus-default-js-client-auth")
+		assert.NoError(t, err)
+		assert.Nil(t, auth)
+	})
+	t.Run("Found", func(t *testing.T) {
+		secretInterface := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "eventbus-default-js-client-auth"},
+			Data:       map[string][]byte{"client-auth": []byte("username: my-user\npassword: my-password\n")},
+		}).CoreV1().Secrets("")
+		auth, err := getClientAuth(ctx, secretInterface, "eventbus-default-js-client-auth")
+		assert.NoError(t, err)
+		assert.Equal(t, &clientAuth{Username: "my-user", Password: "my-password"}, auth)
+	})
+	t.Run("MissingKey", func(t *testing.T) {
+		secretInterface := fake.NewSimpleClientset(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "eventbus-default-js-client-auth"},
+		}).CoreV1().Secrets("")
+		_, err := getClientAuth(ctx, secretInterface, "eventbus-default-js-client-auth")
+		assert.EqualError(t, err, "key client-auth not found in secret eventbus-default-js-client-auth")
+	})
+}
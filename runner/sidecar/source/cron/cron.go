@@ -1,10 +1,13 @@
 package cron
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"text/template"
 	"time"
 
+	"github.com/Masterminds/sprig"
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
@@ -19,7 +22,32 @@ type cronSource struct {
 	crn *cron.Cron
 }
 
+// ParseTemplate parses a cron source's template, so it can be checked before the source is created.
+func ParseTemplate(text string) (*template.Template, error) {
+	return template.New("cron").Funcs(sprig.TxtFuncMap()).Parse(text)
+}
+
+// newMessage returns a function that creates the message for the time the schedule fired.
+func newMessage(x dfv1.Cron) (func(now time.Time) ([]byte, error), error) {
+	if x.Template == "" {
+		return func(now time.Time) ([]byte, error) { return []byte(now.Format(x.Layout)), nil }, nil
+	}
+	tmpl, err := ParseTemplate(x.Template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	return func(now time.Time) ([]byte, error) {
+		msg := &bytes.Buffer{}
+		err := tmpl.Execute(msg, map[string]interface{}{"Time": now, "Schedule": x.Schedule})
+		return msg.Bytes(), err
+	}, nil
+}
+
 func New(ctx context.Context, sourceName, sourceURN string, x dfv1.Cron, process source.Process) (source.Interface, error) {
+	newMsg, err := newMessage(x)
+	if err != nil {
+		return nil, err
+	}
 	crn := cron.New(
 		cron.WithParser(cron.NewParser(cron.SecondOptional|cron.Minute|cron.Hour|cron.Dom|cron.Month|cron.Dow|cron.Descriptor)),
 		cron.WithChain(cron.Recover(logger)),
@@ -30,11 +58,15 @@ func New(ctx context.Context, sourceName, sourceURN string, x dfv1.Cron, process
 		crn.Run()
 	}()
 
-	_, err := crn.AddFunc(x.Schedule, func() {
+	_, err = crn.AddFunc(x.Schedule, func() {
 		span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("cron-source-%s", sourceName))
 		defer span.Finish()
 		now := time.Now()
-		msg := []byte(now.Format(x.Layout))
+		msg, err := newMsg(now)
+		if err != nil {
+			logger.Error(err, "failed to create message", "source", sourceName)
+			return
+		}
 		if err := process(
 			dfv1.ContextWithMeta(
 				ctx,
@@ -0,0 +1,31 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newMessage(t *testing.T) {
+	now := time.Date(2021, 9, 1, 2, 3, 4, 0, time.UTC)
+	t.Run("Layout", func(t *testing.T) {
+		newMsg, err := newMessage(dfv1.Cron{Schedule: "@daily", Layout: time.RFC3339})
+		assert.NoError(t, err)
+		msg, err := newMsg(now)
+		assert.NoError(t, err)
+		assert.Equal(t, "2021-09-01T02:03:04Z", string(msg))
+	})
+	t.Run("Template", func(t *testing.T) {
+		newMsg, err := newMessage(dfv1.Cron{Schedule: "@daily", Template: `{"date": "{{.Time.Format "2006-01-02"}}", "schedule": "{{.Schedule}}", "unix": {{.Time.Unix}}}`})
+		assert.NoError(t, err)
+		msg, err := newMsg(now)
+		assert.NoError(t, err)
+		assert.Equal(t, `{"date": "2021-09-01", "schedule": "@daily", "unix": 1630461784}`, string(msg))
+	})
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		_, err := newMessage(dfv1.Cron{Schedule: "@daily", Template: "{{ .Time"})
+		assert.EqualError(t, err, "failed to parse template: template: cron:1: unclosed action")
+	})
+}
@@ -0,0 +1,65 @@
+package dapr
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+)
+
+type daprSource struct {
+	ready bool
+}
+
+// New creates a source for a Dapr input binding. Dapr's sidecar sends an OPTIONS request to find out if we accept
+// the binding's events, and then POSTs each event to `/{binding}`.
+func New(sourceURN, sourceName string, x dfv1.DaprSource, process source.Process) (source.Interface, error) {
+	if x.Binding == "" {
+		return nil, fmt.Errorf("binding is required")
+	}
+	s := &daprSource{true}
+	http.HandleFunc("/"+x.Binding, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "OPTIONS" {
+			w.WriteHeader(200)
+			return
+		}
+		span := opentracing.StartSpan(fmt.Sprintf("dapr-source-%s", sourceName))
+		defer span.Finish()
+		if !s.ready { // if we are not ready, we cannot serve requests
+			w.WriteHeader(503)
+			_, _ = w.Write([]byte("not ready"))
+			return
+		}
+		msg, err := ioutil.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		if err := process(
+			dfv1.ContextWithMeta(
+				opentracing.ContextWithSpan(r.Context(), span),
+				dfv1.Meta{Source: sourceURN, ID: uuid.New().String(), Time: time.Now().Unix()},
+			),
+			msg,
+		); err != nil {
+			// Dapr retries, or not, depending on the binding
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte(err.Error()))
+		} else {
+			w.WriteHeader(200)
+		}
+	})
+	return s, nil
+}
+
+func (s *daprSource) Close() error {
+	s.ready = false
+	return nil
+}
@@ -11,28 +11,23 @@ import (
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
 	"github.com/opentracing/opentracing-go"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 var logger = sharedutil.NewLogger()
 
-const offsetTableSchema = `CREATE TABLE IF NOT EXISTS argo_dataflow_offsets (
-	uid VARCHAR(255) NOT NULL,
-	remark VARCHAR(255) NOT NULL,
-	offset VARCHAR(255),
-	PRIMARY KEY (uid)
-)`
-
 type rowData = map[string]interface{}
 
 type dbSource struct {
 	db *sql.DB
 }
 
-func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN string, x dfv1.DBSource, process source.Process) (source.Interface, error) {
+func New(ctx context.Context, secretInterface corev1.SecretInterface, steps dynamic.ResourceInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN string, x dfv1.DBSource, process source.Process) (source.Interface, error) {
 	dataSource, err := getDataSource(ctx, secretInterface, x)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find data source: %w", err)
@@ -43,26 +38,29 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 	}
 	db.SetConnMaxLifetime(time.Minute * 3)
 
-	if x.InitSchema {
-		_, err = db.ExecContext(ctx, offsetTableSchema)
-		if err != nil {
-			return nil, fmt.Errorf("failed to init offsets table schema: %w", err)
+	var store offsetStore
+	if x.GetOffsetStore() == dfv1.DBOffsetStoreStatus {
+		store = &statusStore{steps: steps, stepName: pipelineName + "-" + stepName, sourceName: sourceName}
+	} else {
+		if x.InitSchema {
+			_, err = db.ExecContext(ctx, rebind(x.Driver, offsetTableSchema))
+			if err != nil {
+				return nil, fmt.Errorf("failed to init offsets table schema: %w", err)
+			}
+		}
+		store = &tableStore{
+			db:     db,
+			driver: x.Driver,
+			uid:    sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName),
+			remark: fmt.Sprintf("%s.%s.%s.%s.sources.%s", cluster, namespace, pipelineName, stepName, sourceName),
 		}
 	}
 
-	var offset string
-	remark := fmt.Sprintf("%s.%s.%s.%s.sources.%s", cluster, namespace, pipelineName, stepName, sourceName)
-	uid := sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName)
-	offset, err = getOffsetFromDB(ctx, db, uid)
+	offset, err := store.load(ctx)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			if _, err = insertOffset(ctx, db, uid, remark, ""); err != nil {
-				return nil, fmt.Errorf("failed to initialize offset: %w", err)
-			}
-		} else {
-			return nil, fmt.Errorf("failed to get offset from db: %w", err)
-		}
+		return nil, err
 	}
+	logger.Info("loaded offset", "source", sourceName, "offset", offset)
 
 	go func() {
 		defer runtime.HandleCrash()
@@ -72,7 +70,7 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 			case <-ctx.Done():
 				return
 			default:
-				if err = queryData(ctx, db, sourceURN, x.Query, x.OffsetColumn, offset, func(ctx context.Context, d rowData) error {
+				if err = queryData(ctx, db, x.Driver, sourceURN, x.Query, x.OffsetColumn, offset, func(ctx context.Context, d rowData) error {
 					span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("db-source-%s", sourceName))
 					defer span.Finish()
 					jsonData, err := json.Marshal(d)
@@ -82,7 +80,7 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 					if err := process(ctx, jsonData); err != nil {
 						return fmt.Errorf("failed to process data: %w", err)
 					}
-					offset = fmt.Sprintf("%v", d[x.OffsetColumn])
+					offset = offsetOf(d[x.OffsetColumn])
 					return nil
 				}); err != nil {
 					logger.Error(err, "failed to process data query")
@@ -91,21 +89,23 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 		}
 	}()
 
-	// update offset in db
+	// commit the offset
 	go func() {
 		defer runtime.HandleCrash()
 		ticker := time.NewTicker(x.CommitInterval.Duration)
 		defer ticker.Stop()
+		committed := offset
 		for {
 			select {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if offset != "" {
-					if _, err := updateOffset(ctx, db, uid, offset); err != nil {
-						logger.Error(err, "failed to update offset", "source", sourceName)
+				if offset != "" && offset != committed {
+					if err := store.commit(ctx, offset); err != nil {
+						logger.Error(err, "failed to commit offset", "source", sourceName)
 						continue
 					}
+					committed = offset
 				}
 			}
 		}
@@ -118,50 +118,20 @@ func (d dbSource) Close() error {
 	return d.db.Close()
 }
 
-func getOffsetFromDB(ctx context.Context, db *sql.DB, uid string) (string, error) {
-	stmt, err := db.Prepare("select offset from argo_dataflow_offsets where uid=?")
-	if err != nil {
-		return "", fmt.Errorf("failed to prepare statement for offset query")
-	}
-	defer func() { _ = stmt.Close() }()
-	var offset string
-	if err = stmt.QueryRowContext(ctx, uid).Scan(&offset); err != nil {
-		return "", err
-	}
-	return offset, nil
-}
-
-func updateOffset(ctx context.Context, db *sql.DB, uid, offset string) (int64, error) {
-	stmt, err := db.Prepare("update argo_dataflow_offsets set offset=? where uid=? and offset<>?")
-	if err != nil {
-		return 0, fmt.Errorf("failed to prepare offset update statement: %w", err)
-	}
-	defer func() { _ = stmt.Close() }()
-	if result, err := stmt.ExecContext(ctx, offset, uid, offset); err != nil {
-		return 0, fmt.Errorf("failed to exec offset update statement: %w", err)
-	} else {
-		return result.RowsAffected()
-	}
-}
-
-func insertOffset(ctx context.Context, db *sql.DB, uid, remark, offset string) (int64, error) {
-	stmt, err := db.Prepare("insert into argo_dataflow_offsets (uid, remark, offset) values (?, ?, ?)")
-	if err != nil {
-		return 0, fmt.Errorf("failed to prepare offset insert statement: %w", err)
-	}
-	defer func() { _ = stmt.Close() }()
-	if result, err := stmt.ExecContext(ctx, uid, remark, offset); err != nil {
-		return 0, fmt.Errorf("failed to exec offset insert statement: %w", err)
-	} else {
-		return result.RowsAffected()
+// offsetOf returns the offset column's value as a string that the database can compare it to, e.g. times are
+// formatted as RFC3339.
+func offsetOf(v interface{}) string {
+	if t, ok := v.(time.Time); ok {
+		return t.Format(time.RFC3339Nano)
 	}
+	return fmt.Sprint(v)
 }
 
-func queryData(ctx context.Context, db *sql.DB, sourceURN, query, offsetColumn, offset string, f func(context.Context, rowData) error) error {
+func queryData(ctx context.Context, db *sql.DB, driver, sourceURN, query, offsetColumn, offset string, f func(context.Context, rowData) error) error {
 	sql := fmt.Sprintf("select * from (%s) as dataflow_query_table order by %s", query, offsetColumn)
 	params := []interface{}{}
 	if offset != "" {
-		sql = fmt.Sprintf("select * from (%s) as dataflow_query_table where %s > ? order by %s", query, offsetColumn, offsetColumn)
+		sql = fmt.Sprintf(rebind(driver, "select * from (%s) as dataflow_query_table where %s > ? order by %s"), query, offsetColumn, offsetColumn)
 		params = append(params, offset)
 	}
 	stmt, err := db.Prepare(sql)
@@ -0,0 +1,14 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_offsetOf(t *testing.T) {
+	assert.Equal(t, "1", offsetOf(int64(1)))
+	assert.Equal(t, "2021-01-01 00:00:00", offsetOf("2021-01-01 00:00:00"))
+	assert.Equal(t, "2021-01-01T00:00:00.5Z", offsetOf(time.Date(2021, 1, 1, 0, 0, 0, 5e8, time.UTC)))
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+const offsetTableSchema = "CREATE TABLE IF NOT EXISTS argo_dataflow_offsets (\n" +
+	"	uid VARCHAR(255) NOT NULL,\n" +
+	"	remark VARCHAR(255) NOT NULL,\n" +
+	"	`offset` VARCHAR(255),\n" +
+	"	PRIMARY KEY (uid)\n" +
+	")"
+
+// offsetStore loads and commits the offset of a source, the value of the offset column of the last row processed.
+type offsetStore interface {
+	// load returns the committed offset, or "" if there is none.
+	load(ctx context.Context) (string, error)
+	commit(ctx context.Context, offset string) error
+}
+
+// rebind rewrites a statement written for MySQL for the driver, i.e. for Postgres, `?` placeholders become `$1`, `$2`,
+// etc., and identifiers are quoted with `"` rather than backticks.
+func rebind(driver, query string) string {
+	if driver != "postgres" && driver != "pgx" {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		switch r {
+		case '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+		case '`':
+			b.WriteRune('"')
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// tableStore stores offsets in the argo_dataflow_offsets table, in the database being queried.
+type tableStore struct {
+	db     *sql.DB
+	driver string
+	uid    string
+	remark string
+}
+
+func (s *tableStore) load(ctx context.Context) (string, error) {
+	var offset string
+	err := s.db.QueryRowContext(ctx, rebind(s.driver, "select `offset` from argo_dataflow_offsets where uid=?"), s.uid).Scan(&offset)
+	if err == sql.ErrNoRows {
+		if _, err := s.db.ExecContext(ctx, rebind(s.driver, "insert into argo_dataflow_offsets (uid, remark, `offset`) values (?, ?, ?)"), s.uid, s.remark, ""); err != nil {
+			return "", fmt.Errorf("failed to initialize offset: %w", err)
+		}
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get offset from db: %w", err)
+	}
+	return offset, nil
+}
+
+func (s *tableStore) commit(ctx context.Context, offset string) error {
+	if _, err := s.db.ExecContext(ctx, rebind(s.driver, "update argo_dataflow_offsets set `offset`=? where uid=?"), offset, s.uid); err != nil {
+		return fmt.Errorf("failed to update offset: %w", err)
+	}
+	return nil
+}
+
+// statusStore stores offsets in the step's status, so the source does not need to write to the database.
+type statusStore struct {
+	steps      dynamic.ResourceInterface
+	stepName   string
+	sourceName string
+}
+
+func (s *statusStore) load(ctx context.Context) (string, error) {
+	un, err := s.steps.Get(ctx, s.stepName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get step %q: %w", s.stepName, err)
+	}
+	offset, _, err := unstructured.NestedString(un.Object, "status", "sourceOffsets", s.sourceName)
+	return offset, err
+}
+
+func (s *statusStore) commit(ctx context.Context, offset string) error {
+	patch := sharedutil.MustJSON(map[string]interface{}{"status": map[string]interface{}{"sourceOffsets": map[string]string{s.sourceName: offset}}})
+	if _, err := s.steps.Patch(ctx, s.stepName, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("failed to patch step %q status: %w", s.stepName, err)
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+)
+
+func Test_rebind(t *testing.T) {
+	query := "update argo_dataflow_offsets set `offset`=? where uid=?"
+	assert.Equal(t, query, rebind("mysql", query))
+	assert.Equal(t, `update argo_dataflow_offsets set "offset"=$1 where uid=$2`, rebind("postgres", query))
+	assert.Equal(t, `update argo_dataflow_offsets set "offset"=$1 where uid=$2`, rebind("pgx", query))
+}
+
+func Test_statusStore(t *testing.T) {
+	ctx := context.Background()
+	step := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": dfv1.GroupVersion.String(),
+		"kind":       "Step",
+		"metadata":   map[string]interface{}{"namespace": "my-ns", "name": "my-pl-main"},
+		"status":     map[string]interface{}{"sourceOffsets": map[string]interface{}{"other": "1"}},
+	}}
+	steps := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), step).
+		Resource(dfv1.StepGroupVersionResource).Namespace("my-ns")
+	s := &statusStore{steps: steps, stepName: "my-pl-main", sourceName: "my-source"}
+
+	offset, err := s.load(ctx)
+	assert.NoError(t, err)
+	assert.Empty(t, offset)
+
+	assert.NoError(t, s.commit(ctx, "2021-01-01T00:00:00Z"))
+	offset, err = s.load(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "2021-01-01T00:00:00Z", offset)
+
+	un, err := steps.Get(ctx, "my-pl-main", metav1.GetOptions{})
+	assert.NoError(t, err)
+	other, _, _ := unstructured.NestedString(un.Object, "status", "sourceOffsets", "other")
+	assert.Equal(t, "1", other, "other sources' offsets are kept")
+}
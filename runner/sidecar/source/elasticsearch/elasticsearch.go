@@ -0,0 +1,254 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/opentracing/opentracing-go"
+	corev1api "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type esSource struct {
+	stop      chan struct{}
+	done      chan struct{}
+	total     int64 // hits matching the query, or -1 until the first page has been fetched
+	processed int64
+	drained   int32
+	mu        sync.Mutex
+	err       error // why the query failed
+}
+
+type client struct {
+	url                string
+	username, password string
+	httpClient         *http.Client
+}
+
+type hit struct {
+	Index string          `json:"_index"`
+	ID    string          `json:"_id"`
+	Sort  json.RawMessage `json:"sort"` // raw, as sort values may be longs that do not fit in a float64
+}
+
+type searchResponse struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
+		Total struct {
+			Value int64 `json:"value"`
+		} `json:"total"`
+		Hits []json.RawMessage `json:"hits"`
+	} `json:"hits"`
+}
+
+// backoff is used to retry failed requests, the point in time must not expire while retrying
+var backoff = wait.Backoff{Duration: time.Second, Factor: 2, Jitter: 0.1, Steps: 5}
+
+// New creates a source that runs the query once, emitting each hit as a message. Only the lead replica runs the query,
+// the other replicas are drained immediately.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sourceName, sourceURN string, x dfv1.ElasticsearchSource, process source.Process, leadReplica bool) (source.HasPending, error) {
+	c := &client{url: strings.TrimSuffix(x.URL, "/"), httpClient: &http.Client{Timeout: 30 * time.Second}}
+	for _, s := range []struct {
+		selector *corev1api.SecretKeySelector
+		value    *string
+	}{{x.UsernameSecret, &c.username}, {x.PasswordSecret, &c.password}} {
+		if s.selector == nil {
+			continue
+		}
+		secret, err := secretInterface.Get(ctx, s.selector.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get secret %q: %w", s.selector.Name, err)
+		}
+		value, ok := secret.Data[s.selector.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found in secret %q", s.selector.Key, s.selector.Name)
+		}
+		*s.value = string(value)
+	}
+	s := &esSource{stop: make(chan struct{}), done: make(chan struct{}), total: -1}
+	if !leadReplica {
+		s.total = 0
+		s.drained = 1
+		close(s.done)
+		return s, nil
+	}
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(s.done)
+		if err := s.run(ctx, c, sourceName, sourceURN, x, process); err != nil {
+			logger.Error(err, "failed to query", "source", sourceName)
+			s.mu.Lock()
+			s.err = err
+			s.mu.Unlock()
+			return
+		}
+		atomic.StoreInt32(&s.drained, 1)
+	}()
+	return s, nil
+}
+
+func (s *esSource) run(ctx context.Context, c *client, sourceName, sourceURN string, x dfv1.ElasticsearchSource, process source.Process) error {
+	keepAlive := fmt.Sprintf("%dms", x.GetKeepAlive().Milliseconds())
+	pit := struct {
+		ID string `json:"id"`
+	}{}
+	if err := c.retry(ctx, http.MethodPost, "/"+x.Index+"/_pit?keep_alive="+keepAlive, nil, &pit); err != nil {
+		return fmt.Errorf("failed to open point in time: %w", err)
+	}
+	defer func() {
+		// the point in time expires anyway, so this is best effort
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := c.do(ctx, http.MethodDelete, "/_pit", pit, nil); err != nil {
+			logger.Error(err, "failed to close point in time", "source", sourceName)
+		}
+	}()
+	var searchAfter json.RawMessage
+	for {
+		req := map[string]interface{}{
+			"size":  x.GetPageSize(),
+			"query": json.RawMessage(x.GetQuery()),
+			"pit":   map[string]string{"id": pit.ID, "keep_alive": keepAlive},
+			"sort":  []interface{}{map[string]string{"_shard_doc": "asc"}},
+		}
+		if searchAfter != nil {
+			req["search_after"] = searchAfter
+		} else {
+			req["track_total_hits"] = true
+		}
+		res := &searchResponse{}
+		if err := c.retry(ctx, http.MethodPost, "/_search", req, res); err != nil {
+			return fmt.Errorf("failed to search: %w", err)
+		}
+		// the point in time's ID may change with each response
+		pit.ID = dfv1.StringOr(res.PitID, pit.ID)
+		if searchAfter == nil {
+			logger.Info("query matched", "source", sourceName, "total", res.Hits.Total.Value)
+			atomic.StoreInt64(&s.total, res.Hits.Total.Value)
+		}
+		for _, data := range res.Hits.Hits {
+			select {
+			case <-s.stop:
+				return nil
+			default:
+			}
+			h := hit{}
+			if err := json.Unmarshal(data, &h); err != nil {
+				return fmt.Errorf("failed to unmarshal hit: %w", err)
+			}
+			span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("elasticsearch-source-%s", sourceName))
+			if err := process(
+				dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: h.Index + "/" + h.ID, Time: time.Now().Unix()}),
+				data,
+			); err != nil {
+				logger.Error(err, "failed to process message", "source", sourceName)
+			}
+			span.Finish()
+			atomic.AddInt64(&s.processed, 1)
+			searchAfter = h.Sort
+		}
+		if len(res.Hits.Hits) < int(x.GetPageSize()) {
+			logger.Info("processed all hits", "source", sourceName, "processed", atomic.LoadInt64(&s.processed))
+			return nil
+		}
+	}
+}
+
+// retry does the request, retrying it with backoff if it fails.
+func (c *client) retry(ctx context.Context, method, path string, body, out interface{}) error {
+	var lastErr error
+	err := wait.ExponentialBackoff(backoff, func() (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		lastErr = c.do(ctx, method, path, body, out)
+		return lastErr == nil, nil
+	})
+	if err == wait.ErrWaitTimeout {
+		return lastErr
+	}
+	return err
+}
+
+func (c *client) do(ctx context.Context, method, path string, body, out interface{}) error {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, bytes.NewBuffer(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %q %q", method, path, resp.Status, respBody)
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
+
+// Close stops processing hits, once the hit being processed is done.
+func (s *esSource) Close() error {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+	return nil
+}
+
+// GetPending returns the number of hits not yet processed.
+func (s *esSource) GetPending(context.Context) (uint64, error) {
+	total := atomic.LoadInt64(&s.total)
+	if total < 0 {
+		return 0, source.ErrPendingUnavailable
+	}
+	if pending := total - atomic.LoadInt64(&s.processed); pending > 0 {
+		return uint64(pending), nil
+	}
+	return 0, nil
+}
+
+// IsDrained returns true once every hit has been processed, or an error if the query failed.
+func (s *esSource) IsDrained(context.Context) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return false, s.err
+	}
+	return atomic.LoadInt32(&s.drained) == 1, nil
+}
@@ -0,0 +1,130 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// fakeElasticsearch serves the point in time and search APIs, for an index of n documents
+func fakeElasticsearch(t *testing.T, n int) (*httptest.Server, *[]map[string]interface{}) {
+	var requests []map[string]interface{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/my-index/_pit", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "1000ms", r.URL.Query().Get("keep_alive"))
+		username, password, _ := r.BasicAuth()
+		assert.Equal(t, "my-username", username)
+		assert.Equal(t, "my-password", password)
+		_, _ = w.Write([]byte(`{"id": "pit-0"}`))
+	})
+	mux.HandleFunc("/_pit", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		_, _ = w.Write([]byte(`{"succeeded": true}`))
+	})
+	mux.HandleFunc("/_search", func(w http.ResponseWriter, r *http.Request) {
+		req := map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		from := 0
+		if x, ok := req["search_after"].([]interface{}); ok {
+			from = int(x[0].(float64)) + 1
+		}
+		size := int(req["size"].(float64))
+		var hits []string
+		for i := from; i < from+size && i < n; i++ {
+			hits = append(hits, fmt.Sprintf(`{"_index": "my-index", "_id": "%d", "_source": {"i": %d}, "sort": [%d]}`, i, i, i))
+		}
+		_, _ = fmt.Fprintf(w, `{"pit_id": "pit-%d", "hits": {"total": {"value": %d}, "hits": [%s]}}`, len(requests), n, strings.Join(hits, ","))
+	})
+	return httptest.NewServer(mux), &requests
+}
+
+func TestNew(t *testing.T) {
+	backoff.Duration = time.Millisecond
+	ctx := context.Background()
+	secretInterface := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-elasticsearch-default"},
+		Data:       map[string][]byte{"username": []byte("my-username"), "password": []byte("my-password")},
+	}).CoreV1().Secrets("")
+	selector := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "dataflow-elasticsearch-default"}, Key: key}
+	}
+	t.Run("LeadReplica", func(t *testing.T) {
+		server, requests := fakeElasticsearch(t, 5)
+		defer server.Close()
+		x := dfv1.ElasticsearchSource{
+			URL:            server.URL,
+			Index:          "my-index",
+			PageSize:       2,
+			KeepAlive:      metav1.Duration{Duration: time.Second},
+			UsernameSecret: selector("username"),
+			PasswordSecret: selector("password"),
+		}
+		var mu sync.Mutex
+		var msgs, ids []string
+		s, err := New(ctx, secretInterface, "my-source", "my-urn", x, func(ctx context.Context, msg []byte) error {
+			meta, err := dfv1.MetaFromContext(ctx)
+			assert.NoError(t, err)
+			mu.Lock()
+			defer mu.Unlock()
+			msgs = append(msgs, string(msg))
+			ids = append(ids, meta.ID)
+			return nil
+		}, true)
+		assert.NoError(t, err)
+		drainable := s.(source.Drainable)
+		assert.Eventually(t, func() bool {
+			drained, err := drainable.IsDrained(ctx)
+			return err == nil && drained
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.NoError(t, s.Close())
+		pending, err := s.GetPending(ctx)
+		assert.NoError(t, err)
+		assert.Zero(t, pending)
+		assert.Equal(t, []string{"my-index/0", "my-index/1", "my-index/2", "my-index/3", "my-index/4"}, ids)
+		assert.Equal(t, `{"_index": "my-index", "_id": "0", "_source": {"i": 0}, "sort": [0]}`, msgs[0])
+		if assert.Len(t, *requests, 3) {
+			first, last := (*requests)[0], (*requests)[2]
+			assert.Equal(t, true, first["track_total_hits"])
+			assert.Nil(t, first["search_after"])
+			assert.Equal(t, map[string]interface{}{"match_all": map[string]interface{}{}}, first["query"])
+			assert.Equal(t, map[string]interface{}{"id": "pit-0", "keep_alive": "1000ms"}, first["pit"])
+			assert.Equal(t, []interface{}{float64(3)}, last["search_after"])
+			assert.Equal(t, map[string]interface{}{"id": "pit-2", "keep_alive": "1000ms"}, last["pit"])
+		}
+	})
+	t.Run("OtherReplica", func(t *testing.T) {
+		s, err := New(ctx, secretInterface, "my-source", "my-urn", dfv1.ElasticsearchSource{}, nil, false)
+		assert.NoError(t, err)
+		drained, err := s.(source.Drainable).IsDrained(ctx)
+		assert.NoError(t, err)
+		assert.True(t, drained)
+		assert.NoError(t, s.Close())
+	})
+	t.Run("Error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(404)
+			_, _ = w.Write([]byte(`{"error": "index_not_found_exception"}`))
+		}))
+		defer server.Close()
+		s, err := New(ctx, secretInterface, "my-source", "my-urn", dfv1.ElasticsearchSource{URL: server.URL, Index: "my-index"}, nil, true)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Close())
+		_, err = s.(source.Drainable).IsDrained(ctx)
+		assert.EqualError(t, err, `failed to open point in time: POST /my-index/_pit?keep_alive=60000ms: "404 Not Found" "{\"error\": \"index_not_found_exception\"}"`)
+	})
+}
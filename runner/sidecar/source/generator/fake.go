@@ -0,0 +1,47 @@
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"text/template"
+)
+
+var (
+	firstNames = []string{"Alex", "Ana", "Ben", "Chen", "Chloe", "Dmitri", "Emma", "Fatima", "Hiro", "Isla", "Jamal", "Kai", "Lena", "Luca", "Maya", "Noah", "Olga", "Priya", "Sam", "Zoe"}
+	lastNames  = []string{"Ahmed", "Brown", "Garcia", "Ivanova", "Jones", "Kim", "Kowalski", "Li", "Martin", "Mueller", "Nguyen", "Okafor", "Patel", "Rossi", "Sato", "Silva", "Smith", "Tanaka", "Wilson", "Yilmaz"}
+	words      = []string{"alpha", "bravo", "cloud", "data", "event", "flow", "graph", "harbor", "input", "join", "kernel", "lambda", "message", "node", "output", "pipeline", "queue", "record", "stream", "topic"}
+	cities     = []string{"Amsterdam", "Bangalore", "Berlin", "Cairo", "Chicago", "Lagos", "Lima", "London", "Madrid", "Mumbai", "Nairobi", "New York", "Paris", "San Francisco", "Seoul", "Singapore", "Sydney", "Tokyo", "Toronto", "Warsaw"}
+	countries  = []string{"Argentina", "Australia", "Brazil", "Canada", "China", "Egypt", "France", "Germany", "India", "Italy", "Japan", "Kenya", "Mexico", "Netherlands", "Nigeria", "Poland", "Singapore", "South Korea", "Spain", "United States"}
+	domains    = []string{"example.com", "example.net", "example.org"}
+)
+
+// fakeFuncs returns template functions that return fake data, picked using r, which must not be shared between
+// goroutines.
+func fakeFuncs(r *rand.Rand) template.FuncMap {
+	pick := func(xs []string) func() string {
+		return func() string { return xs[r.Intn(len(xs))] }
+	}
+	firstName, lastName, word := pick(firstNames), pick(lastNames), pick(words)
+	return template.FuncMap{
+		"firstName": firstName,
+		"lastName":  lastName,
+		"name":      func() string { return firstName() + " " + lastName() },
+		"email": func() string {
+			return fmt.Sprintf("%s.%s@%s", strings.ToLower(firstName()), strings.ToLower(lastName()), pick(domains)())
+		},
+		"word": word,
+		"sentence": func() string {
+			n := 4 + r.Intn(5)
+			ws := make([]string, n)
+			for i := range ws {
+				ws[i] = word()
+			}
+			s := strings.Join(ws, " ")
+			return strings.ToUpper(s[:1]) + s[1:] + "."
+		},
+		"city":    pick(cities),
+		"country": pick(countries),
+		"float":   func() float64 { return r.Float64() },
+	}
+}
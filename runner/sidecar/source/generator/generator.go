@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig"
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+var logger = sharedutil.NewLogger()
+
+type generatorSource struct {
+	stop    chan struct{}
+	done    chan struct{}
+	drained int32
+	seq     *uint64
+}
+
+// ParseTemplate parses a generator source's template, so it can be checked before the source is created.
+func ParseTemplate(text string) (*template.Template, error) {
+	return parse(text, new(uint64), 0, rand.New(rand.NewSource(0)))
+}
+
+func parse(text string, seq *uint64, replica int, r *rand.Rand) (*template.Template, error) {
+	funcs := template.FuncMap{
+		"seq":     func() uint64 { return *seq },
+		"replica": func() int { return replica },
+	}
+	for name, f := range fakeFuncs(r) {
+		funcs[name] = f
+	}
+	return template.New("generator").Funcs(sprig.TxtFuncMap()).Funcs(funcs).Parse(text)
+}
+
+// New creates a source that generates messages from the template, at up to the rate. If count is greater than zero,
+// it stops once it has generated that many messages. If there is a position, i.e. the source is restored from a
+// checkpoint, it continues from there.
+func New(ctx context.Context, sourceName, sourceURN string, replica int, x dfv1.GeneratorSource, process source.Process, position string) (source.Interface, error) {
+	var seq uint64
+	if position != "" {
+		v, err := strconv.ParseUint(position, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse position %q: %w", position, err)
+		}
+		logger.Info("continuing from position", "source", sourceName, "seq", v)
+		seq = v
+	}
+	tmpl, err := parse(x.GetTemplate(), &seq, replica, rand.New(rand.NewSource(time.Now().UnixNano()+int64(replica))))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template: %w", err)
+	}
+	interval := time.Second / time.Duration(x.GetRate())
+	if interval <= 0 {
+		interval = 1
+	}
+	s := &generatorSource{stop: make(chan struct{}), done: make(chan struct{}), seq: &seq}
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for ; x.Count == 0 || seq < x.Count; atomic.AddUint64(&seq, 1) {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+			msg := &bytes.Buffer{}
+			if err := tmpl.Execute(msg, nil); err != nil {
+				logger.Error(err, "failed to generate message", "source", sourceName)
+				continue
+			}
+			span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("generator-source-%s", sourceName))
+			if err := process(
+				dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: uuid.New().String(), Time: time.Now().Unix()}),
+				msg.Bytes(),
+			); err != nil {
+				logger.Error(err, "failed to process message", "source", sourceName)
+			}
+			span.Finish()
+		}
+		logger.Info("generated all messages", "source", sourceName, "count", x.Count)
+		atomic.StoreInt32(&s.drained, 1)
+	}()
+	return s, nil
+}
+
+// Close stops generating messages, once the message being processed is done.
+func (s *generatorSource) Close() error {
+	select {
+	case <-s.stop:
+	default:
+		close(s.stop)
+	}
+	<-s.done
+	return nil
+}
+
+// IsDrained returns true once count messages have been generated and processed.
+func (s *generatorSource) IsDrained(context.Context) (bool, error) {
+	return atomic.LoadInt32(&s.drained) == 1, nil
+}
+
+// GetPosition returns the number of messages generated.
+func (s *generatorSource) GetPosition() string {
+	return strconv.FormatUint(atomic.LoadUint64(s.seq), 10)
+}
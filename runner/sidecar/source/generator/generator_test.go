@@ -0,0 +1,77 @@
+package generator
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTemplate(t *testing.T) {
+	_, err := ParseTemplate(`{{seq}} {{replica}} {{name}} {{email}} {{upper "sprig"}}`)
+	assert.NoError(t, err)
+	_, err = ParseTemplate(`{{nope}}`)
+	assert.EqualError(t, err, `template: generator:1: function "nope" not defined`)
+}
+
+func TestGenerator(t *testing.T) {
+	var msgs []string
+	s, err := New(context.Background(), "my-source", "my-urn", 2, dfv1.GeneratorSource{
+		Rate:     1000,
+		Template: `{"seq": {{seq}}, "replica": {{replica}}, "email": "{{email}}"}`,
+		Count:    3,
+	}, func(ctx context.Context, msg []byte) error {
+		m, err := dfv1.MetaFromContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, "my-urn", m.Source)
+		msgs = append(msgs, string(msg))
+		return nil
+	}, "")
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		drained, _ := s.(*generatorSource).IsDrained(context.Background())
+		return drained
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, s.Close())
+	if assert.Len(t, msgs, 3) {
+		for i, msg := range msgs {
+			x := map[string]interface{}{}
+			assert.NoError(t, json.Unmarshal([]byte(msg), &x))
+			assert.Equal(t, float64(i), x["seq"])
+			assert.Equal(t, float64(2), x["replica"])
+			assert.Regexp(t, `^[a-z]+\.[a-z]+@example\.(com|net|org)$`, x["email"])
+		}
+	}
+}
+
+func TestGenerator_Close(t *testing.T) {
+	s, err := New(context.Background(), "my-source", "my-urn", 0, dfv1.GeneratorSource{}, func(context.Context, []byte) error { return nil }, "")
+	assert.NoError(t, err)
+	assert.NoError(t, s.Close())
+	assert.NoError(t, s.Close())
+	drained, err := s.(*generatorSource).IsDrained(context.Background())
+	assert.NoError(t, err)
+	assert.False(t, drained)
+}
+
+func TestGenerator_Position(t *testing.T) {
+	var msgs []string
+	s, err := New(context.Background(), "my-source", "my-urn", 0, dfv1.GeneratorSource{Rate: 1000, Template: "{{seq}}", Count: 3}, func(ctx context.Context, msg []byte) error {
+		msgs = append(msgs, string(msg))
+		return nil
+	}, "1")
+	assert.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		drained, _ := s.(*generatorSource).IsDrained(context.Background())
+		return drained
+	}, time.Second, 10*time.Millisecond)
+	assert.NoError(t, s.Close())
+	assert.Equal(t, []string{"1", "2"}, msgs)
+	assert.Equal(t, "3", s.(source.Checkpointable).GetPosition())
+	_, err = New(context.Background(), "my-source", "my-urn", 0, dfv1.GeneratorSource{}, nil, "x")
+	assert.EqualError(t, err, `failed to parse position "x": strconv.ParseUint: parsing "x": invalid syntax`)
+}
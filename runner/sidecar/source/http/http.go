@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
@@ -12,6 +13,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/opentracing/opentracing-go"
 	"github.com/opentracing/opentracing-go/ext"
+	apiv1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
@@ -20,13 +22,26 @@ type httpSource struct {
 	ready bool
 }
 
-func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceURN, sourceName string, process source.Process) (string, source.Interface, error) {
+// New creates a HTTP source. Requests must present the step's bearer token, or the token in the token secret, or, if
+// the source has OIDC, a JWT from the issuer.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceURN, sourceName string, x dfv1.HTTPSource, process source.Process) (string, source.Interface, error) {
 	// we don't want to share this secret
 	secret, err := secretInterface.Get(ctx, pipelineName+"-"+stepName, metav1.GetOptions{})
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to get secret %q: %w", stepName, err)
 	}
 	authorization := string(secret.Data[fmt.Sprintf("sources.%s.http.authorization", sourceName)])
+	if s := x.TokenSecret; s != nil {
+		if authorization, err = getAuthorization(ctx, secretInterface, *s); err != nil {
+			return "", nil, err
+		}
+	}
+	var v *verifier
+	if x.OIDC != nil {
+		if v, err = newVerifier(ctx, *x.OIDC); err != nil {
+			return "", nil, err
+		}
+	}
 	h := &httpSource{true}
 	http.HandleFunc("/sources/"+sourceName, func(w http.ResponseWriter, r *http.Request) {
 		wireContext, err := opentracing.GlobalTracer().Extract(opentracing.HTTPHeaders, opentracing.HTTPHeadersCarrier(r.Header))
@@ -39,7 +54,13 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineNa
 		}
 		defer span.Finish()
 		ctx := opentracing.ContextWithSpan(r.Context(), span)
-		if r.Header.Get("Authorization") != authorization {
+		if v != nil {
+			if err := v.verify(ctx, r.Header.Get("Authorization")); err != nil {
+				w.WriteHeader(403)
+				_, _ = w.Write([]byte(err.Error()))
+				return
+			}
+		} else if r.Header.Get("Authorization") != authorization {
 			w.WriteHeader(403)
 			return
 		}
@@ -63,7 +84,7 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineNa
 
 		if err := process(
 			dfv1.ContextWithMeta(
-				ctx,
+				source.ContextWithHeader(ctx, r.Header.Get),
 				dfv1.Meta{
 					Source: sourceURN,
 					ID:     id,
@@ -81,6 +102,19 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineNa
 	return authorization, h, nil
 }
 
+// getAuthorization returns the authorization header for the bearer token in the secret.
+func getAuthorization(ctx context.Context, secretInterface corev1.SecretInterface, s apiv1.SecretKeySelector) (string, error) {
+	secret, err := secretInterface.Get(ctx, s.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get token secret %q: %w", s.Name, err)
+	}
+	token, ok := secret.Data[s.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in token secret", s.Key)
+	}
+	return "Bearer " + strings.TrimSpace(string(token)), nil
+}
+
 func (s *httpSource) Close() error {
 	s.ready = false
 	return nil
@@ -0,0 +1,32 @@
+package http
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_getAuthorization(t *testing.T) {
+	ctx := context.Background()
+	secrets := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-token"},
+		Data:       map[string][]byte{"token": []byte("my-token\n")},
+	}).CoreV1().Secrets("")
+	t.Run("Found", func(t *testing.T) {
+		authorization, err := getAuthorization(ctx, secrets, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-token"}, Key: "token"})
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer my-token", authorization)
+	})
+	t.Run("KeyNotFound", func(t *testing.T) {
+		_, err := getAuthorization(ctx, secrets, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-token"}, Key: "other"})
+		assert.EqualError(t, err, `key "other" not found in token secret`)
+	})
+	t.Run("SecretNotFound", func(t *testing.T) {
+		_, err := getAuthorization(ctx, secrets, corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "other"}, Key: "token"})
+		assert.Error(t, err)
+	})
+}
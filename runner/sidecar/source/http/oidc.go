@@ -0,0 +1,227 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/form3tech-oss/jwt-go"
+)
+
+var logger = sharedutil.NewLogger()
+
+// keys are only refreshed this often, so that requests with unknown key IDs cannot flood the issuer
+const minKeyRefreshInterval = time.Minute
+
+type verifier struct {
+	issuer   string
+	audience string
+	client   *http.Client
+	jwksURI  string
+
+	mu          sync.Mutex
+	keys        map[string]interface{} // key ID -> public key
+	refreshedAt time.Time
+}
+
+type jwk struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// newVerifier discovers the issuer's keys, so that a misconfigured issuer fails when the source is created, rather
+// than on the first request.
+func newVerifier(ctx context.Context, x dfv1.OIDC) (*verifier, error) {
+	v := &verifier{
+		issuer:   strings.TrimSuffix(x.Issuer, "/"),
+		audience: x.Audience,
+		client:   &http.Client{Timeout: 10 * time.Second},
+	}
+	discovery := struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}{}
+	if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC issuer %q: %w", v.issuer, err)
+	}
+	if strings.TrimSuffix(discovery.Issuer, "/") != v.issuer {
+		return nil, fmt.Errorf("OIDC issuer %q does not match discovered issuer %q", v.issuer, discovery.Issuer)
+	}
+	v.jwksURI = discovery.JWKSURI
+	if err := v.refreshKeys(ctx); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func (v *verifier) getJSON(ctx context.Context, url string, obj interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("%q returned %q", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(obj)
+}
+
+func (v *verifier) refreshKeys(ctx context.Context) error {
+	set := struct {
+		Keys []jwk `json:"keys"`
+	}{}
+	if err := v.getJSON(ctx, v.jwksURI, &set); err != nil {
+		return fmt.Errorf("failed to get OIDC keys: %w", err)
+	}
+	keys := make(map[string]interface{})
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		if key, err := k.publicKey(); err != nil {
+			logger.Info("ignoring OIDC key", "kid", k.Kid, "error", err.Error())
+		} else {
+			keys[k.Kid] = key
+		}
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	v.keys = keys
+	v.refreshedAt = time.Now()
+	return nil
+}
+
+func (k jwk) publicKey() (interface{}, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
+
+func (v *verifier) getKey(ctx context.Context, kid string) (interface{}, error) {
+	v.mu.Lock()
+	key, ok := v.keys[kid]
+	refresh := !ok && time.Since(v.refreshedAt) > minKeyRefreshInterval
+	v.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+	// the issuer may have rotated its keys
+	if refresh {
+		if err := v.refreshKeys(ctx); err != nil {
+			return nil, err
+		}
+		v.mu.Lock()
+		key, ok = v.keys[kid]
+		v.mu.Unlock()
+		if ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown key ID %q", kid)
+}
+
+// verify returns an error unless the authorization header is a bearer token, signed by the issuer, for the audience.
+func (v *verifier) verify(ctx context.Context, authorization string) error {
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return fmt.Errorf("missing bearer token")
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(strings.TrimPrefix(authorization, "Bearer "), claims, func(t *jwt.Token) (interface{}, error) {
+		switch t.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA:
+		default:
+			return nil, fmt.Errorf("unsupported signing method %q", t.Method.Alg())
+		}
+		kid, _ := t.Header["kid"].(string)
+		return v.getKey(ctx, kid)
+	})
+	if err != nil {
+		return err
+	}
+	if !claims.VerifyIssuer(v.issuer, true) && !claims.VerifyIssuer(v.issuer+"/", true) {
+		return fmt.Errorf("invalid issuer")
+	}
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return fmt.Errorf("token is expired")
+	}
+	if !hasAudience(claims["aud"], v.audience) {
+		return fmt.Errorf("invalid audience")
+	}
+	return nil
+}
+
+// the "aud" claim is either a string, or an array of strings
+func hasAudience(aud interface{}, audience string) bool {
+	switch x := aud.(type) {
+	case string:
+		return x == audience
+	case []interface{}:
+		for _, y := range x {
+			if y == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
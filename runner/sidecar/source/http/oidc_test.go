@@ -0,0 +1,84 @@
+package http
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/form3tech-oss/jwt-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifier(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"issuer": server.URL, "jwks_uri": server.URL + "/keys"})
+	})
+	mux.HandleFunc("/keys", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []jwk{{
+			Kid: "my-kid",
+			Kty: "RSA",
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	v, err := newVerifier(ctx, dfv1.OIDC{Issuer: server.URL, Audience: "my-aud"})
+	assert.NoError(t, err)
+	sign := func(claims jwt.MapClaims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "my-kid"
+		s, err := token.SignedString(key)
+		assert.NoError(t, err)
+		return "Bearer " + s
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, v.verify(ctx, sign(jwt.MapClaims{"iss": server.URL, "aud": "my-aud", "exp": exp})))
+	})
+	t.Run("AudienceArray", func(t *testing.T) {
+		assert.NoError(t, v.verify(ctx, sign(jwt.MapClaims{"iss": server.URL, "aud": []string{"other", "my-aud"}, "exp": exp})))
+	})
+	t.Run("MissingToken", func(t *testing.T) {
+		assert.EqualError(t, v.verify(ctx, ""), "missing bearer token")
+	})
+	t.Run("WrongIssuer", func(t *testing.T) {
+		assert.EqualError(t, v.verify(ctx, sign(jwt.MapClaims{"iss": "other", "aud": "my-aud", "exp": exp})), "invalid issuer")
+	})
+	t.Run("WrongAudience", func(t *testing.T) {
+		assert.EqualError(t, v.verify(ctx, sign(jwt.MapClaims{"iss": server.URL, "aud": "other", "exp": exp})), "invalid audience")
+	})
+	t.Run("NoExpiry", func(t *testing.T) {
+		assert.EqualError(t, v.verify(ctx, sign(jwt.MapClaims{"iss": server.URL, "aud": "my-aud"})), "token is expired")
+	})
+	t.Run("Expired", func(t *testing.T) {
+		assert.Error(t, v.verify(ctx, sign(jwt.MapClaims{"iss": server.URL, "aud": "my-aud", "exp": time.Now().Add(-time.Hour).Unix()})))
+	})
+	t.Run("HMAC", func(t *testing.T) {
+		s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"iss": server.URL, "aud": "my-aud", "exp": exp}).SignedString([]byte("secret"))
+		assert.NoError(t, err)
+		assert.Error(t, v.verify(ctx, "Bearer "+s))
+	})
+	t.Run("WrongKey", func(t *testing.T) {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"iss": server.URL, "aud": "my-aud", "exp": exp})
+		token.Header["kid"] = "my-kid"
+		s, err := token.SignedString(other)
+		assert.NoError(t, err)
+		assert.Error(t, v.verify(ctx, "Bearer "+s))
+	})
+}
@@ -0,0 +1,40 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// LoadCertificate loads the certificate and key the source serves from their secrets.
+func LoadCertificate(ctx context.Context, secretInterface corev1.SecretInterface, x dfv1.TLS) (*tls.Certificate, error) {
+	cs, ks := x.CertSecret, x.KeySecret
+	if cs == nil || ks == nil {
+		return nil, fmt.Errorf("tls: both clientCertSecret and clientKeySecret are required")
+	}
+	certSecret, err := secretInterface.Get(ctx, cs.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cert secret, %w", err)
+	}
+	cert, ok := certSecret.Data[cs.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in cert secret", cs.Key)
+	}
+	keySecret, err := secretInterface.Get(ctx, ks.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get key secret, %w", err)
+	}
+	key, ok := keySecret.Data[ks.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %q not found in key secret", ks.Key)
+	}
+	cer, err := tls.X509KeyPair(cert, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load certificate: %w", err)
+	}
+	return &cer, nil
+}
@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestLoadCertificate(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), DNSNames: []string{"my-svc"}, NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	secrets := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cert"},
+		Data: map[string][]byte{
+			"tls.crt": pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		},
+	}).CoreV1().Secrets("")
+	ref := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-cert"}, Key: key}
+	}
+	t.Run("OK", func(t *testing.T) {
+		cer, err := LoadCertificate(ctx, secrets, dfv1.TLS{CertSecret: ref("tls.crt"), KeySecret: ref("tls.key")})
+		assert.NoError(t, err)
+		assert.NotNil(t, cer)
+	})
+	t.Run("MissingKey", func(t *testing.T) {
+		_, err := LoadCertificate(ctx, secrets, dfv1.TLS{CertSecret: ref("tls.crt")})
+		assert.Error(t, err)
+	})
+	t.Run("KeyNotFound", func(t *testing.T) {
+		_, err := LoadCertificate(ctx, secrets, dfv1.TLS{CertSecret: ref("tls.crt"), KeySecret: ref("not-found")})
+		assert.EqualError(t, err, `key "not-found" not found in key secret`)
+	})
+}
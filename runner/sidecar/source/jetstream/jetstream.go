@@ -26,13 +26,20 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 	if err != nil {
 		return nil, err
 	}
+	return Subscribe(ctx, conn, cluster, namespace, pipelineName, stepName, sourceURN, sourceName, x.Subject, process)
+}
+
+// Subscribe creates a source that consumes the subject using a durable queue subscription. The source closes the
+// connection when it is closed.
+func Subscribe(ctx context.Context, conn *nats.Conn, cluster, namespace, pipelineName, stepName, sourceURN, sourceName, subject string, process source.Process) (source.Interface, error) {
 	js, err := conn.JetStream()
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
 	queueName := sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName)
-	durableName := fmt.Sprintf("%s-%s", queueName, sharedutil.MustHash(x.Subject))
-	sub, err := js.QueueSubscribe(x.Subject, queueName, func(msg *nats.Msg) {
+	durableName := fmt.Sprintf("%s-%s", queueName, sharedutil.MustHash(subject))
+	sub, err := js.QueueSubscribe(subject, queueName, func(msg *nats.Msg) {
 		span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("jetstream-source-%s", sourceName))
 		defer span.Finish()
 		if metadata, err := msg.Metadata(); err != nil {
@@ -53,6 +60,7 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 		}
 	}, nats.ManualAck(), nats.Durable(durableName), nats.DeliverNew())
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
 
@@ -0,0 +1,78 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+)
+
+// watermarks are a partition's low and high offsets when the source started.
+type watermarks struct {
+	low, high int64
+}
+
+// getWatermarks returns the watermarks of every partition of the topics the source consumes.
+func (s *kafkaSource) getWatermarks() (map[topicPartition]watermarks, error) {
+	metadata, err := s.consumer.GetMetadata(nil, true, 10*seconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get metadata: %w", err)
+	}
+	ends := map[topicPartition]watermarks{}
+	for name, topic := range metadata.Topics {
+		if !s.spec.Consumes(name) {
+			continue
+		}
+		for _, p := range topic.Partitions {
+			low, high, err := s.consumer.QueryWatermarkOffsets(name, p.ID, 10*seconds)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get topic %q partition %d watermarks: %w", name, p.ID, err)
+			}
+			ends[topicPartition{name, p.ID}] = watermarks{low, high}
+		}
+	}
+	if len(ends) == 0 {
+		return nil, fmt.Errorf("no topics matching %q found", s.spec.GetTopics())
+	}
+	return ends, nil
+}
+
+// IsDrained returns true once the consumer group has committed every message that existed when the source started.
+// As the offsets are the group's, every replica agrees, including replicas that are not assigned any partitions.
+func (s *kafkaSource) IsDrained(context.Context) (bool, error) {
+	var partitions []kafka.TopicPartition
+	for tp := range s.watermarks {
+		topic := tp.topic
+		partitions = append(partitions, kafka.TopicPartition{Topic: &topic, Partition: tp.partition})
+	}
+	committed, err := s.consumer.Committed(partitions, 10*seconds)
+	if err != nil {
+		return false, fmt.Errorf("failed to get committed offsets: %w", err)
+	}
+	return drained(s.watermarks, committed, s.spec.GetAutoOffsetReset() == "latest"), nil
+}
+
+// drained returns true if every partition is empty, or its committed offset is at or after its high watermark. A
+// partition without a committed offset is only drained if the consumer starts at the latest offset.
+func drained(ends map[topicPartition]watermarks, committed []kafka.TopicPartition, latest bool) bool {
+	offsets := map[topicPartition]kafka.Offset{}
+	for _, p := range committed {
+		offsets[newTopicPartition(p)] = p.Offset
+	}
+	for tp, w := range ends {
+		if w.high <= w.low {
+			continue
+		}
+		offset, ok := offsets[tp]
+		if !ok || offset < 0 {
+			if latest {
+				continue
+			}
+			return false
+		}
+		if int64(offset) < w.high {
+			return false
+		}
+	}
+	return true
+}
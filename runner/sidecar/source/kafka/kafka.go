@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -21,15 +22,36 @@ import (
 )
 
 type kafkaSource struct {
-	logger     logr.Logger
-	sourceName string
-	sourceURN  string
-	consumer   *kafka.Consumer
-	topic      string
-	wg         *sync.WaitGroup
-	channels   map[int32]chan *kafka.Message
-	process    source.Process
-	totalLag   int64
+	logger      logr.Logger
+	cluster     string
+	namespace   string
+	sourceName  string
+	sourceURN   string
+	consumer    *kafka.Consumer
+	spec        dfv1.KafkaSource
+	wg          *sync.WaitGroup
+	channels    map[topicPartition]chan *kafka.Message
+	process     source.Process
+	dlq         source.Process
+	recordEvent func(eventType, reason, message string)
+	fail        func(error) // stops the sidecar, when the source cannot continue
+	totalLag    int64
+	txn         *transaction // nil unless the source is transactional
+	bounded     bool
+	watermarks  map[topicPartition]watermarks // nil unless the source is bounded
+
+	mu               sync.Mutex
+	partitionPending map[string]uint64 // nil until we have stats
+	assigned         bool              // whether partitions were assigned since they were last revoked
+}
+
+type topicPartition struct {
+	topic     string
+	partition int32
+}
+
+func newTopicPartition(p kafka.TopicPartition) topicPartition {
+	return topicPartition{*p.Topic, p.Partition}
 }
 
 const (
@@ -37,7 +59,7 @@ const (
 	pendingUnavailable = math.MinInt32
 )
 
-func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN string, replica int, x dfv1.KafkaSource, process source.Process) (source.Interface, error) {
+func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN string, replica int, x dfv1.KafkaSource, bounded bool, process, dlq source.Process, recordEvent func(eventType, reason, message string), fail func(error), transactionalProducer *kafka.Producer) (source.Interface, error) {
 	logger := sharedutil.NewLogger().WithValues("source", sourceName)
 	config, err := sharedkafka.GetConfig(ctx, secretInterface, x.KafkaConfig)
 	if err != nil {
@@ -52,6 +74,9 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 	config["enable.auto.commit"] = false
 	config["enable.auto.offset.store"] = false
 	config["auto.offset.reset"] = x.GetAutoOffsetReset()
+	if x.OffsetOutOfRange != "" {
+		config["auto.offset.reset"] = "error" // we reset offsets ourselves, see partitionError
+	}
 	config["statistics.interval.ms"] = 5 * seconds
 	// https://docs.confluent.io/cloud/current/client-apps/optimizing/throughput.html
 	config["fetch.min.bytes"] = x.GetFetchMinBytes()
@@ -73,18 +98,34 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 	}, 3*time.Second, 1.2, true)
 
 	s := &kafkaSource{
-		logger:     logger,
-		sourceName: sourceName,
-		sourceURN:  sourceURN,
-		consumer:   consumer,
-		topic:      x.Topic,
-		channels:   map[int32]chan *kafka.Message{}, // partition -> messages
-		wg:         &sync.WaitGroup{},
-		process:    process,
-		totalLag:   pendingUnavailable,
-	}
-
-	if err = consumer.Subscribe(x.Topic, func(consumer *kafka.Consumer, event kafka.Event) error {
+		logger:      logger,
+		cluster:     cluster,
+		namespace:   namespace,
+		sourceName:  sourceName,
+		sourceURN:   sourceURN,
+		consumer:    consumer,
+		spec:        x,
+		channels:    map[topicPartition]chan *kafka.Message{}, // partition -> messages
+		wg:          &sync.WaitGroup{},
+		process:     process,
+		dlq:         dlq,
+		recordEvent: recordEvent,
+		fail:        fail,
+		totalLag:    pendingUnavailable,
+		bounded:     bounded,
+	}
+	if transactionalProducer != nil {
+		s.txn = newTransaction(transactionalProducer)
+	}
+	if bounded {
+		// we must get the watermarks before we consume, so messages produced after we start are not waited for
+		if s.watermarks, err = s.getWatermarks(); err != nil {
+			return nil, err
+		}
+		s.logger.Info("bounded source", "partitions", len(s.watermarks))
+	}
+
+	if err = consumer.SubscribeTopics(x.GetTopics(), func(consumer *kafka.Consumer, event kafka.Event) error {
 		return s.rebalanced(ctx, event)
 	}); err != nil {
 		return nil, err
@@ -96,13 +137,24 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 }
 
 func (s *kafkaSource) processMessage(ctx context.Context, msg *kafka.Message) error {
+	if !matchesHeaders(s.spec.MatchHeaders, msg.Headers) {
+		return nil // skipped, but committed like any other message
+	}
 	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("kafka-source-%s", s.sourceName))
 	defer span.Finish()
+	ctx = source.ContextWithHeader(ctx, func(key string) string {
+		for _, h := range msg.Headers {
+			if h.Key == key {
+				return string(h.Value)
+			}
+		}
+		return ""
+	})
 	return s.process(
 		dfv1.ContextWithMeta(
 			ctx,
 			dfv1.Meta{
-				Source: s.sourceURN,
+				Source: s.getSourceURN(*msg.TopicPartition.Topic),
 				ID:     fmt.Sprintf("%d-%d", msg.TopicPartition.Partition, msg.TopicPartition.Offset),
 				Time:   msg.Timestamp.Unix(),
 			},
@@ -111,13 +163,43 @@ func (s *kafkaSource) processMessage(ctx context.Context, msg *kafka.Message) er
 	)
 }
 
-func (s *kafkaSource) assignedPartition(ctx context.Context, partition int32) {
-	logger := s.logger.WithValues("partition", partition)
-	if _, ok := s.channels[partition]; !ok {
+// matchesHeaders returns whether the headers match every rule. A rule matches if any header with its key matches.
+func matchesHeaders(rules []dfv1.KafkaHeaderMatch, headers []kafka.Header) bool {
+	for _, r := range rules {
+		matches := r.Matches(nil, false)
+		for _, h := range headers {
+			if h.Key == r.Key && r.Matches(h.Value, true) {
+				matches = true
+				break
+			}
+		}
+		if !matches {
+			return false
+		}
+	}
+	return true
+}
+
+// getSourceURN returns the URN of the topic a message was consumed from, as the source may consume several topics.
+func (s *kafkaSource) getSourceURN(topic string) string {
+	if topic == s.spec.Topic {
+		return s.sourceURN
+	}
+	k := s.spec.Kafka
+	k.Topic = topic
+	return k.GenURN(s.cluster, s.namespace)
+}
+
+func (s *kafkaSource) assignedPartition(ctx context.Context, tp topicPartition) {
+	if s.txn != nil {
+		return // messages are processed by the poll loop, in transactions
+	}
+	logger := s.logger.WithValues("topic", tp.topic, "partition", tp.partition)
+	if _, ok := s.channels[tp]; !ok {
 		logger.Info("assigned partition")
-		s.channels[partition] = make(chan *kafka.Message, 256)
+		s.channels[tp] = make(chan *kafka.Message, 256)
 		go wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
-			s.consumePartition(ctx, partition)
+			s.consumePartition(ctx, tp)
 		}, 3*time.Second, 1.2, true)
 	}
 }
@@ -126,13 +208,21 @@ func (s *kafkaSource) startPollLoop(ctx context.Context) {
 	s.logger.Info("starting poll loop")
 	for {
 		// shutdown will be blocked for the amount of time we specify here
-		ev := s.consumer.Poll(5 * 1000)
+		ev := s.consumer.Poll(s.pollTimeoutMs())
 		select {
 		case <-ctx.Done():
 			return
 		default:
 			switch e := ev.(type) {
 			case *kafka.Message:
+				if err := e.TopicPartition.Error; err != nil {
+					s.partitionError(ctx, e.TopicPartition)
+					break
+				}
+				if s.txn != nil {
+					s.processInTransaction(ctx, e)
+					break
+				}
 				func() {
 					defer func() {
 						// Fact 1 - if you send a message on a closed channel, you get a panic.
@@ -142,7 +232,7 @@ func (s *kafkaSource) startPollLoop(ctx context.Context) {
 							s.logger.Info("recovered from panic while queuing message", "recover", fmt.Sprint(r))
 						}
 					}()
-					s.channels[e.TopicPartition.Partition] <- e
+					s.channels[newTopicPartition(e.TopicPartition)] <- e
 				}()
 			case *kafka.Stats:
 				// https://github.com/edenhill/librdkafka/wiki/Consumer-lag-monitoring
@@ -151,7 +241,10 @@ func (s *kafkaSource) startPollLoop(ctx context.Context) {
 				if err := json.Unmarshal([]byte(e.String()), stats); err != nil {
 					s.logger.Error(err, "failed to unmarshall stats")
 				} else {
-					s.totalLag = stats.totalLag(s.topic)
+					s.totalLag = stats.totalLag(s.spec.Consumes)
+					s.mu.Lock()
+					s.partitionPending = stats.partitionPending(s.spec.Consumes)
+					s.mu.Unlock()
 				}
 			case kafka.Error:
 				s.logger.Info("poll error", "error", fmt.Errorf("%v", e))
@@ -160,11 +253,21 @@ func (s *kafkaSource) startPollLoop(ctx context.Context) {
 			default:
 				s.logger.Info("ignored event", "event", ev)
 			}
+			if s.txn != nil {
+				s.commitTransactionIfDue(ctx)
+			}
 		}
 	}
 }
 
 func (s *kafkaSource) Close() error {
+	if s.txn != nil {
+		s.logger.Info("committing open transaction")
+		// bound how long we retry committing for, so we do not block shutdown
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		s.closeTransaction(ctx)
+	}
 	s.logger.Info("closing partition channels")
 	for _, ch := range s.channels {
 		close(ch)
@@ -185,19 +288,168 @@ func (s *kafkaSource) GetPending(context.Context) (uint64, error) {
 	}
 }
 
+func (s *kafkaSource) GetPartitionPending(context.Context) (map[string]uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.partitionPending == nil {
+		return nil, source.ErrPendingUnavailable
+	}
+	pending := make(map[string]uint64, len(s.partitionPending))
+	for partition, n := range s.partitionPending {
+		pending[partition] = n
+	}
+	return pending, nil
+}
+
 func (s *kafkaSource) rebalanced(ctx context.Context, event kafka.Event) error {
 	s.logger.Info("re-balance", "event", event.String())
 	switch e := event.(type) {
+	case kafka.RevokedPartitions:
+		s.setAssigned(false)
+		if s.txn != nil {
+			// we must commit before the partitions are assigned to another consumer, or it will consume the same
+			// messages again
+			s.commitTransaction(ctx)
+		}
 	case kafka.AssignedPartitions:
 		for _, p := range e.Partitions {
-			s.assignedPartition(ctx, p.Partition)
+			s.assignedPartition(ctx, newTopicPartition(p))
 		}
+		if len(s.spec.StartOffsets) > 0 {
+			if err := s.assignStartOffsets(e.Partitions); err != nil {
+				return err
+			}
+		}
+		// a replica may be assigned no partitions, if there are more replicas than partitions, but it is still subscribed
+		s.setAssigned(true)
+	}
+	return nil
+}
+
+func (s *kafkaSource) setAssigned(assigned bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assigned = assigned
+}
+
+// IsConnected returns true once the consumer has joined its group and been assigned partitions, and false while the
+// group re-balances.
+func (s *kafkaSource) IsConnected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.assigned
+}
+
+// assignStartOffsets assigns the partitions, starting any partition without a committed offset at its start offset.
+// If we do not call Assign, the consumer assigns the partitions itself, starting at the committed offset or
+// auto.offset.reset.
+func (s *kafkaSource) assignStartOffsets(partitions []kafka.TopicPartition) error {
+	committed, err := s.consumer.Committed(partitions, 10*seconds)
+	if err != nil {
+		return fmt.Errorf("failed to get committed offsets: %w", err)
+	}
+	for i, p := range committed {
+		if offset, ok := s.spec.GetStartOffset(p.Partition); ok && p.Offset < 0 && *p.Topic == s.spec.Topic {
+			s.logger.Info("starting partition at start offset", "partition", p.Partition, "offset", offset)
+			committed[i].Offset = kafka.Offset(offset)
+		}
+	}
+	return s.consumer.Assign(committed)
+}
+
+// GetCommittedOffsets returns the consumer group's committed offsets for every partition of the topic, keyed by
+// partition, in the same form as KafkaSource.StartOffsets. Partitions without a committed offset are omitted.
+func GetCommittedOffsets(ctx context.Context, secretInterface corev1.SecretInterface, groupID string, x dfv1.KafkaSource) (map[string]int64, error) {
+	config, err := sharedkafka.GetConfig(ctx, secretInterface, x.KafkaConfig)
+	if err != nil {
+		return nil, err
+	}
+	config["group.id"] = groupID
+	consumer, err := kafka.NewConsumer(&config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = consumer.Close() }()
+	metadata, err := consumer.GetMetadata(&x.Topic, false, 10*seconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get topic %q metadata: %w", x.Topic, err)
+	}
+	var partitions []kafka.TopicPartition
+	for _, p := range metadata.Topics[x.Topic].Partitions {
+		partitions = append(partitions, kafka.TopicPartition{Topic: &x.Topic, Partition: p.ID})
+	}
+	committed, err := consumer.Committed(partitions, 10*seconds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get committed offsets: %w", err)
+	}
+	offsets := map[string]int64{}
+	for _, p := range committed {
+		if p.Offset >= 0 {
+			offsets[strconv.Itoa(int(p.Partition))] = int64(p.Offset)
+		}
+	}
+	return offsets, nil
+}
+
+// ResetOffsets commits offsets for every partition of the topics the source consumes, so the consumer group starts
+// consuming at the earliest offset, or the earliest offset at or after the time. Partitions without messages after the
+// time are reset to their end. The consumer group must be empty.
+func ResetOffsets(ctx context.Context, secretInterface corev1.SecretInterface, groupID string, x dfv1.KafkaSource, offset dfv1.ResetOffset) error {
+	t, err := offset.GetTime()
+	if err != nil {
+		return err
+	}
+	config, err := sharedkafka.GetConfig(ctx, secretInterface, x.KafkaConfig)
+	if err != nil {
+		return err
+	}
+	config["group.id"] = groupID
+	consumer, err := kafka.NewConsumer(&config)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = consumer.Close() }()
+	metadata, err := consumer.GetMetadata(nil, true, 10*seconds)
+	if err != nil {
+		return fmt.Errorf("failed to get metadata: %w", err)
+	}
+	var partitions []kafka.TopicPartition
+	for name, topic := range metadata.Topics {
+		if !x.Consumes(name) {
+			continue
+		}
+		name := name
+		for _, p := range topic.Partitions {
+			partitions = append(partitions, kafka.TopicPartition{Topic: &name, Partition: p.ID, Offset: kafka.Offset(t.UnixNano() / int64(time.Millisecond))})
+		}
+	}
+	if len(partitions) == 0 {
+		return fmt.Errorf("no topics matching %q found", x.GetTopics())
+	}
+	if !t.IsZero() {
+		if partitions, err = consumer.OffsetsForTimes(partitions, 10*seconds); err != nil {
+			return fmt.Errorf("failed to get offsets for %v: %w", t, err)
+		}
+	}
+	for i, p := range partitions {
+		low, high, err := consumer.QueryWatermarkOffsets(*p.Topic, p.Partition, 10*seconds)
+		if err != nil {
+			return fmt.Errorf("failed to get topic %q partition %d watermarks: %w", *p.Topic, p.Partition, err)
+		}
+		if t.IsZero() {
+			partitions[i].Offset = kafka.Offset(low)
+		} else if p.Offset < 0 {
+			partitions[i].Offset = kafka.Offset(high)
+		}
+	}
+	if _, err := consumer.CommitOffsets(partitions); err != nil {
+		return fmt.Errorf("failed to commit offsets: %w", err)
 	}
 	return nil
 }
 
-func (s *kafkaSource) consumePartition(ctx context.Context, partition int32) {
-	logger := s.logger.WithValues("partition", partition)
+func (s *kafkaSource) consumePartition(ctx context.Context, tp topicPartition) {
+	logger := s.logger.WithValues("topic", tp.topic, "partition", tp.partition)
 	logger.Info("consuming partition")
 	s.wg.Add(1)
 	var lastUncommitted *kafka.Message
@@ -221,7 +473,7 @@ func (s *kafkaSource) consumePartition(ctx context.Context, partition int32) {
 		select {
 		case <-ticker.C:
 			commitLastUncommitted()
-		case msg, ok := <-s.channels[partition]:
+		case msg, ok := <-s.channels[tp]:
 			if !ok {
 				return
 			}
@@ -232,6 +484,10 @@ func (s *kafkaSource) consumePartition(ctx context.Context, partition int32) {
 					logger.Info("failed to process message", "err", err.Error())
 				} else {
 					logger.Error(err, "failed to process message")
+					if s.bounded {
+						// the message has been sent to the DLQ, and a bounded source must not wait for it forever
+						lastUncommitted = msg
+					}
 				}
 			} else {
 				lastUncommitted = msg
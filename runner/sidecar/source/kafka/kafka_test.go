@@ -0,0 +1,80 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_matchesHeaders(t *testing.T) {
+	headers := []kafka.Header{{Key: "team", Value: []byte("a")}, {Key: "team", Value: []byte("b")}, {Key: "type", Value: []byte("order")}}
+	assert.True(t, matchesHeaders(nil, headers))
+	assert.True(t, matchesHeaders([]dfv1.KafkaHeaderMatch{{Key: "team", Value: "b"}, {Key: "type"}}, headers))
+	assert.False(t, matchesHeaders([]dfv1.KafkaHeaderMatch{{Key: "team", Value: "c"}}, headers))
+	assert.False(t, matchesHeaders([]dfv1.KafkaHeaderMatch{{Key: "team"}, {Key: "region"}}, headers))
+	assert.False(t, matchesHeaders([]dfv1.KafkaHeaderMatch{{Key: "type"}}, nil))
+}
+
+func TestStats_partitionPending(t *testing.T) {
+	stats := &Stats{}
+	err := json.Unmarshal([]byte(`{"topics":{
+"my-topic":{"partitions":{"-1":{"consumer_lag":-1},"0":{"consumer_lag":3},"1":{"consumer_lag":-1}}},
+"other":{"partitions":{"0":{"consumer_lag":5}}}
+}}`), stats)
+	assert.NoError(t, err)
+	pending := stats.partitionPending(func(topic string) bool { return topic == "my-topic" })
+	assert.Equal(t, map[string]uint64{"my-topic-0": 3}, pending)
+}
+
+func Test_drained(t *testing.T) {
+	topic := "my-topic"
+	committed := func(offsets ...kafka.Offset) []kafka.TopicPartition {
+		var partitions []kafka.TopicPartition
+		for i, offset := range offsets {
+			partitions = append(partitions, kafka.TopicPartition{Topic: &topic, Partition: int32(i), Offset: offset})
+		}
+		return partitions
+	}
+	ends := map[topicPartition]watermarks{{topic, 0}: {0, 3}, {topic, 1}: {2, 2}}
+	assert.True(t, drained(ends, committed(3, kafka.OffsetInvalid), false))
+	assert.True(t, drained(ends, committed(4, 0), false), "messages produced after we started")
+	assert.False(t, drained(ends, committed(2, 2), false))
+	assert.False(t, drained(ends, committed(kafka.OffsetInvalid, 2), false))
+	assert.True(t, drained(ends, committed(kafka.OffsetInvalid, 2), true), "latest never consumes existing messages")
+	assert.False(t, drained(ends, nil, false))
+}
+
+func Test_offsetOutOfRange(t *testing.T) {
+	assert.Equal(t, `topic "my-topic" partition 1 offset 5 is out of range [10, 20), 5 messages expired before they were consumed`, offsetOutOfRange{Topic: "my-topic", Partition: 1, Offset: 5, Low: 10, High: 20}.String())
+	assert.Equal(t, `topic "my-topic" partition 1 offset 30 is out of range [10, 20)`, offsetOutOfRange{Topic: "my-topic", Partition: 1, Offset: 30, Low: 10, High: 20}.String())
+}
+
+func Test_resetOffset(t *testing.T) {
+	_, _, reset := resetOffset(dfv1.KafkaOffsetOutOfRangeFail)
+	assert.False(t, reset)
+	offset, _, reset := resetOffset(dfv1.KafkaOffsetOutOfRangeEarliest)
+	assert.True(t, reset)
+	assert.Equal(t, kafka.OffsetBeginning, offset)
+	offset, _, reset = resetOffset(dfv1.KafkaOffsetOutOfRangeLatest)
+	assert.True(t, reset)
+	assert.Equal(t, kafka.OffsetEnd, offset)
+	offset, action, reset := resetOffset(dfv1.KafkaOffsetOutOfRangeDeadLetterQueue)
+	assert.True(t, reset)
+	assert.Equal(t, kafka.OffsetBeginning, offset)
+	assert.Contains(t, action, "dead-letter queue")
+}
+
+func Test_rebalanced(t *testing.T) {
+	ctx := context.Background()
+	s := &kafkaSource{logger: sharedutil.NewLogger(), channels: map[topicPartition]chan *kafka.Message{}}
+	assert.False(t, s.IsConnected())
+	assert.NoError(t, s.rebalanced(ctx, kafka.AssignedPartitions{}))
+	assert.True(t, s.IsConnected(), "no partitions to assign, but subscribed")
+	assert.NoError(t, s.rebalanced(ctx, kafka.RevokedPartitions{}))
+	assert.False(t, s.IsConnected())
+}
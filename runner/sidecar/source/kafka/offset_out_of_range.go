@@ -0,0 +1,92 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// offsetOutOfRange is a partition whose committed offset is out of range, as noted in the dead-letter queue.
+type offsetOutOfRange struct {
+	Topic     string `json:"topic"`
+	Partition int32  `json:"partition"`
+	Offset    int64  `json:"offset"` // the committed offset
+	Low       int64  `json:"low"`    // the partition's earliest offset
+	High      int64  `json:"high"`   // the offset the partition's next message will have
+}
+
+func (x offsetOutOfRange) String() string {
+	s := fmt.Sprintf("topic %q partition %d offset %d is out of range [%d, %d)", x.Topic, x.Partition, x.Offset, x.Low, x.High)
+	if x.Offset < x.Low {
+		s += fmt.Sprintf(", %d messages expired before they were consumed", x.Low-x.Offset)
+	}
+	return s
+}
+
+// resetOffset returns the offset to reset the partition to, and what is done, or false if it must not be reset.
+func resetOffset(policy dfv1.KafkaOffsetOutOfRange) (kafka.Offset, string, bool) {
+	switch policy {
+	case dfv1.KafkaOffsetOutOfRangeEarliest:
+		return kafka.OffsetBeginning, "consuming from the earliest offset", true
+	case dfv1.KafkaOffsetOutOfRangeLatest:
+		return kafka.OffsetEnd, "consuming from the latest offset", true
+	case dfv1.KafkaOffsetOutOfRangeDeadLetterQueue:
+		return kafka.OffsetBeginning, "consuming from the earliest offset, and noting it in the dead-letter queue", true
+	default:
+		return 0, "not consuming the partition until its offset is reset", false
+	}
+}
+
+// partitionError handles a consumer error for a partition. The only one we act on is the one we get, rather than the
+// consumer resetting the partition itself, when the source has an offsetOutOfRange policy.
+func (s *kafkaSource) partitionError(ctx context.Context, tp kafka.TopicPartition) {
+	logger := s.logger.WithValues("topic", *tp.Topic, "partition", tp.Partition, "offset", tp.Offset)
+	if err, ok := tp.Error.(kafka.Error); !ok || err.Code() != kafka.ErrAutoOffsetReset {
+		logger.Info("partition error", "error", tp.Error.Error())
+		return
+	}
+	// without a committed offset, the partition starts at its start offset, as it would without a policy
+	if tp.Offset < 0 {
+		offset := kafka.OffsetEnd
+		if s.spec.GetAutoOffsetReset() == "earliest" {
+			offset = kafka.OffsetBeginning
+		}
+		logger.Info("no committed offset, starting partition at start offset", "startOffset", s.spec.StartOffset)
+		if err := s.consumer.Seek(kafka.TopicPartition{Topic: tp.Topic, Partition: tp.Partition, Offset: offset}, 10*seconds); err != nil {
+			logger.Error(err, "failed to seek partition")
+		}
+		return
+	}
+	x := offsetOutOfRange{Topic: *tp.Topic, Partition: tp.Partition, Offset: int64(tp.Offset)}
+	if low, high, err := s.consumer.QueryWatermarkOffsets(x.Topic, x.Partition, 10*seconds); err != nil {
+		logger.Error(err, "failed to get partition watermarks")
+	} else {
+		x.Low, x.High = low, high
+	}
+	offset, action, reset := resetOffset(s.spec.OffsetOutOfRange)
+	message := fmt.Sprintf("source %q %s, %s", s.sourceName, x, action)
+	logger.Error(errors.New(message), "offset out of range", "policy", s.spec.OffsetOutOfRange)
+	s.recordEvent(corev1.EventTypeWarning, "OffsetOutOfRange", message)
+	if !reset {
+		return
+	}
+	if s.spec.OffsetOutOfRange == dfv1.KafkaOffsetOutOfRangeDeadLetterQueue {
+		ctx := dfv1.ContextWithMeta(ctx, dfv1.Meta{
+			Source: s.getSourceURN(x.Topic),
+			ID:     fmt.Sprintf("%d-%d", x.Partition, x.Offset),
+			Time:   time.Now().Unix(),
+		})
+		if err := s.dlq(ctx, []byte(sharedutil.MustJSON(map[string]interface{}{"offsetOutOfRange": x}))); err != nil {
+			logger.Error(err, "failed to note offset out of range in the dead-letter queue")
+		}
+	}
+	if err := s.consumer.Seek(kafka.TopicPartition{Topic: tp.Topic, Partition: tp.Partition, Offset: offset}, 10*seconds); err != nil {
+		logger.Error(err, "failed to seek partition")
+	}
+}
@@ -8,10 +8,34 @@ type Stats struct {
 	} `json:"topics"`
 }
 
-func (s Stats) totalLag(topic string) int64 {
+// totalLag returns the total lag of the topics that are consumed.
+func (s Stats) totalLag(consumes func(topic string) bool) int64 {
 	var totalLag int64
-	for _, p := range s.Topics[topic].Partitions {
-		totalLag += p.ConsumerLag
+	for topic, t := range s.Topics {
+		if !consumes(topic) {
+			continue
+		}
+		for _, p := range t.Partitions {
+			totalLag += p.ConsumerLag
+		}
 	}
 	return totalLag
 }
+
+// partitionPending returns the lag of each of the consumed topics' partitions that are assigned to this consumer, keyed
+// by "{topic}-{partition}". Unassigned partitions, and librdkafka's internal "-1" partition, have no lag.
+func (s Stats) partitionPending(consumes func(topic string) bool) map[string]uint64 {
+	pending := map[string]uint64{}
+	for topic, t := range s.Topics {
+		if !consumes(topic) {
+			continue
+		}
+		for partition, p := range t.Partitions {
+			if partition == "-1" || p.ConsumerLag < 0 {
+				continue
+			}
+			pending[topic+"-"+partition] = uint64(p.ConsumerLag)
+		}
+	}
+	return pending
+}
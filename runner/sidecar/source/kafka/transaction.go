@@ -0,0 +1,173 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/confluentinc/confluent-kafka-go/kafka"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// transaction is the Kafka transaction that a transactional source's messages are processed in. The messages
+// produced to the step's sinks, and the offsets of the consumed messages, are committed together, or not at all.
+type transaction struct {
+	mu        sync.Mutex
+	producer  *kafka.Producer
+	open      bool
+	closed    bool
+	startedAt time.Time
+	n         int
+	first     map[topicPartition]kafka.Offset // offset of each partition's first message in the transaction, to rewind to on abort
+	next      map[topicPartition]kafka.Offset // offset to commit for each partition
+}
+
+func newTransaction(producer *kafka.Producer) *transaction {
+	return &transaction{producer: producer, first: map[topicPartition]kafka.Offset{}, next: map[topicPartition]kafka.Offset{}}
+}
+
+func (t *transaction) reset() {
+	t.open = false
+	t.n = 0
+	t.first = map[topicPartition]kafka.Offset{}
+	t.next = map[topicPartition]kafka.Offset{}
+}
+
+// pollTimeoutMs returns how long to poll for, so a transaction is committed within a second even if no more messages
+// arrive.
+func (s *kafkaSource) pollTimeoutMs() int {
+	if s.txn != nil {
+		return 1 * seconds
+	}
+	return 5 * seconds
+}
+
+// processInTransaction processes the message in the open transaction, beginning one if needed. If the message fails,
+// the transaction is aborted, and the consumer rewound, so its messages are processed again in a new transaction.
+func (s *kafkaSource) processInTransaction(ctx context.Context, msg *kafka.Message) {
+	t := s.txn
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return // not committed, so it will be consumed again
+	}
+	tp := newTopicPartition(msg.TopicPartition)
+	if _, ok := t.first[tp]; !ok {
+		t.first[tp] = msg.TopicPartition.Offset
+	}
+	if !t.open {
+		if err := t.producer.BeginTransaction(); err != nil {
+			s.abortTransaction(ctx, fmt.Errorf("failed to begin transaction: %w", err))
+			return
+		}
+		t.open = true
+		t.startedAt = time.Now()
+	}
+	if err := s.processMessage(ctx, msg); err != nil {
+		s.abortTransaction(ctx, fmt.Errorf("failed to process message at offset %d of topic %q partition %d: %w", msg.TopicPartition.Offset, tp.topic, tp.partition, err))
+		return
+	}
+	t.next[tp] = msg.TopicPartition.Offset + 1
+	t.n++
+	if t.n >= dfv1.CommitN {
+		s.commitOpenTransaction(ctx)
+	}
+}
+
+func (s *kafkaSource) commitTransactionIfDue(ctx context.Context) {
+	t := s.txn
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.open && time.Since(t.startedAt) >= time.Second {
+		s.commitOpenTransaction(ctx)
+	}
+}
+
+func (s *kafkaSource) commitTransaction(ctx context.Context) {
+	t := s.txn
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s.commitOpenTransaction(ctx)
+}
+
+func (s *kafkaSource) closeTransaction(ctx context.Context) {
+	t := s.txn
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	s.commitOpenTransaction(ctx)
+	t.closed = true
+}
+
+// commitOpenTransaction sends the consumed offsets to the open transaction, and commits it. The caller must hold the
+// transaction's lock.
+func (s *kafkaSource) commitOpenTransaction(ctx context.Context) {
+	t := s.txn
+	if !t.open {
+		return
+	}
+	metadata, err := s.consumer.GetConsumerGroupMetadata()
+	if err != nil {
+		s.abortTransaction(ctx, fmt.Errorf("failed to get consumer group metadata: %w", err))
+		return
+	}
+	var offsets []kafka.TopicPartition
+	for tp, offset := range t.next {
+		topic := tp.topic
+		offsets = append(offsets, kafka.TopicPartition{Topic: &topic, Partition: tp.partition, Offset: offset})
+	}
+	if err := t.producer.SendOffsetsToTransaction(ctx, offsets, metadata); err != nil {
+		s.abortTransaction(ctx, fmt.Errorf("failed to send offsets to transaction: %w", err))
+		return
+	}
+	backoff := wait.Backoff{Duration: 100 * time.Millisecond, Factor: 2, Jitter: 0.1, Steps: math.MaxInt32, Cap: 5 * time.Second}
+	for {
+		err := t.producer.CommitTransaction(ctx)
+		if err == nil {
+			s.logger.V(1).Info("committed transaction", "messages", t.n)
+			t.reset()
+			return
+		}
+		var kafkaErr kafka.Error
+		if !errors.As(err, &kafkaErr) || !kafkaErr.IsRetriable() {
+			s.abortTransaction(ctx, fmt.Errorf("failed to commit transaction: %w", err))
+			return
+		}
+		s.logger.Info("retrying commit of transaction", "error", err.Error())
+		select {
+		case <-ctx.Done():
+			s.abortTransaction(ctx, fmt.Errorf("failed to commit transaction: %w", ctx.Err()))
+			return
+		case <-time.After(backoff.Step()):
+		}
+	}
+}
+
+// abortTransaction aborts the open transaction, and rewinds each of its partitions to its first message. The caller
+// must hold the transaction's lock. If the producer has failed, e.g. because it was fenced by a newer instance of this
+// replica, it cannot be used again, so the transaction is closed, and the sidecar stops, and is restarted.
+func (s *kafkaSource) abortTransaction(ctx context.Context, cause error) {
+	t := s.txn
+	s.logger.Error(cause, "aborting transaction", "messages", t.n)
+	if t.open {
+		if err := t.producer.AbortTransaction(ctx); err != nil {
+			s.logger.Error(err, "failed to abort transaction")
+		}
+	}
+	if err := t.producer.GetFatalError(); err != nil {
+		t.reset()
+		t.closed = true // not committed, so its messages will be consumed again after the restart
+		s.fail(fmt.Errorf("transactional producer failed: %w", err))
+		return
+	}
+	for tp, offset := range t.first {
+		topic := tp.topic
+		if err := s.consumer.Seek(kafka.TopicPartition{Topic: &topic, Partition: tp.partition, Offset: offset}, 10*seconds); err != nil {
+			s.logger.Info("failed to rewind partition, it may have been revoked", "topic", topic, "partition", tp.partition, "offset", offset, "error", err.Error())
+		}
+	}
+	t.reset()
+}
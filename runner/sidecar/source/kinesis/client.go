@@ -0,0 +1,78 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+)
+
+// client makes the few requests to the Kinesis API that the source needs.
+type client struct {
+	client *kinesis.Client
+}
+
+// newClient returns a client for the region, sending requests to the endpoint, if specified.
+func newClient(region string, credentials aws.CredentialsProvider, endpoint *dfv1.AWSEndpoint) *client {
+	options := kinesis.Options{
+		Region:      region,
+		Credentials: credentials,
+		HTTPClient:  &http.Client{Timeout: 30 * time.Second},
+	}
+	if e := endpoint; e != nil {
+		options.EndpointResolver = kinesis.EndpointResolverFunc(func(region string, options kinesis.EndpointResolverOptions) (aws.Endpoint, error) {
+			return aws.Endpoint{URL: e.URL, SigningRegion: region, HostnameImmutable: true}, nil
+		})
+	}
+	return &client{client: kinesis.New(options)}
+}
+
+// listShards returns the IDs of the stream's shards, including closed shards that still have records.
+func (c *client) listShards(ctx context.Context, stream string) ([]string, error) {
+	var shardIDs []string
+	in := &kinesis.ListShardsInput{StreamName: aws.String(stream)}
+	for {
+		output, err := c.client.ListShards(ctx, in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list shards: %w", err)
+		}
+		for _, s := range output.Shards {
+			shardIDs = append(shardIDs, aws.ToString(s.ShardId))
+		}
+		if aws.ToString(output.NextToken) == "" {
+			return shardIDs, nil
+		}
+		// the stream's name must not be specified with the token
+		in = &kinesis.ListShardsInput{NextToken: output.NextToken}
+	}
+}
+
+// getShardIterator returns an iterator for the records after the sequence number, or, if there is no sequence number,
+// for the records at the iterator type's position.
+func (c *client) getShardIterator(ctx context.Context, stream, shardID, sequenceNumber, iteratorType string) (string, error) {
+	in := &kinesis.GetShardIteratorInput{StreamName: aws.String(stream), ShardId: aws.String(shardID), ShardIteratorType: types.ShardIteratorType(iteratorType)}
+	if sequenceNumber != "" {
+		in.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		in.StartingSequenceNumber = aws.String(sequenceNumber)
+	}
+	output, err := c.client.GetShardIterator(ctx, in)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shard iterator: %w", err)
+	}
+	return aws.ToString(output.ShardIterator), nil
+}
+
+// getRecords returns up to limit records, the iterator for the next records, which is "" if the shard is closed and
+// there are no more records, and how far behind the latest record the records are.
+func (c *client) getRecords(ctx context.Context, iterator string, limit uint32) ([]types.Record, string, time.Duration, error) {
+	output, err := c.client.GetRecords(ctx, &kinesis.GetRecordsInput{ShardIterator: aws.String(iterator), Limit: aws.Int32(int32(limit))})
+	if err != nil {
+		return nil, "", 0, fmt.Errorf("failed to get records: %w", err)
+	}
+	return output.Records, aws.ToString(output.NextShardIterator), time.Duration(aws.ToInt64(output.MillisBehindLatest)) * time.Millisecond, nil
+}
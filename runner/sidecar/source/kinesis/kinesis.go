@@ -0,0 +1,309 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	awsshared "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/aws"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/dynamic"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type kinesisSource struct {
+	ctx        context.Context // records are processed with this context, so they are not cancelled if the shard is revoked
+	client     *client
+	store      offsetStore
+	sourceName string
+	sourceURN  string
+	x          dfv1.KinesisSource
+	process    source.Process
+	replica    int
+	replicas   func() int
+	mu         sync.Mutex
+	shards     map[string]*shardReader // by shard ID
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// shardReader reads one shard's records.
+type shardReader struct {
+	cancel    context.CancelFunc
+	done      chan struct{}
+	mu        sync.Mutex
+	position  string // the sequence number of the last record processed
+	committed string
+	lag       *time.Duration // how far behind the latest record the last records read were, nil until known
+}
+
+func (r *shardReader) getPosition() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.position
+}
+
+func (r *shardReader) setPosition(sequenceNumber string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.position = sequenceNumber
+}
+
+func (r *shardReader) getLag() *time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lag
+}
+
+func (r *shardReader) setLag(lag time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lag = &lag
+}
+
+// New creates a source that reads the shards assigned to the replica. Every interval, it re-lists the stream's shards,
+// and the replicas, so it reads new shards after the stream is re-sharded, and hands shards over after the step is
+// scaled, and it commits each shard's position.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, steps dynamic.ResourceInterface, pipelineName, stepName, sourceName, sourceURN string, replica int, replicas func() int, x dfv1.KinesisSource, process source.Process, interval time.Duration) (source.Interface, error) {
+	credentials, err := awsshared.GetCredentials(ctx, secretInterface, x.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	c := newClient(x.Region, credentials, x.Endpoint)
+	// check we can access the stream, so misconfiguration is reported at startup
+	if _, err := c.listShards(ctx, x.Stream); err != nil {
+		return nil, fmt.Errorf("failed to list shards of stream %q: %w", x.Stream, err)
+	}
+	store := &statusStore{steps: steps, stepName: pipelineName + "-" + stepName, sourceName: sourceName}
+	return start(ctx, c, store, sourceName, sourceURN, replica, replicas, x, process, interval), nil
+}
+
+func start(ctx context.Context, c *client, store offsetStore, sourceName, sourceURN string, replica int, replicas func() int, x dfv1.KinesisSource, process source.Process, interval time.Duration) *kinesisSource {
+	s := &kinesisSource{
+		ctx:        ctx,
+		client:     c,
+		store:      store,
+		sourceName: sourceName,
+		sourceURN:  sourceURN,
+		x:          x,
+		process:    process,
+		replica:    replica,
+		replicas:   replicas,
+		shards:     map[string]*shardReader{},
+		done:       make(chan struct{}),
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(s.done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			if err := s.rebalance(ctx); err != nil && ctx.Err() == nil {
+				logger.Error(err, "failed to assign shards", "source", sourceName)
+			}
+			s.commit(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+	return s
+}
+
+// assignedShards returns the shards assigned to the replica, i.e. every replicas-th shard in order of ID, starting at
+// the replica's index, so each shard is assigned to exactly one replica.
+func assignedShards(shardIDs []string, replica, replicas int) map[string]bool {
+	if replicas < 1 {
+		replicas = 1
+	}
+	sorted := append([]string(nil), shardIDs...)
+	sort.Strings(sorted)
+	assigned := map[string]bool{}
+	for i, shardID := range sorted {
+		if i%replicas == replica {
+			assigned[shardID] = true
+		}
+	}
+	return assigned
+}
+
+// rebalance stops reading the shards no longer assigned to the replica, committing their positions, and starts
+// reading newly assigned shards from their committed positions.
+func (s *kinesisSource) rebalance(ctx context.Context) error {
+	shardIDs, err := s.client.listShards(ctx, s.x.Stream)
+	if err != nil {
+		return err
+	}
+	assigned := assignedShards(shardIDs, s.replica, s.replicas())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	revoked := false
+	for shardID, r := range s.shards {
+		if !assigned[shardID] {
+			logger.Info("shard revoked", "source", s.sourceName, "shardId", shardID)
+			r.cancel()
+			<-r.done
+			revoked = true
+		}
+	}
+	if revoked {
+		s.commitLocked(ctx)
+		for shardID := range s.shards {
+			if !assigned[shardID] {
+				delete(s.shards, shardID)
+			}
+		}
+	}
+	var sequenceNumbers map[string]string
+	for shardID := range assigned {
+		if _, ok := s.shards[shardID]; ok {
+			continue
+		}
+		if sequenceNumbers == nil {
+			if sequenceNumbers, err = s.store.load(ctx); err != nil {
+				return err
+			}
+		}
+		logger.Info("shard assigned", "source", s.sourceName, "shardId", shardID, "sequenceNumber", sequenceNumbers[shardID])
+		s.shards[shardID] = s.read(ctx, shardID, sequenceNumbers[shardID])
+	}
+	return nil
+}
+
+// read starts reading the shard's records after the sequence number.
+func (s *kinesisSource) read(ctx context.Context, shardID, sequenceNumber string) *shardReader {
+	ctx, cancel := context.WithCancel(ctx)
+	r := &shardReader{cancel: cancel, done: make(chan struct{}), position: sequenceNumber, committed: sequenceNumber}
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(r.done)
+		logger := logger.WithValues("source", s.sourceName, "shardId", shardID)
+		iterator := ""
+		for {
+			if iterator == "" {
+				var err error
+				// iterators expire after five minutes, so we get a new one after any error
+				if iterator, err = s.client.getShardIterator(ctx, s.x.Stream, shardID, r.getPosition(), s.x.StartPosition.GetShardIteratorType()); err != nil && ctx.Err() == nil {
+					logger.Error(err, "failed to get shard iterator")
+				}
+			}
+			if iterator != "" {
+				records, next, lag, err := s.client.getRecords(ctx, iterator, s.x.GetLimit())
+				if err != nil {
+					if ctx.Err() == nil {
+						logger.Error(err, "failed to get records")
+					}
+					iterator = ""
+				} else {
+					for _, x := range records {
+						if ctx.Err() != nil {
+							return
+						}
+						s.processRecord(shardID, x)
+						r.setPosition(aws.ToString(x.SequenceNumber))
+					}
+					r.setLag(lag)
+					if next == "" {
+						logger.Info("shard is closed, and every record has been read")
+						return
+					}
+					iterator = next
+					if len(records) > 0 {
+						continue
+					}
+				}
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(s.x.GetPollPeriod()):
+			}
+		}
+	}()
+	return r
+}
+
+func (s *kinesisSource) processRecord(shardID string, x types.Record) {
+	span, ctx := opentracing.StartSpanFromContext(s.ctx, fmt.Sprintf("kinesis-source-%s", s.sourceName))
+	defer span.Finish()
+	if err := s.process(
+		dfv1.ContextWithMeta(ctx, dfv1.Meta{
+			Source: s.sourceURN,
+			ID:     shardID + "-" + aws.ToString(x.SequenceNumber),
+			Time:   aws.ToTime(x.ApproximateArrivalTimestamp).Unix(),
+		}),
+		x.Data,
+	); err != nil {
+		logger.Error(err, "failed to process record", "source", s.sourceName, "shardId", shardID, "sequenceNumber", aws.ToString(x.SequenceNumber))
+	}
+}
+
+func (s *kinesisSource) commit(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.commitLocked(ctx)
+}
+
+// commitLocked commits the positions of the shards that have changed since they were last committed.
+func (s *kinesisSource) commitLocked(ctx context.Context) {
+	sequenceNumbers := map[string]string{}
+	for shardID, r := range s.shards {
+		if p := r.getPosition(); p != r.committed {
+			sequenceNumbers[shardID] = p
+		}
+	}
+	if len(sequenceNumbers) == 0 {
+		return
+	}
+	if err := s.store.commit(ctx, sequenceNumbers); err != nil {
+		logger.Error(err, "failed to commit sequence numbers", "source", s.sourceName)
+		return
+	}
+	for shardID, p := range sequenceNumbers {
+		s.shards[shardID].committed = p
+	}
+}
+
+// GetPartitionLag returns how far behind the latest record each shard read by this replica is. Kinesis does not report
+// how many records are pending.
+func (s *kinesisSource) GetPartitionLag(context.Context) (map[string]time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lags := map[string]time.Duration{}
+	for shardID, r := range s.shards {
+		if lag := r.getLag(); lag != nil {
+			lags[shardID] = *lag
+		}
+	}
+	if len(lags) == 0 {
+		return nil, source.ErrPendingUnavailable
+	}
+	return lags, nil
+}
+
+// Close stops reading every shard, once the records being processed are done, and commits their positions.
+func (s *kinesisSource) Close() error {
+	s.cancel()
+	<-s.done
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.shards {
+		r.cancel()
+		<-r.done
+	}
+	s.commitLocked(context.Background())
+	return nil
+}
@@ -0,0 +1,111 @@
+package kinesis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_assignedShards(t *testing.T) {
+	shardIDs := []string{"shard-2", "shard-0", "shard-1"}
+	assert.Equal(t, map[string]bool{"shard-0": true, "shard-1": true, "shard-2": true}, assignedShards(shardIDs, 0, 0))
+	assert.Equal(t, map[string]bool{"shard-0": true, "shard-2": true}, assignedShards(shardIDs, 0, 2))
+	assert.Equal(t, map[string]bool{"shard-1": true}, assignedShards(shardIDs, 1, 2))
+	assert.Empty(t, assignedShards(shardIDs, 3, 4))
+}
+
+type memoryStore struct {
+	mu              sync.Mutex
+	sequenceNumbers map[string]string
+}
+
+func (s *memoryStore) load(context.Context) (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	x := map[string]string{}
+	for k, v := range s.sequenceNumbers {
+		x[k] = v
+	}
+	return x, nil
+}
+
+func (s *memoryStore) commit(_ context.Context, sequenceNumbers map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, v := range sequenceNumbers {
+		s.sequenceNumbers[k] = v
+	}
+	return nil
+}
+
+func Test_kinesisSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=my-access-key-id/"))
+		in := map[string]interface{}{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+		switch r.Header.Get("X-Amz-Target") {
+		case "Kinesis_20131202.ListShards":
+			if in["StreamName"] == "other" {
+				w.WriteHeader(400)
+				_, _ = w.Write([]byte(`{"__type": "ResourceNotFoundException", "message": "no such stream"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"Shards": [{"ShardId": "shard-1"}, {"ShardId": "shard-0"}]}`))
+		case "Kinesis_20131202.GetShardIterator":
+			// only shard-0 is assigned to replica 0 of 2, and it resumes after the committed sequence number
+			assert.Equal(t, "shard-0", in["ShardId"])
+			assert.Equal(t, "AFTER_SEQUENCE_NUMBER", in["ShardIteratorType"])
+			assert.Equal(t, "1", in["StartingSequenceNumber"])
+			_, _ = w.Write([]byte(`{"ShardIterator": "iterator-0"}`))
+		case "Kinesis_20131202.GetRecords":
+			switch in["ShardIterator"] {
+			case "iterator-0":
+				_, _ = w.Write([]byte(`{"Records": [{"Data": "Zm9v", "PartitionKey": "a", "SequenceNumber": "2", "ApproximateArrivalTimestamp": 1630461784.5}], "NextShardIterator": "iterator-1", "MillisBehindLatest": 1500}`))
+			default:
+				// the shard is closed, and every record has been read
+				_, _ = w.Write([]byte(`{"Records": []}`))
+			}
+		default:
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(`{"__type": "UnknownOperationException", "message": "no such operation"}`))
+		}
+	}))
+	defer server.Close()
+	c := newClient("us-west-2", aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "my-access-key-id", SecretAccessKey: "my-secret-access-key"}, nil
+	}), &dfv1.AWSEndpoint{URL: server.URL})
+	store := &memoryStore{sequenceNumbers: map[string]string{"shard-0": "1"}}
+	processed := make(chan string, 1)
+	s := start(context.Background(), c, store, "my-source", "my-urn", 0, func() int { return 2 }, dfv1.KinesisSource{Stream: "my-stream"}, func(ctx context.Context, msg []byte) error {
+		meta, err := dfv1.MetaFromContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, dfv1.Meta{Source: "my-urn", ID: "shard-0-2", Time: 1630461784}, meta)
+		processed <- string(msg)
+		return nil
+	}, time.Hour)
+	assert.Equal(t, "foo", <-processed)
+	assert.Eventually(t, func() bool {
+		lags, err := s.GetPartitionLag(context.Background())
+		_, ok := lags["shard-0"]
+		return err == nil && ok
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.NoError(t, s.Close())
+	assert.Equal(t, map[string]string{"shard-0": "2"}, store.sequenceNumbers)
+	_, _, lag, err := c.getRecords(context.Background(), "iterator-0", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1500*time.Millisecond, lag)
+	_, err = c.listShards(context.Background(), "other")
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "failed to list shards")
+		assert.Contains(t, err.Error(), "ResourceNotFoundException: no such stream")
+	}
+}
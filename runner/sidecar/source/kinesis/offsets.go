@@ -0,0 +1,58 @@
+package kinesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+)
+
+// offsetStore loads and commits the sequence numbers of the last records processed from each shard.
+type offsetStore interface {
+	// load returns the committed sequence numbers, by shard ID.
+	load(ctx context.Context) (map[string]string, error)
+	commit(ctx context.Context, sequenceNumbers map[string]string) error
+}
+
+// statusStore stores sequence numbers in the step's status, by "{sourceName}/{shardId}". Each shard is only read by
+// one replica, so replicas do not overwrite each other's sequence numbers.
+type statusStore struct {
+	steps      dynamic.ResourceInterface
+	stepName   string
+	sourceName string
+}
+
+func (s *statusStore) load(ctx context.Context) (map[string]string, error) {
+	un, err := s.steps.Get(ctx, s.stepName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get step %q: %w", s.stepName, err)
+	}
+	offsets, _, err := unstructured.NestedStringMap(un.Object, "status", "sourceOffsets")
+	if err != nil {
+		return nil, err
+	}
+	sequenceNumbers := map[string]string{}
+	for k, v := range offsets {
+		if shardID := strings.TrimPrefix(k, s.sourceName+"/"); shardID != k {
+			sequenceNumbers[shardID] = v
+		}
+	}
+	return sequenceNumbers, nil
+}
+
+func (s *statusStore) commit(ctx context.Context, sequenceNumbers map[string]string) error {
+	offsets := map[string]string{}
+	for shardID, v := range sequenceNumbers {
+		offsets[s.sourceName+"/"+shardID] = v
+	}
+	patch := sharedutil.MustJSON(map[string]interface{}{"status": map[string]interface{}{"sourceOffsets": offsets}})
+	if _, err := s.steps.Patch(ctx, s.stepName, types.MergePatchType, []byte(patch), metav1.PatchOptions{}, "status"); err != nil {
+		return fmt.Errorf("failed to patch step %q status: %w", s.stepName, err)
+	}
+	return nil
+}
@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync/atomic"
 	"time"
 
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
 	httpsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/http"
 	"github.com/go-logr/logr"
@@ -17,8 +19,11 @@ import (
 )
 
 type loadBalanced struct {
-	httpSource source.Interface
-	jobs       workqueue.Interface
+	httpSource  source.Interface
+	jobs        workqueue.Interface
+	leadReplica bool
+	polled      int32 // 1 once the initial poll has queued its items
+	remaining   int64 // the number of polled items yet to be processed
 }
 
 func (s *loadBalanced) GetPending(context.Context) (uint64, error) {
@@ -28,6 +33,15 @@ func (s *loadBalanced) GetPending(context.Context) (uint64, error) {
 	return uint64(s.jobs.Len()), nil
 }
 
+// IsDrained returns true once the lead replica has processed the items of its initial poll. Only the lead replica
+// processes the items of a bounded source, so other replicas are always drained.
+func (s *loadBalanced) IsDrained(context.Context) (bool, error) {
+	if !s.leadReplica {
+		return true, nil
+	}
+	return atomic.LoadInt32(&s.polled) == 1 && atomic.LoadInt64(&s.remaining) == 0, nil
+}
+
 type NewReq struct {
 	Logger       logr.Logger
 	PipelineName string
@@ -35,11 +49,16 @@ type NewReq struct {
 	SourceName   string
 	SourceURN    string
 	LeadReplica  bool
-	Concurrency  int
-	PollPeriod   time.Duration
-	Process      source.Process
-	RemoveItem   func(item interface{}) error
-	ListItems    func() ([]interface{}, error)
+	// Bounded sources only poll once, and the lead replica processes the items itself, rather than load-balancing them
+	// across the replicas, as other replicas may complete before it does.
+	Bounded     bool
+	Concurrency int
+	PollPeriod  time.Duration
+	// Timeout is how long the lead replica waits for another replica to process an item, default 10s.
+	Timeout    time.Duration
+	Process    source.Process
+	RemoveItem func(item interface{}) error
+	ListItems  func() ([]interface{}, error)
 }
 
 func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq) (source.HasPending, error) {
@@ -47,10 +66,11 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq)
 	// (a) in the future we could use a named queue to expose metrics
 	// (b) it would be good to limit the size of this work queue and have the `Add
 	jobs := workqueue.New()
-	authorization, httpSource, err := httpsource.New(ctx, secretInterface, r.PipelineName, r.StepName, r.SourceURN, r.SourceName, r.Process)
+	authorization, httpSource, err := httpsource.New(ctx, secretInterface, r.PipelineName, r.StepName, r.SourceURN, r.SourceName, dfv1.HTTPSource{}, r.Process)
 	if err != nil {
 		return nil, err
 	}
+	s := &loadBalanced{httpSource: httpSource, jobs: jobs, leadReplica: r.LeadReplica}
 	if r.LeadReplica {
 		endpoint := "https://" + r.PipelineName + "-" + r.StepName + "/sources/" + r.SourceName
 		t := http.DefaultTransport.(*http.Transport).Clone()
@@ -58,7 +78,11 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq)
 		t.MaxConnsPerHost = 32
 		t.MaxIdleConnsPerHost = 32
 		t.TLSClientConfig.InsecureSkipVerify = true
-		httpClient := &http.Client{Timeout: 10 * time.Second, Transport: t}
+		timeout := r.Timeout
+		if timeout == 0 {
+			timeout = 10 * time.Second
+		}
+		httpClient := &http.Client{Timeout: timeout, Transport: t}
 
 		logger.Info("starting lead replica's workers", "source", r.SourceName, "endpoint", endpoint)
 		for w := 0; w < r.Concurrency; w++ {
@@ -72,6 +96,18 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq)
 					func() {
 						defer jobs.Done(item)
 						itemS := item.(string)
+						if r.Bounded {
+							defer atomic.AddInt64(&s.remaining, -1)
+							if err := r.Process(ctx, []byte(itemS)); err != nil {
+								logger.Error(err, "failed to process item", "item", item)
+							} else {
+								logger.Info("deleting item", "item", item)
+								if err := r.RemoveItem(item); err != nil {
+									logger.Error(err, "failed to delete item", "item", item)
+								}
+							}
+							return
+						}
 						req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBufferString(itemS))
 						if err != nil {
 							logger.Error(err, "failed to create request", "item", item)
@@ -124,6 +160,9 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq)
 				if err != nil {
 					logger.Error(err, "failed to list items")
 				} else {
+					if r.Bounded {
+						atomic.AddInt64(&s.remaining, int64(len(list)))
+					}
 					for _, item := range list {
 						jobs.Add(item)
 					}
@@ -131,7 +170,10 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq)
 			}
 			logger.Info("executing initial poll")
 			poll()
-			if r.PollPeriod > 0 {
+			atomic.StoreInt32(&s.polled, 1)
+			if r.Bounded {
+				logger.Info("polling loop disabled, the source is bounded")
+			} else if r.PollPeriod > 0 {
 				logger.Info("starting polling loop", "pollPeriod", r.PollPeriod)
 				ticker := time.NewTicker(r.PollPeriod)
 				defer ticker.Stop()
@@ -148,7 +190,7 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, r NewReq)
 			}
 		}()
 	}
-	return &loadBalanced{httpSource, jobs}, nil
+	return s, nil
 }
 
 func (s *loadBalanced) Close() error {
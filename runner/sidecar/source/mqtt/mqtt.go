@@ -0,0 +1,174 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+// connectTimeout is how long to wait to connect, and subscribe, before returning an error
+const connectTimeout = 30 * time.Second
+
+type mqttSource struct {
+	client mqtt.Client
+}
+
+// New creates a source that subscribes to the topic filter, using MQTT 5 if the protocol version is 5, otherwise
+// MQTT 3.1.1. The client ID must be unique to the replica. For QoS 1 and 2, the broker keeps the replica's session
+// while it is disconnected, so it receives the messages it missed when it reconnects. Messages are acknowledged once
+// they have been processed, or have failed to be processed.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, clientID, sourceName, sourceURN string, x dfv1.MQTTSource, process source.Process) (source.Interface, error) {
+	var password string
+	if s := x.PasswordSecret; s != nil {
+		var err error
+		if password, err = getSecretValue(ctx, secretInterface, s); err != nil {
+			return nil, err
+		}
+	}
+	var tlsConfig *tls.Config
+	if t := x.TLS; t != nil {
+		var err error
+		if tlsConfig, err = getTLSConfig(ctx, secretInterface, *t); err != nil {
+			return nil, fmt.Errorf("failed to get TLS config: %w", err)
+		}
+	}
+	handler := func(topic string, payload []byte) {
+		span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("mqtt-source-%s", sourceName))
+		defer span.Finish()
+		if err := process(
+			dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: uuid.New().String(), Time: time.Now().Unix()}),
+			payload,
+		); err != nil {
+			logger.Error(err, "failed to process message", "source", sourceName, "topic", topic)
+		}
+	}
+	if x.ProtocolVersion == 5 {
+		return newV5(ctx, clientID, sourceName, x, password, tlsConfig, handler)
+	}
+	return newV3(clientID, sourceName, x, password, tlsConfig, handler)
+}
+
+// newV3 creates an MQTT 3.1.1 source. MQTT 3.1.1 has no negative acknowledgements.
+func newV3(clientID, sourceName string, x dfv1.MQTTSource, password string, tlsConfig *tls.Config, handle func(topic string, payload []byte)) (source.Interface, error) {
+	opts := mqtt.NewClientOptions().
+		AddBroker(x.URL).
+		SetClientID(clientID).
+		SetCleanSession(x.QoS == 0).
+		SetAutoReconnect(true).
+		SetConnectTimeout(connectTimeout).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			logger.Error(err, "connection lost", "source", sourceName)
+		})
+	if x.Username != "" {
+		opts.SetUsername(x.Username)
+	}
+	if password != "" {
+		opts.SetPassword(password)
+	}
+	if tlsConfig != nil {
+		opts.SetTLSConfig(tlsConfig)
+	}
+	handler := func(_ mqtt.Client, m mqtt.Message) {
+		handle(m.Topic(), m.Payload())
+	}
+	subscribe := func(c mqtt.Client) error {
+		token := c.Subscribe(x.Topic, byte(x.QoS), handler)
+		if !token.WaitTimeout(connectTimeout) {
+			return fmt.Errorf("timed out subscribing to %q", x.Topic)
+		}
+		if err := token.Error(); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %w", x.Topic, err)
+		}
+		return nil
+	}
+	// the broker may not have kept the subscription, e.g. if it restarted without persistence, so we re-subscribe when
+	// we reconnect
+	var connected int32
+	opts.SetOnConnectHandler(func(c mqtt.Client) {
+		if atomic.CompareAndSwapInt32(&connected, 0, 1) {
+			return // the first connection subscribes below
+		}
+		logger.Info("reconnected, re-subscribing", "source", sourceName)
+		if err := subscribe(c); err != nil {
+			logger.Error(err, "failed to re-subscribe", "source", sourceName)
+		}
+	})
+	client := mqtt.NewClient(opts)
+	token := client.Connect()
+	if !token.WaitTimeout(connectTimeout) {
+		return nil, fmt.Errorf("timed out connecting to %q", x.URL)
+	}
+	if err := token.Error(); err != nil {
+		return nil, fmt.Errorf("failed to connect to %q: %w", x.URL, err)
+	}
+	if err := subscribe(client); err != nil {
+		client.Disconnect(0)
+		return nil, err
+	}
+	return &mqttSource{client: client}, nil
+}
+
+func (s *mqttSource) Close() error {
+	logger.Info("closing mqtt source connection")
+	s.client.Disconnect(uint((5 * time.Second).Milliseconds())) // waits for the message being processed
+	return nil
+}
+
+func getSecretValue(ctx context.Context, secretInterface corev1.SecretInterface, s *apiv1.SecretKeySelector) (string, error) {
+	secret, err := secretInterface.Get(ctx, s.Name, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %q: %w", s.Name, err)
+	}
+	v, ok := secret.Data[s.Key]
+	if !ok {
+		return "", fmt.Errorf("key %q not found in secret %q", s.Key, s.Name)
+	}
+	return string(v), nil
+}
+
+// getTLSConfig returns the TLS config, with the CA certificate to verify the broker with, if specified, rather than the
+// system's, and the client certificate to authenticate with, if specified.
+func getTLSConfig(ctx context.Context, secretInterface corev1.SecretInterface, t dfv1.TLS) (*tls.Config, error) {
+	c := &tls.Config{MinVersion: tls.VersionTLS12}
+	if s := t.CACertSecret; s != nil {
+		caCert, err := getSecretValue(ctx, secretInterface, s)
+		if err != nil {
+			return nil, err
+		}
+		c.RootCAs = x509.NewCertPool()
+		if !c.RootCAs.AppendCertsFromPEM([]byte(caCert)) {
+			return nil, fmt.Errorf("failed to parse CA certificate")
+		}
+	}
+	if cs, ks := t.CertSecret, t.KeySecret; cs != nil && ks != nil {
+		cert, err := getSecretValue(ctx, secretInterface, cs)
+		if err != nil {
+			return nil, err
+		}
+		key, err := getSecretValue(ctx, secretInterface, ks)
+		if err != nil {
+			return nil, err
+		}
+		pair, err := tls.X509KeyPair([]byte(cert), []byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate: %w", err)
+		}
+		c.Certificates = []tls.Certificate{pair}
+	}
+	return c, nil
+}
@@ -0,0 +1,106 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"math"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/eclipse/paho.golang/autopaho"
+	"github.com/eclipse/paho.golang/paho"
+)
+
+type mqtt5Source struct {
+	conn *autopaho.ConnectionManager
+}
+
+// newV5 creates an MQTT 5 source. For QoS 1 and 2, the session never expires, like an MQTT 3.1.1 session.
+func newV5(ctx context.Context, clientID, sourceName string, x dfv1.MQTTSource, password string, tlsConfig *tls.Config, handle func(topic string, payload []byte)) (source.Interface, error) {
+	u, err := url.Parse(x.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse URL %q: %w", x.URL, err)
+	}
+	subscribe := func(ctx context.Context, c *autopaho.ConnectionManager) error {
+		ctx, cancel := context.WithTimeout(ctx, connectTimeout)
+		defer cancel()
+		if _, err := c.Subscribe(ctx, &paho.Subscribe{Subscriptions: map[string]paho.SubscribeOptions{x.Topic: {QoS: byte(x.QoS)}}}); err != nil {
+			return fmt.Errorf("failed to subscribe to %q: %w", x.Topic, err)
+		}
+		return nil
+	}
+	// the broker may not have kept the subscription, e.g. if it restarted without persistence, so we re-subscribe when
+	// we reconnect
+	var connected int32
+	cfg := autopaho.ClientConfig{
+		BrokerUrls:     []*url.URL{u},
+		TlsCfg:         tlsConfig,
+		KeepAlive:      30,
+		ConnectTimeout: connectTimeout,
+		OnConnectionUp: func(c *autopaho.ConnectionManager, _ *paho.Connack) {
+			if atomic.CompareAndSwapInt32(&connected, 0, 1) {
+				return // the first connection subscribes below
+			}
+			logger.Info("reconnected, re-subscribing", "source", sourceName)
+			if err := subscribe(ctx, c); err != nil {
+				logger.Error(err, "failed to re-subscribe", "source", sourceName)
+			}
+		},
+		OnConnectError: func(err error) {
+			logger.Error(err, "failed to connect", "source", sourceName)
+		},
+		ClientConfig: paho.ClientConfig{
+			ClientID: clientID,
+			Router: paho.NewSingleHandlerRouter(func(p *paho.Publish) {
+				handle(p.Topic, p.Payload)
+			}),
+			OnClientError: func(err error) {
+				logger.Error(err, "connection lost", "source", sourceName)
+			},
+			OnServerDisconnect: func(d *paho.Disconnect) {
+				logger.Info("disconnected by broker", "source", sourceName, "reasonCode", d.ReasonCode)
+			},
+		},
+	}
+	cfg.SetUsernamePassword(x.Username, []byte(password))
+	cfg.SetConnectPacketConfigurator(withSession(x.QoS))
+	conn, err := autopaho.NewConnection(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %q: %w", x.URL, err)
+	}
+	connectCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+	if err := conn.AwaitConnection(connectCtx); err != nil {
+		_ = conn.Disconnect(context.Background())
+		return nil, fmt.Errorf("timed out connecting to %q: %w", x.URL, err)
+	}
+	if err := subscribe(ctx, conn); err != nil {
+		_ = conn.Disconnect(context.Background())
+		return nil, err
+	}
+	return &mqtt5Source{conn: conn}, nil
+}
+
+// withSession returns a function that configures the connect packet to keep the session while the replica is
+// disconnected, unless the QoS is 0.
+func withSession(qos uint32) func(*paho.Connect) *paho.Connect {
+	return func(c *paho.Connect) *paho.Connect {
+		c.CleanStart = qos == 0
+		if !c.CleanStart {
+			sessionExpiryInterval := uint32(math.MaxUint32) // never expires
+			c.Properties = &paho.ConnectProperties{SessionExpiryInterval: &sessionExpiryInterval}
+		}
+		return c
+	}
+}
+
+func (s *mqtt5Source) Close() error {
+	logger.Info("closing mqtt source connection")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second) // waits for the message being processed
+	defer cancel()
+	return s.conn.Disconnect(ctx)
+}
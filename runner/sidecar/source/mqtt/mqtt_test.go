@@ -0,0 +1,74 @@
+package mqtt
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/eclipse/paho.golang/paho"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func Test_getTLSConfig(t *testing.T) {
+	ctx := context.Background()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), DNSNames: []string{"my-client"}, NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	assert.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	secrets := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-cert"},
+		Data: map[string][]byte{
+			"ca.crt":  cert,
+			"tls.crt": cert,
+			"tls.key": pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+			"invalid": []byte("invalid"),
+		},
+	}).CoreV1().Secrets("")
+	ref := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "my-cert"}, Key: key}
+	}
+	t.Run("Default", func(t *testing.T) {
+		c, err := getTLSConfig(ctx, secrets, dfv1.TLS{})
+		assert.NoError(t, err)
+		assert.Nil(t, c.RootCAs)
+		assert.Empty(t, c.Certificates)
+	})
+	t.Run("CACertAndClientCert", func(t *testing.T) {
+		c, err := getTLSConfig(ctx, secrets, dfv1.TLS{CACertSecret: ref("ca.crt"), CertSecret: ref("tls.crt"), KeySecret: ref("tls.key")})
+		assert.NoError(t, err)
+		assert.NotNil(t, c.RootCAs)
+		assert.Len(t, c.Certificates, 1)
+	})
+	t.Run("InvalidCACert", func(t *testing.T) {
+		_, err := getTLSConfig(ctx, secrets, dfv1.TLS{CACertSecret: ref("invalid")})
+		assert.EqualError(t, err, "failed to parse CA certificate")
+	})
+	t.Run("KeyNotFound", func(t *testing.T) {
+		_, err := getTLSConfig(ctx, secrets, dfv1.TLS{CertSecret: ref("tls.crt"), KeySecret: ref("not-found")})
+		assert.EqualError(t, err, `key "not-found" not found in secret "my-cert"`)
+	})
+}
+
+func Test_withSession(t *testing.T) {
+	c := withSession(0)(&paho.Connect{})
+	assert.True(t, c.CleanStart)
+	assert.Nil(t, c.Properties)
+	c = withSession(1)(&paho.Connect{})
+	assert.False(t, c.CleanStart)
+	assert.Equal(t, uint32(math.MaxUint32), *c.Properties.SessionExpiryInterval)
+}
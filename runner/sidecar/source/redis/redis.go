@@ -0,0 +1,74 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedredis "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/redis"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type redisSource struct {
+	client *redis.Client
+	sub    *redis.PubSub
+	wg     sync.WaitGroup
+}
+
+// New creates a source that subscribes to the channel. Redis pub/sub has no acknowledgements or backlog, so messages
+// that fail to be processed, or are published while the source is not subscribed, are lost.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sourceName, sourceURN string, x dfv1.RedisSource, process source.Process) (source.Interface, error) {
+	client, err := sharedredis.NewClient(ctx, secretInterface, x.URL, x.PasswordSecret)
+	if err != nil {
+		return nil, err
+	}
+	var sub *redis.PubSub
+	if x.Pattern {
+		sub = client.PSubscribe(ctx, x.Channel)
+	} else {
+		sub = client.Subscribe(ctx, x.Channel)
+	}
+	// wait for the subscription to be confirmed, so we know it succeeded
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to subscribe to %q: %w", x.Channel, err)
+	}
+	s := &redisSource{client: client, sub: sub}
+	s.wg.Add(1)
+	go func() {
+		defer runtime.HandleCrash()
+		defer s.wg.Done()
+		// the channel is closed when the subscription is closed
+		for msg := range sub.Channel() {
+			span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("redis-source-%s", sourceName))
+			if err := process(
+				dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: uuid.New().String(), Time: time.Now().Unix()}),
+				[]byte(msg.Payload),
+			); err != nil {
+				logger.Error(err, "failed to process message", "source", sourceName, "channel", msg.Channel)
+			}
+			span.Finish()
+		}
+	}()
+	return s, nil
+}
+
+func (s *redisSource) Close() error {
+	logger.Info("closing redis source subscription")
+	if err := s.sub.Close(); err != nil {
+		return err
+	}
+	s.wg.Wait()
+	return s.client.Close()
+}
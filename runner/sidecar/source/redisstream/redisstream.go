@@ -0,0 +1,243 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedredis "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/redis"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/go-redis/redis/v8"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+// how long each read blocks waiting for new entries, and so how long closing the source may take
+const block = time.Second
+
+type redisStreamSource struct {
+	client     *redis.Client
+	sourceName string
+	sourceURN  string
+	consumer   string
+	group      string
+	x          dfv1.RedisStreamSource
+	process    source.Process
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+// New creates a source that reads the stream as the consumer, one of the group, creating the group if it does not
+// exist. Entries are acknowledged once they have been processed. Entries that are not, because they failed to be
+// processed, or the consumer that read them stopped, are claimed and retried once they have been idle for the min idle
+// time.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sourceName, sourceURN, consumer, group string, x dfv1.RedisStreamSource, process source.Process) (source.HasPending, error) {
+	client, err := sharedredis.NewClient(ctx, secretInterface, x.URL, x.PasswordSecret)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.XGroupCreateMkStream(ctx, x.Stream, group, x.GetStartID()).Err(); err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to create consumer group %q of stream %q: %w", group, x.Stream, err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &redisStreamSource{
+		client:     client,
+		sourceName: sourceName,
+		sourceURN:  sourceURN,
+		consumer:   consumer,
+		group:      group,
+		x:          x,
+		process:    process,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(s.done)
+		s.consume(ctx)
+	}()
+	return s, nil
+}
+
+func (s *redisStreamSource) consume(ctx context.Context) {
+	// "0" reads the entries already delivered to this consumer, e.g. before it restarted, and ">" reads new entries
+	id := "0"
+	claimed := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(claimed) >= s.x.GetMinIdleTime() {
+			if err := s.claim(ctx); err != nil && ctx.Err() == nil {
+				logger.Error(err, "failed to claim idle entries", "source", s.sourceName)
+			}
+			claimed = time.Now()
+		}
+		streams, err := s.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    s.group,
+			Consumer: s.consumer,
+			Streams:  []string{s.x.Stream, id},
+			Count:    s.x.GetCount(),
+			Block:    block,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue // no new entries
+		} else if err != nil {
+			if ctx.Err() == nil {
+				logger.Error(err, "failed to read stream", "source", s.sourceName, "stream", s.x.Stream)
+				time.Sleep(block)
+			}
+			continue
+		}
+		for _, stream := range streams {
+			if id != ">" && len(stream.Messages) == 0 {
+				id = ">"
+			}
+			for _, m := range stream.Messages {
+				s.handle(ctx, m)
+				if id != ">" {
+					id = m.ID
+				}
+			}
+		}
+	}
+}
+
+// claim claims the pending entries that have been idle for the min idle time, from any consumer, and processes them.
+// Entries that have been delivered the max deliveries are acknowledged without being processed.
+func (s *redisStreamSource) claim(ctx context.Context) error {
+	minIdleTime := s.x.GetMinIdleTime()
+	start := "-"
+	for {
+		pending, err := s.client.XPendingExt(ctx, &redis.XPendingExtArgs{
+			Stream: s.x.Stream,
+			Group:  s.group,
+			Start:  start,
+			End:    "+",
+			Count:  s.x.GetCount(),
+		}).Result()
+		if err != nil {
+			return fmt.Errorf("failed to list pending entries: %w", err)
+		}
+		if len(pending) == 0 {
+			return nil
+		}
+		var ids []string
+		for _, p := range pending {
+			if p.Idle < minIdleTime {
+				continue
+			}
+			if max := s.x.MaxDeliveries; max > 0 && p.RetryCount >= int64(max) {
+				logger.Info("dropping entry delivered too many times", "source", s.sourceName, "id", p.ID, "deliveries", p.RetryCount)
+				if err := s.client.XAck(ctx, s.x.Stream, s.group, p.ID).Err(); err != nil {
+					return fmt.Errorf("failed to acknowledge entry %q: %w", p.ID, err)
+				}
+				continue
+			}
+			ids = append(ids, p.ID)
+		}
+		if len(ids) > 0 {
+			// only the entries that are still idle are claimed, so each is claimed by just one consumer
+			messages, err := s.client.XClaim(ctx, &redis.XClaimArgs{
+				Stream:   s.x.Stream,
+				Group:    s.group,
+				Consumer: s.consumer,
+				MinIdle:  minIdleTime,
+				Messages: ids,
+			}).Result()
+			if err != nil {
+				return fmt.Errorf("failed to claim entries: %w", err)
+			}
+			for _, m := range messages {
+				s.handle(ctx, m)
+			}
+		}
+		start = "(" + pending[len(pending)-1].ID
+	}
+}
+
+// handle processes the entry, and acknowledges it. An entry that fails to be processed is left pending, so it is
+// claimed and retried once it has been idle for the min idle time.
+func (s *redisStreamSource) handle(ctx context.Context, m redis.XMessage) {
+	span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("redis-stream-source-%s", s.sourceName))
+	defer span.Finish()
+	msg, err := messageOf(m, s.x.GetField())
+	if err == nil {
+		err = s.process(dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: s.sourceURN, ID: m.ID, Time: timeOf(m.ID).Unix()}), msg)
+	}
+	if err != nil {
+		logger.Error(err, "failed to process entry", "source", s.sourceName, "id", m.ID)
+	} else if err := s.client.XAck(ctx, s.x.Stream, s.group, m.ID).Err(); err != nil {
+		logger.Error(err, "failed to acknowledge entry", "source", s.sourceName, "id", m.ID)
+	}
+}
+
+// messageOf returns the value of the entry's field, or, if it does not have the field, e.g. because it was added by
+// another producer, all of the entry's fields as a JSON object.
+func messageOf(m redis.XMessage, field string) ([]byte, error) {
+	if v, ok := m.Values[field]; ok {
+		return []byte(fmt.Sprint(v)), nil
+	}
+	return json.Marshal(m.Values)
+}
+
+// timeOf returns the time the entry was added, from its ID, which is "{milliseconds}-{sequence}", or the current time
+// if the ID was not generated by Redis.
+func timeOf(id string) time.Time {
+	if ms, err := strconv.ParseInt(strings.Split(id, "-")[0], 10, 64); err == nil {
+		return time.UnixMilli(ms)
+	}
+	return time.Now()
+}
+
+// Close stops reading, once the entry being processed is done. Entries that were read, but not processed, are claimed
+// by the other consumers, or by this one when it restarts.
+func (s *redisStreamSource) Close() error {
+	logger.Info("closing redis stream source")
+	s.cancel()
+	<-s.done
+	return s.client.Close()
+}
+
+// GetPending returns the number of entries in the group's pending entries list, i.e. delivered but not acknowledged,
+// and, on Redis 7 and later, the entries that have not been delivered to the group yet.
+func (s *redisStreamSource) GetPending(ctx context.Context) (uint64, error) {
+	v, err := s.client.Do(ctx, "XINFO", "GROUPS", s.x.Stream).Result()
+	if err != nil {
+		return 0, err
+	}
+	return pendingOf(v, s.group)
+}
+
+// pendingOf returns the sum of the group's "pending" and "lag", from the reply of XINFO GROUPS. The lag is absent before
+// Redis 7, and nil if Redis cannot determine it.
+func pendingOf(v interface{}, group string) (uint64, error) {
+	groups, ok := v.([]interface{})
+	if !ok {
+		return 0, fmt.Errorf("unexpected reply %v", v)
+	}
+	for _, g := range groups {
+		fields, ok := g.([]interface{})
+		if !ok {
+			return 0, fmt.Errorf("unexpected group %v", g)
+		}
+		info := map[string]interface{}{}
+		for i := 0; i+1 < len(fields); i += 2 {
+			info[fmt.Sprint(fields[i])] = fields[i+1]
+		}
+		if info["name"] != group {
+			continue
+		}
+		pending, _ := info["pending"].(int64)
+		lag, _ := info["lag"].(int64)
+		return uint64(pending + lag), nil
+	}
+	return 0, source.ErrPendingUnavailable
+}
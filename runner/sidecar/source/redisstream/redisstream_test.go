@@ -0,0 +1,53 @@
+package redisstream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_messageOf(t *testing.T) {
+	t.Run("Field", func(t *testing.T) {
+		msg, err := messageOf(redis.XMessage{Values: map[string]interface{}{"message": "my-msg", "other": "x"}}, "message")
+		assert.NoError(t, err)
+		assert.Equal(t, "my-msg", string(msg))
+	})
+	t.Run("NoField", func(t *testing.T) {
+		msg, err := messageOf(redis.XMessage{Values: map[string]interface{}{"a": "1", "b": "2"}}, "message")
+		assert.NoError(t, err)
+		assert.Equal(t, `{"a":"1","b":"2"}`, string(msg))
+	})
+}
+
+func Test_timeOf(t *testing.T) {
+	assert.Equal(t, time.UnixMilli(1526919030474), timeOf("1526919030474-55"))
+	assert.WithinDuration(t, time.Now(), timeOf("my-id"), time.Minute)
+}
+
+func Test_pendingOf(t *testing.T) {
+	reply := []interface{}{
+		[]interface{}{"name", "other-group", "consumers", int64(1), "pending", int64(100), "last-delivered-id", "0-0"},
+		[]interface{}{"name", "my-group", "consumers", int64(2), "pending", int64(3), "last-delivered-id", "1-0", "entries-read", int64(5), "lag", int64(7)},
+	}
+	t.Run("Lag", func(t *testing.T) {
+		pending, err := pendingOf(reply, "my-group")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(10), pending)
+	})
+	t.Run("NoLag", func(t *testing.T) {
+		pending, err := pendingOf(reply, "other-group")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), pending)
+	})
+	t.Run("NoGroup", func(t *testing.T) {
+		_, err := pendingOf(reply, "no-group")
+		assert.Equal(t, source.ErrPendingUnavailable, err)
+	})
+	t.Run("Unexpected", func(t *testing.T) {
+		_, err := pendingOf("foo", "my-group")
+		assert.EqualError(t, err, "unexpected reply foo")
+	})
+}
@@ -1,69 +1,72 @@
 package s3
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
+	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	awsshared "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/aws"
+	sharedsqs "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/sqs"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/loadbalanced"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-logr/logr"
 	"github.com/opentracing/opentracing-go"
-	apierr "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
+const (
+	// how long each receive of notifications waits for them to arrive
+	notificationsWaitTime = 20 * time.Second
+	// the longest line of an object whose lines are emitted
+	maxLineSize = 16 * 1024 * 1024
+	// how long the lead replica waits for an object's lines to be processed
+	linesTimeout = time.Hour
+)
+
 type message struct {
 	Key  string `json:"key"`
 	Path string `json:"path"`
 }
 
-func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceName, sourceURN string, x dfv1.S3Source, process source.Process, leadReplica bool) (source.HasPending, error) {
+// api is the part of the S3 client the source uses.
+type api interface {
+	s3.ListObjectsV2APIClient
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+	CopyObject(ctx context.Context, params *s3.CopyObjectInput, optFns ...func(*s3.Options)) (*s3.CopyObjectOutput, error)
+	GetObjectTagging(ctx context.Context, params *s3.GetObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error)
+	PutObjectTagging(ctx context.Context, params *s3.PutObjectTaggingInput, optFns ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error)
+}
+
+type bucket struct {
+	logger logr.Logger
+	client api
+	x      dfv1.S3Source
+}
+
+func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceName, sourceURN string, x dfv1.S3Source, bounded bool, process source.Process, leadReplica bool) (source.HasPending, error) {
 	logger := sharedutil.NewLogger().WithValues("source", x.Name, "bucket", x.Bucket)
-	var accessKeyID string
-	{
-		secretName := x.Credentials.AccessKeyID.Name
-		secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get secret %q: %w", secretName, err)
-		}
-		accessKeyID = string(secret.Data[x.Credentials.AccessKeyID.Key])
-	}
-	var secretAccessKey string
-	{
-		secretName := x.Credentials.SecretAccessKey.Name
-		secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
-		if err != nil {
-			return nil, fmt.Errorf("failed to get secret %q: %w", secretName, err)
-		}
-		secretAccessKey = string(secret.Data[x.Credentials.SecretAccessKey.Key])
-	}
-	var sessionToken string
-	{
-		secretName := x.Credentials.SessionToken.Name
-		secret, err := secretInterface.Get(ctx, secretName, metav1.GetOptions{})
-		if err == nil {
-			sessionToken = string(secret.Data[x.Credentials.SessionToken.Key])
-		} else {
-			// it is okay for sessionToken to be missing
-			if !apierr.IsNotFound(err) {
-				return nil, err
-			}
-		}
+	credentials, err := awsshared.GetCredentials(ctx, secretInterface, x.Credentials)
+	if err != nil {
+		return nil, err
 	}
 	options := s3.Options{
-		Region: x.Region,
-		Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
-			return aws.Credentials{AccessKeyID: accessKeyID, SecretAccessKey: secretAccessKey, SessionToken: sessionToken}, nil
-		}),
+		Region:      x.Region,
+		Credentials: credentials,
 	}
 	if e := x.Endpoint; e != nil {
 		options.EndpointResolver = s3.EndpointResolverFunc(func(region string, options s3.EndpointResolverOptions) (aws.Endpoint, error) {
@@ -76,7 +79,27 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineNa
 		return nil, fmt.Errorf("failed to create %q: %w", dir, err)
 	}
 
-	client := s3.New(options)
+	b := bucket{logger: logger, client: s3.New(options), x: x}
+
+	listItems := func() ([]interface{}, error) { return b.list(ctx) }
+	pollPeriod := x.PollPeriod.Duration
+	if n := x.Notifications; n != nil {
+		notifications := sharedsqs.NewClient(n.QueueURL, "", credentials, x.Endpoint, notificationsWaitTime+10*time.Second)
+		// the bucket is listed once, for the objects created before the source started, e.g. while it was restarting
+		listed := false
+		listItems = func() ([]interface{}, error) {
+			if !listed {
+				listed = true
+				return b.list(ctx)
+			}
+			return b.receive(ctx, notifications)
+		}
+		pollPeriod = time.Second // each receive waits for notifications to arrive
+	}
+	var timeout time.Duration
+	if x.GetEmit() == dfv1.S3EmitLines {
+		timeout = linesTimeout
+	}
 
 	return loadbalanced.New(ctx, secretInterface, loadbalanced.NewReq{
 		Logger:       logger,
@@ -85,62 +108,232 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineNa
 		SourceName:   sourceName,
 		SourceURN:    sourceURN,
 		LeadReplica:  leadReplica,
+		Bounded:      bounded,
 		Concurrency:  int(x.Concurrency),
-		PollPeriod:   x.PollPeriod.Duration,
+		PollPeriod:   pollPeriod,
+		Timeout:      timeout,
 
 		Process: func(ctx context.Context, msg []byte) error {
 			span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("s3-source-%s", sourceName))
 			defer span.Finish()
-			key := string(msg)
-			path := filepath.Join(dir, key)
-			output, err := client.GetObject(ctx, &s3.GetObjectInput{Bucket: &x.Bucket, Key: &key})
-			if err != nil {
-				return fmt.Errorf("failed to get object %q %q: %w", x.Bucket, key, err)
-			}
-			defer output.Body.Close()
-			if err := syscall.Mkfifo(path, 0o600); sharedutil.IgnoreExist(err) != nil {
-				return fmt.Errorf("failed to create fifo %q: %w", path, err)
-			}
-			defer os.Remove(path)
-			go func() {
-				defer runtime.HandleCrash()
-				logger.Info("opening file", "key", key)
-				file, err := os.OpenFile(path, os.O_WRONLY, os.ModeNamedPipe)
-				if err != nil {
-					logger.Error(err, "failed to open file", "path", path)
-				}
-				defer file.Close()
-				if _, err := io.Copy(file, output.Body); err != nil {
-					logger.Error(err, "failed to copy object to FIFO", "path", path)
-				}
-			}()
-			return process(
-				dfv1.ContextWithMeta(
-					ctx,
-					dfv1.Meta{
-						Source: sourceURN,
-						ID:     key,
-						Time:   output.LastModified.Unix(),
-					},
-				),
-				[]byte(sharedutil.MustJSON(message{Key: key, Path: path})),
-			)
-		},
-		ListItems: func() ([]interface{}, error) {
-			list, err := client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{Bucket: aws.String(x.Bucket)})
-			if err != nil {
-				return nil, err
-			}
-			keys := make([]interface{}, len(list.Contents))
-			for i, obj := range list.Contents {
-				keys[i] = *obj.Key
-			}
-			return keys, nil
+			return b.process(ctx, sourceURN, dir, string(msg), process)
 		},
+		ListItems: listItems,
 		RemoveItem: func(item interface{}) error {
-			key := item.(string)
-			_, err := client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: &x.Bucket, Key: &key})
-			return err
+			return b.markProcessed(ctx, item.(string))
 		},
 	})
 }
+
+// list returns the keys of the objects to process.
+func (b bucket) list(ctx context.Context) ([]interface{}, error) {
+	var keys []interface{}
+	pages := s3.NewListObjectsV2Paginator(b.client, &s3.ListObjectsV2Input{Bucket: aws.String(b.x.Bucket), Prefix: aws.String(b.x.Prefix)})
+	for pages.HasMorePages() {
+		page, err := pages.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			key := aws.ToString(obj.Key)
+			if !b.x.IsUnprocessed(key) {
+				continue
+			}
+			if tagged, err := b.isTagged(ctx, key); err != nil {
+				return nil, err
+			} else if !tagged {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys, nil
+}
+
+// receive returns the keys of the objects created, from the bucket's event notifications. The notifications are deleted
+// once their keys are returned, as objects that are not processed are listed when the source next starts.
+func (b bucket) receive(ctx context.Context, notifications *sharedsqs.Client) ([]interface{}, error) {
+	messages, err := notifications.Receive(ctx, 10, notificationsWaitTime, 0)
+	if err != nil {
+		return nil, err
+	}
+	var keys []interface{}
+	for _, m := range messages {
+		createdKeys, err := b.createdKeys(aws.ToString(m.Body))
+		if err != nil {
+			b.logger.Error(err, "failed to parse notification", "id", aws.ToString(m.MessageId))
+		}
+		for _, key := range createdKeys {
+			keys = append(keys, key)
+		}
+	}
+	if len(messages) > 0 {
+		if err := notifications.Delete(ctx, messages); err != nil {
+			b.logger.Error(err, "failed to delete notifications")
+		}
+	}
+	return keys, nil
+}
+
+type notification struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Bucket struct {
+				Name string `json:"name"`
+			} `json:"bucket"`
+			Object struct {
+				Key string `json:"key"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// createdKeys returns the keys of the objects to process that the notification is for. Other notifications, e.g.
+// "s3:TestEvent", have none.
+func (b bucket) createdKeys(body string) ([]string, error) {
+	n := &notification{}
+	if err := json.Unmarshal([]byte(body), n); err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, r := range n.Records {
+		if !strings.HasPrefix(r.EventName, "ObjectCreated:") || r.S3.Bucket.Name != b.x.Bucket {
+			continue
+		}
+		// keys are URL-encoded, e.g. spaces are "+"
+		key, err := url.QueryUnescape(r.S3.Object.Key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode key %q: %w", r.S3.Object.Key, err)
+		}
+		if b.x.IsUnprocessed(key) {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+// isTagged returns whether the object has the tag processed objects are tagged with, false if they are not tagged.
+func (b bucket) isTagged(ctx context.Context, key string) (bool, error) {
+	x := b.x.OnProcessed
+	if x == nil || x.Tag == nil {
+		return false, nil
+	}
+	output, err := b.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{Bucket: aws.String(b.x.Bucket), Key: aws.String(key)})
+	if err != nil {
+		return false, fmt.Errorf("failed to get tags of object %q: %w", key, err)
+	}
+	for _, t := range output.TagSet {
+		if aws.ToString(t.Key) == x.Tag.Key && aws.ToString(t.Value) == x.Tag.Value {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// markProcessed tags the object, moves it, or, by default, deletes it.
+func (b bucket) markProcessed(ctx context.Context, key string) error {
+	x := b.x.OnProcessed
+	if x != nil && x.Tag != nil {
+		output, err := b.client.GetObjectTagging(ctx, &s3.GetObjectTaggingInput{Bucket: aws.String(b.x.Bucket), Key: aws.String(key)})
+		if err != nil {
+			return fmt.Errorf("failed to get tags of object %q: %w", key, err)
+		}
+		// putting tags replaces all of them, so we keep the object's other tags
+		tags := []types.Tag{{Key: aws.String(x.Tag.Key), Value: aws.String(x.Tag.Value)}}
+		for _, t := range output.TagSet {
+			if aws.ToString(t.Key) != x.Tag.Key {
+				tags = append(tags, t)
+			}
+		}
+		b.logger.Info("tagging object", "key", key)
+		if _, err := b.client.PutObjectTagging(ctx, &s3.PutObjectTaggingInput{Bucket: aws.String(b.x.Bucket), Key: aws.String(key), Tagging: &types.Tagging{TagSet: tags}}); err != nil {
+			return fmt.Errorf("failed to tag object %q: %w", key, err)
+		}
+		return nil
+	}
+	if x != nil && x.MoveToPrefix != "" {
+		newKey := x.MoveToPrefix + strings.TrimPrefix(key, b.x.Prefix)
+		b.logger.Info("moving object", "key", key, "newKey", newKey)
+		copySource := (&url.URL{Path: b.x.Bucket + "/" + key}).EscapedPath()
+		if _, err := b.client.CopyObject(ctx, &s3.CopyObjectInput{Bucket: aws.String(b.x.Bucket), CopySource: aws.String(copySource), Key: aws.String(newKey)}); err != nil {
+			return fmt.Errorf("failed to copy object %q to %q: %w", key, newKey, err)
+		}
+	}
+	b.logger.Info("deleting object", "key", key)
+	_, err := b.client.DeleteObject(ctx, &s3.DeleteObjectInput{Bucket: aws.String(b.x.Bucket), Key: aws.String(key)})
+	return err
+}
+
+func (b bucket) process(ctx context.Context, sourceURN, dir, key string, process source.Process) error {
+	// the object may have been listed again while it was being processed
+	if tagged, err := b.isTagged(ctx, key); err != nil {
+		return err
+	} else if tagged {
+		return nil
+	}
+	output, err := b.client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(b.x.Bucket), Key: aws.String(key)})
+	if err != nil {
+		return fmt.Errorf("failed to get object %q %q: %w", b.x.Bucket, key, err)
+	}
+	defer output.Body.Close()
+	meta := dfv1.Meta{
+		Source: sourceURN,
+		ID:     key,
+		Time:   output.LastModified.Unix(),
+	}
+	switch b.x.GetEmit() {
+	case dfv1.S3EmitObject:
+		data, err := io.ReadAll(output.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read object %q: %w", key, err)
+		}
+		return process(dfv1.ContextWithMeta(ctx, meta), data)
+	case dfv1.S3EmitLines:
+		return processLines(ctx, meta, output.Body, process)
+	}
+	// the key may contain "/", e.g. when the source has a prefix, so the FIFO may be in a sub-directory
+	path := filepath.Join(dir, key)
+	if !strings.HasPrefix(path, filepath.Clean(dir)+string(filepath.Separator)) {
+		return fmt.Errorf("object key %q is not a valid file path", key)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create directory for fifo %q: %w", path, err)
+	}
+	if err := syscall.Mkfifo(path, 0o600); sharedutil.IgnoreExist(err) != nil {
+		return fmt.Errorf("failed to create fifo %q: %w", path, err)
+	}
+	defer os.Remove(path)
+	go func() {
+		defer runtime.HandleCrash()
+		b.logger.Info("opening file", "key", key)
+		file, err := os.OpenFile(path, os.O_WRONLY, os.ModeNamedPipe)
+		if err != nil {
+			b.logger.Error(err, "failed to open file", "path", path)
+		}
+		defer file.Close()
+		if _, err := io.Copy(file, output.Body); err != nil {
+			b.logger.Error(err, "failed to copy object to FIFO", "path", path)
+		}
+	}()
+	return process(dfv1.ContextWithMeta(ctx, meta), []byte(sharedutil.MustJSON(message{Key: key, Path: path})))
+}
+
+// processLines processes each line of the object, in order, with the line's number appended to the message's ID, e.g.
+// "my-key:1". Empty lines are dropped. If a line fails to be processed, the object is not marked as processed, so its
+// lines are processed again, from the first.
+func processLines(ctx context.Context, meta dfv1.Meta, r io.Reader, process source.Process) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+	for n := 1; scanner.Scan(); n++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		m := meta
+		m.ID = fmt.Sprintf("%s:%d", meta.ID, n)
+		// the scanner re-uses its buffer, so we copy the line
+		if err := process(dfv1.ContextWithMeta(ctx, m), append([]byte(nil), scanner.Bytes()...)); err != nil {
+			return fmt.Errorf("failed to process line %d: %w", n, err)
+		}
+	}
+	return scanner.Err()
+}
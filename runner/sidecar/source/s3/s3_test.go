@@ -0,0 +1,195 @@
+package s3
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI is a bucket of objects, and their tags, keyed by key.
+type fakeAPI struct {
+	objects map[string]string
+	tags    map[string][]types.Tag
+	copied  []string
+}
+
+func (f *fakeAPI) ListObjectsV2(_ context.Context, in *s3.ListObjectsV2Input, _ ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	out := &s3.ListObjectsV2Output{}
+	for key := range f.objects {
+		if strings.HasPrefix(key, aws.ToString(in.Prefix)) {
+			out.Contents = append(out.Contents, types.Object{Key: aws.String(key)})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeAPI) GetObject(_ context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{
+		Body:         io.NopCloser(strings.NewReader(f.objects[aws.ToString(in.Key)])),
+		LastModified: aws.Time(time.Unix(1, 0)),
+	}, nil
+}
+
+func (f *fakeAPI) DeleteObject(_ context.Context, in *s3.DeleteObjectInput, _ ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, aws.ToString(in.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func (f *fakeAPI) CopyObject(_ context.Context, in *s3.CopyObjectInput, _ ...func(*s3.Options)) (*s3.CopyObjectOutput, error) {
+	f.copied = append(f.copied, aws.ToString(in.CopySource))
+	f.objects[aws.ToString(in.Key)] = "copied"
+	return &s3.CopyObjectOutput{}, nil
+}
+
+func (f *fakeAPI) GetObjectTagging(_ context.Context, in *s3.GetObjectTaggingInput, _ ...func(*s3.Options)) (*s3.GetObjectTaggingOutput, error) {
+	return &s3.GetObjectTaggingOutput{TagSet: f.tags[aws.ToString(in.Key)]}, nil
+}
+
+func (f *fakeAPI) PutObjectTagging(_ context.Context, in *s3.PutObjectTaggingInput, _ ...func(*s3.Options)) (*s3.PutObjectTaggingOutput, error) {
+	f.tags[aws.ToString(in.Key)] = in.Tagging.TagSet
+	return &s3.PutObjectTaggingOutput{}, nil
+}
+
+func newBucket(x dfv1.S3Source, objects map[string]string) (bucket, *fakeAPI) {
+	x.Bucket = "my-bucket"
+	f := &fakeAPI{objects: objects, tags: map[string][]types.Tag{}}
+	return bucket{logger: sharedutil.NewLogger(), client: f, x: x}, f
+}
+
+var processedTag = &dfv1.S3Tag{Key: "processed", Value: "true"}
+
+func Test_bucket_list(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Prefix", func(t *testing.T) {
+		b, _ := newBucket(dfv1.S3Source{Prefix: "in/", OnProcessed: &dfv1.S3OnProcessed{MoveToPrefix: "in/done/"}}, map[string]string{"in/a": "", "in/done/b": "", "other/c": ""})
+		keys, err := b.list(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{"in/a"}, keys)
+	})
+	t.Run("Tag", func(t *testing.T) {
+		b, f := newBucket(dfv1.S3Source{OnProcessed: &dfv1.S3OnProcessed{Tag: processedTag}}, map[string]string{"a": ""})
+		keys, err := b.list(ctx)
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.NoError(t, b.markProcessed(ctx, "a"))
+		assert.Contains(t, f.objects, "a", "not deleted")
+		keys, err = b.list(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
+
+func Test_bucket_createdKeys(t *testing.T) {
+	b, _ := newBucket(dfv1.S3Source{Prefix: "in/"}, nil)
+	keys, err := b.createdKeys(`{"Records": [
+		{"eventName": "ObjectCreated:Put", "s3": {"bucket": {"name": "my-bucket"}, "object": {"key": "in/my+file%21.csv"}}},
+		{"eventName": "ObjectRemoved:Delete", "s3": {"bucket": {"name": "my-bucket"}, "object": {"key": "in/b"}}},
+		{"eventName": "ObjectCreated:Put", "s3": {"bucket": {"name": "other-bucket"}, "object": {"key": "in/c"}}},
+		{"eventName": "ObjectCreated:Copy", "s3": {"bucket": {"name": "my-bucket"}, "object": {"key": "out/d"}}}
+	]}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"in/my file!.csv"}, keys)
+	keys, err = b.createdKeys(`{"Service": "Amazon S3", "Event": "s3:TestEvent"}`)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+	_, err = b.createdKeys(`{`)
+	assert.Error(t, err)
+}
+
+func Test_bucket_markProcessed(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Delete", func(t *testing.T) {
+		b, f := newBucket(dfv1.S3Source{}, map[string]string{"a": ""})
+		assert.NoError(t, b.markProcessed(ctx, "a"))
+		assert.Empty(t, f.objects)
+	})
+	t.Run("Tag", func(t *testing.T) {
+		b, f := newBucket(dfv1.S3Source{OnProcessed: &dfv1.S3OnProcessed{Tag: processedTag}}, map[string]string{"a": ""})
+		f.tags["a"] = []types.Tag{{Key: aws.String("other"), Value: aws.String("x")}, {Key: aws.String("processed"), Value: aws.String("false")}}
+		assert.NoError(t, b.markProcessed(ctx, "a"))
+		assert.Equal(t, []types.Tag{{Key: aws.String("processed"), Value: aws.String("true")}, {Key: aws.String("other"), Value: aws.String("x")}}, f.tags["a"])
+	})
+	t.Run("Move", func(t *testing.T) {
+		b, f := newBucket(dfv1.S3Source{Prefix: "in/", OnProcessed: &dfv1.S3OnProcessed{MoveToPrefix: "done/"}}, map[string]string{"in/my file.csv": ""})
+		assert.NoError(t, b.markProcessed(ctx, "in/my file.csv"))
+		assert.Equal(t, []string{"my-bucket/in/my%20file.csv"}, f.copied)
+		assert.Equal(t, map[string]string{"done/my file.csv": "copied"}, f.objects)
+	})
+}
+
+func Test_bucket_process(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Object", func(t *testing.T) {
+		b, _ := newBucket(dfv1.S3Source{Emit: dfv1.S3EmitObject}, map[string]string{"a": "my-data"})
+		var msgs []string
+		assert.NoError(t, b.process(ctx, "my-urn", "", "a", func(ctx context.Context, msg []byte) error {
+			m, err := dfv1.MetaFromContext(ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, dfv1.Meta{Source: "my-urn", ID: "a", Time: 1}, m)
+			msgs = append(msgs, string(msg))
+			return nil
+		}))
+		assert.Equal(t, []string{"my-data"}, msgs)
+	})
+	t.Run("PathNestedKey", func(t *testing.T) {
+		dir := t.TempDir()
+		b, _ := newBucket(dfv1.S3Source{Prefix: "incoming/"}, map[string]string{"incoming/a.csv": "my-data"})
+		assert.NoError(t, b.process(ctx, "my-urn", dir, "incoming/a.csv", func(ctx context.Context, msg []byte) error {
+			m := message{}
+			assert.NoError(t, json.Unmarshal(msg, &m))
+			assert.Equal(t, message{Key: "incoming/a.csv", Path: filepath.Join(dir, "incoming", "a.csv")}, m)
+			data, err := os.ReadFile(m.Path)
+			assert.NoError(t, err)
+			assert.Equal(t, "my-data", string(data))
+			return nil
+		}))
+	})
+	t.Run("PathInvalidKey", func(t *testing.T) {
+		b, _ := newBucket(dfv1.S3Source{}, map[string]string{"../a": "my-data"})
+		assert.Error(t, b.process(ctx, "my-urn", t.TempDir(), "../a", func(ctx context.Context, msg []byte) error {
+			t.Fatal("processed an invalid key")
+			return nil
+		}))
+	})
+	t.Run("Tagged", func(t *testing.T) {
+		b, f := newBucket(dfv1.S3Source{Emit: dfv1.S3EmitObject, OnProcessed: &dfv1.S3OnProcessed{Tag: processedTag}}, map[string]string{"a": "my-data"})
+		f.tags["a"] = []types.Tag{{Key: aws.String("processed"), Value: aws.String("true")}}
+		assert.NoError(t, b.process(ctx, "my-urn", "", "a", func(ctx context.Context, msg []byte) error {
+			t.Fatal("processed a tagged object")
+			return nil
+		}))
+	})
+}
+
+func Test_processLines(t *testing.T) {
+	ctx := context.Background()
+	var ids, msgs []string
+	process := func(ctx context.Context, msg []byte) error {
+		m, err := dfv1.MetaFromContext(ctx)
+		assert.NoError(t, err)
+		ids = append(ids, m.ID)
+		msgs = append(msgs, string(msg))
+		if string(msg) == "error" {
+			return assert.AnError
+		}
+		return nil
+	}
+	assert.NoError(t, processLines(ctx, dfv1.Meta{ID: "a"}, strings.NewReader("foo\r\n\nbar"), process))
+	assert.Equal(t, []string{"a:1", "a:3"}, ids)
+	assert.Equal(t, []string{"foo", "bar"}, msgs)
+	err := processLines(ctx, dfv1.Meta{ID: "b"}, strings.NewReader("error\nbaz"), process)
+	assert.EqualError(t, err, "failed to process line 1: "+assert.AnError.Error())
+	assert.Equal(t, []string{"foo", "bar", "error"}, msgs)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"io"
+	"time"
 )
 
 type Interface interface {
@@ -12,6 +13,22 @@ type Interface interface {
 
 type Process func(ctx context.Context, msg []byte) error
 
+type headerKey struct{}
+
+// ContextWithHeader returns a context with a function that returns the value of one of the message's headers, for
+// sources whose messages have headers.
+func ContextWithHeader(ctx context.Context, header func(key string) string) context.Context {
+	return context.WithValue(ctx, headerKey{}, header)
+}
+
+// HeaderFromContext returns the value of one of the message's headers, or "" if it does not have the header.
+func HeaderFromContext(ctx context.Context, key string) string {
+	if header, ok := ctx.Value(headerKey{}).(func(string) string); ok {
+		return header(key)
+	}
+	return ""
+}
+
 var ErrPendingUnavailable = errors.New("pending not available")
 
 type HasPending interface {
@@ -20,3 +37,43 @@ type HasPending interface {
 	// It may return ErrPendingUnavailable if this is not available yet.
 	GetPending(ctx context.Context) (uint64, error)
 }
+
+type HasPartitionPending interface {
+	Interface
+	// GetPartitionPending returns the number of pending messages of each partition assigned to this replica, keyed by
+	// partition (e.g. "{topic}-{partition}" for Kafka).
+	// It may return ErrPendingUnavailable if this is not available yet.
+	GetPartitionPending(ctx context.Context) (map[string]uint64, error)
+}
+
+type HasPartitionLag interface {
+	Interface
+	// GetPartitionLag returns how far behind the latest message each partition assigned to this replica is, keyed by
+	// partition (e.g. shard ID for Kinesis), for sources that cannot count their pending messages.
+	// It may return ErrPendingUnavailable if this is not available yet.
+	GetPartitionLag(ctx context.Context) (map[string]time.Duration, error)
+}
+
+type Drainable interface {
+	Interface
+	// IsDrained returns true once every message that existed when the source started has been processed.
+	IsDrained(ctx context.Context) (bool, error)
+}
+
+// Checkpointable is a source whose position is only kept in memory, e.g. how many messages a generator has generated.
+// If the step's state is checkpointed, the position is checkpointed too, and the source is created with it when the
+// sidecar restarts.
+type Checkpointable interface {
+	Interface
+	// GetPosition returns the source's position, which includes every message it has processed.
+	GetPosition() string
+}
+
+// Connectable is a source that connects asynchronously, e.g. a Kafka consumer, which must join its consumer group and
+// be assigned partitions before it consumes. The sidecar is not ready until every such source is connected.
+type Connectable interface {
+	Interface
+	// IsConnected returns true once the source is connected and subscribed, and false while it is re-subscribing (e.g.
+	// while a consumer group re-balances).
+	IsConnected() bool
+}
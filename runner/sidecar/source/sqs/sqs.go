@@ -0,0 +1,101 @@
+package sqs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	awsshared "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/aws"
+	sharedsqs "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/sqs"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/opentracing/opentracing-go"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type sqsSource struct {
+	client *sharedsqs.Client
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a source that receives messages from the queue. Each batch of messages is deleted once they have all
+// been processed, so messages that fail to be processed are received again once their visibility timeout expires.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, sourceName, sourceURN string, x dfv1.SQSSource, process source.Process) (source.HasPending, error) {
+	credentials, err := awsshared.GetCredentials(ctx, secretInterface, x.Credentials)
+	if err != nil {
+		return nil, err
+	}
+	c := sharedsqs.NewClient(x.QueueURL, x.Region, credentials, x.Endpoint, x.GetWaitTime()+10*time.Second)
+	// check we can access the queue, so misconfiguration is reported at startup
+	if _, err := c.GetApproximateNumberOfMessages(ctx); err != nil {
+		return nil, fmt.Errorf("failed to access queue %q: %w", x.QueueURL, err)
+	}
+	return start(ctx, c, sourceName, sourceURN, x, process), nil
+}
+
+func start(ctx context.Context, c *sharedsqs.Client, sourceName, sourceURN string, x dfv1.SQSSource, process source.Process) *sqsSource {
+	receiveCtx, cancel := context.WithCancel(ctx)
+	s := &sqsSource{client: c, cancel: cancel, done: make(chan struct{})}
+	var visibilityTimeout time.Duration
+	if x.VisibilityTimeout != nil {
+		visibilityTimeout = x.VisibilityTimeout.Duration
+	}
+	go func() {
+		defer runtime.HandleCrash()
+		defer close(s.done)
+		for receiveCtx.Err() == nil {
+			messages, err := c.Receive(receiveCtx, x.GetMaxMessages(), x.GetWaitTime(), visibilityTimeout)
+			if err != nil {
+				if receiveCtx.Err() == nil {
+					logger.Error(err, "failed to receive messages", "source", sourceName)
+					select {
+					case <-receiveCtx.Done():
+					case <-time.After(time.Second):
+					}
+				}
+				continue
+			}
+			var processed []sharedsqs.Message
+			for _, m := range messages {
+				span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("sqs-source-%s", sourceName))
+				if err := process(
+					source.ContextWithHeader(
+						dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: aws.ToString(m.MessageId), Time: m.SentAt().Unix()}),
+						m.Header,
+					),
+					[]byte(aws.ToString(m.Body)),
+				); err != nil {
+					logger.Error(err, "failed to process message", "source", sourceName, "id", aws.ToString(m.MessageId))
+				} else {
+					processed = append(processed, m)
+				}
+				span.Finish()
+			}
+			if len(processed) > 0 {
+				// messages that are not deleted are received again, so they may be processed more than once
+				if err := c.Delete(ctx, processed); err != nil {
+					logger.Error(err, "failed to delete messages", "source", sourceName)
+				}
+			}
+		}
+	}()
+	return s
+}
+
+// Close stops receiving messages, once the batch being processed is done.
+func (s *sqsSource) Close() error {
+	s.cancel()
+	<-s.done
+	return nil
+}
+
+// GetPending returns the approximate number of messages available to receive.
+func (s *sqsSource) GetPending(ctx context.Context) (uint64, error) {
+	return s.client.GetApproximateNumberOfMessages(ctx)
+}
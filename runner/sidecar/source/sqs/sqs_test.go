@@ -0,0 +1,98 @@
+package sqs
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedsqs "github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/sqs"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_sqsSource(t *testing.T) {
+	var mu sync.Mutex
+	received := false
+	var deleted []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=my-access-key-id/"))
+		assert.Equal(t, "my-queue-url", r.Form.Get("QueueUrl"))
+		mu.Lock()
+		defer mu.Unlock()
+		switch r.Form.Get("Action") {
+		case "ReceiveMessage":
+			assert.Equal(t, "2", r.Form.Get("MaxNumberOfMessages"))
+			assert.Equal(t, "1", r.Form.Get("WaitTimeSeconds"))
+			assert.Equal(t, "60", r.Form.Get("VisibilityTimeout"))
+			if received {
+				_, _ = w.Write([]byte(`<ReceiveMessageResponse><ReceiveMessageResult/></ReceiveMessageResponse>`))
+				return
+			}
+			received = true
+			_, _ = w.Write([]byte(`<ReceiveMessageResponse>
+  <ReceiveMessageResult>
+    <Message>
+      <MessageId>1</MessageId>
+      <ReceiptHandle>handle-1</ReceiptHandle>
+      <Body>foo</Body>
+      <Attribute><Name>SentTimestamp</Name><Value>1630461784000</Value></Attribute>
+      <MessageAttribute><Name>my-header</Name><Value><StringValue>my-value</StringValue><DataType>String</DataType></Value></MessageAttribute>
+    </Message>
+    <Message>
+      <MessageId>2</MessageId>
+      <ReceiptHandle>handle-2</ReceiptHandle>
+      <Body>error</Body>
+    </Message>
+  </ReceiveMessageResult>
+</ReceiveMessageResponse>`))
+		case "DeleteMessageBatch":
+			deleted = append(deleted, r.Form.Get("DeleteMessageBatchRequestEntry.1.ReceiptHandle"), r.Form.Get("DeleteMessageBatchRequestEntry.2.ReceiptHandle"))
+			_, _ = w.Write([]byte(`<DeleteMessageBatchResponse><DeleteMessageBatchResult/></DeleteMessageBatchResponse>`))
+		case "GetQueueAttributes":
+			_, _ = w.Write([]byte(`<GetQueueAttributesResponse>
+  <GetQueueAttributesResult>
+    <Attribute><Name>ApproximateNumberOfMessages</Name><Value>3</Value></Attribute>
+  </GetQueueAttributesResult>
+</GetQueueAttributesResponse>`))
+		}
+	}))
+	defer server.Close()
+	credentials := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "my-access-key-id", SecretAccessKey: "my-secret-access-key"}, nil
+	})
+	c := sharedsqs.NewClient("my-queue-url", "us-west-2", credentials, &dfv1.AWSEndpoint{URL: server.URL}, 10*time.Second)
+	ctx := context.Background()
+	processed := make(chan string, 2)
+	s := start(ctx, c, "my-source", "my-urn", dfv1.SQSSource{
+		MaxMessages:       2,
+		WaitTime:          &metav1.Duration{Duration: time.Second},
+		VisibilityTimeout: &metav1.Duration{Duration: time.Minute},
+	}, func(ctx context.Context, msg []byte) error {
+		processed <- string(msg)
+		if string(msg) == "error" {
+			return assert.AnError
+		}
+		meta, err := dfv1.MetaFromContext(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, dfv1.Meta{Source: "my-urn", ID: "1", Time: 1630461784}, meta)
+		assert.Equal(t, "my-value", source.HeaderFromContext(ctx, "my-header"))
+		return nil
+	})
+	assert.Equal(t, "foo", <-processed)
+	assert.Equal(t, "error", <-processed)
+	pending, err := s.GetPending(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), pending)
+	assert.NoError(t, s.Close())
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"handle-1", ""}, deleted, "only the processed message is deleted")
+}
@@ -5,8 +5,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/rand"
 	"net/http"
+	"net/url"
+	"sync"
 	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
@@ -18,12 +21,14 @@ import (
 	"github.com/nats-io/stan.go/pb"
 	"github.com/opentracing/opentracing-go"
 	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
 	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 )
 
 var logger = sharedutil.NewLogger()
 
 type stanSource struct {
+	mu                sync.Mutex
 	sub               stan.Subscription
 	conn              *sharedstan.Conn
 	subject           string
@@ -31,7 +36,7 @@ type stanSource struct {
 	queueName         string
 }
 
-func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, namespace, pipelineName, stepName, sourceURN string, replica int, sourceName string, x dfv1.STAN, process source.Process) (source.Interface, error) {
+func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, namespace, pipelineName, stepName, sourceURN string, replica int, sourceName string, x dfv1.STAN, process, dlq source.Process) (source.Interface, error) {
 	genClientID := func() string {
 		// In a particular situation, the stan connection status is inconsistent between stan server and client,
 		// the connection is lost from client side, but the server still thinks it's alive. In this case, use
@@ -41,90 +46,144 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, cluster, n
 		return fmt.Sprintf("%s-%s-%s-%d-source-%s-%v", namespace, pipelineName, stepName, replica, sourceName, r1.Intn(100))
 	}
 
-	var conn *sharedstan.Conn
-	var err error
-	clientID := genClientID()
-	conn, err = sharedstan.ConnectSTAN(ctx, secretInterface, x, clientID)
-	if err != nil {
-		return nil, err
-	}
-
 	// https://docs.nats.io/developing-with-nats-streaming/queues
-	var sub stan.Subscription
 	queueName := sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName)
-	subFunc := func() (stan.Subscription, error) {
-		logger.Info("subscribing to STAN queue", "source", sourceName, "queueName", queueName)
-		sub, err := conn.QueueSubscribe(x.Subject, queueName, func(msg *stan.Msg) {
-			span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("stan-source-%s", sourceName))
-			defer span.Finish()
-			if err := process(
-				dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: fmt.Sprint(msg.Sequence), Time: msg.Timestamp}),
-				msg.Data,
-			); err != nil {
-				logger.Error(err, "failed to process message")
-			} else if err := msg.Ack(); err != nil {
-				if errors.Is(err, stan.ErrBadSubscription) {
-					logger.Info("failed to ack a message, stan subscription might have been closed", "source", sourceName, "error", err)
-				} else {
-					logger.Error(err, "failed to ack a message", "source", sourceName)
-				}
+	handler := func(msg *stan.Msg) {
+		span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("stan-source-%s", sourceName))
+		defer span.Finish()
+		ctx = dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: fmt.Sprint(msg.Sequence), Time: msg.Timestamp})
+		if n := x.MaxRedeliveries; n > 0 && msg.RedeliveryCount > n {
+			logger.Info("message re-delivered too many times, sending to DLQ", "source", sourceName, "sequence", msg.Sequence, "redeliveryCount", msg.RedeliveryCount)
+			if err := dlq(ctx, msg.Data); err != nil {
+				logger.Error(err, "failed to send message to DLQ", "source", sourceName)
+				return
+			}
+		} else if err := process(ctx, msg.Data); err != nil {
+			logger.Error(err, "failed to process message", "redeliveryCount", msg.RedeliveryCount)
+			return
+		}
+		if err := msg.Ack(); err != nil {
+			if errors.Is(err, stan.ErrBadSubscription) {
+				logger.Info("failed to ack a message, stan subscription might have been closed", "source", sourceName, "error", err)
+			} else {
+				logger.Error(err, "failed to ack a message", "source", sourceName)
 			}
-		}, stan.DurableName(queueName),
+		}
+	}
+
+	s := &stanSource{
+		subject:           x.Subject,
+		natsMonitoringURL: x.NATSMonitoringURL,
+		queueName:         queueName,
+	}
+	connect := func() error {
+		clientID := genClientID()
+		conn, err := sharedstan.ConnectSTAN(ctx, secretInterface, x, clientID)
+		if err != nil {
+			return err
+		}
+		logger.Info("subscribing to STAN queue", "source", sourceName, "queueName", queueName, "clientID", clientID)
+		sub, err := conn.QueueSubscribe(x.Subject, queueName, handler, stan.DurableName(queueName),
 			stan.SetManualAckMode(),
 			stan.StartAt(pb.StartPosition_NewOnly),
-			stan.AckWait(30*time.Second),
+			stan.AckWait(x.GetAckWait()),
 			stan.MaxInflight(x.GetMaxInflight()))
 		if err != nil {
-			return nil, fmt.Errorf("failed to subscribe: %w", err)
+			// close the connection, so we connect with a new client ID next time
+			_ = conn.Close()
+			return fmt.Errorf("failed to subscribe: %w", err)
 		}
-		return sub, nil
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.conn, s.sub = conn, sub
+		return nil
 	}
 
-	sub, err = subFunc()
-	if err != nil {
+	if err := connect(); err != nil {
 		return nil, err
 	}
+
+	r := x.GetReconnect()
+	newBackoff := func() wait.Backoff {
+		return wait.Backoff{Duration: r.GetBackoff(), Factor: 2, Jitter: 0.2, Steps: math.MaxInt32, Cap: r.GetMaxBackoff()}
+	}
 	go func() {
 		defer runtimeutil.HandleCrash()
 		logger.Info("starting stan auto reconnection daemon", "source", sourceName)
-		ticker := time.NewTicker(5 * time.Second)
-		defer ticker.Stop()
+		interval := time.Duration(r.GetPingInterval()) * time.Second
+		backoff := newBackoff()
+		delay := interval
 		for {
 			select {
 			case <-ctx.Done():
 				logger.Info("exiting stan auto reconnection daemon", "source", sourceName)
 				return
-			case <-ticker.C:
-				if conn == nil || conn.IsClosed() {
-					_ = sub.Close()
-					logger.Info("stan connection lost, reconnecting...", "source", sourceName)
-					clientID := genClientID()
-					conn, err = sharedstan.ConnectSTAN(ctx, secretInterface, x, clientID)
-					if err != nil {
-						logger.Info("failed to reconnect, will try again soon", "source", sourceName, "clientID", clientID, "error", err)
-						continue
-					}
-					logger.Info("reconnected to stan server.", "source", sourceName, "clientID", clientID)
-					if sub, err = subFunc(); err != nil {
-						logger.Error(err, "failed to subscribe after reconnection", "source", sourceName, "clientID", clientID)
-						// Close the connection to let it retry
-						_ = conn.Close()
-					}
-				}
+			case <-time.After(delay):
+			}
+			delay = interval
+			if !s.isClosed() {
+				backoff = newBackoff()
+				continue
 			}
+			logger.Info("stan connection lost, reconnecting...", "source", sourceName)
+			s.closeSubscription()
+			if err := connect(); err != nil {
+				delay = backoff.Step()
+				logger.Info("failed to reconnect, will try again", "source", sourceName, "delay", delay.String(), "error", err)
+				continue
+			}
+			logger.Info("reconnected to stan server", "source", sourceName)
 		}
 	}()
 
-	return stanSource{
-		conn:              conn,
-		sub:               sub,
-		subject:           x.Subject,
-		natsMonitoringURL: x.NATSMonitoringURL,
-		queueName:         queueName,
-	}, nil
+	return s, nil
 }
 
-func (s stanSource) Close() error {
+func (s *stanSource) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conn.IsClosed()
+}
+
+func (s *stanSource) closeSubscription() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_ = s.sub.Close()
+}
+
+// ResetPosition deletes the durable queue, then re-creates it starting at the first available message, or the first
+// message at or after the time. The durable queue must not have any subscribers.
+func ResetPosition(ctx context.Context, secretInterface corev1.SecretInterface, clientID, queueName string, x dfv1.STAN, offset dfv1.ResetOffset) error {
+	t, err := offset.GetTime()
+	if err != nil {
+		return err
+	}
+	conn, err := sharedstan.ConnectSTAN(ctx, secretInterface, x, clientID)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	noop := func(*stan.Msg) {} // messages are not acked, so they are re-delivered
+	sub, err := conn.QueueSubscribe(x.Subject, queueName, noop, stan.DurableName(queueName), stan.SetManualAckMode())
+	if err != nil {
+		return fmt.Errorf("failed to subscribe: %w", err)
+	}
+	if err := sub.Unsubscribe(); err != nil {
+		return fmt.Errorf("failed to delete durable queue: %w", err)
+	}
+	start := stan.DeliverAllAvailable()
+	if !t.IsZero() {
+		start = stan.StartAtTime(t)
+	}
+	if sub, err = conn.QueueSubscribe(x.Subject, queueName, noop, stan.DurableName(queueName), stan.SetManualAckMode(), start, stan.MaxInflight(1)); err != nil {
+		return fmt.Errorf("failed to re-create durable queue: %w", err)
+	}
+	return sub.Close()
+}
+
+func (s *stanSource) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	logger.Info("closing stan subscription")
 	if err := s.sub.Close(); err != nil {
 		return err
@@ -137,41 +196,50 @@ var httpClient = http.Client{
 	Timeout: time.Second * 3,
 }
 
-func (s stanSource) GetPending(ctx context.Context) (uint64, error) {
-	pendingMessages := func(ctx context.Context, channel, queueNameCombo string) (int64, error) {
-		monitoringEndpoint := fmt.Sprintf("%s/streaming/channelsz?channel=%s&subs=1", s.natsMonitoringURL, channel)
-		req, err := http.NewRequestWithContext(ctx, "GET", monitoringEndpoint, nil)
-		if err != nil {
-			return 0, err
-		}
-		resp, err := httpClient.Do(req)
-		if err != nil {
-			return 0, err
-		}
-		if resp.StatusCode != 200 {
-			return 0, fmt.Errorf("invalid response: %s", resp.Status)
-		}
-		defer func() { _ = resp.Body.Close() }()
-		o := server.Channelz{}
-		if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
-			return 0, err
-		}
-		maxLastSent := uint64(0)
-		for _, s := range o.Subscriptions {
-			if s.QueueName == queueNameCombo && s.LastSent > maxLastSent {
+// GetPending returns the number of messages in the channel that have not been sent to the durable queue, i.e. the
+// channel's last sequence minus the durable queue's last sent sequence, using the NATS Streaming monitoring endpoint.
+func (s *stanSource) GetPending(ctx context.Context) (uint64, error) {
+	if s.natsMonitoringURL == "" {
+		return 0, fmt.Errorf("natsMonitoringUrl not configured: %w", source.ErrPendingUnavailable)
+	}
+	monitoringEndpoint := fmt.Sprintf("%s/streaming/channelsz?channel=%s&subs=1", s.natsMonitoringURL, url.QueryEscape(s.subject))
+	req, err := http.NewRequestWithContext(ctx, "GET", monitoringEndpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get STAN pending: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != 200 {
+		return 0, fmt.Errorf("failed to get STAN pending: invalid response: %s", resp.Status)
+	}
+	o := server.Channelz{}
+	if err := json.NewDecoder(resp.Body).Decode(&o); err != nil {
+		return 0, fmt.Errorf("failed to get STAN pending: %w", err)
+	}
+	// queueNameCombo := {durableName}:{queueGroup}
+	return pendingOf(o, s.queueName+":"+s.queueName)
+}
+
+func pendingOf(o server.Channelz, queueNameCombo string) (uint64, error) {
+	found := false
+	maxLastSent := uint64(0)
+	for _, s := range o.Subscriptions {
+		if s.QueueName == queueNameCombo {
+			found = true
+			if s.LastSent > maxLastSent {
 				maxLastSent = s.LastSent
 			}
 		}
-		return int64(o.LastSeq - maxLastSent), nil
 	}
-
-	// queueNameCombo := {durableName}:{queueGroup}
-	queueNameCombo := s.queueName + ":" + s.queueName
-	if pending, err := pendingMessages(ctx, s.subject, queueNameCombo); err != nil {
-		return 0, fmt.Errorf("failed to get STAN pending for: %w", err)
-	} else if pending >= 0 {
-		logger.Info("setting STAN pending", "pending", pending)
-		return uint64(pending), nil
-	}
-	return 0, nil
+	if !found {
+		// the channel's last sequence would be the number of messages ever published, not the number pending
+		return 0, fmt.Errorf("durable queue %q not found: %w", queueNameCombo, source.ErrPendingUnavailable)
+	}
+	if o.LastSeq < maxLastSent {
+		return 0, nil
+	}
+	return o.LastSeq - maxLastSent, nil
 }
@@ -0,0 +1,57 @@
+package stan
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/nats-io/nats-streaming-server/server"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_pendingOf(t *testing.T) {
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := pendingOf(server.Channelz{LastSeq: 10}, "q:q")
+		assert.True(t, errors.Is(err, source.ErrPendingUnavailable))
+	})
+	t.Run("Found", func(t *testing.T) {
+		pending, err := pendingOf(server.Channelz{
+			LastSeq: 10,
+			Subscriptions: []*server.Subscriptionz{
+				{QueueName: "other:other", LastSent: 1},
+				{QueueName: "q:q", LastSent: 4},
+				{QueueName: "q:q", LastSent: 7},
+			},
+		}, "q:q")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), pending)
+	})
+	t.Run("AheadOfChannel", func(t *testing.T) {
+		pending, err := pendingOf(server.Channelz{LastSeq: 1, Subscriptions: []*server.Subscriptionz{{QueueName: "q:q", LastSent: 2}}}, "q:q")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(0), pending)
+	})
+}
+
+func TestGetPending(t *testing.T) {
+	ctx := context.Background()
+	t.Run("NotConfigured", func(t *testing.T) {
+		_, err := (&stanSource{}).GetPending(ctx)
+		assert.True(t, errors.Is(err, source.ErrPendingUnavailable))
+	})
+	t.Run("Configured", func(t *testing.T) {
+		s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/streaming/channelsz", r.URL.Path)
+			assert.Equal(t, "my.subject", r.URL.Query().Get("channel"))
+			_ = json.NewEncoder(w).Encode(server.Channelz{LastSeq: 5, Subscriptions: []*server.Subscriptionz{{QueueName: "q:q", LastSent: 2}}})
+		}))
+		defer s.Close()
+		pending, err := (&stanSource{natsMonitoringURL: s.URL, subject: "my.subject", queueName: "q"}).GetPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(3), pending)
+	})
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/google/uuid"
+	"github.com/opentracing/opentracing-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+var logger = sharedutil.NewLogger()
+
+type testSource struct {
+	queue chan []byte
+	ready bool
+	stop  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New creates a source that reads from an in-memory queue. Messages are added to the queue by POSTing them to
+// `/test/sources/{sourceName}`, presenting the step's bearer token.
+func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceURN, sourceName string, x dfv1.TestSource, process source.Process) (source.Interface, error) {
+	secret, err := secretInterface.Get(ctx, pipelineName+"-"+stepName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %q: %w", stepName, err)
+	}
+	authorization := secret.Data[fmt.Sprintf("sources.%s.http.authorization", sourceName)]
+	s := &testSource{queue: make(chan []byte, x.GetCapacity()), ready: true, stop: make(chan struct{})}
+	http.HandleFunc("/test/sources/"+sourceName, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(405)
+			return
+		}
+		if len(authorization) == 0 || subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), authorization) != 1 {
+			w.WriteHeader(403)
+			return
+		}
+		if !s.ready { // if we are not ready, we cannot serve requests
+			w.WriteHeader(503)
+			_, _ = w.Write([]byte("not ready"))
+			return
+		}
+		msg, err := ioutil.ReadAll(r.Body)
+		_ = r.Body.Close()
+		if err != nil {
+			w.WriteHeader(400)
+			_, _ = w.Write([]byte(err.Error()))
+			return
+		}
+		select {
+		case s.queue <- msg:
+			w.WriteHeader(204)
+		default:
+			w.WriteHeader(503)
+			_, _ = w.Write([]byte("queue full"))
+		}
+	})
+	s.wg.Add(1)
+	go func() {
+		defer runtime.HandleCrash()
+		defer s.wg.Done()
+		for {
+			select {
+			case <-s.stop:
+				return
+			case msg := <-s.queue:
+				span, ctx := opentracing.StartSpanFromContext(ctx, fmt.Sprintf("test-source-%s", sourceName))
+				if err := process(
+					dfv1.ContextWithMeta(ctx, dfv1.Meta{Source: sourceURN, ID: uuid.New().String(), Time: time.Now().Unix()}),
+					msg,
+				); err != nil {
+					logger.Error(err, "failed to process message", "source", sourceName)
+				}
+				span.Finish()
+			}
+		}
+	}()
+	return s, nil
+}
+
+// Close stops reading from the queue, once the message being processed is done. Messages still in the queue are lost.
+func (s *testSource) Close() error {
+	s.ready = false
+	close(s.stop)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *testSource) GetPending(context.Context) (uint64, error) {
+	return uint64(len(s.queue)), nil
+}
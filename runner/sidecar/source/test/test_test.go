@@ -0,0 +1,54 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTestSource(t *testing.T) {
+	secretInterface := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pl-my-step"},
+		Data:       map[string][]byte{"sources.my-source.http.authorization": []byte("Bearer my-token")},
+	}).CoreV1().Secrets("")
+	processed := make(chan string, 1)
+	block := make(chan struct{})
+	s, err := New(context.Background(), secretInterface, "my-pl", "my-step", "my-urn", "my-source", dfv1.TestSource{Capacity: 1}, func(ctx context.Context, msg []byte) error {
+		<-block
+		processed <- string(msg)
+		return nil
+	})
+	assert.NoError(t, err)
+	post := func(authorization, msg string) int {
+		r := httptest.NewRequest("POST", "/test/sources/my-source", strings.NewReader(msg))
+		r.Header.Set("Authorization", authorization)
+		w := httptest.NewRecorder()
+		http.DefaultServeMux.ServeHTTP(w, r)
+		return w.Code
+	}
+	pending := func() uint64 {
+		n, _ := s.(*testSource).GetPending(context.Background())
+		return n
+	}
+	assert.Equal(t, 403, post("Bearer other", "my-msg"))
+	assert.Equal(t, 204, post("Bearer my-token", "my-msg-0"))
+	// the first message is taken from the queue and blocks, so the second fills it
+	assert.Eventually(t, func() bool { return pending() == 0 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, 204, post("Bearer my-token", "my-msg-1"))
+	assert.Equal(t, uint64(1), pending())
+	assert.Equal(t, 503, post("Bearer my-token", "my-msg-2"))
+	close(block)
+	assert.Equal(t, "my-msg-0", <-processed)
+	assert.Equal(t, "my-msg-1", <-processed)
+	assert.NoError(t, s.Close())
+	assert.Equal(t, 503, post("Bearer my-token", "my-msg-3"))
+}
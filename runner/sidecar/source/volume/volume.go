@@ -21,7 +21,7 @@ type message struct {
 	Path string `json:"path"`
 }
 
-func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceName, sourceURN string, x dfv1.VolumeSource, process source.Process, leadReplica bool) (source.HasPending, error) {
+func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineName, stepName, sourceName, sourceURN string, x dfv1.VolumeSource, bounded bool, process source.Process, leadReplica bool) (source.HasPending, error) {
 	logger := sharedutil.NewLogger().WithValues("source", sourceName)
 	dir := filepath.Join(dfv1.PathVarRun, "sources", sourceName)
 	return loadbalanced.New(ctx, secretInterface, loadbalanced.NewReq{
@@ -31,6 +31,7 @@ func New(ctx context.Context, secretInterface corev1.SecretInterface, pipelineNa
 		SourceName:   sourceName,
 		SourceURN:    sourceURN,
 		LeadReplica:  leadReplica,
+		Bounded:      bounded,
 		Concurrency:  int(x.Concurrency),
 		PollPeriod:   x.PollPeriod.Duration,
 		Process: func(ctx context.Context, msg []byte) error {
@@ -4,28 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/shared/codec"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	amqpsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/amqp"
+	argoeventssource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/argoevents"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/cron"
+	daprsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/dapr"
 	dbsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/db"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/elasticsearch"
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/generator"
 	httpsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/http"
 	jssource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/jetstream"
 	kafkasource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/kafka"
+	kinesissource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/kinesis"
+	mqttsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/mqtt"
+	redissource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/redis"
+	redisstreamsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/redisstream"
 	s3source "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/s3"
+	sqssource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/sqs"
 	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/stan"
+	testsource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/test"
 	volumeSource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/volume"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/opentracing/opentracing-go"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 )
 
-func connectSources(ctx context.Context, process func(context.Context, []byte) error, dlq func(context.Context, []byte) error) error {
+func connectSources(ctx context.Context, process func(context.Context, []byte) error, dlq func(context.Context, []byte) error, audit func(context.Context, []byte) error, late func(context.Context, []byte) error, complete func(), fail func(error)) error {
 	var pendingGauge *prometheus.GaugeVec
 	if leadReplica() {
 		pendingGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
@@ -35,6 +46,18 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 		}, []string{"sourceName"})
 	}
 
+	// every replica reports the partitions assigned to it
+	partitionPendingGauge := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "sources",
+		Name:      "partition_pending",
+		Help:      "Pending messages by partition, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_partition_pending",
+	}, []string{"sourceName", "partition"})
+	partitionLagGauge := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "sources",
+		Name:      "partition_lag_seconds",
+		Help:      "How far behind the latest message each partition is, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_partition_lag_seconds",
+	}, []string{"sourceName", "partition"})
+
 	totalCounter := promauto.NewCounterVec(prometheus.CounterOpts{
 		Subsystem: "sources",
 		Name:      "total",
@@ -58,6 +81,24 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 		Name:      "totalBytes",
 		Help:      "Total number of bytes processed, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_retries",
 	}, []string{"sourceName", "replica"})
+	watermarkGauge := promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "sources",
+		Name:      "watermark",
+		Help:      "Latest event time processed, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_watermark",
+	}, []string{"sourceName", "replica"})
+
+	eventTimeErrorsCounter := promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sources",
+		Name:      "event_time_errors",
+		Help:      "Number of messages without a valid event time, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_event_time_errors",
+	}, []string{"sourceName", "replica"})
+
+	lateDroppedCounter := promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sources",
+		Name:      "late_dropped",
+		Help:      "Number of late messages dropped, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_late_dropped",
+	}, []string{"sourceName", "replica"})
+
 	processLatencyHistoGram := promauto.NewHistogramVec(prometheus.HistogramOpts{
 		Subsystem: "sources",
 		Name:      "process_latency_seconds",
@@ -65,7 +106,45 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 		Buckets:   []float64{0.0, 1.0, 3.0, 5.0, 10.0, 15.0, 30.0, 45.0, 60.0, 75.0, 90.0, 105.0, 120.0},
 	}, []string{"sourceName", "replica"})
 
-	if err := createSecret(ctx); err != nil {
+	handlerErrorsCounter := promauto.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: "sources",
+		Name:      "handler_errors",
+		Help:      "Number of errors returned by the main container, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_handler_errors",
+	}, []string{"sourceName", "replica", "class"})
+
+	lastErrs := newLastErrors(promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: "sources",
+		Name:      "last_error",
+		Help:      "Time of the most recent error returned by the main container, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#sources_last_error",
+	}, []string{"sourceName", "replica", "class", "message"}))
+
+	var merge *merger
+	if x := step.Spec.Merge; x != nil {
+		logger.Info("merging sources", "policy", x.GetPolicy(), "concurrency", x.GetConcurrency())
+		merge = newMerger(*x, step.Spec.Sources)
+	}
+
+	var join *joiner
+	if x := step.Spec.Join; x != nil {
+		logger.Info("joining sources", "left", x.Left, "right", x.Right, "window", x.GetWindow(), "unmatched", x.GetUnmatched())
+		store, err := state.NewFromEnv(ctx, "join")
+		if err != nil {
+			return fmt.Errorf("failed to create state store: %w", err)
+		}
+		addStopHook(func(context.Context) error {
+			logger.Info("closing state store")
+			return store.Close()
+		})
+		if y, err := newJoiner(*x, store, process, dlq); err != nil {
+			return err
+		} else {
+			join = y
+		}
+		join.run(ctx)
+	}
+
+	checkpoints, err := newCheckpointer(ctx)
+	if err != nil {
 		return err
 	}
 
@@ -78,9 +157,40 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 			return fmt.Errorf("duplicate source named %q", sourceName)
 		}
 
-		processWithRetry := func(ctx context.Context, msg []byte) error {
+		w := &watermark{}
+
+		var decoder codec.Interface
+		if x := s.Codec; x != nil {
+			logger.Info("adding codec", "source", sourceName)
+			if y, err := codec.New(*x); err != nil {
+				return fmt.Errorf("failed to create codec for source %q: %w", sourceName, err)
+			} else {
+				decoder = y
+			}
+		}
+
+		processWithRetry := func(ctx context.Context, msg []byte) (err error) {
 			span, ctx := opentracing.StartSpanFromContext(ctx, "processWithRetry")
 			defer span.Finish()
+			// a paused source's messages wait here, so the source stops fetching more
+			if err := live.waitUntilResumed(ctx, sourceName); err != nil {
+				return fmt.Errorf("could not send message: %w", err)
+			}
+			if err := waitForQuota(ctx, len(msg)); err != nil {
+				return fmt.Errorf("could not send message: %w", err)
+			}
+			if step.Spec.Audit != nil {
+				size, startedAt := len(msg), time.Now()
+				defer func() { auditMessage(ctx, audit, sourceName, size, startedAt, err) }()
+			}
+			atomic.AddInt64(&inFlight, 1)
+			defer func() {
+				atomic.AddInt64(&inFlight, -1)
+				if n, m := atomic.AddInt64(&processed, 1), step.Spec.Completion.GetMessages(); m > 0 && uint64(n) == m {
+					logger.Info("processed messages, completing", "messages", n)
+					complete()
+				}
+			}()
 			totalCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Inc()
 			totalBytesCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Add(float64(len(msg)))
 
@@ -90,15 +200,47 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 			}
 
 			sourceMsgTime := time.Unix(meta.Time, 0).UTC()
-			processLatencyHistoGram.WithLabelValues(sourceName, fmt.Sprint(replica)).Observe(time.Now().UTC().Sub(sourceMsgTime).Seconds())
-			backoff := newBackoff(s.Retry)
+			observe(ctx, processLatencyHistoGram.WithLabelValues(sourceName, fmt.Sprint(replica)), time.Now().UTC().Sub(sourceMsgTime).Seconds())
+			if decoder != nil {
+				data, err := decoder.Decode(msg)
+				if err != nil {
+					// decoding is not retried, it will always fail
+					logger.Error(err, "failed to decode message", "source", sourceName)
+					errorsCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Inc()
+					if dlqErr := dlq(ctx, msg); dlqErr != nil {
+						logger.Error(dlqErr, "failed to send undecodable message to DLQ")
+					}
+					return fmt.Errorf("failed to decode message: %w", err)
+				}
+				msg = data
+			}
+			if x := s.EventTime; x != nil {
+				if t, err := x.Extract(msg, func(key string) string { return source.HeaderFromContext(ctx, key) }); err != nil {
+					// the message is still processed, it just does not move the watermark
+					eventTimeErrorsCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Inc()
+				} else if x := step.Spec.GetLateness(); x.IsLate(t, w.get()) {
+					switch x.GetPolicy() {
+					case dfv1.LateEmit:
+						// processed like any other message
+					case dfv1.LateSink:
+						return late(ctx, msg)
+					default:
+						lateDroppedCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Inc()
+						return nil
+					}
+				} else if w.advance(t) {
+					watermarkGauge.WithLabelValues(sourceName, fmt.Sprint(replica)).Set(float64(w.get().UnixNano()) / float64(time.Second))
+				}
+			}
+			retry := live.getRetry(sourceName)
+			backoff := newBackoff(retry)
 			for {
 				select {
 				case <-ctx.Done():
 					// we don't report error here, this is normal cancellation
 					return fmt.Errorf("could not send message: %w", ctx.Err())
 				default:
-					if uint64(backoff.Steps) < s.Retry.Steps { // this is a retry
+					if uint64(backoff.Steps) < retry.Steps { // this is a retry
 						logger.Info("retry", "source", sourceName, "backoff", backoff)
 						retriesCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Inc()
 					}
@@ -107,6 +249,12 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 					if err != nil {
 						return err
 					}
+					// a slot is acquired for each attempt, so messages waiting to be retried do not hold one
+					if merge != nil {
+						if err := merge.acquire(ctx, sourceName); err != nil {
+							return fmt.Errorf("could not send message: %w", err)
+						}
+					}
 					newCtx, cancel := context.WithTimeout(
 						dfv1.ContextWithMeta(
 							opentracing.ContextWithSpan(context.Background(), span),
@@ -115,13 +263,24 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 						15*time.Second,
 					)
 
-					err = process(newCtx, msg)
+					if join != nil {
+						err = join.process(newCtx, sourceName, msg)
+					} else {
+						err = process(newCtx, msg)
+					}
 					cancel()
+					if merge != nil {
+						merge.release()
+					}
 					if err == nil {
 						return nil
 					}
-					giveUp := backoff.Steps <= 0
-					logger := logger.WithValues("source", sourceName, "backoffSteps", backoff.Steps, "giveUp", giveUp)
+					class := errorClass(err)
+					handlerErrorsCounter.WithLabelValues(sourceName, fmt.Sprint(replica), class).Inc()
+					lastErrs.set(sourceName, err, time.Now())
+					// permanent errors will recur, so there is no point retrying them
+					giveUp := backoff.Steps <= 0 || class == errorClassPermanent
+					logger := logger.WithValues("source", sourceName, "backoffSteps", backoff.Steps, "giveUp", giveUp, "class", class)
 					if giveUp {
 						logger.Error(err, "failed to send process message")
 						errorsCounter.WithLabelValues(sourceName, fmt.Sprint(replica)).Inc()
@@ -137,6 +296,7 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 				}
 			}
 		}
+		processWithRetry = injectAckFaults(processWithRetry)
 		if x := s.Cron; x != nil {
 			if y, err := cron.New(ctx, sourceName, sourceURN, *x, processWithRetry); err != nil {
 				return err
@@ -144,47 +304,151 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 				sources[sourceName] = y
 			}
 		} else if x := s.STAN; x != nil {
-			if y, err := stan.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceURN, replica, sourceName, *x, processWithRetry); err != nil {
+			if r, done := offsetReset(dfv1.PathVarRun, sourceName); r != nil {
+				logger.Info("resetting STAN durable queue", "source", sourceName, "offset", r.Offset)
+				clientID := fmt.Sprintf("%s-%s-%s-%d-source-%s-reset", namespace, pipelineName, stepName, replica, sourceName)
+				if err := stan.ResetPosition(ctx, secretInterface, clientID, sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName), *x, r.Offset); err != nil {
+					return fmt.Errorf("failed to reset source %q: %w", sourceName, err)
+				}
+				if err := done(); err != nil {
+					return err
+				}
+			}
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = stan.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceURN, replica, sourceName, *x, processWithRetry, dlq)
+				return err
+			}); err != nil {
 				return err
-			} else {
-				sources[sourceName] = y
 			}
 		} else if x := s.Kafka; x != nil {
-			if y, err := kafkasource.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN, replica, *x, processWithRetry); err != nil {
+			if r, done := offsetReset(dfv1.PathVarRun, sourceName); r != nil {
+				logger.Info("resetting Kafka consumer group", "source", sourceName, "offset", r.Offset)
+				if err := kafkasource.ResetOffsets(ctx, secretInterface, x.GetGroupID(sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName)), *x, r.Offset); err != nil {
+					return fmt.Errorf("failed to reset source %q: %w", sourceName, err)
+				}
+				if err := done(); err != nil {
+					return err
+				}
+			}
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = kafkasource.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN, replica, *x, s.Bounded, processWithRetry, dlq, recordEvent, fail, transactionalProducer)
+				return err
+			}); err != nil {
 				return err
-			} else {
-				sources[sourceName] = y
 			}
 		} else if x := s.HTTP; x != nil {
-			if _, y, err := httpsource.New(ctx, secretInterface, pipelineName, stepName, sourceURN, sourceName, processWithRetry); err != nil {
+			if _, y, err := httpsource.New(ctx, secretInterface, pipelineName, stepName, sourceURN, sourceName, *x, processWithRetry); err != nil {
 				return err
 			} else {
 				sources[sourceName] = y
 			}
 		} else if x := s.S3; x != nil {
-			if y, err := s3source.New(ctx, secretInterface, pipelineName, stepName, sourceName, sourceURN, *x, processWithRetry, leadReplica()); err != nil {
+			if y, err := s3source.New(ctx, secretInterface, pipelineName, stepName, sourceName, sourceURN, *x, s.Bounded, processWithRetry, leadReplica()); err != nil {
 				return err
 			} else {
 				sources[sourceName] = y
 			}
 		} else if x := s.DB; x != nil {
-			if y, err := dbsource.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceName, sourceURN, *x, processWithRetry); err != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = dbsource.New(ctx, secretInterface, dynamicInterface.Resource(dfv1.StepGroupVersionResource).Namespace(namespace), cluster, namespace, pipelineName, stepName, sourceName, sourceURN, *x, processWithRetry)
+				return err
+			}); err != nil {
 				return err
-			} else {
-				sources[sourceName] = y
 			}
 		} else if x := s.Volume; x != nil {
-			if y, err := volumeSource.New(ctx, secretInterface, pipelineName, stepName, sourceName, sourceURN, *x, processWithRetry, leadReplica()); err != nil {
+			if y, err := volumeSource.New(ctx, secretInterface, pipelineName, stepName, sourceName, sourceURN, *x, s.Bounded, processWithRetry, leadReplica()); err != nil {
 				return err
 			} else {
 				sources[sourceName] = y
 			}
 		} else if x := s.JetStream; x != nil {
-			if y, err := jssource.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceURN, replica, sourceName, *x, processWithRetry); err != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = jssource.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceURN, replica, sourceName, *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.ArgoEvents; x != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = argoeventssource.New(ctx, secretInterface, cluster, namespace, pipelineName, stepName, sourceURN, sourceName, *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.Dapr; x != nil {
+			if y, err := daprsource.New(sourceURN, sourceName, *x, processWithRetry); err != nil {
 				return err
 			} else {
 				sources[sourceName] = y
 			}
+		} else if x := s.Generator; x != nil {
+			position, err := checkpoints.position(ctx, sourceName)
+			if err != nil {
+				return err
+			}
+			if y, err := generator.New(ctx, sourceName, sourceURN, replica, *x, processWithRetry, position); err != nil {
+				return err
+			} else {
+				sources[sourceName] = y
+			}
+		} else if x := s.Test; x != nil {
+			if y, err := testsource.New(ctx, secretInterface, pipelineName, stepName, sourceURN, sourceName, *x, processWithRetry); err != nil {
+				return err
+			} else {
+				sources[sourceName] = y
+			}
+		} else if x := s.Redis; x != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = redissource.New(ctx, secretInterface, sourceName, sourceURN, *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.RedisStream; x != nil {
+			sourceUID := sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName)
+			consumer := fmt.Sprintf("%s-%d", sourceUID, replica)
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = redisstreamsource.New(ctx, secretInterface, sourceName, sourceURN, consumer, x.GetGroup(sourceUID), *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.Elasticsearch; x != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = elasticsearch.New(ctx, secretInterface, sourceName, sourceURN, *x, processWithRetry, leadReplica())
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.SQS; x != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = sqssource.New(ctx, secretInterface, sourceName, sourceURN, *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.Kinesis; x != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = kinesissource.New(ctx, secretInterface, dynamicInterface.Resource(dfv1.StepGroupVersionResource).Namespace(namespace), pipelineName, stepName, sourceName, sourceURN, replica, live.getReplicas, *x, processWithRetry, updateInterval)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.AMQP; x != nil {
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = amqpsource.New(ctx, secretInterface, sourceName, sourceURN, *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
+		} else if x := s.MQTT; x != nil {
+			clientID := fmt.Sprintf("%s-%d", sharedutil.GetSourceUID(cluster, namespace, pipelineName, stepName, sourceName), replica)
+			if err := connectWithRetry(ctx, fmt.Sprintf("source %q", sourceName), func() (err error) {
+				sources[sourceName], err = mqttsource.New(ctx, secretInterface, clientID, sourceName, sourceURN, *x, processWithRetry)
+				return err
+			}); err != nil {
+				return err
+			}
 		} else {
 			return fmt.Errorf("source misconfigured")
 		}
@@ -194,37 +458,86 @@ func connectSources(ctx context.Context, process func(context.Context, []byte) e
 		})
 		if x, ok := sources[sourceName].(source.HasPending); ok && leadReplica() {
 			logger.Info("starting pending loop", "source", sourceName, "updateInterval", updateInterval.String())
-			go wait.JitterUntilWithContext(ctx, func(ctx context.Context) {
+			go untilWithFailureBackoff(ctx, func(ctx context.Context) error {
 				if pending, err := x.GetPending(ctx); err != nil {
 					if errors.Is(err, source.ErrPendingUnavailable) {
 						logger.Info("failed to get pending", "source", sourceName, "err", err.Error())
 					} else {
 						logger.Error(err, "failed to get pending", "source", sourceName)
 					}
+					return err
 				} else {
 					logger.Info("got pending", "source", sourceName, "pending", pending)
 					pendingGauge.WithLabelValues(sourceName).Set(float64(pending))
 				}
-			}, updateInterval, 1.2, true)
+				return nil
+			}, updateInterval)
+		}
+		if x, ok := sources[sourceName].(source.HasPartitionPending); ok {
+			partitions := map[string]bool{}
+			go untilWithFailureBackoff(ctx, func(ctx context.Context) error {
+				pending, err := x.GetPartitionPending(ctx)
+				if err != nil {
+					if !errors.Is(err, source.ErrPendingUnavailable) {
+						logger.Error(err, "failed to get partition pending", "source", sourceName)
+					}
+					return err
+				}
+				// partitions may be revoked, and then be reported by another replica
+				for partition := range partitions {
+					if _, ok := pending[partition]; !ok {
+						partitionPendingGauge.DeleteLabelValues(sourceName, partition)
+						delete(partitions, partition)
+					}
+				}
+				for partition, n := range pending {
+					partitionPendingGauge.WithLabelValues(sourceName, partition).Set(float64(n))
+					partitions[partition] = true
+				}
+				return nil
+			}, updateInterval)
+		}
+		if x, ok := sources[sourceName].(source.HasPartitionLag); ok {
+			partitions := map[string]bool{}
+			go untilWithFailureBackoff(ctx, func(ctx context.Context) error {
+				lags, err := x.GetPartitionLag(ctx)
+				if err != nil {
+					if !errors.Is(err, source.ErrPendingUnavailable) {
+						logger.Error(err, "failed to get partition lag", "source", sourceName)
+					}
+					return err
+				}
+				for partition := range partitions {
+					if _, ok := lags[partition]; !ok {
+						partitionLagGauge.DeleteLabelValues(sourceName, partition)
+						delete(partitions, partition)
+					}
+				}
+				for partition, lag := range lags {
+					partitionLagGauge.WithLabelValues(sourceName, partition).Set(lag.Seconds())
+					partitions[partition] = true
+				}
+				return nil
+			}, updateInterval)
 		}
 	}
+	checkpoints.run(ctx, sources)
+	connectCompletion(ctx, sources, complete)
+	sourcesConnected = func() bool { return allConnected(sources) }
 	return nil
 }
 
-func createSecret(ctx context.Context) error {
-	data := map[string]string{}
-	for _, s := range step.Spec.Sources {
-		data[fmt.Sprintf("sources.%s.http.authorization", s.Name)] = fmt.Sprintf("Bearer %s", sharedutil.RandString())
-	}
-	_, err := secretInterface.Create(ctx, &corev1.Secret{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:            step.Name,
-			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(step.GetObjectMeta(), dfv1.StepGroupVersionKind)},
-		},
-		StringData: data,
-	}, metav1.CreateOptions{})
-	if sharedutil.IgnoreAlreadyExists(err) != nil {
-		return fmt.Errorf("failed to create secret %q: %w", step.Name, err)
+// sourcesConnected returns whether every source is connected, so the sidecar is not ready, and HTTP sources do not
+// receive traffic, while other sources are still connecting. It is set by connectSources.
+var sourcesConnected = func() bool { return true }
+
+// allConnected returns whether every source that connects asynchronously is connected, see source.Connectable.
+func allConnected(sources map[string]source.Interface) bool {
+	for sourceName, s := range sources {
+		if x, ok := s.(source.Connectable); ok && !x.IsConnected() {
+			logger.Info("source not connected", "source", sourceName)
+			return false
+		}
 	}
-	return nil
+	return true
 }
@@ -0,0 +1,21 @@
+package sidecar
+
+import (
+	"testing"
+
+	"github.com/argoproj-labs/argo-dataflow/runner/sidecar/source"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnectable struct {
+	source.Interface
+	connected bool
+}
+
+func (s testConnectable) IsConnected() bool { return s.connected }
+
+func Test_allConnected(t *testing.T) {
+	assert.True(t, allConnected(nil))
+	assert.True(t, allConnected(map[string]source.Interface{"a": testConnectable{connected: true}, "b": nil}))
+	assert.False(t, allConnected(map[string]source.Interface{"a": testConnectable{connected: true}, "b": testConnectable{}}))
+}
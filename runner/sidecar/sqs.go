@@ -0,0 +1,34 @@
+package sidecar
+
+import (
+	"context"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func sqsFromSecret(x *dfv1.SQSSource, secret *corev1.Secret) {
+	x.QueueURL = dfv1.StringOr(x.QueueURL, string(secret.Data["queueUrl"]))
+	x.Region = dfv1.StringOr(x.Region, string(secret.Data["region"]))
+	// without credentials, we use workload identity
+	if x.Credentials == nil {
+		x.Credentials = awsCredentialsFromSecret(secret)
+	}
+	if v, ok := secret.Data["endpoint.url"]; ok && x.Endpoint == nil {
+		x.Endpoint = &dfv1.AWSEndpoint{URL: string(v)}
+	}
+}
+
+func enrichSQS(ctx context.Context, x *dfv1.SQSSource) error {
+	secret, err := secretInterface.Get(ctx, "dataflow-sqs-"+x.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			return err
+		}
+	} else {
+		sqsFromSecret(x, secret)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package sidecar
+
+import (
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_sqsFromSecret(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dataflow-sqs-default"},
+		Data: map[string][]byte{
+			"queueUrl":                         []byte("https://sqs.us-west-2.amazonaws.com/123456789012/my-queue"),
+			"region":                           []byte("us-west-2"),
+			"credentials.accessKeyId.name":     []byte("my-secret"),
+			"credentials.accessKeyId.key":      []byte("accessKeyId"),
+			"credentials.secretAccessKey.name": []byte("my-secret"),
+			"credentials.secretAccessKey.key":  []byte("secretAccessKey"),
+			"endpoint.url":                     []byte("http://localstack:4566"),
+		},
+	}
+	t.Run("FromSecret", func(t *testing.T) {
+		x := &dfv1.SQSSource{}
+		sqsFromSecret(x, secret)
+		assert.Equal(t, "https://sqs.us-west-2.amazonaws.com/123456789012/my-queue", x.QueueURL)
+		assert.Equal(t, "us-west-2", x.Region)
+		if assert.NotNil(t, x.Credentials) {
+			assert.Equal(t, "my-secret", x.Credentials.AccessKeyID.Name)
+			assert.Equal(t, "secretAccessKey", x.Credentials.SecretAccessKey.Key)
+		}
+		assert.Equal(t, &dfv1.AWSEndpoint{URL: "http://localstack:4566"}, x.Endpoint)
+	})
+	t.Run("Specified", func(t *testing.T) {
+		x := &dfv1.SQSSource{QueueURL: "my-queue-url", Region: "eu-west-1"}
+		sqsFromSecret(x, &corev1.Secret{})
+		assert.Equal(t, "my-queue-url", x.QueueURL)
+		assert.Equal(t, "eu-west-1", x.Region)
+		assert.Nil(t, x.Credentials, "without credentials, we use workload identity")
+	})
+}
@@ -0,0 +1,28 @@
+package sidecar
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// watermark is the latest event time processed by this replica, as Unix nanoseconds. It never goes backwards, so
+// late messages do not move it.
+type watermark struct{ unixNano int64 }
+
+// advance moves the watermark to the time, if it is later, and returns true if it did.
+func (w *watermark) advance(t time.Time) bool {
+	n := t.UnixNano()
+	for {
+		old := atomic.LoadInt64(&w.unixNano)
+		if n <= old {
+			return false
+		}
+		if atomic.CompareAndSwapInt64(&w.unixNano, old, n) {
+			return true
+		}
+	}
+}
+
+func (w *watermark) get() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&w.unixNano))
+}
@@ -0,0 +1,18 @@
+package sidecar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_watermark(t *testing.T) {
+	w := &watermark{}
+	now := time.Now()
+	assert.True(t, w.advance(now))
+	assert.False(t, w.advance(now.Add(-time.Second)), "late messages do not move the watermark")
+	assert.True(t, now.Equal(w.get()))
+	assert.True(t, w.advance(now.Add(time.Second)))
+	assert.True(t, now.Add(time.Second).Equal(w.get()))
+}
@@ -0,0 +1,121 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	kafkasource "github.com/argoproj-labs/argo-dataflow/runner/sidecar/source/kafka"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/yaml"
+)
+
+var logger = sharedutil.NewLogger()
+
+// getOffsets returns the committed offsets of a Kafka source, keyed by partition.
+type getOffsets func(ctx context.Context, groupID string, x dfv1.KafkaSource) (map[string]int64, error)
+
+// Exec writes a snapshot of the sidecar's pipeline to out. The snapshot is the pipeline's manifest, with each Kafka
+// source's startOffsets set to the offsets committed by its consumer group, so applying it (e.g. in another cluster)
+// creates a pipeline that resumes where this one is. It is run in the sidecar container, e.g. using `kubectl exec`, so
+// it can connect to the same brokers as the pipeline.
+func Exec(ctx context.Context, out io.Writer) error {
+	cluster := os.Getenv(dfv1.EnvCluster)
+	namespace := os.Getenv(dfv1.EnvNamespace)
+	pipelineName := os.Getenv(dfv1.EnvPipelineName)
+	restConfig := ctrl.GetConfigOrDie()
+	un, err := dynamic.NewForConfigOrDie(restConfig).
+		Resource(dfv1.PipelineGroupVersionResource).
+		Namespace(namespace).
+		Get(ctx, pipelineName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get pipeline %q: %w", pipelineName, err)
+	}
+	secretInterface := util.NewSecretInterface(kubernetes.NewForConfigOrDie(restConfig).CoreV1().Secrets(namespace))
+	x, err := snapshot(ctx, cluster, namespace, un, func(ctx context.Context, groupID string, x dfv1.KafkaSource) (map[string]int64, error) {
+		return kafkasource.GetCommittedOffsets(ctx, secretInterface, groupID, x)
+	})
+	if err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(x.Object)
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(data)
+	return err
+}
+
+func snapshot(ctx context.Context, cluster, namespace string, un *unstructured.Unstructured, getOffsets getOffsets) (*unstructured.Unstructured, error) {
+	pl := dfv1.Pipeline{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(un.Object, &pl); err != nil {
+		return nil, err
+	}
+	steps, _, err := unstructured.NestedSlice(un.Object, "spec", "steps")
+	if err != nil {
+		return nil, err
+	}
+	for i, step := range pl.Spec.Steps {
+		sources, _, err := unstructured.NestedSlice(steps[i].(map[string]interface{}), "sources")
+		if err != nil {
+			return nil, err
+		}
+		for j, source := range step.Sources {
+			x := source.Kafka
+			if x == nil {
+				logger.Info("source does not have positions, it will start according to its spec", "step", step.Name, "source", source.Name)
+				continue
+			}
+			if !x.HasSingleTopic() {
+				logger.Info("source consumes several topics, it will start according to its spec", "step", step.Name, "source", source.Name)
+				continue
+			}
+			groupID := x.GetGroupID(sharedutil.GetSourceUID(cluster, namespace, pl.Name, step.Name, source.Name))
+			offsets, err := getOffsets(ctx, groupID, *x)
+			if err != nil {
+				return nil, fmt.Errorf("step %q source %q: %w", step.Name, source.Name, err)
+			}
+			// keep any start offsets that have not been committed yet, e.g. if this pipeline was restored from a snapshot
+			startOffsets := map[string]interface{}{}
+			for partition, offset := range x.StartOffsets {
+				startOffsets[partition] = offset
+			}
+			for partition, offset := range offsets {
+				startOffsets[partition] = offset
+			}
+			logger.Info("snapshot source", "step", step.Name, "source", source.Name, "startOffsets", startOffsets)
+			if err := unstructured.SetNestedField(sources[j].(map[string]interface{}), startOffsets, "kafka", "startOffsets"); err != nil {
+				return nil, err
+			}
+		}
+		if err := unstructured.SetNestedSlice(steps[i].(map[string]interface{}), sources, "sources"); err != nil {
+			return nil, err
+		}
+	}
+	spec, _, err := unstructured.NestedMap(un.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+	if err := unstructured.SetNestedSlice(spec, steps, "steps"); err != nil {
+		return nil, err
+	}
+	// only keep what is needed to create the pipeline, in any namespace
+	x := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	x.SetAPIVersion(dfv1.GroupVersion.String())
+	x.SetKind(dfv1.PipelineGroupVersionKind.Kind)
+	x.SetName(un.GetName())
+	x.SetLabels(un.GetLabels())
+	annotations := un.GetAnnotations()
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	x.SetAnnotations(annotations)
+	return x, nil
+}
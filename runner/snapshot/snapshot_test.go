@@ -0,0 +1,98 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func Test_snapshot(t *testing.T) {
+	un := &unstructured.Unstructured{}
+	err := yaml.Unmarshal([]byte(`
+apiVersion: dataflow.argoproj.io/v1alpha1
+kind: Pipeline
+metadata:
+  name: my-pl
+  namespace: my-ns
+  uid: my-uid
+  resourceVersion: "1"
+  labels:
+    my-label: my-value
+  annotations:
+    kubectl.kubernetes.io/last-applied-configuration: "{}"
+    my-annotation: my-value
+spec:
+  steps:
+  - name: main
+    cat: {}
+    sources:
+    - name: cron
+      cron:
+        schedule: "* * * * *"
+    - name: kafka
+      kafka:
+        topic: my-topic
+        startOffsets:
+          "0": 1
+          "1": 2
+    - name: topics
+      kafka:
+        topic: my-topic
+        topics:
+        - ^my-.*
+status:
+  phase: Running
+`), &un.Object)
+	assert.NoError(t, err)
+	groupID := sharedutil.GetSourceUID("my-cluster", "my-ns", "my-pl", "main", "kafka")
+	x, err := snapshot(context.Background(), "my-cluster", "my-ns", un, func(_ context.Context, g string, x dfv1.KafkaSource) (map[string]int64, error) {
+		assert.Equal(t, groupID, g)
+		assert.Equal(t, "my-topic", x.Topic)
+		return map[string]int64{"1": 20, "2": 30}, nil
+	})
+	assert.NoError(t, err)
+	data, err := yaml.Marshal(x.Object)
+	assert.NoError(t, err)
+	assert.Equal(t, `apiVersion: dataflow.argoproj.io/v1alpha1
+kind: Pipeline
+metadata:
+  annotations:
+    my-annotation: my-value
+  labels:
+    my-label: my-value
+  name: my-pl
+spec:
+  steps:
+  - cat: {}
+    name: main
+    sources:
+    - cron:
+        schedule: '* * * * *'
+      name: cron
+    - kafka:
+        startOffsets:
+          "0": 1
+          "1": 20
+          "2": 30
+        topic: my-topic
+      name: kafka
+    - kafka:
+        topic: my-topic
+        topics:
+        - ^my-.*
+      name: topics
+`, string(data))
+
+	t.Run("Error", func(t *testing.T) {
+		_, err := snapshot(context.Background(), "my-cluster", "my-ns", un, func(context.Context, string, dfv1.KafkaSource) (map[string]int64, error) {
+			return nil, fmt.Errorf("failed")
+		})
+		assert.EqualError(t, err, `step "main" source "kafka": failed`)
+	})
+}
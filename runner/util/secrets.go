@@ -0,0 +1,62 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type mountedSecrets struct {
+	corev1client.SecretInterface
+	vault *vaultSecrets
+	dir   string
+}
+
+// NewSecretInterface returns a secret interface that reads the secrets the controller mounted into the pod from disk,
+// then, if configured, from Vault. It never reads secrets using the Kubernetes API, so the pod does not need RBAC to
+// read secrets.
+func NewSecretInterface(secretInterface corev1client.SecretInterface) corev1client.SecretInterface {
+	m := mountedSecrets{SecretInterface: secretInterface, dir: dfv1.PathSecrets}
+	if addr := os.Getenv(dfv1.EnvVaultAddr); addr != "" {
+		m.vault = newVaultSecrets(addr)
+	}
+	return m
+}
+
+func (m mountedSecrets) Get(ctx context.Context, name string, _ metav1.GetOptions) (*corev1.Secret, error) {
+	dir := filepath.Join(m.dir, name)
+	items, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("secret %q not mounted, only secrets referenced by the step are mounted", name)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read secret dir %q: %w", dir, err)
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}, Data: map[string][]byte{}}
+	for _, item := range items {
+		// Kubernetes keeps the real files in hidden directories, e.g. `..data`, and each key is a symlink
+		if strings.HasPrefix(item.Name(), ".") || item.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, item.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret key %q: %w", item.Name(), err)
+		}
+		secret.Data[item.Name()] = data
+	}
+	// the secret is optional, so the controller mounts an empty directory if it does not exist, but it may be in Vault
+	if len(secret.Data) == 0 {
+		if m.vault != nil {
+			return m.vault.Get(ctx, name)
+		}
+		return nil, apierr.NewNotFound(corev1.Resource("secrets"), name)
+	}
+	return secret, nil
+}
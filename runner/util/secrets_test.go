@@ -0,0 +1,34 @@
+package util
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewSecretInterface(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "mounted", "..data"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "mounted", "my-key"), []byte("my-value"), 0o600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "missing"), 0o700))
+	s := mountedSecrets{dir: dir}
+	t.Run("Mounted", func(t *testing.T) {
+		secret, err := s.Get(ctx, "mounted", metav1.GetOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]byte{"my-key": []byte("my-value")}, secret.Data)
+	})
+	t.Run("MountedButMissing", func(t *testing.T) {
+		_, err := s.Get(ctx, "missing", metav1.GetOptions{})
+		assert.True(t, apierr.IsNotFound(err))
+	})
+	t.Run("NotMounted", func(t *testing.T) {
+		_, err := s.Get(ctx, "not-mounted", metav1.GetOptions{})
+		assert.EqualError(t, err, `secret "not-mounted" not mounted, only secrets referenced by the step are mounted`)
+	})
+}
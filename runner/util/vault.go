@@ -0,0 +1,138 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+type vaultSecrets struct {
+	addr      string
+	role      string
+	authPath  string
+	path      string
+	tokenPath string
+	client    *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time // zero if the token does not expire
+}
+
+// newVaultSecrets returns a secret interface that reads secrets from Vault, using Kubernetes auth. The secret
+// `my-secret` is read from `${path}/my-secret`, and each key in the KV v2 secret becomes a key in the secret's data.
+// Values that are not strings, e.g. numbers, are JSON encoded. Secrets that are not in Vault are not found.
+func newVaultSecrets(addr string) *vaultSecrets {
+	return &vaultSecrets{
+		addr:      strings.TrimSuffix(addr, "/"),
+		role:      sharedutil.GetEnvString(dfv1.EnvVaultRole, "argo-dataflow"),
+		authPath:  strings.Trim(sharedutil.GetEnvString(dfv1.EnvVaultAuthPath, "kubernetes"), "/"),
+		path:      strings.Trim(sharedutil.GetEnvString(dfv1.EnvVaultPath, "secret/data/argo-dataflow"), "/"),
+		tokenPath: serviceAccountTokenPath,
+		client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (v *vaultSecrets) Get(ctx context.Context, name string) (*corev1.Secret, error) {
+	token, err := v.getToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}{}
+	if found, err := v.do(ctx, "GET", v.path+"/"+name, token, nil, &resp); err != nil {
+		return nil, fmt.Errorf("failed to read secret %q from Vault: %w", name, err)
+	} else if !found {
+		return nil, apierr.NewNotFound(corev1.Resource("secrets"), name)
+	}
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name}, Data: map[string][]byte{}}
+	for k, x := range resp.Data.Data {
+		if s, ok := x.(string); ok {
+			secret.Data[k] = []byte(s)
+		} else if secret.Data[k], err = json.Marshal(x); err != nil {
+			return nil, fmt.Errorf("failed to encode key %q of secret %q from Vault: %w", k, name, err)
+		}
+	}
+	return secret, nil
+}
+
+// getToken logs in using the pod's service account token, and re-uses the Vault token until shortly before it expires.
+// A lease duration of zero means the token does not expire.
+func (v *vaultSecrets) getToken(ctx context.Context) (string, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.token != "" && (v.expiresAt.IsZero() || time.Now().Before(v.expiresAt)) {
+		return v.token, nil
+	}
+	jwt, err := os.ReadFile(v.tokenPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read service account token: %w", err)
+	}
+	resp := struct {
+		Auth struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}{}
+	body := map[string]string{"role": v.role, "jwt": string(jwt)}
+	if found, err := v.do(ctx, "POST", "auth/"+v.authPath+"/login", "", body, &resp); err != nil {
+		return "", fmt.Errorf("failed to login to Vault: %w", err)
+	} else if !found || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("failed to login to Vault: no token returned")
+	}
+	v.token = resp.Auth.ClientToken
+	v.expiresAt = time.Time{}
+	if d := resp.Auth.LeaseDuration; d > 0 {
+		v.expiresAt = time.Now().Add(time.Duration(d) * time.Second * 9 / 10)
+	}
+	return v.token, nil
+}
+
+// do makes a request to the Vault API, returning false if the path was not found.
+func (v *vaultSecrets) do(ctx context.Context, method, path, token string, body, obj interface{}) (bool, error) {
+	var data []byte
+	if body != nil {
+		var err error
+		if data, err = json.Marshal(body); err != nil {
+			return false, err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, v.addr+"/v1/"+path, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == 404 {
+		return false, nil
+	}
+	if resp.StatusCode != 200 {
+		return false, fmt.Errorf("%s %q returned %q", method, path, resp.Status)
+	}
+	d := json.NewDecoder(resp.Body)
+	d.UseNumber() // so numbers in secrets are not re-encoded as floats
+	return true, d.Decode(obj)
+}
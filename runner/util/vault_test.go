@@ -0,0 +1,86 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+)
+
+func TestVaultSecrets(t *testing.T) {
+	ctx := context.Background()
+	logins := 0
+	leaseDuration := 3600
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/auth/kubernetes/login":
+			logins++
+			body := map[string]string{}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			if body["role"] != "argo-dataflow" || body["jwt"] != "my-jwt" {
+				w.WriteHeader(403)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"auth": map[string]interface{}{"client_token": "my-token", "lease_duration": leaseDuration}})
+		case "/v1/secret/data/argo-dataflow/dataflow-kafka-default":
+			if r.Header.Get("X-Vault-Token") != "my-token" {
+				w.WriteHeader(403)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"data": map[string]interface{}{
+				"brokers":  "kafka-broker:9092",
+				"port":     9092,
+				"enabled":  true,
+				"settings": map[string]string{"a": "b"},
+			}}})
+		default:
+			w.WriteHeader(404)
+		}
+	}))
+	defer server.Close()
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenPath, []byte("my-jwt"), 0o600))
+	v := newVaultSecrets(server.URL + "/")
+	v.tokenPath = tokenPath
+	t.Run("InVault", func(t *testing.T) {
+		secret, err := v.Get(ctx, "dataflow-kafka-default")
+		assert.NoError(t, err)
+		assert.Equal(t, "kafka-broker:9092", string(secret.Data["brokers"]))
+		assert.Equal(t, "9092", string(secret.Data["port"]))
+		assert.Equal(t, "true", string(secret.Data["enabled"]))
+		assert.Equal(t, `{"a":"b"}`, string(secret.Data["settings"]))
+	})
+	t.Run("NotInVault", func(t *testing.T) {
+		_, err := v.Get(ctx, "not-in-vault")
+		assert.True(t, apierr.IsNotFound(err))
+	})
+	t.Run("TokenReused", func(t *testing.T) {
+		assert.Equal(t, 1, logins)
+	})
+	t.Run("NonExpiringToken", func(t *testing.T) {
+		leaseDuration = 0
+		defer func() { leaseDuration = 3600 }()
+		v := newVaultSecrets(server.URL)
+		v.tokenPath = tokenPath
+		logins = 0
+		_, err := v.Get(ctx, "dataflow-kafka-default")
+		assert.NoError(t, err)
+		_, err = v.Get(ctx, "dataflow-kafka-default")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, logins)
+		assert.True(t, v.expiresAt.IsZero())
+	})
+	t.Run("LoginFailed", func(t *testing.T) {
+		v := newVaultSecrets(server.URL)
+		v.tokenPath = tokenPath
+		v.role = "other"
+		_, err := v.Get(ctx, "dataflow-kafka-default")
+		assert.Error(t, err)
+	})
+}
@@ -7,8 +7,10 @@ import (
 	"time"
 
 	"github.com/antonmedv/expr"
+	"github.com/antonmedv/expr/vm"
 	"github.com/argoproj-labs/argo-dataflow/runner/util"
 	"github.com/argoproj-labs/argo-dataflow/shared/builtin"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
 	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -22,7 +24,9 @@ var (
 	mu     = sync.Mutex{}
 )
 
-func New(ctx context.Context, uid string, maxSize resource.Quantity) (builtin.Process, error) {
+// New returns a process that drops messages whose UID it has seen before. If there is a store, UIDs are kept in it,
+// until its TTL elapses, otherwise they are kept in memory, until there are more than maxSize.
+func New(ctx context.Context, uid string, maxSize resource.Quantity, store state.Store) (builtin.Process, error) {
 	duplicates := promauto.NewCounter(prometheus.CounterOpts{
 		Name: "duplicate_messages",
 		Help: "Duplicates messages, see https://github.com/argoproj-labs/argo-dataflow/blob/main/docs/METRICS.md#duplicate_messages",
@@ -33,6 +37,10 @@ func New(ctx context.Context, uid string, maxSize resource.Quantity) (builtin.Pr
 		return nil, fmt.Errorf("failed to compile %q: %w", uid, err)
 	}
 
+	if store != nil {
+		return withStore(prog, store, duplicates), nil
+	}
+
 	int64MaxSize, ok := maxSize.AsInt64()
 	if !ok {
 		return nil, fmt.Errorf("max size %v must be int64", maxSize)
@@ -72,3 +80,28 @@ func New(ctx context.Context, uid string, maxSize resource.Quantity) (builtin.Pr
 		return msg, nil
 	}, nil
 }
+
+func withStore(prog *vm.Program, store state.Store, duplicates prometheus.Counter) builtin.Process {
+	return func(ctx context.Context, msg []byte) ([]byte, error) {
+		env, err := util.ExprEnv(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expr env: %w", err)
+		}
+		r, err := expr.Run(prog, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute program: %w", err)
+		}
+		id, ok := r.(string)
+		if !ok {
+			return nil, fmt.Errorf("expression did not evaluate to string")
+		}
+		// atomic, so replicas sharing a store never both see the same ID as new
+		if set, err := store.SetIfAbsent(ctx, id, []byte{1}); err != nil {
+			return nil, fmt.Errorf("failed to set %q in state: %w", id, err)
+		} else if !set {
+			duplicates.Inc()
+			return nil, nil
+		}
+		return msg, nil
+	}
+}
@@ -3,15 +3,20 @@ package dedupe
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/antonmedv/expr"
 	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/state"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestNew(t *testing.T) {
 	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id"})
-	p, err := New(ctx, `"1"`, resource.MustParse("1"))
+	p, err := New(ctx, `"1"`, resource.MustParse("1"), nil)
 	assert.NoError(t, err)
 	resp, err := p(ctx, []byte{0})
 	assert.NoError(t, err)
@@ -20,3 +25,23 @@ func TestNew(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Nil(t, resp)
 }
+
+func Test_withStore(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id"})
+	store := state.NewMemory(ctx, time.Minute)
+	defer func() { _ = store.Close() }()
+	prog, err := expr.Compile("string(msg)")
+	assert.NoError(t, err)
+	duplicates := prometheus.NewCounter(prometheus.CounterOpts{Name: "duplicate_messages"})
+	p := withStore(prog, store, duplicates)
+	resp, err := p(ctx, []byte("a"))
+	assert.NoError(t, err)
+	assert.NotNil(t, resp)
+	resp, err = p(ctx, []byte("a"))
+	assert.NoError(t, err)
+	assert.Nil(t, resp)
+	assert.Equal(t, float64(1), testutil.ToFloat64(duplicates))
+	v, err := store.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.NotNil(t, v)
+}
@@ -0,0 +1,59 @@
+package builtin
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+)
+
+var logger = sharedutil.NewLogger()
+
+// Reload returns a process for the expression, created using newProcess, that is re-created whenever the expression
+// in the file at path changes, so that a changed expression is applied without re-creating the pod. Until the file
+// exists, the expression is used. An expression that cannot be compiled is logged, and the previous process kept.
+func Reload(ctx context.Context, path, expression string, newProcess func(expression string) (Process, error), period time.Duration) (Process, error) {
+	p, err := newProcess(expression)
+	if err != nil {
+		return nil, err
+	}
+	mu := sync.RWMutex{}
+	go func() {
+		ticker := time.NewTicker(period)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				data, err := os.ReadFile(path)
+				if os.IsNotExist(err) {
+					continue
+				} else if err != nil {
+					logger.Error(err, "failed to read expression", "path", path)
+					continue
+				}
+				if x := string(data); x != expression {
+					q, err := newProcess(x)
+					if err != nil {
+						logger.Error(err, "failed to apply expression change, keeping current expression")
+						expression = x // only log once per change
+						continue
+					}
+					mu.Lock()
+					p, expression = q, x
+					mu.Unlock()
+					logger.Info("applied expression change", "expression", x)
+				}
+			}
+		}
+	}()
+	return func(ctx context.Context, msg []byte) ([]byte, error) {
+		mu.RLock()
+		q := p
+		mu.RUnlock()
+		return q(ctx, msg)
+	}, nil
+}
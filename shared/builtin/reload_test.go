@@ -0,0 +1,42 @@
+package builtin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	path := filepath.Join(t.TempDir(), "expression")
+	newProcess := func(expression string) (Process, error) {
+		if expression == "invalid" {
+			return nil, fmt.Errorf("invalid")
+		}
+		return func(ctx context.Context, msg []byte) ([]byte, error) {
+			return []byte(expression), nil
+		}, nil
+	}
+	_, err := Reload(ctx, path, "invalid", newProcess, time.Millisecond)
+	assert.Error(t, err)
+	p, err := Reload(ctx, path, "a", newProcess, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assertProcess := func(expected string) {
+		assert.Eventually(t, func() bool {
+			resp, err := p(ctx, nil)
+			return err == nil && string(resp) == expected
+		}, time.Second, 10*time.Millisecond)
+	}
+	assertProcess("a")
+	assert.NoError(t, os.WriteFile(path, []byte("b"), 0o600))
+	assertProcess("b")
+	assert.NoError(t, os.WriteFile(path, []byte("invalid"), 0o600))
+	time.Sleep(50 * time.Millisecond)
+	assertProcess("b")
+}
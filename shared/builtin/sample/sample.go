@@ -0,0 +1,54 @@
+package sample
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/antonmedv/expr"
+	"github.com/argoproj-labs/argo-dataflow/runner/util"
+	"github.com/argoproj-labs/argo-dataflow/shared/builtin"
+)
+
+// New returns a process that keeps the percentage of messages. If there is a key expression, messages are kept by the
+// hash of their key, otherwise at random.
+func New(percent float64, key string) (builtin.Process, error) {
+	if key == "" {
+		return func(ctx context.Context, msg []byte) ([]byte, error) {
+			if rand.Float64()*100 < percent {
+				return msg, nil
+			}
+			return nil, nil
+		}, nil
+	}
+	prog, err := expr.Compile(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile %q: %w", key, err)
+	}
+	return func(ctx context.Context, msg []byte) ([]byte, error) {
+		env, err := util.ExprEnv(ctx, msg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expr env: %w", err)
+		}
+		res, err := expr.Run(prog, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed to run program: %w", err)
+		}
+		k, ok := res.(string)
+		if !ok {
+			return nil, fmt.Errorf("key expression must return a string")
+		}
+		if keyFraction(k)*100 < percent {
+			return msg, nil
+		}
+		return nil, nil
+	}, nil
+}
+
+// keyFraction returns a number in [0, 1) from the key's hash, which is the same for the key in every process.
+func keyFraction(key string) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return float64(h.Sum64()>>11) / (1 << 53)
+}
@@ -0,0 +1,63 @@
+package sample
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id", Time: 0})
+	count := func(p func(context.Context, []byte) ([]byte, error)) int {
+		n := 0
+		for i := 0; i < 10000; i++ {
+			resp, err := p(ctx, []byte(fmt.Sprint(i)))
+			assert.NoError(t, err)
+			if resp != nil {
+				n++
+			}
+		}
+		return n
+	}
+	t.Run("Random", func(t *testing.T) {
+		p, err := New(10, "")
+		assert.NoError(t, err)
+		assert.InDelta(t, 1000, count(p), 200)
+	})
+	t.Run("None", func(t *testing.T) {
+		p, err := New(0, "")
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count(p))
+	})
+	t.Run("All", func(t *testing.T) {
+		p, err := New(100, "string(msg)")
+		assert.NoError(t, err)
+		assert.Equal(t, 10000, count(p))
+	})
+	t.Run("Key", func(t *testing.T) {
+		p, err := New(10, "string(msg)")
+		assert.NoError(t, err)
+		assert.InDelta(t, 1000, count(p), 200)
+		// the same key is always kept, or always dropped
+		first, err := p(ctx, []byte("my-key"))
+		assert.NoError(t, err)
+		for i := 0; i < 10; i++ {
+			resp, err := p(ctx, []byte("my-key"))
+			assert.NoError(t, err)
+			assert.Equal(t, first, resp)
+		}
+	})
+	t.Run("NotString", func(t *testing.T) {
+		p, err := New(10, "1")
+		assert.NoError(t, err)
+		_, err = p(ctx, []byte("my-key"))
+		assert.EqualError(t, err, "key expression must return a string")
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New(10, "bytes(")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,83 @@
+package split
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/argoproj-labs/argo-dataflow/shared/builtin"
+)
+
+// Send sends a part to the sinks.
+type Send func(ctx context.Context, id string, part []byte) error
+
+// New returns a process that splits each message on the delimiter, or into chunks of size bytes, and sends each part,
+// rather than returning it. If sending a part fails, the message is retried, so parts may be sent more than once.
+func New(delimiter string, size int64, send Send) (builtin.Process, error) {
+	if (delimiter == "") == (size <= 0) {
+		return nil, fmt.Errorf("exactly one of delimiter or size must be specified")
+	}
+	return func(ctx context.Context, msg []byte) ([]byte, error) {
+		m, err := dfv1.MetaFromContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		parts := Parts(msg, []byte(delimiter), int(size))
+		for i, part := range parts {
+			if err := send(ctx, fmt.Sprintf("%s/%d-of-%d", m.ID, i, len(parts)), part); err != nil {
+				return nil, fmt.Errorf("failed to send part %d of %d: %w", i, len(parts), err)
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// Parts splits the message on the delimiter, dropping empty parts, or, without a delimiter, into chunks of size bytes.
+func Parts(msg, delimiter []byte, size int) [][]byte {
+	var parts [][]byte
+	if len(delimiter) > 0 {
+		for _, part := range bytes.Split(msg, delimiter) {
+			if len(part) > 0 {
+				parts = append(parts, part)
+			}
+		}
+		return parts
+	}
+	for len(msg) > size {
+		parts = append(parts, msg[:size])
+		msg = msg[size:]
+	}
+	if len(msg) > 0 {
+		parts = append(parts, msg)
+	}
+	return parts
+}
+
+// NewSidecarSend returns a Send that POSTs parts to the sidecar, as a main container may, see IMAGE_CONTRACT.md.
+func NewSidecarSend() (Send, error) {
+	authorization, err := ioutil.ReadFile(dfv1.PathAuthorization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read authorization file: %w", err)
+	}
+	return func(ctx context.Context, id string, part []byte) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", "http://localhost:3569/messages", bytes.NewBuffer(part))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", string(authorization))
+		req.Header.Set(dfv1.MetaID, id)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode >= 300 {
+			return fmt.Errorf("HTTP request failed: %q %q", resp.Status, body)
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,52 @@
+package split
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	ctx := dfv1.ContextWithMeta(context.Background(), dfv1.Meta{Source: "my-source", ID: "my-id", Time: 0})
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := New("", 0, nil)
+		assert.EqualError(t, err, "exactly one of delimiter or size must be specified")
+		_, err = New(",", 1, nil)
+		assert.Error(t, err)
+	})
+	t.Run("Send", func(t *testing.T) {
+		sent := map[string]string{}
+		p, err := New(",", 0, func(ctx context.Context, id string, part []byte) error {
+			sent[id] = string(part)
+			return nil
+		})
+		assert.NoError(t, err)
+		resp, err := p(ctx, []byte("a,b"))
+		assert.NoError(t, err)
+		assert.Nil(t, resp)
+		assert.Equal(t, map[string]string{"my-id/0-of-2": "a", "my-id/1-of-2": "b"}, sent)
+	})
+	t.Run("Error", func(t *testing.T) {
+		p, err := New(",", 0, func(ctx context.Context, id string, part []byte) error {
+			return errors.New("failed")
+		})
+		assert.NoError(t, err)
+		_, err = p(ctx, []byte("a,b"))
+		assert.EqualError(t, err, "failed to send part 0 of 2: failed")
+	})
+}
+
+func TestParts(t *testing.T) {
+	t.Run("Delimiter", func(t *testing.T) {
+		assert.Equal(t, [][]byte{[]byte("a"), []byte("bc")}, Parts([]byte("a\n\nbc\n"), []byte("\n"), 0))
+		assert.Nil(t, Parts([]byte(""), []byte("\n"), 0))
+	})
+	t.Run("Size", func(t *testing.T) {
+		assert.Equal(t, [][]byte{[]byte("ab"), []byte("cd"), []byte("e")}, Parts([]byte("abcde"), nil, 2))
+		assert.Equal(t, [][]byte{[]byte("ab")}, Parts([]byte("ab"), nil, 2))
+		assert.Nil(t, Parts([]byte(""), nil, 2))
+	})
+}
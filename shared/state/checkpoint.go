@@ -0,0 +1,128 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// checkpointedStore keeps state in memory, and writes the keys that changed to another store every interval, so
+// changes are cheap, but up to an interval of them are lost if the process crashes.
+type checkpointedStore struct {
+	Store // the memory store
+	to    Store
+	mu    sync.Mutex
+	dirty map[string]bool // keys changed since the last checkpoint
+	stop  context.CancelFunc
+	done  chan struct{}
+}
+
+// NewCheckpointed returns a store that keeps its state in memory, and checkpoints it to the other store every
+// interval, and when it is closed. It starts with the other store's state, i.e. as of the last checkpoint. Restored keys
+// are kept until the TTL elapses again.
+func NewCheckpointed(ctx context.Context, to Store, ttl, interval time.Duration) (Store, error) {
+	s := &checkpointedStore{Store: NewMemory(ctx, ttl), to: to, dirty: map[string]bool{}, done: make(chan struct{})}
+	if err := s.restore(ctx); err != nil {
+		_ = s.Store.Close()
+		_ = to.Close()
+		return nil, err
+	}
+	ctx, s.stop = context.WithCancel(ctx)
+	go func() {
+		defer close(s.done)
+		wait.UntilWithContext(ctx, func(ctx context.Context) {
+			if err := s.checkpoint(ctx); err != nil {
+				logger.Error(err, "failed to checkpoint state")
+			}
+		}, interval)
+	}()
+	return s, nil
+}
+
+func (s *checkpointedStore) restore(ctx context.Context) error {
+	keys, err := s.to.Keys(ctx, "")
+	if err != nil {
+		return fmt.Errorf("failed to list checkpointed keys: %w", err)
+	}
+	for _, key := range keys {
+		value, err := s.to.Get(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get checkpointed key %q: %w", key, err)
+		}
+		if value != nil {
+			if err := s.Store.Set(ctx, key, value); err != nil {
+				return err
+			}
+		}
+	}
+	logger.Info("restored state from checkpoint", "keys", len(keys))
+	return nil
+}
+
+func (s *checkpointedStore) Set(ctx context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty[key] = true
+	return s.Store.Set(ctx, key, value)
+}
+
+func (s *checkpointedStore) SetIfAbsent(ctx context.Context, key string, value []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	set, err := s.Store.SetIfAbsent(ctx, key, value)
+	if set {
+		s.dirty[key] = true
+	}
+	return set, err
+}
+
+func (s *checkpointedStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dirty[key] = true
+	return s.Store.Delete(ctx, key)
+}
+
+// checkpoint writes the keys that changed since the last checkpoint. Keys that fail to be written are retried at the
+// next one.
+func (s *checkpointedStore) checkpoint(ctx context.Context) error {
+	s.mu.Lock()
+	dirty := s.dirty
+	s.dirty = map[string]bool{}
+	s.mu.Unlock()
+	var firstErr error
+	for key := range dirty {
+		value, err := s.Store.Get(ctx, key)
+		if err == nil {
+			if value == nil {
+				err = s.to.Delete(ctx, key)
+			} else {
+				err = s.to.Set(ctx, key, value)
+			}
+		}
+		if err != nil {
+			s.mu.Lock()
+			s.dirty[key] = true
+			s.mu.Unlock()
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to checkpoint key %q: %w", key, err)
+			}
+		}
+	}
+	return firstErr
+}
+
+// Close writes a final checkpoint, and closes both stores.
+func (s *checkpointedStore) Close() error {
+	s.stop()
+	<-s.done
+	err := s.checkpoint(context.Background())
+	_ = s.Store.Close()
+	if closeErr := s.to.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,63 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCheckpointed(t *testing.T) {
+	ctx := context.Background()
+	t.Run("Store", func(t *testing.T) {
+		s, err := NewCheckpointed(ctx, NewMemory(ctx, time.Minute), time.Minute, time.Hour)
+		assert.NoError(t, err)
+		testStore(t, s)
+	})
+	t.Run("Checkpoint", func(t *testing.T) {
+		to := NewMemory(ctx, time.Minute)
+		defer func() { _ = to.Close() }()
+		s, err := NewCheckpointed(ctx, to, time.Minute, time.Hour)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Set(ctx, "a", []byte("1")))
+		assert.NoError(t, s.Set(ctx, "b", []byte("2")))
+		v, err := to.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Nil(t, v, "not checkpointed yet")
+		assert.NoError(t, s.(*checkpointedStore).checkpoint(ctx))
+		v, err = to.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("1"), v)
+		assert.NoError(t, s.Delete(ctx, "a"))
+		assert.NoError(t, s.(*checkpointedStore).checkpoint(ctx))
+		v, err = to.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Nil(t, v)
+	})
+	t.Run("Restore", func(t *testing.T) {
+		to := NewMemory(ctx, time.Minute)
+		defer func() { _ = to.Close() }()
+		assert.NoError(t, to.Set(ctx, "a", []byte("1")))
+		s, err := NewCheckpointed(ctx, to, time.Minute, time.Hour)
+		assert.NoError(t, err)
+		v, err := s.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("1"), v)
+	})
+	t.Run("CheckpointOnClose", func(t *testing.T) {
+		path := t.TempDir() + "/my.db"
+		to, err := NewDisk(ctx, path, time.Minute)
+		assert.NoError(t, err)
+		s, err := NewCheckpointed(ctx, to, time.Minute, time.Hour)
+		assert.NoError(t, err)
+		assert.NoError(t, s.Set(ctx, "a", []byte("1")))
+		assert.NoError(t, s.Close())
+		to, err = NewDisk(ctx, path, time.Minute)
+		assert.NoError(t, err)
+		defer func() { _ = to.Close() }()
+		v, err := to.Get(ctx, "a")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("1"), v)
+	})
+}
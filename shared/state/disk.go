@@ -0,0 +1,137 @@
+package state
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+var bucket = []byte("state")
+
+type diskStore struct {
+	ttl    time.Duration
+	db     *bbolt.DB
+	cancel context.CancelFunc
+}
+
+// NewDisk returns a store that keeps its state in an embedded database in the file, so it survives restarts if the
+// file is on a persistent volume. Only one process can open the file at a time.
+func NewDisk(ctx context.Context, path string, ttl time.Duration) (Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create state dir: %w", err)
+	}
+	logger.Info("opening disk state store", "path", path)
+	// the timeout stops us waiting forever if another replica has the file open
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: 30 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open state database %q: %w", path, err)
+	}
+	if err := db.Update(func(tx *bbolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(bucket)
+		return err
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create state bucket: %w", err)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	s := &diskStore{ttl: ttl, db: db, cancel: cancel}
+	go wait.JitterUntil(func() {
+		if err := s.expire(time.Now()); err != nil {
+			logger.Error(err, "failed to expire state")
+		}
+	}, time.Minute, 1.2, true, ctx.Done())
+	return s, nil
+}
+
+// values are stored with the time they expire at, in Unix nanoseconds, before them
+func (s *diskStore) encode(value []byte) []byte {
+	data := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(data, uint64(time.Now().Add(s.ttl).UnixNano()))
+	copy(data[8:], value)
+	return data
+}
+
+func decode(data []byte, now time.Time) ([]byte, bool) {
+	if len(data) < 8 || int64(binary.BigEndian.Uint64(data)) <= now.UnixNano() {
+		return nil, false
+	}
+	return data[8:], true
+}
+
+func (s *diskStore) Get(_ context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		if v, ok := decode(tx.Bucket(bucket).Get([]byte(key)), time.Now()); ok {
+			// values are only valid during the transaction
+			value = append([]byte{}, v...)
+		}
+		return nil
+	})
+	return value, err
+}
+
+func (s *diskStore) Set(_ context.Context, key string, value []byte) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Put([]byte(key), s.encode(value))
+	})
+}
+
+func (s *diskStore) SetIfAbsent(_ context.Context, key string, value []byte) (bool, error) {
+	set := false
+	err := s.db.Update(func(tx *bbolt.Tx) error {
+		b := tx.Bucket(bucket)
+		if _, ok := decode(b.Get([]byte(key)), time.Now()); ok {
+			return nil
+		}
+		set = true
+		return b.Put([]byte(key), s.encode(value))
+	})
+	return set && err == nil, err
+}
+
+func (s *diskStore) Delete(_ context.Context, key string) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket(bucket).Delete([]byte(key))
+	})
+}
+
+func (s *diskStore) Keys(_ context.Context, prefix string) ([]string, error) {
+	var keys []string
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		now := time.Now()
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+			if _, ok := decode(v, now); ok {
+				keys = append(keys, string(k))
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+func (s *diskStore) expire(now time.Time) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if _, ok := decode(v, now); !ok {
+				if err := c.Delete(); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func (s *diskStore) Close() error {
+	s.cancel()
+	return s.db.Close()
+}
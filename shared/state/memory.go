@@ -0,0 +1,92 @@
+package state
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+type memoryStore struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	cancel  context.CancelFunc
+}
+
+// NewMemory returns a store that keeps its state in memory, so it is lost when the process exits.
+func NewMemory(ctx context.Context, ttl time.Duration) Store {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &memoryStore{ttl: ttl, entries: map[string]memoryEntry{}, cancel: cancel}
+	go wait.JitterUntil(func() { s.expire(time.Now()) }, time.Minute, 1.2, true, ctx.Done())
+	return s
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.entries[key]; ok && e.expiresAt.After(time.Now()) {
+		return e.value, nil
+	}
+	return nil, nil
+}
+
+func (s *memoryStore) Set(_ context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(s.ttl)}
+	return nil
+}
+
+func (s *memoryStore) SetIfAbsent(_ context.Context, key string, value []byte) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if e, ok := s.entries[key]; ok && e.expiresAt.After(now) {
+		return false, nil
+	}
+	s.entries[key] = memoryEntry{value: value, expiresAt: now.Add(s.ttl)}
+	return true, nil
+}
+
+func (s *memoryStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *memoryStore) Keys(_ context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	var keys []string
+	for k, e := range s.entries {
+		if strings.HasPrefix(k, prefix) && e.expiresAt.After(now) {
+			keys = append(keys, k)
+		}
+	}
+	return keys, nil
+}
+
+func (s *memoryStore) expire(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for k, e := range s.entries {
+		if !e.expiresAt.After(now) {
+			delete(s.entries, k)
+		}
+	}
+}
+
+func (s *memoryStore) Close() error {
+	s.cancel()
+	return nil
+}
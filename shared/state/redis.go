@@ -0,0 +1,75 @@
+package state
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+type redisStore struct {
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+}
+
+// NewRedis returns a store that keeps its state in Redis, so it survives restarts, and is shared by every process
+// using the same prefix. Values are not locked, so a read and a write by different processes may interleave, unless
+// SetIfAbsent is used.
+func NewRedis(ctx context.Context, url, password, prefix string, ttl time.Duration) (Store, error) {
+	opts, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
+	}
+	if password != "" {
+		opts.Password = password
+	}
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	return &redisStore{client: client, prefix: prefix, ttl: ttl}, nil
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	v, err := s.client.Get(ctx, s.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (s *redisStore) Set(ctx context.Context, key string, value []byte) error {
+	return s.client.Set(ctx, s.prefix+key, value, s.ttl).Err()
+}
+
+func (s *redisStore) SetIfAbsent(ctx context.Context, key string, value []byte) (bool, error) {
+	// SET NX PX, so the key is set and expires atomically
+	return s.client.SetNX(ctx, s.prefix+key, value, s.ttl).Result()
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, s.prefix+key).Err()
+}
+
+func (s *redisStore) Keys(ctx context.Context, prefix string) ([]string, error) {
+	var keys []string
+	iter := s.client.Scan(ctx, 0, globEscape(s.prefix+prefix)+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, strings.TrimPrefix(iter.Val(), s.prefix))
+	}
+	return keys, iter.Err()
+}
+
+// globEscape escapes the characters that are special in Redis glob-style patterns.
+func globEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+	return r.Replace(s)
+}
+
+func (s *redisStore) Close() error {
+	return s.client.Close()
+}
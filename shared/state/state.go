@@ -0,0 +1,80 @@
+// Package state is the state API for stateful built-in processors, such as dedupe and join. Each processor keeps its
+// state in a Store, whose backend is configured by the step's State.
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+)
+
+var logger = sharedutil.NewLogger()
+
+// Store is a key-value store. Keys expire once the state's TTL has elapsed since they were last set.
+type Store interface {
+	// Get returns the key's value, or nil if it is not set.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set sets the key's value.
+	Set(ctx context.Context, key string, value []byte) error
+	// SetIfAbsent atomically sets the key's value, unless it is already set, returning true if it set it. Processes
+	// sharing a store can use it to agree which of them saw a key first.
+	SetIfAbsent(ctx context.Context, key string, value []byte) (bool, error)
+	// Delete deletes the key, if it is set.
+	Delete(ctx context.Context, key string) error
+	// Keys returns the keys that start with the prefix.
+	Keys(ctx context.Context, prefix string) ([]string, error)
+	Close() error
+}
+
+// FromEnv returns the state configured by the environment the controller sets, which is empty if there is none.
+func FromEnv() (*dfv1.State, error) {
+	x := &dfv1.State{}
+	if v := os.Getenv(dfv1.EnvState); v != "" {
+		if err := json.Unmarshal([]byte(v), x); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", dfv1.EnvState, err)
+		}
+	}
+	return x, nil
+}
+
+// NewFromEnv returns a store for the processor, e.g. "dedupe", configured by the environment the controller sets, see
+// State. Without any configuration, the store is in memory. If the state is checkpointed, the store is in memory, and
+// checkpointed to the configured store.
+func NewFromEnv(ctx context.Context, processor string) (Store, error) {
+	x, err := FromEnv()
+	if err != nil {
+		return nil, err
+	}
+	store, err := Open(ctx, x, processor)
+	if err != nil {
+		return nil, err
+	}
+	if interval := x.GetCheckpointInterval(); interval > 0 && x.IsPersistent() {
+		return NewCheckpointed(ctx, store, x.GetTTL(), interval)
+	}
+	return store, nil
+}
+
+// Open returns a store for the processor, in the state's backend, without checkpointing. A Redis URL and password
+// that are not in the state are read from the environment.
+func Open(ctx context.Context, x *dfv1.State, processor string) (Store, error) {
+	ttl := x.GetTTL()
+	switch {
+	case x != nil && x.Disk != nil:
+		return NewDisk(ctx, filepath.Join(dfv1.PathState, processor+".db"), ttl)
+	case x != nil && x.Redis != nil:
+		url := dfv1.StringOr(x.Redis.URL, os.Getenv(dfv1.EnvStateRedisURL))
+		if url == "" {
+			return nil, fmt.Errorf("redis state store URL must be specified, or in the secret")
+		}
+		prefix := os.Getenv(dfv1.EnvStateKeyPrefix) + processor + ":"
+		return NewRedis(ctx, url, os.Getenv(dfv1.EnvStateRedisPassword), prefix, ttl)
+	default:
+		return NewMemory(ctx, ttl), nil
+	}
+}
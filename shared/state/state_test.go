@@ -0,0 +1,108 @@
+package state
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStore(t *testing.T, s Store) {
+	ctx := context.Background()
+	v, err := s.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	assert.NoError(t, s.Set(ctx, "a/1", []byte("1")))
+	assert.NoError(t, s.Set(ctx, "a/2", []byte("2")))
+	assert.NoError(t, s.Set(ctx, "b/1", []byte("3")))
+	v, err = s.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), v)
+	keys, err := s.Keys(ctx, "a/")
+	assert.NoError(t, err)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a/1", "a/2"}, keys)
+	assert.NoError(t, s.Delete(ctx, "a/1"))
+	v, err = s.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Nil(t, v)
+	set, err := s.SetIfAbsent(ctx, "a/1", []byte("4"))
+	assert.NoError(t, err)
+	assert.True(t, set)
+	set, err = s.SetIfAbsent(ctx, "a/1", []byte("5"))
+	assert.NoError(t, err)
+	assert.False(t, set, "already set")
+	v, err = s.Get(ctx, "a/1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("4"), v)
+	assert.NoError(t, s.Close())
+}
+
+func TestNewMemory(t *testing.T) {
+	t.Run("Store", func(t *testing.T) {
+		testStore(t, NewMemory(context.Background(), time.Minute))
+	})
+	t.Run("Expire", func(t *testing.T) {
+		s := NewMemory(context.Background(), time.Minute).(*memoryStore)
+		defer func() { _ = s.Close() }()
+		assert.NoError(t, s.Set(context.Background(), "a", []byte("1")))
+		s.expire(time.Now().Add(time.Minute))
+		assert.Empty(t, s.entries)
+	})
+}
+
+func TestNewDisk(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "my.db")
+	t.Run("Store", func(t *testing.T) {
+		s, err := NewDisk(context.Background(), path, time.Minute)
+		assert.NoError(t, err)
+		testStore(t, s)
+	})
+	t.Run("SurvivesRestart", func(t *testing.T) {
+		s, err := NewDisk(context.Background(), path, time.Minute)
+		assert.NoError(t, err)
+		v, err := s.Get(context.Background(), "a/2")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("2"), v)
+		assert.NoError(t, s.Close())
+	})
+	t.Run("Expire", func(t *testing.T) {
+		s, err := NewDisk(context.Background(), path, time.Minute)
+		assert.NoError(t, err)
+		defer func() { _ = s.Close() }()
+		assert.NoError(t, s.(*diskStore).expire(time.Now().Add(time.Minute)))
+		keys, err := s.Keys(context.Background(), "")
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
+
+func TestNewFromEnv(t *testing.T) {
+	t.Run("Default", func(t *testing.T) {
+		s, err := NewFromEnv(context.Background(), "dedupe")
+		assert.NoError(t, err)
+		assert.IsType(t, &memoryStore{}, s)
+		assert.Equal(t, 24*time.Hour, s.(*memoryStore).ttl)
+		assert.NoError(t, s.Close())
+	})
+	t.Run("Redis", func(t *testing.T) {
+		t.Setenv(dfv1.EnvState, `{"redis":{}}`)
+		_, err := NewFromEnv(context.Background(), "dedupe")
+		assert.EqualError(t, err, "redis state store URL must be specified, or in the secret")
+	})
+}
+
+func TestOpen(t *testing.T) {
+	s, err := Open(context.Background(), nil, "dedupe")
+	assert.NoError(t, err)
+	assert.IsType(t, &memoryStore{}, s)
+	assert.NoError(t, s.Close())
+}
+
+func Test_globEscape(t *testing.T) {
+	assert.Equal(t, `a\*b\?\[c\]\\`, globEscape(`a*b?[c]\`))
+}
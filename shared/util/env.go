@@ -19,6 +19,17 @@ func GetEnvDuration(key string, def time.Duration) time.Duration {
 	return def
 }
 
+func GetEnvBool(key string, def bool) bool {
+	if x, ok := os.LookupEnv(key); ok {
+		if v, err := strconv.ParseBool(x); err != nil {
+			panic(fmt.Errorf("%s=%s; value must be bool: %w", key, x, err))
+		} else {
+			return v
+		}
+	}
+	return def
+}
+
 func GetEnvInt(key string, def int) int {
 	if x, ok := os.LookupEnv(key); ok {
 		if v, err := strconv.Atoi(x); err != nil {
@@ -36,3 +47,10 @@ func GetEnvStringArr(key string, def []string) []string {
 	}
 	return def
 }
+
+func GetEnvString(key string, def string) string {
+	if x, ok := os.LookupEnv(key); ok {
+		return x
+	}
+	return def
+}
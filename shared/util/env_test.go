@@ -19,3 +19,22 @@ func Test_GetEnvDuration(t *testing.T) {
 		_ = GetEnvDuration("FOO", 0)
 	})
 }
+
+func Test_GetEnvBool(t *testing.T) {
+	defer os.Unsetenv("FOO")
+	assert.False(t, GetEnvBool("FOO", false))
+	_ = os.Setenv("FOO", "true")
+	assert.True(t, GetEnvBool("FOO", false))
+	_ = os.Setenv("FOO", "xx")
+
+	assert.Panics(t, func() {
+		_ = GetEnvBool("FOO", false)
+	})
+}
+
+func Test_GetEnvString(t *testing.T) {
+	defer os.Unsetenv("FOO")
+	assert.Equal(t, "bar", GetEnvString("FOO", "bar"))
+	_ = os.Setenv("FOO", "baz")
+	assert.Equal(t, "baz", GetEnvString("FOO", "bar"))
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// replyTimeout is how long to wait for the command to reply to a message. It is shorter than the sidecar's timeout, so
+// the command is restarted before the sidecar gives up on the message.
+const replyTimeout = 8 * time.Second
+
+// stdio runs the command, which reads messages from its standard input, one per line, and writes a reply to each of
+// them to its standard output, also one per line. It serves the messages using the HTTP interface the sidecar uses.
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatal("usage: stdio COMMAND [ARG...]")
+	}
+	os.Exit(mainE(os.Args[1], os.Args[2:]...))
+}
+
+func mainE(name string, args ...string) int {
+	exited := make(chan error, 1)
+	start := func() (*child, error) {
+		cmd := exec.Command(name, args...)
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, err
+		}
+		c := newChild(stdin, stdout, func(sig os.Signal) { _ = cmd.Process.Signal(sig) })
+		go func() {
+			err := cmd.Wait()
+			// a command that is restarted, because it did not reply in time, is not the command exiting
+			if !c.isRestarted() {
+				exited <- err
+			}
+		}()
+		return c, nil
+	}
+	h, err := newHandler(start, replyTimeout)
+	if err != nil {
+		log.Fatal(err)
+	}
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("stopping command on %v\n", sig)
+		h.stop()
+	}()
+	http.HandleFunc("/ready", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(204)
+	})
+	http.Handle("/messages", h)
+	go func() {
+		log.Fatal(http.ListenAndServe(":8080", nil))
+	}()
+	if err := <-exited; err != nil {
+		log.Printf("command failed: %v\n", err)
+		if x, ok := err.(*exec.ExitError); ok {
+			return x.ExitCode()
+		}
+		return 1
+	}
+	return 0
+}
+
+// child is a running instance of the command.
+type child struct {
+	stdin     io.WriteCloser
+	replies   chan []byte // each line the command writes, closed once its output is closed
+	signal    func(os.Signal)
+	restarted int32
+}
+
+func newChild(stdin io.WriteCloser, stdout io.Reader, signal func(os.Signal)) *child {
+	c := &child{stdin: stdin, replies: make(chan []byte), signal: signal}
+	go func() {
+		defer close(c.replies)
+		r := bufio.NewReader(stdout)
+		for {
+			line, err := r.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			c.replies <- bytes.TrimSuffix(line, []byte("\n"))
+		}
+	}()
+	return c
+}
+
+func (c *child) isRestarted() bool { return atomic.LoadInt32(&c.restarted) == 1 }
+
+// kill kills the command, and discards anything else it writes.
+func (c *child) kill() {
+	atomic.StoreInt32(&c.restarted, 1)
+	_ = c.stdin.Close()
+	c.signal(syscall.SIGKILL)
+	go func() {
+		for range c.replies {
+		}
+	}()
+}
+
+type handler struct {
+	mu      sync.Mutex // only one message can be in the command at once, so its reply is the next line
+	start   func() (*child, error)
+	timeout time.Duration
+	child   *child
+}
+
+func newHandler(start func() (*child, error), timeout time.Duration) (*handler, error) {
+	c, err := start()
+	if err != nil {
+		return nil, err
+	}
+	return &handler{start: start, timeout: timeout, child: c}, nil
+}
+
+// stop closes the command's standard input, as most filters exit at the end of their input, and sends it SIGTERM.
+func (h *handler) stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_ = h.child.stdin.Close()
+	h.child.signal(syscall.SIGTERM)
+}
+
+// restart kills the command, which has not replied to a message, and starts a new one, so that its late reply is not
+// taken to be the reply to the next message.
+func (h *handler) restart(reason string) error {
+	log.Printf("restarting command: %s\n", reason)
+	h.child.kill()
+	c, err := h.start()
+	if err != nil {
+		return fmt.Errorf("failed to restart command: %w", err)
+	}
+	h.child = c
+	return nil
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	if bytes.ContainsRune(data, '\n') {
+		w.WriteHeader(400)
+		_, _ = w.Write([]byte("messages must not contain new lines"))
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, err := h.child.stdin.Write(append(data, '\n')); err != nil {
+		w.WriteHeader(500)
+		_, _ = w.Write([]byte(err.Error()))
+		return
+	}
+	timer := time.NewTimer(h.timeout)
+	defer timer.Stop()
+	var reason string
+	select {
+	case line, ok := <-h.child.replies:
+		if !ok {
+			w.WriteHeader(500)
+			_, _ = w.Write([]byte("command closed its output"))
+			return
+		}
+		if len(line) == 0 {
+			w.WriteHeader(204) // no reply
+			return
+		}
+		w.WriteHeader(201)
+		_, _ = w.Write(line)
+		return
+	case <-timer.C:
+		reason = fmt.Sprintf("no reply within %v", h.timeout)
+	case <-r.Context().Done():
+		reason = fmt.Sprintf("request cancelled while waiting for reply: %v", r.Context().Err())
+	}
+	if err := h.restart(reason); err != nil {
+		log.Fatal(err)
+	}
+	w.WriteHeader(500)
+	_, _ = w.Write([]byte(reason))
+}
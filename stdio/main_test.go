@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// startFilter starts a filter that upper-cases each line, replies to "skip" with an empty line, and does not reply to
+// "hang".
+func startFilter() (*child, error) {
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go func() {
+		scanner := bufio.NewScanner(inR)
+		for scanner.Scan() {
+			reply := strings.ToUpper(scanner.Text())
+			switch reply {
+			case "HANG":
+				continue
+			case "SKIP":
+				reply = ""
+			}
+			_, _ = outW.Write([]byte(reply + "\n"))
+		}
+	}()
+	return newChild(inW, outR, func(os.Signal) {
+		_ = inR.Close()
+		_ = outW.Close()
+	}), nil
+}
+
+func Test_handler(t *testing.T) {
+	starts := 0
+	h, err := newHandler(func() (*child, error) {
+		starts++
+		return startFilter()
+	}, 100*time.Millisecond)
+	assert.NoError(t, err)
+	post := func(msg string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/messages", bytes.NewBufferString(msg)))
+		return w
+	}
+	w := post("hello")
+	assert.Equal(t, 201, w.Code)
+	assert.Equal(t, "HELLO", w.Body.String())
+	assert.Equal(t, 204, post("skip").Code)
+	w = post("a\nb")
+	assert.Equal(t, 400, w.Code)
+	assert.Equal(t, "messages must not contain new lines", w.Body.String())
+	t.Run("Timeout", func(t *testing.T) {
+		w := post("hang")
+		assert.Equal(t, 500, w.Code)
+		assert.Equal(t, "no reply within 100ms", w.Body.String())
+		assert.Equal(t, 2, starts, "the command is restarted")
+		w = post("hello")
+		assert.Equal(t, 201, w.Code)
+		assert.Equal(t, "HELLO", w.Body.String())
+	})
+}
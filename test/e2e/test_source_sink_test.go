@@ -0,0 +1,45 @@
+//go:build test
+// +build test
+
+package e2e
+
+import (
+	"testing"
+
+	. "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	. "github.com/argoproj-labs/argo-dataflow/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestTestSourceAndSink(t *testing.T) {
+	defer Setup(t)()
+
+	CreatePipeline(Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-kinds"},
+		Spec: PipelineSpec{
+			Steps: []StepSpec{
+				{
+					Name:    "main",
+					Map:     &Map{Expression: "bytes(string(msg) + '!')"},
+					Sources: []Source{{Test: &TestSource{}}},
+					Sinks:   []Sink{{Name: "test", Test: &TestSink{}}},
+				},
+			},
+		},
+	})
+
+	WaitForPod()
+
+	defer StartPortForward("test-kinds-main-0")()
+
+	SendMessageViaTestSource("foo-bar")
+
+	WaitForSunkMessages()
+	WaitForTotalSunkMessages(1)
+
+	assert.Equal(t, []string{"foo-bar!"}, GetTestSinkMessages())
+
+	DeletePipelines()
+	WaitForPodsToBeDeleted()
+}
@@ -13,6 +13,18 @@ import (
 //go:generate kubectl -n argo-dataflow-system apply -f ../../config/apps/kafka.yaml
 //go:generate kubectl -n argo-dataflow-system apply -f ../../config/apps/stan.yaml
 
+func Test_101_generator_pipeline(t *testing.T) {
+	defer Setup(t)()
+
+	CreatePipelineFromFile("../../examples/101-generator-pipeline.yaml")
+
+	WaitForPipeline()
+	WaitForPipeline(UntilRunning, 90*time.Second)
+
+	DeletePipelines()
+	WaitForPodsToBeDeleted()
+}
+
 func Test_101_hello_pipeline(t *testing.T) {
 	defer Setup(t)()
 
@@ -73,6 +85,18 @@ func Test_102_flatten_expand_pipeline(t *testing.T) {
 	WaitForPodsToBeDeleted()
 }
 
+func Test_102_join_pipeline(t *testing.T) {
+	defer Setup(t)()
+
+	CreatePipelineFromFile("../../examples/102-join-pipeline.yaml")
+
+	WaitForPipeline()
+	WaitForPipeline(UntilRunning, 90*time.Second)
+
+	DeletePipelines()
+	WaitForPodsToBeDeleted()
+}
+
 func Test_102_map_pipeline(t *testing.T) {
 	defer Setup(t)()
 
@@ -85,6 +109,30 @@ func Test_102_map_pipeline(t *testing.T) {
 	WaitForPodsToBeDeleted()
 }
 
+func Test_102_sample_pipeline(t *testing.T) {
+	defer Setup(t)()
+
+	CreatePipelineFromFile("../../examples/102-sample-pipeline.yaml")
+
+	WaitForPipeline()
+	WaitForPipeline(UntilRunning, 90*time.Second)
+
+	DeletePipelines()
+	WaitForPodsToBeDeleted()
+}
+
+func Test_102_split_pipeline(t *testing.T) {
+	defer Setup(t)()
+
+	CreatePipelineFromFile("../../examples/102-split-pipeline.yaml")
+
+	WaitForPipeline()
+	WaitForPipeline(UntilRunning, 90*time.Second)
+
+	DeletePipelines()
+	WaitForPodsToBeDeleted()
+}
+
 func Test_103_autoscaling_pipeline(t *testing.T) {
 	defer Setup(t)()
 
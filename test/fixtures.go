@@ -4,10 +4,11 @@
 package test
 
 import (
+	"fmt"
 	"log"
 	"testing"
 
-	"k8s.io/client-go/dynamic"
+	"github.com/argoproj-labs/argo-dataflow/test/framework"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -19,8 +20,8 @@ const (
 
 var (
 	restConfig             = ctrl.GetConfigOrDie()
-	dynamicInterface       = dynamic.NewForConfigOrDie(restConfig)
 	kubernetesInterface    = kubernetes.NewForConfigOrDie(restConfig)
+	fw                     = framework.New(panicT{}, framework.WithNamespace(namespace), framework.WithRestConfig(restConfig))
 	stopTestAPIPortForward func()
 )
 
@@ -28,6 +29,19 @@ func init() {
 	log.Default().SetFlags(log.Ltime)
 }
 
+// panicT panics on failure, so that these helpers can be called without a testing.T, and the panic is recovered by
+// the teardown func returned by Setup.
+type panicT struct{}
+
+func (panicT) Helper() {}
+
+func (panicT) Logf(format string, args ...interface{}) { log.Printf(format+"\n", args...) }
+
+func (panicT) Fatalf(format string, args ...interface{}) { panic(fmt.Errorf(format, args...)) }
+
+// pipelines and port-forwards are cleaned up by Setup and the callers
+func (panicT) Cleanup(func()) {}
+
 func Setup(t *testing.T) (teardown func()) {
 	log.Printf("\n")
 	DeletePipelines()
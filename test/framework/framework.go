@@ -0,0 +1,85 @@
+// Package framework helps you write integration tests for your own pipelines, running against a real cluster (e.g.
+// kind) with Argo Dataflow installed.
+//
+//	func TestMyPipeline(t *testing.T) {
+//		f := framework.New(t, framework.WithNamespace("my-namespace"))
+//		name := f.CreatePipelineFromFile("my-pipeline.yaml")
+//		f.WaitForPipeline(name, framework.UntilRunning, time.Minute)
+//		f.StartPortForward(f.WaitForStepPod(name, "main", time.Minute), 3569)
+//		f.ExpectMetric(3569, "sinks_total", framework.Gt(0), time.Minute)
+//	}
+//
+// Failures are reported using T.Fatalf, so a test stops at the first failure.
+package framework
+
+import (
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// T is the subset of testing.TB used by the framework. Fatalf must not return, e.g. it calls runtime.Goexit or panics.
+type T interface {
+	Helper()
+	Logf(format string, args ...interface{})
+	Fatalf(format string, args ...interface{})
+	Cleanup(func())
+}
+
+type Framework struct {
+	t                   T
+	namespace           string
+	restConfig          *rest.Config
+	kubernetesInterface kubernetes.Interface
+	dynamicInterface    dynamic.Interface
+}
+
+type Option func(*Framework)
+
+// WithNamespace sets the namespace the pipelines run in, by default "default".
+func WithNamespace(namespace string) Option {
+	return func(f *Framework) { f.namespace = namespace }
+}
+
+// WithRestConfig sets the config used to connect to the cluster, by default the same config as kubectl.
+func WithRestConfig(restConfig *rest.Config) Option {
+	return func(f *Framework) { f.restConfig = restConfig }
+}
+
+func New(t T, opts ...Option) *Framework {
+	t.Helper()
+	f := &Framework{t: t, namespace: "default"}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if f.restConfig == nil {
+		restConfig, err := ctrl.GetConfig()
+		if err != nil {
+			t.Fatalf("failed to get rest config: %v", err)
+		}
+		f.restConfig = restConfig
+	}
+	var err error
+	if f.kubernetesInterface, err = kubernetes.NewForConfig(f.restConfig); err != nil {
+		t.Fatalf("failed to create Kubernetes client: %v", err)
+	}
+	if f.dynamicInterface, err = dynamic.NewForConfig(f.restConfig); err != nil {
+		t.Fatalf("failed to create dynamic client: %v", err)
+	}
+	return f
+}
+
+func (f *Framework) Namespace() string { return f.namespace }
+
+func (f *Framework) KubernetesInterface() kubernetes.Interface { return f.kubernetesInterface }
+
+func (f *Framework) pipelineInterface() dynamic.ResourceInterface {
+	return f.dynamicInterface.Resource(dfv1.PipelineGroupVersionResource).Namespace(f.namespace)
+}
+
+func (f *Framework) stepInterface() dynamic.ResourceInterface {
+	return f.dynamicInterface.Resource(dfv1.StepGroupVersionResource).Namespace(f.namespace)
+}
@@ -0,0 +1,137 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+type fatal string
+
+type fakeT struct {
+	cleanups []func()
+}
+
+func (*fakeT) Helper()                                   {}
+func (*fakeT) Logf(string, ...interface{})               {}
+func (*fakeT) Fatalf(format string, args ...interface{}) { panic(fatal(fmt.Sprintf(format, args...))) }
+func (t *fakeT) Cleanup(f func())                        { t.cleanups = append(t.cleanups, f) }
+
+func (t *fakeT) cleanup() {
+	for _, f := range t.cleanups {
+		f()
+	}
+}
+
+func expectFatal(t *testing.T, f func()) (msg string) {
+	defer func() {
+		r := recover()
+		if assert.IsType(t, fatal(""), r) {
+			msg = string(r.(fatal))
+		}
+	}()
+	f()
+	return ""
+}
+
+func newFake(t T, objects ...runtime.Object) *Framework {
+	return &Framework{
+		t:                   t,
+		namespace:           "my-ns",
+		kubernetesInterface: fake.NewSimpleClientset(objects...),
+		dynamicInterface: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			dfv1.PipelineGroupVersionResource: "PipelineList",
+			dfv1.StepGroupVersionResource:     "StepList",
+		}),
+	}
+}
+
+func TestFramework_Pipelines(t *testing.T) {
+	ft := &fakeT{}
+	f := newFake(ft)
+	name := f.CreatePipeline(dfv1.Pipeline{
+		ObjectMeta: metav1.ObjectMeta{Name: "my-pl"},
+		Spec:       dfv1.PipelineSpec{Steps: []dfv1.StepSpec{{Name: "main"}}},
+	})
+	assert.Equal(t, "my-pl", name)
+	pl := f.GetPipeline(name)
+	assert.Equal(t, "main", pl.Spec.Steps[0].Name)
+	assert.Len(t, f.ListPipelines(), 1)
+	assert.Contains(t, expectFatal(t, func() { f.GetPipeline("not-found") }), `failed to get pipeline "not-found"`)
+	assert.Contains(t, expectFatal(t, func() { f.WaitForPipeline(name, UntilRunning, time.Millisecond) }), "context deadline exceeded")
+	ft.cleanup()
+	assert.Empty(t, f.ListPipelines())
+}
+
+func TestFramework_GetAuthorization(t *testing.T) {
+	f := newFake(&fakeT{}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-main"},
+		Data: map[string][]byte{
+			"sources.default.http.authorization": []byte("Bearer my-token"),
+			"sinks.default.test.authorization":   []byte("Bearer my-sink-token"),
+		},
+	})
+	assert.Equal(t, "Bearer my-token", f.GetAuthorization("my-pl", "main", "default"))
+	assert.Equal(t, `source "other" not found in secret "my-pl-main"`, expectFatal(t, func() { f.GetAuthorization("my-pl", "main", "other") }))
+	assert.Equal(t, "Bearer my-sink-token", f.GetSinkAuthorization("my-pl", "main", "default"))
+	assert.Equal(t, `sink "other" not found in secret "my-pl-main"`, expectFatal(t, func() { f.GetSinkAuthorization("my-pl", "main", "other") }))
+}
+
+func TestFramework_GetTestSinkMessages(t *testing.T) {
+	f := newFake(&fakeT{}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "my-ns", Name: "my-pl-main"},
+		Data:       map[string][]byte{"sinks.default.test.authorization": []byte("Bearer my-token")},
+	})
+	body := ""
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/test/sinks/default" || r.Header.Get("Authorization") != "Bearer my-token" {
+			w.WriteHeader(403)
+		} else if body == "" {
+			w.WriteHeader(204)
+		} else {
+			_, _ = w.Write([]byte(body))
+		}
+	}))
+	defer server.Close()
+	u, _ := url.Parse(server.URL)
+	port, _ := strconv.Atoi(u.Port())
+	assert.Empty(t, f.GetTestSinkMessages("my-pl", "main", "default", port))
+	body = "my-msg-0\nmy-msg-1\n"
+	assert.Equal(t, []string{"my-msg-0", "my-msg-1"}, f.GetTestSinkMessages("my-pl", "main", "default", port))
+}
+
+func TestMatcher(t *testing.T) {
+	m := Gt(1)
+	assert.Equal(t, "gt 1", m.String())
+	ok, err := m.match(2)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	m = Eq(1)
+	for i := 0; i < 10; i++ {
+		ok, err = m.match(0)
+	}
+	assert.EqualError(t, err, "stalled at 0")
+	assert.False(t, ok)
+	assert.True(t, Missing().test(missing))
+}
+
+func TestPodRunningAndReady(t *testing.T) {
+	assert.False(t, PodRunningAndReady(&corev1.Pod{}))
+	assert.True(t, PodRunningAndReady(&corev1.Pod{Status: corev1.PodStatus{
+		Phase:      corev1.PodRunning,
+		Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}},
+	}}))
+}
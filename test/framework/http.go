@@ -0,0 +1,112 @@
+package framework
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetAuthorization returns the bearer token that must be presented to send messages to the step's source.
+func (f *Framework) GetAuthorization(pipelineName, stepName, sourceName string) string {
+	f.t.Helper()
+	return f.getAuthorization(pipelineName, stepName, "source", fmt.Sprintf("sources.%s.http.authorization", sourceName), sourceName)
+}
+
+// GetSinkAuthorization returns the bearer token that must be presented to read the messages of the step's test sink.
+func (f *Framework) GetSinkAuthorization(pipelineName, stepName, sinkName string) string {
+	f.t.Helper()
+	return f.getAuthorization(pipelineName, stepName, "sink", fmt.Sprintf("sinks.%s.test.authorization", sinkName), sinkName)
+}
+
+func (f *Framework) getAuthorization(pipelineName, stepName, kind, key, name string) string {
+	f.t.Helper()
+	secretName := fmt.Sprintf("%s-%s", pipelineName, stepName)
+	secret, err := f.kubernetesInterface.CoreV1().Secrets(f.namespace).Get(context.Background(), secretName, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatalf("failed to get secret %q: %v", secretName, err)
+	}
+	data, ok := secret.Data[key]
+	if !ok {
+		f.t.Fatalf("%s %q not found in secret %q", kind, name, secretName)
+	}
+	return string(data)
+}
+
+// SendMessageViaHTTP sends the message to the step's HTTP source, served on localhost on the port (typically
+// port-forwarded to a sidecar's 3569).
+func (f *Framework) SendMessageViaHTTP(pipelineName, stepName, sourceName string, port int, msg string) {
+	f.t.Helper()
+	authorization := f.GetAuthorization(pipelineName, stepName, sourceName)
+	status, body := f.post(http.DefaultClient, fmt.Sprintf("http://localhost:%d/sources/%s", port, sourceName), authorization, msg)
+	if status != 204 {
+		f.t.Fatalf("failed to send message: %d %q", status, body)
+	}
+}
+
+// InjectMessage processes the message as if it came from the step's source, and returns the messages sent to the
+// sinks as a result, each followed by a new line. The sidecar's HTTPS port 3570 must be forwarded to the port.
+func (f *Framework) InjectMessage(pipelineName, stepName, sourceName string, port int, msg string) string {
+	f.t.Helper()
+	authorization := f.GetAuthorization(pipelineName, stepName, sourceName)
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{InsecureSkipVerify: true}}}
+	status, body := f.post(client, fmt.Sprintf("https://localhost:%d/inject?source=%s", port, url.QueryEscape(sourceName)), authorization, msg)
+	if status != 200 && status != 204 {
+		f.t.Fatalf("failed to inject message: %d %q", status, body)
+	}
+	return body
+}
+
+// SendMessageViaTestSource adds the message to the queue of the step's test source, served on localhost on the port
+// (typically port-forwarded to a sidecar's 3569).
+func (f *Framework) SendMessageViaTestSource(pipelineName, stepName, sourceName string, port int, msg string) {
+	f.t.Helper()
+	authorization := f.GetAuthorization(pipelineName, stepName, sourceName)
+	status, body := f.post(http.DefaultClient, fmt.Sprintf("http://localhost:%d/test/sources/%s", port, sourceName), authorization, msg)
+	if status != 204 {
+		f.t.Fatalf("failed to send message: %d %q", status, body)
+	}
+}
+
+// GetTestSinkMessages returns the messages written to the step's test sink, oldest first, served on localhost on the
+// port (typically port-forwarded to a sidecar's 3569). Each replica has its own messages.
+func (f *Framework) GetTestSinkMessages(pipelineName, stepName, sinkName string, port int) []string {
+	f.t.Helper()
+	authorization := f.GetSinkAuthorization(pipelineName, stepName, sinkName)
+	status, body := f.do(http.DefaultClient, "GET", fmt.Sprintf("http://localhost:%d/test/sinks/%s", port, sinkName), authorization, "")
+	switch status {
+	case 200:
+		return strings.Split(strings.TrimSuffix(body, "\n"), "\n")
+	case 204:
+		return nil
+	}
+	f.t.Fatalf("failed to get messages: %d %q", status, body)
+	return nil
+}
+
+func (f *Framework) post(client *http.Client, url, authorization, msg string) (int, string) {
+	f.t.Helper()
+	return f.do(client, "POST", url, authorization, msg)
+}
+
+func (f *Framework) do(client *http.Client, method, url, authorization, msg string) (int, string) {
+	f.t.Helper()
+	req, err := http.NewRequest(method, url, bytes.NewBufferString(msg))
+	if err != nil {
+		f.t.Fatalf("failed to create request: %v", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	resp, err := client.Do(req)
+	if err != nil {
+		f.t.Fatalf("failed to %s %q: %v", strings.ToLower(method), url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := ioutil.ReadAll(resp.Body)
+	return resp.StatusCode, string(body)
+}
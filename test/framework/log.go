@@ -0,0 +1,101 @@
+package framework
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"regexp"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LogMatches returns a func that returns true if the log line matches the regular expression.
+func LogMatches(pattern string) func([]byte) bool {
+	exp := regexp.MustCompile(pattern)
+	return func(bytes []byte) bool {
+		return exp.Match(bytes)
+	}
+}
+
+// ExpectLogLine waits for a line of the sidecar log of any running pod of the step to match. If the pipeline name is
+// empty, pods of steps with that name in any pipeline are considered.
+func (f *Framework) ExpectLogLine(pipelineName, stepName string, match func([]byte) bool, timeout time.Duration) {
+	f.t.Helper()
+	containerName := "sidecar"
+	f.t.Logf("expect step %q container %q to match %q", stepName, containerName, sharedutil.GetFuncName(match))
+	labelSelector := fmt.Sprintf("%s=%s", dfv1.KeyStepName, stepName)
+	if pipelineName != "" {
+		labelSelector += fmt.Sprintf(",%s=%s", dfv1.KeyPipelineName, pipelineName)
+	}
+	ctx := context.Background()
+	podList, err := f.podInterface().List(ctx, metav1.ListOptions{LabelSelector: labelSelector, FieldSelector: "status.phase=Running"})
+	if err != nil {
+		f.t.Fatalf("error getting step pods: %v", err)
+	}
+	if !f.podsLogMatches(ctx, podList, containerName, match, timeout) {
+		f.t.Fatalf("no log lines matched %q", sharedutil.GetFuncName(match))
+	}
+}
+
+func (f *Framework) podsLogMatches(ctx context.Context, podList *corev1.PodList, containerName string, match func([]byte) bool, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	errChan := make(chan error)
+	resultChan := make(chan bool)
+	for _, p := range podList.Items {
+		for _, s := range p.Status.ContainerStatuses {
+			if s.Name == containerName && s.State.Running != nil {
+				go func(podName string) {
+					contains, err := f.podLogContains(ctx, podName, containerName, match)
+					if err != nil {
+						select {
+						case errChan <- err:
+						case <-ctx.Done():
+						}
+						return
+					}
+					if contains {
+						select {
+						case resultChan <- true:
+						case <-ctx.Done():
+						}
+					}
+				}(p.Name)
+			}
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case <-resultChan:
+			return true
+		case err := <-errChan:
+			f.t.Logf("error: %v", err)
+		}
+	}
+}
+
+func (f *Framework) podLogContains(ctx context.Context, podName, containerName string, match func([]byte) bool) (bool, error) {
+	stream, err := f.podInterface().GetLogs(podName, &corev1.PodLogOptions{Container: containerName, Follow: true, Timestamps: true}).Stream(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = stream.Close() }()
+
+	s := bufio.NewScanner(stream)
+	for s.Scan() {
+		if match(s.Bytes()) {
+			return true, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return false, nil
+	}
+	return false, s.Err()
+}
@@ -0,0 +1,123 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	io_prometheus_client "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+var missing = rand.Int()
+
+type Matcher struct {
+	string
+	stalls int
+	last   int
+	test   func(w int) bool
+}
+
+func (m *Matcher) String() string { return m.string }
+
+// match returns an error if the same value has been seen too many times in a row, as we're unlikely to ever match.
+func (m *Matcher) match(v int) (bool, error) {
+	if v == m.last {
+		m.stalls++
+	} else {
+		m.stalls = 0
+	}
+	m.last = v
+	if m.stalls >= 10 {
+		return false, fmt.Errorf("stalled at %d", v)
+	}
+	return m.test(v), nil
+}
+
+func Eq(v int) *Matcher {
+	return &Matcher{string: fmt.Sprintf("eq %v", v), test: func(w int) bool { return w == v }}
+}
+
+func Gt(v int) *Matcher {
+	return &Matcher{string: fmt.Sprintf("gt %v", v), test: func(w int) bool { return w > v }}
+}
+
+// Missing matches if the metric is not reported at all.
+func Missing() *Matcher {
+	return &Matcher{string: "missing", test: func(w int) bool { return w == missing }}
+}
+
+// ExpectMetric waits for any series of the named metric, read from localhost on the port (typically port-forwarded
+// to a sidecar's 3569), to match.
+func (f *Framework) ExpectMetric(port int, name string, matcher *Matcher, timeout time.Duration) {
+	f.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	f.t.Logf("expect metric %q to be %s within %v", name, matcher, timeout)
+	for {
+		families, err := getMetrics(ctx, port)
+		if err != nil {
+			f.t.Fatalf("failed to get metrics: %v", err)
+		}
+		found := false
+		for n, family := range families {
+			if n == name {
+				found = true
+				for _, m := range family.Metric {
+					v, err := getValue(m)
+					if err != nil {
+						f.t.Fatalf("metric %q: %v", name, err)
+					}
+					if ok, err := matcher.match(v); err != nil {
+						f.t.Fatalf("failed to wait for metric named %q to be %s: %v", name, matcher, err)
+					} else if ok {
+						return
+					}
+					f.t.Logf("%s=%v, !%s", name, v, matcher)
+				}
+			}
+		}
+		if !found && matcher.test(missing) {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			f.t.Fatalf("failed to wait for metric named %q to be %s: %v", name, matcher, ctx.Err())
+			return
+		case <-time.After(2 * time.Second):
+		}
+	}
+}
+
+func getValue(m *io_prometheus_client.Metric) (int, error) {
+	if x := m.Counter; x != nil {
+		return int(x.GetValue()), nil
+	} else if x := m.Gauge; x != nil {
+		return int(x.GetValue()), nil
+	} else {
+		return 0, fmt.Errorf("metric not-supported (not a counter/gauge)")
+	}
+}
+
+func getMetrics(ctx context.Context, port int) (map[string]*io_prometheus_client.MetricFamily, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", fmt.Sprintf("http://localhost:%d/metrics", port), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, nil // rather than fail, just return nothing, the caller reports the timeout
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("%s", resp.Status)
+	}
+	p := &expfmt.TextParser{}
+	return p.TextToMetricFamilies(resp.Body)
+}
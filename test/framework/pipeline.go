@@ -0,0 +1,154 @@
+package framework
+
+import (
+	"context"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func UntilRunning(pl dfv1.Pipeline) bool   { return untilHasCondition(dfv1.ConditionRunning)(pl) }
+func UntilCompleted(pl dfv1.Pipeline) bool { return untilHasCondition(dfv1.ConditionCompleted)(pl) }
+
+func untilHasCondition(condition string) func(pl dfv1.Pipeline) bool {
+	return func(pl dfv1.Pipeline) bool {
+		return meta.FindStatusCondition(pl.Status.Conditions, condition) != nil
+	}
+}
+
+func UntilSucceeded(pl dfv1.Pipeline) bool {
+	return pl.Status.Phase == dfv1.PipelineSucceeded
+}
+
+// CreatePipeline creates the pipeline, and deletes it when the test finishes. It returns the name of the pipeline,
+// which is generated if the pipeline has a generateName.
+func (f *Framework) CreatePipeline(pl dfv1.Pipeline) string {
+	f.t.Helper()
+	f.t.Logf("creating pipeline %q", pl.Name+pl.GenerateName)
+	un, err := toUnstructured(pl)
+	if err != nil {
+		f.t.Fatalf("failed to convert pipeline: %v", err)
+	}
+	created, err := f.pipelineInterface().Create(context.Background(), un, metav1.CreateOptions{})
+	if err != nil {
+		f.t.Fatalf("failed to create pipeline %q: %v", pl.Name+pl.GenerateName, err)
+	}
+	name := created.GetName()
+	f.t.Cleanup(func() {
+		if err := f.pipelineInterface().Delete(context.Background(), name, metav1.DeleteOptions{}); err != nil && !apierr.IsNotFound(err) {
+			f.t.Logf("failed to delete pipeline %q: %v", name, err)
+		}
+	})
+	return name
+}
+
+// CreatePipelineFromFile creates the first pipeline in the file.
+func (f *Framework) CreatePipelineFromFile(filename string) string {
+	f.t.Helper()
+	list, err := sharedutil.ReadFile(filename)
+	if err != nil {
+		f.t.Fatalf("failed to read %q: %v", filename, err)
+	}
+	for _, item := range list.Items {
+		if item.GetObjectKind().GroupVersionKind() == dfv1.PipelineGroupVersionKind {
+			pl, err := fromUnstructured(&item)
+			if err != nil {
+				f.t.Fatalf("failed to convert pipeline in %q: %v", filename, err)
+			}
+			return f.CreatePipeline(pl)
+		}
+	}
+	f.t.Fatalf("no pipeline found in %q", filename)
+	return ""
+}
+
+func (f *Framework) GetPipeline(name string) dfv1.Pipeline {
+	f.t.Helper()
+	un, err := f.pipelineInterface().Get(context.Background(), name, metav1.GetOptions{})
+	if err != nil {
+		f.t.Fatalf("failed to get pipeline %q: %v", name, err)
+	}
+	pl, err := fromUnstructured(un)
+	if err != nil {
+		f.t.Fatalf("failed to convert pipeline %q: %v", name, err)
+	}
+	return pl
+}
+
+func (f *Framework) ListPipelines() []dfv1.Pipeline {
+	f.t.Helper()
+	list, err := f.pipelineInterface().List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		f.t.Fatalf("failed to list pipelines: %v", err)
+	}
+	var pls []dfv1.Pipeline
+	for i := range list.Items {
+		pl, err := fromUnstructured(&list.Items[i])
+		if err != nil {
+			f.t.Fatalf("failed to convert pipeline %q: %v", list.Items[i].GetName(), err)
+		}
+		pls = append(pls, pl)
+	}
+	return pls
+}
+
+// DeletePipelines deletes all the pipelines in the namespace.
+func (f *Framework) DeletePipelines() {
+	f.t.Helper()
+	f.t.Logf("deleting pipelines")
+	if err := f.pipelineInterface().DeleteCollection(context.Background(), metav1.DeleteOptions{}, metav1.ListOptions{}); err != nil {
+		f.t.Fatalf("failed to delete pipelines: %v", err)
+	}
+}
+
+// WaitForPipeline waits until the named pipeline satisfies the condition, e.g. UntilRunning. If the name is empty, it
+// waits for any pipeline in the namespace.
+func (f *Framework) WaitForPipeline(name string, until func(dfv1.Pipeline) bool, timeout time.Duration) {
+	f.t.Helper()
+	funcName := sharedutil.GetFuncName(until)
+	f.t.Logf("waiting for pipeline %q %q in %v", name, funcName, timeout)
+	listOptions := metav1.ListOptions{}
+	if name != "" {
+		listOptions.FieldSelector = "metadata.name=" + name
+	}
+	f.watch(f.pipelineInterface(), listOptions, timeout, func(un *unstructured.Unstructured) bool {
+		pl, err := fromUnstructured(un)
+		if err != nil {
+			f.t.Fatalf("failed to convert pipeline %q: %v", un.GetName(), err)
+		}
+		s := pl.Status
+		var y []string
+		for _, c := range s.Conditions {
+			if c.Status == metav1.ConditionTrue {
+				y = append(y, c.Type)
+			}
+		}
+		f.t.Logf("pipeline %q is %s %q conditions %v", pl.Name, s.Phase, s.Message, y)
+		return until(pl)
+	})
+}
+
+var converter = runtime.DefaultUnstructuredConverter
+
+func toUnstructured(pl dfv1.Pipeline) (*unstructured.Unstructured, error) {
+	obj, err := converter.ToUnstructured(&pl)
+	if err != nil {
+		return nil, err
+	}
+	un := &unstructured.Unstructured{Object: obj}
+	un.SetKind(dfv1.PipelineGroupVersionKind.Kind)
+	un.SetAPIVersion(dfv1.GroupVersion.String())
+	return un, nil
+}
+
+func fromUnstructured(un *unstructured.Unstructured) (dfv1.Pipeline, error) {
+	x := dfv1.Pipeline{}
+	err := converter.FromUnstructured(un.Object, &x)
+	return x, err
+}
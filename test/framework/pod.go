@@ -0,0 +1,115 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+func PodRunningAndReady(p *corev1.Pod) bool {
+	return p.GetDeletionTimestamp() == nil && p.Status.Phase == corev1.PodRunning && PodReady(p)
+}
+
+func PodReady(p *corev1.Pod) bool {
+	for _, c := range p.Status.Conditions {
+		if p.GetDeletionTimestamp() == nil && c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *Framework) podInterface() typedcorev1.PodInterface {
+	return f.kubernetesInterface.CoreV1().Pods(f.namespace)
+}
+
+// WaitForPodsToBeDeleted waits for all the pipeline pods in the namespace to be deleted.
+func (f *Framework) WaitForPodsToBeDeleted() {
+	f.t.Helper()
+	f.t.Logf("waiting for pods to be deleted")
+
+	// pods MUST exit within 30s, because 30s after SIGTERM, they'll be SIGKILLed which will result in data loss
+	// so we need to be tougher on how long this is allowed to take
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	for {
+		list, err := f.podInterface().List(ctx, metav1.ListOptions{LabelSelector: dfv1.KeyPipelineName})
+		if err != nil {
+			f.t.Fatalf("failed to wait for pods to be deleted: %v", err)
+		}
+		if len(list.Items) == 0 {
+			return
+		}
+		select {
+		case <-ctx.Done():
+			f.t.Fatalf("failed to wait for pods to be deleted: %v", ctx.Err())
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// WaitForStepPod waits for a pod of the pipeline's step to be running and ready, and returns its name.
+func (f *Framework) WaitForStepPod(pipelineName, stepName string, timeout time.Duration) string {
+	f.t.Helper()
+	return f.WaitForPod(metav1.ListOptions{
+		LabelSelector: fmt.Sprintf("%s=%s,%s=%s", dfv1.KeyPipelineName, pipelineName, dfv1.KeyStepName, stepName),
+	}, PodRunningAndReady, timeout)
+}
+
+// WaitForPod waits for a pod matching the list options to satisfy the condition, and returns its name.
+func (f *Framework) WaitForPod(listOptions metav1.ListOptions, until func(*corev1.Pod) bool, timeout time.Duration) string {
+	f.t.Helper()
+	f.t.Logf("waiting for pod %q %q in %v", sharedutil.MustJSON(listOptions), sharedutil.GetFuncName(until), timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	w, err := f.podInterface().Watch(ctx, listOptions)
+	if err != nil {
+		f.t.Fatalf("failed to watch pods: %v", err)
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			f.t.Fatalf("failed to wait for pod %q: %v", sharedutil.MustJSON(listOptions), ctx.Err())
+			return ""
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				f.t.Fatalf("watch of pods %q closed", sharedutil.MustJSON(listOptions))
+				return ""
+			}
+			p, ok := e.Object.(*corev1.Pod)
+			if !ok {
+				f.t.Fatalf("failed to watch pods: %v", apierr.FromObject(e.Object))
+				return ""
+			}
+			s := p.Status
+			var y []string
+			for _, c := range s.Conditions {
+				if c.Status == corev1.ConditionTrue {
+					y = append(y, string(c.Type))
+				}
+			}
+			f.t.Logf("pod %q is %s %q %q", p.Name, s.Phase, s.Message, y)
+			if until(p) {
+				return p.Name
+			}
+		}
+	}
+}
+
+func (f *Framework) DeletePod(podName string) {
+	f.t.Helper()
+	f.t.Logf("deleting pod %q", podName)
+	if err := f.podInterface().Delete(context.Background(), podName, metav1.DeleteOptions{}); err != nil {
+		f.t.Fatalf("failed to delete %q: %v", podName, err)
+	}
+}
@@ -0,0 +1,54 @@
+package framework
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+
+	runtimeutil "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// StartPortForward forwards the local port to the same port on the pod, e.g. 3569 for the sidecar's metrics, until
+// stopped or the test finishes.
+func (f *Framework) StartPortForward(podName string, port int) (stopPortForward func()) {
+	f.t.Helper()
+	f.t.Logf("starting port-forward to pod %q on %d", podName, port)
+	transport, upgrader, err := spdy.RoundTripperFor(f.restConfig)
+	if err != nil {
+		f.t.Fatalf("failed to create round-tripper: %v", err)
+	}
+	x, err := url.Parse(fmt.Sprintf("%s/api/v1/namespaces/%s/pods/%s/portforward", f.restConfig.Host, f.namespace, podName))
+	if err != nil {
+		f.t.Fatalf("failed to parse URL: %v", err)
+	}
+	dialer := spdy.NewDialer(upgrader, &http.Client{Transport: transport}, "POST", x)
+	stopChan, readyChan := make(chan struct{}, 1), make(chan struct{}, 1)
+	forwarder, err := portforward.New(dialer, []string{fmt.Sprintf("%d:%d", port, port)}, stopChan, readyChan, os.Stdout, os.Stderr)
+	if err != nil {
+		f.t.Fatalf("failed to create port-forward to %q on %d: %v", podName, port, err)
+	}
+	errChan := make(chan error, 1)
+	go func() {
+		defer runtimeutil.HandleCrash()
+		errChan <- forwarder.ForwardPorts()
+	}()
+	select {
+	case <-readyChan:
+	case err := <-errChan:
+		f.t.Fatalf("failed to port-forward to %q on %d: %v", podName, port, err)
+	}
+	f.t.Logf("started port-forward to %q on %d", podName, port)
+	once := sync.Once{}
+	stopPortForward = func() {
+		once.Do(func() {
+			close(stopChan)
+			f.t.Logf("stopped port-forward to %q on %d", podName, port)
+		})
+	}
+	f.t.Cleanup(stopPortForward)
+	return stopPortForward
+}
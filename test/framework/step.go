@@ -0,0 +1,69 @@
+package framework
+
+import (
+	"context"
+	"time"
+
+	dfv1 "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+	sharedutil "github.com/argoproj-labs/argo-dataflow/shared/util"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+)
+
+func UntilStepRunning(step dfv1.Step) bool { return step.Status.Phase == dfv1.StepRunning }
+
+// UntilStepReplicas returns a condition that is true once the step has the number of replicas.
+func UntilStepReplicas(replicas uint32) func(dfv1.Step) bool {
+	return func(step dfv1.Step) bool { return step.Status.Replicas == replicas }
+}
+
+// WaitForStep waits until the pipeline's step satisfies the condition, e.g. UntilStepRunning.
+func (f *Framework) WaitForStep(pipelineName, stepName string, until func(dfv1.Step) bool, timeout time.Duration) {
+	f.t.Helper()
+	f.t.Logf("waiting for step %q/%q %q in %v", pipelineName, stepName, sharedutil.GetFuncName(until), timeout)
+	listOptions := metav1.ListOptions{FieldSelector: "metadata.name=" + pipelineName + "-" + stepName}
+	f.watch(f.stepInterface(), listOptions, timeout, func(un *unstructured.Unstructured) bool {
+		step := dfv1.Step{}
+		if err := converter.FromUnstructured(un.Object, &step); err != nil {
+			f.t.Fatalf("failed to convert step %q: %v", un.GetName(), err)
+		}
+		s := step.Status
+		f.t.Logf("step %q is %s %q replicas %d", step.Name, s.Phase, s.Message, s.Replicas)
+		return until(step)
+	})
+}
+
+// watch calls the func for each object added or modified until it returns true, failing if that does not happen
+// within the timeout.
+func (f *Framework) watch(ri dynamic.ResourceInterface, listOptions metav1.ListOptions, timeout time.Duration, fn func(*unstructured.Unstructured) bool) {
+	f.t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	w, err := ri.Watch(ctx, listOptions)
+	if err != nil {
+		f.t.Fatalf("failed to watch %q: %v", sharedutil.MustJSON(listOptions), err)
+	}
+	defer w.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			f.t.Fatalf("failed to wait for %q: %v", sharedutil.MustJSON(listOptions), ctx.Err())
+			return
+		case e, ok := <-w.ResultChan():
+			if !ok {
+				f.t.Fatalf("watch of %q closed", sharedutil.MustJSON(listOptions))
+				return
+			}
+			un, ok := e.Object.(*unstructured.Unstructured)
+			if !ok {
+				f.t.Fatalf("failed to watch %q: %v", sharedutil.MustJSON(listOptions), apierr.FromObject(e.Object))
+				return
+			}
+			if fn(un) {
+				return
+			}
+		}
+	}
+}
@@ -4,31 +4,15 @@
 package test
 
 import (
-	"bytes"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
 	"net/url"
 	"time"
 )
 
 func SendMessageViaHTTP(msg string) {
-	req, err := http.NewRequest("POST", "http://localhost:3569/sources/default", bytes.NewBufferString(msg))
-	if err != nil {
-		panic(err)
-	}
-	req.Header.Set("Authorization", GetAuthorization())
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		panic(err)
-	} else {
-		body, _ := ioutil.ReadAll(resp.Body)
-		_ = resp.Body.Close()
-		if resp.StatusCode != 204 {
-			panic(fmt.Errorf("%s: %q", resp.Status, body))
-		}
-	}
+	pl, stepName, sourceName := getHTTPSource()
+	fw.SendMessageViaHTTP(pl.Name, stepName, sourceName, 3569, msg)
 }
 
 func PumpHTTP(_url, prefix string, n int, opts ...interface{}) {
@@ -4,29 +4,23 @@
 package test
 
 import (
-	"context"
 	"fmt"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	. "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
 )
 
-var secretsInterface = kubernetesInterface.CoreV1().Secrets(namespace)
-
 func GetAuthorization() string {
-	ctx := context.Background()
-	pl := GetPipeline()
+	pl, stepName, sourceName := getHTTPSource()
+	return fw.GetAuthorization(pl.Name, stepName, sourceName)
+}
+
+// getHTTPSource returns the first HTTP source of the only pipeline
+func getHTTPSource() (pl Pipeline, stepName, sourceName string) {
+	pl = GetPipeline()
 	for _, step := range pl.Spec.Steps {
 		for _, source := range step.Sources {
 			if source.HTTP != nil {
-				secret, err := secretsInterface.Get(ctx, fmt.Sprintf("%s-%s", pl.Name, step.Name), metav1.GetOptions{})
-				if err != nil {
-					panic(err)
-				}
-				data, ok := secret.Data[fmt.Sprintf("sources.%s.http.authorization", source.Name)]
-				if !ok {
-					panic(fmt.Errorf("source %q not found", source.Name))
-				}
-				return string(data)
+				return pl, step.Name, source.Name
 			}
 		}
 	}
@@ -0,0 +1,46 @@
+//go:build test
+// +build test
+
+package test
+
+import (
+	"fmt"
+
+	. "github.com/argoproj-labs/argo-dataflow/api/v1alpha1"
+)
+
+// SendMessageViaTestSource adds the message to the queue of the first test source of the only pipeline.
+func SendMessageViaTestSource(msg string) {
+	pl, stepName, sourceName := getTestSource()
+	fw.SendMessageViaTestSource(pl.Name, stepName, sourceName, 3569, msg)
+}
+
+// GetTestSinkMessages returns the messages written to the first test sink of the only pipeline.
+func GetTestSinkMessages() []string {
+	pl, stepName, sinkName := getTestSink()
+	return fw.GetTestSinkMessages(pl.Name, stepName, sinkName, 3569)
+}
+
+func getTestSource() (pl Pipeline, stepName, sourceName string) {
+	pl = GetPipeline()
+	for _, step := range pl.Spec.Steps {
+		for _, source := range step.Sources {
+			if source.Test != nil {
+				return pl, step.Name, source.Name
+			}
+		}
+	}
+	panic(fmt.Errorf("not test source"))
+}
+
+func getTestSink() (pl Pipeline, stepName, sinkName string) {
+	pl = GetPipeline()
+	for _, step := range pl.Spec.Steps {
+		for _, sink := range step.Sinks {
+			if sink.Test != nil {
+				return pl, step.Name, sink.Name
+			}
+		}
+	}
+	panic(fmt.Errorf("not test sink"))
+}